@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -299,6 +300,7 @@ type Object struct {
 	mimeType   string                // Content-Type of the object
 	accessTier azblob.AccessTierType // Blob Access Tier
 	meta       map[string]string     // blob metadata
+	versionID  string                // ID of the version to read if not the current one
 }
 
 // ------------------------------------------------------------
@@ -1247,11 +1249,19 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 		return nil, fs.ErrorCantCopy
 	}
 	dstBlobURL := f.getBlobReference(dstContainer, dstPath)
-	srcBlobURL := srcObj.getBlobReference()
+	err = f.copyBlob(ctx, dstBlobURL, srcObj.getBlobReference())
+	if err != nil {
+		return nil, err
+	}
+	return f.NewObject(ctx, remote)
+}
 
+// copyBlob does a server-side copy of srcBlobURL to dstBlobURL
+// waiting for it to finish
+func (f *Fs) copyBlob(ctx context.Context, dstBlobURL, srcBlobURL azblob.BlobURL) error {
 	source, err := url.Parse(srcBlobURL.String())
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	options := azblob.BlobAccessConditions{}
@@ -1262,7 +1272,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 		return f.shouldRetry(ctx, err)
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	copyStatus := startCopy.CopyStatus()
@@ -1270,12 +1280,159 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 		time.Sleep(1 * time.Second)
 		getMetadata, err := dstBlobURL.GetProperties(ctx, options, azblob.ClientProvidedKeyOptions{})
 		if err != nil {
-			return nil, err
+			return err
 		}
 		copyStatus = getMetadata.CopyStatus()
 	}
+	return nil
+}
 
-	return f.NewObject(ctx, remote)
+// ListVersions calls callback with the versions of each object under
+// dir, newest first.
+//
+// The storage account must have blob versioning enabled for old
+// versions to be kept. Azure doesn't record when blobs are deleted
+// so no Deleted versions are returned.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	container, directory := f.split(dir)
+	if container == "" {
+		return errors.New("can't list versions without a container")
+	}
+	prefix := f.rootDirectory
+	if prefix != "" {
+		prefix += "/"
+	}
+	if directory != "" {
+		directory += "/"
+	}
+	var (
+		last     string
+		versions []fs.ObjectVersion
+	)
+	// send the versions of last to the callback
+	flush := func() error {
+		if len(versions) == 0 {
+			return nil
+		}
+		sort.SliceStable(versions, func(i, j int) bool {
+			return versions[i].Time.After(versions[j].Time)
+		})
+		err := callback(last, versions)
+		versions = nil
+		return err
+	}
+	options := azblob.ListBlobsSegmentOptions{
+		Details: azblob.BlobListingDetails{
+			Metadata: true,
+			Versions: true,
+		},
+		Prefix: directory,
+	}
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		var response *azblob.ListBlobsFlatSegmentResponse
+		err := f.pacer.Call(func() (bool, error) {
+			var err error
+			response, err = f.cntURL(container).ListBlobsFlatSegment(ctx, marker, options)
+			return f.shouldRetry(ctx, err)
+		})
+		if err != nil {
+			if storageErr, ok := err.(azblob.StorageError); ok && (storageErr.ServiceCode() == azblob.ServiceCodeContainerNotFound || storageErr.Response().StatusCode == http.StatusNotFound) {
+				return fs.ErrorDirNotFound
+			}
+			return err
+		}
+		marker = response.NextMarker
+		for i := range response.Segment.BlobItems {
+			file := &response.Segment.BlobItems[i]
+			remote := f.opt.Enc.ToStandardPath(file.Name)
+			if !strings.HasPrefix(remote, prefix) {
+				fs.Debugf(f, "Odd name received %q", remote)
+				continue
+			}
+			remote = remote[len(prefix):]
+			if isDirectoryMarker(*file.Properties.ContentLength, file.Metadata, remote) {
+				continue // skip directory marker
+			}
+			if f.rootContainer == "" {
+				remote = path.Join(container, remote)
+			}
+			if remote != last {
+				err = flush()
+				if err != nil {
+					return err
+				}
+				last = remote
+			}
+			o := &Object{
+				fs:     f,
+				remote: remote,
+			}
+			err = o.decodeMetaDataFromBlob(file)
+			if err != nil {
+				return err
+			}
+			versionID := ""
+			if file.VersionID != nil {
+				versionID = *file.VersionID
+			}
+			// The version ID is the time the version was made
+			t, err := time.Parse(time.RFC3339Nano, versionID)
+			if err != nil {
+				t = file.Properties.LastModified
+			}
+			versions = append(versions, fs.ObjectVersion{
+				ID:      versionID,
+				Time:    t,
+				ModTime: o.modTime,
+				Size:    o.size,
+				Current: file.IsCurrentVersion != nil && *file.IsCurrentVersion,
+			})
+		}
+	}
+	return flush()
+}
+
+// newVersionObject returns the version of the object at remote with
+// the ID given with its metadata read
+func (f *Fs) newVersionObject(remote string, versionID string) (*Object, error) {
+	o := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: versionID,
+	}
+	return o, o.readMetaData()
+}
+
+// OpenVersion opens the version of the object at remote with the ID
+// given for reading.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	o, err := f.newVersionObject(remote, versionID)
+	if err != nil {
+		return nil, err
+	}
+	return o.Open(ctx, options...)
+}
+
+// RestoreVersion makes the version of the object at remote with the
+// ID given the current version by copying it over the object.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	srcObj, err := f.newVersionObject(remote, versionID)
+	if err != nil {
+		return err
+	}
+	container, containerPath := f.split(remote)
+	return f.copyBlob(ctx, f.getBlobReference(container, containerPath), srcObj.getBlobReference())
+}
+
+// DeleteVersion deletes the version of the object at remote with the
+// ID given.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	o := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: versionID,
+	}
+	return o.Remove(ctx)
 }
 
 func (f *Fs) getMemoryPool(size int64) *pool.Pool {
@@ -1411,7 +1568,11 @@ func (o *Object) decodeMetaDataFromBlob(info *azblob.BlobItemInternal) (err erro
 // getBlobReference creates an empty blob reference with no metadata
 func (o *Object) getBlobReference() azblob.BlobURL {
 	container, directory := o.split()
-	return o.fs.getBlobReference(container, directory)
+	blob := o.fs.getBlobReference(container, directory)
+	if o.versionID != "" {
+		blob = blob.WithVersionID(o.versionID)
+	}
+	return blob
 }
 
 // clearMetaData clears enough metadata so readMetaData will re-read it
@@ -1745,6 +1906,7 @@ var (
 	_ fs.PutStreamer = &Fs{}
 	_ fs.Purger      = &Fs{}
 	_ fs.ListRer     = &Fs{}
+	_ fs.Versioner   = &Fs{}
 	_ fs.Object      = &Object{}
 	_ fs.MimeTyper   = &Object{}
 	_ fs.GetTierer   = &Object{}
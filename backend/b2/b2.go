@@ -1333,6 +1333,113 @@ func (f *Fs) Hashes() hash.Set {
 	return hash.Set(hash.SHA1)
 }
 
+// ListVersions calls callback with the versions of each object under
+// dir, newest first.
+//
+// Hide markers are returned as Deleted versions.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	bucket, directory := f.split(dir)
+	if bucket == "" {
+		return errors.New("can't list versions without a bucket")
+	}
+	var (
+		last     string
+		versions []fs.ObjectVersion
+	)
+	// send the versions of last to the callback
+	flush := func() error {
+		if len(versions) == 0 {
+			return nil
+		}
+		err := callback(last, versions)
+		versions = nil
+		return err
+	}
+	err := f.list(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", true, 0, true, false, func(remote string, object *api.File, isDirectory bool) error {
+		// skip directories and unfinished large uploads
+		if isDirectory || (object.Action != "upload" && object.Action != "hide") {
+			return nil
+		}
+		if remote != last {
+			err := flush()
+			if err != nil {
+				return err
+			}
+			last = remote
+		}
+		o := &Object{
+			fs:     f,
+			remote: remote,
+		}
+		err := o.decodeMetaData(object)
+		if err != nil {
+			return err
+		}
+		versions = append(versions, fs.ObjectVersion{
+			ID:      object.ID,
+			Time:    time.Time(object.UploadTimestamp),
+			ModTime: o.modTime,
+			Size:    object.Size,
+			Current: len(versions) == 0,
+			Deleted: object.Action == "hide",
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// newVersionObject returns the version of the object at remote with
+// the ID given with its metadata read
+func (f *Fs) newVersionObject(ctx context.Context, remote string, versionID string) (*Object, error) {
+	if f.opt.DownloadURL != "" {
+		return nil, errors.New("can't read versions with --b2-download-url")
+	}
+	o := &Object{
+		fs:     f,
+		remote: remote,
+		id:     versionID,
+	}
+	_, info, err := o.getOrHead(ctx, "HEAD", nil)
+	if err != nil {
+		return nil, err
+	}
+	return o, o.decodeMetaData(info)
+}
+
+// OpenVersion opens the version of the object at remote with the ID
+// given for reading.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	o, err := f.newVersionObject(ctx, remote, versionID)
+	if err != nil {
+		return nil, err
+	}
+	return o.Open(ctx, options...)
+}
+
+// RestoreVersion makes the version of the object at remote with the
+// ID given the current version by copying it over the object.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	srcObj, err := f.newVersionObject(ctx, remote, versionID)
+	if err != nil {
+		return err
+	}
+	dstObj := &Object{
+		fs:     f,
+		remote: remote,
+	}
+	return f.copy(ctx, dstObj, srcObj, nil)
+}
+
+// DeleteVersion deletes the version of the object at remote with the
+// ID given.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	_, bucketPath := f.split(remote)
+	return f.deleteByID(ctx, versionID, bucketPath)
+}
+
 // getDownloadAuthorization returns authorization token for downloading
 // without account.
 func (f *Fs) getDownloadAuthorization(ctx context.Context, bucket, remote string) (authorization string, err error) {
@@ -1998,6 +2105,7 @@ var (
 	_ fs.CleanUpper   = &Fs{}
 	_ fs.ListRer      = &Fs{}
 	_ fs.PublicLinker = &Fs{}
+	_ fs.Versioner    = &Fs{}
 	_ fs.Object       = &Object{}
 	_ fs.MimeTyper    = &Object{}
 	_ fs.IDer         = &Object{}
@@ -823,6 +823,17 @@ func (f *Fs) receiveChangeNotify(forgetPath string, entryType fs.EntryType) {
 	// notify upstreams too (vfs)
 	f.notifyChangeUpstream(forgetPath, entryType)
 
+	cd := f.expireCached(forgetPath, entryType)
+
+	f.notifiedMu.Lock()
+	defer f.notifiedMu.Unlock()
+	f.notifiedRemotes[forgetPath] = true
+	f.notifiedRemotes[cd.Remote()] = true
+}
+
+// expireCached expires the cached entry at forgetPath and the
+// directory it is in, returning the directory
+func (f *Fs) expireCached(forgetPath string, entryType fs.EntryType) *Directory {
 	var cd *Directory
 	if entryType == fs.EntryObject {
 		co := NewObject(f, forgetPath)
@@ -845,11 +856,7 @@ func (f *Fs) receiveChangeNotify(forgetPath string, entryType fs.EntryType) {
 	} else {
 		fs.Debugf(forgetPath, "notify: expired '%v'", cd)
 	}
-
-	f.notifiedMu.Lock()
-	defer f.notifiedMu.Unlock()
-	f.notifiedRemotes[forgetPath] = true
-	f.notifiedRemotes[cd.Remote()] = true
+	return cd
 }
 
 // notifyChangeUpstreamIfNeeded will check if the wrapped remote doesn't notify on changes
@@ -886,6 +893,75 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	}()
 }
 
+// ChangeCursor returns a cursor for the current position in the
+// changes of the wrapped Fs.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	do := f.Fs.Features().ChangeCursor
+	if do == nil {
+		return "", errors.New("can't ChangeCursor")
+	}
+	return do(ctx)
+}
+
+// ListChanges calls callback with each path changed in the wrapped
+// Fs since cursor, expiring the cached entries for it first so they
+// are read again.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	do := f.Fs.Features().ListChanges
+	if do == nil {
+		return "", errors.New("can't ListChanges")
+	}
+	return do(ctx, cursor, func(remote string, entryType fs.EntryType) error {
+		f.expireCached(remote, entryType)
+		return callback(remote, entryType)
+	})
+}
+
+// ListVersions calls callback with the versions of each object under
+// dir from the wrapped Fs. Versions aren't cached.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	do := f.Fs.Features().ListVersions
+	if do == nil {
+		return errors.New("can't ListVersions")
+	}
+	return do(ctx, dir, callback)
+}
+
+// OpenVersion opens the version of the object at remote with the ID
+// given from the wrapped Fs.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	do := f.Fs.Features().OpenVersion
+	if do == nil {
+		return nil, errors.New("can't OpenVersion")
+	}
+	return do(ctx, remote, versionID, options...)
+}
+
+// RestoreVersion makes the version of the object at remote with the
+// ID given the current version, expiring the cached object.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	do := f.Fs.Features().RestoreVersion
+	if do == nil {
+		return errors.New("can't RestoreVersion")
+	}
+	err := do(ctx, remote, versionID)
+	f.expireCached(remote, fs.EntryObject)
+	return err
+}
+
+// DeleteVersion deletes the version of the object at remote with the
+// ID given, expiring the cached object in case it was the current
+// version.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	do := f.Fs.Features().DeleteVersion
+	if do == nil {
+		return errors.New("can't DeleteVersion")
+	}
+	err := do(ctx, remote, versionID)
+	f.expireCached(remote, fs.EntryObject)
+	return err
+}
+
 // Name of the remote (as passed into NewFs)
 func (f *Fs) Name() string {
 	return f.name
@@ -1951,6 +2027,8 @@ var (
 	_ fs.Wrapper        = (*Fs)(nil)
 	_ fs.ListRer        = (*Fs)(nil)
 	_ fs.ChangeNotifier = (*Fs)(nil)
+	_ fs.ChangeLister   = (*Fs)(nil)
+	_ fs.Versioner      = (*Fs)(nil)
 	_ fs.Abouter        = (*Fs)(nil)
 	_ fs.UserInfoer     = (*Fs)(nil)
 	_ fs.Disconnecter   = (*Fs)(nil)
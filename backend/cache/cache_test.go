@@ -16,9 +16,15 @@ import (
 // TestIntegration runs integration tests against the remote
 func TestIntegration(t *testing.T) {
 	fstests.Run(t, &fstests.Opt{
-		RemoteName:                   "TestCache:",
-		NilObject:                    (*cache.Object)(nil),
-		UnimplementableFsMethods:     []string{"PublicLink", "OpenWriterAt"},
+		RemoteName: "TestCache:",
+		NilObject:  (*cache.Object)(nil),
+		UnimplementableFsMethods: []string{
+			"PublicLink",
+			"OpenWriterAt",
+			// listings come from the cache which can't use hints
+			"ListHinted",
+			"ListRHinted",
+		},
 		UnimplementableObjectMethods: []string{"MimeType", "ID", "GetTier", "SetTier"},
 		SkipInvalidUTF8:              true, // invalid UTF-8 confuses the cache
 	})
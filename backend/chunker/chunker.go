@@ -733,6 +733,50 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	})
 }
 
+// translateHint returns a hint for the base Fs which asks for at
+// least every chunk of the files hint asks for.
+//
+// Chunk names start with the name of their composite file so the
+// prefixes can be kept, but the chunks of an excluded file can sort
+// after StartAfter and their modification times can differ from the
+// file's, so the rest of the hint is dropped.
+func (f *Fs) translateHint(hint *fs.ListHint) *fs.ListHint {
+	if hint == nil || len(hint.Prefixes) == 0 {
+		return nil
+	}
+	return &fs.ListHint{Prefixes: hint.Prefixes}
+}
+
+// ListHinted lists the objects and directories in dir like List but
+// passes the prefixes of the hint on to the base Fs.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
+	do := f.base.Features().ListHinted
+	if do == nil {
+		return f.List(ctx, dir)
+	}
+	entries, err = do(ctx, dir, f.translateHint(hint))
+	if err != nil {
+		return nil, err
+	}
+	return f.processEntries(ctx, entries, dir)
+}
+
+// ListRHinted lists recursively like ListR but passes the prefixes
+// of the hint on to the base Fs.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
+	do := f.base.Features().ListRHinted
+	if do == nil {
+		return f.ListR(ctx, dir, callback)
+	}
+	return do(ctx, dir, f.translateHint(hint), func(entries fs.DirEntries) error {
+		newEntries, err := f.processEntries(ctx, entries, dir)
+		if err != nil {
+			return err
+		}
+		return callback(newEntries)
+	})
+}
+
 // processEntries assembles chunk entries into composite entries
 func (f *Fs) processEntries(ctx context.Context, origEntries fs.DirEntries, dirPath string) (newEntries fs.DirEntries, err error) {
 	var sortedEntries fs.DirEntries
@@ -1926,23 +1970,54 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	}
 	wrappedNotifyFunc := func(path string, entryType fs.EntryType) {
 		//fs.Debugf(f, "ChangeNotify: path %q entryType %d", path, entryType)
-		if entryType == fs.EntryObject {
-			mainPath, _, _, xactID := f.parseChunkName(path)
-			metaXactID := ""
-			if f.useNoRename {
-				metaObject, _ := f.base.NewObject(ctx, mainPath)
-				dummyObject := f.newObject("", metaObject, nil)
-				metaXactID, _ = dummyObject.readXactID(ctx)
-			}
-			if mainPath != "" && xactID == metaXactID {
-				path = mainPath
-			}
-		}
-		notifyFunc(path, entryType)
+		notifyFunc(f.changedPath(ctx, path, entryType), entryType)
 	}
 	do(ctx, wrappedNotifyFunc, pollIntervalChan)
 }
 
+// changedPath returns the path of the composite file for a changed
+// path in the base Fs if it is one of its active data chunks, or path
+// otherwise.
+func (f *Fs) changedPath(ctx context.Context, path string, entryType fs.EntryType) string {
+	if entryType != fs.EntryObject {
+		return path
+	}
+	mainPath, _, _, xactID := f.parseChunkName(path)
+	metaXactID := ""
+	if f.useNoRename {
+		metaObject, _ := f.base.NewObject(ctx, mainPath)
+		dummyObject := f.newObject("", metaObject, nil)
+		metaXactID, _ = dummyObject.readXactID(ctx)
+	}
+	if mainPath != "" && xactID == metaXactID {
+		return mainPath
+	}
+	return path
+}
+
+// ChangeCursor returns a cursor for the current position in the
+// changes of the base Fs.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	do := f.base.Features().ChangeCursor
+	if do == nil {
+		return "", errors.New("can't ChangeCursor")
+	}
+	return do(ctx)
+}
+
+// ListChanges calls callback with each path changed in the base Fs
+// since cursor, replacing data chunk names by the name of their
+// composite file like ChangeNotify.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	do := f.base.Features().ListChanges
+	if do == nil {
+		return "", errors.New("can't ListChanges")
+	}
+	return do(ctx, cursor, func(path string, entryType fs.EntryType) error {
+		return callback(f.changedPath(ctx, path, entryType), entryType)
+	})
+}
+
 // Shutdown the backend, closing any background tasks and any
 // cached connections.
 func (f *Fs) Shutdown(ctx context.Context) error {
@@ -2545,6 +2620,9 @@ var (
 	_ fs.CleanUpper      = (*Fs)(nil)
 	_ fs.UnWrapper       = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListHinter      = (*Fs)(nil)
+	_ fs.ListRHinter     = (*Fs)(nil)
+	_ fs.ChangeLister    = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.Wrapper         = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
@@ -354,6 +354,39 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	})
 }
 
+// ListHinted lists the objects and directories in dir like List,
+// passing the hint on to the wrapped Fs.
+//
+// The data file names start with the name of the file they hold so
+// the hint still asks for every entry needed.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
+	do := f.Fs.Features().ListHinted
+	if do == nil {
+		return f.List(ctx, dir)
+	}
+	entries, err = do(ctx, dir, hint)
+	if err != nil {
+		return nil, err
+	}
+	return f.processEntries(entries)
+}
+
+// ListRHinted lists recursively like ListR, passing the hint on to
+// the wrapped Fs.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
+	do := f.Fs.Features().ListRHinted
+	if do == nil {
+		return f.ListR(ctx, dir, callback)
+	}
+	return do(ctx, dir, hint, func(entries fs.DirEntries) error {
+		newEntries, err := f.processEntries(entries)
+		if err != nil {
+			return err
+		}
+		return callback(newEntries)
+	})
+}
+
 // NewObject finds the Object at remote.
 func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	// Read metadata from metadata object
@@ -981,6 +1014,41 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	do(ctx, wrappedNotifyFunc, pollIntervalChan)
 }
 
+// ChangeCursor returns a cursor for the current position in the
+// changes of the wrapped Fs.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	do := f.Fs.Features().ChangeCursor
+	if do == nil {
+		return "", errors.New("can't ChangeCursor: not supported by underlying remote")
+	}
+	return do(ctx)
+}
+
+// ListChanges calls callback with each path changed in the wrapped
+// Fs since cursor, turning the names of data and metadata files back
+// into the names of the files they hold.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	do := f.Fs.Features().ListChanges
+	if do == nil {
+		return "", errors.New("can't ListChanges: not supported by underlying remote")
+	}
+	return do(ctx, cursor, func(path string, entryType fs.EntryType) error {
+		if entryType == fs.EntryObject {
+			if isMetadataFile(path) {
+				path = strings.TrimSuffix(path, metaFileExt)
+			} else {
+				origFileName, _, _, err := processFileName(path)
+				if err != nil {
+					fs.Debugf(f, "ListChanges: skipping %q: %v", path, err)
+					return nil
+				}
+				path = origFileName
+			}
+		}
+		return callback(path, entryType)
+	})
+}
+
 // PublicLink generates a public link to the remote path (usually readable by anyone)
 func (f *Fs) PublicLink(ctx context.Context, remote string, duration fs.Duration, unlink bool) (string, error) {
 	do := f.Fs.Features().PublicLink
@@ -1399,6 +1467,9 @@ var (
 	_ fs.CleanUpper      = (*Fs)(nil)
 	_ fs.UnWrapper       = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListHinter      = (*Fs)(nil)
+	_ fs.ListRHinter     = (*Fs)(nil)
+	_ fs.ChangeLister    = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.Wrapper         = (*Fs)(nil)
 	_ fs.MergeDirser     = (*Fs)(nil)
@@ -27,6 +27,12 @@ func TestIntegration(t *testing.T) {
 			"PutStream",
 			"UserInfo",
 			"Disconnect",
+			// the versions of a file are spread over data
+			// files named by size with separate metadata
+			"ListVersions",
+			"OpenVersion",
+			"RestoreVersion",
+			"DeleteVersion",
 		},
 		TiersToTest:                  []string{"STANDARD", "STANDARD_IA"},
 		UnimplementableObjectMethods: []string{}}
@@ -51,6 +57,12 @@ func TestRemoteGzip(t *testing.T) {
 			"PutStream",
 			"UserInfo",
 			"Disconnect",
+			// the versions of a file are spread over data
+			// files named by size with separate metadata
+			"ListVersions",
+			"OpenVersion",
+			"RestoreVersion",
+			"DeleteVersion",
 		},
 		UnimplementableObjectMethods: []string{
 			"GetTier",
@@ -349,6 +349,53 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	})
 }
 
+// translateHint returns a hint for the wrapped Fs which asks for at
+// least every entry hint does.
+//
+// Encrypted names don't keep the order or prefixes of the plain
+// names unless file name encryption is off, where adding the suffix
+// keeps both. Modification times aren't encrypted.
+func (f *Fs) translateHint(hint *fs.ListHint) *fs.ListHint {
+	if hint == nil || f.cipher.NameEncryptionMode() == NameEncryptionOff {
+		return hint
+	}
+	return &fs.ListHint{
+		ModifiedSince:  hint.ModifiedSince,
+		ModifiedBefore: hint.ModifiedBefore,
+	}
+}
+
+// ListHinted lists the objects and directories in dir like List but
+// passes as much of the hint as survives encryption on to the wrapped
+// Fs.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
+	do := f.Fs.Features().ListHinted
+	if do == nil {
+		return f.List(ctx, dir)
+	}
+	entries, err = do(ctx, f.cipher.EncryptDirName(dir), f.translateHint(hint))
+	if err != nil {
+		return nil, err
+	}
+	return f.encryptEntries(ctx, entries)
+}
+
+// ListRHinted lists recursively like ListR but passes as much of the
+// hint as survives encryption on to the wrapped Fs.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
+	do := f.Fs.Features().ListRHinted
+	if do == nil {
+		return f.ListR(ctx, dir, callback)
+	}
+	return do(ctx, f.cipher.EncryptDirName(dir), f.translateHint(hint), func(entries fs.DirEntries) error {
+		newEntries, err := f.encryptEntries(ctx, entries)
+		if err != nil {
+			return err
+		}
+		return callback(newEntries)
+	})
+}
+
 // NewObject finds the Object at remote.
 func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 	o, err := f.Fs.NewObject(ctx, f.cipher.EncryptFileName(remote))
@@ -751,6 +798,138 @@ func (f *Fs) ChangeNotify(ctx context.Context, notifyFunc func(string, fs.EntryT
 	do(ctx, wrappedNotifyFunc, pollIntervalChan)
 }
 
+// decryptPath decrypts path from the wrapped Fs as the entryType
+// given
+func (f *Fs) decryptPath(path string, entryType fs.EntryType) (string, error) {
+	switch entryType {
+	case fs.EntryDirectory:
+		return f.cipher.DecryptDirName(path)
+	case fs.EntryObject:
+		return f.cipher.DecryptFileName(path)
+	}
+	return "", errors.Errorf("unknown EntryType %d", entryType)
+}
+
+// ChangeCursor returns a cursor for the current position in the
+// changes of the wrapped Fs.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	do := f.Fs.Features().ChangeCursor
+	if do == nil {
+		return "", errors.New("can't ChangeCursor")
+	}
+	return do(ctx)
+}
+
+// ListChanges calls callback with the decrypted path of each entry
+// changed in the wrapped Fs since cursor.
+//
+// Paths which can't be decrypted aren't crypt's files so are skipped.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	do := f.Fs.Features().ListChanges
+	if do == nil {
+		return "", errors.New("can't ListChanges")
+	}
+	return do(ctx, cursor, func(path string, entryType fs.EntryType) error {
+		decrypted, err := f.decryptPath(path, entryType)
+		if err != nil {
+			fs.Debugf(f, "ListChanges: skipping undecryptable %q: %v", path, err)
+			return nil
+		}
+		return callback(decrypted, entryType)
+	})
+}
+
+// ListVersions calls callback with the versions of each object under
+// dir with their names and sizes decrypted.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	do := f.Fs.Features().ListVersions
+	if do == nil {
+		return errors.New("can't ListVersions")
+	}
+	return do(ctx, f.cipher.EncryptDirName(dir), func(remote string, versions []fs.ObjectVersion) error {
+		decrypted, err := f.cipher.DecryptFileName(remote)
+		if err != nil {
+			fs.Debugf(remote, "Skipping undecryptable file name: %v", err)
+			return nil
+		}
+		if !f.opt.NoDataEncryption {
+			for i := range versions {
+				v := &versions[i]
+				if v.Deleted {
+					continue
+				}
+				v.Size, err = f.cipher.DecryptedSize(v.Size)
+				if err != nil {
+					fs.Debugf(decrypted, "Bad size for decrypt of version %q: %v", v.ID, err)
+				}
+			}
+		}
+		return callback(decrypted, versions)
+	})
+}
+
+// OpenVersion opens the version of the object at remote with the ID
+// given, decrypting it.
+//
+// Ranges from the end of the file aren't supported as the size of
+// the version isn't known.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	do := f.Fs.Features().OpenVersion
+	if do == nil {
+		return nil, errors.New("can't OpenVersion")
+	}
+	encrypted := f.cipher.EncryptFileName(remote)
+	if f.opt.NoDataEncryption {
+		return do(ctx, encrypted, versionID, options...)
+	}
+	var openOptions []fs.OpenOption
+	var offset, limit int64 = 0, -1
+	for _, option := range options {
+		switch x := option.(type) {
+		case *fs.SeekOption:
+			offset = x.Offset
+		case *fs.RangeOption:
+			if x.Start < 0 {
+				return nil, errors.New("can't OpenVersion: ranges from the end aren't supported")
+			}
+			offset, limit = x.Decode(-1)
+		default:
+			openOptions = append(openOptions, option)
+		}
+	}
+	return f.cipher.DecryptDataSeek(ctx, func(ctx context.Context, underlyingOffset, underlyingLimit int64) (io.ReadCloser, error) {
+		if underlyingOffset == 0 && underlyingLimit < 0 {
+			return do(ctx, encrypted, versionID, openOptions...)
+		}
+		end := int64(-1)
+		if underlyingLimit >= 0 {
+			end = underlyingOffset + underlyingLimit - 1
+		}
+		newOpenOptions := append(openOptions, &fs.RangeOption{Start: underlyingOffset, End: end})
+		return do(ctx, encrypted, versionID, newOpenOptions...)
+	}, offset, limit)
+}
+
+// RestoreVersion makes the version of the object at remote with the
+// ID given the current version.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	do := f.Fs.Features().RestoreVersion
+	if do == nil {
+		return errors.New("can't RestoreVersion")
+	}
+	return do(ctx, f.cipher.EncryptFileName(remote), versionID)
+}
+
+// DeleteVersion deletes the version of the object at remote with the
+// ID given.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	do := f.Fs.Features().DeleteVersion
+	if do == nil {
+		return errors.New("can't DeleteVersion")
+	}
+	return do(ctx, f.cipher.EncryptFileName(remote), versionID)
+}
+
 var commandHelp = []fs.CommandHelp{
 	{
 		Name:  "encode",
@@ -1069,6 +1248,10 @@ var (
 	_ fs.CleanUpper      = (*Fs)(nil)
 	_ fs.UnWrapper       = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListHinter      = (*Fs)(nil)
+	_ fs.ListRHinter     = (*Fs)(nil)
+	_ fs.ChangeLister    = (*Fs)(nil)
+	_ fs.Versioner       = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.Wrapper         = (*Fs)(nil)
 	_ fs.MergeDirser     = (*Fs)(nil)
@@ -6,12 +6,14 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"testing"
 	"time"
 
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/fs/object"
+	"github.com/rclone/rclone/fstest/mockfs"
 	"github.com/rclone/rclone/lib/random"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -141,3 +143,134 @@ func (f *Fs) InternalTest(t *testing.T) {
 	t.Run("ObjectInfoWrap", func(t *testing.T) { testObjectInfo(t, f, true) })
 	t.Run("ComputeHash", func(t *testing.T) { testComputeHash(t, f) })
 }
+
+// versionsFs is an Fs with versions and changes for testing the
+// passthrough of those to the wrapped Fs
+type versionsFs struct {
+	fs.Fs
+	features *fs.Features
+	versions map[string][]fs.ObjectVersion
+	contents map[string][]byte // by version ID
+	changes  map[string]fs.EntryType
+	restored string
+}
+
+// Features returns the optional features of this Fs
+func (vf *versionsFs) Features() *fs.Features {
+	return vf.features
+}
+
+func (vf *versionsFs) ChangeCursor(ctx context.Context) (string, error) {
+	return "cursor", nil
+}
+
+func (vf *versionsFs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (string, error) {
+	for remote, entryType := range vf.changes {
+		if err := callback(remote, entryType); err != nil {
+			return "", err
+		}
+	}
+	return cursor + "+", nil
+}
+
+func (vf *versionsFs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	for remote, versions := range vf.versions {
+		if err := callback(remote, append([]fs.ObjectVersion(nil), versions...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vf *versionsFs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	if _, ok := vf.versions[remote]; !ok {
+		return nil, fs.ErrorObjectNotFound
+	}
+	data := vf.contents[versionID]
+	for _, option := range options {
+		if x, ok := option.(*fs.RangeOption); ok {
+			offset, limit := x.Decode(int64(len(data)))
+			data = data[offset:]
+			if limit >= 0 && limit < int64(len(data)) {
+				data = data[:limit]
+			}
+		}
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (vf *versionsFs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	vf.restored = remote + "@" + versionID
+	return nil
+}
+
+func (vf *versionsFs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	return nil
+}
+
+func TestVersionsAndChanges(t *testing.T) {
+	ctx := context.Background()
+	c, err := newCipher(NameEncryptionStandard, "", "", true)
+	require.NoError(t, err)
+	encrypt := func(contents string) []byte {
+		in, err := c.EncryptData(bytes.NewBufferString(contents))
+		require.NoError(t, err)
+		out, err := ioutil.ReadAll(in)
+		require.NoError(t, err)
+		return out
+	}
+	v1, v2 := encrypt("hello"), encrypt("hello, world")
+	name := c.EncryptFileName("dir/file")
+	vf := &versionsFs{
+		Fs: mockfs.NewFs(ctx, "versions", ""),
+		versions: map[string][]fs.ObjectVersion{
+			name:          {{ID: "2", Size: int64(len(v2)), Current: true}, {ID: "1", Size: int64(len(v1))}},
+			"not-crypted": {{ID: "3", Size: 10, Current: true}},
+		},
+		contents: map[string][]byte{"1": v1, "2": v2},
+		changes: map[string]fs.EntryType{
+			name:                    fs.EntryObject,
+			c.EncryptDirName("dir"): fs.EntryDirectory,
+			"not-crypted":           fs.EntryObject,
+		},
+	}
+	vf.features = (&fs.Features{}).Fill(ctx, vf)
+	f := &Fs{Fs: vf, cipher: c}
+
+	var remotes []string
+	err = f.ListVersions(ctx, "", func(remote string, versions []fs.ObjectVersion) error {
+		remotes = append(remotes, remote)
+		require.Len(t, versions, 2)
+		assert.Equal(t, int64(12), versions[0].Size)
+		assert.Equal(t, int64(5), versions[1].Size)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dir/file"}, remotes)
+
+	rc, err := f.OpenVersion(ctx, "dir/file", "1")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "hello", string(data))
+
+	rc, err = f.OpenVersion(ctx, "dir/file", "2", &fs.RangeOption{Start: 7, End: -1})
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "world", string(data))
+
+	require.NoError(t, f.RestoreVersion(ctx, "dir/file", "1"))
+	assert.Equal(t, name+"@1", vf.restored)
+
+	changes := map[string]fs.EntryType{}
+	cursor, err := f.ListChanges(ctx, "cursor", func(remote string, entryType fs.EntryType) error {
+		changes[remote] = entryType
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cursor+", cursor)
+	assert.Equal(t, map[string]fs.EntryType{"dir/file": fs.EntryObject, "dir": fs.EntryDirectory}, changes)
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/rclone/rclone/fs/fshttp"
 	"github.com/rclone/rclone/fs/fspath"
 	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/fs/object"
 	"github.com/rclone/rclone/fs/operations"
 	"github.com/rclone/rclone/fs/walk"
 	"github.com/rclone/rclone/lib/dircache"
@@ -762,8 +763,12 @@ func (f *Fs) getRootID(ctx context.Context) (string, error) {
 //
 // If the user fn ever returns true then it early exits with found = true
 //
+// If hint is set then only files modified in its time range are
+// listed, otherwise the filter is used if this remote is a
+// sync/copy/walk source.
+//
 // Search params: https://developers.google.com/drive/search-parameters
-func (f *Fs) list(ctx context.Context, dirIDs []string, title string, directoriesOnly, filesOnly, trashedOnly, includeAll bool, fn listFn) (found bool, err error) {
+func (f *Fs) list(ctx context.Context, dirIDs []string, title string, directoriesOnly, filesOnly, trashedOnly, includeAll bool, hint *fs.ListHint, fn listFn) (found bool, err error) {
 	var query []string
 	if !includeAll {
 		q := "trashed=" + strconv.FormatBool(trashedOnly)
@@ -832,21 +837,25 @@ func (f *Fs) list(ctx context.Context, dirIDs []string, title string, directorie
 		query = append(query, fmt.Sprintf("mimeType!='%s'", driveFolderType))
 	}
 
-	// Constrain query using filter if this remote is a sync/copy/walk source.
-	if fi, use := filter.GetConfig(ctx), filter.GetUseFilter(ctx); fi != nil && use {
-		queryByTime := func(op string, tm time.Time) {
-			if tm.IsZero() {
-				return
-			}
-			// https://developers.google.com/drive/api/v3/ref-search-terms#operators
-			// Query times use RFC 3339 format, default timezone is UTC
-			timeStr := tm.UTC().Format("2006-01-02T15:04:05")
-			term := fmt.Sprintf("(modifiedTime %s '%s' or mimeType = '%s')", op, timeStr, driveFolderType)
-			query = append(query, term)
+	// Constrain query using the hint or the filter if this remote is a sync/copy/walk source.
+	var modifiedSince, modifiedBefore time.Time
+	if hint != nil {
+		modifiedSince, modifiedBefore = hint.ModifiedSince, hint.ModifiedBefore
+	} else if fi, use := filter.GetConfig(ctx), filter.GetUseFilter(ctx); fi != nil && use {
+		modifiedSince, modifiedBefore = fi.ModTimeFrom, fi.ModTimeTo
+	}
+	queryByTime := func(op string, tm time.Time) {
+		if tm.IsZero() {
+			return
 		}
-		queryByTime(">=", fi.ModTimeFrom)
-		queryByTime("<=", fi.ModTimeTo)
+		// https://developers.google.com/drive/api/v3/ref-search-terms#operators
+		// Query times use RFC 3339 format, default timezone is UTC
+		timeStr := tm.UTC().Format("2006-01-02T15:04:05")
+		term := fmt.Sprintf("(modifiedTime %s '%s' or mimeType = '%s')", op, timeStr, driveFolderType)
+		query = append(query, term)
 	}
+	queryByTime(">=", modifiedSince)
+	queryByTime("<=", modifiedBefore)
 
 	list := f.svc.Files.List()
 	queryString := strings.Join(query, " and ")
@@ -1431,7 +1440,7 @@ func (f *Fs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
 func (f *Fs) FindLeaf(ctx context.Context, pathID, leaf string) (pathIDOut string, found bool, err error) {
 	// Find the leaf in pathID
 	pathID = actualID(pathID)
-	found, err = f.list(ctx, []string{pathID}, leaf, true, false, f.opt.TrashedOnly, false, func(item *drive.File) bool {
+	found, err = f.list(ctx, []string{pathID}, leaf, true, false, f.opt.TrashedOnly, false, nil, func(item *drive.File) bool {
 		if !f.opt.SkipGdocs {
 			_, exportName, _, isDocument := f.findExportFormat(ctx, item)
 			if exportName == leaf {
@@ -1617,6 +1626,12 @@ func (f *Fs) findImportFormat(ctx context.Context, mimeType string) string {
 // This should return ErrDirNotFound if the directory isn't
 // found.
 func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	return f.ListHinted(ctx, dir, nil)
+}
+
+// ListHinted lists the objects and directories in dir like List but
+// only lists the files modified in the time range of the hint.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
 	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
 	if err != nil {
 		return nil, err
@@ -1624,7 +1639,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 	directoryID = actualID(directoryID)
 
 	var iErr error
-	_, err = f.list(ctx, []string{directoryID}, "", false, false, f.opt.TrashedOnly, false, func(item *drive.File) bool {
+	_, err = f.list(ctx, []string{directoryID}, "", false, false, f.opt.TrashedOnly, false, hint, func(item *drive.File) bool {
 		entry, err := f.itemToDirEntry(ctx, path.Join(dir, item.Name), item)
 		if err != nil {
 			iErr = err
@@ -1685,7 +1700,7 @@ func (s listRSlices) Less(i, j int) bool {
 // In each cycle it will read up to grouping entries from the in channel without blocking.
 // If an error occurs it will be send to the out channel and then return. Once the in channel is closed,
 // nil is send to the out channel and the function returns.
-func (f *Fs) listRRunner(ctx context.Context, wg *sync.WaitGroup, in chan listREntry, out chan<- error, cb func(fs.DirEntry) error, sendJob func(listREntry)) {
+func (f *Fs) listRRunner(ctx context.Context, wg *sync.WaitGroup, in chan listREntry, out chan<- error, cb func(fs.DirEntry) error, sendJob func(listREntry), hint *fs.ListHint) {
 	var dirs []string
 	var paths []string
 	var grouping int32
@@ -1709,7 +1724,7 @@ func (f *Fs) listRRunner(ctx context.Context, wg *sync.WaitGroup, in chan listRE
 		listRSlices{dirs, paths}.Sort()
 		var iErr error
 		foundItems := false
-		_, err := f.list(ctx, dirs, "", false, false, f.opt.TrashedOnly, false, func(item *drive.File) bool {
+		_, err := f.list(ctx, dirs, "", false, false, f.opt.TrashedOnly, false, hint, func(item *drive.File) bool {
 			// shared with me items have no parents when at the root
 			if f.opt.SharedWithMe && len(item.Parents) == 0 && len(paths) == 1 && paths[0] == "" {
 				item.Parents = dirs
@@ -1831,6 +1846,12 @@ func (f *Fs) listRRunner(ctx context.Context, wg *sync.WaitGroup, in chan listRE
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
 func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.ListRHinted(ctx, dir, nil, callback)
+}
+
+// ListRHinted lists recursively like ListR but only lists the files
+// modified in the time range of the hint.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
 	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
 	if err != nil {
 		return err
@@ -1880,7 +1901,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 	in <- listREntry{directoryID, dir}
 
 	for i := 0; i < f.ci.Checkers; i++ {
-		go f.listRRunner(ctx, &wg, in, out, cb, sendJob)
+		go f.listRRunner(ctx, &wg, in, out, cb, sendJob, hint)
 	}
 	go func() {
 		// wait until the all directories are processed
@@ -2187,7 +2208,7 @@ func (f *Fs) MergeDirs(ctx context.Context, dirs []fs.Directory) error {
 	for _, srcDir := range dirs[1:] {
 		// list the objects
 		infos := []*drive.File{}
-		_, err := f.list(ctx, []string{srcDir.ID()}, "", false, false, f.opt.TrashedOnly, true, func(info *drive.File) bool {
+		_, err := f.list(ctx, []string{srcDir.ID()}, "", false, false, f.opt.TrashedOnly, true, nil, func(info *drive.File) bool {
 			infos = append(infos, info)
 			return false
 		})
@@ -2265,7 +2286,7 @@ func (f *Fs) purgeCheck(ctx context.Context, dir string, check bool) error {
 	}
 	var trashedFiles = false
 	if check {
-		found, err := f.list(ctx, []string{directoryID}, "", false, false, f.opt.TrashedOnly, true, func(item *drive.File) bool {
+		found, err := f.list(ctx, []string{directoryID}, "", false, false, f.opt.TrashedOnly, true, nil, func(item *drive.File) bool {
 			if !item.Trashed {
 				fs.Debugf(dir, "Rmdir: contains file: %q", item.Name)
 				return true
@@ -2435,7 +2456,7 @@ func (r cleanupResult) Error() string {
 }
 
 func (f *Fs) cleanupTeamDrive(ctx context.Context, dir string, directoryID string) (r cleanupResult, err error) {
-	_, err = f.list(ctx, []string{directoryID}, "", false, false, true, false, func(item *drive.File) bool {
+	_, err = f.list(ctx, []string{directoryID}, "", false, false, true, false, nil, func(item *drive.File) bool {
 		remote := path.Join(dir, item.Name)
 		if item.ExplicitlyTrashed { // description is wrong - can also be set for folders - no need to recurse them
 			err := f.delete(ctx, item.Id, false)
@@ -2747,29 +2768,35 @@ func (f *Fs) changeNotifyStartPageToken(ctx context.Context) (pageToken string,
 	return startPageToken.StartPageToken, nil
 }
 
+// listChangesPage reads the page of changes at pageToken with the
+// fields passed in
+func (f *Fs) listChangesPage(ctx context.Context, pageToken string, fields googleapi.Field) (changeList *drive.ChangeList, err error) {
+	err = f.pacer.Call(func() (bool, error) {
+		changesCall := f.svc.Changes.List(pageToken).
+			Fields(fields)
+		if f.opt.ListChunk > 0 {
+			changesCall.PageSize(f.opt.ListChunk)
+		}
+		changesCall.SupportsAllDrives(true)
+		changesCall.IncludeItemsFromAllDrives(true)
+		if f.isTeamDrive {
+			changesCall.DriveId(f.opt.TeamDriveID)
+		}
+		// If using appDataFolder then need to add Spaces
+		if f.rootFolderID == "appDataFolder" {
+			changesCall.Spaces("appDataFolder")
+		}
+		changeList, err = changesCall.Context(ctx).Do()
+		return f.shouldRetry(ctx, err)
+	})
+	return changeList, err
+}
+
 func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.EntryType), startPageToken string) (newStartPageToken string, err error) {
 	pageToken := startPageToken
 	for {
 		var changeList *drive.ChangeList
-
-		err = f.pacer.Call(func() (bool, error) {
-			changesCall := f.svc.Changes.List(pageToken).
-				Fields("nextPageToken,newStartPageToken,changes(fileId,file(name,parents,mimeType))")
-			if f.opt.ListChunk > 0 {
-				changesCall.PageSize(f.opt.ListChunk)
-			}
-			changesCall.SupportsAllDrives(true)
-			changesCall.IncludeItemsFromAllDrives(true)
-			if f.isTeamDrive {
-				changesCall.DriveId(f.opt.TeamDriveID)
-			}
-			// If using appDataFolder then need to add Spaces
-			if f.rootFolderID == "appDataFolder" {
-				changesCall.Spaces("appDataFolder")
-			}
-			changeList, err = changesCall.Context(ctx).Do()
-			return f.shouldRetry(ctx, err)
-		})
+		changeList, err = f.listChangesPage(ctx, pageToken, "nextPageToken,newStartPageToken,changes(fileId,file(name,parents,mimeType))")
 		if err != nil {
 			return
 		}
@@ -2833,6 +2860,297 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	}
 }
 
+// ChangeCursor returns a cursor for the current position in the
+// changes of the remote to pass to ListChanges later.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	return f.changeNotifyStartPageToken(ctx)
+}
+
+// changePaths finds the paths of changed files from their parents
+type changePaths struct {
+	f       *Fs
+	paths   map[string]string   // path relative to the root by ID
+	outside map[string]struct{} // IDs which aren't under the root
+}
+
+// find the path of the file or directory with id, returning false if
+// it isn't under the root
+func (c *changePaths) find(ctx context.Context, id string) (p string, ok bool, err error) {
+	if p, ok = c.paths[id]; ok {
+		return p, true, nil
+	}
+	if _, outside := c.outside[id]; outside {
+		return "", false, nil
+	}
+	if p, ok = c.f.dirCache.GetInv(id); ok {
+		c.paths[id] = p
+		return p, true, nil
+	}
+	info, err := c.f.getFile(ctx, id, "name,parents")
+	if gerr, isGoogle := err.(*googleapi.Error); isGoogle && gerr.Code == 404 {
+		// Not visible to us so can't be under the root
+		c.outside[id] = struct{}{}
+		return "", false, nil
+	} else if err != nil {
+		return "", false, errors.Wrap(err, "failed to find path of changed file")
+	}
+	for _, parent := range info.Parents {
+		parentPath, ok, err := c.find(ctx, parent)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			p = path.Join(parentPath, c.f.opt.Enc.ToStandardName(info.Name))
+			c.paths[id] = p
+			return p, true, nil
+		}
+	}
+	c.outside[id] = struct{}{}
+	return "", false, nil
+}
+
+// ListChanges calls callback with the path of each entry under the
+// root changed since cursor was read and returns the cursor to use
+// next time.
+//
+// The paths of changed files are found by looking up their parents.
+// Drive doesn't say where removed and moved files used to be, so
+// those are only found if their old directory is in the directory
+// cache, otherwise fs.ErrorChangesIncomplete is returned.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	rootID, err := f.dirCache.RootID(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	c := changePaths{
+		f:       f,
+		paths:   map[string]string{rootID: ""},
+		outside: map[string]struct{}{},
+	}
+	incomplete := false
+	pageToken := cursor
+	for {
+		changeList, err := f.listChangesPage(ctx, pageToken, "nextPageToken,newStartPageToken,changes(fileId,removed,file(name,parents,mimeType))")
+		if gerr, ok := err.(*googleapi.Error); ok && (gerr.Code == 400 || gerr.Code == 404 || gerr.Code == 410) {
+			return "", fs.ErrorChangeCursorInvalid
+		} else if err != nil {
+			return "", errors.Wrap(err, "failed to list changes")
+		}
+		for _, change := range changeList.Changes {
+			// the old path is only known for directories in the cache
+			oldPath, inCache := f.dirCache.GetInv(change.FileId)
+			if inCache && oldPath != "" {
+				err = callback(oldPath, fs.EntryDirectory)
+				if err != nil {
+					return "", err
+				}
+			}
+			if change.Removed || change.File == nil {
+				if !inCache {
+					incomplete = true
+				}
+				continue
+			}
+			entryType := fs.EntryObject
+			if change.File.MimeType == driveFolderType {
+				entryType = fs.EntryDirectory
+			}
+			name := f.opt.Enc.ToStandardName(change.File.Name)
+			for _, parent := range change.File.Parents {
+				parentPath, ok, err := c.find(ctx, parent)
+				if err != nil {
+					return "", err
+				}
+				if ok {
+					err = callback(path.Join(parentPath, name), entryType)
+					if err != nil {
+						return "", err
+					}
+				}
+			}
+		}
+		if changeList.NewStartPageToken != "" {
+			newCursor = changeList.NewStartPageToken
+			break
+		}
+		if changeList.NextPageToken == "" {
+			return "", errors.New("no page token returned for changes")
+		}
+		pageToken = changeList.NextPageToken
+	}
+	if incomplete {
+		return newCursor, fs.ErrorChangesIncomplete
+	}
+	return newCursor, nil
+}
+
+// listRevisions lists the revisions of the file with the ID given,
+// oldest first
+func (f *Fs) listRevisions(ctx context.Context, ID string) (revisions []*drive.Revision, err error) {
+	list := f.svc.Revisions.List(actualID(ID)).
+		Fields("nextPageToken,revisions(id,modifiedTime,size,keepForever)").
+		PageSize(1000)
+	for {
+		var revisionList *drive.RevisionList
+		err = f.pacer.Call(func() (bool, error) {
+			revisionList, err = list.Context(ctx).Do()
+			return f.shouldRetry(ctx, err)
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list revisions")
+		}
+		revisions = append(revisions, revisionList.Revisions...)
+		if revisionList.NextPageToken == "" {
+			break
+		}
+		list.PageToken(revisionList.NextPageToken)
+	}
+	return revisions, nil
+}
+
+// ListVersions calls callback with the revisions of each file under
+// dir, newest first.
+//
+// Google docs are skipped as their revisions can't be downloaded.
+// Drive doesn't keep versions of deleted files.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	var (
+		mu      sync.Mutex
+		objects []*Object
+	)
+	err := f.ListR(ctx, dir, func(entries fs.DirEntries) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, entry := range entries {
+			if o, ok := entry.(*Object); ok && o.mimeType != shortcutMimeTypeDangling {
+				objects = append(objects, o)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].remote < objects[j].remote
+	})
+	for _, o := range objects {
+		revisions, err := f.listRevisions(ctx, o.id)
+		if err != nil {
+			return errors.Wrapf(err, "%s", o.remote)
+		}
+		versions := make([]fs.ObjectVersion, 0, len(revisions))
+		for i := len(revisions) - 1; i >= 0; i-- {
+			revision := revisions[i]
+			t, err := time.Parse(timeFormatIn, revision.ModifiedTime)
+			if err != nil {
+				fs.Debugf(o, "Bad revision time %q: %v", revision.ModifiedTime, err)
+			}
+			version := fs.ObjectVersion{
+				ID:      revision.Id,
+				Time:    t,
+				ModTime: t,
+				Size:    revision.Size,
+				Current: len(versions) == 0,
+			}
+			// The file has the modification time of the
+			// current revision
+			if version.Current {
+				version.ModTime = o.ModTime(ctx)
+			}
+			versions = append(versions, version)
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		err = callback(o.remote, versions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionObject finds the file at remote to use for its revisions
+func (f *Fs) versionObject(ctx context.Context, remote string) (*Object, error) {
+	obj, err := f.NewObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	o, ok := obj.(*Object)
+	if !ok || o.mimeType == shortcutMimeTypeDangling {
+		return nil, errors.Errorf("can't use revisions of %q as it isn't a regular file", remote)
+	}
+	return o, nil
+}
+
+// OpenVersion opens the revision of the file at remote with the ID
+// given for reading.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	o, err := f.versionObject(ctx, remote)
+	if err != nil {
+		return nil, err
+	}
+	var revision *drive.Revision
+	err = f.pacer.Call(func() (bool, error) {
+		revision, err = f.svc.Revisions.Get(actualID(o.id), versionID).
+			Fields("size").
+			Context(ctx).Do()
+		return f.shouldRetry(ctx, err)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read revision")
+	}
+	// Read the revision with its own size so range requests work
+	revisionObject := o.baseObject
+	revisionObject.bytes = revision.Size
+	url := fmt.Sprintf("%sfiles/%s/revisions/%s?alt=media", f.svc.BasePath, actualID(o.id), versionID)
+	return revisionObject.open(ctx, url, options...)
+}
+
+// RestoreVersion makes the revision of the file at remote with the ID
+// given the current one by uploading it as a new revision.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) (err error) {
+	o, err := f.versionObject(ctx, remote)
+	if err != nil {
+		return err
+	}
+	var revision *drive.Revision
+	err = f.pacer.Call(func() (bool, error) {
+		revision, err = f.svc.Revisions.Get(actualID(o.id), versionID).
+			Fields("size,modifiedTime").
+			Context(ctx).Do()
+		return f.shouldRetry(ctx, err)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to read revision")
+	}
+	modTime, err := time.Parse(timeFormatIn, revision.ModifiedTime)
+	if err != nil {
+		return errors.Wrapf(err, "bad revision time %q", revision.ModifiedTime)
+	}
+	in, err := f.OpenVersion(ctx, remote, versionID)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	src := object.NewStaticObjectInfo(remote, modTime, revision.Size, true, nil, f)
+	return o.Update(ctx, in, src)
+}
+
+// DeleteVersion deletes the revision of the file at remote with the
+// ID given.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	o, err := f.versionObject(ctx, remote)
+	if err != nil {
+		return err
+	}
+	return f.pacer.Call(func() (bool, error) {
+		err = f.svc.Revisions.Delete(actualID(o.id), versionID).Context(ctx).Do()
+		return f.shouldRetry(ctx, err)
+	})
+}
+
 // DirCacheFlush resets the directory cache - used in testing as an
 // optional interface
 func (f *Fs) DirCacheFlush() {
@@ -3012,7 +3330,7 @@ func (r unTrashResult) Error() string {
 func (f *Fs) unTrash(ctx context.Context, dir string, directoryID string, recurse bool) (r unTrashResult, err error) {
 	directoryID = actualID(directoryID)
 	fs.Debugf(dir, "finding trash to restore in directory %q", directoryID)
-	_, err = f.list(ctx, []string{directoryID}, "", false, false, f.opt.TrashedOnly, true, func(item *drive.File) bool {
+	_, err = f.list(ctx, []string{directoryID}, "", false, false, f.opt.TrashedOnly, true, nil, func(item *drive.File) bool {
 		remote := path.Join(dir, item.Name)
 		if item.ExplicitlyTrashed {
 			fs.Infof(remote, "restoring %q", item.Id)
@@ -3407,7 +3725,7 @@ func (f *Fs) getRemoteInfoWithExport(ctx context.Context, remote string) (
 	}
 	directoryID = actualID(directoryID)
 
-	found, err := f.list(ctx, []string{directoryID}, leaf, false, false, f.opt.TrashedOnly, false, func(item *drive.File) bool {
+	found, err := f.list(ctx, []string{directoryID}, leaf, false, false, f.opt.TrashedOnly, false, nil, func(item *drive.File) bool {
 		if !f.opt.SkipGdocs {
 			extension, exportName, exportMimeType, isDocument = f.findExportFormat(ctx, item)
 			if exportName == leaf {
@@ -3853,11 +4171,15 @@ var (
 	_ fs.Commander       = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.ChangeNotifier  = (*Fs)(nil)
+	_ fs.ChangeLister    = (*Fs)(nil)
 	_ fs.PutUncheckeder  = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListHinter      = (*Fs)(nil)
+	_ fs.ListRHinter     = (*Fs)(nil)
 	_ fs.MergeDirser     = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.Versioner       = (*Fs)(nil)
 	_ fs.Object          = (*Object)(nil)
 	_ fs.MimeTyper       = (*Object)(nil)
 	_ fs.IDer            = (*Object)(nil)
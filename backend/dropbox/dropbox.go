@@ -1395,6 +1395,17 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 		time.Sleep(time.Duration(res.Backoff) * time.Second)
 	}
 
+	return f.listChanges(ctx, cursor, false, func(entryPath string, entryType fs.EntryType) error {
+		notifyFunc(entryPath, entryType)
+		return nil
+	})
+}
+
+// listChanges calls fn with the paths changed since cursor and
+// returns the cursor for the next changes.
+//
+// Deleted entries are given as objects unless deletedDirs is set.
+func (f *Fs) listChanges(ctx context.Context, cursor string, deletedDirs bool, fn func(entryPath string, entryType fs.EntryType) error) (newCursor string, err error) {
 	for {
 		var changeList *files.ListFolderResult
 
@@ -1406,6 +1417,9 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 			return shouldRetry(ctx, err)
 		})
 		if err != nil {
+			if e, ok := err.(files.ListFolderContinueAPIError); ok && e.EndpointError != nil && e.EndpointError.Tag == files.ListFolderContinueErrorReset {
+				return "", fs.ErrorChangeCursorInvalid
+			}
 			return "", errors.Wrap(err, "list continue")
 		}
 		cursor = changeList.Cursor
@@ -1420,7 +1434,11 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 				entryType = fs.EntryObject
 				entryPath = strings.TrimPrefix(info.PathDisplay, f.slashRootSlash)
 			case *files.DeletedMetadata:
+				// Deleted entries may have been files or folders
 				entryType = fs.EntryObject
+				if deletedDirs {
+					entryType = fs.EntryDirectory
+				}
 				entryPath = strings.TrimPrefix(info.PathDisplay, f.slashRootSlash)
 			default:
 				fs.Errorf(entry, "dropbox ChangeNotify: ignoring unknown EntryType %T", entry)
@@ -1428,7 +1446,10 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 			}
 
 			if entryPath != "" {
-				notifyFunc(entryPath, entryType)
+				err = fn(entryPath, entryType)
+				if err != nil {
+					return "", err
+				}
 			}
 		}
 		if !changeList.HasMore {
@@ -1438,6 +1459,25 @@ func (f *Fs) changeNotifyRunner(ctx context.Context, notifyFunc func(string, fs.
 	return cursor, nil
 }
 
+// ChangeCursor returns a cursor for the current position in the
+// changes of the remote to pass to ListChanges later.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	return f.changeNotifyCursor(ctx)
+}
+
+// ListChanges calls callback with the path of each entry under the
+// root changed since cursor was read and returns the cursor to use
+// next time.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	return f.listChanges(ctx, cursor, true, func(entryPath string, entryType fs.EntryType) error {
+		if strings.HasPrefix(entryPath, "/") {
+			// the root itself
+			return nil
+		}
+		return callback(f.opt.Enc.ToStandardPath(entryPath), entryType)
+	})
+}
+
 // Hashes returns the supported hash sets.
 func (f *Fs) Hashes() hash.Set {
 	return hash.Set(DbHashType)
@@ -1800,6 +1840,7 @@ var (
 	_ fs.PublicLinker = (*Fs)(nil)
 	_ fs.DirMover     = (*Fs)(nil)
 	_ fs.Abouter      = (*Fs)(nil)
+	_ fs.ChangeLister = (*Fs)(nil)
 	_ fs.Shutdowner   = &Fs{}
 	_ fs.Object       = (*Object)(nil)
 	_ fs.IDer         = (*Object)(nil)
@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -508,14 +509,40 @@ type listFn func(remote string, object *storage.Object, isDirectory bool) error
 //
 // The remote has prefix removed from it and if addBucket is set
 // then it adds the bucket to the start.
-func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, fn listFn) (err error) {
+//
+// If hint is set then only the objects with its prefixes which sort
+// after its StartAfter are listed.
+func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, hint *fs.ListHint, fn listFn) (err error) {
 	if prefix != "" {
 		prefix += "/"
 	}
 	if directory != "" {
 		directory += "/"
 	}
-	list := f.svc.Objects.List(bucket).Prefix(directory).MaxResults(listChunks)
+	startOffset := ""
+	if startAfter := hint.EncodedStartAfter(f.opt.Enc.FromStandardPath); startAfter != "" {
+		startOffset = directory + startAfter
+	}
+	listPrefixes := hint.EncodedPrefixes(f.opt.Enc.FromStandardPath)
+	if len(listPrefixes) == 0 {
+		return f.listPrefix(ctx, bucket, directory, prefix, addBucket, recurse, startOffset, fn)
+	}
+	for _, listPrefix := range listPrefixes {
+		err = f.listPrefix(ctx, bucket, directory+listPrefix, prefix, addBucket, recurse, startOffset, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listPrefix lists the objects with names starting with listPrefix
+// from startOffset into the function supplied, as described in list.
+func (f *Fs) listPrefix(ctx context.Context, bucket, listPrefix, prefix string, addBucket bool, recurse bool, startOffset string, fn listFn) (err error) {
+	list := f.svc.Objects.List(bucket).Prefix(listPrefix).MaxResults(listChunks)
+	if startOffset != "" {
+		list = list.StartOffset(startOffset)
+	}
 	if !recurse {
 		list = list.Delimiter("/")
 	}
@@ -596,9 +623,9 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *storage.
 }
 
 // listDir lists a single directory
-func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool) (entries fs.DirEntries, err error) {
+func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, hint *fs.ListHint) (entries fs.DirEntries, err error) {
 	// List the objects
-	err = f.list(ctx, bucket, directory, prefix, addBucket, false, func(remote string, object *storage.Object, isDirectory bool) error {
+	err = f.list(ctx, bucket, directory, prefix, addBucket, false, hint, func(remote string, object *storage.Object, isDirectory bool) error {
 		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
@@ -653,6 +680,13 @@ func (f *Fs) listBuckets(ctx context.Context) (entries fs.DirEntries, err error)
 // This should return ErrDirNotFound if the directory isn't
 // found.
 func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	return f.ListHinted(ctx, dir, nil)
+}
+
+// ListHinted lists the objects and directories in dir like List but
+// only lists the objects with the prefixes in the hint and after its
+// StartAfter.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
 	bucket, directory := f.split(dir)
 	if bucket == "" {
 		if directory != "" {
@@ -660,7 +694,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 		}
 		return f.listBuckets(ctx)
 	}
-	return f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "")
+	return f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", hint)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -680,10 +714,16 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
 func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.ListRHinted(ctx, dir, nil, callback)
+}
+
+// ListRHinted lists recursively like ListR but only lists the objects
+// with the prefixes in the hint and after its StartAfter.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
 	bucket, directory := f.split(dir)
 	list := walk.NewListRHelper(callback)
-	listR := func(bucket, directory, prefix string, addBucket bool) error {
-		return f.list(ctx, bucket, directory, prefix, addBucket, true, func(remote string, object *storage.Object, isDirectory bool) error {
+	listR := func(bucket, directory, prefix string, addBucket bool, hint *fs.ListHint) error {
+		return f.list(ctx, bucket, directory, prefix, addBucket, true, hint, func(remote string, object *storage.Object, isDirectory bool) error {
 			entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 			if err != nil {
 				return err
@@ -702,7 +742,9 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 				return err
 			}
 			bucket := entry.Remote()
-			err = listR(bucket, "", f.rootDirectory, true)
+			// The hint is relative to the root so can't be used
+			// within each bucket
+			err = listR(bucket, "", f.rootDirectory, true, nil)
 			if err != nil {
 				return err
 			}
@@ -710,7 +752,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 			f.cache.MarkOK(bucket)
 		}
 	} else {
-		err = listR(bucket, directory, f.rootDirectory, f.rootBucket == "")
+		err = listR(bucket, directory, f.rootDirectory, f.rootBucket == "", hint)
 		if err != nil {
 			return err
 		}
@@ -843,6 +885,16 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 
 	rewriteRequest := f.svc.Objects.Rewrite(srcBucket, srcPath, dstBucket, dstPath, nil)
+	err = f.rewrite(ctx, rewriteRequest, dstObj)
+	if err != nil {
+		return nil, err
+	}
+	return dstObj, nil
+}
+
+// rewrite does the server-side copy in rewriteRequest to dstObj
+// setting its metadata
+func (f *Fs) rewrite(ctx context.Context, rewriteRequest *storage.ObjectsRewriteCall, dstObj *Object) (err error) {
 	if !f.opt.BucketPolicyOnly {
 		rewriteRequest.DestinationPredefinedAcl(f.opt.ObjectACL)
 	}
@@ -853,7 +905,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 			return shouldRetry(ctx, err)
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if rewriteResponse.Done {
 			break
@@ -863,7 +915,7 @@ func (f *Fs) Copy(ctx context.Context, src fs.Object, remote string) (fs.Object,
 	}
 	// Set the metadata for the new object while we have it
 	dstObj.setMetaData(rewriteResponse.Resource)
-	return dstObj, nil
+	return nil
 }
 
 // Hashes returns the supported hash sets.
@@ -871,6 +923,194 @@ func (f *Fs) Hashes() hash.Set {
 	return hash.Set(hash.MD5)
 }
 
+// parseGeneration parses a version ID into an object generation
+func parseGeneration(versionID string) (int64, error) {
+	generation, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("bad version ID %q: must be an object generation", versionID)
+	}
+	return generation, nil
+}
+
+// ListVersions calls callback with the versions of each object under
+// dir, newest first.
+//
+// The bucket must have object versioning enabled for old versions to
+// be kept. The version IDs are the object generations. An object with
+// no live version has a Deleted version at the time its newest
+// version was deleted.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) (err error) {
+	bucket, directory := f.split(dir)
+	if bucket == "" {
+		return errors.New("can't list versions without a bucket")
+	}
+	prefix := f.rootDirectory
+	if prefix != "" {
+		prefix += "/"
+	}
+	if directory != "" {
+		directory += "/"
+	}
+	var (
+		last    string
+		objects []*storage.Object
+	)
+	// send the versions of last to the callback
+	flush := func() error {
+		if len(objects) == 0 {
+			return nil
+		}
+		sort.Slice(objects, func(i, j int) bool {
+			return objects[i].Generation > objects[j].Generation
+		})
+		var versions []fs.ObjectVersion
+		if timeDeleted := objects[0].TimeDeleted; timeDeleted != "" {
+			t, err := time.Parse(time.RFC3339, timeDeleted)
+			if err != nil {
+				fs.Debugf(last, "Bad deletion time %q: %v", timeDeleted, err)
+			} else {
+				versions = append(versions, fs.ObjectVersion{
+					Time:    t,
+					ModTime: t,
+					Current: true,
+					Deleted: true,
+				})
+			}
+		}
+		for _, object := range objects {
+			o := &Object{
+				fs:     f,
+				remote: last,
+			}
+			o.setMetaData(object)
+			t, err := time.Parse(time.RFC3339, object.TimeCreated)
+			if err != nil {
+				fs.Debugf(o, "Bad creation time %q: %v", object.TimeCreated, err)
+				t = o.modTime
+			}
+			versions = append(versions, fs.ObjectVersion{
+				ID:      strconv.FormatInt(object.Generation, 10),
+				Time:    t,
+				ModTime: o.modTime,
+				Size:    o.bytes,
+				Current: object.TimeDeleted == "",
+			})
+		}
+		objects = nil
+		return callback(last, versions)
+	}
+	list := f.svc.Objects.List(bucket).Prefix(directory).Versions(true).MaxResults(listChunks)
+	for {
+		var page *storage.Objects
+		err = f.pacer.Call(func() (bool, error) {
+			page, err = list.Context(ctx).Do()
+			return shouldRetry(ctx, err)
+		})
+		if err != nil {
+			if gErr, ok := err.(*googleapi.Error); ok && gErr.Code == http.StatusNotFound {
+				return fs.ErrorDirNotFound
+			}
+			return err
+		}
+		for _, object := range page.Items {
+			remote := f.opt.Enc.ToStandardPath(object.Name)
+			if !strings.HasPrefix(remote, prefix) {
+				fs.Logf(f, "Odd name received %q", object.Name)
+				continue
+			}
+			remote = remote[len(prefix):]
+			// skip directory markers
+			if remote == "" || strings.HasSuffix(remote, "/") {
+				continue
+			}
+			if f.rootBucket == "" {
+				remote = path.Join(bucket, remote)
+			}
+			if remote != last {
+				err = flush()
+				if err != nil {
+					return err
+				}
+				last = remote
+			}
+			objects = append(objects, object)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		list.PageToken(page.NextPageToken)
+	}
+	return flush()
+}
+
+// newVersionObject returns the version of the object at remote with
+// the ID given with its metadata read
+func (f *Fs) newVersionObject(ctx context.Context, remote string, versionID string) (*Object, error) {
+	generation, err := parseGeneration(versionID)
+	if err != nil {
+		return nil, err
+	}
+	o := &Object{
+		fs:     f,
+		remote: remote,
+	}
+	bucket, bucketPath := o.split()
+	var object *storage.Object
+	err = f.pacer.Call(func() (bool, error) {
+		object, err = f.svc.Objects.Get(bucket, bucketPath).Generation(generation).Context(ctx).Do()
+		return shouldRetry(ctx, err)
+	})
+	if err != nil {
+		if gErr, ok := err.(*googleapi.Error); ok && gErr.Code == http.StatusNotFound {
+			return nil, fs.ErrorObjectNotFound
+		}
+		return nil, err
+	}
+	// The MediaLink reads this generation
+	o.setMetaData(object)
+	return o, nil
+}
+
+// OpenVersion opens the version of the object at remote with the ID
+// given for reading.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	o, err := f.newVersionObject(ctx, remote, versionID)
+	if err != nil {
+		return nil, err
+	}
+	return o.Open(ctx, options...)
+}
+
+// RestoreVersion makes the version of the object at remote with the
+// ID given the current version by copying it over the object.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	generation, err := parseGeneration(versionID)
+	if err != nil {
+		return err
+	}
+	dstObj := &Object{
+		fs:     f,
+		remote: remote,
+	}
+	bucket, bucketPath := dstObj.split()
+	rewriteRequest := f.svc.Objects.Rewrite(bucket, bucketPath, bucket, bucketPath, nil).SourceGeneration(generation)
+	return f.rewrite(ctx, rewriteRequest, dstObj)
+}
+
+// DeleteVersion deletes the version of the object at remote with the
+// ID given.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) (err error) {
+	generation, err := parseGeneration(versionID)
+	if err != nil {
+		return err
+	}
+	bucket, bucketPath := f.split(remote)
+	return f.pacer.Call(func() (bool, error) {
+		err = f.svc.Objects.Delete(bucket, bucketPath).Generation(generation).Context(ctx).Do()
+		return shouldRetry(ctx, err)
+	})
+}
+
 // ------------------------------------------------------------
 
 // Fs returns the parent Fs
@@ -1154,6 +1394,9 @@ var (
 	_ fs.Copier      = &Fs{}
 	_ fs.PutStreamer = &Fs{}
 	_ fs.ListRer     = &Fs{}
+	_ fs.ListHinter  = &Fs{}
+	_ fs.ListRHinter = &Fs{}
+	_ fs.Versioner   = &Fs{}
 	_ fs.Object      = &Object{}
 	_ fs.MimeTyper   = &Object{}
 )
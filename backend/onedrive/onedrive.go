@@ -212,6 +212,24 @@ this flag there.
 			Help: `Set the password for links created by the link command.
 
 At the time of writing this only works with OneDrive personal paid accounts.
+`,
+			Advanced: true,
+		}, {
+			Name:    "delta",
+			Default: false,
+			Help: `Use delta listings to implement recursive listings.
+
+If set rclone will read the changes feed of the drive with the delta
+API to list recursively (as used by --fast-list) instead of listing
+each directory.
+
+When filtering by modification time with --max-age on OneDrive for
+Business and SharePoint only the items changed since then are read,
+which can be very much quicker on large drives.
+
+Note that this reads the changes of the whole drive on OneDrive for
+Business and SharePoint, so it is best used when the remote is most of
+the drive.
 `,
 			Advanced: true,
 		}, {
@@ -534,6 +552,7 @@ type Options struct {
 	LinkScope               string               `config:"link_scope"`
 	LinkType                string               `config:"link_type"`
 	LinkPassword            string               `config:"link_password"`
+	Delta                   bool                 `config:"delta"`
 	Enc                     encoder.MultiEncoder `config:"encoding"`
 }
 
@@ -817,6 +836,10 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 		CanHaveEmptyDirectories: true,
 		ServerSideAcrossConfigs: opt.ServerSideAcrossConfigs,
 	}).Fill(ctx, f)
+	if !opt.Delta {
+		f.features.ListR = nil
+		f.features.ListRHinted = nil
+	}
 	f.srv.SetErrorHandler(errorHandler)
 
 	// Renew the token in the background
@@ -1022,27 +1045,13 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 	}
 	var iErr error
 	_, err = f.listAll(ctx, directoryID, false, false, func(info *api.Item) bool {
-		if !f.opt.ExposeOneNoteFiles && info.GetPackageType() == api.PackageTypeOneNote {
-			fs.Debugf(info.Name, "OneNote file not shown in directory listing")
-			return false
-		}
-
-		remote := path.Join(dir, info.GetName())
-		folder := info.GetFolder()
-		if folder != nil {
-			// cache the directory ID for later lookups
-			id := info.GetID()
-			f.dirCache.Put(remote, id)
-			d := fs.NewDir(remote, time.Time(info.GetLastModifiedDateTime())).SetID(id)
-			d.SetItems(folder.ChildCount)
-			entries = append(entries, d)
-		} else {
-			o, err := f.newObjectWithInfo(ctx, remote, info)
-			if err != nil {
-				iErr = err
-				return true
-			}
-			entries = append(entries, o)
+		entry, err := f.itemToDirEntry(ctx, dir, info)
+		if err != nil {
+			iErr = err
+			return true
+		}
+		if entry != nil {
+			entries = append(entries, entry)
 		}
 		return false
 	})
@@ -1055,6 +1064,344 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 	return entries, nil
 }
 
+// itemToDirEntry converts info, an item in dir, into a DirEntry,
+// returning nil if it shouldn't be listed
+func (f *Fs) itemToDirEntry(ctx context.Context, dir string, info *api.Item) (entry fs.DirEntry, err error) {
+	if !f.opt.ExposeOneNoteFiles && info.GetPackageType() == api.PackageTypeOneNote {
+		fs.Debugf(info.Name, "OneNote file not shown in directory listing")
+		return nil, nil
+	}
+	remote := path.Join(dir, info.GetName())
+	folder := info.GetFolder()
+	if folder != nil {
+		// cache the directory ID for later lookups
+		id := info.GetID()
+		f.dirCache.Put(remote, id)
+		d := fs.NewDir(remote, time.Time(info.GetLastModifiedDateTime())).SetID(id)
+		d.SetItems(folder.ChildCount)
+		return d, nil
+	}
+	return f.newObjectWithInfo(ctx, remote, info)
+}
+
+// ListR lists the objects and directories of the Fs starting
+// from dir recursively into out using the delta API.
+//
+// dir should be "" to start from the root, and should not
+// have trailing slashes.
+//
+// This should return ErrDirNotFound if the directory isn't
+// found.
+//
+// It should call callback for each tranche of entries read.
+// These need not be returned in any particular order.  If
+// callback returns an error then the listing will stop
+// immediately.
+//
+// This is only used if --onedrive-delta is set.
+func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.ListRHinted(ctx, dir, nil, callback)
+}
+
+// ListRHinted lists recursively like ListR but on OneDrive for
+// Business and SharePoint only reads the items changed since the
+// ModifiedSince of the hint.
+//
+// The delta API is only supported on the root of the drive on
+// OneDrive for Business and SharePoint so the changes of the whole
+// drive are read and those outside dir are discarded.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
+	directoryID, err := f.dirCache.FindDir(ctx, dir, false)
+	if err != nil {
+		return err
+	}
+	opts := f.deltaOpts(ctx, directoryID)
+	if hint != nil && !hint.ModifiedSince.IsZero() && f.driveType != driveTypePersonal {
+		// A time stamp can be used as the token on OneDrive
+		// for Business and SharePoint to read the changes
+		// since then
+		opts.Parameters.Set("token", hint.ModifiedSince.UTC().Format(time.RFC3339))
+	}
+	items := map[string]*api.Item{}
+	_, err = f.readDelta(ctx, opts, items)
+	if err == fs.ErrorChangeCursorInvalid && opts.Parameters.Get("token") != "" {
+		// The time stamp is too old so read everything
+		fs.Debugf(f, "Reading all of the delta listing as the changes since %v are unavailable", hint.ModifiedSince)
+		items = map[string]*api.Item{}
+		_, err = f.readDelta(ctx, f.deltaOpts(ctx, directoryID), items)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Find the paths of the items from their parents
+	paths := deltaPaths{
+		f:     f,
+		items: items,
+		dir:   dir,
+		paths: map[string]string{directoryID: dir},
+	}
+	list := walk.NewListRHelper(callback)
+	for id, item := range items {
+		if item.Deleted != nil || id == directoryID {
+			continue
+		}
+		ref := item.GetParentReference()
+		if ref == nil || ref.ID == "" {
+			continue
+		}
+		parent, inDir, err := paths.find(ctx, ref.DriveID+"#"+ref.ID)
+		if err != nil {
+			return err
+		}
+		if !inDir {
+			continue
+		}
+		entry, err := f.itemToDirEntry(ctx, parent, item)
+		if err != nil {
+			return err
+		}
+		if entry != nil {
+			err = list.Add(entry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return list.Flush()
+}
+
+// deltaOpts returns the options to read the delta listing of
+// directoryID.
+//
+// The delta API is only supported on the root of the drive on
+// OneDrive for Business and SharePoint so these read the whole
+// drive.
+func (f *Fs) deltaOpts(ctx context.Context, directoryID string) (opts rest.Opts) {
+	if f.driveType == driveTypePersonal {
+		opts = f.newOptsCall(directoryID, "GET", "/delta")
+	} else {
+		opts = f.newOptsCallWithPath(ctx, "", "GET", "/delta")
+	}
+	opts.Parameters = url.Values{}
+	opts.Parameters.Set("$top", strconv.FormatInt(f.opt.ListChunk, 10))
+	return opts
+}
+
+// readDelta reads the pages of the delta listing from opts into
+// items by ID and returns the delta link to read the next changes
+// from.
+//
+// The same item may be returned more than once with the last one
+// being the current one so they are collected before being used.
+//
+// It returns fs.ErrorChangeCursorInvalid if the token can't be used.
+func (f *Fs) readDelta(ctx context.Context, opts rest.Opts, items map[string]*api.Item) (deltaLink string, err error) {
+	for {
+		var result api.ViewDeltaResponse
+		var resp *http.Response
+		err = f.pacer.Call(func() (bool, error) {
+			resp, err = f.srv.CallJSON(ctx, &opts, nil, &result)
+			return shouldRetry(ctx, resp, err)
+		})
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusGone {
+				return "", fs.ErrorChangeCursorInvalid
+			}
+			return "", errors.Wrap(err, "couldn't list changes")
+		}
+		for i := range result.Value {
+			item := &result.Value[i]
+			item.Name = f.opt.Enc.ToStandardName(item.GetName())
+			items[item.GetID()] = item
+		}
+		if result.NextLink == "" {
+			return result.DeltaLink, nil
+		}
+		opts.Path = ""
+		opts.RootURL = result.NextLink
+		opts.Parameters = nil
+	}
+}
+
+// ChangeCursor returns a cursor for the current position in the
+// changes of the remote to pass to ListChanges later.
+//
+// This is the delta link of the root.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	rootID, err := f.dirCache.RootID(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	opts := f.deltaOpts(ctx, rootID)
+	opts.Parameters.Set("token", "latest")
+	deltaLink, err := f.readDelta(ctx, opts, map[string]*api.Item{})
+	if err != nil {
+		return "", err
+	}
+	if deltaLink == "" {
+		return "", errors.New("no delta link returned")
+	}
+	return deltaLink, nil
+}
+
+// ListChanges calls callback with the path of each entry under the
+// root changed since cursor was read and returns the cursor to use
+// next time.
+//
+// Deleted items are given as directories. The delta listing doesn't
+// say where moved items used to be so their old paths are only
+// found if they are in the directory cache. If the paths of some
+// deleted items can't be found fs.ErrorChangesIncomplete is
+// returned.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	rootID, err := f.dirCache.RootID(ctx, false)
+	if err != nil {
+		return "", err
+	}
+	items := map[string]*api.Item{}
+	newCursor, err = f.readDelta(ctx, rest.Opts{
+		Method:  "GET",
+		RootURL: cursor,
+	}, items)
+	if err != nil {
+		return "", err
+	}
+	if newCursor == "" {
+		return "", errors.New("no delta link returned")
+	}
+	paths := deltaPaths{
+		f:     f,
+		items: items,
+		paths: map[string]string{rootID: ""},
+	}
+	incomplete := false
+	for id, item := range items {
+		if id == rootID {
+			continue
+		}
+		if oldPath, ok := f.dirCache.GetInv(id); ok && oldPath != "" {
+			err = callback(oldPath, fs.EntryDirectory)
+			if err != nil {
+				return "", err
+			}
+		}
+		entryType := fs.EntryObject
+		if item.GetFolder() != nil || item.Deleted != nil {
+			entryType = fs.EntryDirectory
+		}
+		ref := item.GetParentReference()
+		if ref == nil || ref.ID == "" || item.GetName() == "" {
+			if item.Deleted != nil {
+				incomplete = true
+			}
+			continue
+		}
+		parent, inDir, err := paths.find(ctx, ref.DriveID+"#"+ref.ID)
+		if err != nil {
+			if item.Deleted != nil {
+				// the parent may have been deleted too
+				incomplete = true
+				continue
+			}
+			return "", err
+		}
+		if !inDir {
+			continue
+		}
+		err = callback(path.Join(parent, item.GetName()), entryType)
+		if err != nil {
+			return "", err
+		}
+	}
+	if incomplete {
+		return newCursor, fs.ErrorChangesIncomplete
+	}
+	return newCursor, nil
+}
+
+// deltaPaths finds the paths of the folders in a delta listing.
+//
+// The items in delta listings don't have their paths set so these
+// are found by following their parents up to the directory being
+// listed. Parents which aren't in the listing haven't changed so
+// their paths are looked up in the directory cache, and read and
+// added to it if they aren't there, so later delta runs don't need to
+// read them again.
+type deltaPaths struct {
+	f     *Fs
+	items map[string]*api.Item // items in the listing by ID
+	dir   string               // path of the directory being listed
+	paths map[string]string    // paths of the folders found by ID
+	out   map[string]struct{}  // IDs of folders found not in the directory
+}
+
+// cached returns the path of the unchanged folder with the normalized
+// ID given from the directory cache if it is there
+func (p *deltaPaths) cached(id string) (folderPath string, inDir bool, ok bool) {
+	folderPath, ok = p.f.dirCache.GetInv(id)
+	if !ok {
+		return "", false, false
+	}
+	inDir = p.dir == "" || folderPath == p.dir || strings.HasPrefix(folderPath, p.dir+"/")
+	return folderPath, inDir, true
+}
+
+// find returns the path of the folder with the normalized ID given
+// and whether it is in the directory being listed
+func (p *deltaPaths) find(ctx context.Context, id string) (folderPath string, inDir bool, err error) {
+	if folderPath, ok := p.paths[id]; ok {
+		return folderPath, true, nil
+	}
+	if _, ok := p.out[id]; ok {
+		return "", false, nil
+	}
+	item := p.items[id]
+	read := false
+	if item == nil {
+		if folderPath, inDir, ok := p.cached(id); ok {
+			p.remember(id, folderPath, inDir)
+			return folderPath, inDir, nil
+		}
+		read = true
+		item, _, err = p.f.readMetaDataForPathRelativeToID(ctx, id, "")
+		if err != nil {
+			return "", false, errors.Wrap(err, "couldn't read parent of changed item")
+		}
+		item.Name = p.f.opt.Enc.ToStandardName(item.GetName())
+	}
+	ref := item.GetParentReference()
+	if ref != nil && ref.ID != "" {
+		folderPath, inDir, err = p.find(ctx, ref.DriveID+"#"+ref.ID)
+		if err != nil {
+			return "", false, err
+		}
+	}
+	if !inDir {
+		p.remember(id, "", false)
+		return "", false, nil
+	}
+	folderPath = path.Join(folderPath, item.GetName())
+	p.remember(id, folderPath, true)
+	if read {
+		p.f.dirCache.Put(folderPath, id)
+	}
+	return folderPath, true, nil
+}
+
+// remember the path of the folder with the normalized ID given for
+// the rest of the run
+func (p *deltaPaths) remember(id, folderPath string, inDir bool) {
+	if !inDir {
+		if p.out == nil {
+			p.out = map[string]struct{}{}
+		}
+		p.out[id] = struct{}{}
+		return
+	}
+	p.paths[id] = folderPath
+}
+
 // Creates from the parameters passed in a half finished Object which
 // must have setMetaData called on it
 //
@@ -2289,6 +2636,9 @@ var (
 	_ fs.DirMover        = (*Fs)(nil)
 	_ fs.DirCacheFlusher = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
+	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListRHinter     = (*Fs)(nil)
+	_ fs.ChangeLister    = (*Fs)(nil)
 	_ fs.PublicLinker    = (*Fs)(nil)
 	_ fs.CleanUpper      = (*Fs)(nil)
 	_ fs.Object          = (*Object)(nil)
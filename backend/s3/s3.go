@@ -1400,6 +1400,7 @@ type Object struct {
 	meta         map[string]*string // The object metadata if known - may be nil
 	mimeType     string             // MimeType of object - may be ""
 	storageClass string             // e.g. GLACIER
+	versionID    *string            // ID of the version to read if not the current one
 }
 
 // ------------------------------------------------------------
@@ -1836,19 +1837,39 @@ type listFn func(remote string, object *s3.Object, isDirectory bool) error
 // removed from it and if addBucket is set then it adds the
 // bucket to the start.
 //
-// Set recurse to read sub directories
-func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, fn listFn) error {
+// Set recurse to read sub directories. If hint is set then only the
+// keys with its prefixes which sort after its StartAfter are listed.
+func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBucket bool, recurse bool, hint *fs.ListHint, fn listFn) error {
 	if prefix != "" {
 		prefix += "/"
 	}
 	if directory != "" {
 		directory += "/"
 	}
+	var marker *string
+	if startAfter := hint.EncodedStartAfter(f.opt.Enc.FromStandardPath); startAfter != "" {
+		marker = aws.String(directory + startAfter)
+	}
+	listPrefixes := hint.EncodedPrefixes(f.opt.Enc.FromStandardPath)
+	if len(listPrefixes) == 0 {
+		return f.listPrefix(ctx, bucket, directory, prefix, addBucket, recurse, marker, fn)
+	}
+	for _, listPrefix := range listPrefixes {
+		err := f.listPrefix(ctx, bucket, directory+listPrefix, prefix, addBucket, recurse, marker, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listPrefix lists the objects with keys starting with listPrefix
+// after marker into the function supplied, as described in list.
+func (f *Fs) listPrefix(ctx context.Context, bucket, listPrefix, prefix string, addBucket bool, recurse bool, marker *string, fn listFn) error {
 	delimiter := ""
 	if !recurse {
 		delimiter = "/"
 	}
-	var marker *string
 	// URL encode the listings so we can use control characters in object names
 	// See: https://github.com/aws/aws-sdk-go/issues/1914
 	//
@@ -1870,7 +1891,7 @@ func (f *Fs) list(ctx context.Context, bucket, directory, prefix string, addBuck
 		req := s3.ListObjectsInput{
 			Bucket:    &bucket,
 			Delimiter: &delimiter,
-			Prefix:    &directory,
+			Prefix:    &listPrefix,
 			MaxKeys:   &f.opt.ListChunk,
 			Marker:    marker,
 		}
@@ -2018,9 +2039,9 @@ func (f *Fs) itemToDirEntry(ctx context.Context, remote string, object *s3.Objec
 }
 
 // listDir lists files and directories to out
-func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool) (entries fs.DirEntries, err error) {
+func (f *Fs) listDir(ctx context.Context, bucket, directory, prefix string, addBucket bool, hint *fs.ListHint) (entries fs.DirEntries, err error) {
 	// List the objects and directories
-	err = f.list(ctx, bucket, directory, prefix, addBucket, false, func(remote string, object *s3.Object, isDirectory bool) error {
+	err = f.list(ctx, bucket, directory, prefix, addBucket, false, hint, func(remote string, object *s3.Object, isDirectory bool) error {
 		entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 		if err != nil {
 			return err
@@ -2068,6 +2089,13 @@ func (f *Fs) listBuckets(ctx context.Context) (entries fs.DirEntries, err error)
 // This should return ErrDirNotFound if the directory isn't
 // found.
 func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	return f.ListHinted(ctx, dir, nil)
+}
+
+// ListHinted lists the objects and directories in dir like List but
+// only lists the keys with the prefixes in the hint and after its
+// StartAfter.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
 	bucket, directory := f.split(dir)
 	if bucket == "" {
 		if directory != "" {
@@ -2075,7 +2103,7 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 		}
 		return f.listBuckets(ctx)
 	}
-	return f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "")
+	return f.listDir(ctx, bucket, directory, f.rootDirectory, f.rootBucket == "", hint)
 }
 
 // ListR lists the objects and directories of the Fs starting
@@ -2095,10 +2123,16 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 // Don't implement this unless you have a more efficient way
 // of listing recursively than doing a directory traversal.
 func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.ListRHinted(ctx, dir, nil, callback)
+}
+
+// ListRHinted lists recursively like ListR but only lists the keys
+// with the prefixes in the hint and after its StartAfter.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
 	bucket, directory := f.split(dir)
 	list := walk.NewListRHelper(callback)
-	listR := func(bucket, directory, prefix string, addBucket bool) error {
-		return f.list(ctx, bucket, directory, prefix, addBucket, true, func(remote string, object *s3.Object, isDirectory bool) error {
+	listR := func(bucket, directory, prefix string, addBucket bool, hint *fs.ListHint) error {
+		return f.list(ctx, bucket, directory, prefix, addBucket, true, hint, func(remote string, object *s3.Object, isDirectory bool) error {
 			entry, err := f.itemToDirEntry(ctx, remote, object, isDirectory)
 			if err != nil {
 				return err
@@ -2117,7 +2151,9 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 				return err
 			}
 			bucket := entry.Remote()
-			err = listR(bucket, "", f.rootDirectory, true)
+			// The hint is relative to the root so can't be used
+			// within each bucket
+			err = listR(bucket, "", f.rootDirectory, true, nil)
 			if err != nil {
 				return err
 			}
@@ -2125,7 +2161,7 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 			f.cache.MarkOK(bucket)
 		}
 	} else {
-		err = listR(bucket, directory, f.rootDirectory, f.rootBucket == "")
+		err = listR(bucket, directory, f.rootDirectory, f.rootBucket == "", hint)
 		if err != nil {
 			return err
 		}
@@ -2254,6 +2290,9 @@ func (f *Fs) copy(ctx context.Context, req *s3.CopyObjectInput, dstBucket, dstPa
 	req.ACL = &f.opt.ACL
 	req.Key = &dstPath
 	source := pathEscape(path.Join(srcBucket, srcPath))
+	if src.versionID != nil {
+		source += "?versionId=" + url.QueryEscape(*src.versionID)
+	}
 	req.CopySource = &source
 	if f.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -2762,6 +2801,172 @@ func (f *Fs) CleanUp(ctx context.Context) (err error) {
 	return f.cleanUp(ctx, 24*time.Hour)
 }
 
+// ListVersions calls callback with the versions of each object under
+// dir, newest first.
+//
+// The bucket must have versioning enabled for old versions to be
+// kept. Delete markers are returned as Deleted versions.
+func (f *Fs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	bucket, directory := f.split(dir)
+	if bucket == "" {
+		return errors.New("can't list versions without a bucket")
+	}
+	prefix := f.rootDirectory
+	if prefix != "" {
+		prefix += "/"
+	}
+	if directory != "" {
+		directory += "/"
+	}
+	var (
+		remote   string
+		versions []fs.ObjectVersion
+	)
+	// send the versions of remote to the callback
+	flush := func() error {
+		if len(versions) == 0 {
+			return nil
+		}
+		sort.SliceStable(versions, func(i, j int) bool {
+			if versions[i].Current != versions[j].Current {
+				return versions[i].Current
+			}
+			return versions[i].Time.After(versions[j].Time)
+		})
+		err := callback(remote, versions)
+		versions = nil
+		return err
+	}
+	type keyVersion struct {
+		key     string
+		version fs.ObjectVersion
+	}
+	req := s3.ListObjectVersionsInput{
+		Bucket:  &bucket,
+		Prefix:  &directory,
+		MaxKeys: &f.opt.ListChunk,
+	}
+	for {
+		var resp *s3.ListObjectVersionsOutput
+		err := f.pacer.Call(func() (bool, error) {
+			var err error
+			resp, err = f.c.ListObjectVersionsWithContext(ctx, &req)
+			return f.shouldRetry(ctx, err)
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.RequestFailure); ok && awsErr.StatusCode() == http.StatusNotFound {
+				return fs.ErrorDirNotFound
+			}
+			return err
+		}
+		// The versions and delete markers are returned in
+		// separate lists so merge them in key order
+		var keyVersions []keyVersion
+		for _, v := range resp.Versions {
+			keyVersions = append(keyVersions, keyVersion{
+				key: aws.StringValue(v.Key),
+				version: fs.ObjectVersion{
+					ID:      aws.StringValue(v.VersionId),
+					Time:    aws.TimeValue(v.LastModified),
+					ModTime: aws.TimeValue(v.LastModified),
+					Size:    aws.Int64Value(v.Size),
+					Current: aws.BoolValue(v.IsLatest),
+				},
+			})
+		}
+		for _, d := range resp.DeleteMarkers {
+			keyVersions = append(keyVersions, keyVersion{
+				key: aws.StringValue(d.Key),
+				version: fs.ObjectVersion{
+					ID:      aws.StringValue(d.VersionId),
+					Time:    aws.TimeValue(d.LastModified),
+					ModTime: aws.TimeValue(d.LastModified),
+					Current: aws.BoolValue(d.IsLatest),
+					Deleted: true,
+				},
+			})
+		}
+		sort.SliceStable(keyVersions, func(i, j int) bool {
+			return keyVersions[i].key < keyVersions[j].key
+		})
+		for _, kv := range keyVersions {
+			name := f.opt.Enc.ToStandardPath(kv.key)
+			if !strings.HasPrefix(name, prefix) {
+				fs.Logf(f, "Odd name received %q", name)
+				continue
+			}
+			name = name[len(prefix):]
+			// skip directory markers
+			if name == "" || strings.HasSuffix(name, "/") {
+				continue
+			}
+			if f.rootBucket == "" {
+				name = path.Join(bucket, name)
+			}
+			if name != remote {
+				err = flush()
+				if err != nil {
+					return err
+				}
+				remote = name
+			}
+			versions = append(versions, kv.version)
+		}
+		if !aws.BoolValue(resp.IsTruncated) {
+			break
+		}
+		req.KeyMarker = resp.NextKeyMarker
+		req.VersionIdMarker = resp.NextVersionIdMarker
+	}
+	return flush()
+}
+
+// OpenVersion opens the version of the object at remote with the ID
+// given for reading.
+func (f *Fs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	o := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: &versionID,
+	}
+	// read the size of the version for the range options
+	err := o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return o.Open(ctx, options...)
+}
+
+// RestoreVersion makes the version of the object at remote with the
+// ID given the current version by copying it over the object.
+func (f *Fs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	srcObj := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: &versionID,
+	}
+	err := srcObj.readMetaData(ctx)
+	if err != nil {
+		return err
+	}
+	bucket, bucketPath := srcObj.split()
+	req := s3.CopyObjectInput{
+		MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+	}
+	return f.copy(ctx, &req, bucket, bucketPath, bucket, bucketPath, srcObj)
+}
+
+// DeleteVersion deletes the version of the object at remote with the
+// ID given.
+func (f *Fs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	o := &Object{
+		fs:        f,
+		remote:    remote,
+		versionID: &versionID,
+	}
+	return o.Remove(ctx)
+}
+
 // ------------------------------------------------------------
 
 // Fs returns the parent Fs
@@ -2826,8 +3031,9 @@ func (o *Object) Size() int64 {
 func (o *Object) headObject(ctx context.Context) (resp *s3.HeadObjectOutput, err error) {
 	bucket, bucketPath := o.split()
 	req := s3.HeadObjectInput{
-		Bucket: &bucket,
-		Key:    &bucketPath,
+		Bucket:    &bucket,
+		Key:       &bucketPath,
+		VersionId: o.versionID,
 	}
 	if o.fs.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -3028,13 +3234,14 @@ func (o *Object) downloadFromURL(ctx context.Context, bucketPath string, options
 func (o *Object) Open(ctx context.Context, options ...fs.OpenOption) (in io.ReadCloser, err error) {
 	bucket, bucketPath := o.split()
 
-	if o.fs.opt.DownloadURL != "" {
+	if o.fs.opt.DownloadURL != "" && o.versionID == nil {
 		return o.downloadFromURL(ctx, bucketPath, options...)
 	}
 
 	req := s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &bucketPath,
+		Bucket:    &bucket,
+		Key:       &bucketPath,
+		VersionId: o.versionID,
 	}
 	if o.fs.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -3484,8 +3691,9 @@ func (o *Object) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, op
 func (o *Object) Remove(ctx context.Context) error {
 	bucket, bucketPath := o.split()
 	req := s3.DeleteObjectInput{
-		Bucket: &bucket,
-		Key:    &bucketPath,
+		Bucket:    &bucket,
+		Key:       &bucketPath,
+		VersionId: o.versionID,
 	}
 	if o.fs.opt.RequesterPays {
 		req.RequestPayer = aws.String(s3.RequestPayerRequester)
@@ -3507,6 +3715,27 @@ func (o *Object) MimeType(ctx context.Context) string {
 	return o.mimeType
 }
 
+// Metadata returns the user metadata of the object
+//
+// S3 metadata keys are case insensitive so they are returned in
+// lower case.
+func (o *Object) Metadata(ctx context.Context) (map[string]string, error) {
+	err := o.readMetaData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.meta) == 0 {
+		return nil, nil
+	}
+	metadata := make(map[string]string, len(o.meta))
+	for k, v := range o.meta {
+		if v != nil {
+			metadata[strings.ToLower(k)] = *v
+		}
+	}
+	return metadata, nil
+}
+
 // SetTier performs changing storage class
 func (o *Object) SetTier(tier string) (err error) {
 	ctx := context.TODO()
@@ -3538,10 +3767,14 @@ var (
 	_ fs.Copier      = &Fs{}
 	_ fs.PutStreamer = &Fs{}
 	_ fs.ListRer     = &Fs{}
+	_ fs.ListHinter  = &Fs{}
+	_ fs.ListRHinter = &Fs{}
 	_ fs.Commander   = &Fs{}
 	_ fs.CleanUpper  = &Fs{}
+	_ fs.Versioner   = &Fs{}
 	_ fs.Object      = &Object{}
 	_ fs.MimeTyper   = &Object{}
+	_ fs.Metadataer  = &Object{}
 	_ fs.GetTierer   = &Object{}
 	_ fs.SetTierer   = &Object{}
 )
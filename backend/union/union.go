@@ -3,6 +3,7 @@ package union
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"path"
@@ -395,6 +396,82 @@ func (f *Fs) ChangeNotify(ctx context.Context, fn func(string, fs.EntryType), ch
 	}()
 }
 
+// ChangeCursor returns a cursor for the current position in the
+// changes of all the upstreams.
+//
+// The cursor is a JSON list of the cursors of the upstreams.
+func (f *Fs) ChangeCursor(ctx context.Context) (cursor string, err error) {
+	cursors := make([]string, len(f.upstreams))
+	errs := Errors(make([]error, len(f.upstreams)))
+	multithread(len(f.upstreams), func(i int) {
+		u := f.upstreams[i]
+		var err error
+		cursors[i], err = u.Features().ChangeCursor(ctx)
+		if err != nil {
+			errs[i] = errors.Wrap(err, u.Name())
+		}
+	})
+	if err := errs.Err(); err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(cursors)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ListChanges calls callback with each path changed in any of the
+// upstreams since cursor.
+//
+// If some upstreams couldn't find all their changes it returns the
+// new cursor with fs.ErrorChangesIncomplete.
+func (f *Fs) ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType fs.EntryType) error) (newCursor string, err error) {
+	var cursors []string
+	if json.Unmarshal([]byte(cursor), &cursors) != nil || len(cursors) != len(f.upstreams) {
+		return "", fs.ErrorChangeCursorInvalid
+	}
+	// Call callback from one goroutine at a time
+	var mu sync.Mutex
+	incomplete := false
+	errs := Errors(make([]error, len(f.upstreams)))
+	multithread(len(f.upstreams), func(i int) {
+		u := f.upstreams[i]
+		newCursor, err := u.Features().ListChanges(ctx, cursors[i], func(remote string, entryType fs.EntryType) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return callback(remote, entryType)
+		})
+		if err == fs.ErrorChangesIncomplete {
+			mu.Lock()
+			incomplete = true
+			mu.Unlock()
+			err = nil
+		}
+		if err != nil {
+			errs[i] = errors.Wrap(err, u.Name())
+			return
+		}
+		cursors[i] = newCursor
+	})
+	if err := errs.Err(); err != nil {
+		for _, e := range errs {
+			if errors.Cause(e) == fs.ErrorChangeCursorInvalid {
+				return "", fs.ErrorChangeCursorInvalid
+			}
+		}
+		return "", err
+	}
+	out, err := json.Marshal(cursors)
+	if err != nil {
+		return "", err
+	}
+	if incomplete {
+		return string(out), fs.ErrorChangesIncomplete
+	}
+	return string(out), nil
+}
+
 // DirCacheFlush resets the directory cache - used in testing
 // as an optional interface
 func (f *Fs) DirCacheFlush() {
@@ -587,11 +664,23 @@ func (f *Fs) About(ctx context.Context) (*fs.Usage, error) {
 // This should return ErrDirNotFound if the directory isn't
 // found.
 func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	return f.ListHinted(ctx, dir, nil)
+}
+
+// ListHinted lists the objects and directories in dir like List,
+// passing the hint on to the upstreams which can use it.
+func (f *Fs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (entries fs.DirEntries, err error) {
 	entriesList := make([][]upstream.Entry, len(f.upstreams))
 	errs := Errors(make([]error, len(f.upstreams)))
 	multithread(len(f.upstreams), func(i int) {
 		u := f.upstreams[i]
-		entries, err := u.List(ctx, dir)
+		var entries fs.DirEntries
+		var err error
+		if do := u.Features().ListHinted; do != nil && !hint.IsEmpty() {
+			entries, err = do(ctx, dir, hint)
+		} else {
+			entries, err = u.List(ctx, dir)
+		}
 		if err != nil {
 			errs[i] = errors.Wrap(err, u.Name())
 			return
@@ -634,6 +723,12 @@ func (f *Fs) List(ctx context.Context, dir string) (entries fs.DirEntries, err e
 // Don't implement this unless you have a more efficient way
 // of listing recursively that doing a directory traversal.
 func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (err error) {
+	return f.ListRHinted(ctx, dir, nil, callback)
+}
+
+// ListRHinted lists recursively like ListR, passing the hint on to
+// the upstreams which can use it.
+func (f *Fs) ListRHinted(ctx context.Context, dir string, hint *fs.ListHint, callback fs.ListRCallback) (err error) {
 	var entriesList [][]upstream.Entry
 	errs := Errors(make([]error, len(f.upstreams)))
 	var mutex sync.Mutex
@@ -650,8 +745,9 @@ func (f *Fs) ListR(ctx context.Context, dir string, callback fs.ListRCallback) (
 			mutex.Unlock()
 			return nil
 		}
-		do := u.Features().ListR
-		if do != nil {
+		if do := u.Features().ListRHinted; do != nil && !hint.IsEmpty() {
+			err = do(ctx, dir, hint, callback)
+		} else if do := u.Features().ListR; do != nil {
 			err = do(ctx, dir, callback)
 		} else {
 			err = walk.ListR(ctx, u, dir, true, -1, walk.ListAll, callback)
@@ -888,6 +984,17 @@ func NewFs(ctx context.Context, name, root string, m configmap.Mapper) (fs.Fs, e
 		}
 	}
 
+	// Enable the hinted listings when any upstream can use hints as
+	// the others can list as normal
+	for _, u := range upstreams {
+		if u.Features().ListHinted != nil {
+			features.ListHinted = f.ListHinted
+		}
+		if u.Features().ListRHinted != nil && features.ListR != nil {
+			features.ListRHinted = f.ListRHinted
+		}
+	}
+
 	f.features = features
 
 	// Get common intersection of hashes
@@ -933,5 +1040,8 @@ var (
 	_ fs.ChangeNotifier  = (*Fs)(nil)
 	_ fs.Abouter         = (*Fs)(nil)
 	_ fs.ListRer         = (*Fs)(nil)
+	_ fs.ListHinter      = (*Fs)(nil)
+	_ fs.ListRHinter     = (*Fs)(nil)
+	_ fs.ChangeLister    = (*Fs)(nil)
 	_ fs.Shutdowner      = (*Fs)(nil)
 )
@@ -4,12 +4,19 @@ import (
 	"context"
 
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/operations"
 	"github.com/spf13/cobra"
 )
 
+var (
+	keepVersions = -1
+)
+
 func init() {
 	cmd.Root.AddCommand(commandDefinition)
+	cmdFlags := commandDefinition.Flags()
+	flags.IntVarP(cmdFlags, &keepVersions, "keep-versions", "", keepVersions, "Delete all but this many old versions of each object")
 }
 
 var commandDefinition = &cobra.Command{
@@ -18,11 +25,21 @@ var commandDefinition = &cobra.Command{
 	Long: `
 Clean up the remote if possible.  Empty the trash or delete old file
 versions. Not supported by all remotes.
+
+If the remote keeps old versions of objects (eg s3, b2, gcs and
+azureblob with versioning enabled or drive revisions) then use
+--keep-versions N to delete all but the newest N old versions of each
+object in the path instead. The current versions are never deleted.
+Use --keep-versions 0 to delete all the old versions. This obeys the
+filters, so you can clean up only some of the objects.
 `,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(true, false, command, func() error {
+			if keepVersions >= 0 {
+				return operations.CleanUpVersions(context.Background(), fsrc, keepVersions)
+			}
 			return operations.CleanUp(context.Background(), fsrc)
 		})
 	},
@@ -292,6 +292,11 @@ func Run(Retry bool, showStats bool, cmd *cobra.Command, f func() error) {
 		}
 	}
 	stopStats()
+	if ci.DryRun {
+		if estimate := accounting.APIEstimate(); estimate != "" {
+			fs.Logf(nil, "%s", estimate)
+		}
+	}
 	if showStats && (accounting.GlobalStats().Errored() || *statsInterval > 0) {
 		accounting.GlobalStats().Log()
 	}
@@ -487,7 +492,9 @@ func resolveExitCode(err error) {
 		os.Exit(exitcode.FileNotFound)
 	case unwrapped == errorUncategorized:
 		os.Exit(exitcode.UncategorizedError)
-	case unwrapped == accounting.ErrorMaxTransferLimitReached:
+	case unwrapped == accounting.ErrorMaxTransferLimitReached,
+		unwrapped == accounting.ErrorMaxAPICallsReached,
+		unwrapped == accounting.ErrorMaxEgressReached:
 		os.Exit(exitcode.TransferExceeded)
 	case fserrors.ShouldRetry(err):
 		os.Exit(exitcode.RetryError)
@@ -3,22 +3,30 @@ package copy
 import (
 	"context"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/operations/operationsflags"
 	"github.com/rclone/rclone/fs/sync"
 	"github.com/spf13/cobra"
 )
 
 var (
 	createEmptySrcDirs = false
+	reportOpt          = operationsflags.ReportOpt{}
+	at                 = ""
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefinition)
 	cmdFlags := commandDefinition.Flags()
 	flags.BoolVarP(cmdFlags, &createEmptySrcDirs, "create-empty-src-dirs", "", createEmptySrcDirs, "Create empty source dirs on destination after copy")
+	operationsflags.AddReportFlags(cmdFlags, &reportOpt)
+	flags.StringVarP(cmdFlags, &at, "at", "", at, "Copy the versions of the files at this time or this long ago")
 }
 
 var commandDefinition = &cobra.Command{
@@ -73,17 +81,40 @@ recently very efficiently like this:
 
     rclone copy --max-age 24h --no-traverse /path/to/src remote:
 
+If the source keeps old versions of objects (eg s3, b2, gcs and
+azureblob with versioning enabled or drive revisions) then use |--at|
+to copy the files as they were at a time, eg |--at 2d| for two days
+ago or |--at "2021-03-01 16:30:00"| (in UTC). This lists all the
+versions of the objects in the source, so may be slow on large
+remotes. The files copied get the modification times of their
+versions, which on some remotes is when the version was uploaded.
+
 **Note**: Use the |-P|/|--progress| flag to view real-time transfer statistics.
 
 **Note**: Use the |--dry-run| or the |--interactive|/|-i| flag to test without copying anything.
-`, "|", "`"),
+`, "|", "`") + operationsflags.ReportHelp,
 	Run: func(command *cobra.Command, args []string) {
 
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, srcFileName, fdst := cmd.NewFsSrcFileDst(args)
 		cmd.Run(true, true, command, func() error {
+			if at != "" {
+				ago, err := fs.ParseDuration(at)
+				if err != nil {
+					return errors.Wrapf(err, "bad --at %q", at)
+				}
+				fsrc, err = operations.VersionsAt(context.Background(), fsrc, time.Now().Add(-ago))
+				if err != nil {
+					return err
+				}
+			}
 			if srcFileName == "" {
-				return sync.CopyDir(context.Background(), fdst, fsrc, createEmptySrcDirs)
+				ctx, close, err := operationsflags.ConfigureReport(context.Background(), &reportOpt)
+				if err != nil {
+					return err
+				}
+				defer close()
+				return sync.CopyDir(ctx, fdst, fsrc, createEmptySrcDirs)
 			}
 			return operations.CopyFile(context.Background(), fdst, fsrc, srcFileName, srcFileName)
 		})
@@ -1,19 +1,33 @@
 package link
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/rclone/rclone/cmd"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/fshttp"
+	"github.com/rclone/rclone/fs/fspath"
 	"github.com/rclone/rclone/fs/operations"
 	"github.com/spf13/cobra"
 )
 
 var (
-	expire = fs.DurationOff
-	unlink = false
+	expire       = fs.DurationOff
+	unlink       = false
+	shareURL     = ""
+	shareUser    = ""
+	sharePass    = ""
+	password     = ""
+	maxDownloads = 0
 )
 
 func init() {
@@ -21,6 +35,11 @@ func init() {
 	cmdFlags := commandDefinition.Flags()
 	flags.FVarP(cmdFlags, &expire, "expire", "", "The amount of time that the link will be valid")
 	flags.BoolVarP(cmdFlags, &unlink, "unlink", "", unlink, "Remove existing public link to file/folder")
+	flags.StringVarP(cmdFlags, &shareURL, "share", "", shareURL, "Make a share link served by the rclone at this rc URL")
+	flags.StringVarP(cmdFlags, &shareUser, "share-user", "", shareUser, "Username to use to rclone remote control with --share")
+	flags.StringVarP(cmdFlags, &sharePass, "share-pass", "", sharePass, "Password to use to connect to rclone remote control with --share")
+	flags.StringVarP(cmdFlags, &password, "password", "", password, "Password needed to download a --share link")
+	flags.IntVarP(cmdFlags, &maxDownloads, "max-downloads", "", maxDownloads, "Number of times a --share link can be used - 0 for no limit")
 }
 
 var commandDefinition = &cobra.Command{
@@ -47,9 +66,36 @@ If successful, the last line of the output will contain the
 link. Exact capabilities depend on the remote, but the link will
 always by default be created with the least constraints – e.g. no
 expiry, no password protection, accessible without account.
+
+### Share links
+
+Use the --share flag to make a link served by a running "rclone serve
+http --shares" or "rclone rcd --rc-shares" rather than by the backend.
+This works with any backend. Give it the URL of the remote control of
+that rclone which is "http://localhost:5572/" by default, and use
+--share-user and --share-pass if it needs authentication.
+
+    rclone link --share http://localhost:5572/ --expire 1d remote:path/to/file
+
+For "serve http" give the path of the file within the remote it is
+serving, and for "rcd" give the remote and path as usual.
+
+Share links can only be made to files. Use --password to make the link
+need a password and --max-downloads to limit the number of times it
+can be used. Use "rclone rc share/list" and "rclone rc share/revoke" to
+see and revoke the links.
 `,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
+		if shareURL != "" {
+			cmd.Run(false, false, command, func() error {
+				return share(context.Background(), args[0])
+			})
+			return
+		}
+		if password != "" || maxDownloads != 0 {
+			fs.Logf(nil, "--password and --max-downloads are only used with --share")
+		}
 		fsrc, remote := cmd.NewFsFile(args[0])
 		cmd.Run(false, false, command, func() error {
 			link, err := operations.PublicLink(context.Background(), fsrc, remote, expire, unlink)
@@ -63,3 +109,76 @@ expiry, no password protection, accessible without account.
 		})
 	},
 }
+
+// share makes a share link to arg on the rclone at shareURL
+func share(ctx context.Context, arg string) (err error) {
+	if unlink {
+		return errors.New("can't use --unlink with --share - use rclone rc share/revoke")
+	}
+	in := map[string]interface{}{}
+	parsed, err := fspath.Parse(arg)
+	if err != nil {
+		return err
+	}
+	if parsed.Name == "" {
+		// a path within the remote being served
+		in["remote"] = arg
+	} else {
+		parent, leaf, err := fspath.Split(arg)
+		if err != nil {
+			return err
+		}
+		in["fs"] = parent
+		in["remote"] = leaf
+	}
+	if expire != fs.DurationOff {
+		in["expire"] = time.Duration(expire).String()
+	}
+	if password != "" {
+		in["password"] = password
+	}
+	if maxDownloads != 0 {
+		in["maxDownloads"] = maxDownloads
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode JSON")
+	}
+	url := shareURL
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url+"share/create", bytes.NewBuffer(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to make request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if shareUser != "" || sharePass != "" {
+		req.SetBasicAuth(shareUser, sharePass)
+	}
+	resp, err := fshttp.NewClient(ctx).Do(req)
+	if err != nil {
+		return errors.Wrap(err, "connection failed")
+	}
+	defer fs.CheckClose(resp.Body, &err)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+	var out struct {
+		URL   string `json:"url"`
+		Error string `json:"error"`
+	}
+	err = json.Unmarshal(body, &out)
+	if resp.StatusCode != http.StatusOK {
+		if err == nil && out.Error != "" {
+			return errors.Errorf("failed to make share link: %s", out.Error)
+		}
+		return errors.Errorf("failed to make share link: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to decode JSON")
+	}
+	fmt.Println(out.URL)
+	return nil
+}
@@ -6,12 +6,19 @@ import (
 
 	"github.com/rclone/rclone/cmd"
 	"github.com/rclone/rclone/cmd/ls/lshelp"
+	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/operations"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listVersions = false
+)
+
 func init() {
 	cmd.Root.AddCommand(commandDefinition)
+	cmdFlags := commandDefinition.Flags()
+	flags.BoolVarP(cmdFlags, &listVersions, "versions", "", listVersions, "List all the versions of each object")
 }
 
 var commandDefinition = &cobra.Command{
@@ -29,11 +36,24 @@ Eg
         94467 diwogej7
         37600 fubuwic
 
+If the remote keeps old versions of objects (eg s3, b2, gcs and
+azureblob with versioning enabled or drive revisions) then use
+--versions to list all the versions of each object. Each line has
+the size, the time the version was made, whether it is the current,
+an old or a deleted version, the ID of the version and the path.
+
+    $ rclone ls --versions s3:bucket
+           12 2021-03-02 16:30:00.000000000 current 3HL4kqtJlcpXroDTDmJ.rDnDi kabuto
+            8 2021-03-01 09:12:44.000000000 old     ydB0bN7lWyTBKWkXHJ3kl.jj2 kabuto
+
 ` + lshelp.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
 		fsrc := cmd.NewFsSrc(args)
 		cmd.Run(false, false, command, func() error {
+			if listVersions {
+				return operations.ListVersions(context.Background(), fsrc, os.Stdout)
+			}
 			return operations.List(context.Background(), fsrc, os.Stdout)
 		})
 	},
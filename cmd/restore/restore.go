@@ -0,0 +1,74 @@
+package restore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/sync"
+	"github.com/spf13/cobra"
+)
+
+var (
+	at = ""
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	cmdFlags := commandDefinition.Flags()
+	flags.StringVarP(cmdFlags, &at, "at", "", at, "Restore the files as they were at this time or this long ago")
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "restore --backup-dir backup:path --at time source:path dest:path",
+	Short: `Restore the files synced to source as they were at a time.`,
+	// Note: "|" will be replaced by backticks below
+	Long: strings.ReplaceAll(`
+Restore the files in source:path, which has been the destination of
+|rclone sync|, |copy| or |move| with |--backup-versions|, to
+dest:path as they were at the time given with |--at|, using the
+backups in |--backup-dir|.
+
+For example if you back up with
+
+    rclone sync --backup-dir remote:old --backup-versions /path/to/local remote:current
+
+then you can get the files as they were two days ago with
+
+    rclone restore --backup-dir remote:old --at 2d remote:current /path/to/restore
+
+|--at| can be a duration like |2d| for two days ago, or a time like
+|2021-03-01| or |2021-03-01 16:30:00| (in UTC).
+
+The files restored are those from the latest backup version at or
+before the time. If old versions have been removed with
+|--backup-keep-daily| or |--backup-keep-weekly| then this may be
+older than the time, and times before the oldest version kept restore
+the oldest version.
+
+Files are only copied to the destination, so files in dest:path which
+didn't exist at the time aren't deleted. Restore into an empty
+directory to get just the files as they were. Filters can be used to
+restore only some of the files.
+
+**Note**: Use the |--dry-run| or the |--interactive|/|-i| flag to test without copying anything.
+`, "|", "`"),
+	Run: func(command *cobra.Command, args []string) {
+		cmd.CheckArgs(2, 2, command, args)
+		fsrc, fdst := cmd.NewFsSrcDst(args)
+		cmd.Run(true, true, command, func() error {
+			if at == "" {
+				return errors.New("need --at to say when to restore the files from")
+			}
+			ago, err := fs.ParseDuration(at)
+			if err != nil {
+				return errors.Wrapf(err, "bad --at %q", at)
+			}
+			return sync.Restore(context.Background(), fdst, fsrc, time.Now().Add(-ago))
+		})
+	},
+}
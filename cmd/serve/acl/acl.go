@@ -0,0 +1,349 @@
+// Package acl implements access control lists for rclone serve
+package acl
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+)
+
+// Help contains text describing how to use access control lists
+var Help = strings.Replace(`
+### Access control lists
+
+If you supply the parameter |--acl /path/to/acl| then rclone will
+restrict what each authenticated user can do to the paths given to
+them in that file. Without it every user can do anything.
+
+Each line of the file is either a group definition
+
+    group NAME USER [USER...]
+
+or a rule
+
+    WHO PATH PERMISSIONS [QUOTA]
+
+where
+- |WHO| is a user name, |@group| for the members of a group or |*|
+  for everyone, including unauthenticated users
+- |PATH| is the directory (or file) the rule applies to, |/| for
+  everything
+- |PERMISSIONS| is any of |r| (read files), |w| (write files and make
+  directories), |d| (delete and rename) and |l| (list directories), or
+  |-| for none
+- |QUOTA| is an optional size, e.g. |10G|, which the files under
+  |PATH| can't go over - writes which would are refused
+
+Blank lines and lines starting with |#| are ignored. For example
+
+|||
+group staff alice bob
+alice   /home/alice  rwdl  10G
+@staff  /shared      rwdl
+*       /public      rl
+bob     /public/drop w
+|||
+
+The rules with the longest |PATH| which matches a file or directory
+and apply to the user are used and their permissions combined. Paths
+which no rule gives the user permissions on are hidden, except for
+the directories leading to the paths which do.
+
+Users are the ones given by |--user|, |--htpasswd| or the
+|--auth-proxy|. The auth proxy may also return a |_groups| parameter
+with a comma separated list of groups the user belongs to, which are
+added to those in the file.
+
+The file is checked for changes every 5 seconds and re-read when it
+changes, so rules can be changed while rclone is running. If it can't
+be read then the old rules carry on being used.
+
+The space used under each quota is counted the first time it is
+needed then kept up to date as files are written and removed through
+the server, so changes made to the remote by other means aren't
+noticed until the quota hasn't been used for an hour.
+`, "|", "`", -1)
+
+// Options is options for the access control lists
+type Options struct {
+	ACL string // path to the ACL file
+}
+
+// DefaultOpt is the default values uses for Opt
+var DefaultOpt = Options{
+	ACL: "",
+}
+
+// Perm is a set of permissions
+type Perm uint8
+
+// Permissions which may be granted
+const (
+	PermRead   Perm = 1 << iota // read files
+	PermWrite                   // write files and make directories
+	PermDelete                  // delete and rename files and directories
+	PermList                    // list directories
+	PermNone   Perm = 0
+)
+
+var permLetters = []struct {
+	perm   Perm
+	letter byte
+}{
+	{PermRead, 'r'},
+	{PermWrite, 'w'},
+	{PermDelete, 'd'},
+	{PermList, 'l'},
+}
+
+// String turns the permissions into a string like "rw-l"
+func (p Perm) String() string {
+	out := make([]byte, len(permLetters))
+	for i, pl := range permLetters {
+		if p&pl.perm != 0 {
+			out[i] = pl.letter
+		} else {
+			out[i] = '-'
+		}
+	}
+	return string(out)
+}
+
+// parsePerm parses permissions like "rwdl" or "-"
+func parsePerm(s string) (p Perm, err error) {
+outer:
+	for i := 0; i < len(s); i++ {
+		if s[i] == '-' {
+			continue
+		}
+		for _, pl := range permLetters {
+			if s[i] == pl.letter {
+				p |= pl.perm
+				continue outer
+			}
+		}
+		return p, errors.Errorf("unknown permission %q in %q", s[i], s)
+	}
+	return p, nil
+}
+
+// Rule grants permissions on a path to a user, group or everyone
+type Rule struct {
+	Who   string        // user name, "@group" or "*"
+	Path  string        // path the rule applies to, "" for the root
+	Perm  Perm          // permissions granted
+	Quota fs.SizeSuffix // maximum size of the files under Path or -1 for none
+}
+
+// cleanPath turns p into the form used for matching - no leading or
+// trailing "/" and "" for the root
+func cleanPath(p string) string {
+	p = path.Clean("/" + p)
+	return strings.Trim(p, "/")
+}
+
+// under returns true if remote is p or inside it
+func under(remote, p string) bool {
+	return p == "" || remote == p || strings.HasPrefix(remote, p+"/")
+}
+
+// rules are the parsed contents of an ACL file
+type rules struct {
+	groups map[string][]string // group name to users
+	rules  []Rule
+}
+
+// parse reads the rules from in
+func parse(in io.Reader) (r *rules, err error) {
+	r = &rules{groups: map[string][]string{}}
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if fields[0] == "group" {
+			if len(fields) < 3 {
+				return nil, errors.Errorf("line %d: need group NAME USER [USER...]", lineNumber)
+			}
+			r.groups[fields[1]] = append(r.groups[fields[1]], fields[2:]...)
+			continue
+		}
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, errors.Errorf("line %d: need WHO PATH PERMISSIONS [QUOTA]", lineNumber)
+		}
+		rule := Rule{
+			Who:   fields[0],
+			Path:  cleanPath(fields[1]),
+			Quota: -1,
+		}
+		rule.Perm, err = parsePerm(fields[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", lineNumber)
+		}
+		if len(fields) == 4 {
+			err = rule.Quota.Set(fields[3])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d: bad quota", lineNumber)
+			}
+		}
+		r.rules = append(r.rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// checkInterval is how often the file is checked for changes
+var checkInterval = 5 * time.Second
+
+// ACL is an access control list read from a file which is re-read
+// when it changes
+type ACL struct {
+	path    string
+	mu      sync.Mutex // held while reloading
+	modTime time.Time
+	size    int64
+	checked int64        // when the file was last checked in UnixNano - atomic
+	rules   atomic.Value // current *rules
+	quotas  quotas       // space used under the quotas
+}
+
+// New reads the access control list from the file in opt, returning
+// nil if there isn't one.
+func New(opt *Options) (*ACL, error) {
+	if opt.ACL == "" {
+		return nil, nil
+	}
+	a := &ACL{path: opt.ACL}
+	err := a.load()
+	if err != nil {
+		return nil, err
+	}
+	a.checked = time.Now().UnixNano()
+	fs.Infof(nil, "Using %q as access control list", a.path)
+	return a, nil
+}
+
+// load reads the file if it has changed since it was last read - call
+// with the lock held
+func (a *ACL) load() (err error) {
+	fi, err := os.Stat(a.path)
+	if err != nil {
+		return errors.Wrap(err, "acl")
+	}
+	loaded := a.rules.Load() != nil
+	if loaded && fi.ModTime().Equal(a.modTime) && fi.Size() == a.size {
+		return nil
+	}
+	// only try each version of the file once
+	a.modTime, a.size = fi.ModTime(), fi.Size()
+	in, err := os.Open(a.path)
+	if err != nil {
+		return errors.Wrap(err, "acl")
+	}
+	defer fs.CheckClose(in, &err)
+	r, err := parse(in)
+	if err != nil {
+		return errors.Wrapf(err, "acl: failed to read %q", a.path)
+	}
+	if loaded {
+		fs.Infof(nil, "Reloaded access control list %q", a.path)
+	}
+	a.rules.Store(r)
+	return nil
+}
+
+// current returns the rules, reloading them if the file changed.
+//
+// The file is only looked at once every checkInterval, by whichever
+// caller gets there first, so this is cheap to call for every access.
+func (a *ACL) current() *rules {
+	now := time.Now().UnixNano()
+	checked := atomic.LoadInt64(&a.checked)
+	if now-checked >= int64(checkInterval) && atomic.CompareAndSwapInt64(&a.checked, checked, now) {
+		a.mu.Lock()
+		err := a.load()
+		a.mu.Unlock()
+		if err != nil {
+			fs.Errorf(nil, "Failed to reload access control list - using old rules: %v", err)
+		}
+	}
+	return a.rules.Load().(*rules)
+}
+
+// applies returns true if rule applies to user in groups
+func (r *rules) applies(rule *Rule, user string, groups []string) bool {
+	switch {
+	case rule.Who == "*":
+		return true
+	case strings.HasPrefix(rule.Who, "@"):
+		group := rule.Who[1:]
+		for _, g := range groups {
+			if g == group {
+				return true
+			}
+		}
+		if user == "" {
+			return false
+		}
+		for _, member := range r.groups[group] {
+			if member == user {
+				return true
+			}
+		}
+		return false
+	}
+	return user != "" && rule.Who == user
+}
+
+// access returns the permissions user has on remote along with the
+// rules they came from
+func (r *rules) access(user string, groups []string, remote string) (perm Perm, matched []*Rule) {
+	best := -1
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !under(remote, rule.Path) || !r.applies(rule, user, groups) {
+			continue
+		}
+		switch {
+		case len(rule.Path) > best:
+			best = len(rule.Path)
+			perm, matched = rule.Perm, []*Rule{rule}
+		case len(rule.Path) == best:
+			perm |= rule.Perm
+			matched = append(matched, rule)
+		}
+	}
+	return perm, matched
+}
+
+// below returns the rules applying to user for paths inside remote
+func (r *rules) below(user string, groups []string, remote string) (out []*Rule) {
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.Path != remote && under(rule.Path, remote) && r.applies(rule, user, groups) {
+			out = append(out, rule)
+		}
+	}
+	return out
+}
+
+// Access returns the permissions user, who is a member of groups as
+// well as those in the file, has on remote.
+func (a *ACL) Access(user string, groups []string, remote string) Perm {
+	perm, _ := a.current().access(user, groups, cleanPath(remote))
+	return perm
+}
@@ -0,0 +1,133 @@
+package acl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testACL = `
+# test rules
+group staff alice bob
+
+alice   /home/alice  rwdl  10k
+@staff  /shared      rwdl
+*       /public      rl
+bob     /public/drop w
+alice   /shared/ro   rl
+`
+
+func TestPerm(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want Perm
+		str  string
+		err  bool
+	}{
+		{"-", PermNone, "----", false},
+		{"r", PermRead, "r---", false},
+		{"rwdl", PermRead | PermWrite | PermDelete | PermList, "rwdl", false},
+		{"lr", PermRead | PermList, "r--l", false},
+		{"rx", PermRead, "", true},
+	} {
+		got, err := parsePerm(test.in)
+		if test.err {
+			assert.Error(t, err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.str, got.String(), test.in)
+	}
+}
+
+func TestParse(t *testing.T) {
+	r, err := parse(strings.NewReader(testACL))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"staff": {"alice", "bob"}}, r.groups)
+	require.Equal(t, 5, len(r.rules))
+	assert.Equal(t, Rule{Who: "alice", Path: "home/alice", Perm: PermRead | PermWrite | PermDelete | PermList, Quota: 10 * fs.Kibi}, r.rules[0])
+	assert.Equal(t, Rule{Who: "*", Path: "public", Perm: PermRead | PermList, Quota: -1}, r.rules[2])
+
+	for _, bad := range []string{
+		"group staff",
+		"alice /home",
+		"alice /home rwdl 10k extra",
+		"alice /home rwx",
+		"alice /home rwdl potato",
+	} {
+		_, err := parse(strings.NewReader(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestAccess(t *testing.T) {
+	r, err := parse(strings.NewReader(testACL))
+	require.NoError(t, err)
+	for _, test := range []struct {
+		user   string
+		groups []string
+		remote string
+		want   Perm
+	}{
+		{"alice", nil, "home/alice/file.txt", PermRead | PermWrite | PermDelete | PermList},
+		{"alice", nil, "home/alicex", PermNone},
+		{"alice", nil, "home", PermNone},
+		{"bob", nil, "home/alice", PermNone},
+		{"bob", nil, "shared/file", PermRead | PermWrite | PermDelete | PermList},
+		{"carol", nil, "shared/file", PermNone},
+		{"carol", []string{"staff"}, "shared/file", PermRead | PermWrite | PermDelete | PermList},
+		{"alice", nil, "shared/ro/file", PermRead | PermList},
+		{"", nil, "public/file", PermRead | PermList},
+		{"", nil, "public/drop/file", PermRead | PermList},
+		{"bob", nil, "public/drop/file", PermWrite},
+	} {
+		got, _ := r.access(test.user, test.groups, test.remote)
+		assert.Equal(t, test.want, got, "%s %v %s", test.user, test.groups, test.remote)
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-acl-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	aclFile := filepath.Join(dir, "acl")
+
+	// No file is no ACL
+	a, err := New(&Options{})
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	_, err = New(&Options{ACL: aclFile})
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(aclFile, []byte("alice / r\n"), 0600))
+	a, err = New(&Options{ACL: aclFile})
+	require.NoError(t, err)
+	assert.Equal(t, PermRead, a.Access("alice", nil, "file"))
+
+	// Changed file isn't noticed until it is next checked
+	require.NoError(t, ioutil.WriteFile(aclFile, []byte("alice / rw\n"), 0600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(aclFile, future, future))
+	assert.Equal(t, PermRead, a.Access("alice", nil, "file"))
+
+	// Changed file is reloaded
+	oldCheckInterval := checkInterval
+	checkInterval = 0
+	defer func() { checkInterval = oldCheckInterval }()
+	assert.Equal(t, PermRead|PermWrite, a.Access("alice", nil, "file"))
+
+	// Broken file keeps the old rules
+	require.NoError(t, ioutil.WriteFile(aclFile, []byte("alice / potato\n"), 0600))
+	future = future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(aclFile, future, future))
+	assert.Equal(t, PermRead|PermWrite, a.Access("alice", nil, "file"))
+}
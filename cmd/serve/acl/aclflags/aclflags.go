@@ -0,0 +1,18 @@
+// Package aclflags implements command line flags to set up access control lists
+package aclflags
+
+import (
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/spf13/pflag"
+)
+
+// Options set by command line flags
+var (
+	Opt = acl.DefaultOpt
+)
+
+// AddFlags adds the access control list flags to the command
+func AddFlags(flagSet *pflag.FlagSet) {
+	flags.StringVarP(flagSet, &Opt.ACL, "acl", "", Opt.ACL, "File of access control rules giving users permissions on paths.")
+}
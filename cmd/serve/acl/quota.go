@@ -0,0 +1,352 @@
+package acl
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+// quotaExpiry is how long the usage under a quota which isn't looked
+// up is kept before it is forgotten and needs counting again
+var quotaExpiry = time.Hour
+
+// usageKey identifies the usage under a quota by the root of the
+// remote, so the VFSes the auth proxy makes for the same remote share
+// it
+type usageKey struct {
+	root string
+	path string
+}
+
+// usage is the space used under a quota, counted once then kept up
+// to date as files are written and removed
+type usage struct {
+	mu      sync.Mutex // held while counting or changing used
+	used    int64
+	counted bool      // set once used has been counted
+	touched time.Time // last time it was looked up - under quotas.mu
+}
+
+// quotas holds the usage under each quota
+type quotas struct {
+	mu    sync.Mutex
+	usage map[usageKey]*usage
+}
+
+// get returns the usage for key making it if needed
+func (q *quotas) get(key usageKey) *usage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.usage == nil {
+		q.usage = map[usageKey]*usage{}
+	}
+	now := time.Now()
+	u := q.usage[key]
+	if u == nil {
+		// Forget usage which hasn't been needed for a while, eg
+		// from VFSes which have gone
+		for k, old := range q.usage {
+			if now.Sub(old.touched) > quotaExpiry {
+				delete(q.usage, k)
+			}
+		}
+		u = &usage{}
+		q.usage[key] = u
+	}
+	u.touched = now
+	return u
+}
+
+// quotaUse is a quota which applies to a path and its usage
+type quotaUse struct {
+	rule  *Rule
+	usage *usage
+}
+
+// quotaUses returns the quotas which apply to remote
+func (v *VFS) quotaUses(r *rules, remote string) (out []quotaUse) {
+	if r == nil {
+		return nil
+	}
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.Quota < 0 || !under(remote, rule.Path) || !r.applies(rule, v.user, v.groups) {
+			continue
+		}
+		out = append(out, quotaUse{
+			rule:  rule,
+			usage: v.acl.quotas.get(usageKey{root: fs.ConfigString(v.vfs.Fs()), path: rule.Path}),
+		})
+	}
+	return out
+}
+
+// count returns the total size of the files under remote
+func (v *VFS) count(remote string) (total int64, err error) {
+	node, err := v.vfs.Stat(remote)
+	if err == vfs.ENOENT {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return node.Size(), nil
+	}
+	nodes, err := dir.ReadDirAll()
+	if err != nil {
+		return 0, err
+	}
+	for _, node := range nodes {
+		size, err := v.count(node.Path())
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// used returns the space used under q, counting it if needed - call
+// with q.usage.mu held
+func (v *VFS) used(q quotaUse) (int64, error) {
+	u := q.usage
+	if !u.counted {
+		used, err := v.count(q.rule.Path)
+		if err != nil {
+			return 0, err
+		}
+		u.used, u.counted = used, true
+	}
+	return u.used, nil
+}
+
+// reserve adds size bytes to the usage of qs, returning
+// ErrQuotaExceeded and changing nothing if that would go over any of
+// them. A size of 0 checks there is some space left.
+func (v *VFS) reserve(qs []quotaUse, remote string, size int64) error {
+	for i, q := range qs {
+		q.usage.mu.Lock()
+		used, err := v.used(q)
+		if err == nil {
+			quota := int64(q.rule.Quota)
+			if (size == 0 && used >= quota) || (size > 0 && used+size > quota) {
+				err = ErrQuotaExceeded
+			} else {
+				q.usage.used += size
+			}
+		}
+		q.usage.mu.Unlock()
+		if err != nil {
+			if err == ErrQuotaExceeded {
+				fs.Infof(remote, "Refused write for user %q as %q has used %v of its %v quota", v.user, "/"+q.rule.Path, fs.SizeSuffix(used), q.rule.Quota)
+			}
+			v.release(qs[:i], size)
+			return err
+		}
+	}
+	return nil
+}
+
+// release frees size bytes from the usage of qs. Usage which hasn't
+// been counted yet is left to be counted.
+func (v *VFS) release(qs []quotaUse, size int64) {
+	for _, q := range qs {
+		q.usage.mu.Lock()
+		if q.usage.counted {
+			q.usage.used -= size
+		}
+		q.usage.mu.Unlock()
+	}
+}
+
+// invalidate makes the usage of qs be counted again when next needed.
+//
+// Use this only when the change in size can't be known.
+func (v *VFS) invalidate(qs []quotaUse) {
+	for _, q := range qs {
+		q.usage.mu.Lock()
+		q.usage.counted = false
+		q.usage.mu.Unlock()
+	}
+}
+
+// CheckQuota returns ErrQuotaExceeded if writing size bytes to name
+// would go over a quota which applies to it.
+//
+// Use this to refuse uploads whose size is known before starting them.
+func (v *VFS) CheckQuota(name string, size int64) error {
+	remote := cleanPath(v.join(name))
+	qs := v.quotaUses(v.rules(), remote)
+	if err := v.reserve(qs, remote, size); err != nil {
+		return err
+	}
+	v.release(qs, size)
+	return nil
+}
+
+// only returns the quotas in qs which aren't in other
+func only(qs, other []quotaUse) (out []quotaUse) {
+outer:
+	for _, q := range qs {
+		for _, o := range other {
+			if q.usage == o.usage {
+				continue outer
+			}
+		}
+		out = append(out, q)
+	}
+	return out
+}
+
+// renameQuota renames oldName to newName in the underlying VFS,
+// moving the space used between the quotas which apply to only one
+// of them and refusing the rename if it would go over a quota.
+func (v *VFS) renameQuota(r *rules, oldName, newName string) error {
+	oldRemote, newRemote := cleanPath(oldName), cleanPath(newName)
+	oldQs, newQs := v.quotaUses(r, oldRemote), v.quotaUses(r, newRemote)
+	from, to := only(oldQs, newQs), only(newQs, oldQs)
+	var size, replaced int64
+	if len(from) > 0 || len(to) > 0 {
+		var err error
+		size, err = v.count(oldRemote)
+		if err != nil {
+			return err
+		}
+	}
+	if node, err := v.vfs.Stat(newName); err == nil && !node.IsDir() {
+		replaced = node.Size()
+	}
+	if err := v.reserve(to, newRemote, size); err != nil {
+		return err
+	}
+	if err := v.vfs.Rename(oldName, newName); err != nil {
+		v.release(to, size)
+		return err
+	}
+	v.release(from, size)
+	v.release(newQs, replaced)
+	return nil
+}
+
+// quotaHandle is a handle open for writing which counts the bytes
+// written against the quotas which apply to it, refusing writes which
+// would go over them.
+//
+// The space reserved is corrected to the size of the file when it is
+// closed.
+type quotaHandle struct {
+	vfs.Handle
+	v        *VFS
+	remote   string
+	qs       []quotaUse
+	mu       sync.Mutex
+	reserved int64 // bytes reserved for the file
+	closed   bool
+}
+
+// newQuotaHandle wraps fd which was opened on remote with flags
+func (v *VFS) newQuotaHandle(fd vfs.Handle, qs []quotaUse, remote string, flags int, oldSize int64) *quotaHandle {
+	h := &quotaHandle{
+		Handle:   fd,
+		v:        v,
+		remote:   remote,
+		qs:       qs,
+		reserved: oldSize,
+	}
+	if flags&os.O_TRUNC != 0 && oldSize > 0 {
+		v.release(qs, oldSize)
+		h.reserved = 0
+	}
+	return h
+}
+
+// grow reserves n more bytes for the file
+func (h *quotaHandle) grow(n int64) error {
+	if n <= 0 {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.v.reserve(h.qs, h.remote, n); err != nil {
+		return err
+	}
+	h.reserved += n
+	return nil
+}
+
+// Write writes p to the file if there is space
+func (h *quotaHandle) Write(p []byte) (n int, err error) {
+	if err = h.grow(int64(len(p))); err != nil {
+		return 0, err
+	}
+	return h.Handle.Write(p)
+}
+
+// WriteAt writes p to the file at off if there is space
+func (h *quotaHandle) WriteAt(p []byte, off int64) (n int, err error) {
+	if err = h.grow(int64(len(p))); err != nil {
+		return 0, err
+	}
+	return h.Handle.WriteAt(p, off)
+}
+
+// WriteString writes s to the file if there is space
+func (h *quotaHandle) WriteString(s string) (n int, err error) {
+	if err = h.grow(int64(len(s))); err != nil {
+		return 0, err
+	}
+	return h.Handle.WriteString(s)
+}
+
+// Truncate changes the size of the file if there is space
+func (h *quotaHandle) Truncate(size int64) error {
+	h.mu.Lock()
+	grow := size - h.reserved
+	h.mu.Unlock()
+	if err := h.grow(grow); err != nil {
+		return err
+	}
+	return h.Handle.Truncate(size)
+}
+
+// settle corrects the space reserved to the size of the file
+func (h *quotaHandle) settle() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	node, err := h.v.vfs.Stat(h.remote)
+	if err != nil {
+		h.v.invalidate(h.qs)
+		return
+	}
+	h.v.release(h.qs, h.reserved-node.Size())
+	h.reserved = node.Size()
+}
+
+// size returns the space the file is using under the quotas
+func (h *quotaHandle) size() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.reserved
+}
+
+// Close closes the file, correcting the space used
+func (h *quotaHandle) Close() error {
+	err := h.Handle.Close()
+	h.settle()
+	return err
+}
+
+// Release releases the file, correcting the space used
+func (h *quotaHandle) Release() error {
+	err := h.Handle.Release()
+	h.settle()
+	return err
+}
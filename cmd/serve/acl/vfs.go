@@ -0,0 +1,444 @@
+package acl
+
+import (
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/random"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
+)
+
+// ErrQuotaExceeded is returned when writing to a path whose quota has
+// been used up
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// ErrNotDirectory is returned when listing something which isn't a
+// directory
+var ErrNotDirectory = errors.New("not a directory")
+
+// VFS is a view of a *vfs.VFS for a single user which only allows
+// what the access control list gives them permission to do.
+//
+// It has the methods of *vfs.VFS which the serve commands use, taking
+// paths rather than nodes so that every access is checked.
+type VFS struct {
+	Opt    *vfscommon.Options // options of the underlying VFS
+	vfs    *vfs.VFS
+	acl    *ACL
+	user   string
+	groups []string
+	root   string // directory the user is confined to, "" for none
+}
+
+// NewVFS returns a view of f for user who is a member of groups as
+// well as those in the file.
+//
+// If acl is nil then the user may do anything.
+func NewVFS(f *vfs.VFS, acl *ACL, user string, groups []string) *VFS {
+	return &VFS{
+		Opt:    &f.Opt,
+		vfs:    f,
+		acl:    acl,
+		user:   user,
+		groups: groups,
+	}
+}
+
+// WithRoot returns a copy of the view where all names are inside the
+// directory root, so the user can't see anything outside it.
+//
+// The access control list still applies to the full paths.
+func (v *VFS) WithRoot(root string) *VFS {
+	newV := *v
+	newV.root = cleanPath(path.Join(v.root, cleanPath(root)))
+	return &newV
+}
+
+// join returns the path of name in the underlying VFS
+func (v *VFS) join(name string) string {
+	if v.root == "" {
+		return name
+	}
+	return path.Join(v.root, cleanPath(name))
+}
+
+// Fs returns the Fs passed into the New call
+func (v *VFS) Fs() fs.Fs {
+	return v.vfs.Fs()
+}
+
+// User returns the name of the user this view is for
+func (v *VFS) User() string {
+	return v.user
+}
+
+// Root returns the directory the user is confined to, "" for none
+func (v *VFS) Root() string {
+	return v.root
+}
+
+// rules returns the current rules or nil if there is no ACL
+func (v *VFS) rules() *rules {
+	if v.acl == nil {
+		return nil
+	}
+	return v.acl.current()
+}
+
+// visible returns true if the user may know remote exists - they have
+// permissions on it or on something inside it.
+func (v *VFS) visible(r *rules, remote string) bool {
+	if perm, _ := r.access(v.user, v.groups, remote); perm != PermNone {
+		return true
+	}
+	for _, rule := range r.below(v.user, v.groups, remote) {
+		if rule.Perm != PermNone {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns an error unless the user has all of need on remote
+func (v *VFS) check(r *rules, remote string, need Perm) error {
+	if r == nil {
+		return nil
+	}
+	perm, _ := r.access(v.user, v.groups, remote)
+	if perm&need == need {
+		return nil
+	}
+	if !v.visible(r, remote) {
+		return vfs.ENOENT
+	}
+	fs.Infof(remote, "Denied %s to user %q with permissions %s", need, v.user, perm)
+	return vfs.EPERM
+}
+
+// checkTree returns an error unless the user has all of need on
+// remote and everything inside it
+func (v *VFS) checkTree(r *rules, remote string, need Perm) error {
+	err := v.check(r, remote, need)
+	if err != nil || r == nil {
+		return err
+	}
+	for _, rule := range r.below(v.user, v.groups, remote) {
+		if rule.Perm&need != need {
+			fs.Infof(remote, "Denied %s to user %q as not allowed on %q", need, v.user, rule.Path)
+			return vfs.EPERM
+		}
+	}
+	return nil
+}
+
+// checkList returns an error unless the user may list dir.
+//
+// Directories leading to the paths the user has permissions on may
+// be listed without the list permission but only show those paths.
+func (v *VFS) checkList(r *rules, dir string) error {
+	err := v.check(r, dir, PermList)
+	if err != nil && r != nil && v.visible(r, dir) {
+		return nil
+	}
+	return err
+}
+
+// filter removes the entries the user may not see from nodes
+func (v *VFS) filter(r *rules, nodes vfs.Nodes) vfs.Nodes {
+	if r == nil {
+		return nodes
+	}
+	out := nodes[:0:0]
+	for _, node := range nodes {
+		if v.visible(r, node.Path()) {
+			out = append(out, node)
+		}
+	}
+	return out
+}
+
+// Check returns an error unless the user has all of need on name
+func (v *VFS) Check(name string, need Perm) error {
+	return v.check(v.rules(), cleanPath(v.join(name)), need)
+}
+
+// stat finds the Node by its path in the underlying VFS
+func (v *VFS) stat(r *rules, name string) (node vfs.Node, err error) {
+	if r != nil && !v.visible(r, cleanPath(name)) {
+		return nil, vfs.ENOENT
+	}
+	return v.vfs.Stat(name)
+}
+
+// Stat finds the Node by path starting from the root
+func (v *VFS) Stat(name string) (node vfs.Node, err error) {
+	return v.stat(v.rules(), v.join(name))
+}
+
+// ReadDirAll reads the contents of the directory name
+func (v *VFS) ReadDirAll(name string) (nodes vfs.Nodes, err error) {
+	name = v.join(name)
+	r := v.rules()
+	if err = v.checkList(r, cleanPath(name)); err != nil {
+		return nil, err
+	}
+	node, err := v.vfs.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := node.(*vfs.Dir)
+	if !ok {
+		return nil, ErrNotDirectory
+	}
+	nodes, err = dir.ReadDirAll()
+	if err != nil {
+		return nil, err
+	}
+	return v.filter(r, nodes), nil
+}
+
+// ReadDir reads the directory named by dirname and returns a list of
+// directory entries sorted by filename.
+func (v *VFS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	nodes, err := v.ReadDirAll(dirname)
+	if err != nil {
+		return nil, err
+	}
+	fis := make([]os.FileInfo, len(nodes))
+	for i, node := range nodes {
+		fis[i] = node
+	}
+	return fis, nil
+}
+
+// OpenFile a file according to the flags and perm provided
+func (v *VFS) OpenFile(name string, flags int, perm os.FileMode) (fd vfs.Handle, err error) {
+	name = v.join(name)
+	r := v.rules()
+	remote := cleanPath(name)
+	if flags&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+		if err = v.check(r, remote, PermWrite); err != nil {
+			return nil, err
+		}
+		qs := v.quotaUses(r, remote)
+		if len(qs) == 0 {
+			return v.vfs.OpenFile(name, flags, perm)
+		}
+		var oldSize int64
+		if node, err := v.vfs.Stat(name); err == nil && !node.IsDir() {
+			oldSize = node.Size()
+		}
+		// Truncating a file frees space so is always allowed
+		if flags&os.O_TRUNC == 0 || oldSize == 0 {
+			if err = v.reserve(qs, remote, 0); err != nil {
+				return nil, err
+			}
+		}
+		fd, err = v.vfs.OpenFile(name, flags, perm)
+		if err != nil {
+			return nil, err
+		}
+		return v.newQuotaHandle(fd, qs, remote, flags, oldSize), nil
+	}
+	if r == nil {
+		return v.vfs.OpenFile(name, flags, perm)
+	}
+	node, err := v.stat(r, name)
+	if err != nil {
+		return nil, err
+	}
+	if node.IsDir() {
+		if err = v.checkList(r, remote); err != nil {
+			return nil, err
+		}
+		fd, err = v.vfs.OpenFile(name, flags, perm)
+		if err != nil {
+			return nil, err
+		}
+		return &dirHandle{Handle: fd, v: v, r: r}, nil
+	}
+	if err = v.check(r, remote, PermRead); err != nil {
+		return nil, err
+	}
+	return v.vfs.OpenFile(name, flags, perm)
+}
+
+// Open opens the named file for reading
+func (v *VFS) Open(name string) (vfs.Handle, error) {
+	return v.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// Create creates the named file with mode 0666 (before umask), truncating
+// it if it already exists.
+func (v *VFS) Create(name string) (vfs.Handle, error) {
+	return v.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// WriteFile writes in to the file name, replacing it if it exists.
+//
+// The data is written to a temporary file next to name which is
+// renamed over it once complete, so if writing fails the old file is
+// left as it was. It needs write permission on name only.
+func (v *VFS) WriteFile(name string, in io.Reader) (err error) {
+	remote := cleanPath(v.join(name))
+	r := v.rules()
+	if err = v.check(r, remote, PermWrite); err != nil {
+		return err
+	}
+	qs := v.quotaUses(r, remote)
+	if err = v.reserve(qs, remote, 0); err != nil {
+		return err
+	}
+	dir, leaf := path.Split(remote)
+	tmp := path.Join(dir, "."+leaf+"."+random.String(8)+".upload")
+	fd, err := v.vfs.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	h := v.newQuotaHandle(fd, qs, tmp, 0, 0)
+	_, err = io.Copy(h, in)
+	closeErr := h.Close()
+	if err == nil {
+		err = closeErr
+	}
+	var replaced int64
+	if err == nil {
+		if node, statErr := v.vfs.Stat(remote); statErr == nil && !node.IsDir() {
+			replaced = node.Size()
+		}
+		err = v.vfs.Rename(tmp, remote)
+	}
+	if err != nil {
+		if removeErr := v.vfs.Remove(tmp); removeErr != nil && removeErr != vfs.ENOENT {
+			fs.Errorf(tmp, "Failed to remove partial upload: %v", removeErr)
+			v.invalidate(qs)
+		} else {
+			v.release(qs, h.size())
+		}
+		return err
+	}
+	v.release(qs, replaced)
+	return nil
+}
+
+// Mkdir creates a new directory with the specified name and permission bits
+// (before umask).
+func (v *VFS) Mkdir(name string, perm os.FileMode) error {
+	name = v.join(name)
+	if err := v.check(v.rules(), cleanPath(name), PermWrite); err != nil {
+		return err
+	}
+	return v.vfs.Mkdir(name, perm)
+}
+
+// Remove removes the named file or (empty) directory.
+func (v *VFS) Remove(name string) error {
+	name = v.join(name)
+	r := v.rules()
+	remote := cleanPath(name)
+	if err := v.check(r, remote, PermDelete); err != nil {
+		return err
+	}
+	node, err := v.vfs.Stat(name)
+	if err != nil {
+		return err
+	}
+	err = node.Remove()
+	if err == nil && !node.IsDir() {
+		v.release(v.quotaUses(r, remote), node.Size())
+	}
+	return err
+}
+
+// RemoveAll removes the named file or directory and everything in it
+func (v *VFS) RemoveAll(name string) error {
+	name = v.join(name)
+	r := v.rules()
+	remote := cleanPath(name)
+	if err := v.checkTree(r, remote, PermDelete); err != nil {
+		return err
+	}
+	node, err := v.vfs.Stat(name)
+	if err != nil {
+		return err
+	}
+	qs := v.quotaUses(r, remote)
+	var size int64
+	if len(qs) > 0 {
+		size, err = v.count(name)
+		if err != nil {
+			return err
+		}
+	}
+	err = node.RemoveAll()
+	if err != nil {
+		// Some of it may have gone so count it again
+		v.invalidate(qs)
+		return err
+	}
+	v.release(qs, size)
+	return nil
+}
+
+// Rename oldName to newName
+func (v *VFS) Rename(oldName, newName string) error {
+	oldName, newName = v.join(oldName), v.join(newName)
+	r := v.rules()
+	if err := v.checkTree(r, cleanPath(oldName), PermDelete); err != nil {
+		return err
+	}
+	if err := v.check(r, cleanPath(newName), PermWrite); err != nil {
+		return err
+	}
+	return v.renameQuota(r, oldName, newName)
+}
+
+// Chtimes changes the access and modification times of the named file, similar
+// to the Unix utime() or utimes() functions.
+func (v *VFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	name = v.join(name)
+	if err := v.check(v.rules(), cleanPath(name), PermWrite); err != nil {
+		return err
+	}
+	return v.vfs.Chtimes(name, atime, mtime)
+}
+
+// Statfs returns into about the filing system if known
+func (v *VFS) Statfs() (total, used, free int64) {
+	return v.vfs.Statfs()
+}
+
+// dirHandle is a handle on a directory which only lists the entries
+// the user may see
+type dirHandle struct {
+	vfs.Handle
+	v *VFS
+	r *rules
+}
+
+// Readdir reads the contents of the directory
+func (h *dirHandle) Readdir(n int) (fis []os.FileInfo, err error) {
+	fis, err = h.Handle.Readdir(n)
+	out := fis[:0]
+	for _, fi := range fis {
+		node, ok := fi.(vfs.Node)
+		if ok && h.v.visible(h.r, node.Path()) {
+			out = append(out, fi)
+		}
+	}
+	return out, err
+}
+
+// Readdirnames reads the names of the entries in the directory
+func (h *dirHandle) Readdirnames(n int) (names []string, err error) {
+	fis, err := h.Readdir(n)
+	for _, fi := range fis {
+		names = append(names, path.Base(fi.Name()))
+	}
+	return names, err
+}
@@ -0,0 +1,209 @@
+package acl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestVFS makes a VFS on a temporary directory with some files in
+// and an ACL from rules
+func newTestVFS(t *testing.T, rules string) (*vfs.VFS, *ACL, func()) {
+	dir, err := ioutil.TempDir("", "rclone-acl-test")
+	require.NoError(t, err)
+	for _, name := range []string{"home/alice/a.txt", "home/bob/b.txt", "public/p.txt", "shared/ro/r.txt"} {
+		p := filepath.Join(dir, "root", filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0777))
+		require.NoError(t, ioutil.WriteFile(p, []byte(strings.Repeat("x", 100)), 0600))
+	}
+	aclFile := filepath.Join(dir, "acl")
+	require.NoError(t, ioutil.WriteFile(aclFile, []byte(rules), 0600))
+	a, err := New(&Options{ACL: aclFile})
+	require.NoError(t, err)
+	f, err := fs.NewFs(context.Background(), filepath.Join(dir, "root"))
+	require.NoError(t, err)
+	VFS := vfs.New(f, nil)
+	return VFS, a, func() {
+		VFS.Shutdown()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func names(t *testing.T, v *VFS, dir string) (out []string) {
+	nodes, err := v.ReadDirAll(dir)
+	require.NoError(t, err)
+	for _, node := range nodes {
+		out = append(out, node.Name())
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestVFS(t *testing.T) {
+	VFS, a, cleanup := newTestVFS(t, testACL)
+	defer cleanup()
+	alice := NewVFS(VFS, a, "alice", nil)
+	anon := NewVFS(VFS, a, "", nil)
+
+	// Listing shows only the paths the user may see
+	assert.Equal(t, []string{"home", "public", "shared"}, names(t, alice, ""))
+	assert.Equal(t, []string{"alice"}, names(t, alice, "home"))
+	assert.Equal(t, []string{"public"}, names(t, anon, "/"))
+	assert.Equal(t, []string{"p.txt"}, names(t, anon, "public"))
+
+	// Hidden paths don't exist
+	_, err := alice.Stat("home/bob/b.txt")
+	assert.Equal(t, vfs.ENOENT, err)
+	_, err = alice.ReadDirAll("home/bob")
+	assert.Equal(t, vfs.ENOENT, err)
+	_, err = alice.Stat("home/alice/a.txt")
+	assert.NoError(t, err)
+
+	// Reading
+	_, err = alice.Open("home/bob/b.txt")
+	assert.Equal(t, vfs.ENOENT, err)
+	fd, err := anon.Open("public/p.txt")
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	// Directory handles are filtered too
+	fd, err = alice.Open("home")
+	require.NoError(t, err)
+	fis, err := fd.Readdir(-1)
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	assert.Equal(t, 1, len(fis))
+
+	// Writing
+	_, err = anon.Create("public/new.txt")
+	assert.Equal(t, vfs.EPERM, err)
+	assert.Equal(t, vfs.EPERM, alice.Mkdir("shared/ro/dir", 0777))
+	require.NoError(t, alice.Mkdir("home/alice/dir", 0777))
+	fd, err = alice.Create("home/alice/new.txt")
+	require.NoError(t, err)
+	_, err = fd.Write([]byte(strings.Repeat("y", 10*1024-100)))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	// Quota is now used up
+	_, err = alice.Create("home/alice/another.txt")
+	assert.Equal(t, ErrQuotaExceeded, err)
+
+	// Deleting and renaming
+	assert.Equal(t, vfs.EPERM, alice.Remove("shared/ro/r.txt"))
+	assert.Equal(t, vfs.EPERM, alice.Rename("shared/ro/r.txt", "home/alice/r.txt"))
+	assert.Equal(t, vfs.ENOENT, alice.Remove("home/bob/b.txt"))
+	require.NoError(t, alice.Remove("home/alice/new.txt"))
+	require.NoError(t, alice.Rename("home/alice/a.txt", "home/alice/dir/a.txt"))
+	require.NoError(t, alice.RemoveAll("home/alice/dir"))
+
+	// Removing a tree with something undeletable in is refused
+	assert.Equal(t, vfs.EPERM, alice.RemoveAll("shared"))
+
+	// No ACL allows everything
+	all := NewVFS(VFS, nil, "", nil)
+	assert.Equal(t, []string{"b.txt"}, names(t, all, "home/bob"))
+	require.NoError(t, all.RemoveAll("home/bob"))
+}
+
+func TestVFSQuota(t *testing.T) {
+	VFS, a, cleanup := newTestVFS(t, testACL)
+	defer cleanup()
+	alice := NewVFS(VFS, a, "alice", nil)
+
+	// Sizes known in advance are checked against the 10k quota
+	// with a.txt using 100 bytes
+	assert.NoError(t, alice.CheckQuota("home/alice/new.txt", 10*1024-100))
+	assert.Equal(t, ErrQuotaExceeded, alice.CheckQuota("home/alice/new.txt", 10*1024))
+	assert.NoError(t, alice.CheckQuota("shared/new.txt", 1<<40))
+
+	// Writes which would go over the quota are refused
+	fd, err := alice.Create("home/alice/new.txt")
+	require.NoError(t, err)
+	_, err = fd.Write([]byte(strings.Repeat("y", 10*1024-200)))
+	require.NoError(t, err)
+	_, err = fd.Write([]byte(strings.Repeat("y", 200)))
+	assert.Equal(t, ErrQuotaExceeded, err)
+	_, err = fd.Write([]byte(strings.Repeat("y", 100)))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	_, err = alice.Create("home/alice/another.txt")
+	assert.Equal(t, ErrQuotaExceeded, err)
+
+	// Overwriting a file frees its old size
+	fd, err = alice.Create("home/alice/new.txt")
+	require.NoError(t, err)
+	_, err = fd.Write([]byte("z"))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	assert.NoError(t, alice.CheckQuota("home/alice/another.txt", 10*1024-101))
+
+	// Moving a file out of the quota frees its space, and moving it
+	// in needs space
+	require.NoError(t, alice.Rename("home/alice/a.txt", "shared/a.txt"))
+	assert.NoError(t, alice.CheckQuota("home/alice/another.txt", 10*1024-1))
+	fd, err = alice.Create("home/alice/big.txt")
+	require.NoError(t, err)
+	_, err = fd.Write([]byte(strings.Repeat("y", 10*1024-50)))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	assert.Equal(t, ErrQuotaExceeded, alice.Rename("shared/a.txt", "home/alice/a.txt"))
+
+	// Removing frees space
+	require.NoError(t, alice.Remove("home/alice/big.txt"))
+	assert.NoError(t, alice.CheckQuota("home/alice/another.txt", 10*1024-1))
+	require.NoError(t, alice.Rename("shared/a.txt", "home/alice/a.txt"))
+	assert.Equal(t, ErrQuotaExceeded, alice.CheckQuota("home/alice/another.txt", 10*1024-100))
+
+	// Usage is shared by the views of the same remote and kept up
+	// to date without counting it again
+	VFS2 := vfs.New(VFS.Fs(), nil)
+	defer VFS2.Shutdown()
+	alice2 := NewVFS(VFS2, a, "alice", nil)
+	require.NoError(t, alice2.Mkdir("home/alice/dir", 0777))
+	fd, err = alice2.Create("home/alice/dir/c.txt")
+	require.NoError(t, err)
+	_, err = fd.Write([]byte(strings.Repeat("c", 1000)))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+	assert.NoError(t, alice.CheckQuota("home/alice/another.txt", 10*1024-1101))
+	assert.Equal(t, ErrQuotaExceeded, alice.CheckQuota("home/alice/another.txt", 10*1024-1100))
+
+	// Removing a directory frees the space used in it
+	require.NoError(t, alice2.RemoveAll("home/alice/dir"))
+	assert.NoError(t, alice.CheckQuota("home/alice/another.txt", 10*1024-101))
+	assert.Equal(t, ErrQuotaExceeded, alice.CheckQuota("home/alice/another.txt", 10*1024-100))
+}
+
+func TestVFSWithRoot(t *testing.T) {
+	VFS, a, cleanup := newTestVFS(t, testACL)
+	defer cleanup()
+	alice := NewVFS(VFS, a, "alice", nil).WithRoot("/home/alice")
+
+	assert.Equal(t, []string{"a.txt"}, names(t, alice, ""))
+	assert.Equal(t, []string{"a.txt"}, names(t, alice, "/../.."))
+	node, err := alice.Stat("a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "home/alice/a.txt", node.Path())
+	_, err = alice.Stat("../bob/b.txt")
+	assert.Equal(t, vfs.ENOENT, err)
+
+	// the ACL still applies to the full path
+	bob := NewVFS(VFS, a, "bob", nil).WithRoot("home/alice")
+	_, err = bob.Stat("a.txt")
+	assert.Equal(t, vfs.ENOENT, err)
+
+	// without an ACL the root still confines the user
+	anyone := NewVFS(VFS, nil, "", nil).WithRoot("shared")
+	assert.Equal(t, []string{"ro"}, names(t, anyone, "/"))
+}
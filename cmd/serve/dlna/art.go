@@ -0,0 +1,271 @@
+package dlna
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/dlna/probe"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/vfs"
+)
+
+const (
+	// largest side of a thumbnail in pixels
+	thumbnailSize = 160
+	// largest image file to make a thumbnail of
+	maxThumbnailSource = 32 * 1024 * 1024
+	// largest number of pixels in an image to make a thumbnail of, as
+	// small files can decode to huge images
+	maxThumbnailPixels = 24 * 1000 * 1000
+)
+
+// names of files used as the cover art of the media in a folder, in
+// order of preference
+var folderArtNames = []string{
+	"folder.jpg", "cover.jpg", "albumart.jpg", "front.jpg",
+	"folder.png", "cover.png", "front.png",
+}
+
+// folderArt returns the path of the cover art for the folder dir or
+// "" if there isn't any.
+func (s *server) folderArt(dir string) string {
+	node, err := s.vfs.Stat(dir)
+	if err != nil || !node.IsDir() {
+		return ""
+	}
+	nodes, err := node.(*vfs.Dir).ReadDirAll()
+	if err != nil {
+		return ""
+	}
+	art, best := "", len(folderArtNames)
+	for _, node := range nodes {
+		name := strings.ToLower(node.Name())
+		for i, artName := range folderArtNames[:best] {
+			if name == artName {
+				art, best = path.Join(dir, node.Name()), i
+				break
+			}
+		}
+	}
+	return art
+}
+
+// Serves the cover art embedded in media files.
+func (s *server) coverArtHandler(w http.ResponseWriter, r *http.Request) {
+	remote := r.URL.Path
+	node, err := s.vfs.Stat(remote)
+	if err != nil || node.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	info := s.metadata.info(remote)
+	if info == nil || info.Cover == nil || info.Cover.Offset+info.Cover.Length > node.Size() {
+		http.NotFound(w, r)
+		return
+	}
+	in, err := node.(*vfs.File).Open(os.O_RDONLY)
+	if err != nil {
+		serveError(node, w, "Could not open resource", err)
+		return
+	}
+	defer fs.CheckClose(in, &err)
+	w.Header().Set("Content-Type", info.Cover.MimeType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeContent(w, r, "", node.ModTime(), io.NewSectionReader(in, info.Cover.Offset, info.Cover.Length))
+}
+
+// Serves thumbnails of images.
+func (s *server) thumbnailHandler(w http.ResponseWriter, r *http.Request) {
+	remote := r.URL.Path
+	node, err := s.vfs.Stat(remote)
+	if err != nil || node.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	thumbnail, err := s.thumbnail(node.(*vfs.File))
+	if err != nil {
+		fs.Debugf(node, "Failed to make thumbnail: %v", err)
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("contentFeatures.dlna.org", "DLNA.ORG_PN=JPEG_TN")
+	http.ServeContent(w, r, "", node.ModTime(), bytes.NewReader(thumbnail))
+}
+
+// thumbnail returns a JPEG thumbnail of the image file, reading it
+// from the cache directory if it was made before.
+func (s *server) thumbnail(file *vfs.File) (thumbnail []byte, err error) {
+	hash := md5.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%d", fs.ConfigString(s.f), file.Path(), file.Size(), file.ModTime().UnixNano())))
+	cachePath := filepath.Join(config.GetCacheDir(), "serve-dlna", "thumbnails", hex.EncodeToString(hash[:])+".jpg")
+	thumbnail, err = ioutil.ReadFile(cachePath)
+	if err == nil {
+		return thumbnail, nil
+	}
+	if file.Size() > maxThumbnailSource {
+		return nil, errors.Errorf("image too big (%d bytes)", file.Size())
+	}
+	in, err := file.Open(os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(in, maxThumbnailSource))
+	_ = in.Close()
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxThumbnailPixels {
+		return nil, errors.Errorf("image too big (%dx%d pixels)", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, scaleImage(img, thumbnailSize), &jpeg.Options{Quality: 80})
+	if err != nil {
+		return nil, err
+	}
+	thumbnail = buf.Bytes()
+	err = os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err == nil {
+		err = ioutil.WriteFile(cachePath, thumbnail, 0600)
+	}
+	if err != nil {
+		fs.Debugf(nil, "Failed to cache thumbnail: %v", err)
+	}
+	return thumbnail, nil
+}
+
+// scaleImage shrinks img so its largest side is at most size pixels
+// by averaging the pixels of each box it covers.
+func scaleImage(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	tw, th := size, h*size/w
+	if h > w {
+		tw, th = w*size/h, size
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+	out := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			i := out.PixOffset(x, y)
+			out.Pix[i+0] = uint8(r / n >> 8)
+			out.Pix[i+1] = uint8(g / n >> 8)
+			out.Pix[i+2] = uint8(bl / n >> 8)
+			out.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return out
+}
+
+// Returns the URL of the embedded subtitle track of the media file at
+// remote.
+func subtitleURL(host, remote string, track int) string {
+	return resourceURL(host, subtitlePath, path.Join(strconv.Itoa(track), remote))
+}
+
+// Serves the text subtitle tracks embedded in media files as SRT.
+//
+// The path is the track number followed by the path of the file.
+func (s *server) subtitleHandler(w http.ResponseWriter, r *http.Request) {
+	i := strings.IndexByte(r.URL.Path, '/')
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	track, err := strconv.Atoi(r.URL.Path[:i])
+	remote := r.URL.Path[i:]
+	node, statErr := s.vfs.Stat(remote)
+	if err != nil || statErr != nil || node.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	found := false
+	if info := s.metadata.info(remote); info != nil {
+		for _, subtitle := range info.Subtitles {
+			if subtitle.Track == track {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	subtitles, err := s.subtitles(node.(*vfs.File), track)
+	if err != nil {
+		serveError(node, w, "Could not read subtitles", err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/srt; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeContent(w, r, "", node.ModTime(), bytes.NewReader(subtitles))
+}
+
+// subtitles returns the embedded subtitle track of file as SRT,
+// reading it from the cache directory if it was read before.
+func (s *server) subtitles(file *vfs.File, track int) (subtitles []byte, err error) {
+	hash := md5.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%d\x00%d", fs.ConfigString(s.f), file.Path(), file.Size(), file.ModTime().UnixNano(), track)))
+	cachePath := filepath.Join(config.GetCacheDir(), "serve-dlna", "subtitles", hex.EncodeToString(hash[:])+".srt")
+	subtitles, err = ioutil.ReadFile(cachePath)
+	if err == nil {
+		return subtitles, nil
+	}
+	in, err := file.Open(os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	subtitles, err = probe.ReadSubtitles(in, file.Size(), track)
+	_ = in.Close()
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err == nil {
+		err = ioutil.WriteFile(cachePath, subtitles, 0600)
+	}
+	if err != nil {
+		fs.Debugf(nil, "Failed to cache subtitles: %v", err)
+	}
+	return subtitles, nil
+}
@@ -1,6 +1,7 @@
 package dlna
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
 	"fmt"
@@ -108,39 +109,91 @@ func (cds *contentDirectoryService) cdsObjectToUpnpavObject(cdsObject object, fi
 	obj.Title = fileInfo.Name()
 	obj.Date = upnpav.Timestamp{Time: fileInfo.ModTime()}
 
-	item := upnpav.Item{
-		Object: obj,
-		Res:    make([]upnpav.Resource, 0, 1),
-	}
-
-	item.Res = append(item.Res, upnpav.Resource{
-		URL: (&url.URL{
-			Scheme: "http",
-			Host:   host,
-			Path:   path.Join(resPath, cdsObject.Path),
-		}).String(),
+	res := upnpav.Resource{
+		URL: resourceURL(host, resPath, cdsObject.Path),
 		ProtocolInfo: fmt.Sprintf("http-get:*:%s:%s", mimeType, dlna.ContentFeatures{
 			SupportRange: true,
 		}.String()),
 		Size: uint64(fileInfo.Size()),
-	})
+	}
 
+	// Add the metadata read from the file if known
+	info := cds.metadata.get(cdsObject.Path, fileInfo).Info
+	if info != nil {
+		if info.Title != "" {
+			obj.Title = info.Title
+		}
+		obj.Artist, obj.Creator = info.Artist, info.Artist
+		obj.Album, obj.Genre = info.Album, info.Genre
+		if info.Duration > 0 {
+			res.Duration = formatDuration(info.Duration)
+		}
+		if info.Width > 0 && info.Height > 0 {
+			res.Resolution = fmt.Sprintf("%dx%d", info.Width, info.Height)
+		}
+		res.Bitrate = uint(info.Bitrate)
+		res.SampleRate, res.Channels = info.SampleRate, info.Channels
+		if info.Cover != nil {
+			obj.AlbumArtURI = resourceURL(host, coverArtPath, cdsObject.Path)
+		}
+	}
+	var thumbnail string
+	if mediaType[1] == "image" {
+		thumbnail = resourceURL(host, thumbnailPath, cdsObject.Path)
+		obj.AlbumArtURI = thumbnail
+	} else if obj.AlbumArtURI == "" {
+		if art := cds.folderArt(path.Dir(cdsObject.Path)); art != "" {
+			obj.AlbumArtURI = resourceURL(host, thumbnailPath, art)
+		}
+	}
+
+	item := upnpav.Item{
+		Object: obj,
+		Res:    make([]upnpav.Resource, 0, 1),
+	}
+	item.Res = append(item.Res, res)
+	if thumbnail != "" {
+		item.Res = append(item.Res, upnpav.Resource{
+			URL:          thumbnail,
+			ProtocolInfo: "http-get:*:image/jpeg:DLNA.ORG_PN=JPEG_TN",
+		})
+	}
+
+	var subtitles []string
 	for _, resource := range resources {
-		subtitleURL := (&url.URL{
-			Scheme: "http",
-			Host:   host,
-			Path:   path.Join(resPath, resource.Path()),
-		}).String()
+		subtitles = append(subtitles, resourceURL(host, resPath, resource.Path()))
+	}
+	if info != nil {
+		for _, subtitle := range info.Subtitles {
+			subtitles = append(subtitles, subtitleURL(host, cdsObject.Path, subtitle.Track))
+		}
+	}
+	for i, subtitleURL := range subtitles {
 		item.Res = append(item.Res, upnpav.Resource{
 			URL:          subtitleURL,
 			ProtocolInfo: fmt.Sprintf("http-get:*:%s:*", "text/srt"),
 		})
+		// Samsung devices find the subtitles from this
+		if i == 0 {
+			var buf bytes.Buffer
+			_ = xml.EscapeText(&buf, []byte(subtitleURL))
+			item.InnerXML = `<sec:CaptionInfoEx sec:type="srt">` + buf.String() + `</sec:CaptionInfoEx>`
+		}
 	}
 
 	ret = item
 	return
 }
 
+// Returns the URL of remote served under prefix.
+func resourceURL(host, prefix, remote string) string {
+	return (&url.URL{
+		Scheme: "http",
+		Host:   host,
+		Path:   path.Join(prefix, remote),
+	}).String()
+}
+
 // Returns all the upnpav objects in a directory.
 func (cds *contentDirectoryService) readContainer(o object, host string) (ret []interface{}, err error) {
 	node, err := cds.vfs.Stat(o.Path)
@@ -180,6 +233,25 @@ func (cds *contentDirectoryService) readContainer(o object, host string) (ret []
 	return
 }
 
+// Returns the resources associated with the media file at remote.
+func (s *server) mediaResources(remote string) vfs.Nodes {
+	node, err := s.vfs.Stat(path.Dir(remote))
+	if err != nil || !node.IsDir() {
+		return nil
+	}
+	nodes, err := node.(*vfs.Dir).ReadDirAll()
+	if err != nil {
+		return nil
+	}
+	media, resources := mediaWithResources(nodes)
+	for _, node := range media {
+		if node.Name() == path.Base(remote) {
+			return resources[node]
+		}
+	}
+	return nil
+}
+
 // Given a list of nodes, separate them into potential media items and any associated resources (external subtitles,
 // for example.)
 //
@@ -280,16 +352,8 @@ func (cds *contentDirectoryService) Handle(action string, argsXML []byte, r *htt
 				return nil, upnp.Errorf(upnpav.NoSuchObjectErrorCode, err.Error())
 			}
 			totalMatches := len(objs)
-			objs = objs[func() (low int) {
-				low = browse.StartingIndex
-				if low > len(objs) {
-					low = len(objs)
-				}
-				return
-			}():]
-			if browse.RequestedCount != 0 && browse.RequestedCount < len(objs) {
-				objs = objs[:browse.RequestedCount]
-			}
+			low, high := page(len(objs), browse.StartingIndex, browse.RequestedCount)
+			objs = objs[low:high]
 			result, err := xml.Marshal(objs)
 			if err != nil {
 				return nil, err
@@ -305,8 +369,7 @@ func (cds *contentDirectoryService) Handle(action string, argsXML []byte, r *htt
 			if err != nil {
 				return nil, err
 			}
-			// TODO: External subtitles won't appear in the metadata here, but probably should.
-			upnpObject, err := cds.cdsObjectToUpnpavObject(obj, node, vfs.Nodes{}, host)
+			upnpObject, err := cds.cdsObjectToUpnpavObject(obj, node, cds.mediaResources(obj.Path), host)
 			if err != nil {
 				return nil, err
 			}
@@ -322,7 +385,26 @@ func (cds *contentDirectoryService) Handle(action string, argsXML []byte, r *htt
 		}
 	case "GetSearchCapabilities":
 		return map[string]string{
-			"SearchCaps": "",
+			"SearchCaps": searchCapabilities,
+		}, nil
+	case "Search":
+		var search search
+		if err := xml.Unmarshal(argsXML, &search); err != nil {
+			return nil, err
+		}
+		objs, totalMatches, err := cds.search(search, host)
+		if err != nil {
+			return nil, err
+		}
+		result, err := xml.Marshal(objs)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{
+			"TotalMatches":   fmt.Sprint(totalMatches),
+			"NumberReturned": fmt.Sprint(len(objs)),
+			"Result":         didlLite(string(result)),
+			"UpdateID":       cds.updateIDString(),
 		}, nil
 	// Samsung Extensions
 	case "X_GetFeatureList":
@@ -43,6 +43,39 @@ Rclone will list all files present in the remote, without filtering based on med
 file extensions. Additionally, there is no media transcoding support. This means that some
 players might show files that they are not able to play back correctly.
 
+### Metadata
+
+Rclone reads the duration, resolution, bitrate, tags (title, artist,
+album and genre) and embedded cover art of MP4, MKV/WebM, MP3 and FLAC
+files, and the size of images, from the headers of the files. Only
+the parts of the file with the headers are read, using range requests.
+This is done in the background the first time a file is listed, so
+the metadata appears when the folder is next browsed. Use
+` + "`--no-metadata`" + ` to turn this off.
+
+The metadata is kept in the cache directory (see "rclone help flags
+cache-dir") in the "serve-dlna" directory so it is only read once.
+Thumbnails of images are kept there too.
+
+Embedded cover art is served as the album art of the file. If there
+isn't any then a "folder.jpg" or "cover.jpg" in the same directory
+is used.
+
+Subtitles in ".srt" files with the same name as a video (e.g.
+"video.srt" or "video.en.srt" for "video.mp4") are served along with
+it. Text subtitle tracks embedded in MKV files are served as SRT
+too. As the subtitles are spread through the file the whole file is
+read the first time they are requested and the result is kept in the
+cache directory. Subtitles embedded in MP4 files and image based
+subtitles, such as those from DVDs and Blu-rays, aren't served.
+
+### Search
+
+The ContentDirectory Search action is supported. It searches the
+index of media files rclone has seen, so only files in folders which
+have been browsed are found unless ` + "`--scan`" + ` is used, which indexes
+the whole remote when the server starts.
+
 ` + dlnaflags.Help + vfs.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
@@ -63,6 +96,9 @@ const (
 	serverField       = "Linux/3.4 DLNADOC/1.50 UPnP/1.0 DMS/1.0"
 	rootDescPath      = "/rootDesc.xml"
 	resPath           = "/r/"
+	coverArtPath      = "/a/"
+	thumbnailPath     = "/t/"
+	subtitlePath      = "/s/"
 	serviceControlURL = "/ctl"
 )
 
@@ -86,8 +122,9 @@ type server struct {
 	// Time interval between SSPD announces
 	AnnounceInterval time.Duration
 
-	f   fs.Fs
-	vfs *vfs.VFS
+	f        fs.Fs
+	vfs      *vfs.VFS
+	metadata *metadataCache
 }
 
 func newServer(f fs.Fs, opt *dlnaflags.Options) *server {
@@ -107,6 +144,10 @@ func newServer(f fs.Fs, opt *dlnaflags.Options) *server {
 		f:   f,
 		vfs: vfs.New(f, &vfsflags.Opt),
 	}
+	s.metadata = newMetadataCache(s.vfs, !opt.NoMetadata)
+	if opt.Scan {
+		go s.metadata.scan("/")
+	}
 
 	s.services = map[string]UPnPService{
 		"ContentDirectory": &contentDirectoryService{
@@ -124,6 +165,12 @@ func newServer(f fs.Fs, opt *dlnaflags.Options) *server {
 	r := http.NewServeMux()
 	r.Handle(resPath, http.StripPrefix(resPath,
 		http.HandlerFunc(s.resourceHandler)))
+	r.Handle(coverArtPath, http.StripPrefix(coverArtPath,
+		http.HandlerFunc(s.coverArtHandler)))
+	r.Handle(thumbnailPath, http.StripPrefix(thumbnailPath,
+		http.HandlerFunc(s.thumbnailHandler)))
+	r.Handle(subtitlePath, http.StripPrefix(subtitlePath,
+		http.HandlerFunc(s.subtitleHandler)))
 	if opt.LogTrace {
 		r.Handle(rootDescPath, traceLogging(http.HandlerFunc(s.rootDescHandler)))
 		r.Handle(serviceControlURL, traceLogging(http.HandlerFunc(s.serviceControlHandler)))
@@ -241,6 +288,13 @@ func (s *server) resourceHandler(w http.ResponseWriter, r *http.Request) {
 		}.String())
 	}
 	w.Header().Set("transferMode.dlna.org", "Streaming")
+	if r.Header.Get("getCaptionInfo.sec") != "" {
+		if subtitles := s.mediaResources(remotePath); len(subtitles) > 0 {
+			w.Header().Set("CaptionInfo.sec", resourceURL(r.Host, resPath, subtitles[0].Path()))
+		} else if info := s.metadata.info(remotePath); info != nil && len(info.Subtitles) > 0 {
+			w.Header().Set("CaptionInfo.sec", subtitleURL(r.Host, remotePath, info.Subtitles[0].Track))
+		}
+	}
 
 	file := node.(*vfs.File)
 	in, err := file.Open(os.O_RDONLY)
@@ -286,6 +340,7 @@ func (s *server) Wait() {
 }
 
 func (s *server) Close() {
+	s.metadata.close()
 	err := s.HTTPConn.Close()
 	if err != nil {
 		fs.Errorf(s.f, "Error closing HTTP server: %v", err)
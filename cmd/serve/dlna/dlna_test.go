@@ -13,6 +13,7 @@ import (
 
 	"github.com/anacrolix/dms/soap"
 
+	"github.com/rclone/rclone/fs/config"
 	"github.com/rclone/rclone/fs/config/configfile"
 	"github.com/rclone/rclone/vfs"
 
@@ -42,6 +43,9 @@ func startServer(t *testing.T, f fs.Fs) {
 
 func TestInit(t *testing.T) {
 	configfile.Install()
+	cacheDir, err := ioutil.TempDir("", "rclone-serve-dlna")
+	require.NoError(t, err)
+	require.NoError(t, config.SetCacheDir(cacheDir))
 
 	f, err := fs.NewFs(context.Background(), "testdata/files")
 	l, _ := f.List(context.Background(), "")
@@ -203,3 +207,59 @@ func TestContentDirectoryBrowseDirectChildren(t *testing.T) {
 	require.Contains(t, string(body), "/r/subdir/video.mp4")
 	require.Contains(t, string(body), "/r/subdir/video.srt")
 }
+
+// Check that ContentDirectory#Search finds the items browsed so far.
+func TestContentDirectorySearch(t *testing.T) {
+	req, err := http.NewRequest("POST", baseURL+serviceControlURL, strings.NewReader(`
+<?xml version="1.0" encoding="utf-8"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
+            s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
+    <s:Body>
+        <u:Search xmlns:u="urn:schemas-upnp-org:service:ContentDirectory:1">
+            <ContainerID>0</ContainerID>
+            <SearchCriteria>upnp:class derivedfrom "object.item.videoItem" and dc:title contains "VIDEO"</SearchCriteria>
+            <Filter>*</Filter>
+            <StartingIndex>0</StartingIndex>
+            <RequestedCount>0</RequestedCount>
+            <SortCriteria>-@id</SortCriteria>
+        </u:Search>
+    </s:Body>
+</s:Envelope>`))
+	require.NoError(t, err)
+	req.Header.Set("SOAPACTION", `"urn:schemas-upnp-org:service:ContentDirectory:1#Search"`)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "<TotalMatches>2</TotalMatches>")
+	subdir := strings.Index(string(body), "/r/subdir/video.mp4")
+	root := strings.Index(string(body), "/r/video.mp4")
+	require.True(t, subdir >= 0 && root >= 0)
+	assert.True(t, root < subdir, "sorted by descending id")
+	assert.NotContains(t, string(body), "small_jpeg.jpg")
+	// The subtitles should be found too
+	assert.Contains(t, string(body), "/r/subdir/video.srt")
+	assert.Contains(t, string(body), html.EscapeString("<sec:CaptionInfoEx"))
+
+	// Bad criteria is an error
+	req, err = http.NewRequest("POST", baseURL+serviceControlURL, strings.NewReader(`
+<?xml version="1.0" encoding="utf-8"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"
+            s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
+    <s:Body>
+        <u:Search xmlns:u="urn:schemas-upnp-org:service:ContentDirectory:1">
+            <ContainerID>0</ContainerID>
+            <SearchCriteria>dc:title likes "video"</SearchCriteria>
+        </u:Search>
+    </s:Body>
+</s:Envelope>`))
+	require.NoError(t, err)
+	req.Header.Set("SOAPACTION", `"urn:schemas-upnp-org:service:ContentDirectory:1#Search"`)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	body, err = ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "<errorCode>708</errorCode>")
+}
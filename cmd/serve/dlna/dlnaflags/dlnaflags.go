@@ -26,6 +26,8 @@ type Options struct {
 	ListenAddr   string
 	FriendlyName string
 	LogTrace     bool
+	NoMetadata   bool
+	Scan         bool
 }
 
 // DefaultOpt contains the defaults options for DLNA serving.
@@ -33,6 +35,8 @@ var DefaultOpt = Options{
 	ListenAddr:   ":7879",
 	FriendlyName: "",
 	LogTrace:     false,
+	NoMetadata:   false,
+	Scan:         false,
 }
 
 // Opt contains the options for DLNA serving.
@@ -45,6 +49,8 @@ func addFlagsPrefix(flagSet *pflag.FlagSet, prefix string, Opt *Options) {
 	flags.StringVarP(flagSet, &Opt.ListenAddr, prefix+"addr", "", Opt.ListenAddr, "ip:port or :port to bind the DLNA http server to.")
 	flags.StringVarP(flagSet, &Opt.FriendlyName, prefix+"name", "", Opt.FriendlyName, "name of DLNA server")
 	flags.BoolVarP(flagSet, &Opt.LogTrace, prefix+"log-trace", "", Opt.LogTrace, "enable trace logging of SOAP traffic")
+	flags.BoolVarP(flagSet, &Opt.NoMetadata, prefix+"no-metadata", "", Opt.NoMetadata, "don't read the metadata of media files")
+	flags.BoolVarP(flagSet, &Opt.Scan, prefix+"scan", "", Opt.Scan, "index all the media files at startup so they can be searched")
 }
 
 // AddFlags add the command line flags for DLNA serving.
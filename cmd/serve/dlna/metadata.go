@@ -0,0 +1,297 @@
+package dlna
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/dlna/probe"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/lib/atexit"
+	"github.com/rclone/rclone/vfs"
+)
+
+const (
+	// how long to wait after a change before saving the metadata
+	metadataSaveDelay = 10 * time.Second
+	// number of files to probe at once
+	metadataProbers = 4
+	// maximum number of files waiting to be probed
+	metadataQueueSize = 4096
+)
+
+// metadataEntry is the cached metadata of a media file
+type metadataEntry struct {
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"modTime"`
+	Probed  bool        `json:"probed,omitempty"`
+	Info    *probe.Info `json:"info,omitempty"` // nil if not probed or the format is unknown
+}
+
+// metadataCache is the index of the media files the server has seen
+// along with the metadata read from their headers.
+//
+// Files are probed in the background so listing directories isn't
+// slowed down. The index is saved in the cache directory so it
+// survives restarts.
+type metadataCache struct {
+	vfs       *vfs.VFS
+	path      string // file to save the index in
+	probe     bool   // set to read the metadata
+	mu        sync.Mutex
+	entries   map[string]*metadataEntry // by path
+	pending   map[string]bool           // paths queued for probing
+	queue     chan string
+	saveTimer *time.Timer
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// metadataCachePath returns the file the index for f is kept in
+func metadataCachePath(f fs.Fs) string {
+	hash := md5.Sum([]byte(fs.ConfigString(f)))
+	return filepath.Join(config.GetCacheDir(), "serve-dlna", hex.EncodeToString(hash[:])+".json")
+}
+
+// newMetadataCache makes the index for the VFS, reading it from
+// the cache directory if it was saved there.
+//
+// If probeFiles is set then the metadata of the files is read.
+func newMetadataCache(VFS *vfs.VFS, probeFiles bool) *metadataCache {
+	c := &metadataCache{
+		vfs:     VFS,
+		path:    metadataCachePath(VFS.Fs()),
+		probe:   probeFiles,
+		entries: map[string]*metadataEntry{},
+		pending: map[string]bool{},
+		queue:   make(chan string, metadataQueueSize),
+		done:    make(chan struct{}),
+	}
+	data, err := ioutil.ReadFile(c.path)
+	if err == nil {
+		err = json.Unmarshal(data, &c.entries)
+		if err != nil {
+			fs.Errorf(nil, "Ignoring corrupted DLNA metadata cache %q: %v", c.path, err)
+			c.entries = map[string]*metadataEntry{}
+		} else {
+			fs.Debugf(nil, "Read metadata of %d files from %q", len(c.entries), c.path)
+		}
+	} else if !os.IsNotExist(err) {
+		fs.Errorf(nil, "Failed to read DLNA metadata cache: %v", err)
+	}
+	if c.probe {
+		for i := 0; i < metadataProbers; i++ {
+			c.wg.Add(1)
+			go c.prober()
+		}
+	}
+	atexit.Register(c.flush)
+	return c
+}
+
+// get returns the entry for the media file node, noting it in the
+// index and queueing it to be probed if it isn't up to date.
+func (c *metadataCache) get(remote string, node vfs.Node) *metadataEntry {
+	remote = path.Clean("/" + remote)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entries[remote]
+	if entry != nil && entry.Size == node.Size() && entry.ModTime.Equal(node.ModTime()) {
+		if entry.Probed || !c.probe {
+			return entry
+		}
+	} else {
+		entry = &metadataEntry{
+			Size:    node.Size(),
+			ModTime: node.ModTime(),
+		}
+		c.entries[remote] = entry
+		c.changed()
+	}
+	if c.probe && !c.pending[remote] {
+		select {
+		case c.queue <- remote:
+			c.pending[remote] = true
+		default:
+			// queue is full - try again when next seen
+		}
+	}
+	return entry
+}
+
+// info returns the metadata for remote if known
+func (c *metadataCache) info(remote string) *probe.Info {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry := c.entries[path.Clean("/"+remote)]; entry != nil {
+		return entry.Info
+	}
+	return nil
+}
+
+// paths returns the paths in the index under dir
+func (c *metadataCache) paths(dir string) (paths []string) {
+	dir = path.Clean("/" + dir)
+	prefix := dir + "/"
+	if dir == "/" {
+		prefix = dir
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for remote := range c.entries {
+		if strings.HasPrefix(remote, prefix) {
+			paths = append(paths, remote)
+		}
+	}
+	return paths
+}
+
+// forget removes remote from the index
+func (c *metadataCache) forget(remote string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, path.Clean("/"+remote))
+	c.changed()
+}
+
+// prober probes the files from the queue until the cache is closed
+func (c *metadataCache) prober() {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.done:
+			return
+		case remote := <-c.queue:
+			c.probeFile(remote)
+		}
+	}
+}
+
+// probeFile reads the metadata of remote and stores it in the index
+func (c *metadataCache) probeFile(remote string) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, remote)
+		c.mu.Unlock()
+	}()
+	node, err := c.vfs.Stat(remote)
+	if err != nil {
+		c.forget(remote)
+		return
+	}
+	file, ok := node.(*vfs.File)
+	if !ok {
+		c.forget(remote)
+		return
+	}
+	info, err := probeNode(file)
+	if err != nil && err != probe.ErrUnknownFormat {
+		fs.Debugf(remote, "Failed to read media metadata: %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[remote] = &metadataEntry{
+		Size:    node.Size(),
+		ModTime: node.ModTime(),
+		Probed:  true,
+		Info:    info,
+	}
+	c.changed()
+}
+
+// probeNode reads the metadata of file
+func probeNode(file *vfs.File) (info *probe.Info, err error) {
+	in, err := file.Open(os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.CheckClose(in, &err)
+	return probe.Probe(in, file.Size())
+}
+
+// scan adds all the media files under dir to the index
+func (c *metadataCache) scan(dir string) {
+	node, err := c.vfs.Stat(dir)
+	if err != nil {
+		fs.Errorf(dir, "Failed to scan for media: %v", err)
+		return
+	}
+	d, ok := node.(*vfs.Dir)
+	if !ok {
+		return
+	}
+	nodes, err := d.ReadDirAll()
+	if err != nil {
+		fs.Errorf(dir, "Failed to scan for media: %v", err)
+		return
+	}
+	for _, node := range nodes {
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		remote := path.Join(dir, node.Name())
+		if node.IsDir() {
+			c.scan(remote)
+		} else if mediaMimeTypeRegexp.MatchString(fs.MimeTypeFromName(node.Name())) {
+			c.get(remote, node)
+		}
+	}
+}
+
+// changed schedules the index to be saved - call with the lock held
+func (c *metadataCache) changed() {
+	if c.saveTimer != nil {
+		return
+	}
+	c.saveTimer = time.AfterFunc(metadataSaveDelay, c.flush)
+}
+
+// flush saves the index if it has changed
+func (c *metadataCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.saveTimer == nil {
+		return
+	}
+	c.saveTimer.Stop()
+	c.saveTimer = nil
+	if err := c.save(); err != nil {
+		fs.Errorf(nil, "Failed to save DLNA metadata cache: %v", err)
+	}
+}
+
+// save writes the index to the file - call with the lock held
+func (c *metadataCache) save() error {
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write metadata cache")
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// close stops the probers and saves the index
+func (c *metadataCache) close() {
+	close(c.done)
+	c.wg.Wait()
+	c.flush()
+}
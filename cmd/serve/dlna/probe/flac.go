@@ -0,0 +1,134 @@
+package probe
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FLAC files start with "fLaC" and a series of metadata blocks, each
+// with a 4 byte header giving its type and length.
+
+// FLAC metadata block types
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+	flacPicture       = 6
+)
+
+// probeFLAC reads the metadata from a FLAC file
+func probeFLAC(br *blockReader, info *Info) error {
+	for off := int64(4); ; {
+		hdr, err := br.read(off, 4)
+		if err != nil {
+			return err
+		}
+		if len(hdr) < 4 {
+			return errors.New("flac: short metadata block")
+		}
+		last := hdr[0]&0x80 != 0
+		typ := hdr[0] & 0x7F
+		size := int64(hdr[1])<<16 | int64(hdr[2])<<8 | int64(hdr[3])
+		off += 4
+		switch typ {
+		case flacStreamInfo, flacVorbisComment, flacPicture:
+			data, err := br.read(off, size)
+			if err != nil {
+				return err
+			}
+			switch typ {
+			case flacStreamInfo:
+				parseStreamInfo(data, info)
+			case flacVorbisComment:
+				parseVorbisComment(data, info)
+			case flacPicture:
+				parseFLACPicture(data, off, info)
+			}
+		}
+		if last {
+			return nil
+		}
+		off += size
+	}
+}
+
+// parseStreamInfo reads the sample rate, channels and duration
+func parseStreamInfo(data []byte, info *Info) {
+	if len(data) < 18 {
+		return
+	}
+	// 20 bits of sample rate, 3 bits of channels-1, 5 bits of bits
+	// per sample-1 then 36 bits of total samples
+	x := binary.BigEndian.Uint64(data[10:])
+	sampleRate := int(x >> 44)
+	info.SampleRate = sampleRate
+	info.Channels = int((x>>41)&7) + 1
+	samples := x & (1<<36 - 1)
+	if sampleRate > 0 && samples > 0 {
+		info.Duration = time.Duration(float64(samples) / float64(sampleRate) * float64(time.Second))
+	}
+}
+
+// parseVorbisComment reads the tags
+func parseVorbisComment(data []byte, info *Info) {
+	// everything is little endian
+	if len(data) < 4 {
+		return
+	}
+	vendorLen := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	if vendorLen+4 > len(data) {
+		return
+	}
+	data = data[vendorLen:]
+	n := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	for i := 0; i < n && len(data) >= 4; i++ {
+		length := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if length > len(data) {
+			return
+		}
+		comment := string(data[:length])
+		data = data[length:]
+		if i := strings.IndexByte(comment, '='); i > 0 {
+			setTag(info, comment[:i], comment[i+1:])
+		}
+	}
+}
+
+// parseFLACPicture parses a picture block at off
+func parseFLACPicture(data []byte, off int64, info *Info) {
+	if len(data) < 8 {
+		return
+	}
+	pictureType := binary.BigEndian.Uint32(data)
+	i := 4
+	field := func() []byte {
+		if i+4 > len(data) {
+			return nil
+		}
+		n := int(binary.BigEndian.Uint32(data[i:]))
+		i += 4
+		if n > len(data)-i {
+			i = len(data)
+			return nil
+		}
+		b := data[i : i+n]
+		i += n
+		return b
+	}
+	mimeType := string(field())
+	field()    // description
+	i += 4 * 4 // width, height, depth, colours
+	picture := field()
+	if len(picture) == 0 || !strings.HasPrefix(mimeType, "image/") {
+		return
+	}
+	// Prefer the front cover
+	if info.Cover == nil || pictureType == 3 {
+		info.Cover = &Part{MimeType: mimeType, Offset: off + int64(i-len(picture)), Length: int64(len(picture))}
+	}
+}
@@ -0,0 +1,479 @@
+package probe
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Matroska and WebM files are EBML - a tree of elements each with an
+// ID and a size. The metadata is in the Info, Tracks, Tags and
+// Attachments elements of the Segment, which may be before or after
+// the Clusters with the media. The SeekHead says where they are.
+
+// EBML element IDs
+const (
+	idSegment      = 0x18538067
+	idSeekHead     = 0x114D9B74
+	idSeek         = 0x4DBB
+	idSeekID       = 0x53AB
+	idSeekPosition = 0x53AC
+	idInfo         = 0x1549A966
+	idTimecode     = 0x2AD7B1
+	idDuration     = 0x4489
+	idTitle        = 0x7BA9
+	idTracks       = 0x1654AE6B
+	idTrackEntry   = 0xAE
+	idTrackNumber  = 0xD7
+	idCodecID      = 0x86
+	idName         = 0x536E
+	idLanguage     = 0x22B59C
+	idVideo        = 0xE0
+	idPixelWidth   = 0xB0
+	idPixelHeight  = 0xBA
+	idAudio        = 0xE1
+	idSampleRate   = 0xB5
+	idChannels     = 0x9F
+	idTags         = 0x1254C367
+	idTag          = 0x7373
+	idSimpleTag    = 0x67C8
+	idTagName      = 0x45A3
+	idTagString    = 0x4487
+	idAttachments  = 0x1941A469
+	idAttachedFile = 0x61A7
+	idFileName     = 0x466E
+	idFileMimeType = 0x4660
+	idFileData     = 0x465C
+	idCluster      = 0x1F43B675
+	idClusterTime  = 0xE7
+	idSimpleBlock  = 0xA3
+	idBlockGroup   = 0xA0
+	idBlock        = 0xA1
+	idBlockDur     = 0x9B
+)
+
+// codecSRT is the codec ID of plain text subtitle tracks, which are
+// in SRT format without the numbers and times
+const codecSRT = "S_TEXT/UTF8"
+
+// unknownSize is the size of elements with an unknown size
+const unknownSize = -1
+
+// readVint reads a variable length integer from b, returning it and
+// its length. If keepMarker is set the length marker bit is kept, as
+// it is for element IDs.
+func readVint(b []byte, keepMarker bool) (value int64, n int, err error) {
+	if len(b) == 0 || b[0] == 0 {
+		return 0, 0, errors.New("mkv: bad variable length integer")
+	}
+	n = 1
+	for mask := byte(0x80); b[0]&mask == 0; mask >>= 1 {
+		n++
+	}
+	if n > 8 || len(b) < n {
+		return 0, 0, errors.New("mkv: bad variable length integer")
+	}
+	first := b[0]
+	if !keepMarker {
+		first &^= 0x80 >> uint(n-1)
+	}
+	value = int64(first)
+	allOnes := first == 0xFF>>uint(n)
+	for _, c := range b[1:n] {
+		value = value<<8 | int64(c)
+		allOnes = allOnes && c == 0xFF
+	}
+	if !keepMarker && allOnes {
+		value = unknownSize
+	}
+	return value, n, nil
+}
+
+// element is a parsed element header
+type element struct {
+	id   int64
+	off  int64 // offset of the data in the file
+	size int64 // size of the data or unknownSize
+}
+
+// readElement reads the element header at off
+func readElement(br *blockReader, off int64) (e element, err error) {
+	b, err := br.read(off, 12)
+	if err != nil {
+		return e, err
+	}
+	id, n, err := readVint(b, true)
+	if err != nil {
+		return e, err
+	}
+	size, m, err := readVint(b[n:], false)
+	if err != nil {
+		return e, err
+	}
+	return element{id: id, off: off + int64(n+m), size: size}, nil
+}
+
+// children calls fn for each element between start and end
+func children(br *blockReader, start, end int64, fn func(e element) error) error {
+	for off := start; off < end; {
+		e, err := readElement(br, off)
+		if err != nil {
+			return err
+		}
+		if e.size == unknownSize {
+			// only allowed for the Segment and Clusters - stop here
+			return fn(e)
+		}
+		err = fn(e)
+		if err != nil {
+			return err
+		}
+		off = e.off + e.size
+	}
+	return nil
+}
+
+// data reads the data of e
+func (e element) data(br *blockReader) ([]byte, error) {
+	return br.read(e.off, e.size)
+}
+
+// uint reads e as an unsigned integer
+func (e element) uint(br *blockReader) (uint64, error) {
+	b, err := e.data(br)
+	if err != nil || len(b) > 8 {
+		return 0, err
+	}
+	var value uint64
+	for _, c := range b {
+		value = value<<8 | uint64(c)
+	}
+	return value, nil
+}
+
+// float reads e as a floating point number
+func (e element) float(br *blockReader) (float64, error) {
+	b, err := e.data(br)
+	if err != nil {
+		return 0, err
+	}
+	switch len(b) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	}
+	return 0, nil
+}
+
+// string reads e as a string
+func (e element) string(br *blockReader) (string, error) {
+	b, err := e.data(br)
+	return strings.TrimRight(string(b), "\x00"), err
+}
+
+// probeMKV reads the metadata from a Matroska or WebM file
+func probeMKV(br *blockReader, info *Info) error {
+	header, err := readElement(br, 0)
+	if err != nil {
+		return err
+	}
+	segment, err := readElement(br, header.off+header.size)
+	if err != nil {
+		return err
+	}
+	if segment.id != idSegment {
+		return errors.New("mkv: no segment")
+	}
+	end := br.size
+	if segment.size != unknownSize && segment.off+segment.size < end {
+		end = segment.off + segment.size
+	}
+	m := &mkv{
+		br:      br,
+		info:    info,
+		segment: segment.off,
+		done:    map[int64]bool{},
+	}
+	// Read the top level elements until the first Cluster
+	err = children(br, segment.off, end, func(e element) error {
+		if e.id == idCluster {
+			return errStop
+		}
+		return m.parse(e)
+	})
+	if err != nil && err != errStop {
+		return err
+	}
+	// Read anything else the SeekHead says is after the Clusters
+	for _, pos := range m.seeks {
+		e, err := readElement(br, pos)
+		if err != nil {
+			return err
+		}
+		err = m.parse(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errStop stops the parsing
+var errStop = errors.New("stop")
+
+// mkv holds the state of parsing a Matroska file
+type mkv struct {
+	br      *blockReader
+	info    *Info
+	segment int64          // offset of the segment data
+	done    map[int64]bool // elements parsed
+	seeks   []int64        // positions of elements from the SeekHead
+	scale   float64        // timecode scale
+	length  float64        // duration in timecode units
+}
+
+// parse parses a top level element
+func (m *mkv) parse(e element) error {
+	if m.done[e.id] && e.id != idSeekHead {
+		return nil
+	}
+	m.done[e.id] = true
+	switch e.id {
+	case idSeekHead:
+		return m.parseSeekHead(e)
+	case idInfo:
+		return m.parseInfo(e)
+	case idTracks:
+		return m.parseTracks(e)
+	case idTags:
+		return m.parseTags(e)
+	case idAttachments:
+		return m.parseAttachments(e)
+	}
+	return nil
+}
+
+// parseSeekHead notes where the elements we want are
+func (m *mkv) parseSeekHead(e element) error {
+	return children(m.br, e.off, e.off+e.size, func(seek element) error {
+		if seek.id != idSeek {
+			return nil
+		}
+		var id int64
+		pos := int64(-1)
+		err := children(m.br, seek.off, seek.off+seek.size, func(e element) error {
+			switch e.id {
+			case idSeekID:
+				b, err := e.data(m.br)
+				if err != nil {
+					return err
+				}
+				id, _, err = readVint(b, true)
+				return err
+			case idSeekPosition:
+				p, err := e.uint(m.br)
+				pos = int64(p)
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		switch id {
+		case idInfo, idTracks, idTags, idAttachments, idSeekHead:
+			if pos >= 0 && !m.done[id] {
+				m.seeks = append(m.seeks, m.segment+pos)
+			}
+		}
+		return nil
+	})
+}
+
+// parseInfo reads the duration and title
+func (m *mkv) parseInfo(e element) error {
+	m.scale = 1000000
+	err := children(m.br, e.off, e.off+e.size, func(e element) (err error) {
+		switch e.id {
+		case idTimecode:
+			var scale uint64
+			scale, err = e.uint(m.br)
+			if scale != 0 {
+				m.scale = float64(scale)
+			}
+		case idDuration:
+			m.length, err = e.float(m.br)
+		case idTitle:
+			m.info.Title, err = e.string(m.br)
+		}
+		return err
+	})
+	if m.length > 0 {
+		m.info.Duration = time.Duration(m.length * m.scale)
+	}
+	return err
+}
+
+// parseTracks reads the video size, audio sample rate and the text
+// subtitle tracks
+func (m *mkv) parseTracks(e element) error {
+	return children(m.br, e.off, e.off+e.size, func(track element) error {
+		if track.id != idTrackEntry {
+			return nil
+		}
+		var (
+			number uint64
+			codec  string
+			sub    Subtitle
+		)
+		err := children(m.br, track.off, track.off+track.size, func(e element) (err error) {
+			switch e.id {
+			case idTrackNumber:
+				number, err = e.uint(m.br)
+				return err
+			case idCodecID:
+				codec, err = e.string(m.br)
+				return err
+			case idName:
+				sub.Name, err = e.string(m.br)
+				return err
+			case idLanguage:
+				sub.Language, err = e.string(m.br)
+				return err
+			case idVideo:
+				var width, height uint64
+				err := children(m.br, e.off, e.off+e.size, func(e element) (err error) {
+					switch e.id {
+					case idPixelWidth:
+						width, err = e.uint(m.br)
+					case idPixelHeight:
+						height, err = e.uint(m.br)
+					}
+					return err
+				})
+				if int(width) > m.info.Width {
+					m.info.Width, m.info.Height = int(width), int(height)
+				}
+				return err
+			case idAudio:
+				return children(m.br, e.off, e.off+e.size, func(e element) error {
+					switch e.id {
+					case idSampleRate:
+						rate, err := e.float(m.br)
+						if m.info.SampleRate == 0 {
+							m.info.SampleRate = int(rate)
+						}
+						return err
+					case idChannels:
+						channels, err := e.uint(m.br)
+						if m.info.Channels == 0 {
+							m.info.Channels = int(channels)
+						}
+						return err
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if codec == codecSRT && number > 0 {
+			sub.Track = int(number)
+			m.info.Subtitles = append(m.info.Subtitles, sub)
+		}
+		return nil
+	})
+}
+
+// parseTags reads the title, artist, album and genre tags
+func (m *mkv) parseTags(e element) error {
+	return children(m.br, e.off, e.off+e.size, func(tag element) error {
+		if tag.id != idTag {
+			return nil
+		}
+		return children(m.br, tag.off, tag.off+tag.size, func(simple element) error {
+			if simple.id != idSimpleTag {
+				return nil
+			}
+			var name, value string
+			err := children(m.br, simple.off, simple.off+simple.size, func(e element) (err error) {
+				switch e.id {
+				case idTagName:
+					name, err = e.string(m.br)
+				case idTagString:
+					value, err = e.string(m.br)
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			setTag(m.info, name, value)
+			return nil
+		})
+	})
+}
+
+// parseAttachments finds the cover art
+func (m *mkv) parseAttachments(e element) error {
+	return children(m.br, e.off, e.off+e.size, func(file element) error {
+		if file.id != idAttachedFile {
+			return nil
+		}
+		var name, mimeType string
+		var data element
+		err := children(m.br, file.off, file.off+file.size, func(e element) (err error) {
+			switch e.id {
+			case idFileName:
+				name, err = e.string(m.br)
+			case idFileMimeType:
+				mimeType, err = e.string(m.br)
+			case idFileData:
+				data = e
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(mimeType, "image/") || data.size <= 0 {
+			return nil
+		}
+		// Prefer the attachment called cover
+		if m.info.Cover == nil || strings.HasPrefix(strings.ToLower(name), "cover") {
+			m.info.Cover = &Part{MimeType: mimeType, Offset: data.off, Length: data.size}
+		}
+		return nil
+	})
+}
+
+// setTag sets the tag called name, if it is one we want, to value
+func setTag(info *Info, name, value string) {
+	if value == "" {
+		return
+	}
+	switch strings.ToUpper(name) {
+	case "TITLE":
+		if info.Title == "" {
+			info.Title = value
+		}
+	case "ARTIST":
+		if info.Artist == "" {
+			info.Artist = value
+		}
+	case "ALBUM":
+		if info.Album == "" {
+			info.Album = value
+		}
+	case "GENRE":
+		if info.Genre == "" {
+			info.Genre = value
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// MP3 files are a series of frames, optionally preceded by an ID3v2
+// tag with the metadata and followed by an ID3v1 tag. The first frame
+// may be a Xing or VBRI header saying how many frames there are.
+
+// bitrates in kbit/s indexed by [mpeg2][layer-1][index]
+var mp3Bitrates = [2][3][16]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// sample rates for MPEG 1 - halved for MPEG 2 and quartered for 2.5
+var mp3SampleRates = [3]int{44100, 48000, 32000}
+
+// mp3Frame is a parsed frame header
+type mp3Frame struct {
+	version    int // 1, 2 or 25 for MPEG 2.5
+	layer      int
+	bitrate    int // bits/s
+	sampleRate int
+	channels   int
+	samples    int // samples in the frame
+}
+
+// parseMP3Frame parses the frame header in b
+func parseMP3Frame(b []byte) (f mp3Frame, ok bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return f, false
+	}
+	switch (b[1] >> 3) & 3 {
+	case 0:
+		f.version = 25
+	case 2:
+		f.version = 2
+	case 3:
+		f.version = 1
+	default:
+		return f, false
+	}
+	f.layer = 4 - int((b[1]>>1)&3)
+	if f.layer == 4 {
+		return f, false
+	}
+	bitrateIndex := b[2] >> 4
+	rateIndex := (b[2] >> 2) & 3
+	if bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return f, false
+	}
+	mpeg2 := 0
+	if f.version != 1 {
+		mpeg2 = 1
+	}
+	f.bitrate = mp3Bitrates[mpeg2][f.layer-1][bitrateIndex] * 1000
+	f.sampleRate = mp3SampleRates[rateIndex]
+	switch f.version {
+	case 2:
+		f.sampleRate /= 2
+	case 25:
+		f.sampleRate /= 4
+	}
+	f.channels = 2
+	if b[3]>>6 == 3 {
+		f.channels = 1
+	}
+	switch {
+	case f.layer == 1:
+		f.samples = 384
+	case f.layer == 3 && f.version != 1:
+		f.samples = 576
+	default:
+		f.samples = 1152
+	}
+	return f, true
+}
+
+// isMP3Frame returns whether b starts with an MP3 frame header
+func isMP3Frame(b []byte) bool {
+	_, ok := parseMP3Frame(b)
+	return ok
+}
+
+// probeMP3 reads the metadata from an MP3 file
+func probeMP3(br *blockReader, info *Info) error {
+	start := int64(0)
+	hdr, err := br.read(0, 10)
+	if err != nil {
+		return err
+	}
+	if len(hdr) == 10 && string(hdr[:3]) == "ID3" {
+		size := syncsafe(hdr[6:10])
+		tag, err := br.read(10, size)
+		if err != nil {
+			return err
+		}
+		parseID3v2(tag, hdr[3], hdr[5], 10, info)
+		start = 10 + size
+		if hdr[5]&0x10 != 0 {
+			start += 10 // footer
+		}
+	}
+	parseID3v1(br, info)
+
+	// Find the first frame - there may be padding after the tag
+	data, err := br.read(start, 4096)
+	if err != nil {
+		return err
+	}
+	i := 0
+	for ; i+4 <= len(data) && !isMP3Frame(data[i:]); i++ {
+	}
+	frame, ok := parseMP3Frame(data[i:])
+	if !ok {
+		if start > 0 {
+			// An ID3 tag on something else
+			return nil
+		}
+		return errors.New("mp3: no frame found")
+	}
+	info.SampleRate = frame.sampleRate
+	info.Channels = frame.channels
+	frames := vbrFrames(data[i:], frame)
+	if frames > 0 {
+		info.Duration = time.Duration(float64(frames) * float64(frame.samples) / float64(frame.sampleRate) * float64(time.Second))
+	} else {
+		// Assume a constant bit rate
+		info.Bitrate = int64(frame.bitrate / 8)
+		info.Duration = time.Duration(float64(br.size-start-int64(i)) / float64(info.Bitrate) * float64(time.Second))
+	}
+	return nil
+}
+
+// vbrFrames returns the number of frames from the Xing or VBRI header
+// in the frame in data or 0 if there isn't one
+func vbrFrames(data []byte, frame mp3Frame) int {
+	// The Xing header is after the side information
+	var side int
+	switch {
+	case frame.version == 1 && frame.channels == 2:
+		side = 32
+	case frame.version == 1 || frame.channels == 2:
+		side = 17
+	default:
+		side = 9
+	}
+	if off := 4 + side; len(data) >= off+12 {
+		tag := string(data[off : off+4])
+		flags := binary.BigEndian.Uint32(data[off+4:])
+		if (tag == "Xing" || tag == "Info") && flags&1 != 0 {
+			return int(binary.BigEndian.Uint32(data[off+8:]))
+		}
+	}
+	// The VBRI header is always 32 bytes after the frame header
+	if off := 4 + 32; len(data) >= off+18 && string(data[off:off+4]) == "VBRI" {
+		return int(binary.BigEndian.Uint32(data[off+14:]))
+	}
+	return 0
+}
+
+// syncsafe decodes a 28 bit syncsafe integer
+func syncsafe(b []byte) int64 {
+	return int64(b[0]&0x7F)<<21 | int64(b[1]&0x7F)<<14 | int64(b[2]&0x7F)<<7 | int64(b[3]&0x7F)
+}
+
+// parseID3v2 parses the frames in the ID3v2 tag in data, which is at
+// off in the file
+func parseID3v2(data []byte, version, flags byte, off int64, info *Info) {
+	if flags&0x80 != 0 {
+		// Unsynchronisation changes the offsets so don't try to
+		// find the cover art
+		data = bytes.Replace(data, []byte{0xFF, 0x00}, []byte{0xFF}, -1)
+		off = -1
+	}
+	pos := 0
+	if flags&0x40 != 0 && version >= 3 && len(data) >= 4 {
+		// skip the extended header
+		if version == 3 {
+			pos = 4 + int(binary.BigEndian.Uint32(data))
+		} else {
+			pos = int(syncsafe(data))
+		}
+	}
+	idLen, hdrLen := 4, 10
+	if version == 2 {
+		idLen, hdrLen = 3, 6
+	}
+	for pos+hdrLen <= len(data) && data[pos] != 0 {
+		id := string(data[pos : pos+idLen])
+		var size int
+		switch version {
+		case 2:
+			size = int(data[pos+3])<<16 | int(data[pos+4])<<8 | int(data[pos+5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(data[pos+4:]))
+		default:
+			size = int(syncsafe(data[pos+4:]))
+		}
+		pos += hdrLen
+		if size < 0 || pos+size > len(data) {
+			return
+		}
+		frame := data[pos : pos+size]
+		switch id {
+		case "TIT2", "TT2":
+			info.Title = id3Text(frame)
+		case "TPE1", "TP1":
+			info.Artist = id3Text(frame)
+		case "TALB", "TAL":
+			info.Album = id3Text(frame)
+		case "TCON", "TCO":
+			info.Genre = id3Genre(id3Text(frame))
+		case "APIC", "PIC":
+			if off >= 0 {
+				parseID3Picture(frame, id == "PIC", off+int64(pos), info)
+			}
+		}
+		pos += size
+	}
+}
+
+// parseID3Picture parses an attached picture frame at off
+func parseID3Picture(frame []byte, v22 bool, off int64, info *Info) {
+	if len(frame) < 4 {
+		return
+	}
+	encoding := frame[0]
+	var mimeType string
+	i := 1
+	if v22 {
+		switch strings.ToUpper(string(frame[1:4])) {
+		case "PNG":
+			mimeType = "image/png"
+		default:
+			mimeType = "image/jpeg"
+		}
+		i = 4
+	} else {
+		end := bytes.IndexByte(frame[i:], 0)
+		if end < 0 {
+			return
+		}
+		mimeType = strings.ToLower(string(frame[i : i+end]))
+		if !strings.Contains(mimeType, "/") {
+			mimeType = "image/" + mimeType
+		}
+		i += end + 1
+	}
+	if i >= len(frame) {
+		return
+	}
+	pictureType := frame[i]
+	i++
+	// skip the description
+	if encoding == 1 || encoding == 2 {
+		for ; i+1 < len(frame) && (frame[i] != 0 || frame[i+1] != 0); i += 2 {
+		}
+		i += 2
+	} else {
+		for ; i < len(frame) && frame[i] != 0; i++ {
+		}
+		i++
+	}
+	if i >= len(frame) {
+		return
+	}
+	// Prefer the front cover
+	if info.Cover == nil || pictureType == 3 {
+		info.Cover = &Part{MimeType: mimeType, Offset: off + int64(i), Length: int64(len(frame) - i)}
+	}
+}
+
+// id3Text decodes a text frame
+func id3Text(frame []byte) string {
+	if len(frame) == 0 {
+		return ""
+	}
+	var s string
+	text := frame[1:]
+	switch frame[0] {
+	case 0:
+		// ISO-8859-1
+		runes := make([]rune, len(text))
+		for i, c := range text {
+			runes[i] = rune(c)
+		}
+		s = string(runes)
+	case 1, 2:
+		// UTF-16 with a BOM or big endian without
+		bigEndian := frame[0] == 2
+		if len(text) >= 2 {
+			switch {
+			case text[0] == 0xFF && text[1] == 0xFE:
+				bigEndian, text = false, text[2:]
+			case text[0] == 0xFE && text[1] == 0xFF:
+				bigEndian, text = true, text[2:]
+			}
+		}
+		u := make([]uint16, len(text)/2)
+		for i := range u {
+			if bigEndian {
+				u[i] = binary.BigEndian.Uint16(text[2*i:])
+			} else {
+				u[i] = binary.LittleEndian.Uint16(text[2*i:])
+			}
+		}
+		s = string(utf16.Decode(u))
+	default:
+		s = string(text)
+	}
+	// Only the first of multiple values
+	if i := strings.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// id3Genres are the ID3v1 genres
+var id3Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge",
+	"Hip-Hop", "Jazz", "Metal", "New Age", "Oldies", "Other", "Pop", "R&B",
+	"Rap", "Reggae", "Rock", "Techno", "Industrial", "Alternative", "Ska",
+	"Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient",
+	"Trip-Hop", "Vocal", "Jazz+Funk", "Fusion", "Trance", "Classical",
+	"Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"Alternative Rock", "Bass", "Soul", "Punk", "Space", "Meditative",
+	"Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic", "Darkwave",
+	"Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream",
+	"Southern Rock", "Comedy", "Cult", "Gangsta", "Top 40", "Christian Rap",
+	"Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave",
+	"Psychedelic", "Rave", "Showtunes", "Trailer", "Lo-Fi", "Tribal",
+	"Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll",
+	"Hard Rock",
+}
+
+// id3Genre turns genres like "(17)" or "17" into their names
+func id3Genre(genre string) string {
+	s := strings.TrimSuffix(strings.TrimPrefix(genre, "("), ")")
+	n := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return genre
+		}
+		n = n*10 + int(c-'0')
+	}
+	if s != "" && n < len(id3Genres) {
+		return id3Genres[n]
+	}
+	return genre
+}
+
+// parseID3v1 fills in anything missing from the ID3v1 tag at the end
+// of the file
+func parseID3v1(br *blockReader, info *Info) {
+	if br.size < 128 {
+		return
+	}
+	tag, err := br.read(br.size-128, 128)
+	if err != nil || string(tag[:3]) != "TAG" {
+		return
+	}
+	field := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return strings.TrimSpace(string(b))
+	}
+	setTag(info, "TITLE", field(tag[3:33]))
+	setTag(info, "ARTIST", field(tag[33:63]))
+	setTag(info, "ALBUM", field(tag[63:93]))
+	if genre := int(tag[127]); genre < len(id3Genres) {
+		setTag(info, "GENRE", id3Genres[genre])
+	}
+}
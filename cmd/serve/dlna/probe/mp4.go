@@ -0,0 +1,213 @@
+package probe
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MP4 and QuickTime files are made of boxes, each starting with its
+// size and type. The metadata is all in the moov box, which may be at
+// the start or the end of the file.
+
+// box is a parsed box header
+type box struct {
+	typ  string
+	off  int64 // offset of the data in the file
+	size int64 // size of the data
+}
+
+// parseBoxes calls fn for each box in data, which is at off in the
+// file, until fn returns an error
+func parseBoxes(data []byte, off int64, fn func(b box, data []byte) error) error {
+	for len(data) >= 8 {
+		size := int64(binary.BigEndian.Uint32(data))
+		typ := string(data[4:8])
+		hdr := int64(8)
+		switch size {
+		case 0:
+			size = int64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return errors.New("mp4: short box")
+			}
+			size = int64(binary.BigEndian.Uint64(data[8:]))
+			hdr = 16
+		}
+		if size < hdr || size > int64(len(data)) {
+			return errors.Errorf("mp4: bad size for %q box", typ)
+		}
+		err := fn(box{typ: typ, off: off + hdr, size: size - hdr}, data[hdr:size])
+		if err != nil {
+			return err
+		}
+		data = data[size:]
+		off += size
+	}
+	return nil
+}
+
+// probeMP4 reads the metadata from an MP4 file
+func probeMP4(br *blockReader, info *Info) error {
+	// Find the moov box at the top level without reading the others
+	for off := int64(0); off+8 <= br.size; {
+		hdr, err := br.read(off, 16)
+		if err != nil {
+			return err
+		}
+		size, hdrSize := int64(binary.BigEndian.Uint32(hdr)), int64(8)
+		switch size {
+		case 0:
+			size = br.size - off
+		case 1:
+			if len(hdr) < 16 {
+				return errors.New("mp4: short box")
+			}
+			size, hdrSize = int64(binary.BigEndian.Uint64(hdr[8:])), 16
+		}
+		if size < hdrSize {
+			return errors.New("mp4: bad box size")
+		}
+		if string(hdr[4:8]) == "moov" {
+			data, err := br.read(off+hdrSize, size-hdrSize)
+			if err != nil {
+				return err
+			}
+			return parseMoov(data, off+hdrSize, info)
+		}
+		off += size
+	}
+	return errors.New("mp4: no moov box")
+}
+
+// parseMoov parses the moov box
+func parseMoov(data []byte, off int64, info *Info) error {
+	return parseBoxes(data, off, func(b box, data []byte) error {
+		switch b.typ {
+		case "mvhd":
+			parseMvhd(data, info)
+		case "trak":
+			return parseBoxes(data, b.off, func(b box, data []byte) error {
+				switch b.typ {
+				case "tkhd":
+					parseTkhd(data, info)
+				case "mdia":
+					return parseMdia(data, b.off, info)
+				}
+				return nil
+			})
+		case "udta":
+			return parseBoxes(data, b.off, func(b box, data []byte) error {
+				if b.typ == "meta" {
+					return parseMeta(data, b.off, info)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// parseMvhd reads the duration from the movie header
+func parseMvhd(data []byte, info *Info) {
+	if len(data) < 32 {
+		return
+	}
+	var timescale, duration uint64
+	if data[0] == 1 {
+		timescale = uint64(binary.BigEndian.Uint32(data[20:]))
+		duration = binary.BigEndian.Uint64(data[24:])
+	} else {
+		timescale = uint64(binary.BigEndian.Uint32(data[12:]))
+		duration = uint64(binary.BigEndian.Uint32(data[16:]))
+	}
+	if timescale != 0 && duration != 0xFFFFFFFF && duration != 1<<64-1 {
+		info.Duration = time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+	}
+}
+
+// parseTkhd reads the picture size from a track header
+func parseTkhd(data []byte, info *Info) {
+	off := 4 + 20
+	if len(data) > 0 && data[0] == 1 {
+		off = 4 + 32
+	}
+	off += 8 + 2 + 2 + 2 + 2 + 36
+	if len(data) < off+8 {
+		return
+	}
+	// 16.16 fixed point
+	width := int(binary.BigEndian.Uint32(data[off:]) >> 16)
+	height := int(binary.BigEndian.Uint32(data[off+4:]) >> 16)
+	if width > info.Width {
+		info.Width, info.Height = width, height
+	}
+}
+
+// parseMdia reads the sample rate of an audio track
+func parseMdia(data []byte, off int64, info *Info) error {
+	isSound := false
+	var sampleRate int
+	err := parseBoxes(data, off, func(b box, data []byte) error {
+		switch b.typ {
+		case "hdlr":
+			isSound = len(data) >= 12 && string(data[8:12]) == "soun"
+		case "mdhd":
+			if len(data) >= 24 {
+				if data[0] == 1 && len(data) >= 32 {
+					sampleRate = int(binary.BigEndian.Uint32(data[20:]))
+				} else {
+					sampleRate = int(binary.BigEndian.Uint32(data[12:]))
+				}
+			}
+		}
+		return nil
+	})
+	if isSound && info.SampleRate == 0 {
+		info.SampleRate = sampleRate
+	}
+	return err
+}
+
+// parseMeta reads the iTunes style tags
+func parseMeta(data []byte, off int64, info *Info) error {
+	// The meta box is usually a full box with 4 bytes of version
+	// and flags, but not always in QuickTime files
+	if len(data) >= 8 && string(data[4:8]) != "hdlr" {
+		data, off = data[4:], off+4
+	}
+	return parseBoxes(data, off, func(b box, data []byte) error {
+		if b.typ != "ilst" {
+			return nil
+		}
+		return parseBoxes(data, b.off, func(item box, data []byte) error {
+			return parseBoxes(data, item.off, func(b box, data []byte) error {
+				if b.typ != "data" || len(data) < 8 {
+					return nil
+				}
+				dataType := binary.BigEndian.Uint32(data) & 0xFFFFFF
+				value := data[8:]
+				switch item.typ {
+				case "\xa9nam":
+					info.Title = string(value)
+				case "\xa9ART":
+					info.Artist = string(value)
+				case "\xa9alb":
+					info.Album = string(value)
+				case "\xa9gen":
+					info.Genre = string(value)
+				case "covr":
+					if info.Cover == nil {
+						mimeType := "image/jpeg"
+						if dataType == 14 {
+							mimeType = "image/png"
+						}
+						info.Cover = &Part{MimeType: mimeType, Offset: b.off + 8, Length: int64(len(value))}
+					}
+				}
+				return nil
+			})
+		})
+	})
+}
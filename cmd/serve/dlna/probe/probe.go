@@ -0,0 +1,190 @@
+// Package probe reads the metadata of media files from their
+// container headers.
+//
+// Only the parts of the file with the headers are read, using
+// ReadAt, so it works efficiently on remote files with range
+// requests.
+package probe
+
+import (
+	"bytes"
+	"image"
+	"io"
+	"time"
+
+	// image formats for DecodeConfig
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownFormat is returned if the format of the file isn't
+// recognised.
+var ErrUnknownFormat = errors.New("probe: unknown format")
+
+// maximum amount of header to read in one go
+const maxHeader = 16 * 1024 * 1024
+
+// Info is the metadata read from a media file. Fields which aren't
+// known are left as their zero values.
+type Info struct {
+	Duration   time.Duration `json:"duration,omitempty"`
+	Width      int           `json:"width,omitempty"`
+	Height     int           `json:"height,omitempty"`
+	Bitrate    int64         `json:"bitrate,omitempty"` // average in bytes/s
+	SampleRate int           `json:"sampleRate,omitempty"`
+	Channels   int           `json:"channels,omitempty"`
+	Title      string        `json:"title,omitempty"`
+	Artist     string        `json:"artist,omitempty"`
+	Album      string        `json:"album,omitempty"`
+	Genre      string        `json:"genre,omitempty"`
+	Cover      *Part         `json:"cover,omitempty"`     // embedded cover art
+	Subtitles  []Subtitle    `json:"subtitles,omitempty"` // embedded text subtitle tracks
+}
+
+// Subtitle is an embedded subtitle track which can be read with
+// ReadSubtitles
+type Subtitle struct {
+	Track    int    `json:"track"`
+	Language string `json:"language,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Part is part of a file, such as embedded cover art
+type Part struct {
+	MimeType string `json:"mimeType"`
+	Offset   int64  `json:"offset"`
+	Length   int64  `json:"length"`
+}
+
+// Probe reads the metadata of the media file in r which is size
+// bytes long.
+//
+// It returns ErrUnknownFormat if the file isn't one it understands.
+func Probe(r io.ReaderAt, size int64) (info *Info, err error) {
+	br := newBlockReader(r, size)
+	magic, err := br.read(0, 12)
+	if err != nil {
+		return nil, err
+	}
+	info = &Info{}
+	switch {
+	case len(magic) >= 8 && string(magic[4:8]) == "ftyp":
+		err = probeMP4(br, info)
+	case bytes.HasPrefix(magic, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		err = probeMKV(br, info)
+	case bytes.HasPrefix(magic, []byte("fLaC")):
+		err = probeFLAC(br, info)
+	case bytes.HasPrefix(magic, []byte("ID3")) || isMP3Frame(magic):
+		err = probeMP3(br, info)
+	default:
+		err = probeImage(br, info)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.Bitrate == 0 && info.Duration > 0 {
+		info.Bitrate = int64(float64(size) / info.Duration.Seconds())
+	}
+	return info, nil
+}
+
+// probeImage reads the size of an image
+func probeImage(br *blockReader, info *Info) error {
+	config, _, err := image.DecodeConfig(io.NewSectionReader(br, 0, br.size))
+	if err != nil {
+		return ErrUnknownFormat
+	}
+	info.Width, info.Height = config.Width, config.Height
+	return nil
+}
+
+// size of the blocks the blockReader reads
+const blockSize = 64 * 1024
+
+// maximum number of blocks the blockReader keeps, so reading through
+// a whole file doesn't keep it all in memory
+const maxBlocks = 256
+
+// blockReader reads blocks of a file and caches them, so parsing lots
+// of small headers doesn't make lots of small reads.
+type blockReader struct {
+	r      io.ReaderAt
+	size   int64
+	blocks map[int64][]byte
+}
+
+// newBlockReader makes a blockReader reading r which is size long
+func newBlockReader(r io.ReaderAt, size int64) *blockReader {
+	return &blockReader{
+		r:      r,
+		size:   size,
+		blocks: map[int64][]byte{},
+	}
+}
+
+// block returns the block starting at off
+func (br *blockReader) block(off int64) ([]byte, error) {
+	if b, ok := br.blocks[off]; ok {
+		return b, nil
+	}
+	n := int64(blockSize)
+	if off+n > br.size {
+		n = br.size - off
+	}
+	b := make([]byte, n)
+	_, err := br.r.ReadAt(b, off)
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(br.blocks) >= maxBlocks {
+		br.blocks = map[int64][]byte{}
+	}
+	br.blocks[off] = b
+	return b, nil
+}
+
+// ReadAt reads len(p) bytes at off
+func (br *blockReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("probe: negative offset")
+	}
+	for n < len(p) {
+		if off >= br.size {
+			return n, io.EOF
+		}
+		start := off - off%blockSize
+		b, err := br.block(start)
+		if err != nil {
+			return n, err
+		}
+		copied := copy(p[n:], b[off-start:])
+		n += copied
+		off += int64(copied)
+	}
+	return n, nil
+}
+
+// read returns n bytes at off, or fewer if the file ends first
+func (br *blockReader) read(off, n int64) ([]byte, error) {
+	if off < 0 || off > br.size {
+		return nil, errors.New("probe: offset outside file")
+	}
+	if n > br.size-off {
+		n = br.size - off
+	}
+	if n > maxHeader {
+		return nil, errors.Errorf("probe: header too big (%d bytes)", n)
+	}
+	b := make([]byte, n)
+	n2, err := br.ReadAt(b, off)
+	if err == io.EOF && int64(n2) == n {
+		err = nil
+	}
+	return b, err
+}
@@ -0,0 +1,353 @@
+package probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cat joins byte slices
+func cat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func be32(x uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, x)
+	return b
+}
+
+func le32(x uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, x)
+	return b
+}
+
+// mp4Box makes an MP4 box
+func mp4Box(typ string, parts ...[]byte) []byte {
+	data := cat(parts...)
+	return cat(be32(uint32(len(data)+8)), []byte(typ), data)
+}
+
+var cover = []byte("\xff\xd8\xff\xe0not really a jpeg")
+
+func TestProbeMP4(t *testing.T) {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000) // timescale
+	binary.BigEndian.PutUint32(mvhd[16:], 5500) // duration
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], 640<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], 480<<16)
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint32(mdhd[12:], 44100)
+	hdlr := func(typ string) []byte {
+		return mp4Box("hdlr", make([]byte, 8), []byte(typ), make([]byte, 12))
+	}
+	tag := func(typ string, dataType uint32, value []byte) []byte {
+		return mp4Box(typ, mp4Box("data", be32(dataType), be32(0), value))
+	}
+	file := cat(
+		mp4Box("ftyp", []byte("isom\x00\x00\x02\x00isom")),
+		mp4Box("mdat", make([]byte, 1000)),
+		mp4Box("moov",
+			mp4Box("mvhd", mvhd),
+			mp4Box("trak", mp4Box("tkhd", tkhd), mp4Box("mdia", hdlr("vide"))),
+			mp4Box("trak", mp4Box("mdia", hdlr("soun"), mp4Box("mdhd", mdhd))),
+			mp4Box("udta", mp4Box("meta", be32(0), hdlr("mdir"), mp4Box("ilst",
+				tag("\xa9nam", 1, []byte("Title")),
+				tag("\xa9ART", 1, []byte("Artist")),
+				tag("\xa9alb", 1, []byte("Album")),
+				tag("\xa9gen", 1, []byte("Genre")),
+				tag("covr", 13, cover),
+			))),
+		),
+	)
+	info, err := Probe(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.NotNil(t, info.Cover)
+	assert.Equal(t, cover, file[info.Cover.Offset:info.Cover.Offset+info.Cover.Length])
+	info.Cover = nil
+	assert.Equal(t, &Info{
+		Duration:   5500 * time.Millisecond,
+		Width:      640,
+		Height:     480,
+		Bitrate:    int64(float64(len(file)) / 5.5),
+		SampleRate: 44100,
+		Title:      "Title",
+		Artist:     "Artist",
+		Album:      "Album",
+		Genre:      "Genre",
+	}, info)
+}
+
+// ebml makes an EBML element with an 8 byte size
+func ebml(id uint32, parts ...[]byte) []byte {
+	data := cat(parts...)
+	idBytes := be32(id)
+	for idBytes[0] == 0 {
+		idBytes = idBytes[1:]
+	}
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(data)))
+	size[0] = 0x01
+	return cat(idBytes, size, data)
+}
+
+func ebmlUint(id uint32, x uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, x)
+	return ebml(id, b)
+}
+
+func ebmlFloat(id uint32, x float64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(x))
+	return ebml(id, b)
+}
+
+func TestProbeMKV(t *testing.T) {
+	simpleTag := func(name, value string) []byte {
+		return ebml(idSimpleTag, ebml(idTagName, []byte(name)), ebml(idTagString, []byte(value)))
+	}
+	before := cat(
+		ebml(idInfo,
+			ebmlUint(idTimecode, 1000000),
+			ebmlFloat(idDuration, 2500),
+			ebml(idTitle, []byte("Title")),
+		),
+		ebml(idTracks,
+			ebml(idTrackEntry, ebml(idVideo, ebmlUint(idPixelWidth, 1920), ebmlUint(idPixelHeight, 1080))),
+			ebml(idTrackEntry, ebml(idAudio, ebmlFloat(idSampleRate, 48000), ebmlUint(idChannels, 6))),
+			ebml(idTrackEntry, ebmlUint(idTrackNumber, 3), ebml(idCodecID, []byte("S_TEXT/UTF8")), ebml(idLanguage, []byte("eng"))),
+			ebml(idTrackEntry, ebmlUint(idTrackNumber, 4), ebml(idCodecID, []byte("S_HDMV/PGS"))),
+		),
+		ebml(idCluster, make([]byte, 1000)),
+	)
+	// The Tags and Attachments are after the Cluster so must be
+	// found with the SeekHead
+	tags := ebml(idTags, ebml(idTag, simpleTag("ARTIST", "Artist"), simpleTag("genre", "Genre")))
+	attachments := ebml(idAttachments,
+		ebml(idAttachedFile, ebml(idFileName, []byte("notes.txt")), ebml(idFileMimeType, []byte("text/plain")), ebml(idFileData, []byte("notes"))),
+		ebml(idAttachedFile, ebml(idFileName, []byte("cover.jpg")), ebml(idFileMimeType, []byte("image/jpeg")), ebml(idFileData, cover)),
+	)
+	seekHead := func(offset uint64) []byte {
+		tagsOffset := offset + uint64(len(before))
+		return ebml(idSeekHead,
+			ebml(idSeek, ebml(idSeekID, be32(idTags)), ebmlUint(idSeekPosition, tagsOffset)),
+			ebml(idSeek, ebml(idSeekID, be32(idAttachments)), ebmlUint(idSeekPosition, tagsOffset+uint64(len(tags)))),
+		)
+	}
+	// The SeekHead positions are relative to the segment data which
+	// starts with the SeekHead itself
+	head := seekHead(0)
+	head = seekHead(uint64(len(head)))
+	file := cat(
+		ebml(0x1A45DFA3, ebml(0x4282, []byte("matroska"))),
+		ebml(idSegment, head, before, tags, attachments),
+	)
+	info, err := Probe(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.NotNil(t, info.Cover)
+	assert.Equal(t, "image/jpeg", info.Cover.MimeType)
+	assert.Equal(t, cover, file[info.Cover.Offset:info.Cover.Offset+info.Cover.Length])
+	info.Cover = nil
+	assert.Equal(t, &Info{
+		Duration:   2500 * time.Millisecond,
+		Width:      1920,
+		Height:     1080,
+		Bitrate:    int64(float64(len(file)) / 2.5),
+		SampleRate: 48000,
+		Channels:   6,
+		Title:      "Title",
+		Artist:     "Artist",
+		Genre:      "Genre",
+		Subtitles:  []Subtitle{{Track: 3, Language: "eng"}},
+	}, info)
+}
+
+func TestReadSubtitles(t *testing.T) {
+	// block makes a block in track at time relative to the cluster
+	block := func(id uint32, track byte, relative int16, text string) []byte {
+		return ebml(id, []byte{0x80 | track, byte(uint16(relative) >> 8), byte(relative), 0}, []byte(text))
+	}
+	cluster := func(time uint64, parts ...[]byte) []byte {
+		return ebml(idCluster, append([][]byte{ebmlUint(idClusterTime, time)}, parts...)...)
+	}
+	file := cat(
+		ebml(0x1A45DFA3, ebml(0x4282, []byte("matroska"))),
+		ebml(idSegment,
+			ebml(idInfo, ebmlUint(idTimecode, 1000000)),
+			cluster(1000,
+				block(idSimpleBlock, 1, 0, "video"),
+				ebml(idBlockGroup, block(idBlock, 3, 500, "Hello\r\nthere"), ebmlUint(idBlockDur, 1500)),
+				block(idSimpleBlock, 3, 3000, "No duration"),
+			),
+			cluster(3600000,
+				block(idSimpleBlock, 3, 1, "Last"),
+				block(idSimpleBlock, 2, 0, "audio"),
+			),
+		),
+	)
+	srt, err := ReadSubtitles(bytes.NewReader(file), int64(len(file)), 3)
+	require.NoError(t, err)
+	assert.Equal(t, `1
+00:00:01,500 --> 00:00:03,000
+Hello
+there
+
+2
+00:00:04,000 --> 00:00:09,000
+No duration
+
+3
+01:00:00,001 --> 01:00:05,001
+Last
+
+`, string(srt))
+
+	srt, err = ReadSubtitles(bytes.NewReader(file), int64(len(file)), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "", string(srt))
+
+	_, err = ReadSubtitles(bytes.NewReader(cover), int64(len(cover)), 3)
+	assert.Equal(t, ErrUnknownFormat, err)
+}
+
+func TestReadVint(t *testing.T) {
+	for _, test := range []struct {
+		in         []byte
+		keepMarker bool
+		want       int64
+		wantN      int
+	}{
+		{[]byte{0x81}, false, 1, 1},
+		{[]byte{0x81}, true, 0x81, 1},
+		{[]byte{0x40, 0x02}, false, 2, 2},
+		{[]byte{0x1A, 0x45, 0xDF, 0xA3}, true, 0x1A45DFA3, 4},
+		{[]byte{0xFF}, false, unknownSize, 1},
+		{[]byte{0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, false, unknownSize, 8},
+	} {
+		got, n, err := readVint(test.in, test.keepMarker)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.wantN, n, test.in)
+	}
+	_, _, err := readVint([]byte{0x00}, false)
+	assert.Error(t, err)
+	_, _, err = readVint([]byte{0x40}, false)
+	assert.Error(t, err)
+}
+
+// id3Frame makes an ID3v2.3 frame
+func id3Frame(id string, parts ...[]byte) []byte {
+	data := cat(parts...)
+	return cat([]byte(id), be32(uint32(len(data))), []byte{0, 0}, data)
+}
+
+// id3Tag makes an ID3v2.3 tag
+func id3Tag(frames ...[]byte) []byte {
+	data := cat(frames...)
+	n := len(data)
+	return cat([]byte("ID3\x03\x00\x00"), []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}, data)
+}
+
+func TestProbeMP3(t *testing.T) {
+	tag := id3Tag(
+		id3Frame("TIT2", []byte("\x00Caf\xe9")),
+		id3Frame("TPE1", []byte("\x01\xff\xfeA\x00r\x00t\x00")),
+		id3Frame("TCON", []byte("\x00(17)")),
+		id3Frame("APIC", []byte("\x00image/jpeg\x00\x03desc\x00"), cover),
+	)
+	// MPEG 1 layer III, 128 kbit/s, 44100 Hz, stereo
+	header := []byte{0xFF, 0xFB, 0x90, 0x00}
+	audio := make([]byte, 16000*2)
+	copy(audio, header)
+	file := cat(tag, audio)
+	info, err := Probe(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.NotNil(t, info.Cover)
+	assert.Equal(t, cover, file[info.Cover.Offset:info.Cover.Offset+info.Cover.Length])
+	info.Cover = nil
+	assert.Equal(t, &Info{
+		Duration:   2 * time.Second,
+		Bitrate:    16000,
+		SampleRate: 44100,
+		Channels:   2,
+		Title:      "Café",
+		Artist:     "Art",
+		Genre:      "Rock",
+	}, info)
+
+	// With a Xing header and an ID3v1 tag
+	copy(audio[4+32:], cat([]byte("Xing"), be32(1), be32(100)))
+	v1 := make([]byte, 128)
+	copy(v1, "TAG")
+	copy(v1[3:], "V1 Title")
+	copy(v1[63:], "V1 Album")
+	file = cat(audio, v1)
+	info, err = Probe(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	assert.Equal(t, 2612244897*time.Nanosecond, info.Duration)
+	assert.Equal(t, "V1 Title", info.Title)
+	assert.Equal(t, "V1 Album", info.Album)
+	assert.Equal(t, "Blues", info.Genre)
+}
+
+func TestProbeFLAC(t *testing.T) {
+	// 44100 Hz, 2 channels, 16 bits, 441000 samples
+	streamInfo := make([]byte, 34)
+	x := uint64(44100)<<44 | uint64(1)<<41 | uint64(15)<<36 | 441000
+	binary.BigEndian.PutUint64(streamInfo[10:], x)
+	block := func(typ byte, last bool, data []byte) []byte {
+		if last {
+			typ |= 0x80
+		}
+		n := len(data)
+		return cat([]byte{typ, byte(n >> 16), byte(n >> 8), byte(n)}, data)
+	}
+	comment := func(s string) []byte {
+		return cat(le32(uint32(len(s))), []byte(s))
+	}
+	str := func(s string) []byte {
+		return cat(be32(uint32(len(s))), []byte(s))
+	}
+	file := cat(
+		[]byte("fLaC"),
+		block(flacStreamInfo, false, streamInfo),
+		block(flacVorbisComment, false, cat(comment("vendor"), le32(3), comment("TITLE=Title"), comment("Album=Album"), comment("nonsense"))),
+		block(flacPicture, true, cat(be32(3), str("image/png"), str("front"), make([]byte, 16), be32(uint32(len(cover))), cover)),
+		make([]byte, 1000),
+	)
+	info, err := Probe(bytes.NewReader(file), int64(len(file)))
+	require.NoError(t, err)
+	require.NotNil(t, info.Cover)
+	assert.Equal(t, "image/png", info.Cover.MimeType)
+	assert.Equal(t, cover, file[info.Cover.Offset:info.Cover.Offset+info.Cover.Length])
+	info.Cover = nil
+	assert.Equal(t, &Info{
+		Duration:   10 * time.Second,
+		Bitrate:    int64(len(file)) / 10,
+		SampleRate: 44100,
+		Channels:   2,
+		Title:      "Title",
+		Album:      "Album",
+	}, info)
+}
+
+func TestProbeImage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 30, 20))))
+	info, err := Probe(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, &Info{Width: 30, Height: 20}, info)
+
+	_, err = Probe(bytes.NewReader([]byte("hello world")), 11)
+	assert.Equal(t, ErrUnknownFormat, err)
+}
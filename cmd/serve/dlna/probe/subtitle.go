@@ -0,0 +1,234 @@
+package probe
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The text of the subtitles is in the Blocks of the Clusters so
+// unlike the rest of the metadata the whole file needs reading to
+// find it.
+
+const (
+	// largest amount of subtitle text read from a file
+	maxSubtitles = 16 * 1024 * 1024
+	// how long a subtitle without a duration is shown for at most
+	maxCueDuration = 5 * time.Second
+)
+
+// cue is one subtitle
+type cue struct {
+	start    time.Duration
+	duration time.Duration
+	text     string
+}
+
+// ReadSubtitles reads the text subtitle track given from the Matroska
+// file in r which is size bytes long and returns it in SRT format.
+//
+// Unlike Probe this reads through the whole file.
+func ReadSubtitles(r io.ReaderAt, size int64, track int) ([]byte, error) {
+	br := newBlockReader(r, size)
+	header, err := readElement(br, 0)
+	if err != nil {
+		return nil, err
+	}
+	if header.id != 0x1A45DFA3 {
+		return nil, ErrUnknownFormat
+	}
+	segment, err := readElement(br, header.off+header.size)
+	if err != nil {
+		return nil, err
+	}
+	if segment.id != idSegment {
+		return nil, errors.New("mkv: no segment")
+	}
+	end := br.size
+	if segment.size != unknownSize && segment.off+segment.size < end {
+		end = segment.off + segment.size
+	}
+	s := &subtitleReader{
+		br:    br,
+		track: int64(track),
+		scale: 1000000,
+	}
+	for off := segment.off; off < end; {
+		e, err := readElement(br, off)
+		if err != nil {
+			return nil, err
+		}
+		switch e.id {
+		case idInfo:
+			err = children(br, e.off, e.off+e.size, func(e element) error {
+				if e.id != idTimecode {
+					return nil
+				}
+				scale, err := e.uint(br)
+				if scale != 0 {
+					s.scale = float64(scale)
+				}
+				return err
+			})
+		case idCluster:
+			clusterEnd := end
+			if e.size != unknownSize {
+				clusterEnd = e.off + e.size
+			}
+			off, err = s.cluster(e.off, clusterEnd)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if e.size == unknownSize {
+			break
+		}
+		off = e.off + e.size
+	}
+	return s.srt(), nil
+}
+
+// subtitleReader holds the state of reading a subtitle track
+type subtitleReader struct {
+	br    *blockReader
+	track int64
+	scale float64 // timecode scale
+	time  int64   // timecode of the current cluster
+	total int     // bytes of text read
+	cues  []cue
+}
+
+// cluster reads the subtitles from the cluster with data from start
+// to end, returning where the cluster finished.
+//
+// Clusters may have an unknown size in which case they end at the
+// next top level element, which are the only ones with 4 byte IDs.
+func (s *subtitleReader) cluster(start, end int64) (off int64, err error) {
+	s.time = 0
+	for off = start; off < end; {
+		e, err := readElement(s.br, off)
+		if err != nil {
+			return off, err
+		}
+		if e.id > 0xFFFFFF {
+			return off, nil
+		}
+		if e.size == unknownSize {
+			return off, errors.New("mkv: unknown size inside cluster")
+		}
+		switch e.id {
+		case idClusterTime:
+			t, err := e.uint(s.br)
+			if err != nil {
+				return off, err
+			}
+			s.time = int64(t)
+		case idSimpleBlock:
+			err = s.block(e, 0)
+		case idBlockGroup:
+			var block element
+			var duration uint64
+			err = children(s.br, e.off, e.off+e.size, func(e element) (err error) {
+				switch e.id {
+				case idBlock:
+					block = e
+				case idBlockDur:
+					duration, err = e.uint(s.br)
+				}
+				return err
+			})
+			if err == nil && block.id == idBlock {
+				err = s.block(block, int64(duration))
+			}
+		}
+		if err != nil {
+			return off, err
+		}
+		off = e.off + e.size
+	}
+	return end, nil
+}
+
+// block reads the subtitle from the block e if it is in the track
+func (s *subtitleReader) block(e element, duration int64) error {
+	n := e.size
+	if n > 12 {
+		n = 12
+	}
+	header, err := s.br.read(e.off, n)
+	if err != nil {
+		return err
+	}
+	track, i, err := readVint(header, false)
+	if err != nil || track != s.track {
+		return err
+	}
+	if len(header) < i+3 {
+		return errors.New("mkv: short block")
+	}
+	relative := int64(int16(uint16(header[i])<<8 | uint16(header[i+1])))
+	if header[i+2]&0x06 != 0 {
+		// laced blocks aren't used for subtitles
+		return nil
+	}
+	dataOff := e.off + int64(i+3)
+	length := e.size - int64(i+3)
+	s.total += int(length)
+	if s.total > maxSubtitles {
+		return errors.Errorf("mkv: more than %d bytes of subtitles", maxSubtitles)
+	}
+	text, err := s.br.read(dataOff, length)
+	if err != nil {
+		return err
+	}
+	s.cues = append(s.cues, cue{
+		start:    time.Duration(float64(s.time+relative) * s.scale),
+		duration: time.Duration(float64(duration) * s.scale),
+		text:     strings.TrimSpace(strings.Replace(string(text), "\r\n", "\n", -1)),
+	})
+	return nil
+}
+
+// srt returns the subtitles read in SRT format
+func (s *subtitleReader) srt() []byte {
+	sort.SliceStable(s.cues, func(i, j int) bool {
+		return s.cues[i].start < s.cues[j].start
+	})
+	var out bytes.Buffer
+	n := 0
+	for i, c := range s.cues {
+		if c.text == "" {
+			continue
+		}
+		if c.duration <= 0 {
+			// Show it until the next one up to the maximum
+			c.duration = maxCueDuration
+			if i+1 < len(s.cues) {
+				if gap := s.cues[i+1].start - c.start; gap > 0 && gap < c.duration {
+					c.duration = gap
+				}
+			}
+		}
+		n++
+		_, _ = fmt.Fprintf(&out, "%d\n%s --> %s\n%s\n\n", n, srtTime(c.start), srtTime(c.start+c.duration), c.text)
+	}
+	return out.Bytes()
+}
+
+// srtTime formats t as an SRT time stamp
+func srtTime(t time.Duration) string {
+	if t < 0 {
+		t = 0
+	}
+	ms := t.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
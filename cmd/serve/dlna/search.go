@@ -0,0 +1,480 @@
+package dlna
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/anacrolix/dms/upnp"
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/dlna/upnpav"
+	"github.com/rclone/rclone/fs"
+)
+
+// The properties which can be used in search criteria.
+const searchCapabilities = "@id,@parentID,upnp:class,dc:title,dc:creator,upnp:artist,upnp:album,upnp:genre,dc:date,res,res@size,res@duration,res@resolution,res@bitrate"
+
+type search struct {
+	ContainerID    string
+	SearchCriteria string
+	Filter         string
+	StartingIndex  int
+	RequestedCount int
+	SortCriteria   string
+}
+
+// Runs a ContentDirectory Search against the index of media files,
+// returning the requested page of matching items and the total number
+// of matches.
+//
+// Only files in the index are found - these are the ones which have
+// been browsed or found by --scan.
+func (cds *contentDirectoryService) search(args search, host string) (ret []interface{}, totalMatches int, err error) {
+	container, err := cds.objectFromID(args.ContainerID)
+	if err != nil {
+		return nil, 0, upnp.Errorf(upnpav.NoSuchContainerErrorCode, err.Error())
+	}
+	node, err := cds.vfs.Stat(container.Path)
+	if err != nil || !node.IsDir() {
+		return nil, 0, upnp.Errorf(upnpav.NoSuchContainerErrorCode, "no such container %q", container.Path)
+	}
+	criteria, err := parseSearchCriteria(args.SearchCriteria)
+	if err != nil {
+		return nil, 0, upnp.Errorf(upnpav.InvalidSearchCriteriaErrorCode, err.Error())
+	}
+	sortKeys, err := parseSortCriteria(args.SortCriteria)
+	if err != nil {
+		return nil, 0, upnp.Errorf(upnpav.InvalidSortCriteriaErrorCode, err.Error())
+	}
+
+	paths := cds.metadata.paths(container.Path)
+	sort.Strings(paths)
+	var items []upnpav.Item
+	for _, remote := range paths {
+		node, err := cds.vfs.Stat(remote)
+		if err != nil {
+			fs.Debugf(remote, "Skipping search result: %v", err)
+			continue
+		}
+		obj, err := cds.cdsObjectToUpnpavObject(object{remote}, node, cds.mediaResources(remote), host)
+		if err != nil {
+			fs.Errorf(cds, "error with %s: %s", remote, err)
+			continue
+		}
+		item, ok := obj.(upnpav.Item)
+		if ok && criteria.match(&item) {
+			items = append(items, item)
+		}
+	}
+	sortItems(items, sortKeys)
+
+	totalMatches = len(items)
+	low, high := page(len(items), args.StartingIndex, args.RequestedCount)
+	for _, item := range items[low:high] {
+		ret = append(ret, item)
+	}
+	return ret, totalMatches, nil
+}
+
+// page returns the range of the n results to return for a request
+// starting at start for count results, where a count of 0 means all
+// of them. Out of range values from the client are clamped.
+func page(n, start, count int) (low, high int) {
+	low = start
+	if low < 0 {
+		low = 0
+	} else if low > n {
+		low = n
+	}
+	high = n
+	if count > 0 && count < high-low {
+		high = low + count
+	}
+	return low, high
+}
+
+// itemProperty returns the value of the property called name of item
+// and whether it has it.
+func itemProperty(item *upnpav.Item, name string) (string, bool) {
+	var res *upnpav.Resource
+	if len(item.Res) > 0 {
+		res = &item.Res[0]
+	}
+	value := ""
+	switch strings.ToLower(name) {
+	case "@id":
+		value = item.ID
+	case "@parentid":
+		value = item.ParentID
+	case "upnp:class":
+		value = item.Class
+	case "dc:title":
+		value = item.Title
+	case "dc:creator":
+		value = item.Creator
+	case "upnp:artist":
+		value = item.Artist
+	case "upnp:album":
+		value = item.Album
+	case "upnp:genre":
+		value = item.Genre
+	case "dc:date":
+		value = item.Date.Format("2006-01-02")
+	case "res":
+		if res != nil {
+			value = res.URL
+		}
+	case "res@size":
+		if res != nil {
+			value = strconv.FormatUint(res.Size, 10)
+		}
+	case "res@duration":
+		if res != nil {
+			value = res.Duration
+		}
+	case "res@resolution":
+		if res != nil {
+			value = res.Resolution
+		}
+	case "res@bitrate":
+		if res != nil && res.Bitrate != 0 {
+			value = strconv.FormatUint(uint64(res.Bitrate), 10)
+		}
+	}
+	return value, value != ""
+}
+
+// compareProperty compares the property called name with value a with
+// b, returning -1, 0 or 1. Numbers and durations are compared by
+// value and everything else as case insensitive strings.
+func compareProperty(name, a, b string) int {
+	if strings.EqualFold(name, "res@duration") {
+		da, errA := parseDuration(a)
+		db, errB := parseDuration(b)
+		if errA == nil && errB == nil {
+			return compareFloats(float64(da), float64(db))
+		}
+	}
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return compareFloats(fa, fb)
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// searchExpr is a parsed search criteria expression
+type searchExpr interface {
+	match(item *upnpav.Item) bool
+}
+
+// searchAll matches everything
+type searchAll struct{}
+
+func (searchAll) match(item *upnpav.Item) bool { return true }
+
+// searchAnd matches if all of its expressions do
+type searchAnd []searchExpr
+
+func (e searchAnd) match(item *upnpav.Item) bool {
+	for _, expr := range e {
+		if !expr.match(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchOr matches if any of its expressions do
+type searchOr []searchExpr
+
+func (e searchOr) match(item *upnpav.Item) bool {
+	for _, expr := range e {
+		if expr.match(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchRel compares a property with a value
+type searchRel struct {
+	property string
+	op       string
+	value    string
+}
+
+func (e searchRel) match(item *upnpav.Item) bool {
+	value, ok := itemProperty(item, e.property)
+	switch e.op {
+	case "exists":
+		return ok == (e.value == "true")
+	case "contains":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(e.value))
+	case "doesnotcontain":
+		return !strings.Contains(strings.ToLower(value), strings.ToLower(e.value))
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(e.value))
+	case "derivedfrom":
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(e.value))
+	}
+	c := compareProperty(e.property, value, e.value)
+	switch e.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return ok && c < 0
+	case "<=":
+		return ok && c <= 0
+	case ">":
+		return ok && c > 0
+	case ">=":
+		return ok && c >= 0
+	}
+	return false
+}
+
+// searchOps are the operators allowed in a relational expression
+var searchOps = map[string]bool{
+	"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"contains": true, "doesnotcontain": true, "derivedfrom": true,
+	"startswith": true, "exists": true,
+}
+
+// searchParser parses UPnP ContentDirectory search criteria
+type searchParser struct {
+	tokens []string
+	quoted []bool // whether each token was a quoted string
+}
+
+// parseSearchCriteria parses the search criteria in s which is either
+// "*" or an expression like
+//
+//	upnp:class derivedfrom "object.item.audioItem" and (dc:title contains "love" or upnp:artist = "Queen")
+func parseSearchCriteria(s string) (searchExpr, error) {
+	s = strings.TrimSpace(s)
+	if s == "*" || s == "" {
+		return searchAll{}, nil
+	}
+	p := &searchParser{}
+	err := p.tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if len(p.tokens) > 0 {
+		return nil, errors.Errorf("unexpected %q", p.tokens[0])
+	}
+	return expr, nil
+}
+
+// tokenize splits s into tokens
+func (p *searchParser) tokenize(s string) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			p.add(s[i:i+1], false)
+			i++
+		case c == '"':
+			var value strings.Builder
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return errors.New("unterminated string")
+			}
+			p.add(value.String(), true)
+			i++
+		case strings.IndexByte("=!<>", c) >= 0:
+			j := i + 1
+			for j < len(s) && strings.IndexByte("=!<>", s[j]) >= 0 {
+				j++
+			}
+			p.add(s[i:j], false)
+			i = j
+		default:
+			j := i
+			for j < len(s) && !unicode.IsSpace(rune(s[j])) && strings.IndexByte("()\"=!<>", s[j]) < 0 {
+				j++
+			}
+			p.add(s[i:j], false)
+			i = j
+		}
+	}
+	return nil
+}
+
+// add adds a token
+func (p *searchParser) add(token string, quoted bool) {
+	p.tokens = append(p.tokens, token)
+	p.quoted = append(p.quoted, quoted)
+}
+
+// peek returns whether the next token is the keyword or symbol want
+func (p *searchParser) peek(want string) bool {
+	return len(p.tokens) > 0 && !p.quoted[0] && strings.EqualFold(p.tokens[0], want)
+}
+
+// next returns the next token
+func (p *searchParser) next() (token string, quoted bool, err error) {
+	if len(p.tokens) == 0 {
+		return "", false, errors.New("unexpected end of search criteria")
+	}
+	token, quoted = p.tokens[0], p.quoted[0]
+	p.tokens, p.quoted = p.tokens[1:], p.quoted[1:]
+	return token, quoted, nil
+}
+
+// parseOr parses expressions joined by "or"
+func (p *searchParser) parseOr() (searchExpr, error) {
+	var or searchOr
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, expr)
+		if !p.peek("or") {
+			break
+		}
+		_, _, _ = p.next()
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+// parseAnd parses expressions joined by "and"
+func (p *searchParser) parseAnd() (searchExpr, error) {
+	var and searchAnd
+	for {
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, expr)
+		if !p.peek("and") {
+			break
+		}
+		_, _, _ = p.next()
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+// parsePrimary parses a relational expression or one in brackets
+func (p *searchParser) parsePrimary() (searchExpr, error) {
+	if p.peek("(") {
+		_, _, _ = p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, errors.New("missing )")
+		}
+		_, _, _ = p.next()
+		return expr, nil
+	}
+	property, quoted, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if quoted || property == ")" || property == "(" {
+		return nil, errors.Errorf("expecting property but found %q", property)
+	}
+	op, quoted, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op = strings.ToLower(op)
+	if quoted || !searchOps[op] {
+		return nil, errors.Errorf("unknown operator %q", op)
+	}
+	value, quoted, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op == "exists" {
+		value = strings.ToLower(value)
+		if quoted || (value != "true" && value != "false") {
+			return nil, errors.Errorf("exists needs true or false not %q", value)
+		}
+	} else if !quoted {
+		return nil, errors.Errorf("expecting quoted value but found %q", value)
+	}
+	return searchRel{property: property, op: op, value: value}, nil
+}
+
+// sortKey is a property to sort by
+type sortKey struct {
+	property   string
+	descending bool
+}
+
+// parseSortCriteria parses sort criteria like "+upnp:artist,-dc:date"
+func parseSortCriteria(s string) (keys []sortKey, err error) {
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key := sortKey{property: field[1:]}
+		switch field[0] {
+		case '+':
+		case '-':
+			key.descending = true
+		default:
+			return nil, errors.Errorf("sort property %q must start with + or -", field)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortItems sorts items by keys, keeping them in path order otherwise
+func sortItems(items []upnpav.Item, keys []sortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range keys {
+			a, _ := itemProperty(&items[i], key.property)
+			b, _ := itemProperty(&items[j], key.property)
+			c := compareProperty(key.property, a, b)
+			if key.descending {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,119 @@
+package dlna
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/cmd/serve/dlna/upnpav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCriteria(t *testing.T) {
+	item := &upnpav.Item{
+		Object: upnpav.Object{
+			ID:     "%2Fmusic%2Fsong.mp3",
+			Class:  "object.item.audioItem",
+			Title:  "Bohemian Rhapsody",
+			Artist: "Queen",
+			Date:   upnpav.Timestamp{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		Res: []upnpav.Resource{{
+			Size:     5000000,
+			Duration: "0:05:55.000",
+		}},
+	}
+	for _, test := range []struct {
+		criteria string
+		want     bool
+	}{
+		{`*`, true},
+		{`upnp:class derivedfrom "object.item.audioItem"`, true},
+		{`upnp:class derivedfrom "object.item.videoItem"`, false},
+		{`upnp:class = "object.item.audioItem"`, true},
+		{`dc:title contains "rhapsody"`, true},
+		{`dc:title doesNotContain "rhapsody"`, false},
+		{`upnp:artist = "queen"`, true},
+		{`upnp:artist != "queen"`, false},
+		{`upnp:album exists true`, false},
+		{`upnp:album exists false`, true},
+		{`upnp:artist exists true`, true},
+		{`res@size > "4000000"`, true},
+		{`res@size>="5000001"`, false},
+		{`res@duration < "0:10:00"`, true},
+		{`res@duration <= "0:05:00.000"`, false},
+		{`dc:date >= "2020-01-01"`, true},
+		{`dc:title = "a \"quoted\" title"`, false},
+		{`upnp:class derivedfrom "object.item.videoItem" or upnp:artist = "Queen"`, true},
+		{`upnp:class derivedfrom "object.item.videoItem" or upnp:artist = "Queen" and dc:title contains "x"`, false},
+		{`(upnp:class derivedfrom "object.item.videoItem" or upnp:artist = "Queen") and dc:title contains "Bo"`, true},
+		{`upnp:class derivedfrom "object.item" AND (dc:title contains "x" OR dc:creator exists false)`, true},
+	} {
+		expr, err := parseSearchCriteria(test.criteria)
+		require.NoError(t, err, test.criteria)
+		assert.Equal(t, test.want, expr.match(item), test.criteria)
+	}
+
+	for _, criteria := range []string{
+		`dc:title`,
+		`dc:title =`,
+		`dc:title = unquoted`,
+		`dc:title likes "x"`,
+		`dc:title = "unterminated`,
+		`(dc:title = "x"`,
+		`dc:title = "x")`,
+		`dc:title exists maybe`,
+		`dc:title = "x" and`,
+	} {
+		_, err := parseSearchCriteria(criteria)
+		assert.Error(t, err, criteria)
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	items := []upnpav.Item{
+		{Object: upnpav.Object{Title: "b", Artist: "x"}},
+		{Object: upnpav.Object{Title: "a", Artist: "y"}},
+		{Object: upnpav.Object{Title: "c", Artist: "x"}},
+	}
+	keys, err := parseSortCriteria("+upnp:artist,-dc:title")
+	require.NoError(t, err)
+	sortItems(items, keys)
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, titles)
+
+	_, err = parseSortCriteria("dc:title")
+	assert.Error(t, err)
+}
+
+func TestDuration(t *testing.T) {
+	d := time.Hour + 2*time.Minute + 3*time.Second + 450*time.Millisecond
+	assert.Equal(t, "1:02:03.450", formatDuration(d))
+	got, err := parseDuration("1:02:03.450")
+	require.NoError(t, err)
+	assert.Equal(t, d, got)
+	_, err = parseDuration("3.450")
+	assert.Error(t, err)
+}
+
+func TestPage(t *testing.T) {
+	for _, test := range []struct {
+		n, start, count int
+		low, high       int
+	}{
+		{10, 0, 0, 0, 10},
+		{10, 2, 3, 2, 5},
+		{10, 8, 5, 8, 10},
+		{10, 12, 5, 10, 10},
+		{10, -5, 3, 0, 3},
+		{10, 2, -1, 2, 10},
+		{0, 0, 0, 0, 0},
+	} {
+		low, high := page(test.n, test.start, test.count)
+		assert.Equal(t, test.low, low, "%+v", test)
+		assert.Equal(t, test.high, high, "%+v", test)
+	}
+}
@@ -8,6 +8,12 @@ import (
 const (
 	// NoSuchObjectErrorCode : The specified ObjectID is invalid.
 	NoSuchObjectErrorCode = 701
+	// InvalidSearchCriteriaErrorCode : The search criteria specified is not supported or is invalid.
+	InvalidSearchCriteriaErrorCode = 708
+	// InvalidSortCriteriaErrorCode : The sort criteria specified is not supported or is invalid.
+	InvalidSortCriteriaErrorCode = 709
+	// NoSuchContainerErrorCode : The specified ContainerID is invalid or identifies an object that is not a container.
+	NoSuchContainerErrorCode = 710
 )
 
 // Resource description
@@ -19,6 +25,8 @@ type Resource struct {
 	Bitrate      uint     `xml:"bitrate,attr,omitempty"`
 	Duration     string   `xml:"duration,attr,omitempty"`
 	Resolution   string   `xml:"resolution,attr,omitempty"`
+	SampleRate   int      `xml:"sampleFrequency,attr,omitempty"`
+	Channels     int      `xml:"nrAudioChannels,attr,omitempty"`
 }
 
 // Container description
@@ -45,6 +53,7 @@ type Object struct {
 	Icon        string    `xml:"upnp:icon,omitempty"`
 	Title       string    `xml:"dc:title"`
 	Date        Timestamp `xml:"dc:date"`
+	Creator     string    `xml:"dc:creator,omitempty"`
 	Artist      string    `xml:"upnp:artist,omitempty"`
 	Album       string    `xml:"upnp:album,omitempty"`
 	Genre       string    `xml:"upnp:genre,omitempty"`
@@ -0,0 +1,252 @@
+//go:build !plan9
+// +build !plan9
+
+package ftp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	ftp "goftp.io/server/v2"
+)
+
+// commands returns the FTP commands the server understands - the
+// library's defaults with rclone's extensions added
+func commands(s *server) map[string]ftp.Command {
+	defaults := ftp.DefaultCommands()
+	cmds := make(map[string]ftp.Command, len(defaults)+3)
+	for name, cmd := range defaults {
+		cmds[name] = cmd
+	}
+	cmds["HASH"] = commandHash{s: s}
+	cmds["HOST"] = commandHost{}
+	cmds["MFMT"] = commandMfmt{}
+	cmds["OPTS"] = commandOpts{next: defaults["OPTS"]}
+	return cmds
+}
+
+// hashNames are the names of the hashes HASH supports, in order of
+// preference
+var hashNames = []struct {
+	ht   hash.Type
+	name string
+}{
+	{hash.SHA256, "SHA-256"},
+	{hash.SHA1, "SHA-1"},
+	{hash.MD5, "MD5"},
+	{hash.CRC32, "CRC32"},
+}
+
+// defaultHashType returns the preferred hash out of hashes
+func defaultHashType(hashes hash.Set) hash.Type {
+	for _, h := range hashNames {
+		if hashes.Contains(h.ht) {
+			return h.ht
+		}
+	}
+	return hash.None
+}
+
+// hashName returns the name HASH uses for ht
+func hashName(ht hash.Type) string {
+	for _, h := range hashNames {
+		if h.ht == ht {
+			return h.name
+		}
+	}
+	return ""
+}
+
+// parseHashName returns the hash called name
+func parseHashName(name string) (hash.Type, bool) {
+	for _, h := range hashNames {
+		if strings.EqualFold(h.name, name) {
+			return h.ht, true
+		}
+	}
+	return hash.None, false
+}
+
+// sessionOf returns the state of the logged in client or nil
+func sessionOf(sess *ftp.Session) *session {
+	state, _ := sess.Data[sessionKey].(*session)
+	return state
+}
+
+// commandOpts responds to the OPTS command
+//
+// It handles OPTS HASH to select the hash HASH uses and passes
+// everything else on to the library's OPTS.
+type commandOpts struct {
+	next ftp.Command
+}
+
+func (cmd commandOpts) IsExtend() bool {
+	return cmd.next.IsExtend()
+}
+
+func (cmd commandOpts) RequireParam() bool {
+	return cmd.next.RequireParam()
+}
+
+func (cmd commandOpts) RequireAuth() bool {
+	return cmd.next.RequireAuth()
+}
+
+func (cmd commandOpts) Execute(sess *ftp.Session, param string) {
+	parts := strings.Fields(param)
+	if len(parts) == 0 || !strings.EqualFold(parts[0], "HASH") {
+		cmd.next.Execute(sess, param)
+		return
+	}
+	state := sessionOf(sess)
+	if state == nil {
+		sess.WriteMessage(530, "Not logged in")
+		return
+	}
+	if len(parts) > 1 {
+		ht, ok := parseHashName(parts[1])
+		if !ok || !state.vfs.Fs().Hashes().Contains(ht) {
+			sess.WriteMessage(501, "Unknown algorithm, current selection not changed")
+			return
+		}
+		state.hashType = ht
+	}
+	if state.hashType == hash.None {
+		sess.WriteMessage(504, "No hashes are supported")
+		return
+	}
+	sess.WriteMessage(200, hashName(state.hashType))
+}
+
+// commandHash responds to the HASH command
+//
+// It returns the hash of a file chosen with OPTS HASH, as described
+// in draft-bryan-ftpext-hash.
+type commandHash struct {
+	s *server
+}
+
+func (cmd commandHash) IsExtend() bool {
+	return true
+}
+
+func (cmd commandHash) RequireParam() bool {
+	return true
+}
+
+func (cmd commandHash) RequireAuth() bool {
+	return true
+}
+
+func (cmd commandHash) Execute(sess *ftp.Session, param string) {
+	state := sessionOf(sess)
+	if state == nil {
+		sess.WriteMessage(530, "Not logged in")
+		return
+	}
+	if state.hashType == hash.None {
+		sess.WriteMessage(504, "No hashes are supported")
+		return
+	}
+	path := sess.BuildPath(param)
+	node, err := state.vfs.Stat(path)
+	if err != nil {
+		sess.WriteMessage(550, "File not found")
+		return
+	}
+	if !node.IsFile() {
+		sess.WriteMessage(553, "Not a file")
+		return
+	}
+	err = state.vfs.Check(path, acl.PermRead)
+	if err != nil {
+		sess.WriteMessage(550, err.Error())
+		return
+	}
+	o, ok := node.DirEntry().(fs.ObjectInfo)
+	if !ok {
+		sess.WriteMessage(450, "File not available yet")
+		return
+	}
+	sum, err := o.Hash(cmd.s.ctx, state.hashType)
+	if err != nil || sum == "" {
+		sess.WriteMessage(550, fmt.Sprintf("%s hash not available", hashName(state.hashType)))
+		return
+	}
+	sess.WriteMessage(213, fmt.Sprintf("%s 0-%d %s %s", hashName(state.hashType), node.Size(), sum, param))
+}
+
+// commandMfmt responds to the MFMT command
+//
+// It sets the modification time of a file, as described in
+// draft-somers-ftp-mfxx, e.g. "MFMT 20210102030405 file.txt".
+type commandMfmt struct{}
+
+func (cmd commandMfmt) IsExtend() bool {
+	return true
+}
+
+func (cmd commandMfmt) RequireParam() bool {
+	return true
+}
+
+func (cmd commandMfmt) RequireAuth() bool {
+	return true
+}
+
+func (cmd commandMfmt) Execute(sess *ftp.Session, param string) {
+	state := sessionOf(sess)
+	if state == nil {
+		sess.WriteMessage(530, "Not logged in")
+		return
+	}
+	parts := strings.SplitN(param, " ", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		sess.WriteMessage(501, "Syntax error - need MFMT YYYYMMDDHHMMSS path")
+		return
+	}
+	// fractional seconds are accepted after the seconds
+	modTime, err := time.ParseInLocation("20060102150405", parts[0], time.UTC)
+	if err != nil {
+		sess.WriteMessage(501, "Bad time - need YYYYMMDDHHMMSS")
+		return
+	}
+	path := sess.BuildPath(parts[1])
+	err = state.vfs.Chtimes(path, modTime, modTime)
+	if err != nil {
+		sess.WriteMessage(550, err.Error())
+		return
+	}
+	sess.WriteMessage(213, fmt.Sprintf("Modify=%s; %s", parts[0], parts[1]))
+}
+
+// commandHost responds to the HOST command from RFC 7151
+//
+// Every host name serves the same files, so any name is accepted as
+// long as the client hasn't logged in yet.
+type commandHost struct{}
+
+func (cmd commandHost) IsExtend() bool {
+	return true
+}
+
+func (cmd commandHost) RequireParam() bool {
+	return true
+}
+
+func (cmd commandHost) RequireAuth() bool {
+	return false
+}
+
+func (cmd commandHost) Execute(sess *ftp.Session, param string) {
+	if sess.IsLogin() {
+		sess.WriteMessage(503, "HOST must be sent before logging in")
+		return
+	}
+	sess.WriteMessage(220, "Host accepted")
+}
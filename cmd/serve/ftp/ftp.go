@@ -7,41 +7,49 @@ package ftp
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"os/user"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/cmd/serve/acl/aclflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/fs/log"
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfscommon"
 	"github.com/rclone/rclone/vfs/vfsflags"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	ftp "goftp.io/server/core"
+	ftp "goftp.io/server/v2"
 )
 
 // Options contains options for the http Server
 type Options struct {
 	//TODO add more options
-	ListenAddr   string // Port to listen on
-	PublicIP     string // Passive ports range
-	PassivePorts string // Passive ports range
-	BasicUser    string // single username for basic auth if not using Htpasswd
-	BasicPass    string // password for BasicUser
-	TLSCert      string // TLS PEM key (concatenation of certificate and CA certificate)
-	TLSKey       string // TLS PEM Private key
+	ListenAddr   string        // Port to listen on
+	PublicIP     string        // Passive ports range
+	PassivePorts string        // Passive ports range
+	BasicUser    string        // single username for basic auth if not using Htpasswd
+	BasicPass    string        // password for BasicUser
+	TLSCert      string        // TLS PEM key (concatenation of certificate and CA certificate)
+	TLSKey       string        // TLS PEM Private key
+	ExplicitTLS  bool          // Use explicit FTPS (AUTH TLS) rather than implicit
+	UserBwLimit  fs.SizeSuffix // Bandwidth limit per user in bytes/s or 0 for none
 }
 
 // DefaultOpt is the default values used for Options
@@ -66,11 +74,14 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.StringVarP(flagSet, &Opt.BasicPass, "pass", "", Opt.BasicPass, "Password for authentication. (empty value allow every password)")
 	flags.StringVarP(flagSet, &Opt.TLSCert, "cert", "", Opt.TLSCert, "TLS PEM key (concatenation of certificate and CA certificate)")
 	flags.StringVarP(flagSet, &Opt.TLSKey, "key", "", Opt.TLSKey, "TLS PEM Private key")
+	flags.BoolVarP(flagSet, &Opt.ExplicitTLS, "explicit-tls", "", Opt.ExplicitTLS, "Use explicit FTPS (AUTH TLS) instead of implicit TLS.")
+	flags.FVarP(flagSet, &Opt.UserBwLimit, "user-bwlimit", "", "Bandwidth limit for each user in bytes/s, or use suffix b|k|M|G.")
 }
 
 func init() {
 	vfsflags.AddFlags(Command.Flags())
 	proxyflags.AddFlags(Command.Flags())
+	aclflags.AddFlags(Command.Flags())
 	AddFlags(Command.Flags())
 }
 
@@ -98,7 +109,57 @@ then using Authentication is advised - see the next section for info.
 By default this will serve files without needing a login.
 
 You can set a single username and password with the --user and --pass flags.
-` + vfs.Help + proxy.Help,
+
+#### TLS
+
+Use --cert and --key to serve FTPS. By default this uses implicit TLS,
+where the client must start TLS as soon as it connects, which is
+usually done on port 990. Use --explicit-tls to use explicit FTPS
+(RFC 4217) instead, where clients connect in plain text on the normal
+port and upgrade with AUTH TLS. Clients can then protect the data
+connections with PROT P.
+
+The certificate and key are checked for changes every minute. When
+they change, and the new pair loads, the listener is restarted with
+them. Clients already connected keep their connections.
+
+#### Passive connections
+
+Passive data connections use a random port from the range set with
+--passive-port, e.g. --passive-port 30000-32000. Open this range in
+any firewall in front of the server. If the server is behind NAT then
+set --public-ip to the address clients should connect to.
+
+#### Bandwidth limits
+
+Use --user-bwlimit to limit the bandwidth of each user. The limit is
+shared between all of the user's connections and applies to uploads
+and downloads separately, e.g. --user-bwlimit 1M.
+
+#### Resuming uploads
+
+Clients can resume uploads with REST followed by STOR. The REST offset
+must be the current size of the partial file, and the data is
+appended to it - any other offset is refused. REST 0 or no REST
+replaces the file. APPE appends to the end of the file. The VFS can
+only append to files with --vfs-cache-mode writes or full.
+
+#### Extensions
+
+As well as the commands in RFC 959 the server supports
+
+- MLSD to list directories in a machine readable format
+- MFMT to set the modification time of a file, e.g. MFMT 20210102030405 file.txt
+- HASH to read the hash of a file, chosen with OPTS HASH, e.g. OPTS HASH SHA-1
+- HOST which is accepted before login but serves the same files for every name
+
+HASH supports the hashes the remote supports out of SHA-256, SHA-1,
+MD5 and CRC32. The default is the first of those the remote supports.
+
+MLST isn't supported as the FTP library rclone uses can't send the
+multi-line replies it needs. Use MLSD instead, or "rclone serve sftp"
+to read single entries.
+` + vfs.Help + proxy.Help + acl.Help,
 	Run: func(command *cobra.Command, args []string) {
 		var f fs.Fs
 		if proxyflags.Opt.AuthProxy == "" {
@@ -117,15 +178,27 @@ You can set a single username and password with the --user and --pass flags.
 	},
 }
 
+// how often to check the TLS certificate for changes
+const certCheckInterval = time.Minute
+
 // server contains everything to run the server
 type server struct {
-	f      fs.Fs
-	srv    *ftp.Server
-	ctx    context.Context // for global config
-	opt    Options
-	vfs    *vfs.VFS
-	proxy  *proxy.Proxy
-	useTLS bool
+	f        fs.Fs
+	ctx      context.Context // for global config
+	opt      Options
+	host     string
+	port     int
+	vfs      *vfs.VFS
+	proxy    *proxy.Proxy
+	acl      *acl.ACL // access control list if set
+	useTLS   bool
+	limiters *limiters // per user bandwidth limits if set
+	stop     chan struct{}
+
+	mu       sync.Mutex
+	srv      *ftp.Server // current server - replaced when the certificate changes
+	closed   bool
+	certTime time.Time // latest modification time of the certificate and key
 }
 
 // Make a new FTP to serve the remote
@@ -138,64 +211,201 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options) (*server, error) {
 	if err != nil {
 		return nil, errors.New("Failed to parse host:port")
 	}
+	err = checkPassivePorts(opt.PassivePorts)
+	if err != nil {
+		return nil, err
+	}
 
 	s := &server{
-		f:   f,
-		ctx: ctx,
-		opt: *opt,
+		f:    f,
+		ctx:  ctx,
+		opt:  *opt,
+		host: host,
+		port: portNum,
+		stop: make(chan struct{}),
 	}
 	if proxyflags.Opt.AuthProxy != "" {
 		s.proxy = proxy.New(ctx, &proxyflags.Opt)
 	} else {
 		s.vfs = vfs.New(f, &vfsflags.Opt)
 	}
+	s.acl, err = acl.New(&aclflags.Opt)
+	if err != nil {
+		return nil, err
+	}
 	s.useTLS = s.opt.TLSKey != ""
+	if s.useTLS != (s.opt.TLSCert != "") {
+		return nil, errors.New("need both --cert and --key to use TLS")
+	}
+	if s.opt.ExplicitTLS && !s.useTLS {
+		return nil, errors.New("need --cert and --key to use --explicit-tls")
+	}
+	if s.useTLS {
+		s.certTime, err = s.checkCert()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.opt.UserBwLimit > 0 {
+		s.limiters = newLimiters(s.opt.UserBwLimit)
+	}
+	s.srv, err = s.newFTPServer()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
 
-	ftpopt := &ftp.ServerOpts{
+// checkPassivePorts checks the passive port range is valid
+func checkPassivePorts(ports string) error {
+	if ports == "" {
+		return nil
+	}
+	portRange := strings.Split(ports, "-")
+	if len(portRange) == 2 {
+		minPort, minErr := strconv.Atoi(strings.TrimSpace(portRange[0]))
+		maxPort, maxErr := strconv.Atoi(strings.TrimSpace(portRange[1]))
+		if minErr == nil && maxErr == nil && 0 < minPort && minPort < maxPort && maxPort <= 65535 {
+			return nil
+		}
+	}
+	return errors.Errorf("bad passive port range %q - need min-max, e.g. 30000-32000", ports)
+}
+
+// newFTPServer makes the underlying FTP server from the options
+func (s *server) newFTPServer() (*ftp.Server, error) {
+	ftpopt := &ftp.Options{
 		Name:           "Rclone FTP Server",
 		WelcomeMessage: "Welcome to Rclone " + fs.Version + " FTP Server",
-		Factory:        s, // implemented by NewDriver method
-		Hostname:       host,
-		Port:           portNum,
-		PublicIP:       opt.PublicIP,
-		PassivePorts:   opt.PassivePorts,
+		Commands:       commands(s),
+		Driver:         s,
+		Hostname:       s.host,
+		Port:           s.port,
+		PublicIP:       s.opt.PublicIP,
+		PassivePorts:   s.opt.PassivePorts,
 		Auth:           s, // implemented by CheckPasswd method
+		Perm:           s, // implemented by GetMode and friends
 		Logger:         &Logger{},
 		TLS:            s.useTLS,
 		CertFile:       s.opt.TLSCert,
 		KeyFile:        s.opt.TLSKey,
-		//TODO implement a maximum of https://godoc.org/goftp.io/server#ServerOpts
+		ExplicitFTPS:   s.opt.ExplicitTLS,
+		//TODO implement a maximum of https://godoc.org/goftp.io/server/v2#Options
+	}
+	return ftp.NewServer(ftpopt)
+}
+
+// checkCert checks the certificate and key load, returning the latest
+// time either was modified
+func (s *server) checkCert() (modTime time.Time, err error) {
+	for _, name := range []string{s.opt.TLSCert, s.opt.TLSKey} {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return modTime, errors.Wrap(err, "failed to read TLS certificate")
+		}
+		if fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
+		}
+	}
+	_, err = tls.LoadX509KeyPair(s.opt.TLSCert, s.opt.TLSKey)
+	if err != nil {
+		return modTime, errors.Wrap(err, "failed to load TLS certificate")
+	}
+	return modTime, nil
+}
+
+// reloadCert restarts the listener if the certificate or key changed
+func (s *server) reloadCert() {
+	modTime, err := s.checkCert()
+	s.mu.Lock()
+	if modTime.Equal(s.certTime) || s.closed {
+		s.mu.Unlock()
+		return
+	}
+	// only try each version of the files once
+	s.certTime = modTime
+	if err != nil {
+		s.mu.Unlock()
+		fs.Errorf(nil, "Failed to reload TLS certificate - using old one: %v", err)
+		return
+	}
+	newSrv, err := s.newFTPServer()
+	if err != nil {
+		s.mu.Unlock()
+		fs.Errorf(nil, "Failed to make new FTP server - using old one: %v", err)
+		return
+	}
+	oldSrv := s.srv
+	s.srv = newSrv
+	s.mu.Unlock()
+	fs.Infof(nil, "Reloading TLS certificate %q", s.opt.TLSCert)
+	// Connected clients keep their connections
+	err = oldSrv.Shutdown()
+	if err != nil {
+		fs.Errorf(nil, "Failed to stop old FTP listener: %v", err)
+	}
+}
+
+// watchCert checks for changes to the certificate until the server stops
+func (s *server) watchCert() {
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.reloadCert()
+		case <-s.stop:
+			return
+		}
 	}
-	s.srv = ftp.NewServer(ftpopt)
-	return s, nil
 }
 
 // serve runs the ftp server
 func (s *server) serve() error {
-	fs.Logf(s.f, "Serving FTP on %s", s.srv.Hostname+":"+strconv.Itoa(s.srv.Port))
-	return s.srv.ListenAndServe()
+	fs.Logf(s.f, "Serving FTP on %s", net.JoinHostPort(s.host, strconv.Itoa(s.port)))
+	if s.useTLS {
+		go s.watchCert()
+	}
+	for {
+		s.mu.Lock()
+		srv := s.srv
+		s.mu.Unlock()
+		err := srv.ListenAndServe()
+		s.mu.Lock()
+		restart := err == ftp.ErrServerClosed && !s.closed && srv != s.srv
+		s.mu.Unlock()
+		if !restart {
+			return err
+		}
+	}
 }
 
-// serve runs the ftp server
+// close stops the ftp server
 func (s *server) close() error {
-	fs.Logf(s.f, "Stopping FTP on %s", s.srv.Hostname+":"+strconv.Itoa(s.srv.Port))
+	fs.Logf(s.f, "Stopping FTP on %s", net.JoinHostPort(s.host, strconv.Itoa(s.port)))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
 	return s.srv.Shutdown()
 }
 
-//Logger ftp logger output formatted message
+// Logger ftp logger output formatted message
 type Logger struct{}
 
-//Print log simple text message
+// Print log simple text message
 func (l *Logger) Print(sessionID string, message interface{}) {
 	fs.Infof(sessionID, "%s", message)
 }
 
-//Printf log formatted text message
+// Printf log formatted text message
 func (l *Logger) Printf(sessionID string, format string, v ...interface{}) {
 	fs.Infof(sessionID, format, v...)
 }
 
-//PrintCommand log formatted command execution
+// PrintCommand log formatted command execution
 func (l *Logger) PrintCommand(sessionID string, command string, params string) {
 	if command == "PASS" {
 		fs.Infof(sessionID, "> PASS ****")
@@ -204,89 +414,83 @@ func (l *Logger) PrintCommand(sessionID string, command string, params string) {
 	}
 }
 
-//PrintResponse log responses
+// PrintResponse log responses
 func (l *Logger) PrintResponse(sessionID string, code int, message string) {
 	fs.Infof(sessionID, "< %d %s", code, message)
 }
 
-// CheckPasswd handle auth based on configuration
-//
-// This is not used - the one in Driver should be called instead
-func (s *server) CheckPasswd(user, pass string) (ok bool, err error) {
-	err = errors.New("internal error: server.CheckPasswd should never be called")
-	fs.Errorf(nil, "Error: %v", err)
-	return false, err
-}
+// key for the session in ftp.Session.Data
+const sessionKey = "rclone"
 
-// NewDriver starts a new session for each client connection
-func (s *server) NewDriver() (ftp.Driver, error) {
-	log.Trace("", "Init driver")("")
-	d := &Driver{
-		s:   s,
-		vfs: s.vfs, // this can be nil if proxy set
-	}
-	return d, nil
+// session holds the state for each logged in client connection
+type session struct {
+	vfs      *acl.VFS
+	user     string
+	hashType hash.Type // set with OPTS HASH
 }
 
-//Driver implementation of ftp server
-type Driver struct {
-	s    *server
-	vfs  *vfs.VFS
-	lock sync.Mutex
+// getSession returns the state of the logged in client making the
+// request
+func getSession(ctx *ftp.Context) (*session, error) {
+	if ctx == nil || ctx.Sess == nil {
+		return nil, errors.New("no session")
+	}
+	sess, ok := ctx.Sess.Data[sessionKey].(*session)
+	if !ok {
+		return nil, errors.New("not logged in")
+	}
+	return sess, nil
 }
 
 // CheckPasswd handle auth based on configuration
-func (d *Driver) CheckPasswd(user, pass string) (ok bool, err error) {
-	s := d.s
+func (s *server) CheckPasswd(ctx *ftp.Context, user, pass string) (ok bool, err error) {
+	var VFS *acl.VFS
 	if s.proxy != nil {
-		var VFS *vfs.VFS
-		VFS, _, err = s.proxy.Call(user, pass, false)
+		var f *vfs.VFS
+		f, _, err = s.proxy.Call(user, pass, false)
 		if err != nil {
 			fs.Infof(nil, "proxy login failed: %v", err)
 			return false, nil
 		}
-		d.vfs = VFS
+		VFS = acl.NewVFS(f, s.acl, user, s.proxy.Groups(user))
 	} else {
 		ok = s.opt.BasicUser == user && (s.opt.BasicPass == "" || s.opt.BasicPass == pass)
 		if !ok {
 			fs.Infof(nil, "login failed: bad credentials")
 			return false, nil
 		}
+		VFS = acl.NewVFS(s.vfs, s.acl, user, nil)
+	}
+	ctx.Sess.Data[sessionKey] = &session{
+		vfs:      VFS,
+		user:     user,
+		hashType: defaultHashType(VFS.Fs().Hashes()),
 	}
 	return true, nil
 }
 
-//Stat get information on file or folder
-func (d *Driver) Stat(path string) (fi ftp.FileInfo, err error) {
+// Stat get information on file or folder
+func (s *server) Stat(ctx *ftp.Context, path string) (fi os.FileInfo, err error) {
 	defer log.Trace(path, "")("fi=%+v, err = %v", &fi, &err)
-	n, err := d.vfs.Stat(path)
+	sess, err := getSession(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &FileInfo{n, n.Mode(), d.vfs.Opt.UID, d.vfs.Opt.GID}, err
+	n, err := sess.vfs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileInfo{n}, err
 }
 
-//ChangeDir move current folder
-func (d *Driver) ChangeDir(path string) (err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// ListDir list content of a folder
+func (s *server) ListDir(ctx *ftp.Context, path string, callback func(os.FileInfo) error) (err error) {
 	defer log.Trace(path, "")("err = %v", &err)
-	n, err := d.vfs.Stat(path)
+	sess, err := getSession(ctx)
 	if err != nil {
 		return err
 	}
-	if !n.IsDir() {
-		return errors.New("Not a directory")
-	}
-	return nil
-}
-
-//ListDir list content of a folder
-func (d *Driver) ListDir(path string, callback func(ftp.FileInfo) error) (err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-	defer log.Trace(path, "")("err = %v", &err)
-	node, err := d.vfs.Stat(path)
+	node, err := sess.vfs.Stat(path)
 	if err == vfs.ENOENT {
 		return errors.New("Directory not found")
 	} else if err != nil {
@@ -296,8 +500,7 @@ func (d *Driver) ListDir(path string, callback func(ftp.FileInfo) error) (err er
 		return errors.New("Not a directory")
 	}
 
-	dir := node.(*vfs.Dir)
-	dirEntries, err := dir.ReadDirAll()
+	dirEntries, err := sess.vfs.ReadDirAll(path)
 	if err != nil {
 		return err
 	}
@@ -305,11 +508,11 @@ func (d *Driver) ListDir(path string, callback func(ftp.FileInfo) error) (err er
 	// Account the transfer
 	tr := accounting.GlobalStats().NewTransferRemoteSize(path, node.Size())
 	defer func() {
-		tr.Done(d.s.ctx, err)
+		tr.Done(s.ctx, err)
 	}()
 
 	for _, file := range dirEntries {
-		err = callback(&FileInfo{file, file.Mode(), d.vfs.Opt.UID, d.vfs.Opt.GID})
+		err = callback(&FileInfo{file})
 		if err != nil {
 			return err
 		}
@@ -317,71 +520,68 @@ func (d *Driver) ListDir(path string, callback func(ftp.FileInfo) error) (err er
 	return nil
 }
 
-//DeleteDir delete a folder and his content
-func (d *Driver) DeleteDir(path string) (err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// DeleteDir delete a folder and his content
+func (s *server) DeleteDir(ctx *ftp.Context, path string) (err error) {
 	defer log.Trace(path, "")("err = %v", &err)
-	node, err := d.vfs.Stat(path)
+	sess, err := getSession(ctx)
 	if err != nil {
 		return err
 	}
-	if !node.IsDir() {
-		return errors.New("Not a directory")
-	}
-	err = node.Remove()
+	node, err := sess.vfs.Stat(path)
 	if err != nil {
 		return err
 	}
-	return nil
+	if !node.IsDir() {
+		return errors.New("Not a directory")
+	}
+	return sess.vfs.Remove(path)
 }
 
-//DeleteFile delete a file
-func (d *Driver) DeleteFile(path string) (err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// DeleteFile delete a file
+func (s *server) DeleteFile(ctx *ftp.Context, path string) (err error) {
 	defer log.Trace(path, "")("err = %v", &err)
-	node, err := d.vfs.Stat(path)
+	sess, err := getSession(ctx)
 	if err != nil {
 		return err
 	}
-	if !node.IsFile() {
-		return errors.New("Not a file")
-	}
-	err = node.Remove()
+	node, err := sess.vfs.Stat(path)
 	if err != nil {
 		return err
 	}
-	return nil
+	if !node.IsFile() {
+		return errors.New("Not a file")
+	}
+	return sess.vfs.Remove(path)
 }
 
-//Rename rename a file or folder
-func (d *Driver) Rename(oldName, newName string) (err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// Rename rename a file or folder
+func (s *server) Rename(ctx *ftp.Context, oldName, newName string) (err error) {
 	defer log.Trace(oldName, "newName=%q", newName)("err = %v", &err)
-	return d.vfs.Rename(oldName, newName)
+	sess, err := getSession(ctx)
+	if err != nil {
+		return err
+	}
+	return sess.vfs.Rename(oldName, newName)
 }
 
-//MakeDir create a folder
-func (d *Driver) MakeDir(path string) (err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// MakeDir create a folder
+func (s *server) MakeDir(ctx *ftp.Context, path string) (err error) {
 	defer log.Trace(path, "")("err = %v", &err)
-	dir, leaf, err := d.vfs.StatParent(path)
+	sess, err := getSession(ctx)
 	if err != nil {
 		return err
 	}
-	_, err = dir.Mkdir(leaf)
-	return err
+	return sess.vfs.Mkdir(path, 0777)
 }
 
-//GetFile download a file
-func (d *Driver) GetFile(path string, offset int64) (size int64, fr io.ReadCloser, err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// GetFile download a file
+func (s *server) GetFile(ctx *ftp.Context, path string, offset int64) (size int64, fr io.ReadCloser, err error) {
 	defer log.Trace(path, "offset=%v", offset)("err = %v", &err)
-	node, err := d.vfs.Stat(path)
+	sess, err := getSession(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	node, err := sess.vfs.Stat(path)
 	if err == vfs.ENOENT {
 		fs.Infof(path, "File not found")
 		return 0, nil, errors.New("File not found")
@@ -392,34 +592,45 @@ func (d *Driver) GetFile(path string, offset int64) (size int64, fr io.ReadClose
 		return 0, nil, errors.New("Not a file")
 	}
 
-	handle, err := node.Open(os.O_RDONLY)
+	handle, err := sess.vfs.OpenFile(path, os.O_RDONLY, 0)
 	if err != nil {
 		return 0, nil, err
 	}
 	_, err = handle.Seek(offset, io.SeekStart)
 	if err != nil {
+		_ = handle.Close()
 		return 0, nil, err
 	}
 
 	// Account the transfer
 	tr := accounting.GlobalStats().NewTransferRemoteSize(path, node.Size())
-	defer tr.Done(d.s.ctx, nil)
+	defer tr.Done(s.ctx, nil)
 
-	return node.Size(), handle, nil
+	return node.Size(), s.limiters.readCloser(s.ctx, sess.user, handle), nil
 }
 
-//PutFile upload a file
-func (d *Driver) PutFile(path string, data io.Reader, appendData bool) (n int64, err error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-	defer log.Trace(path, "append=%v", appendData)("err = %v", &err)
+// PutFile upload a file
+//
+// offset is the position the client sent with REST, or -1 if it
+// didn't send one. A client resuming an upload sends the size of the
+// partial file which the data is appended to. Any other offset can't
+// be written by the VFS so is refused. APPE appends to the end of the
+// file.
+func (s *server) PutFile(ctx *ftp.Context, path string, data io.Reader, offset int64) (n int64, err error) {
+	defer log.Trace(path, "offset=%d", offset)("err = %v", &err)
+	sess, err := getSession(ctx)
+	if err != nil {
+		return 0, err
+	}
 	var isExist bool
-	node, err := d.vfs.Stat(path)
+	var size int64
+	node, err := sess.vfs.Stat(path)
 	if err == nil {
 		isExist = true
 		if node.IsDir() {
 			return 0, errors.New("A dir has the same name")
 		}
+		size = node.Size()
 	} else {
 		if os.IsNotExist(err) {
 			isExist = false
@@ -428,80 +639,83 @@ func (d *Driver) PutFile(path string, data io.Reader, appendData bool) (n int64,
 		}
 	}
 
-	if appendData && !isExist {
-		appendData = false
+	if ctx.Cmd == "APPE" && offset < 0 {
+		offset = size
+	}
+	appendData := offset > 0
+	if appendData && offset != size {
+		return 0, errors.Errorf("can't resume upload at offset %d as the file is %d bytes", offset, size)
+	}
+	if appendData && sess.vfs.Opt.CacheMode < vfscommon.CacheModeWrites {
+		return 0, errors.New("resuming uploads needs --vfs-cache-mode writes or full")
 	}
 
-	if !appendData {
-		if isExist {
-			err = node.Remove()
-			if err != nil {
-				return 0, err
-			}
-		}
-		f, err := d.vfs.OpenFile(path, os.O_RDWR|os.O_CREATE, 0660)
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if appendData {
+		flags = os.O_RDWR | os.O_APPEND
+	} else if isExist {
+		err = sess.vfs.Remove(path)
 		if err != nil {
 			return 0, err
 		}
-		defer closeIO(path, f)
-		bytes, err := io.Copy(f, data)
-		if err != nil {
-			return 0, err
-		}
-		return bytes, nil
 	}
-
-	of, err := d.vfs.OpenFile(path, os.O_APPEND|os.O_RDWR, 0660)
+	f, err := sess.vfs.OpenFile(path, flags, 0660)
 	if err != nil {
 		return 0, err
 	}
-	defer closeIO(path, of)
+	defer fs.CheckClose(f, &err)
+	if appendData {
+		_, err = f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return io.Copy(f, s.limiters.reader(s.ctx, sess.user, data))
+}
 
-	_, err = of.Seek(0, os.SEEK_END)
+// GetOwner returns the name of the owner of all the files
+func (s *server) GetOwner(path string) (string, error) {
+	str := fmt.Sprint(vfsflags.Opt.UID)
+	u, err := user.LookupId(str)
 	if err != nil {
-		return 0, err
+		return str, nil //User not found
 	}
+	return u.Username, nil
+}
 
-	bytes, err := io.Copy(of, data)
+// GetGroup returns the name of the group of all the files
+func (s *server) GetGroup(path string) (string, error) {
+	str := fmt.Sprint(vfsflags.Opt.GID)
+	g, err := user.LookupGroupId(str)
 	if err != nil {
-		return 0, err
+		return str, nil //Group not found default to numerical value
 	}
-
-	return bytes, nil
+	return g.Name, nil
 }
 
-//FileInfo struct to hold file info for ftp server
-type FileInfo struct {
-	os.FileInfo
+// GetMode returns the permissions of all the files
+func (s *server) GetMode(path string) (os.FileMode, error) {
+	return vfsflags.Opt.FilePerms & os.ModePerm, nil
+}
 
-	mode  os.FileMode
-	owner uint32
-	group uint32
+// ChOwner isn't supported
+func (s *server) ChOwner(path, owner string) error {
+	return errors.New("changing owner not supported")
 }
 
-//Mode return mode of file.
-func (f *FileInfo) Mode() os.FileMode {
-	return f.mode
+// ChGroup isn't supported
+func (s *server) ChGroup(path, group string) error {
+	return errors.New("changing group not supported")
 }
 
-//Owner return owner of file. Try to find the username if possible
-func (f *FileInfo) Owner() string {
-	str := fmt.Sprint(f.owner)
-	u, err := user.LookupId(str)
-	if err != nil {
-		return str //User not found
-	}
-	return u.Username
+// ChMode isn't supported
+func (s *server) ChMode(path string, mode os.FileMode) error {
+	return errors.New("changing permissions not supported")
 }
 
-//Group return group of file. Try to find the group name if possible
-func (f *FileInfo) Group() string {
-	str := fmt.Sprint(f.group)
-	g, err := user.LookupGroupId(str)
-	if err != nil {
-		return str //Group not found default to numerical value
-	}
-	return g.Name
+// FileInfo struct to hold file info for ftp server
+type FileInfo struct {
+	os.FileInfo
 }
 
 // ModTime returns the time in UTC
@@ -509,9 +723,9 @@ func (f *FileInfo) ModTime() time.Time {
 	return f.FileInfo.ModTime().UTC()
 }
 
-func closeIO(path string, c io.Closer) {
-	err := c.Close()
-	if err != nil {
-		log.Trace(path, "")("err = %v", &err)
-	}
-}
+// Check the interfaces are satisfied
+var (
+	_ ftp.Driver = (*server)(nil)
+	_ ftp.Auth   = (*server)(nil)
+	_ ftp.Perm   = (*server)(nil)
+)
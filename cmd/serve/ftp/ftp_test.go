@@ -9,16 +9,34 @@
 package ftp
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	ftpclient "github.com/jlaffaye/ftp"
 	_ "github.com/rclone/rclone/backend/local"
 	"github.com/rclone/rclone/cmd/serve/servetest"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fs/config/obscure"
+	"github.com/rclone/rclone/vfs/vfscommon"
+	"github.com/rclone/rclone/vfs/vfsflags"
 	"github.com/stretchr/testify/assert"
-	ftp "goftp.io/server/core"
+	"github.com/stretchr/testify/require"
+	ftp "goftp.io/server/v2"
 )
 
 const (
@@ -70,3 +88,267 @@ func TestFTP(t *testing.T) {
 
 	servetest.Run(t, "ftp", start)
 }
+
+func TestCheckPassivePorts(t *testing.T) {
+	for _, test := range []struct {
+		in string
+		ok bool
+	}{
+		{"", true},
+		{"30000-32000", true},
+		{" 1 - 2 ", true},
+		{"30000", false},
+		{"32000-30000", false},
+		{"30000-30000", false},
+		{"0-10", false},
+		{"1-65536", false},
+		{"a-b", false},
+	} {
+		err := checkPassivePorts(test.in)
+		assert.Equal(t, test.ok, err == nil, test.in)
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	ctx := context.Background()
+	var l *limiters
+	in := bytes.NewBufferString("hello")
+	assert.Equal(t, in, l.reader(ctx, "user", in))
+
+	l = newLimiters(1024)
+	assert.True(t, l.get("user") == l.get("user"))
+	assert.True(t, l.get("user") != l.get("other"))
+	data := make([]byte, minBurst+1024)
+	start := time.Now()
+	out, err := ioutil.ReadAll(l.reader(ctx, "user", bytes.NewBuffer(data)))
+	require.NoError(t, err)
+	assert.Equal(t, len(data), len(out))
+	// the first burst is free, the rest must wait
+	assert.True(t, time.Since(start) > 500*time.Millisecond)
+}
+
+// writeCert writes a self signed certificate for localhost with
+// serial number serial
+func writeCert(t *testing.T, certFile, keyFile string, serial int64) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, ioutil.WriteFile(certFile, certPEM, 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, keyPEM, 0600))
+}
+
+// TestExplicitTLS checks AUTH TLS works and that the certificate is
+// reloaded when it changes
+func TestExplicitTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-serve-ftp-tls")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCert(t, certFile, keyFile, 1)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+
+	opt := DefaultOpt
+	opt.ListenAddr = testHOST + ":51781"
+	opt.BasicUser = testUSER
+	opt.BasicPass = testPASS
+	opt.TLSCert = certFile
+	opt.TLSKey = keyFile
+	opt.ExplicitTLS = true
+	s, err := newServer(context.Background(), f, &opt)
+	require.NoError(t, err)
+	quit := make(chan error)
+	go func() {
+		quit <- s.serve()
+	}()
+	defer func() {
+		assert.NoError(t, s.close())
+		assert.Equal(t, ftp.ErrServerClosed, <-quit)
+	}()
+
+	// connect returns the serial number of the server's certificate
+	connect := func() int64 {
+		var serial int64
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				cert, err := x509.ParseCertificate(rawCerts[0])
+				if err == nil {
+					serial = cert.SerialNumber.Int64()
+				}
+				return err
+			},
+		}
+		var c *ftpclient.ServerConn
+		var err error
+		for try := 0; try < 50; try++ {
+			c, err = ftpclient.Dial(opt.ListenAddr, ftpclient.DialWithExplicitTLS(tlsConfig), ftpclient.DialWithTimeout(5*time.Second))
+			if err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		require.NoError(t, err)
+		defer func() { _ = c.Quit() }()
+		require.NoError(t, c.Login(testUSER, testPASS))
+		size, err := c.FileSize("file.txt")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), size)
+		return serial
+	}
+	assert.Equal(t, int64(1), connect())
+
+	// A broken certificate is ignored
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("potato"), 0600))
+	require.NoError(t, os.Chtimes(keyFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	s.reloadCert()
+	assert.Equal(t, int64(1), connect())
+
+	// A new certificate is used
+	writeCert(t, certFile, keyFile, 2)
+	require.NoError(t, os.Chtimes(keyFile, time.Now().Add(2*time.Minute), time.Now().Add(2*time.Minute)))
+	s.reloadCert()
+	assert.Equal(t, int64(2), connect())
+
+	// Explicit TLS needs a certificate
+	_, err = newServer(context.Background(), f, &Options{ListenAddr: opt.ListenAddr, ExplicitTLS: true})
+	assert.Error(t, err)
+}
+
+// newTestServer makes a server for the files in a temporary
+// directory containing file.txt with "hello" in
+func newTestServer(t *testing.T, addr string) (s *server, cleanup func()) {
+	dir, err := ioutil.TempDir("", "rclone-serve-ftp")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+
+	oldCacheMode := vfsflags.Opt.CacheMode
+	vfsflags.Opt.CacheMode = vfscommon.CacheModeWrites
+	opt := DefaultOpt
+	opt.ListenAddr = addr
+	opt.BasicUser = testUSER
+	opt.BasicPass = testPASS
+	s, err = newServer(context.Background(), f, &opt)
+	vfsflags.Opt.CacheMode = oldCacheMode
+	require.NoError(t, err)
+	return s, func() {
+		s.vfs.Shutdown()
+		_ = s.vfs.CleanUp()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// TestPutFileOffset checks uploads are only resumed at the end of
+// the partial file
+func TestPutFileOffset(t *testing.T) {
+	s, cleanup := newTestServer(t, testHOST+":51782")
+	defer cleanup()
+	ctx := &ftp.Context{Sess: &ftp.Session{Data: map[string]interface{}{}}}
+	ok, err := s.CheckPasswd(ctx, testUSER, testPASS)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	put := func(cmd string, data string, offset int64) error {
+		ctx.Cmd = cmd
+		_, err := s.PutFile(ctx, "/file.txt", strings.NewReader(data), offset)
+		return err
+	}
+	get := func() string {
+		_, in, err := s.GetFile(ctx, "/file.txt", 0)
+		require.NoError(t, err)
+		defer func() { _ = in.Close() }()
+		data, err := ioutil.ReadAll(in)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// REST at the size of the file appends
+	require.NoError(t, put("STOR", " world", 5))
+	assert.Equal(t, "hello world", get())
+
+	// REST at any other offset is refused
+	assert.Error(t, put("STOR", "potato", 3))
+	assert.Error(t, put("STOR", "potato", 100))
+	assert.Equal(t, "hello world", get())
+
+	// REST 0 or no REST replaces the file
+	require.NoError(t, put("STOR", "one", 0))
+	assert.Equal(t, "one", get())
+	require.NoError(t, put("STOR", "two", -1))
+	assert.Equal(t, "two", get())
+
+	// APPE appends to the end
+	require.NoError(t, put("APPE", "three", -1))
+	assert.Equal(t, "twothree", get())
+}
+
+// TestCommands checks the extension commands
+func TestCommands(t *testing.T) {
+	s, cleanup := newTestServer(t, testHOST+":51783")
+	defer cleanup()
+	quit := make(chan error)
+	go func() {
+		quit <- s.serve()
+	}()
+	defer func() {
+		assert.NoError(t, s.close())
+		assert.Equal(t, ftp.ErrServerClosed, <-quit)
+	}()
+
+	var c *textproto.Conn
+	var err error
+	for try := 0; try < 50; try++ {
+		c, err = textproto.Dial("tcp", s.opt.ListenAddr)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	defer func() { _ = c.Close() }()
+	_, _, err = c.ReadResponse(220)
+	require.NoError(t, err)
+
+	// cmd sends a command and returns the reply which must have code
+	cmd := func(code int, format string, args ...interface{}) string {
+		_, err := c.Cmd(format, args...)
+		require.NoError(t, err)
+		_, msg, err := c.ReadResponse(code)
+		require.NoError(t, err, format)
+		return msg
+	}
+
+	cmd(220, "HOST localhost")
+	cmd(331, "USER %s", testUSER)
+	cmd(230, "PASS %s", testPASS)
+	cmd(503, "HOST localhost")
+
+	feat := cmd(211, "FEAT")
+	for _, name := range []string{"MLSD", "MFMT", "HASH", "HOST"} {
+		assert.Contains(t, feat, " "+name+"\n")
+	}
+
+	assert.Equal(t, "SHA-256", cmd(200, "OPTS HASH"))
+	assert.Equal(t, "MD5", cmd(200, "OPTS HASH md5"))
+	cmd(501, "OPTS HASH potato")
+	assert.Equal(t, "MD5 0-5 5d41402abc4b2a76b9719d911017c592 file.txt", cmd(213, "HASH file.txt"))
+	cmd(550, "HASH notfound.txt")
+
+	assert.Equal(t, "Modify=20200102030405; file.txt", cmd(213, "MFMT 20200102030405 file.txt"))
+	assert.Equal(t, "20200102030405", cmd(213, "MDTM file.txt"))
+	cmd(501, "MFMT potato file.txt")
+	cmd(550, "MFMT 20200102030405 notfound.txt")
+}
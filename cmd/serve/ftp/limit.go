@@ -0,0 +1,107 @@
+//go:build !plan9
+// +build !plan9
+
+package ftp
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"golang.org/x/time/rate"
+)
+
+// minimum burst so small limits don't make tiny reads
+const minBurst = 64 * 1024
+
+// limiters holds the bandwidth limits for each user
+//
+// Each user has separate limits for uploads and downloads which are
+// shared between all their connections.
+type limiters struct {
+	limit fs.SizeSuffix
+	mu    sync.Mutex
+	users map[string]*userLimiters
+}
+
+// userLimiters are the limits for one user
+type userLimiters struct {
+	in  *rate.Limiter
+	out *rate.Limiter
+}
+
+// newLimiters makes limiters for limit bytes/s per user
+func newLimiters(limit fs.SizeSuffix) *limiters {
+	return &limiters{
+		limit: limit,
+		users: map[string]*userLimiters{},
+	}
+}
+
+// get returns the limiters for user, making them if necessary
+func (l *limiters) get(user string) *userLimiters {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	u := l.users[user]
+	if u == nil {
+		burst := int(l.limit)
+		if burst < minBurst {
+			burst = minBurst
+		}
+		u = &userLimiters{
+			in:  rate.NewLimiter(rate.Limit(l.limit), burst),
+			out: rate.NewLimiter(rate.Limit(l.limit), burst),
+		}
+		l.users[user] = u
+	}
+	return u
+}
+
+// limitedReader is an io.Reader which is rate limited
+type limitedReader struct {
+	ctx     context.Context
+	in      io.Reader
+	limiter *rate.Limiter
+}
+
+// Read reads at most the burst size then waits until the bytes read
+// are within the limit
+func (r *limitedReader) Read(p []byte) (n int, err error) {
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err = r.in.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+	return n, err
+}
+
+// reader limits in, which is an upload from user
+//
+// It returns in unchanged if there are no limits.
+func (l *limiters) reader(ctx context.Context, user string, in io.Reader) io.Reader {
+	if l == nil {
+		return in
+	}
+	return &limitedReader{ctx: ctx, in: in, limiter: l.get(user).in}
+}
+
+// readCloser limits in, which is a download by user
+//
+// It returns in unchanged if there are no limits.
+func (l *limiters) readCloser(ctx context.Context, user string, in io.ReadCloser) io.ReadCloser {
+	if l == nil {
+		return in
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: &limitedReader{ctx: ctx, in: in, limiter: l.get(user).out},
+		Closer: in,
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfsflags"
+)
+
+// maximum number of results a search returns
+const searchLimit = 1000
+
+// errStopWalk is returned by a walk function to stop the walk
+var errStopWalk = errors.New("stop walk")
+
+// walk calls fn for everything under dir which the user can see, with
+// its path relative to dir. Directories the user can't list are
+// skipped.
+func walk(VFS *acl.VFS, dir string, nodes vfs.Nodes, fn func(relPath string, node vfs.Node) error) error {
+	for _, node := range nodes {
+		relPath := node.Name()
+		if err := fn(relPath, node); err != nil {
+			return err
+		}
+		if !node.IsDir() {
+			continue
+		}
+		children, err := VFS.ReadDirAll(path.Join(dir, relPath))
+		if err == vfs.EPERM {
+			continue
+		} else if err != nil {
+			return err
+		}
+		err = walk(VFS, path.Join(dir, relPath), children, func(childPath string, node vfs.Node) error {
+			return fn(path.Join(relPath, childPath), node)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listDir lists dirRemote, writing an error and returning false if it
+// can't be listed
+func listDir(w http.ResponseWriter, VFS *acl.VFS, dirRemote string) (vfs.Nodes, bool) {
+	nodes, err := VFS.ReadDirAll(dirRemote)
+	if err == vfs.ENOENT || err == acl.ErrNotDirectory {
+		http.Error(w, "Directory not found", http.StatusNotFound)
+		return nil, false
+	} else if err == vfs.EPERM {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	} else if err != nil {
+		serve.Error(dirRemote, w, "Failed to list directory", err)
+		return nil, false
+	}
+	return nodes, true
+}
+
+// serveZip sends everything in dirRemote the user can read as a zip file
+func (s *server) serveZip(w http.ResponseWriter, r *http.Request, dirRemote string) {
+	VFS := s.getVFS(r)
+	nodes, ok := listDir(w, VFS, dirRemote)
+	if !ok {
+		return
+	}
+	name := path.Base(dirRemote)
+	if dirRemote == "" {
+		name = "root"
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''`+url.PathEscape(name+".zip"))
+	if r.Method == "HEAD" {
+		return
+	}
+	fs.Infof(dirRemote, "%s: Serving zip file", r.RemoteAddr)
+	zw := zip.NewWriter(w)
+	err := walk(VFS, dirRemote, nodes, func(relPath string, node vfs.Node) (err error) {
+		header := &zip.FileHeader{
+			Name:     relPath,
+			Method:   zip.Deflate,
+			Modified: node.ModTime(),
+		}
+		if node.IsDir() {
+			header.Name += "/"
+			header.Method = zip.Store
+			_, err = zw.CreateHeader(header)
+			return err
+		}
+		remote := path.Join(dirRemote, relPath)
+		if VFS.Check(remote, acl.PermRead) != nil {
+			return nil
+		}
+		in, err := VFS.Open(remote)
+		if err != nil {
+			return err
+		}
+		defer fs.CheckClose(in, &err)
+		out, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, in)
+		return err
+	})
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		// too late to send an error to the client
+		fs.Errorf(dirRemote, "Failed to make zip file: %v", err)
+	}
+}
+
+// searchResult is a search result sent as JSON
+type searchResult struct {
+	Path    string    `json:"path"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// serveSearch finds the files and directories under dirRemote whose
+// names contain term, ignoring case
+func (s *server) serveSearch(w http.ResponseWriter, r *http.Request, dirRemote, term string) {
+	VFS := s.getVFS(r)
+	nodes, ok := listDir(w, VFS, dirRemote)
+	if !ok {
+		return
+	}
+	term = strings.ToLower(strings.TrimSpace(term))
+	results := []searchResult{}
+	if term != "" {
+		err := walk(VFS, dirRemote, nodes, func(relPath string, node vfs.Node) error {
+			if !strings.Contains(strings.ToLower(node.Name()), term) {
+				return nil
+			}
+			result := searchResult{
+				Path:  relPath,
+				IsDir: node.IsDir(),
+				Size:  node.Size(),
+			}
+			if !vfsflags.Opt.NoModTime {
+				result.ModTime = node.ModTime().UTC()
+			}
+			results = append(results, result)
+			if len(results) >= searchLimit {
+				return errStopWalk
+			}
+			return nil
+		})
+		if err != nil && err != errStopWalk {
+			serve.Error(dirRemote, w, "Failed to search directory", err)
+			return
+		}
+	}
+	fs.Infof(dirRemote, "%s: Searched for %q and found %d results", r.RemoteAddr, term, len(results))
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(results); err != nil {
+			fs.Errorf(dirRemote, "Failed to write search results: %v", err)
+		}
+		return
+	}
+
+	directory := serve.NewDirectory(dirRemote, s.HTMLTemplate)
+	directory.Title = "Search for " + term + " in /" + dirRemote
+	directory.Search = term
+	directory.Tools = true
+	for _, result := range results {
+		directory.AddSearchEntry(result.Path, result.IsDir, result.Size, result.ModTime)
+	}
+	directory.ProcessQueryParams(r.URL.Query().Get("sort"), r.URL.Query().Get("order"))
+	directory.Serve(w, r)
+}
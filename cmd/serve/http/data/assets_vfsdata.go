@@ -22,14 +22,14 @@ var Assets = func() http.FileSystem {
 	fs := vfsgen۰FS{
 		"/": &vfsgen۰DirInfo{
 			name:    "/",
-			modTime: time.Date(2020, 5, 5, 16, 40, 6, 115915195, time.UTC),
+			modTime: time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC),
 		},
 		"/index.html": &vfsgen۰CompressedFileInfo{
 			name:             "index.html",
-			modTime:          time.Date(2020, 5, 5, 16, 40, 5, 919909715, time.UTC),
-			uncompressedSize: 15424,
+			modTime:          time.Date(2026, 10, 18, 14, 0, 0, 0, time.UTC),
+			uncompressedSize: 20462,
 
-			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x7b\x6d\x73\xdb\x46\xf2\xe7\x6b\xe9\x53\x4c\x98\xcd\x8a\x4a\xc0\xe1\x3c\x3f\x48\xa4\xf6\x6c\xc6\x59\xbb\x56\x71\x52\xb1\x9d\xad\x6c\x2a\x2f\x20\x62\x48\xe2\x0c\x02\x0c\x00\xea\xc1\x3a\x55\xdd\x87\xb8\x4f\x78\x9f\xe4\xaa\x67\x00\x12\x90\x28\x27\x7b\xf5\xdf\xbf\xec\xa2\x80\x9e\x99\x9e\x7e\xf8\x75\x4f\x37\x08\x4d\xbe\x18\x8d\xd0\xf1\x78\x8c\x66\xc5\xe6\xae\x4c\x97\xab\x1a\x31\x42\x25\xfa\x3e\xae\xeb\x95\xbb\x41\xaf\x8b\xac\x46\x71\x9e\xa0\xf7\x2b\x87\x66\x71\x92\xdc\xa1\x17\xdb\x7a\x55\x94\xd5\xf1\x78\x0c\xeb\x2e\xd3\xb9\xcb\x2b\x97\xa0\x6d\x9e\xb8\x12\xd5\x2b\x87\x5e\x6c\xe2\xf9\xca\xb5\x23\x11\xfa\xd9\x95\x55\x5a\xe4\x88\x61\x82\x86\x30\x61\xd0\x0c\x0d\x4e\xcf\x81\xc5\x5d\xb1\x45\xeb\xf8\x0e\xe5\x45\x8d\xb6\x95\x43\xf5\x2a\xad\xd0\x22\xcd\x1c\x72\xb7\x73\xb7\xa9\x51\x9a\xa3\x79\xb1\xde\x64\x69\x9c\xcf\x1d\xba\x49\xeb\x95\xdf\xa7\xe1\x82\x81\xc7\x2f\x0d\x8f\xe2\xaa\x8e\xd3\x1c\xc5\x68\x5e\x6c\xee\x50\xb1\xe8\x4e\x44\x71\xdd\x08\x0d\x3f\xab\xba\xde\x9c\x8d\xc7\x37\x37\x37\x38\xf6\x02\xe3\xa2\x5c\x8e\xb3\x30\xb5\x1a\x5f\xbe\x99\xbd\x7a\xfb\xee\xd5\x88\x61\xd2\x2c\xfa\x90\x67\xae\xaa\x50\xe9\x7e\xdf\xa6\xa5\x4b\xd0\xd5\x1d\x8a\x37\x9b\x2c\x9d\xc7\x57\x99\x43\x59\x7c\x83\x8a\x12\xc5\xcb\xd2\xb9\x04\xd5\x05\x08\x7d\x53\xa6\x75\x9a\x2f\x23\x54\x15\x8b\xfa\x26\x2e\x1d\xb0\x49\xd2\xaa\x2e\xd3\xab\x6d\xdd\xb3\x59\x2b\x62\x5a\xf5\x26\x14\x39\x8a\x73\x34\x78\xf1\x0e\xbd\x79\x37\x40\x2f\x5f\xbc\x7b\xf3\x2e\x02\x26\xff\x7c\xf3\xfe\xf5\x0f\x1f\xde\xa3\x7f\xbe\xf8\xe9\xa7\x17\x6f\xdf\xbf\x79\xf5\x0e\xfd\xf0\x13\x9a\xfd\xf0\xf6\xdb\x37\xef\xdf\xfc\xf0\xf6\x1d\xfa\xe1\x3b\xf4\xe2\xed\x2f\xe8\x1f\x6f\xde\x7e\x1b\x21\x97\xd6\x2b\x57\x22\x77\xbb\x29\x41\x83\xa2\x44\x29\x58\xd3\x25\xde\x74\xef\x9c\xeb\x89\xb0\x28\x82\x48\xd5\xc6\xcd\xd3\x45\x3a\x47\x59\x9c\x2f\xb7\xf1\xd2\xa1\x65\x71\xed\xca\x3c\xcd\x97\x68\xe3\xca\x75\x5a\x81\x57\x2b\x40\x07\xb0\xc9\xd2\x75\x5a\xc7\xb5\x27\x3d\xd1\x0b\xa3\xe3\xef\x8b\x04\xb8\x85\x19\x67\x08\xbd\x48\xe2\x4d\x1d\x4c\x55\xce\xb3\x22\x77\x68\x1d\x97\x1f\xb7\x1b\x34\x1a\x5d\x1c\x1f\x4f\xbe\xf8\xf6\x87\xd9\xfb\x5f\x7e\x7c\x85\x56\xf5\x3a\xbb\x38\x9e\x84\x5f\x47\x93\x95\x8b\x93\x8b\xe3\xa3\xa3\x49\x9d\xd6\x99\xbb\xb8\xbf\x87\x01\x84\xdf\xc6\x6b\xf7\xf0\x30\x19\x07\x2a\x8c\xaf\x5d\x1d\xa3\xf9\x2a\x2e\x2b\x57\x4f\x07\xdb\x7a\x31\x32\x83\xfd\x40\x1e\xaf\xdd\x74\x70\x9d\xba\x9b\x4d\x51\xd6\x03\x34\x2f\xf2\xda\xe5\xf5\x74\x70\x93\x26\xf5\x6a\x9a\xb8\xeb\x74\xee\x46\xfe\x26\x42\x69\x9e\xd6\x69\x9c\x8d\xaa\x79\x9c\xb9\x29\xc5\xe4\x09\xa3\x65\x51\x2c\x33\xd7\x61\x93\x17\x75\x19\xe7\x55\x16\xd7\x6e\x70\x71\x3c\xa9\xea\x3b\x10\xeb\x6b\x74\x8f\x36\x71\x92\xa4\xf9\xf2\x0c\x91\x73\xd0\x78\x99\xe6\xfe\xf2\xe1\xf8\xaa\x48\xee\xd0\xfd\xf1\xd1\xa2\xc8\xeb\xd1\x22\x5e\xa7\xd9\xdd\x19\xaa\xe2\xbc\x1a\x55\xae\x4c\x17\xe7\xc7\x47\xb5\xbb\xad\x47\xa5\x03\xe3\x7a\x0e\xc5\xa6\x4e\xd7\xe9\x27\x57\x6d\x9c\x4b\xce\x8f\x8f\xae\xe2\xf9\xc7\x65\x59\x6c\xf3\x64\x34\x2f\xb2\xa2\x3c\x43\x5f\x2e\xfc\xcf\xf9\xf1\xc3\x71\x0c\xbc\x5b\x32\x21\xca\x25\xbc\x65\x99\xb8\x79\x51\x7a\xc7\x9c\xa1\xbc\xc8\x9d\x9f\x7e\xb6\x02\x6f\x47\xc7\x2b\x8a\x9a\xeb\x2e\x03\x4e\xed\x3c\xf0\x05\x87\xc0\xbc\x2f\xab\xed\x7a\x1d\x97\x5e\x85\x46\xc7\x51\xe6\x16\xf5\x19\x92\x5f\x9d\xef\x49\x3e\xc7\x04\xda\xc3\x71\xbd\x3a\x5b\xa4\x65\x55\x8f\xe6\xab\x34\x4b\xa2\xe3\x3a\xe9\xde\x03\x27\xef\x81\x33\x44\xbf\x3a\x47\xe3\xaf\x51\x0d\x8b\x5d\xe9\x21\xba\x2e\xae\x20\x45\x7c\x3d\x0e\x7c\xb2\xb8\xc7\x66\x7f\xfb\xe7\xb9\x04\x4d\xba\xf2\xd7\xc5\xe6\x0c\x31\xb9\xb9\xed\x28\x70\x55\xd4\x75\xb1\x3e\x43\x34\x90\x0f\xd9\x9c\xc1\x3f\x6f\x1b\xba\x73\x68\x95\x7e\x72\x67\x88\x11\xbf\xc8\x53\x6e\x5c\x30\x45\x5e\x94\xeb\x38\x3b\x3f\x3e\xba\x59\xa5\xb5\x1b\x55\x9b\x78\xee\x80\x7a\x53\xc6\x9b\xf3\xe3\x23\xb0\xfc\x22\x2b\x6e\x46\xb7\x67\x68\x95\x26\x89\xcb\x5b\xb7\xb5\x23\x67\xc8\x65\x59\xba\xa9\xd2\xea\x7c\xef\x20\x6b\x6d\x23\xc1\x23\xc7\x93\xf3\xe3\xa3\x1d\xee\x90\x00\x79\x1e\x1e\x39\xf9\x09\x28\x7c\x3c\x67\x69\x40\x86\x9f\xfb\xc8\x4d\x7b\x20\x1f\x3f\x1c\xaf\x21\x03\xdf\x1f\x1f\x25\x69\xb5\xc9\xe2\xbb\x33\x74\x95\x15\xf3\x8f\x30\x82\x7d\xc8\xf4\x4d\x42\xd9\xde\x24\x2d\xea\x7f\x76\x65\x12\xe7\x71\xd4\x87\xff\x55\x51\x26\xae\xdc\x3b\x60\x73\x8b\xaa\x22\x4b\x13\xf4\xa5\x9d\xc1\xbf\xf3\x47\x8e\xa3\xe4\xb0\xe3\x48\xd0\xd9\x0b\x33\x4a\x6b\xb7\xde\x6b\xd0\xc2\x93\xba\x35\x4c\xf9\x72\x91\x66\x75\x0f\x12\x67\xc1\x62\x8d\x2c\x3d\x21\x66\xb3\x99\xc7\xb4\x3f\x0e\x3a\xa0\x23\xe4\xab\xbd\xf0\xf3\x22\xcb\xe2\x4d\xe5\xce\x50\x7b\xe5\xd7\xf8\x2d\x0e\xe8\x97\xc4\xd5\xca\x25\xe8\xcb\x24\x86\x7f\x7e\xaa\x4f\x13\x75\xb9\xf7\xd6\x33\x51\xef\xe6\x21\xc2\x20\x1c\x76\x4e\x8d\xb3\x74\x99\x9f\x21\x88\xcb\xf3\x8e\x4e\x60\x12\x04\x7e\x80\xf0\x98\xad\xe2\x7c\xe9\x12\xb4\x28\x8b\x35\x22\x90\x9f\x59\x1b\x65\x4f\x62\xe3\xb3\x26\xee\x79\x59\x79\xca\x41\x88\x7b\xce\x5d\x94\x5e\x65\x71\xc0\x4b\xbd\x42\xd5\xf5\x12\x46\xae\x5d\x59\xa7\xf3\x38\x6b\x35\x58\xa7\x49\x92\x05\xdb\x85\x08\x3f\x18\x3b\x5d\x01\x1a\xa4\xd7\xc9\x59\x5e\xaf\x02\x72\x87\xec\xb4\xe3\x28\x43\xbe\x7a\x32\x81\x9f\xf6\x7c\x4f\x7c\x00\x37\xbf\x9a\x04\xb6\x9f\x2c\x4e\xa3\xfe\x6a\x71\xfa\xd8\xf0\x1e\x5e\x87\xc4\x68\xd4\xdc\x14\x55\x1a\x42\x2e\xbe\xaa\x8a\x6c\x5b\xb7\x2a\x62\x38\x67\xbc\x2b\xf1\xb2\xd8\x6e\x3a\x88\x0d\x39\x96\x62\x2d\x01\xb3\x47\x37\x45\x99\x8c\xae\x4a\x17\x7f\x3c\x43\xfe\xd7\x28\xce\xb2\x6e\x1a\x01\xd3\xb4\x43\x30\xf9\xb1\x57\x36\xa5\x1b\xb5\x7e\xc1\xe9\xbc\xc8\x9f\x46\x87\x6c\x02\x08\x46\x71\x55\x94\x75\x2f\xda\xd3\x1c\x32\xc5\xa8\x09\xfa\x5d\x18\x78\xe9\x56\xae\x13\x5f\x1d\x6d\x4b\x97\xc5\x75\x7a\xed\x20\xb5\x01\xae\x30\x0b\x01\xd8\xd9\x02\xd7\xc5\xe6\x39\x13\x1d\x05\x23\x90\x76\xf9\x88\x3e\x91\x10\x07\x6c\x3e\xcb\xa1\x85\x6e\x58\xba\x67\xf8\x70\xbc\x28\x8a\x27\x39\xc0\xc7\xcb\x53\x90\x87\x54\xd6\x75\xf8\xdc\xe5\xb5\x2b\x81\xcd\xff\x58\xbb\x24\x8d\xd1\x70\x1d\xdf\x8e\x1a\x9b\x28\x42\x36\xb7\x80\x91\xf1\xd7\x47\x78\x95\x26\xae\x4d\x1d\x7b\x63\x86\xe3\xf8\xe8\x01\x95\x6e\x5d\x5c\x87\x72\xe9\xa3\x73\x1b\x94\xc4\xb5\xab\xa0\x3e\xdc\x9f\x60\x47\x07\xb0\xdd\x9a\x3f\xde\xd6\x05\xf0\x39\x3e\xea\x41\x96\x9f\x46\x8f\x96\x05\xc4\x1f\x3a\xae\x8f\x0e\x21\x19\x38\x86\x53\xee\xd1\x11\x13\xe8\x3e\xaa\xbb\xa7\x03\xd0\x3b\x59\xf5\xa8\x63\x0d\x4a\x82\x41\x1f\x8e\x1f\x8e\x27\xe3\xa6\x62\x3a\x9a\x8c\x9b\x8a\x6f\xe2\x13\x5f\x91\x67\x45\x9c\x4c\x4f\x02\x8b\xe1\xe9\x79\x5d\x2c\x97\x99\x1b\x0e\x7c\xee\x1c\x9c\x9e\xcf\x7d\xf6\x7a\x97\x7e\x72\xc3\xd3\x13\x5f\xa6\x41\x68\x5d\x87\x16\x64\x3a\xa0\x98\x0e\xd0\xed\x3a\xcb\xab\xe9\xa0\xd3\x01\xdc\x70\x5f\xfd\x33\x42\xc8\xb8\xba\x5e\x36\x53\xce\x6e\xb3\x34\xff\x78\x68\x22\xb5\xd6\x8e\xfd\xe8\x00\x05\x4c\x4f\x07\x64\x80\x42\xf1\x08\x57\x5e\xfc\xe9\xe0\x00\xd4\x7c\xed\x78\x34\x49\xdc\xa2\xf2\x57\x47\xbe\x05\xfb\xae\xc8\xa0\xf6\x80\xda\xd7\xd3\x96\x28\x4d\xa6\x83\x85\xa7\x0e\xa0\x19\xca\x46\xe5\x16\x38\xe6\x45\xfe\xc9\x95\x45\xa0\xf9\x5b\x17\x38\x1e\x1d\x4d\x36\x71\xbd\x42\xc9\x74\xf0\x3d\x33\x12\x33\x86\xb8\xc6\x52\xae\x46\x54\x30\xac\x2e\x29\x25\xd8\x22\xf2\x9a\x53\xac\x67\x54\x60\x26\x11\x41\x04\x51\x05\xd4\x30\xf5\x5a\x4b\x4c\x57\x1c\x48\xec\x67\xb8\x9e\x93\x11\x23\x58\xc9\x11\xcc\x57\x23\x3f\x69\x04\x0c\xc2\xe5\xa7\x56\x8a\x2f\xbf\xfb\xee\x05\x21\x64\x30\x7e\x56\x12\xd5\xdd\x97\x2b\x44\x90\x24\x98\x19\x44\x90\xd2\x58\x8b\x6b\x2a\x0d\xd6\x73\x82\xa8\xc6\x42\x23\xbf\x1d\x82\x15\xd2\x7f\x86\xcb\xd7\x9e\xd9\x1c\xa6\x08\x10\x19\xe4\xa0\x02\xf3\x70\xe5\xa7\xfc\x0c\xdc\xe4\x9c\x8c\x3c\x9f\x56\x6c\x18\x19\xed\x27\x75\xc5\x9e\xbd\x60\xa6\x15\x7b\x32\x5e\x1e\xb0\xfe\xa8\x5a\x15\x65\x3d\xdf\xd6\xe0\xd4\xb2\xf8\xe8\x1a\xa3\x37\x77\xa3\xc6\xe7\xb4\xe7\x91\xae\xc7\xdc\xb5\xcb\x8b\x24\xd9\x79\xe9\x20\xf3\x11\x9c\xe0\x9b\x83\x9e\x6e\xd6\x3d\xb7\xb0\x5a\xc5\x9b\x1d\x04\x9e\x9a\x5e\x18\xad\x22\xf0\x96\x30\xca\x12\x86\x2e\x3d\x1a\x28\x13\xdc\xf4\xc9\x00\x0f\x46\xb4\x91\x11\x41\x97\x9c\x62\x65\xa9\x92\xcc\x46\x04\x79\xaf\x35\x4b\x08\x22\x11\x55\xd8\x58\x65\x29\x51\x88\xf4\x78\x90\x88\x52\x86\x95\x50\x44\x53\xe0\xa1\x70\xc3\xe3\x19\xb2\x96\x98\x58\xcd\x0d\x91\x68\xd6\x21\x4b\x81\x85\x90\x8a\x10\x83\x38\x61\x58\x49\xc9\x8c\xec\x6e\x74\x58\xb3\x7f\x0d\xbc\x7d\xde\x79\x7b\x3c\x02\xe6\xc5\x64\x0c\x76\xf9\x03\x2b\xa9\x9e\xe2\x5c\xf5\x34\x07\xd0\x46\x1e\xb4\xdc\x48\x65\x10\x89\x3c\x72\xa9\x25\xdc\x82\xea\x8c\x29\x2c\x24\x15\x4c\xa0\x19\x89\x98\xe0\xd8\x12\x2b\x34\x45\x1d\x1e\x4c\x1a\x4c\x2d\xe7\xcc\xa0\xce\x46\x1d\xea\x65\x47\x9c\x0e\x79\xd6\xb1\x43\x8f\xc7\xce\x66\x9d\xfd\xba\xd4\xbd\x4c\x5d\xbb\x77\x04\xef\xd9\x7d\xaf\x5c\xd7\xee\x0a\xf5\x6d\xf4\x8c\x9d\x7d\x24\x3d\xb2\xf3\x2e\xa2\xba\x16\xa7\x4c\x61\x2a\x05\xe5\x22\x62\x92\x60\x29\x2d\x35\x02\xcd\x80\x6c\x24\xb1\x1a\xc8\x14\x1b\xc3\x95\xe6\x88\x32\x8d\x39\x21\x52\x80\x99\x38\x26\x44\x51\xc6\x3c\x55\x6b\xa6\x08\x8b\x98\x14\x98\x06\xea\x8c\x32\x83\x85\xb2\x42\x00\x59\x62\xd6\x4e\x36\xd8\x52\x4b\x28\x98\x54\x61\x4a\x04\x31\x40\xb5\x58\x71\xc3\x39\x58\x54\x63\x42\x18\x11\x14\xcd\x28\xf7\x12\x59\xc5\xbc\xa1\x39\x53\x92\x53\x44\x21\x6f\x30\x63\x24\x4c\xb6\x88\x72\x8e\x29\x21\x44\x6a\x7f\x3b\xa3\x5c\x60\x61\xb9\xe6\xba\x19\x96\x58\x50\xc9\x95\xf0\x3c\xa4\xa4\x84\x21\xca\x15\xa6\x94\x31\x22\xfc\x7e\x4a\x4b\xe9\xb7\x53\xd8\x10\x4b\x84\xe8\x4a\x41\xb9\xc6\x4c\x1a\x45\xad\xd7\xc3\x1e\xa0\x0a\x2c\x75\xcb\xa2\x43\x06\x10\x04\xf5\xba\x54\x86\xcd\x9e\x4a\x38\x37\x9c\x79\x1b\x0b\xa9\xa9\xe0\x41\x0a\x6d\x94\x54\x2a\x62\xc2\x62\x4b\x0c\x55\xdc\x4b\x2c\x15\xd5\xda\x7a\x2a\xf1\xb6\xe8\x53\x0d\x96\xc1\x4d\x9e\x05\x31\x56\x33\x60\xc1\xb0\xa1\x82\x19\xe5\x2d\x61\x94\xb0\xdc\x46\x8c\x6b\xc8\x2f\x82\x98\x3e\x95\x63\xa6\xb9\x50\xde\x8a\x7b\x32\x93\x98\x04\xe1\xba\xb2\x51\x8d\x65\xcb\xd8\x60\x6a\x08\x13\x40\x25\xd8\x08\x65\xb9\x67\x61\xb1\xb6\x46\x53\x1e\x31\x22\x30\x6b\x0c\x27\x00\x4e\x96\x71\x11\x51\x6b\xb0\x82\xed\x04\xa2\x42\x60\xc1\x2c\x67\x26\xa2\x96\x63\xad\x40\x3f\x88\x78\x8d\x19\x55\xca\xd8\x88\x1a\x83\x8d\xb2\xdc\x18\x44\x25\xc1\x4a\x1b\x41\x69\x44\x8d\xc0\x26\x88\x4c\xa5\xc0\x86\x2b\xab\x79\x44\x0d\x6d\xc1\x32\x83\xb3\xcc\x5a\x29\xb9\x8c\xa8\x06\xa0\x5a\x69\x19\xa2\x8a\x63\xc5\x14\x15\x36\xa2\x5a\xec\xf0\xad\x0c\x16\x86\x4a\xc9\x22\xaa\x19\x56\x80\x58\x08\x06\xcd\x31\xe7\xca\x4a\x11\x51\x4d\xb0\xe0\x46\x6b\x85\xa8\xb6\x98\x52\x6e\x0d\x8f\x60\x9d\x52\x92\x13\x85\xa8\x91\x58\x1a\x6d\x80\x85\xd2\x98\x0b\x70\x1f\x9a\x51\xcb\x30\x51\x54\x33\x20\x2b\xcc\x28\x6c\x88\xc0\x00\x5a\x11\xae\x4d\x44\x95\xc4\x5c\x30\x23\x35\x62\x44\x7a\x29\xa8\x88\xa8\x12\x58\x05\xa5\x67\x8c\x32\xb0\x32\xd5\x9e\xca\x82\xf7\x18\xb5\x58\x5a\x43\x85\x8a\x40\x25\x6b\x21\x7e\x11\x63\x06\x53\xc5\xbc\xce\x7b\xea\x25\x13\x0a\x13\x09\x21\xfe\x2c\xd9\xca\xd6\xa9\xb3\x1e\x59\x63\x0d\x16\x92\x08\xa8\x5a\x32\xc1\x81\x6a\x31\x44\x13\x11\x08\xc0\xc7\x35\x31\xd6\x46\x8c\x50\x4c\x9a\x2c\x02\x19\x85\x51\x88\xbe\x88\x41\x0e\x0b\x50\x86\x10\x20\xda\x1a\xa3\x22\x46\x38\x96\x21\x31\x40\x14\x71\xcd\x34\x95\x5d\xea\x0c\x92\x84\x50\x9c\xf1\x47\x93\x0d\x96\x9c\x32\xad\x7b\x8c\x15\xc1\x60\x62\xc6\xbb\x52\x5c\x72\x48\x71\x84\x31\x00\x11\xd7\x58\x5b\x80\x00\x9a\x71\x48\x5b\x8c\x68\xa9\x23\x80\x35\x13\xc6\x1a\xc4\x99\xc1\x4a\x30\x6e\x44\xe4\xf3\x88\x8f\xea\x1e\x91\x61\x06\x8e\xa6\xc0\xa0\x43\x26\x98\x00\x95\xa1\x2e\x5b\x66\x30\x0b\x81\xd3\x95\x81\x29\xac\x83\xc0\x97\x1d\x89\x15\xc7\x42\xb4\xae\xf6\x89\x8a\x6b\xa9\x22\x45\xb1\xb1\x01\xb3\x1d\x53\x28\x1a\xec\x65\x25\xb5\x02\xee\x66\x1d\xa3\xfa\x41\x82\x19\x57\x8a\xb3\x1e\x03\xf0\x92\xe5\x5c\xeb\xfe\x6e\xe0\x52\x2d\x2c\x8d\x94\x68\x40\x21\xbc\x9f\x89\x36\x44\x47\x4a\x61\x0d\x33\xb5\xe9\x12\x21\xa8\x02\x88\x2f\xf7\x54\x4a\x48\x8b\x88\xcb\x2e\x06\xf7\xe4\x19\xa0\xdf\x28\x4e\xc0\x68\x7b\x32\xe4\x7f\xaa\x14\x33\x2c\xa2\x54\x63\xaa\x34\x87\xc2\x93\x4a\xcc\xb4\x10\x5c\x47\x10\xf2\xa2\x3d\x9b\x28\xc1\x4a\x29\x4e\x00\xc6\x14\x2a\x0e\x6b\x10\x25\x06\x73\x49\xac\xe5\x11\xd5\x12\xf3\x86\x6f\x87\x6a\x29\xd6\x21\xc0\x66\x1d\x32\x04\x1b\x6b\x72\x02\x85\x84\x1d\xa0\xc6\x38\xb6\x10\xfc\x12\x51\x26\x20\x46\x85\x14\x11\x13\xba\x0d\xfe\x19\x85\xa4\x48\xb4\x66\x3e\xf1\xd2\x76\xae\x84\x34\xce\xac\x08\x49\xba\x55\xee\xd0\x11\xfb\xcc\xc1\x0d\x3f\x03\xe4\x1f\x57\x2f\x8a\x72\x3d\x1d\xec\x9e\x5c\x0f\x19\x35\x58\x58\x9f\x0c\x11\x55\x04\x13\xff\x73\x8a\xfc\x83\xf0\xe1\x88\x46\x88\x9e\xa2\xfd\xf4\x51\x77\xfe\xa8\xbb\xe0\x51\x61\xb0\xaf\xb4\x77\x17\xbe\x09\x82\x46\xf6\x71\x0b\x94\x66\x6e\x5f\x79\x43\x73\xf9\xb8\xf2\x66\xb2\xab\xcc\xc1\xd2\xbb\x5d\x91\xa5\xb9\x9b\xc7\x9b\xe9\xc0\x3f\x2e\xeb\x91\xff\x67\x91\xe6\x2d\xfd\x49\x17\x43\x39\x62\x02\x53\x76\xcd\x34\xb8\x66\x4e\x90\xc2\x54\x21\x89\x0d\x40\x06\x53\x38\x59\x31\x6d\xae\x5f\x33\x6e\xe7\x1a\x73\x68\xae\x80\x3a\x12\xd8\xaa\xe6\xd2\x4f\xf8\xd9\xd7\x02\xf2\x1d\x44\x36\x0c\xf8\x0a\x85\x6b\x44\xf9\x6b\xf0\x9b\x9e\x51\xe3\x19\x73\xff\x5f\x87\xd5\x41\x80\x4f\x07\x5a\x2c\x40\xb2\x5f\x7d\x49\x99\x0d\x90\x82\x46\x8a\x60\x69\x90\x86\x3e\x8a\x5a\x4c\xa1\xcf\x63\xda\x5f\xbe\x66\xc2\x5e\xee\x16\x7d\x7a\xb6\xfb\x49\x33\xf7\x9f\xea\x7d\xba\xac\xdb\xce\xe7\x20\x00\x29\x6f\x20\x14\xa1\xdd\xe5\xe9\x93\x8e\xa8\xc7\x2e\xf4\x43\x3d\xc4\xfc\x21\x68\x3c\x6e\xfe\xbf\x30\xd2\x75\x04\xb4\x3f\x98\x32\x2a\x8c\x51\xbe\x23\x30\x00\x10\x23\xb4\xf6\x1d\x01\x9c\xc7\xdc\x5a\x26\x3c\x6e\x84\x35\xbe\xc8\xb7\x0a\x5b\x6b\xad\xe1\x01\x21\xcc\x70\xcd\xbb\xd4\x4b\xa8\x85\xac\xd5\x70\xe8\x77\xc8\x33\x5f\x39\x59\xe9\xcb\xe5\x3d\x99\x71\x8b\xa9\x26\x86\xed\xb6\x13\xac\x4b\xdc\x4b\x74\xb9\xa7\x52\xc6\x31\x15\x32\x64\xe6\x43\x54\x0a\x47\xbe\x91\x82\x46\x0c\x1b\xc1\xa8\x26\x56\xb8\x11\x15\x3e\x5d\x72\x65\x05\x1c\x7f\xfd\x91\xcb\x46\x1b\x09\x3a\xf6\x87\x66\x20\x83\x24\xc4\x12\x1d\x8d\x28\xd6\x54\x68\x6b\x0d\x73\x23\x22\x11\x89\x20\x58\x08\x63\xd6\xc2\x4d\xc7\x9e\x4d\xf2\x2a\xdd\xbc\xa6\x54\xd3\xcf\x74\x74\x94\x2a\xa8\x0c\x88\x6f\x64\x29\x55\x3e\xeb\x5b\x22\xac\xf2\x99\x5c\x45\x94\x52\x2c\x0c\x9c\x55\x08\x94\x64\xd2\x10\x62\x22\xca\x20\x5e\x19\x94\xa3\x70\x5c\xc1\xed\x25\x24\x66\x7f\xf1\x98\xe7\xee\xa6\x2b\x96\xb6\xe2\x4f\x35\x40\x42\x63\x43\x38\x05\x73\x5a\x81\x49\x38\xe8\x66\x02\x52\xa7\xb5\x86\x02\x59\x62\x1a\xca\x7b\x61\xb0\x15\x56\x4a\x19\xbc\x4f\x42\x01\x25\x2c\x16\x8c\x2a\xb2\xc3\x84\xa7\xce\x24\xc1\x94\x1a\x21\x0c\x60\x42\x63\x13\xc8\x70\x00\x28\x43\x18\xd3\x11\xf3\xe5\xaf\x2f\x1a\x24\xc5\x0c\xca\x58\x28\x7e\xac\xc5\x3c\xd4\x92\x33\xc9\x30\x23\xc6\x2a\x63\x22\x4e\x08\x16\xfe\xa4\x93\x1c\x73\xad\x7d\x33\xc1\x09\x45\x52\x60\x2d\x2c\x51\x5c\xf8\xdb\x19\x34\x55\x82\x69\xc1\x55\x18\xd6\x98\x28\xc1\x35\xb1\x9e\x85\x0a\x7d\x83\xd4\x58\x2b\xca\xbc\x76\x16\x3a\x4e\xce\x34\x9a\x49\x83\x85\x34\x44\x36\xe4\x46\x0a\xa8\x9f\x89\x56\x0c\x0e\x40\x0b\x2d\xdd\x53\xaa\xc6\x1c\xea\x19\xee\x59\xec\xc9\x0a\x9b\x46\xbd\x2e\x55\x62\xbb\xa3\x2a\x03\x3d\x2e\xf3\xa6\x37\x80\x4f\x1a\xa4\xe0\x52\x6a\x06\x93\x39\xb4\x37\x50\x84\x4b\x83\x19\x25\xbe\xae\x86\x60\x32\x8d\x2d\xfa\x54\xd1\x74\x61\xa0\x1e\x37\x9a\x43\x24\x18\x8d\x75\xa8\xc1\x24\x34\x2c\xdc\x0a\x49\x23\x66\x38\xd6\xa1\x8c\xeb\x52\xb5\xc5\xd6\xf7\x87\xb3\x1e\x95\x63\x16\x64\xeb\x8a\xa6\x74\xdb\x14\x49\x8b\x0d\xb3\x4c\x42\xb7\xa5\x28\x56\x4d\xf3\xaa\x28\x16\xc2\x17\x08\xe0\x92\x60\x35\xc5\xb1\xe4\x86\x09\xc2\x7d\xcb\xa7\x42\x81\xa0\x7c\xfd\xc4\x39\xb4\xd5\x42\x63\xc5\x84\xb0\x68\xa6\xa0\xdf\x91\xbe\xeb\x60\x02\xba\x15\x5f\xf0\x6b\x86\x39\xd3\x82\xfa\xc2\x83\x60\xee\xc5\xd5\x0a\x0b\x23\xad\xb6\xcc\x77\x76\xc1\x36\x33\x43\xb0\x12\x42\x0a\x0a\x54\x81\x65\x68\xcb\x0c\xd4\x3b\x92\x4a\x45\x23\xc6\x59\x8b\x6c\x4b\x30\xe5\x44\x4a\xf0\x05\x07\xb6\xa1\xd4\xb2\x02\x5b\x23\xad\x02\x79\x99\xc1\x32\x74\x33\x10\xc2\x5a\x31\x28\xf6\x99\xf6\x8d\x26\x1c\x8a\x44\x43\xc9\x69\x64\x78\xd0\x41\xda\xa7\x00\x94\x63\x4d\x89\x66\x26\xf4\x91\x06\xf6\x43\x94\x11\x2c\x20\xd6\x64\xc4\x18\xd4\xfd\x54\xc0\x69\xc9\xb4\x97\x82\xf9\xfa\xcb\x04\x85\x67\xd0\xdf\x1b\x66\x29\xd4\xfa\x8c\x63\x11\xdc\x06\x6d\x24\x13\x9a\x36\x93\x59\xd3\x19\x0a\x8b\x0d\xa5\x52\xf4\xa8\x97\xd0\x89\x69\x22\xa4\x35\xcf\x92\xa1\x5c\x6b\x1b\xf0\x0e\x59\x12\x48\x8f\x92\x86\xd6\x90\xd0\xf0\xdc\x88\xed\x1e\x45\x68\x82\x09\xb5\x96\x48\xdf\xee\xcb\x26\x7b\x50\x4d\xa1\xc8\xf5\xcf\x38\x04\x36\x01\xc1\xd0\x45\x6a\x66\x0c\x14\x9d\x52\x62\x19\xf2\x01\xd5\x0a\x13\xe6\x1b\xc3\x0e\x75\x46\x75\x53\x54\xf6\xc8\xd4\x10\xdf\x68\x43\x4a\xe9\x30\x36\x14\x1b\x46\x01\xec\x7b\x19\x2e\x01\x49\x5a\x52\x66\x95\x6f\x86\x4c\xe8\xb3\x67\xa0\x28\x34\xc9\x0a\x4a\x5f\xc8\x45\xbe\xd2\xf6\xfd\x82\xa5\xdc\x86\xae\x8e\x86\x84\xd0\xa3\x6a\xcc\x9a\xc6\xa9\x47\x96\x58\xb4\xcd\xc5\x8e\x31\x85\x44\x1a\x22\xa6\x23\x05\xb4\xc0\x3a\x48\x7c\xb9\x17\x19\xfc\xb8\x7b\xdc\x63\x08\x66\x84\x79\x16\xdc\x62\xdd\x3c\x1a\xd8\x9b\x82\x72\x1b\x0c\x26\x04\x83\xea\xdf\x3f\x65\xd8\x9b\x35\x0c\x53\x6c\x8c\x54\xdc\xf6\x79\x10\x4c\x9a\x56\xad\xbb\x21\x38\x95\x71\x0b\x3d\xb5\x60\x3b\x10\x81\xff\x99\x26\x70\xee\x08\x60\x1e\x9e\x93\x74\xa9\x12\xc2\x18\xfa\x80\xcb\x2e\x59\xef\x9e\x3a\x5c\x76\x80\xd8\x21\xcf\x8c\xc1\x92\x32\x62\xe1\x84\xdb\x93\x01\x64\x54\x32\xe8\xdd\xa8\x11\xd8\x86\x67\x54\x5c\x61\xcb\xb8\xef\x7e\x7c\xeb\xdf\x60\x8b\x33\xcc\xa9\xe4\x44\x43\xe8\x50\xcc\x82\x03\x39\xf1\xd1\x2c\x03\x43\xb8\x13\x12\xdb\x10\x56\x33\xb8\x85\x73\x20\x24\x00\x2e\xb1\x94\x60\x4e\x16\x31\xcd\xc0\x93\x86\x6b\x24\x14\x04\xa4\x50\x04\xce\x25\xda\x46\xfa\x4c\x28\xac\xa4\xd2\x4c\xa9\x50\xc3\x34\x93\x35\xa6\x44\x71\x42\x6c\x48\xc6\x61\xd7\x83\x27\x69\xb7\xcd\x19\xcd\x8a\xcd\xdd\xae\xd2\x6b\x4b\xc1\x43\x5f\xa7\x1c\x2e\x3f\x05\x81\x1a\x48\x59\x19\x21\xc6\xfe\xb8\xff\xe9\xce\x1f\x75\x17\xfc\xb9\xfe\xe7\xc3\x06\xc5\x65\x59\xdc\x3c\xee\x81\xb6\x9b\x91\xa7\x3f\x23\xe5\x08\x4e\x11\xc6\xd0\x88\x11\x83\x29\x3b\xed\xf7\x2f\x9d\x25\xeb\xb8\x2e\xd3\xdb\x21\x66\x4c\x50\xee\xbf\xfd\xc1\x14\x4e\x7b\xc4\xb9\xc4\x4a\x23\xaa\x04\xe6\xf2\xf4\x71\xad\x4c\x06\x50\xb6\xac\x47\x10\x64\x54\x23\x41\x19\x26\x74\x35\x62\x06\x1b\xa6\x9b\x5f\x19\x15\x58\x50\x31\x62\x50\xbf\x49\x74\xe8\x0e\x85\xbb\x03\x0d\x07\xe8\xfe\x6d\x71\x93\x1f\xd6\x3e\x29\x6e\xf2\xff\x94\xfe\xa3\xbe\x01\x00\xb3\x96\xff\x77\x1b\x60\x32\x6e\xbf\x0c\x9c\x8c\xab\x6b\x4f\x9b\x84\x77\x91\xc2\xf0\x8a\x86\xf9\xf7\xf7\x65\x9c\x2f\x1d\xfa\x4b\x1a\xa1\xbf\xcc\xcb\xed\xfa\x0a\x9d\x4d\x11\x7e\x59\xba\x38\xf1\xb7\x0f\x0f\x93\x18\xad\x4a\xb7\x98\x0e\x9a\xf7\xe2\xc2\x34\x7c\x99\xe6\x1f\x1f\x1e\x06\x17\x7d\xea\x7b\x77\x5b\x3f\x3c\x4c\xc6\xf1\xc5\xfd\x7d\xba\x40\x39\x70\x46\xe4\xe1\x61\x7c\x7f\xef\xf2\xe4\xe1\xa1\xf9\x15\x44\x0c\x42\x84\x6f\x63\x83\x60\x93\x75\x9c\xe6\xcd\x97\x99\xe9\x35\x9a\x67\x71\x55\x4d\x07\x6b\x57\xc7\x8d\x03\x3c\x19\x3c\xd8\xbc\x19\xb6\xf3\x4b\xb5\x89\xf3\xee\x7c\xff\x12\xce\xe0\x62\x92\xe6\x9b\x6d\x8d\xea\xbb\x8d\x9b\x0e\x6a\x77\x5b\x0f\xd0\x26\x8b\xe7\x6e\xe5\xbf\xf1\xf2\x7d\x5e\xed\xca\x41\xdb\xf3\xf9\xeb\x22\xff\xe8\xee\xb6\x9b\xfd\x17\xc2\x27\x17\x93\x31\xf0\x6f\x4d\x9c\xa4\xd7\xad\x91\xdb\xab\x8e\xb4\x59\x5a\xd5\x69\xbe\x6c\x05\x0e\xef\xee\xc4\x65\x1a\x8f\x12\x57\xcd\xcb\xf4\xca\x25\x57\x77\x4f\x15\xa8\xdb\xb7\x10\xfd\x4d\xb9\x2b\xf1\xeb\xd5\xc5\x64\xdc\xa9\xfe\xbb\xfd\x49\xeb\x99\xbf\x55\x45\x59\x4f\xf3\x78\xed\x92\xb4\xf4\xaf\x51\xfd\xd5\x7f\x77\x3d\x8d\xab\xf9\xa0\x95\xcb\xbf\x77\xe1\xdf\x5b\x08\xdf\x6b\x5f\xf8\x6f\xb1\x9b\xc1\xba\xd8\xec\xbe\x6a\xa6\x6e\xbd\xff\x06\x1a\x4b\xb8\xeb\x7f\xd7\x7d\x9d\xba\x9b\x97\xc5\xed\x74\xe0\xbf\xec\x65\xd8\x32\x46\xad\x40\x0a\x13\x2e\x8d\xb1\x76\x70\x31\xd9\x56\x0e\xf9\xef\xb2\xcf\x82\x84\x5f\xee\xf2\xcd\xc5\x64\xbc\xad\xdc\x45\x80\x65\x57\x84\xf0\xb6\xc4\x7f\x56\x8a\x4e\xdc\xf7\xe5\x18\xc7\x3b\xab\x7e\xc6\xba\x07\xac\xda\xd8\xf2\x6d\xbc\x76\x1d\x26\x7f\xd2\x63\x55\xfa\xe9\x33\x3c\xdf\xa5\x9f\x3e\xc3\xb3\x9d\xdc\xbe\xe3\x31\x78\x6e\x93\x3a\xfd\x9c\xe0\xe1\x15\x5a\x97\xfc\x3b\x1b\x75\x26\x4c\xc6\x3b\xa8\x02\xb5\x0b\xe1\xab\x22\xb9\x3b\x84\xe7\x04\xd6\x27\xdd\xfb\x27\x82\x63\xbc\xd7\xa6\x1f\xda\xcb\x62\xbb\x19\x5c\xfc\xbd\x40\xdb\x4d\x37\x26\xfd\xf6\x5d\x05\x7a\xfc\xff\xba\x4e\xe2\x6a\x75\xfe\x88\xfc\x54\xaf\x3f\x3b\xaf\x33\xa1\xa3\xff\xfd\xfd\x08\x85\x64\x8a\x5f\xe5\x75\x99\xba\x2a\xe4\x39\xaf\x7e\xcb\xc4\x3f\x7a\x3c\xa0\xfb\x73\x26\x01\xa6\xe9\x02\xe1\x37\xd5\xb7\x69\xd9\xf2\x6b\x5e\x40\x69\x03\x25\x04\x47\x1b\x2a\xf4\x0f\x22\x85\x53\x38\x93\x0e\x46\x47\xf3\x6a\x48\x2f\x32\xba\x82\xb8\xac\x72\xff\x25\x32\x30\x25\x11\x67\xfc\xa0\x0c\x69\xb0\xf0\x33\x12\xb4\x87\xc7\x13\x60\x40\x78\x0e\x2e\x1e\x9f\x55\xf8\xc3\x4f\x97\x9d\x43\x0a\x5f\xba\x78\x11\x8e\xa7\x3e\x7a\xba\xe6\x3f\x6c\x72\x00\x42\x12\xd7\xf1\x28\x44\xd2\x60\x44\x0f\xe2\xe5\x89\x99\x1e\xaf\xbb\xbf\xc7\x10\xd7\x20\xd4\x04\xc2\xff\x62\x47\x98\x8c\xfd\xfd\x13\x6e\x1d\x95\x5b\xd1\xbe\x2f\x92\xf7\xe9\xda\xa1\xff\x85\xe2\x45\xed\xca\x57\x9b\x62\xbe\x42\xbd\x2d\x9f\x62\x16\xd2\x80\x7f\xc3\x0b\x2e\xbc\x1c\x2d\x97\x60\xa0\xce\xed\x64\x0c\x73\x9e\x4a\xf2\x58\xaf\x27\x9b\xfc\xdf\xff\xfd\x7f\x3e\x27\xfe\xbf\x19\x4c\x9d\xa5\x93\x71\x27\x9d\x4c\xc6\xfe\x4c\xed\x1f\xc1\x93\x71\x5b\x3a\x4c\xe0\x90\xdd\xd4\x7e\xf8\x3a\x2e\x51\x38\xc5\x5f\x65\x68\x8a\x92\x62\xbe\x5d\xbb\xbc\xc6\x4b\x57\xbf\xca\x1c\x5c\xbe\xbc\x7b\x93\x0c\x9b\x93\xfe\xe4\xf4\x1c\x16\xb5\x0b\xf0\xa2\x98\x6f\xab\x61\x43\xdc\xe6\xf3\x3a\x2d\x72\xd4\x16\x05\xfe\x55\xb3\xb0\xc3\xef\x68\xba\xdb\x05\x5f\xc7\xd9\xd6\xe1\xba\x4c\xd7\xc3\x53\x5c\x17\x97\xc5\x8d\x2b\x67\x71\xe5\x1a\x3e\x7e\x81\xcb\xdc\xba\xea\xca\xf3\xfb\xd6\x95\x77\xef\x5c\xe6\xe6\x75\x51\xbe\xc8\xb2\xe1\x49\x5d\x62\x08\x85\x46\xa4\x23\xbf\x02\x2f\x8a\xf2\x55\x3c\x5f\x0d\x5b\x61\x86\x2e\x6b\xe5\x38\x4a\x17\x68\xf8\xc5\xef\xbb\xdb\x23\x97\x61\xff\xc2\x18\x6e\xde\xfb\x43\x53\x74\x72\x72\xde\x0c\x96\xae\xde\x96\x79\x73\xd7\xd8\x18\x04\x83\x28\xf2\x96\x72\x59\x5f\xa6\xe1\x89\x7f\x5b\xb4\x15\x67\x37\xf9\xe7\x18\x66\x87\x65\x18\xea\xab\x59\xf8\x6b\x85\xcf\x18\xc0\x4b\xda\xac\xc5\x69\x9e\xb8\xdb\x1f\x16\xc3\xdf\x4f\xd1\x17\xd3\x29\x1a\xd1\x3f\xa7\xc0\x83\x07\xe3\x67\xa7\xe6\x45\xee\x4e\x7a\x1a\x3e\x04\x01\x1e\x7a\xee\xcc\x8a\x79\x9c\xa5\x9f\xdc\xb7\x4d\x64\x0c\x5d\x84\xbc\x50\x11\x8a\xcb\x56\x18\x90\xd8\x75\xd5\x43\xd3\xe9\xd4\xbf\xc1\xbe\x48\x73\x97\xec\x64\xee\x9a\xf5\x61\xe7\xed\x04\x2c\xe4\x6e\x10\x6c\x31\x74\x80\xbd\x17\x75\xf3\xd7\x38\xc3\x93\x36\x22\x4f\x4e\x1b\xf3\xc0\x5e\x69\xf5\x36\x7e\x3b\x4c\x4e\x77\x8c\x1f\xb1\xe8\x48\xd2\x35\xea\x93\x65\x87\xfc\x1c\x3e\x1f\x69\x83\x12\xef\x29\x68\x41\xdf\xd5\x65\x9a\x2f\x87\xbf\xfe\x16\xa1\xfb\x24\xbe\x3b\x43\x03\x36\x4a\xd2\x65\x5a\x0f\x22\xb4\x2e\xf2\x7a\xd5\xa3\xdc\xb9\xb8\x3c\x43\x83\x7c\xbb\x76\x65\x3a\x1f\x44\x68\x55\x6c\xcb\xfe\x9a\x34\xdf\xd6\xae\x47\xaa\xdc\xbc\xc8\x93\x0e\xa9\xeb\x19\xb0\x18\x18\xe4\x32\xad\x40\xb0\x17\x65\x19\xdf\xe1\x4d\x59\xd4\x05\x14\xf1\xb8\xca\xd2\xb9\xc3\xf3\x38\xcb\x86\x07\xa2\xb9\x7a\x79\xf7\x3e\x5e\x42\x31\x36\x1c\x00\x93\x41\x63\xd5\x96\xe1\x2e\x82\x1e\xbb\xfd\xf4\xfc\xb8\xdd\x7c\xe9\xea\x0f\x65\xf6\x63\x5c\xc6\x6b\x57\xbb\x12\x62\xbb\x05\xcb\xa3\xa1\x61\xe5\x2f\xbb\xa9\xa0\xfa\x31\x5e\xba\x0f\x3f\x5d\xa2\x29\xba\x49\xf3\xa4\xb8\xc1\xb0\x13\x2c\xc6\x95\x8b\xcb\xf9\x0a\x57\xdb\xab\x2a\x98\x98\x9e\x46\x7e\x5d\xf5\xe1\xa7\xcb\x9f\xa1\x41\xb8\xca\x1c\x64\x85\x96\x07\xae\x36\x59\x5a\x0f\x4f\xfe\x7a\xd2\x4e\xdc\xed\xfc\xd6\xbf\xb9\xed\xfd\x1e\x04\x3f\x5a\x14\x25\x1a\xa6\x68\x8a\xc8\x39\x4a\xd1\x04\xf5\x98\xe2\xcc\xe5\xcb\x7a\x75\x8e\xd2\x6f\xbe\xd9\x81\xa3\xcf\x0d\xf6\xed\x2e\xf9\x35\xfd\xad\xdd\x7f\x7a\xd2\x58\x27\xa0\xac\xbf\xee\x57\xf2\x9b\x0f\x86\xbe\x29\x5a\xe4\xa1\x47\x93\xe9\x6f\xfd\xc8\x41\x7f\x43\x75\xb9\x75\xe8\x0c\x25\x6e\x5e\x24\xee\xc3\x4f\x6f\x66\xc5\x7a\x53\xe4\x2e\xaf\x1f\x6f\x44\x7f\x3b\x7d\x0a\xe4\x87\x7e\x72\x6e\xde\xdc\xf5\x87\x0c\x2c\x3a\xdd\x7b\xc6\x9f\xbf\x68\xfa\xc4\x87\x27\x7e\xe0\xe4\x51\x76\x06\x2c\x1d\x3e\x30\xaa\x97\x77\xb3\x96\x7d\x67\xa3\xf3\xd6\x0b\x43\x60\xe1\x1d\x11\xa1\x60\x76\x9f\x4e\xc3\xda\xbd\x23\xd0\x04\x1d\x72\x0a\x2c\x9e\x6f\xcb\xf2\x75\xe9\x16\x9d\x75\xe0\x0d\x28\x6c\x76\xc1\x3e\x0c\xe5\xc4\xf4\x04\x7a\xca\x93\xd3\x7b\xd4\x98\xdd\xaf\x5f\x2d\xd1\x74\xc7\x05\x97\xce\x77\xbc\xc3\x30\x35\x42\x27\x31\xac\x38\xdf\xa5\xce\xfe\x0e\xb0\x72\xb5\xec\x9f\x0c\x9d\xed\xe2\x3f\xbd\x5b\x1c\x36\x0b\xf2\xfd\x1b\xbb\x1d\x72\x6b\xe9\xe2\x04\x50\xf9\x5d\x9a\x85\xd7\xb0\xa1\x52\xea\x86\xdd\x36\x4f\xbd\xbf\x7e\x3d\x79\x09\x9b\xfe\xc3\x7f\x7e\xef\x3f\xff\xee\x3f\xdf\xfb\xcf\x1f\xfd\xe7\x2b\xff\xf9\x2f\xff\xf9\xcb\xcb\x93\xdf\xf6\x9e\x0f\xf1\xe3\x6f\x6f\x56\x69\x16\xf6\x41\x17\x53\x44\x09\x13\xfb\xc0\x01\xe2\x38\x10\x1b\xd1\xbf\xf9\x26\xed\x66\xfd\x06\xfc\x9b\xb8\xac\xdc\x77\x59\x11\xd7\x41\x60\x5c\x17\xdf\xa5\xb7\xce\xbf\x47\xff\x0d\x3a\x41\x27\xe8\x9b\x20\xf9\xaf\xe9\x6f\x4d\x02\xec\xa9\xdd\x7d\xef\xbc\x9b\x63\xd2\x4f\xee\x79\x70\xee\xf2\x1f\x4c\x1b\x9c\x76\xd3\xc3\x5e\xc5\x90\x22\x80\xcf\xc1\xd4\xb0\xda\xae\xe3\x1c\xf6\x45\xd3\xc3\xb6\xf7\x0e\x4c\xf3\xdc\x95\xaf\xdf\x7f\x7f\xd9\xba\xf7\xe9\x08\x9a\xa2\x1d\xaf\x8e\x77\xc3\x63\xa9\xb6\x4c\x9b\x8c\x43\x6d\x37\x19\x87\x3f\xc8\xfc\x7f\x01\x00\x00\xff\xff\xd1\xe7\x7f\xef\x40\x3c\x00\x00"),
+			compressedContent: []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xbd\x5c\xeb\x72\xdb\x46\x96\xfe\x2d\x3f\x45\x87\x99\x84\x54\x4c\x82\xb8\x5f\x74\xf1\xac\x43\x3b\x63\xd7\x28\x76\x2a\x56\x32\x35\x9b\xcd\x0f\x88\x6c\x89\x18\x83\x04\x07\x00\x25\x2b\x1a\x55\xed\x43\xec\x13\xee\x93\xec\x77\x4e\x37\x80\x06\x45\xd9\x9e\x9d\xcd\x4a\x89\x09\x1c\x74\x9f\x3e\xf7\x4b\xa3\xa9\x93\x2f\x26\x13\xf1\x64\x3a\x15\xb3\x62\x73\x5b\x66\x57\xcb\x5a\xb8\xb6\x13\x88\xef\xd3\xba\x5e\xca\x1b\xf1\xaa\xc8\x6b\x91\xae\x17\xe2\x7c\x29\xc5\x2c\x5d\x2c\x6e\xc5\xf3\x6d\xbd\x2c\xca\x0a\x93\x68\xde\x59\x36\x97\xeb\x4a\x2e\xc4\x76\xbd\x90\xa5\xc0\x24\xf1\x7c\x93\xce\xf1\xa1\x9f\x8c\xc5\xcf\xb2\xac\xb2\x62\x2d\x5c\xcb\x16\x23\x1a\x30\xd0\x8f\x06\x87\xc7\x84\xe2\xb6\xd8\x8a\x55\x7a\x2b\xd6\x45\x2d\xb6\x95\x04\x8e\xac\x12\x97\x59\x2e\x85\xfc\x30\x97\x9b\x5a\x64\x6b\x31\x2f\x56\x9b\x3c\x4b\xd7\x73\x29\x6e\xb2\x7a\xc9\xeb\x68\x2c\x16\xe1\xf8\xab\xc6\x51\x5c\xd4\x29\x86\xa7\x98\xb0\xc1\xdd\xa5\x39\x50\xa4\xb5\x26\x9a\x7e\x96\x75\xbd\x39\x9a\x4e\x6f\x6e\x6e\xac\x94\x09\xb6\x8a\xf2\x6a\x9a\xab\xa1\xd5\xf4\xec\xf5\xec\xe5\x9b\x77\x2f\x27\x20\x5a\x4f\xfa\x69\x9d\xcb\xaa\x12\xa5\xfc\xfb\x36\x2b\xc1\xf0\xc5\xad\x48\x37\x20\x6a\x9e\x5e\x80\xd4\x3c\xbd\x11\x45\x29\xd2\xab\x52\xe2\x59\x5d\x10\xd1\x37\x65\x56\x67\xeb\xab\xb1\xa8\x8a\xcb\xfa\x26\x2d\x25\xa1\x59\x64\x55\x5d\x66\x17\xdb\xba\x27\xb3\x86\x44\x70\x6e\x0e\x80\xd4\xd2\xb5\x18\x3c\x7f\x27\x5e\xbf\x1b\x88\x6f\x9f\xbf\x7b\xfd\x6e\x4c\x48\xfe\xf2\xfa\xfc\xd5\xdb\x9f\xce\xc5\x5f\x9e\xff\xf8\xe3\xf3\x37\xe7\xaf\x5f\xbe\x13\x6f\x7f\x14\xb3\xb7\x6f\x5e\xbc\x3e\x7f\xfd\xf6\x0d\xee\xbe\x13\xcf\xdf\xfc\x55\xfc\xf9\xf5\x9b\x17\x63\x21\x21\x31\xac\x23\x3f\x6c\x4a\xe2\x00\x64\x66\x24\x4d\xb9\x60\xd1\xbd\x93\xb2\x47\xc2\x65\xa1\x48\xaa\x36\x72\x9e\x5d\x66\x73\xb0\xb6\xbe\xda\xa6\x57\x52\x5c\x15\xd7\xb2\x5c\x83\x23\xb1\x91\xe5\x2a\xab\x48\xab\x15\x59\x07\xa1\xc9\xb3\x55\x56\xa7\x35\x83\x1e\xf0\x65\x89\x27\xdf\x17\x0b\xc2\xa6\x46\x1c\x09\xf1\x7c\x91\x6e\x6a\x25\xaa\x72\x9e\x17\x6b\x09\xfd\x95\xef\xb7\x1b\x31\x99\x3c\x7b\xf2\xe4\xe4\x8b\x17\x6f\x67\xe7\x7f\xfd\xe1\x25\xf4\xb4\xca\x9f\x3d\x39\x51\x1f\x07\x27\x4b\x99\x2e\xf0\x79\x70\x52\x67\x75\x2e\x9f\xdd\xdd\xd1\x03\x61\xbd\x49\x57\xf2\xfe\xfe\x64\xaa\xa0\xf4\x7c\x25\x6b\x58\xc1\x32\x2d\x2b\x59\x9f\x0e\xb6\xf5\xe5\x24\x1e\x74\x0f\xd6\x18\x7f\x3a\xb8\xce\xe4\xcd\xa6\x28\xeb\x01\xcc\x65\x5d\xcb\x35\x06\xde\x64\x8b\x7a\x79\xba\x90\xd7\x20\x7c\xc2\x37\x63\xa8\x12\x7a\x4c\xf3\x49\x35\x4f\x73\x79\xea\x58\xf6\x03\x44\x57\x45\x71\x95\x4b\x03\x0d\x6c\xb9\x4c\xd7\x55\x9e\xd6\x12\x83\x4f\xaa\xfa\x96\xc8\xfa\x46\xdc\x89\x0d\x9c\x08\x22\x3c\x12\xf6\x31\x71\x7c\x95\xad\xf9\xf2\xfe\xc9\x45\x01\xe7\xba\x7b\x72\x70\x09\x1c\x93\xcb\x74\x95\xe5\xb7\x47\xa2\x02\x92\x49\x25\xcb\xec\xf2\xf8\xc9\x41\x2d\x3f\xd4\x93\x52\x92\x70\x19\x43\xb1\xa9\x21\xf4\xdf\x24\x34\x25\x17\x78\x7e\x91\xce\xdf\x5f\x95\x05\xa4\x3f\x99\x17\x79\x51\x1e\x89\x2f\x2f\xf9\xe7\xf8\xc9\xfd\x93\x94\x70\x37\x60\xdb\x0e\xe5\xc2\x6b\x50\x2e\xe4\xbc\x28\x59\x31\x47\x70\xc2\xb5\xe4\xe1\x47\x4b\xd2\xf6\xf8\xc9\xd2\x11\xfa\xda\x44\xe0\x39\xc9\x5c\xe1\x25\x85\xd0\xb8\x2f\xab\xed\x0a\xfc\x30\x0b\x9a\xc7\x49\x2e\x2f\xeb\x23\x11\x7c\x75\xdc\x81\x38\xc6\x28\xd8\xfd\x93\x7a\x79\x74\x99\x95\x55\x3d\x99\x2f\xb3\x7c\x31\x7e\x52\x2f\xcc\x7b\xc2\xc4\x1a\x38\x12\xce\x57\xc7\x62\xfa\x8d\xa8\x69\x32\x08\x21\x13\x5d\x15\x17\x14\x22\xbe\x99\x2a\x3c\x79\xda\x43\xd3\xdd\x7e\x3e\x16\xc5\x89\x49\x7f\x5d\x6c\x8e\x84\x1b\x6c\x3e\x18\x0c\x5c\x14\x75\x5d\xac\x80\x4c\x81\xf7\xc9\xdc\xa5\x5f\x96\x8d\xd3\x2a\xb4\x82\x9e\x80\xcb\xe6\x49\x0c\xb9\x91\x4a\x14\xeb\xa2\x5c\xa5\x39\xa0\x37\xcb\xac\x96\x93\x0a\xc1\x48\x12\xf4\xa6\x4c\x37\x80\x92\xe4\x2f\xf3\xe2\x66\xf2\xe1\x48\x2c\xb3\xc5\x42\xae\x1b\xb5\x35\x4f\x8e\x84\xcc\xf3\x6c\x53\x65\xd5\x71\xa7\xa0\x24\x49\x34\x05\x3b\x8a\xb7\x31\xa8\xb5\x3b\xe1\x13\x3d\xf7\x3b\x4a\x7e\x60\x14\xec\xcf\x79\xa6\x2c\x83\xc7\xee\xa8\xa9\x33\x64\x0c\x58\x51\x04\x06\x10\x81\x6c\x93\xa7\x30\xe2\x8b\xbc\x98\xbf\xa7\x27\x16\xbb\x4c\x5f\x24\x8e\xdb\x89\xa4\xb1\x7a\x64\x8c\x45\xba\x4e\xc7\x7d\xf3\xbf\x28\x4a\x90\xd1\x29\x60\xf3\x01\x81\x35\xcf\x16\x60\x76\x46\xbf\xc7\x3b\x8a\x73\xec\xfd\x8a\xb3\x15\xcf\x4c\xcc\x04\x22\x5f\x75\x1c\x34\xe6\xe9\xc8\x15\x0d\xf9\x12\x59\xa8\x56\xc6\x2d\xd3\x72\xbe\x34\x6c\xe3\x48\x89\x4e\x13\xd5\xa3\x66\x36\x9b\x31\x7e\x84\xf0\xba\xe8\x4b\x22\x5b\x93\x14\x27\x5a\x20\x3d\x5c\x0d\xb9\x8f\x21\x6c\xd8\x2f\xd3\x45\xb6\x45\x00\xf5\x1e\x35\xbf\x98\x7e\x0d\x53\xf0\x3c\x4f\x0b\x98\x08\x40\x26\xc8\x6a\x7a\xba\x2d\x2b\x7a\xbc\x29\x32\xc4\xac\xd2\xa0\xb8\x33\x84\x3d\xd8\xe5\x9c\x7e\x77\xb1\x63\x6e\x3a\x57\xb1\x3f\x35\xa4\xa9\xfc\x9f\x85\xb9\x47\xe5\xd7\x59\x95\xc1\xfb\xb2\xfa\xb6\xb3\x6c\xf8\x72\xa9\xd7\xef\x63\x34\x07\xf3\x75\xce\xc6\xf8\xe5\x76\x93\x17\xe9\x62\x52\x21\xf5\x6c\x2b\xd3\xd2\xc3\x30\xa4\x01\x14\x55\xad\x45\x59\x6c\x36\x94\xb7\x1a\xd3\x2c\xb6\x35\x29\x82\xa5\x28\x16\x69\xb5\x44\x22\xea\x82\xa2\x7e\x3a\x29\x2e\x2f\x91\x3c\x8e\xc4\xc4\x53\x16\x53\x73\xa6\x37\xe2\x89\x6d\x7f\xd5\x29\x06\x0b\xe7\xe9\xa6\x02\xd2\xe6\x4a\xb1\xc3\x82\x7c\x68\xba\xcd\xaa\x8b\x94\x7e\x79\x28\x67\x80\x96\xff\xbb\x47\x03\x3a\xc9\x9f\x82\x1e\x45\xba\xd6\x5f\xd3\x3c\xbb\x82\x07\x92\xc8\x4d\xcb\x22\xab\x12\x24\x6f\x8a\x7c\xb3\x25\x52\x39\x16\xbd\x2c\x8b\x15\x9c\x1f\xa9\xd7\x6d\x02\xe8\x83\xb0\xf7\x51\xef\xe9\x69\x33\x64\xc8\xde\xe8\xc5\x98\xcd\x00\x74\x91\xa7\x2a\x14\x00\x5e\x5d\x5f\xb1\x66\x65\x59\xa3\x2e\xc8\x1b\x0e\x56\x30\x05\xa5\x5c\xc5\xdd\xfe\xb0\x68\x12\xa0\x83\x18\xc2\xfe\xba\x5e\xaa\xa0\x34\x72\x0f\x0d\x45\xc5\xf6\x57\x0f\x06\x78\x87\x3d\x6f\xb6\x39\x36\xeb\x0f\x9d\x9b\xba\xc1\xfe\xe1\xb8\x3f\xdb\x3f\xdc\x15\x3c\x47\x8e\x7d\x64\x68\x36\x37\x45\x95\xa9\x68\x9a\x5e\xc0\xaf\x51\xde\x69\x16\x2d\x2a\x21\x58\x95\xd6\x55\x81\xfa\xe7\x81\xfb\x58\x51\xc0\x1e\x74\x03\x23\x9a\x5c\x94\x32\x7d\x0f\x39\xd2\x07\x96\xce\xcd\x0c\x41\xa2\x69\x1e\xd1\xe0\x5d\xad\xa0\xfc\x9b\x34\x7a\xb1\xb2\xb9\x8a\x4d\xfd\xc0\x17\xe8\xd8\x48\x4f\x2d\xc4\x87\xfa\x63\xe1\xab\x71\x03\xa6\x6e\x29\x8d\xd0\x69\x70\x5b\x4a\x14\x41\xd9\xb5\xa4\xac\x45\x76\x65\xb9\x2a\xb6\x1a\x4b\x58\x78\xf0\x98\x88\x0e\x94\x10\xec\x66\xfa\xc4\x79\x40\xa1\xa5\x6c\xf3\x51\x0c\x8d\xe9\xaa\xa9\x1d\xc2\xfb\x27\x97\x45\x51\xf7\x32\x3e\x22\x31\xfb\xcb\x43\x23\x57\x21\xcb\x54\x38\x0a\x5b\x1d\x37\xff\x6d\x25\x17\x59\x2a\x46\xab\xf4\xc3\x44\xcb\x24\xb4\x81\x82\x6c\x64\xfa\xcd\x81\x85\xe0\x26\x9b\xd0\xd1\x09\x53\x55\x5a\x07\xf7\x90\xd0\x0a\x2a\xe4\x4a\xf8\xbd\x94\x1b\x44\x86\x5a\x56\x54\xfa\x77\xc5\xc9\xc1\x1e\xdb\x6e\xc4\x9f\x6e\xeb\x82\xf0\x60\xd0\xb2\x67\xdf\xe3\x9d\x69\xca\xe2\xf7\x55\x62\x07\xfb\x2c\x99\x30\xaa\x02\x66\xa7\x7a\x50\x70\xf6\x6a\x33\xf1\x13\x5c\x27\x4c\xfd\xa8\x95\x86\x63\x2b\x81\xde\x43\x58\x27\x53\x5d\x0c\x1f\x9c\x4c\x75\x31\x7f\xc2\x81\xaf\x58\x53\x24\x3f\x1d\x2a\x14\xa3\xc3\xe3\xba\xb8\x42\x51\x3d\x1a\x70\xec\x44\xaf\x38\xe7\xe8\xf5\x0e\xfa\x18\x1d\x0e\xb9\x02\x27\xd7\xba\x56\xdd\xe5\xe9\xc0\xb1\x9c\x81\xf8\xb0\xca\xd7\xd5\xe9\xc0\x68\xee\x6e\x3c\x6e\xec\x5c\xd0\x3e\xc5\x78\x3d\xe4\xe8\x03\x2c\xf9\xfd\xbe\x81\x0e\x2a\xa7\x29\x3f\x1d\x08\x65\xd3\xa7\x03\x7b\x20\x54\x5f\x40\x57\x4c\xfe\xe9\x60\x8f\xa9\x71\x5b\x70\x70\xb2\x90\x97\x15\x5f\x1d\x9c\x50\x77\xfd\x5d\x91\x53\x59\x49\x6d\x0d\xc3\xae\x44\xb6\x38\x1d\x5c\x32\x74\x40\x7d\x6e\x3e\x29\xb7\x84\x11\x06\xf1\x9b\x2c\x0b\x05\xe3\x5b\xa9\x30\x62\xd2\x26\x45\xc0\xc4\xb4\xef\xdd\x38\xb0\x5c\x57\x78\x91\x15\x04\xcb\x89\xe3\xbb\x56\x78\xe6\x38\xb6\x95\x08\xfb\x95\x87\x50\x31\x73\x7c\xcb\x0d\x10\xc7\x6c\x04\x66\x82\xaa\xa1\xd7\x51\x60\x39\x4b\x8f\x40\xee\xcf\x74\x3d\xb7\x27\xae\x6d\x85\xc1\x84\xc6\x87\x13\x1e\x34\x21\x04\xea\xf2\xb7\x86\x8a\x2f\xbf\xfb\xee\x39\x44\x37\x98\x3e\x4a\x49\x68\xae\xeb\x85\x58\x31\xb0\x2d\x37\xc6\x67\x18\x59\x91\x7f\xed\x04\xb1\x15\xcd\x41\x4e\x64\xf9\x91\xe0\xe5\x04\xcd\x08\xf8\x5f\x75\xf9\x8a\x91\xcd\x69\x88\x4f\x24\x13\x1d\x18\xe9\xa9\x2b\x1e\xf2\x33\x61\x0b\x40\x36\xe3\x69\xc8\xa6\x27\x93\x6e\x90\x49\xf6\xec\xb9\x1b\x37\x64\x9f\x4c\xaf\xf6\x48\x7f\x52\x2d\x11\x3f\xe6\xdb\x9a\x94\x5a\x16\xef\xa5\x16\xba\xbe\x9b\x68\x9d\x3b\x3d\x8d\x98\x1a\x93\xd7\x72\x5d\x2c\x16\xad\x96\xf6\x22\x9f\x50\x06\xdf\xec\xd5\xb4\x9e\xf7\xd8\xc4\x6a\x99\x6e\x5a\x13\x78\x28\x7a\x3f\x8e\xc2\x31\x69\xcb\x8f\xc3\xc4\x76\xc5\x19\x5b\x83\xe3\xfa\x5e\xdc\x07\x93\x79\xb8\x76\x14\x07\x63\x5b\x9c\x41\x4e\x61\xe2\x84\x81\x9b\xe0\x8e\xb5\xa6\xa7\xc0\x64\xc6\xb0\x8f\x38\xc1\x63\x1b\x6a\xec\xe1\xc0\x23\x07\xc8\xfd\xd0\x8e\x1c\xc2\x01\x3b\x52\x38\x1e\x01\xc3\xc4\xec\x24\xf2\x62\x3b\x10\x33\x03\x1c\xf8\x50\x70\x80\xe0\x18\x0b\xcf\xc6\xc4\x20\x00\x2b\xe6\x42\xfb\x39\xfb\xf7\x01\xcb\xe7\x1d\xcb\x63\xc7\x30\x9f\x9d\x4c\x49\x2e\x9f\x90\x52\xd8\x63\x1c\xb7\x26\xe7\x64\xb4\x63\x36\x5a\x2f\x0e\x42\x58\xee\x98\x2d\xd7\x49\x6c\x2f\x21\xd6\x5d\x37\xb4\xfc\x00\xd2\xf5\xc5\x0c\x77\xbe\x67\x25\x76\xe2\x83\x63\x03\x87\x0b\x2b\x77\x12\xcf\x83\xe1\x1b\x0b\x19\xd0\x33\x83\x1c\x03\x3c\x33\xe4\xd0\xc3\xd1\xca\xcc\x58\xcf\x84\x76\x34\x99\x72\x37\x08\xef\xc9\xbd\x63\xce\x94\x7b\x28\xfa\x32\x7a\x44\xce\xec\x49\x3b\x72\x6e\x3d\xca\x94\xb8\x03\xa2\x9c\xc0\x77\x3c\x1f\xbc\xd8\x08\x23\x89\x13\x43\x66\x04\x8e\x03\xd8\x03\x81\x1d\x2b\x8e\xbd\x30\xf2\x90\x52\x23\xcb\xb3\xed\xc0\x27\x31\x79\x16\xaa\x6f\x07\xe1\x84\xa0\x51\xe4\x86\xb6\x0b\xa8\x6f\x39\x0a\x0a\x14\xb1\xe5\x87\x89\xef\x13\x18\x24\x37\x83\x63\x70\x98\xd8\x0e\x89\x14\x4b\xdb\xbe\x1d\x13\x34\xb1\x42\x2f\xf6\x3c\x92\x68\x04\xc4\xae\xed\x3b\x40\xe1\x31\x45\x49\xe8\xb2\xa0\x3d\x37\x0c\x3c\xa8\x90\xe2\x86\x1b\xc7\x01\x0d\x4e\x70\xeb\x01\x0d\xa8\x8a\xf8\x16\x93\x60\xb1\x89\x17\x79\x91\x7e\x1c\x58\xbe\x13\x78\xa1\xcf\x38\x82\xc0\x81\x75\x3a\x1e\x96\x06\x39\xb6\xcf\xeb\x85\x11\xc0\x34\x13\x4c\xdb\x89\xed\xfb\x26\x15\x0e\xac\x1a\x33\x43\x27\x61\x3e\x92\x3d\x50\xdf\x0a\xa2\x06\x85\x01\x26\x23\x50\xec\x99\x50\x17\x6b\xb4\x50\xdb\x03\xd7\x2e\xcb\xd8\x0f\x22\xc7\xf7\x14\x15\x51\x1c\x06\x21\x24\xe4\x27\x40\x11\x3b\xa1\xc7\x14\x07\xa1\x13\x45\x09\x43\x6d\x96\x45\x1f\x0a\xe6\x94\x9a\x18\x85\x1d\x27\xd0\x09\xc0\x58\x0f\x36\x17\x87\x2c\x89\x38\x84\x68\x30\x18\x4b\x23\xbe\x80\x8c\x3e\xd4\xb3\xdc\xc8\x83\xd2\x08\x45\x07\x76\x11\x19\x14\x71\x26\x6d\x08\xea\x41\x83\x18\x4e\x10\xdb\xae\x4f\x50\xdb\x8a\x81\xc0\x63\x14\x89\x15\x25\x71\xe4\x78\x63\x2c\x05\x03\x50\x82\xf3\xc9\x9c\x12\x17\x06\xe7\x24\x31\xb4\x4e\x36\x02\x28\x94\xe6\x26\xf0\x23\x40\x3d\x2b\x0a\x89\x3f\xf2\x78\x08\xce\x09\xc3\x18\x51\x2b\x8e\x41\x50\xe2\xc5\x20\x19\x86\x1a\x46\xb1\xef\x38\x80\xfa\x56\xac\x48\x86\x15\x5b\xe0\x1f\x21\x0c\x50\xa7\x31\x96\x19\xe5\xb2\x24\x09\x02\x98\x16\xe4\x04\x43\x4d\x82\x04\xb2\x0f\x3d\x2b\x44\x20\x80\x25\x3b\x91\xdf\xda\x77\x08\x93\x8d\x1d\xb8\x1a\xa0\xf0\x39\xb2\x58\x72\x86\xc8\xb3\x3c\x60\x0e\x40\x72\x64\x5b\x88\x1d\x51\x04\xae\xa3\x04\x36\xe4\x25\x31\xd6\xc3\xbc\x10\xcb\x21\x06\x3b\xf0\xce\x20\x46\xe0\x06\x0a\x78\xb6\xe7\x93\xfa\x80\x22\x71\x2d\x38\x40\xe4\x12\x38\x04\x4f\xb4\xa0\x20\x01\x44\xa1\xed\x45\xe0\x3a\x0c\x30\x18\xbe\x1d\xa1\x94\x0d\x98\x0a\x07\xcb\x85\x3e\x10\x33\xd3\x33\x17\x61\x1b\x52\x76\x22\x86\xba\x4a\x7b\xae\x93\x58\x41\x02\x05\x87\x63\x62\x09\x8c\xc2\x7f\x85\x0b\x27\x73\x42\x97\x79\xee\xa0\x67\x50\x10\x14\x49\x2e\xfe\x28\x38\x09\x1a\xa5\xce\x7a\x60\x08\x8e\x24\x14\x08\x82\x46\x01\x02\x1c\x41\xb1\xb6\x43\x5a\x15\x64\x7c\x5e\x04\x93\x83\xb5\xd8\x0e\xa6\xa9\x28\x42\x11\xc5\x75\xc8\xfb\x00\x86\x3c\x95\x29\x93\x0b\xd8\x30\x8c\x18\xe6\x62\x7b\x56\xa0\x02\x03\x79\x91\x17\xb9\x91\x13\x98\xd0\x19\x05\x09\x1f\xd6\xe0\xed\x0c\x86\xb1\x7b\x08\x3c\x51\x0f\x71\x68\x5b\x24\x62\xd7\x33\xa9\x38\xf3\x28\xc4\xd9\x90\x09\x74\x0d\xbb\x8f\x12\x32\x01\xc4\x5a\x0a\x5b\xc8\xb1\x88\x1b\x64\xd6\x60\x21\x41\xa8\x85\xe4\x42\xdf\xf5\x62\xc8\x9e\xe2\x08\x7b\x75\x0f\xe8\xc2\x93\xa1\x68\x87\x10\x18\x60\x1b\x2e\x49\xb4\x09\x13\x2d\x06\xb8\xca\x71\x4c\x1a\x70\x19\x29\x82\xcf\x0c\x8a\xa1\x10\xdf\x6f\x54\xcd\x81\xca\x8b\x82\x70\x1c\xc2\x5b\x12\x65\xb3\x86\x28\xb0\x3c\xcb\x2b\x09\x9c\xc4\xa7\xbb\x99\x21\x54\x7e\x08\xc1\x7b\x21\x06\xf7\x10\x90\x96\x90\xac\x20\xb5\xde\x6a\xa4\xd2\xc8\x87\x9e\x61\x57\xca\x28\x7c\xd6\x33\xea\x0f\x1b\x43\xf1\x94\x46\x46\xb1\x09\x24\xa7\x52\x46\x7c\xd6\x41\x11\x86\x1b\x8b\x38\x33\x6d\xb0\x03\xcf\xc8\xfa\xe3\xd0\xb3\x49\x68\x1d\x98\xe2\x3f\x9e\xb8\x31\xdc\x03\x71\x05\x9e\xe3\x51\xe1\xe9\x20\x6d\x44\xbe\x8f\x48\x4e\x2e\xef\x37\xb9\x09\x31\x26\x04\x6f\x36\x99\xb1\x43\x15\x07\x14\xe7\xd8\xe0\x0d\x09\x2b\x81\x37\xa2\x92\xf1\x34\x5e\x03\x9a\x20\x6b\x28\x07\x9b\x19\x60\x72\x36\x57\xc7\x04\x87\x02\xb6\x32\x35\x17\x97\xe4\xfc\x01\x48\xf3\xc9\x47\x7d\x38\xbf\x8b\x68\xa4\x9d\x1f\xd9\x0d\x41\xd1\x46\xda\xe3\xc0\xeb\x34\x63\x03\x0a\xe3\x6e\xe2\xab\x20\xdd\x30\xb7\x2f\xc5\x3e\x92\xb8\xe9\x67\x20\xf8\x4d\xc4\x65\x51\xae\x4e\x07\xed\x4b\x89\x11\x82\x06\x12\x1b\x07\x43\x44\x2a\x18\x1c\xff\x1c\x0a\x7e\xc7\x31\x9a\x38\x80\x1e\x8a\x6e\xf8\xc4\x1c\x3f\x31\x27\xec\x14\x06\x5d\xa5\xdd\x5e\x70\x13\x44\x8d\xec\x6e\x0b\x94\xe5\xb2\xab\xbc\xa9\xb9\xdc\xad\xbc\xdd\xc0\x64\x66\x6f\xe9\xdd\xcc\xa0\x8d\x89\x79\xba\x39\x1d\xf0\x76\x59\x0f\xfc\xb7\x22\x5b\x37\xf0\x07\x5d\x8c\x03\x4f\x47\x99\xe1\x5e\xc3\x36\xa0\x1a\xf4\x29\x90\x6f\x28\x90\xaf\xc8\x64\xf0\x00\x09\x09\x5e\xa5\xae\x5f\xb9\x5e\x32\x47\x1c\xa6\xe6\x8a\xa0\x13\x98\x78\xa8\x2f\x79\xc0\xcf\x5c\x0b\x04\xef\xc8\xb3\xe9\x01\x57\x28\x1e\x66\x7b\xaf\x48\x6f\xe8\x92\x62\x46\xec\xf1\x7f\x91\x9a\xad\x08\xf8\x6d\x4f\x8b\x45\x96\xcc\xb3\xcf\x70\xa5\x4c\x8a\x1a\x29\xa8\x3d\x16\x11\xf5\x51\x08\xd8\x0e\xf5\x79\x6e\xc4\x97\xaf\x60\x28\x67\xed\xa4\xdf\x1e\xed\x7e\x20\xf8\xdf\xab\xf7\x31\x51\x37\x9d\xcf\x5e\x03\x74\x3c\x6d\x42\x63\xd1\x5e\x1e\x3e\xe8\x88\x7a\xe8\x54\x3f\xd4\xb3\x98\x4f\x1a\x0d\xdb\xcd\xff\xca\x46\x4c\x45\x50\xfb\x03\x1d\x39\x3e\x42\x22\x77\x04\x31\x19\x48\xec\x47\x11\x77\x04\x94\x8f\xbd\x24\x41\x7a\x27\xb0\x8f\xc0\x49\x45\x7e\x82\xf2\x1b\x3f\xb1\xa7\x2c\x04\x05\x38\xaa\x09\x03\x7a\x46\xb5\x50\x82\x70\x1a\xf7\xc0\x33\xae\x9c\x10\x44\xa8\x5c\xee\xc0\x30\x3d\x20\x41\xa9\xd7\x2e\xe7\xbb\x26\xb0\xa3\xe8\xac\x83\x3a\x08\x3d\x8e\x1f\xa8\xc8\xbc\x0f\xea\x50\xca\x8f\x11\x4f\xc6\xa8\xec\x7c\x94\x61\x68\x34\x24\x9a\x6b\x0e\x97\xa8\x52\x7c\x4a\x7f\xfd\x27\x67\x9a\x9b\x80\x78\xec\x3f\x9a\x11\x0d\x81\x8d\xaa\x37\x1a\x4f\x10\x21\x51\x71\x81\x76\x57\x4e\xd0\x06\xda\x63\x72\x16\x64\x2d\x24\x05\xd1\x93\xa7\x0e\x5e\xa5\x9c\xd7\x88\xd7\xce\x47\x3a\x3a\x07\xa6\x8e\xca\xc0\xe6\x46\x16\x37\x1c\xf5\x51\x16\x27\x21\x47\x72\xdc\x03\xad\x1f\x53\xae\x12\xc4\x24\x52\x1f\x5a\x4d\xf0\x4b\xfe\xea\x52\x39\x4a\xe9\x8a\x6e\xcf\x28\x30\xf3\xc5\x2e\xce\xf6\xc6\x24\x2b\x4a\xfc\xcf\x6a\x80\x10\xd3\x63\xdb\x73\x48\x9c\x89\x8f\x0e\x98\x13\xdd\xcc\xa7\xd0\x09\x41\x38\x04\x0e\x20\x3c\x2e\x04\x00\x4d\x7c\xd4\x92\x81\xd2\xbe\xad\x0a\x28\x44\x7a\x48\x14\x35\x48\x63\x13\x0c\x9d\x21\xd4\x3b\x08\xf4\x7e\x4c\x36\x81\x45\x14\x98\x12\x40\x88\x5a\xd9\x45\xe5\xc2\xe5\x2f\x17\x0d\x28\xff\x5d\x2a\x63\xa9\xf8\x41\x55\xe5\xa9\x5a\x72\x86\x9c\xee\xa2\xa8\x0a\x63\x34\xbc\x10\x9b\xcf\x99\x0e\x6d\x05\x44\xc2\xcd\x04\x52\xa0\x40\x19\x00\xf3\xb5\x51\x47\xf3\xed\x8c\x9a\x2a\x1f\x91\x11\xc2\xe2\xc7\xe8\x31\x42\x64\x50\x3b\x61\x14\xa1\xea\x1b\x00\x8d\xb0\x2e\x73\x97\x50\xc7\x89\xaa\x05\x53\xc1\x34\xc4\x1f\x68\xb0\xa6\x82\xea\x67\x3b\x42\x26\xf7\x99\x62\x7f\x0f\x14\xf1\x95\xea\x19\x8f\x51\x74\x60\x64\x7a\xcd\x9e\x09\x85\x03\xb5\xd0\x30\xa6\x1e\xd7\x65\xd1\xc7\x64\x9f\x8e\xa2\xc2\x0b\x90\x45\x69\xb0\x47\xed\x0d\x15\xe1\x80\xa2\xb7\xe0\xba\x9a\x9c\x29\xd6\xb2\xe8\x43\x7d\xdd\x85\x11\x7b\xd0\xa3\x47\x9e\x10\xd3\x1e\x14\xd7\x60\x01\x35\x2c\x5e\x82\x16\x1c\x50\xf4\x18\xaa\x8c\x33\xa1\xa8\xe8\x13\xee\x0f\x67\x3d\x28\xac\x52\xd1\x66\x92\x86\xe2\x5e\x37\x45\x41\x02\xda\x13\x14\x60\x70\x2e\xd4\x23\xa1\x6e\x5e\x71\xe9\xfb\x5c\x20\x90\x4a\x94\xd4\x50\x76\xc1\x87\x5d\xdf\xf6\xb8\xe5\x0b\x55\x81\x10\x72\xfd\xe4\x79\xd4\x56\xc3\x1c\x43\xd7\xf7\x51\x9f\x86\xd4\xef\x04\xdc\x75\xd0\x7e\x42\xa8\x0a\x7e\xb4\x25\xd0\x95\xef\x70\xe1\x61\xa3\x8a\x21\x72\x23\x14\x86\x31\xa2\x4f\xe2\x72\x67\xa7\x64\x33\x8b\xa1\x6e\x1f\x75\x8b\x43\x50\xb4\xa8\xaa\x2d\xa3\xdd\x83\x28\x70\x20\x56\x40\xdd\xc6\xb2\x13\x18\xab\x87\x6a\x9f\x74\xe1\x11\x5a\x55\x6a\x41\x2d\x09\x10\x87\x44\x2f\xd2\x62\xa0\xba\x19\x72\x61\x68\x93\x8a\x7d\x14\xbb\xd4\x68\x52\x52\xb4\x23\x2a\x39\x31\x9a\x37\x3a\xec\x66\x17\x00\xa1\x27\xc2\x33\x34\x76\xdc\x47\xc6\xb4\x1e\x3c\x1d\xd6\x4c\xbe\x06\x7b\x77\xa9\xee\x87\xc9\x26\xbc\x91\x40\x54\xb8\x5c\x7f\xc5\x8a\xe1\x19\xf5\xf7\x90\xaf\x43\xb5\x3e\xc0\xbe\x52\x1b\xb5\x91\x10\x96\xa3\x07\xbb\xba\x33\x84\x2f\xc2\x67\x03\xbf\x07\x3d\xa3\x4e\x2c\x42\x09\x97\xc4\x8f\x82\xa9\x5c\x6b\x1a\x70\x03\x0c\x77\x8d\x59\x40\xdc\x1a\xda\x8e\xda\x37\x72\xdb\xad\x08\xb4\x83\x36\xfa\x20\x3b\xe0\x76\x3f\xd0\xd1\x03\x21\x91\x8a\x5c\xde\xe3\x80\x61\x2b\x0b\xa6\x2e\x92\xb6\x2d\xa8\xe8\x0c\xd0\x66\xa9\x78\xe0\x40\x77\xb6\xcb\x8d\xa1\x01\x05\x06\x5d\x54\xf6\xc0\x68\xff\xb8\xd1\xa6\x90\x62\x20\x46\xb7\x1b\xa3\x3b\xf4\x4c\x1a\xce\xc8\x92\xa0\x67\x78\x21\x37\x43\xb1\xea\xb3\x67\xc4\x28\x35\xc9\x21\x95\xbe\x14\x8b\xb8\xd2\xe6\x7e\x21\x81\xc1\xab\xae\xce\x51\x01\xa1\x07\x85\x76\x75\xe3\xd4\x03\xa3\xe0\x6d\x9a\x8b\x16\xb1\x43\x81\x54\x79\x8c\x41\x05\xb5\xc0\x91\xa2\xf8\xac\x23\x99\xf4\xd8\x6e\xf7\x80\x3d\xb4\x73\x8c\x82\xf6\x0e\xf4\xd6\x40\x27\x0a\x40\x95\xc0\x7c\xdf\xa5\xea\x9f\x77\x19\x3a\xb1\xaa\xc7\xb4\xbd\x10\x84\x5e\xd2\xc7\x01\x3d\xe9\x56\xcd\x5c\x90\x94\x8a\x24\x4b\x3d\xb5\xef\xb6\x46\x44\xfa\x77\x23\x9b\xf2\x8e\x4f\xc8\xd5\x3e\x89\x09\x0d\xc8\x8d\xa9\x0f\x38\x33\xc1\x51\xbb\xeb\x70\x66\x18\xa2\x01\x9e\xc5\xf0\x21\xd8\x7e\x42\x19\xae\x03\x93\x91\xc1\x90\xa9\x77\xa3\xfd\x8c\x44\xed\x51\x79\xb4\xf1\xef\x71\xf7\xc3\xad\xbf\xb6\x2d\xf8\xac\x87\x86\x1a\x0d\x26\x6c\x19\x3e\xa0\x14\xe8\xd9\xec\xcd\x81\x42\x48\x77\xa0\x31\x51\x6e\x35\xa3\x5b\xca\x03\x2a\x00\x78\x20\x3e\x20\x71\xc2\xb4\x10\x4d\x5c\x6a\xe3\x22\x81\x1e\x0e\x0e\x49\xbb\xc2\x88\xa4\x4e\xe3\xe9\x33\x80\x11\x83\x22\x97\x36\x7c\xa8\x86\xd1\x83\x23\xda\xe5\x43\x3e\x49\x54\x30\x56\xab\xee\xcd\xa4\x66\x9b\x33\xa1\x13\x8b\x6d\xa5\xd7\x94\x82\xfb\x5e\xa7\xec\x2f\x3f\x11\xef\x20\x7a\x44\xc4\xb1\x70\xdd\x4f\xf7\x3f\xe6\xf8\x89\x39\xe1\xf3\xfa\x9f\x9f\x36\x22\x2d\xcb\xe2\x66\xb7\x07\xda\x6e\x26\x0c\x7f\x84\xca\x09\x65\x11\x44\xbe\x09\x32\x37\xea\xa5\xc3\x7e\xff\x62\x4c\x59\xa5\x75\x99\x7d\x18\xd1\x5e\xae\xe3\xf1\xdb\x1f\x0c\x47\x30\x15\x1e\x34\x44\x9b\x43\xe8\xc6\xbd\xe0\x70\xb7\x56\x86\xc4\x40\xc4\x6a\x42\x4e\x86\x26\x07\xd1\x1d\x11\x68\x39\x41\x74\x8e\xdd\x48\x7f\xe4\x8e\x6f\xf9\x8e\x3f\x71\xa9\x7e\x0b\xc4\xbe\x3b\xa1\xee\xf6\x34\x1c\xc4\xfb\x8b\xe2\x66\xbd\x9f\xfb\x05\x9e\xfc\x5e\xfc\x4f\xfa\x02\x20\x9b\x4d\xbc\xff\x6f\x01\x9c\x4c\x9b\x97\x81\x27\xf4\xf2\x91\x2f\xd4\x31\x33\xf5\x78\xe9\xa8\xf1\x77\x77\x25\xbd\xdb\x14\x7f\xc8\xc6\xe2\x0f\xf3\x72\xbb\xba\x10\x47\xa7\xc2\xfa\xb6\xc4\x58\xbe\xbd\xbf\x3f\x49\xc5\xb2\x94\x97\xa7\x03\x7d\xe4\x51\x0d\xb3\xce\xb2\xf5\xfb\xfb\xfb\xc1\xb3\x3e\xf4\x5c\x7e\xa8\xe9\x38\x64\x0a\x78\x76\x29\xd6\x84\x59\xd8\xf7\xf7\xd3\xbb\x3b\xb9\x5e\xdc\xdf\xeb\x0f\x45\xa2\x22\x42\xbd\x8d\x55\x84\x9d\xd0\x39\x19\xfd\x32\x33\xbb\x16\xf3\x3c\xad\x2a\x48\x49\xd6\xa9\x56\x00\x83\x49\x83\xfa\xd0\x5f\xab\x97\x6a\x93\xae\xcd\xf1\x7c\xbe\x0a\x2e\x92\xad\x37\xdb\x5a\xd4\xb7\x1b\x38\x26\xbd\x6b\x1e\x88\x4d\x9e\xce\xe5\x92\xdf\x78\x71\x9f\x57\xd3\xdb\x50\xdd\xf3\xf1\x75\xb1\x7e\x2f\x6f\xb7\x9b\xee\x85\xf0\x10\x9e\x46\xf8\xf5\x5a\x77\x77\x13\x01\xde\xac\xf3\xa2\xc8\x2b\xc5\x0b\x08\x20\x73\x78\x48\x80\xc0\xe5\xb2\x00\xf2\x2b\x89\xa5\xd5\xc1\xa2\xd3\x81\x35\x6d\xdf\xe6\xee\x1c\x71\xe8\xfa\xd0\x4f\x10\xae\xce\x85\x29\xc2\x9b\x6b\x75\x24\xb4\xb9\xbb\x4e\xf3\xad\x24\xad\x59\xef\x18\x42\xca\x6a\x42\x05\x11\xbb\xc3\x4d\x33\x48\x0f\x49\xf7\xf0\xa2\xcc\x00\xc4\x3f\xfb\x36\x9d\xbf\xa7\x63\x03\x79\x56\xd1\x41\x63\xd2\x77\x87\x4d\xe6\x95\xfc\x08\x1e\xa1\x0e\x7b\x35\xe8\xfe\xf8\x5b\x86\x4e\x9d\x1c\x92\xde\xc2\x3f\x7b\xa1\x2f\x04\xc0\x3b\x68\x1b\xc3\xd9\x7b\x4b\x2c\xfc\xa5\xcc\xf8\xa4\x54\xbb\xb8\x3e\x08\xf7\x28\x05\xc5\x7a\x9e\x67\xf3\xf7\xa7\xc3\xd5\xfb\x45\xc6\x8a\x7e\x23\x6f\x84\x7a\x1b\x7a\x32\x55\xa3\x1a\x99\xe5\xe9\x85\xcc\x1f\x45\xf5\xec\x27\x3e\x0e\xd6\xd3\x9a\xda\x66\x5a\x6d\xf3\x3a\xdb\xe4\xf2\x81\xc2\xd5\xc6\x06\x48\xe0\xd3\x05\xa7\x43\x75\xa0\x8c\xf6\xab\xaa\x11\x9d\x44\xb7\x68\x7e\xc5\xc6\xc7\x6b\x7f\xdc\xd2\x75\x50\x37\xce\xa4\x0d\x1e\x5a\x6d\x27\x33\xc4\x87\xec\xba\x89\x14\xcd\x95\xe1\x72\x5a\xb1\x8d\xd7\xa9\x03\x68\x69\x99\xa5\x93\x85\xac\xe6\x65\x76\x21\x17\x17\xb7\x0f\xbd\xb0\x6e\x4e\x49\xf3\x4d\xd9\x1a\x33\xb2\xd4\xc9\xd4\x68\x61\xcd\x26\xbb\x09\x2f\x7f\xa4\x33\x35\xa7\x64\xc3\xd0\x05\x1f\xf3\xfc\x9a\x0f\x60\x9c\xa6\xd5\x7c\xd0\xd0\xc5\x87\x87\xf8\xf0\x8d\x3a\x9c\xf1\x8c\x8f\x62\xe8\x87\x75\xb1\x69\xcf\x4b\x38\x6c\xb0\xfa\x18\x85\x15\xd0\x5d\xff\xc0\x06\x1d\xc1\xfe\xb6\xf8\x80\x48\x4c\x9b\x6a\x2e\xaa\x14\x17\xb1\x58\xa0\x2c\xf3\x02\xd4\x61\x09\x50\xd3\x77\x02\xf8\x40\xc6\x91\xa2\xf0\xcb\x36\x69\x82\x1b\x3c\x7c\xa6\x62\xab\x49\x82\x3a\xf2\xf3\xfb\x52\x61\x24\xaf\x3e\x1d\xad\xbb\x1c\x1c\x7c\x44\xba\x7b\xa4\xaa\x65\x49\x67\xd9\x0d\x24\x9f\xa9\x31\x3a\xa7\xf4\x38\x4e\x3a\x35\xf3\x38\xce\x66\x70\x73\x50\x69\xf0\xd8\x22\x75\xf6\x31\xc2\xd5\x11\x7f\xb9\xf8\x67\x16\x32\x06\xe0\xb2\xec\x2e\x7b\x26\x4c\x07\x84\xf6\xd9\xf3\x82\xe6\x2f\xcc\xfb\x07\x84\x5b\x56\xc7\x4d\xdf\x6b\xe9\xb4\xdd\xe0\xd9\x9f\x0a\xb1\xdd\xf4\x5c\x94\x96\x37\x19\xe8\xe1\xff\x7a\x45\x07\x37\x8f\x77\xc0\x0f\xf9\xfa\xdc\x71\xc6\x00\x83\x7f\x8a\x12\xaa\x22\xb0\x5e\xae\x51\xd9\xc8\x2e\xc1\xd5\x65\x83\x84\x03\xdb\x1e\xde\x1f\x13\x49\x13\x9f\x5f\x57\x2f\xb2\xb2\xc1\xa7\x4f\x51\x35\x8e\xa2\x9c\xa3\x71\x15\xe7\x13\x9e\xe2\x39\x54\x58\xed\xf5\x0e\x7d\xbe\xa9\xe7\x19\x26\x21\x66\x76\xfa\x97\x68\xa0\x57\xbb\x9e\xeb\xed\xa5\x21\x53\x12\x7e\x84\x82\x2e\x73\xed\x18\x06\xb9\x27\xe6\xed\x14\x5c\xd6\x4f\x3f\x9e\x19\x95\x96\x75\x26\xd3\x4b\x55\x63\xf5\xad\xc7\x14\xff\x7e\x91\x93\x21\x2c\x52\xa4\x0c\xe5\x49\x83\x89\xb3\xd7\x5e\x1e\x88\x69\x77\x1e\xd5\x14\xf0\x6b\x22\xea\x84\xdc\xff\x59\x0b\x00\x45\x74\xff\x00\x9b\xc1\x72\x43\x1a\x9c\xf6\x1c\x5e\x2d\xfe\x21\xd2\x4b\x94\x59\x2f\x37\xc5\x7c\x29\x7a\x4b\x3e\xb4\x59\x0a\x03\x7c\x4c\x91\x2e\x98\x8e\x06\x8b\x12\x90\x71\x4b\x5f\xc9\x59\xed\xa1\x64\x97\xaf\x07\x8b\xfc\xf7\x7f\xfe\xd7\xc7\xc8\xdf\x3b\x87\x8b\xdd\x3f\x18\x05\x48\x4f\xad\xfa\x3c\xb9\xa1\xd9\x2f\x8d\xda\xa3\x94\xa4\x76\xf2\xb6\xdb\x11\x38\x60\x65\x8f\x05\xae\x94\x9e\x0f\x8f\x45\x29\xeb\x6d\xb9\x16\x97\x29\x68\x1f\x3e\x53\xe3\x59\xfd\xfb\xd0\x2d\x64\x2e\xeb\x7f\x02\x9d\x1a\x6f\x58\x93\x2e\xd1\x1f\x8f\x0f\x86\x34\xf0\xa4\x8b\x90\xb8\x21\xe6\xfb\x55\xc5\xc9\xb4\x29\xe9\x4f\xa8\x6e\xd8\xd4\xfc\xf8\x3a\x2d\x85\xaa\xae\x5f\xe6\xe2\x14\xd5\xdf\x7c\xbb\x92\xeb\xda\x42\x95\xfc\x32\x97\x74\xf9\xed\xed\xeb\xc5\x48\x57\xe0\xc3\x43\x3a\x29\x7a\xd0\x4c\xb0\x2e\x31\xbc\x1a\x69\xe0\x76\xcd\xd2\x15\x4d\xb1\xce\x47\x40\xd5\x0a\x7f\x07\xea\x76\x12\x97\xc3\x16\x42\xda\x6a\x74\x68\xd5\xc5\x59\x71\x23\xcb\x59\x5a\x49\x8d\x87\x27\x40\x14\xab\xca\xa4\xe7\xef\x5b\x59\xde\xbe\x03\x78\x5e\x17\xe5\xf3\x3c\x1f\x0d\xeb\x92\xeb\x32\x4d\xd2\x01\xcf\x00\x41\xe5\xcb\x74\xbe\x1c\x35\xc4\x8c\x64\xde\xd0\x71\x00\xc3\x18\x7d\xf1\xf7\xf6\x16\x33\x2c\xae\x04\x2d\x5d\x08\x62\xb9\xe1\xf0\x58\x3f\x54\xaa\xd1\x77\x5a\xc6\x44\x18\x5b\x08\x49\x0a\xb3\x7b\x34\x8d\x86\x7c\x8a\xbb\x21\xa7\x1d\xfc\x73\x4a\xa3\xd5\x34\x8b\xda\x87\x99\xfa\x82\xd8\x47\x04\xc0\x94\xea\xb9\x56\xb6\x5e\xc8\x0f\x6f\x2f\x47\x20\xfc\x8b\xd3\x53\x31\x71\x3e\x8f\x81\x7b\xf6\xaf\x8f\x0e\xa5\xb2\x77\xd8\xe3\xf0\x5e\x11\x70\xdf\x53\x67\x5e\xcc\xd3\x1c\xa1\xe4\x85\x76\xf6\x91\xa4\xaf\xc3\x81\xa8\x31\xca\xd0\x86\x18\xa2\x58\x9a\xec\x89\x53\x10\x4b\x5f\x1a\xba\x44\x2f\xb5\x68\x69\x36\xc5\x7a\xdf\x6a\x7b\x41\x12\x42\xbd\x4f\x4b\x00\x0d\x6c\xef\x79\xad\xbf\x00\x39\x1a\x36\x41\x66\x78\xa8\xc5\x43\x6b\x65\xd5\x9b\xf4\xcd\x68\x71\xd8\x22\xde\x41\x61\x50\x62\x0a\xf5\xc1\xb4\x7d\x7a\x56\xff\xee\x70\x23\x16\xac\x29\xda\x1a\x7a\x57\xd3\x77\xef\x46\xbf\xfc\x0a\x67\x5e\x50\xff\x30\x70\x27\x8b\xec\x2a\xab\x07\x63\xb1\xc2\xf8\x65\x0f\x72\x8b\x6e\x0e\x80\x35\xcc\xb8\xcc\xe6\x00\x2c\x8b\x6d\xd9\x9f\x93\xad\xc1\x68\x0f\x54\x49\x54\xd7\x0b\x03\x64\x6a\x86\x24\x46\x02\x39\x43\x67\x00\xc2\x9e\x97\x65\x7a\x6b\x6d\xca\xa2\x2e\xa8\xdb\xb1\x2a\xfa\x02\xab\x05\x42\xf3\xd1\x1e\x6f\xae\xbe\xbd\x3d\x4f\xaf\xa8\xbe\x1c\x0d\x08\xc9\x40\x4b\xb5\x41\xd8\x7a\xd0\xae\xda\x31\xac\x59\x1c\xd8\x7e\x2a\xf3\x1f\xd2\x12\x58\xe8\xa8\x37\x7c\xbb\x31\x96\x9d\x47\xa3\x8a\x2f\xcd\x50\x00\xc8\x95\x44\x2c\xc4\xac\x1b\x98\x51\x71\x63\xd1\x4a\x34\xd9\x52\xfd\xb2\x55\x6d\x2f\x2a\x25\x62\x87\x0e\xb0\xe3\xa7\xc2\xf8\x9f\xa9\xe7\x41\x50\xa3\xa8\xd0\xe0\xb0\x60\xcb\x59\x3d\x1a\x7e\x3d\x6c\x06\xb6\x2b\xbf\xe1\x6f\x54\xb0\xde\x15\xe1\x07\xf4\x5d\xbf\x51\x86\xd9\xf6\xb1\xc8\xc4\x89\xe8\x21\xb5\x72\xb9\xbe\xaa\x97\x78\xf2\xf4\x69\x6b\x1c\x7d\x6c\xb4\xae\x39\xe5\x97\xec\xd7\x66\xfd\xd3\xa1\x96\x8e\xb2\xb2\xfe\xbc\x5f\xec\x5f\xd9\x19\xfa\xa2\x68\x2c\x4f\xec\x0c\x76\x7e\xed\x7b\x8e\xf8\xa3\xa8\xcb\xad\x14\x47\x82\xbe\x8d\xb7\x00\xd7\xaf\x67\xc5\x6a\x03\xd7\x5d\xd7\xa3\x07\x73\x0f\x1f\x1a\xf2\x7d\x3f\x38\xeb\x13\xf5\x9c\x0f\x69\xd2\x61\xa7\x19\x2e\x29\xc0\xe5\xae\x0e\x87\xfc\x60\xb8\x13\x9d\xc9\x96\xf6\x27\x0c\x98\xd8\xac\x41\x6f\x2c\x74\xdc\x68\x61\x44\x28\x58\x11\x63\xa1\xc4\xce\xe1\x54\xcd\xed\x14\x01\x15\xed\x53\x0a\x4d\x9e\x6f\xcb\xf2\x15\xf2\xad\x31\x8f\xb4\x41\x29\xb8\x75\xf6\x91\xaa\x90\x28\x0d\x57\xf3\xe1\xe1\x9d\xd0\x62\xe7\xf9\xcb\x2b\x4c\x6d\xb0\x58\xa5\xe4\x0d\x9d\x91\x1a\x3a\x16\xc3\x94\x66\x1c\xb7\xa1\xb3\xbf\x02\xcd\x5c\x5e\xf5\x33\x83\xb1\x5c\xfa\xd9\xab\xa5\x6a\x31\x45\xdf\x3f\xb1\xda\x3e\xb5\xd2\x2e\x21\x59\x25\x6d\x5a\xf0\xd7\x23\xa8\xf8\x33\xdd\x6e\xbb\xce\x58\x5f\xbf\x0c\xbf\xa5\x45\xff\x9c\xf1\xc7\xf7\xea\xe3\x4f\xea\xe3\x5c\x7d\xfc\xa0\x3e\x5e\xaa\x8f\x7f\x57\x1f\x7f\xc5\xc7\xaf\x9d\x05\x28\x3f\xe2\xdb\x9b\x25\xd6\xe4\xf5\xc4\xb3\x53\xe1\xd8\xae\xdf\x39\x10\x01\xa7\x0a\xa8\x59\x78\xfa\x34\x33\xa3\xbf\x76\x82\x0d\x7d\x47\xfb\xbb\xbc\x48\x6b\x45\x38\x62\xed\x77\xd9\x07\xc9\xdf\x73\x79\x2a\x86\xf8\x7d\xaa\x38\x80\x58\x74\x20\xec\xb1\x6f\x7e\x2f\xc4\x8c\x35\xf4\xcd\xe8\x47\x8d\xb4\x8d\x83\x34\x6c\x70\x68\x86\x89\x8e\x45\x15\x2a\x08\xcf\xde\x10\xb1\xdc\xae\xd2\x35\xad\x8b\xc1\x7b\x75\xc0\x8a\xcc\xd6\x6b\x59\xbe\x3a\xff\xfe\xac\x51\xf3\xc3\x27\x98\xdf\xe2\x32\xb4\xcc\x34\xd1\xdf\x66\x20\x06\xe9\xeb\x8b\xf5\x12\xff\x56\x54\xb6\xf3\x17\xf4\x65\x49\xdf\x13\x44\xac\x2f\x6e\x00\xac\x9b\x08\x7d\xa3\xcb\x5f\x60\x45\xbd\xd9\x15\xc3\xbb\x46\x43\x9b\x54\xad\xc4\xda\x38\xdc\xc0\xf7\x14\x03\x30\xde\xa2\xac\x47\xb2\x6c\x33\x3f\x12\xa2\x02\x58\x2b\x59\x55\x88\xcb\x9a\xc7\x8f\x20\x99\x2f\xe5\xfc\xfd\xa8\x94\xd5\xc6\x2c\x1f\xbe\x20\x80\x55\xbc\xdf\xa9\x15\x04\x83\x29\x17\x53\xb1\xb4\x94\xeb\xae\xb6\x23\x20\x46\x43\x12\xf4\x62\x81\xb2\xff\xcb\xb2\x44\x7c\x21\xb8\xf8\xc7\x3f\xd4\x4c\xb5\x05\x47\xbb\xe1\x28\xb8\x75\x1a\xed\x1b\x1f\x0d\xdb\x43\xa5\xea\x15\x46\xdb\x32\x1f\x93\x7d\xa6\xab\xaa\xa1\xac\x29\xdb\x65\x8d\x3c\xc9\xcf\xef\xd4\xe6\xf2\x11\x9c\xe7\xed\xbb\x73\xf8\x0b\xd5\xe2\x47\x4c\x12\x52\x86\xda\xd0\xe5\x80\x5a\x8d\x34\xaa\xb1\x98\x97\x72\x01\x43\xcc\x50\xfc\x63\x5e\x05\x4b\x44\x53\x87\x64\xbf\x1e\xde\x6b\x46\x59\x4e\xfb\x04\xa8\x37\x49\x0d\x4b\x5f\xab\x04\x85\x2a\x60\xb5\x41\x42\xea\x36\x4f\x85\x59\x94\x36\x75\x65\x2b\x62\xcd\xe3\xd0\x9a\x82\xe6\x3b\x75\x07\x62\x18\x3f\x20\x34\xf6\x88\xff\x6d\x48\x52\x5a\x1d\x6b\x3b\x30\xa5\xb9\x6b\x27\x5d\x2b\xc5\x12\xca\xd1\xf3\xb4\x86\x86\x6b\x10\x4b\x1f\x6d\x34\x9c\xfe\xc7\xf4\x0f\x53\xc4\x19\x33\xcd\xd4\x85\xc1\xd2\x8f\x8c\x91\x23\x01\xcf\xa7\xb0\x50\x17\x43\x8d\xb9\xe3\x0f\x93\xbe\xfe\x9a\xa6\x52\xb5\x6c\xae\x7a\x60\x2a\xb4\xe3\x55\x51\x0a\x3c\x75\x71\x84\xff\xff\x59\x46\xcd\x26\xef\x01\xa3\x44\x0f\x0a\xb9\xcb\xac\x5c\x8d\x86\x2f\x78\x64\x8f\x81\x3f\x0e\x0f\x3f\x41\x9d\x42\x3f\xfc\x1c\xaa\x74\xa4\xf8\x51\x56\x88\x23\xe4\xfb\x6a\x0f\xba\xea\xfe\x5a\x0a\x7d\x3d\x9a\xeb\xc4\x79\x91\x3f\x69\x93\xd3\x76\xfd\x5e\x07\xb0\x58\x7c\xc3\xa1\x5a\x7f\x1c\xb7\xc5\xe6\xb6\x7a\xc5\x2f\x86\x28\x96\xde\x0d\xcf\xb7\xd5\xa4\x5d\x65\x08\x22\x1d\xcb\xb6\xec\xe1\x6e\xa1\xb1\xad\x7e\x94\xe8\x90\xaa\x7a\xa4\x9c\x63\x2c\x98\x37\xf5\x8a\xa9\x52\x2e\xf2\x19\x1e\xd5\x4c\xd6\xf3\x8e\xc4\xdb\x8b\xbf\xa1\xe1\xb2\x50\x56\x64\x57\xeb\xd1\x1d\x5a\xea\x8e\xbe\x76\xd8\x61\xe3\x82\xf4\xef\xbf\xe4\x6b\x15\x9a\xec\x19\x09\xa9\x52\x84\x51\xcf\x39\x16\xea\x7b\xe1\x63\x12\xe7\x15\xfd\x45\x15\x52\x09\x6f\xd2\x35\x1c\x35\x0f\x46\x6a\xa4\x98\x8a\x11\xcd\xb4\x38\x25\x22\x36\x39\x66\x37\xa3\xc7\x3c\xe3\x26\x59\x8d\xd9\x8d\x82\x3f\xc0\x0d\xb2\x4a\xc2\x5f\xaa\x22\xbf\x6e\x5b\xc5\xae\x87\x02\x99\xd0\xce\xf7\x69\xbd\xb4\xd0\x50\x34\x28\x9f\x76\x0a\x1e\x1b\xc8\x8f\x4d\xa9\x1b\x9a\x1a\xfe\xf0\xfc\x7c\xf6\x6a\xa8\x55\x75\x37\x54\xef\x52\x26\x6f\x19\x19\x54\xad\x5b\x1f\x85\x1c\x32\x1e\xea\xfe\x68\x72\x8e\xce\x83\x4c\x41\xff\x39\x1c\x92\xdc\x54\x8d\x7a\x5a\xcc\xd1\x40\x4c\x50\xd1\xcb\x74\x35\xbc\x6f\xa8\xa0\x26\x65\xd4\x48\x11\xb4\x1f\xee\x06\x77\x33\x3d\xb4\xb5\xf2\x5e\x5d\x70\xf1\xf0\x1a\xe5\x30\xc7\x7b\x6d\x00\x94\xea\x47\x3b\xf4\x83\x60\xc7\x3e\x34\x95\x66\x37\x62\x1c\x8b\x6e\xc7\xa0\xcb\x6d\xac\x1c\xad\x58\xd2\x4e\xd0\x55\xef\x2a\xe7\x60\x6c\xbf\x10\x84\x03\xde\xa4\x59\x4d\xee\x86\x3c\x97\x96\x25\x7d\xa9\x55\x7d\xd9\xfe\x66\x29\x4b\x69\x66\xed\xab\x02\xe3\x8a\x1e\x83\x94\x30\xb4\xa6\x7b\x92\x20\x95\x53\xa6\x03\x17\xb4\xa9\x56\x6c\xeb\x06\x4a\x2c\xd9\x36\x3c\xb6\xa5\xf3\x29\xdb\x96\xb8\xdf\x15\xe8\x83\xce\xc3\xd4\xfb\xab\x97\xcf\x5f\xb4\x6a\xbf\x6f\xdb\x88\x8f\x2a\xe5\x77\xd3\x8a\xc9\x49\x4b\xc9\xf1\xe3\x3b\x14\xdd\xfb\xba\x91\x5e\x5b\xa3\xda\x93\x21\x2f\xea\x22\x1d\x6d\xd7\xa8\xb9\xd3\x8d\x1c\xc9\xf5\x83\x9e\x8a\xcd\x93\x93\xe4\xe1\x47\xdc\x44\x65\x79\x9d\x37\x1b\x86\xce\xb8\x40\xec\xdc\xa4\xf3\x37\x0c\xd5\x63\xbe\x97\x75\x4a\x1b\xb8\xe4\x2c\xf4\xbc\x4d\x6a\x66\x96\xdd\x2f\x6f\x2e\xe3\xcb\x5c\x6f\x74\xa0\xae\xd8\x23\xda\x33\x5d\xc3\x91\x54\xdb\x7a\x8e\xfa\x88\x43\xb3\x3b\xfa\xa8\xe2\xec\xbd\x0e\xf2\x71\xb9\x57\x23\xf5\x8a\xd4\xac\xbd\xd5\x9f\xe3\xf8\xc8\x96\x62\xef\x1d\xa9\x99\xf7\x17\xf4\xa7\xa0\x4e\x1f\x0b\x79\xbb\xfb\x1d\x7a\xc3\x42\xed\x78\x30\x19\x86\x33\x2b\x96\xb2\x6e\x9b\x48\xa1\xa6\x8f\x4f\xb9\xc7\x03\xab\xea\x90\x96\x2a\x3f\x77\x53\x0e\x14\x13\x3b\x5b\x46\x5a\xe7\x54\xb6\x93\x86\x5b\xcb\xe2\xda\x65\x44\xa0\x51\xc6\x46\x4e\x80\x69\x33\xa4\x69\x34\x08\x78\xc8\x13\x39\xa8\xf3\xc1\xf4\x76\x69\x4e\xd1\x36\x4f\xfc\xaa\xdd\xb3\xbc\x7f\xc4\x5b\x0e\x3a\x76\xf7\x15\x10\xac\x54\x8a\x74\x4d\xd7\xd0\xf0\xd5\x6a\x8e\xff\x82\x4a\xba\x58\xbc\xbc\xc6\x1d\xed\x12\xc9\x35\xed\x0b\x2c\xca\x94\xff\x1e\xd8\xd0\x8c\x9e\xad\x50\x24\x74\x44\xdf\x10\xa8\x5f\xc8\xcb\x74\x9b\xd7\xed\xee\x66\x1f\x2d\x6f\x10\xf0\xce\x13\x16\x20\x9c\xea\xef\xb4\x0c\x1f\xb0\xf0\x69\x5a\x50\x56\x5d\xcb\xfd\xc4\x3c\xb6\xa6\xfa\xc3\x0b\xff\xca\xb2\xc5\xe6\xff\x88\xfd\xc7\x48\x39\x30\xdd\x4c\x5a\x14\x3c\xce\xf9\xd4\x93\x2c\xf5\xc1\x84\x07\x2e\x7a\x32\x6d\x77\xf8\x4f\xa6\xea\xb5\xc0\xc9\x54\xfd\xf9\xb4\xff\x01\xb4\xa2\xb4\xd6\xee\x4f\x00\x00"),
 		},
 	}
 	fs["/"].(*vfsgen۰DirInfo).entries = []os.FileInfo{
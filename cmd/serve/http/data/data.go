@@ -38,6 +38,9 @@ to be used within the template to server pages:
 |-- .IsDir    | Boolean for if an entry is a directory or not. |
 |-- .Size     | Size in Bytes of the entry. |
 |-- .ModTime  | The UTC timestamp of an entry. |
+| .Tools      | Set if the server can search and make zip files. |
+| .Writable   | Set if the user can upload, mkdir, rename and delete. |
+| .Search     | The search term if .Entries are search results, with paths in .Leaf. |
 `
 
 // Options for the templating functionality
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"html/template"
 	"io"
 	"log"
@@ -13,13 +14,18 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pkg/errors"
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/cmd/serve/acl/aclflags"
 	"github.com/rclone/rclone/cmd/serve/http/data"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/config/flags"
 	httplib "github.com/rclone/rclone/lib/http"
 	"github.com/rclone/rclone/lib/http/auth"
 	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/lib/http/share"
 	"github.com/rclone/rclone/vfs"
 	"github.com/rclone/rclone/vfs/vfsflags"
 	"github.com/spf13/cobra"
@@ -28,18 +34,31 @@ import (
 // Options required for http server
 type Options struct {
 	data.Options
+	Write            bool          // allow uploads, mkdir, rename and delete
+	MaxUploadSize    fs.SizeSuffix // largest upload allowed, -1 for no limit
+	MaxUploadStaging fs.SizeSuffix // most resumable upload data kept at once, -1 for no limit
+	Share            share.Options
 }
 
 // DefaultOpt is the default values used for Options
-var DefaultOpt = Options{}
+var DefaultOpt = Options{
+	MaxUploadSize:    10 * fs.Gibi,
+	MaxUploadStaging: 100 * fs.Gibi,
+	Share:            share.DefaultOpt,
+}
 
 // Opt is options set by command line flags
 var Opt = DefaultOpt
 
 func init() {
 	data.AddFlags(Command.Flags(), "", &Opt.Options)
+	flags.BoolVarP(Command.Flags(), &Opt.Write, "write", "", Opt.Write, "Allow uploads, mkdir, rename and delete")
+	flags.FVarP(Command.Flags(), &Opt.MaxUploadSize, "max-upload-size", "", "Largest upload allowed with --write, or off for no limit")
+	flags.FVarP(Command.Flags(), &Opt.MaxUploadStaging, "max-upload-staging", "", "Most data kept for all resumable uploads in progress, or off for no limit")
 	httplib.AddFlags(Command.Flags())
 	auth.AddFlags(Command.Flags())
+	share.AddFlagsPrefix(Command.Flags(), "", &Opt.Share)
+	aclflags.AddFlags(Command.Flags())
 	vfsflags.AddFlags(Command.Flags())
 }
 
@@ -58,7 +77,49 @@ The server will log errors.  Use -v to see access logs.
 
 --bwlimit will be respected for file transfers.  Use --stats to
 control the stats printing.
-` + httplib.Help + data.Help + auth.Help + vfs.Help,
+
+Each directory can be downloaded as a zip file by adding "?zip" to its
+URL, and searched by file name with "?search=term" which returns JSON
+if the request has "Accept: application/json".
+
+#### Writing
+
+If "--write" is set then the web interface lets users upload files,
+make directories, and rename and delete files and directories, as
+long as "--read-only" isn't set and the access control list allows
+it. These can also be used directly:
+
+- "PUT /path/file" uploads the request body to the file
+- "POST /path/dir/" with "multipart/form-data" uploads the files in the form to the directory
+- "POST /path/dir/?action=mkdir&name=new" makes the directory "new"
+- "POST /path/file?action=rename&to=name" renames to "name" in the same directory, or to the path "to" if it starts with "/"
+- "POST /path/file?action=delete" or "DELETE /path/file" deletes a file or directory and everything in it
+
+Uploads are written to a hidden temporary file next to the
+destination which replaces it once complete, so a failed upload
+leaves the old file in place.
+
+Requests which change things must come from the web interface itself:
+ones whose "Origin" or "Referer" header is for another site are
+refused, as are form POSTs without either header, so other sites
+can't make users' browsers change files. Clients other than browsers
+should use PUT and DELETE, or send an "Origin" header for the server.
+
+The web interface uses the [tus](https://tus.io/) protocol for large
+uploads so they can be resumed if the connection breaks. Make an
+upload by POSTing to the directory with the "Tus-Resumable" header and
+the file name in the "filename" metadata. Uploads are kept in a
+temporary directory until they are complete, and are forgotten after a
+day without progress or when rclone is restarted. Once an upload is
+complete it can't be written to again.
+
+Uploads bigger than "--max-upload-size" or which would go over a
+quota in the access control list are refused. Each user's resumable
+uploads in progress can't add up to more than "--max-upload-size"
+either, and everyone's can't add up to more than "--max-upload-staging"
+(100 GiB by default), to limit the space they take in the temporary
+directory.
+` + httplib.Help + data.Help + auth.Help + auth.ProvidersHelp + share.Help + vfs.Help + acl.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
 		f := cmd.NewFsSrc(args)
@@ -79,6 +140,10 @@ control the stats printing.
 type server struct {
 	f            fs.Fs
 	vfs          *vfs.VFS
+	acl          *acl.ACL           // access control list if set
+	auth         httplib.Middleware // authentication if set
+	shares       *share.Manager     // share links if enabled
+	tus          *tusUploads        // resumable uploads in progress
 	HTMLTemplate *template.Template // HTML template for web interface
 }
 
@@ -90,30 +155,91 @@ func newServer(f fs.Fs, templatePath string) *server {
 	s := &server{
 		f:            f,
 		vfs:          vfs.New(f, &vfsflags.Opt),
+		tus:          newTusUploads(),
 		HTMLTemplate: htmlTemplate,
 	}
+	var err error
+	s.acl, err = acl.New(&aclflags.Opt)
+	if err != nil {
+		log.Fatalf("Failed to load access control list: %v", err)
+	}
+	s.auth, err = auth.NewAuth(context.Background(), auth.Opt)
+	if err != nil {
+		log.Fatalf("Failed to set up authentication: %v", err)
+	}
+	s.shares, err = share.New("http", &Opt.Share, s.shareFs, httplib.URL)
+	if err != nil {
+		log.Fatalf("Failed to set up share links: %v", err)
+	}
 	return s
 }
 
-func (s *server) Bind(router chi.Router) {
-	if m := auth.Auth(auth.Opt); m != nil {
-		router.Use(m)
+// shareFs returns the Fs for share links which can only be to the
+// remote being served
+func (s *server) shareFs(ctx context.Context, fsString string) (fs.Fs, error) {
+	if fsString != "" && fsString != fs.ConfigString(s.f) {
+		return nil, errors.Errorf("can only share files on %q", fs.ConfigString(s.f))
 	}
+	return s.f, nil
+}
+
+// getVFS returns the VFS as seen by the user making the request
+func (s *server) getVFS(r *http.Request) *acl.VFS {
+	user, _ := r.Context().Value(auth.ContextUserKey).(string)
+	VFS := acl.NewVFS(s.vfs, s.acl, user, nil)
+	if root, ok := r.Context().Value(auth.ContextRootKey).(string); ok {
+		VFS = VFS.WithRoot(root)
+	}
+	return VFS
+}
+
+func (s *server) Bind(router chi.Router) {
 	router.Use(
 		middleware.SetHeader("Accept-Ranges", "bytes"),
 		middleware.SetHeader("Server", "rclone/"+fs.Version),
 	)
-	router.Get("/*", s.handler)
-	router.Head("/*", s.handler)
+	// Share links don't need authentication
+	if s.shares != nil {
+		router.Get("/"+share.Prefix+"*", s.shares.ServeHTTP)
+		router.Head("/"+share.Prefix+"*", s.shares.ServeHTTP)
+	}
+	router.Group(func(router chi.Router) {
+		if s.auth != nil {
+			router.Use(s.auth)
+		}
+		router.Get("/*", s.handler)
+		router.Head("/*", s.handler)
+		if s.writable() {
+			router := router.With(sameOrigin)
+			router.Put("/*", s.handlePut)
+			router.Post("/*", s.handlePost)
+			router.Delete("/*", s.handleDelete)
+			router.Patch("/*", s.tusPatch)
+			router.Options("/*", s.tusOptions)
+		}
+	})
+}
+
+// writable returns true if users may be able to change things
+func (s *server) writable() bool {
+	return Opt.Write && !s.vfs.Opt.ReadOnly
 }
 
 // handler reads incoming requests and dispatches them
 func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 	isDir := strings.HasSuffix(r.URL.Path, "/")
 	remote := strings.Trim(r.URL.Path, "/")
-	if isDir {
+	query := r.URL.Query()
+	switch {
+	case r.Method == "HEAD" && query.Get("upload") != "" && s.writable():
+		s.tusHead(w, r)
+	case isDir && query["zip"] != nil:
+		s.serveZip(w, r, remote)
+	case isDir && query["search"] != nil:
+		s.serveSearch(w, r, remote, query.Get("search"))
+	case isDir:
 		s.serveDir(w, r, remote)
-	} else {
+	default:
 		s.serveFile(w, r, remote)
 	}
 }
@@ -121,7 +247,8 @@ func (s *server) handler(w http.ResponseWriter, r *http.Request) {
 // serveDir serves a directory index at dirRemote
 func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote string) {
 	// List the directory
-	node, err := s.vfs.Stat(dirRemote)
+	VFS := s.getVFS(r)
+	node, err := VFS.Stat(dirRemote)
 	if err == vfs.ENOENT {
 		http.Error(w, "Directory not found", http.StatusNotFound)
 		return
@@ -133,20 +260,24 @@ func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote stri
 		http.Error(w, "Not a directory", http.StatusNotFound)
 		return
 	}
-	dir := node.(*vfs.Dir)
-	dirEntries, err := dir.ReadDirAll()
-	if err != nil {
+	dirEntries, err := VFS.ReadDirAll(dirRemote)
+	if err == vfs.EPERM {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	} else if err != nil {
 		serve.Error(dirRemote, w, "Failed to list directory", err)
 		return
 	}
 
 	// Make the entries for display
 	directory := serve.NewDirectory(dirRemote, s.HTMLTemplate)
+	directory.Tools = true
+	directory.Writable = s.writable() && VFS.Check(dirRemote, acl.PermWrite) == nil
 	for _, node := range dirEntries {
 		if vfsflags.Opt.NoModTime {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), time.Time{})
+			directory.AddHTMLEntry(path.Join(dirRemote, node.Name()), node.IsDir(), node.Size(), time.Time{})
 		} else {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), node.ModTime().UTC())
+			directory.AddHTMLEntry(path.Join(dirRemote, node.Name()), node.IsDir(), node.Size(), node.ModTime().UTC())
 		}
 	}
 
@@ -155,14 +286,15 @@ func (s *server) serveDir(w http.ResponseWriter, r *http.Request, dirRemote stri
 	directory.ProcessQueryParams(sortParm, orderParm)
 
 	// Set the Last-Modified header to the timestamp
-	w.Header().Set("Last-Modified", dir.ModTime().UTC().Format(http.TimeFormat))
+	w.Header().Set("Last-Modified", node.ModTime().UTC().Format(http.TimeFormat))
 
 	directory.Serve(w, r)
 }
 
 // serveFile serves a file object at remote
 func (s *server) serveFile(w http.ResponseWriter, r *http.Request, remote string) {
-	node, err := s.vfs.Stat(remote)
+	VFS := s.getVFS(r)
+	node, err := VFS.Stat(remote)
 	if err == vfs.ENOENT {
 		fs.Infof(remote, "%s: File not found", r.RemoteAddr)
 		http.Error(w, "File not found", http.StatusNotFound)
@@ -175,6 +307,10 @@ func (s *server) serveFile(w http.ResponseWriter, r *http.Request, remote string
 		http.Error(w, "Not a file", http.StatusNotFound)
 		return
 	}
+	if err = VFS.Check(remote, acl.PermRead); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 	entry := node.DirEntry()
 	if entry == nil {
 		http.Error(w, "Can't open file being written", http.StatusNotFound)
@@ -0,0 +1,407 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/atexit"
+	"github.com/rclone/rclone/lib/http/auth"
+	"github.com/rclone/rclone/vfs"
+)
+
+// Resumable uploads with the tus protocol - see https://tus.io/protocols/resumable-upload.html
+//
+// Uploads are made by POSTing to the directory, and their URL is the
+// directory with ?upload=ID. The data is kept in a local temporary
+// file until it is all there, then it is copied into the VFS.
+
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination"
+)
+
+var (
+	tusExpiry = 24 * time.Hour   // forget uploads which haven't progressed for this long
+	tusSweep  = 10 * time.Minute // how often to look for stalled uploads
+)
+
+// errStagingFull is returned if the uploads in progress would take
+// more than --max-upload-staging
+var errStagingFull = errors.New("too many uploads in progress")
+
+// tusUpload is a resumable upload in progress
+type tusUpload struct {
+	mu     sync.Mutex // held while data is being written
+	id     string
+	user   string // user who made the upload
+	remote string // where the file goes in the user's view of the VFS
+	length int64
+	offset int64
+	tmp    string // local file the data is kept in
+
+	// These are protected by tusUploads.mu
+	updated time.Time // last time the upload progressed
+	done    bool      // set when the upload is finished or removed
+}
+
+// tusUploads holds the resumable uploads in progress
+type tusUploads struct {
+	mu      sync.Mutex
+	dir     string // local directory for the data, made when needed
+	uploads map[string]*tusUpload
+	sweeper *time.Timer // expires stalled uploads while there are any
+}
+
+// newTusUploads makes an empty set of uploads
+func newTusUploads() *tusUploads {
+	return &tusUploads{
+		uploads: map[string]*tusUpload{},
+	}
+}
+
+// create makes a new upload - call with the lock held.
+//
+// It returns errUploadTooBig if the user's uploads in progress would
+// add up to more than max bytes, or errStagingFull if everyone's
+// would add up to more than maxTotal bytes. Either limit may be -1
+// for no limit.
+func (t *tusUploads) create(user, remote string, length, max, maxTotal int64) (*tusUpload, error) {
+	if t.dir == "" {
+		dir, err := ioutil.TempDir("", "rclone-serve-http-upload")
+		if err != nil {
+			return nil, err
+		}
+		t.dir = dir
+		atexit.Register(func() {
+			_ = os.RemoveAll(dir)
+		})
+	}
+	now := time.Now()
+	t.expire(now)
+	staged, userStaged := length, length
+	for _, u := range t.uploads {
+		staged += u.length
+		if u.user == user {
+			userStaged += u.length
+		}
+	}
+	if max >= 0 && userStaged > max {
+		return nil, errUploadTooBig
+	}
+	if maxTotal >= 0 && staged > maxTotal {
+		return nil, errStagingFull
+	}
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	u := &tusUpload{
+		id:      hex.EncodeToString(id[:]),
+		user:    user,
+		remote:  remote,
+		length:  length,
+		updated: now,
+	}
+	u.tmp = filepath.Join(t.dir, u.id)
+	if err := ioutil.WriteFile(u.tmp, nil, 0600); err != nil {
+		return nil, err
+	}
+	t.uploads[u.id] = u
+	if t.sweeper == nil {
+		t.sweeper = time.AfterFunc(tusSweep, t.sweep)
+	}
+	return u, nil
+}
+
+// expire forgets stalled uploads - call with the lock held
+func (t *tusUploads) expire(now time.Time) {
+	for id, u := range t.uploads {
+		if !u.done && now.Sub(u.updated) > tusExpiry {
+			fs.Debugf(u.remote, "Forgetting stalled upload %s", id)
+			t.remove(id)
+		}
+	}
+}
+
+// sweep expires stalled uploads periodically while there are any so
+// their data doesn't stay in the temporary directory
+func (t *tusUploads) sweep() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(time.Now())
+	if len(t.uploads) == 0 {
+		t.sweeper = nil
+		return
+	}
+	t.sweeper.Reset(tusSweep)
+}
+
+// remove forgets the upload with id - call with the lock held
+func (t *tusUploads) remove(id string) {
+	if u := t.uploads[id]; u != nil {
+		_ = os.Remove(u.tmp)
+		u.done = true
+		delete(t.uploads, id)
+	}
+}
+
+// finish marks u as finished so no more data is accepted for it,
+// returning false if it was finished or removed already
+func (t *tusUploads) finish(u *tusUpload) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if u.done {
+		return false
+	}
+	u.done = true
+	return true
+}
+
+// progressed notes that u has progressed, returning false if it was
+// finished or removed meanwhile
+func (t *tusUploads) progressed(u *tusUpload) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u.updated = time.Now()
+	return !u.done
+}
+
+// tusGet returns the upload in the request if it belongs to the user
+// making it, writing an error and returning nil if not
+func (s *server) tusGet(w http.ResponseWriter, r *http.Request) *tusUpload {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	if v := r.Header.Get("Tus-Resumable"); v != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		http.Error(w, "Unsupported tus version", http.StatusPreconditionFailed)
+		return nil
+	}
+	user, _ := r.Context().Value(auth.ContextUserKey).(string)
+	s.tus.mu.Lock()
+	u := s.tus.uploads[r.URL.Query().Get("upload")]
+	if u != nil && u.done {
+		u = nil
+	}
+	s.tus.mu.Unlock()
+	if u == nil || u.user != user {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return nil
+	}
+	return u
+}
+
+// parseTusMetadata parses the Upload-Metadata header
+func parseTusMetadata(header string) map[string]string {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 {
+			continue
+		}
+		value := ""
+		if len(fields) > 1 {
+			decoded, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		metadata[fields[0]] = value
+	}
+	return metadata
+}
+
+// tusOptions tells clients what the server supports
+func (s *server) tusOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tusCreate starts an upload into dir
+func (s *server) tusCreate(w http.ResponseWriter, r *http.Request, dir string) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	if v := r.Header.Get("Tus-Resumable"); v != tusVersion {
+		w.Header().Set("Tus-Version", tusVersion)
+		http.Error(w, "Unsupported tus version", http.StatusPreconditionFailed)
+		return
+	}
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "Need Upload-Length", http.StatusBadRequest)
+		return
+	}
+	metadata := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	name, ok := metadata["filename"]
+	if !ok {
+		name = metadata["name"]
+	}
+	if err := checkName(name); err != nil {
+		http.Error(w, "Need filename in Upload-Metadata: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	remote := path.Join(dir, name)
+	VFS := s.getVFS(r)
+	node, err := VFS.Stat(dir)
+	if err == nil && !node.IsDir() {
+		err = vfs.ENOENT
+	}
+	if err != nil {
+		writeError(w, dir, "Upload failed", err)
+		return
+	}
+	if err := VFS.Check(remote, acl.PermWrite); err != nil {
+		writeError(w, remote, "Upload failed", err)
+		return
+	}
+	if err := checkUploadSize(VFS, remote, length); err != nil {
+		writeError(w, remote, "Upload failed", err)
+		return
+	}
+	user, _ := r.Context().Value(auth.ContextUserKey).(string)
+	s.tus.mu.Lock()
+	u, err := s.tus.create(user, remote, length, int64(Opt.MaxUploadSize), int64(Opt.MaxUploadStaging))
+	s.tus.mu.Unlock()
+	if err != nil {
+		writeError(w, remote, "Upload failed", err)
+		return
+	}
+	fs.Debugf(remote, "%s: Started upload %s of %d bytes", r.RemoteAddr, u.id, length)
+	w.Header().Set("Location", "?upload="+u.id)
+	if length == 0 && s.tus.finish(u) && !s.tusFinish(w, r, u) {
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// tusHead returns how much of the upload has been received
+func (s *server) tusHead(w http.ResponseWriter, r *http.Request) {
+	u := s.tusGet(w, r)
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	offset := u.offset
+	u.mu.Unlock()
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(u.length, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// tusPatch receives some of the data of the upload
+func (s *server) tusPatch(w http.ResponseWriter, r *http.Request) {
+	u := s.tusGet(w, r)
+	if u == nil {
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		http.Error(w, "Need Content-Type: application/offset+octet-stream", http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		http.Error(w, "Need Upload-Offset", http.StatusBadRequest)
+		return
+	}
+	u.mu.Lock()
+	if offset != u.offset {
+		u.mu.Unlock()
+		http.Error(w, "Upload-Offset doesn't match", http.StatusConflict)
+		return
+	}
+	fd, err := os.OpenFile(u.tmp, os.O_WRONLY, 0600)
+	if err != nil {
+		u.mu.Unlock()
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return
+	}
+	_, err = fd.Seek(offset, io.SeekStart)
+	var n int64
+	if err == nil {
+		// Keep what was received even if the connection breaks
+		n, err = io.Copy(fd, io.LimitReader(r.Body, u.length-offset))
+	}
+	closeErr := fd.Close()
+	if err == nil {
+		err = closeErr
+	}
+	u.offset += n
+	offset = u.offset
+	// Mark the upload finished before letting go of it so another
+	// PATCH can't add to it while it is copied into the VFS
+	complete := offset == u.length
+	ok := s.tus.progressed(u) && (!complete || s.tus.finish(u))
+	u.mu.Unlock()
+	if !ok {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		fs.Debugf(u.remote, "%s: Upload %s interrupted at %d bytes: %v", r.RemoteAddr, u.id, offset, err)
+		http.Error(w, "Upload interrupted", http.StatusBadRequest)
+		return
+	}
+	if complete && !s.tusFinish(w, r, u) {
+		return
+	}
+	w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tusFinish copies the completed upload into the VFS, returning
+// false having written an error if it failed.
+//
+// Call after marking the upload finished without holding u.mu.
+func (s *server) tusFinish(w http.ResponseWriter, r *http.Request, u *tusUpload) bool {
+	in, err := os.Open(u.tmp)
+	if err == nil {
+		err = upload(s.getVFS(r), u.remote, in)
+		_ = in.Close()
+	}
+	s.tus.mu.Lock()
+	s.tus.remove(u.id)
+	s.tus.mu.Unlock()
+	if err != nil {
+		writeError(w, u.remote, "Upload failed", err)
+		return false
+	}
+	fs.Infof(u.remote, "%s: Uploaded file", r.RemoteAddr)
+	return true
+}
+
+// tusDelete abandons an upload
+func (s *server) tusDelete(w http.ResponseWriter, r *http.Request) {
+	u := s.tusGet(w, r)
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s.tus.mu.Lock()
+	done := u.done
+	if !done {
+		s.tus.remove(u.id)
+	}
+	s.tus.mu.Unlock()
+	if done {
+		http.Error(w, "Upload not found", http.StatusNotFound)
+		return
+	}
+	fs.Debugf(u.remote, "%s: Abandoned upload %s", r.RemoteAddr, u.id)
+	w.WriteHeader(http.StatusNoContent)
+}
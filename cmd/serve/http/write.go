@@ -0,0 +1,319 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+// errUploadTooBig is returned for uploads bigger than --max-upload-size
+var errUploadTooBig = errors.New("upload too big")
+
+// cleanRemote makes a path from the URL into a remote in the VFS
+// which can't escape the root
+func cleanRemote(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
+
+// writeError writes an http error for err from the VFS
+func writeError(w http.ResponseWriter, remote string, what string, err error) {
+	switch {
+	case err == vfs.ENOENT || os.IsNotExist(err):
+		http.Error(w, what+": not found", http.StatusNotFound)
+	case err == vfs.EPERM || err == vfs.EROFS || os.IsPermission(err):
+		http.Error(w, what+": forbidden", http.StatusForbidden)
+	case err == vfs.EEXIST || os.IsExist(err):
+		http.Error(w, what+": already exists", http.StatusConflict)
+	case err == vfs.ENOTEMPTY:
+		http.Error(w, what+": directory not empty", http.StatusConflict)
+	case err == acl.ErrQuotaExceeded:
+		http.Error(w, what+": quota exceeded", http.StatusInsufficientStorage)
+	case err == errUploadTooBig:
+		http.Error(w, what+": "+err.Error(), http.StatusRequestEntityTooLarge)
+	case err == errStagingFull:
+		http.Error(w, what+": "+err.Error(), http.StatusInsufficientStorage)
+	default:
+		fs.Errorf(remote, "%s: %v", what, err)
+		http.Error(w, what+".", http.StatusInternalServerError)
+	}
+}
+
+// done finishes a successful change, sending browsers back to the
+// directory listing
+func done(w http.ResponseWriter, r *http.Request, status int, message string) {
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Location", "./")
+		w.WriteHeader(http.StatusSeeOther)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintln(w, message)
+}
+
+// sameOrigin refuses requests from other sites so they can't make a
+// user's browser change things with their credentials.
+//
+// Requests with an Origin or Referer header must be from this server.
+// Browsers send one of these with requests from other sites, unless
+// they are configured not to, so form POSTs, which other sites can
+// make, are refused without either.
+func sameOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from := r.Header.Get("Origin")
+		if from == "" {
+			from = r.Header.Get("Referer")
+		}
+		if from == "" {
+			if r.Method == "POST" && isFormContentType(r.Header.Get("Content-Type")) {
+				fs.Infof(r.URL.Path, "%s: Refused form without Origin or Referer", r.RemoteAddr)
+				http.Error(w, "Forbidden: need Origin or Referer", http.StatusForbidden)
+				return
+			}
+		} else if u, err := url.Parse(from); err != nil || !strings.EqualFold(u.Host, r.Host) {
+			fs.Infof(r.URL.Path, "%s: Refused %s from %q", r.RemoteAddr, r.Method, from)
+			http.Error(w, "Forbidden: cross site request", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isFormContentType returns true for the content types which HTML
+// forms on other sites can send
+func isFormContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "multipart/form-data", "text/plain":
+		return true
+	}
+	return false
+}
+
+// checkUploadSize returns an error if an upload of size bytes to
+// remote isn't allowed. Use a size of -1 if it isn't known.
+func checkUploadSize(VFS *acl.VFS, remote string, size int64) error {
+	if Opt.MaxUploadSize >= 0 && size > int64(Opt.MaxUploadSize) {
+		return errUploadTooBig
+	}
+	if size > 0 {
+		return VFS.CheckQuota(remote, size)
+	}
+	return nil
+}
+
+// maxReader returns errUploadTooBig if more than n bytes are read
+type maxReader struct {
+	in io.Reader
+	n  int64
+}
+
+// Read bytes from the reader
+func (m *maxReader) Read(p []byte) (n int, err error) {
+	n, err = m.in.Read(p)
+	m.n -= int64(n)
+	if m.n < 0 {
+		return n, errUploadTooBig
+	}
+	return n, err
+}
+
+// limitUpload limits in to --max-upload-size
+func limitUpload(in io.Reader) io.Reader {
+	if Opt.MaxUploadSize < 0 {
+		return in
+	}
+	return &maxReader{in: in, n: int64(Opt.MaxUploadSize)}
+}
+
+// upload writes in to remote in the VFS, replacing it only if it is
+// written successfully
+func upload(VFS *acl.VFS, remote string, in io.Reader) (err error) {
+	return VFS.WriteFile(remote, limitUpload(in))
+}
+
+// handlePut uploads the body of the request
+func (s *server) handlePut(w http.ResponseWriter, r *http.Request) {
+	remote := cleanRemote(r.URL.Path)
+	if remote == "" || strings.HasSuffix(r.URL.Path, "/") {
+		http.Error(w, "Can only PUT files", http.StatusMethodNotAllowed)
+		return
+	}
+	VFS := s.getVFS(r)
+	err := checkUploadSize(VFS, remote, r.ContentLength)
+	if err == nil {
+		err = upload(VFS, remote, r.Body)
+	}
+	if err != nil {
+		writeError(w, remote, "Upload failed", err)
+		return
+	}
+	fs.Infof(remote, "%s: Uploaded file", r.RemoteAddr)
+	done(w, r, http.StatusCreated, "Uploaded "+remote)
+}
+
+// handleDelete deletes a file or directory and everything in it
+func (s *server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("upload") != "" {
+		s.tusDelete(w, r)
+		return
+	}
+	s.delete(w, r, cleanRemote(r.URL.Path))
+}
+
+// delete removes remote and everything in it
+func (s *server) delete(w http.ResponseWriter, r *http.Request, remote string) {
+	if remote == "" {
+		http.Error(w, "Can't delete the root", http.StatusForbidden)
+		return
+	}
+	err := s.getVFS(r).RemoveAll(remote)
+	if err != nil {
+		writeError(w, remote, "Delete failed", err)
+		return
+	}
+	fs.Infof(remote, "%s: Deleted", r.RemoteAddr)
+	done(w, r, http.StatusOK, "Deleted "+remote)
+}
+
+// handlePost does the actions from the web interface
+func (s *server) handlePost(w http.ResponseWriter, r *http.Request) {
+	remote := cleanRemote(r.URL.Path)
+	if r.Header.Get("Tus-Resumable") != "" {
+		s.tusCreate(w, r, remote)
+		return
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		s.uploadForm(w, r, remote)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch action := r.Form.Get("action"); action {
+	case "mkdir":
+		s.mkdir(w, r, remote, r.Form.Get("name"))
+	case "rename":
+		s.rename(w, r, remote, r.Form.Get("to"))
+	case "delete":
+		s.delete(w, r, remote)
+	default:
+		http.Error(w, fmt.Sprintf("Unknown action %q", action), http.StatusBadRequest)
+	}
+}
+
+// checkName returns an error if name can't be used for a new file or
+// directory
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return errors.Errorf("bad name %q", name)
+	}
+	return nil
+}
+
+// mkdir makes the directory name in dir
+func (s *server) mkdir(w http.ResponseWriter, r *http.Request, dir, name string) {
+	if err := checkName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	remote := path.Join(dir, name)
+	err := s.getVFS(r).Mkdir(remote, 0777)
+	if err != nil {
+		writeError(w, remote, "Mkdir failed", err)
+		return
+	}
+	fs.Infof(remote, "%s: Made directory", r.RemoteAddr)
+	done(w, r, http.StatusCreated, "Made directory "+remote)
+}
+
+// rename renames remote to to, which is a name in the same directory
+// or a path from the root if it starts with /
+func (s *server) rename(w http.ResponseWriter, r *http.Request, remote, to string) {
+	if remote == "" {
+		http.Error(w, "Can't rename the root", http.StatusForbidden)
+		return
+	}
+	var newRemote string
+	if strings.HasPrefix(to, "/") {
+		newRemote = cleanRemote(to)
+	} else {
+		if err := checkName(to); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		newRemote = path.Join(path.Dir(remote), to)
+	}
+	if newRemote == "" || newRemote == remote {
+		http.Error(w, fmt.Sprintf("Can't rename to %q", to), http.StatusBadRequest)
+		return
+	}
+	VFS := s.getVFS(r)
+	if _, err := VFS.Stat(newRemote); err == nil {
+		writeError(w, newRemote, "Rename failed", vfs.EEXIST)
+		return
+	}
+	err := VFS.Rename(remote, newRemote)
+	if err != nil {
+		writeError(w, remote, "Rename failed", err)
+		return
+	}
+	fs.Infof(remote, "%s: Renamed to %q", r.RemoteAddr, newRemote)
+	done(w, r, http.StatusOK, "Renamed "+remote+" to "+newRemote)
+}
+
+// uploadForm uploads the files in a multipart form into dir
+func (s *server) uploadForm(w http.ResponseWriter, r *http.Request, dir string) {
+	VFS := s.getVFS(r)
+	if err := checkUploadSize(VFS, dir, r.ContentLength); err != nil {
+		writeError(w, dir, "Upload failed", err)
+		return
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Bad form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	var uploaded []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			http.Error(w, "Bad form: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		name := path.Base(strings.Replace(part.FileName(), "\\", "/", -1))
+		if part.FileName() == "" {
+			continue
+		}
+		if err := checkName(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		remote := path.Join(dir, name)
+		err = upload(VFS, remote, part)
+		if err != nil {
+			writeError(w, remote, "Upload failed", err)
+			return
+		}
+		fs.Infof(remote, "%s: Uploaded file", r.RemoteAddr)
+		uploaded = append(uploaded, remote)
+	}
+	if len(uploaded) == 0 {
+		http.Error(w, "No files in form", http.StatusBadRequest)
+		return
+	}
+	done(w, r, http.StatusCreated, "Uploaded "+strings.Join(uploaded, ", "))
+}
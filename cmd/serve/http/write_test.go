@@ -0,0 +1,332 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWriteServer serves a temporary directory with writes enabled
+func newWriteServer(t *testing.T, write bool) (dir string, testURL string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "rclone-serve-http-write")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dir", "sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "dir", "sub", "Potato.txt"), []byte("potato"), 0600))
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+
+	oldWrite := Opt.Write
+	Opt.Write = write
+	s := newServer(f, "")
+	router := chi.NewRouter()
+	s.Bind(router)
+	server := httptest.NewServer(router)
+	return dir, server.URL + "/", func() {
+		server.Close()
+		Opt.Write = oldWrite
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// do makes a request returning the response with the body read
+func do(t *testing.T, method, url string, body io.Reader, headers ...string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp, string(data)
+}
+
+func readFile(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile(name)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestWriteDisabled(t *testing.T) {
+	_, testURL, cleanup := newWriteServer(t, false)
+	defer cleanup()
+	resp, _ := do(t, "PUT", testURL+"new.txt", strings.NewReader("new"))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	resp, _ = do(t, "DELETE", testURL+"file.txt", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	_, body := do(t, "GET", testURL, nil)
+	assert.NotContains(t, body, ">New folder<")
+	assert.Contains(t, body, "Download zip")
+}
+
+func TestWrite(t *testing.T) {
+	dir, testURL, cleanup := newWriteServer(t, true)
+	defer cleanup()
+
+	_, body := do(t, "GET", testURL, nil)
+	assert.Contains(t, body, ">New folder<")
+	origin := strings.TrimSuffix(testURL, "/")
+
+	// PUT
+	resp, _ := do(t, "PUT", testURL+"dir/new.txt", strings.NewReader("new"))
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "new", readFile(t, filepath.Join(dir, "dir", "new.txt")))
+	resp, _ = do(t, "PUT", testURL+"dir/", strings.NewReader("new"))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	// Multipart form upload from a browser
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, name := range []string{"one.txt", "two.txt"} {
+		part, err := mw.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte("form " + name))
+	}
+	require.NoError(t, mw.Close())
+	resp, _ = do(t, "POST", testURL+"dir/", &buf, "Content-Type", mw.FormDataContentType(), "Accept", "text/html", "Origin", origin)
+	assert.Equal(t, http.StatusSeeOther, resp.StatusCode)
+	assert.Equal(t, "./", resp.Header.Get("Location"))
+	assert.Equal(t, "form two.txt", readFile(t, filepath.Join(dir, "dir", "two.txt")))
+
+	// Mkdir
+	form := url.Values{"action": {"mkdir"}, "name": {"made"}}
+	resp, _ = do(t, "POST", testURL+"dir/", strings.NewReader(form.Encode()), "Content-Type", "application/x-www-form-urlencoded", "Referer", testURL+"dir/")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	fi, err := os.Stat(filepath.Join(dir, "dir", "made"))
+	require.NoError(t, err)
+	assert.True(t, fi.IsDir())
+	resp, _ = do(t, "POST", testURL+"dir/?action=mkdir&name=..", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Rename in the same directory and to a path
+	resp, _ = do(t, "POST", testURL+"dir/one.txt?action=rename&to=uno.txt", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "form one.txt", readFile(t, filepath.Join(dir, "dir", "uno.txt")))
+	resp, _ = do(t, "POST", testURL+"dir/uno.txt?action=rename&to=/dir/made/uno.txt", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "form one.txt", readFile(t, filepath.Join(dir, "dir", "made", "uno.txt")))
+	resp, _ = do(t, "POST", testURL+"dir/two.txt?action=rename&to=new.txt", nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"missing.txt?action=rename&to=x.txt", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Delete a directory with things in and a file
+	resp, _ = do(t, "POST", testURL+"dir/made/?action=delete", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = os.Stat(filepath.Join(dir, "dir", "made"))
+	assert.True(t, os.IsNotExist(err))
+	resp, _ = do(t, "DELETE", testURL+"dir/two.txt", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = os.Stat(filepath.Join(dir, "dir", "two.txt"))
+	assert.True(t, os.IsNotExist(err))
+	resp, _ = do(t, "DELETE", testURL, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"?action=potato", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestWriteCrossSite(t *testing.T) {
+	dir, testURL, cleanup := newWriteServer(t, true)
+	defer cleanup()
+	form := url.Values{"action": {"delete"}}.Encode()
+	formType := []string{"Content-Type", "application/x-www-form-urlencoded"}
+
+	// Forms from other sites or without Origin or Referer are refused
+	resp, _ := do(t, "POST", testURL+"file.txt", strings.NewReader(form), append(formType, "Origin", "http://evil.example.com")...)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"file.txt", strings.NewReader(form), append(formType, "Referer", "http://evil.example.com/page")...)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"file.txt", strings.NewReader(form), append(formType, "Origin", "null")...)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"file.txt", strings.NewReader(form), formType...)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = do(t, "DELETE", testURL+"file.txt", nil, "Origin", "http://evil.example.com")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "hello", readFile(t, filepath.Join(dir, "file.txt")))
+
+	// but allowed from the server itself
+	resp, _ = do(t, "POST", testURL+"file.txt", strings.NewReader(form), append(formType, "Origin", strings.TrimSuffix(testURL, "/"))...)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err := os.Stat(filepath.Join(dir, "file.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWriteMaxUploadSize(t *testing.T) {
+	dir, testURL, cleanup := newWriteServer(t, true)
+	defer cleanup()
+	oldMax := Opt.MaxUploadSize
+	Opt.MaxUploadSize = 5
+	defer func() {
+		Opt.MaxUploadSize = oldMax
+	}()
+
+	// Too big by Content-Length
+	resp, _ := do(t, "PUT", testURL+"file.txt", strings.NewReader("too big"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// Too big without Content-Length leaves the old file
+	resp, _ = do(t, "PUT", testURL+"file.txt", ioutil.NopCloser(strings.NewReader("too big")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, "hello", readFile(t, filepath.Join(dir, "file.txt")))
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(entries), "partial upload left behind")
+
+	resp, _ = do(t, "PUT", testURL+"file.txt", strings.NewReader("small"))
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "small", readFile(t, filepath.Join(dir, "file.txt")))
+
+	// Resumable uploads are limited too, in total for each user
+	tus := []string{"Tus-Resumable", tusVersion, "Upload-Metadata", "filename " + base64.StdEncoding.EncodeToString([]byte("big.bin"))}
+	resp, _ = do(t, "POST", testURL, nil, append(tus, "Upload-Length", "6")...)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL, nil, append(tus, "Upload-Length", "3")...)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL, nil, append(tus, "Upload-Length", "3")...)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// And for everyone even without a size limit
+	oldStaging := Opt.MaxUploadStaging
+	Opt.MaxUploadSize, Opt.MaxUploadStaging = -1, 8
+	defer func() {
+		Opt.MaxUploadStaging = oldStaging
+	}()
+	resp, _ = do(t, "POST", testURL, nil, append(tus, "Upload-Length", "5")...)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL, nil, append(tus, "Upload-Length", "1")...)
+	assert.Equal(t, http.StatusInsufficientStorage, resp.StatusCode)
+}
+
+func TestWriteTus(t *testing.T) {
+	dir, testURL, cleanup := newWriteServer(t, true)
+	defer cleanup()
+	tus := []string{"Tus-Resumable", tusVersion}
+
+	resp, _ := do(t, "OPTIONS", testURL, nil)
+	assert.Equal(t, tusExtensions, resp.Header.Get("Tus-Extension"))
+
+	// Creation
+	metadata := "filename " + base64.StdEncoding.EncodeToString([]byte("big.bin"))
+	resp, _ = do(t, "POST", testURL+"dir/", nil, "Tus-Resumable", "0.2.0", "Upload-Length", "10", "Upload-Metadata", metadata)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"dir/", nil, append(tus, "Upload-Length", "10")...)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = do(t, "POST", testURL+"dir/", nil, append(tus, "Upload-Length", "10", "Upload-Metadata", metadata)...)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	location := resp.Header.Get("Location")
+	require.True(t, strings.HasPrefix(location, "?upload="), location)
+	uploadURL := testURL + "dir/" + location
+
+	// Send the first part then resume
+	patch := append(tus, "Content-Type", "application/offset+octet-stream")
+	resp, _ = do(t, "PATCH", uploadURL, strings.NewReader("01234"), append(patch, "Upload-Offset", "0")...)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "5", resp.Header.Get("Upload-Offset"))
+	resp, _ = do(t, "HEAD", uploadURL, nil, tus...)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "5", resp.Header.Get("Upload-Offset"))
+	assert.Equal(t, "10", resp.Header.Get("Upload-Length"))
+	resp, _ = do(t, "PATCH", uploadURL, strings.NewReader("01234"), append(patch, "Upload-Offset", "0")...)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	_, err := os.Stat(filepath.Join(dir, "dir", "big.bin"))
+	assert.True(t, os.IsNotExist(err))
+	resp, _ = do(t, "PATCH", uploadURL, strings.NewReader("56789"), append(patch, "Upload-Offset", "5")...)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get("Upload-Offset"))
+	assert.Equal(t, "0123456789", readFile(t, filepath.Join(dir, "dir", "big.bin")))
+
+	// The upload has gone once finished
+	resp, _ = do(t, "HEAD", uploadURL, nil, tus...)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = do(t, "PATCH", uploadURL, strings.NewReader("more"), append(patch, "Upload-Offset", "10")...)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "0123456789", readFile(t, filepath.Join(dir, "dir", "big.bin")))
+
+	// Termination
+	resp, _ = do(t, "POST", testURL, nil, append(tus, "Upload-Length", "10", "Upload-Metadata", metadata)...)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	uploadURL = testURL + resp.Header.Get("Location")
+	resp, _ = do(t, "DELETE", uploadURL, nil, tus...)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp, _ = do(t, "HEAD", uploadURL, nil, tus...)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	_, err = os.Stat(filepath.Join(dir, "big.bin"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTusSweep(t *testing.T) {
+	oldSweep := tusSweep
+	tusSweep = time.Millisecond
+	defer func() {
+		tusSweep = oldSweep
+	}()
+	uploads := newTusUploads()
+	uploads.mu.Lock()
+	u, err := uploads.create("user", "file.bin", 10, -1, -1)
+	require.NoError(t, err)
+	require.NotNil(t, uploads.sweeper)
+	u.updated = time.Now().Add(-2 * tusExpiry)
+	uploads.mu.Unlock()
+
+	// The sweeper forgets the stalled upload then stops
+	require.Eventually(t, func() bool {
+		uploads.mu.Lock()
+		defer uploads.mu.Unlock()
+		return len(uploads.uploads) == 0 && uploads.sweeper == nil
+	}, 10*time.Second, time.Millisecond)
+	assert.True(t, u.done)
+	_, err = os.Stat(u.tmp)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestZipAndSearch(t *testing.T) {
+	_, testURL, cleanup := newWriteServer(t, false)
+	defer cleanup()
+
+	resp, body := do(t, "GET", testURL+"dir/?zip", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "dir.zip")
+	zr, err := zip.NewReader(strings.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"sub/", "sub/Potato.txt"}, names)
+	resp, _ = do(t, "GET", testURL+"missing/?zip", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, body = do(t, "GET", testURL+"?search=POTATO", nil, "Accept", "application/json")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var results []searchResult
+	require.NoError(t, json.Unmarshal([]byte(body), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "dir/sub/Potato.txt", results[0].Path)
+	assert.Equal(t, int64(6), results[0].Size)
+
+	_, body = do(t, "GET", testURL+"?search=potato", nil)
+	assert.Contains(t, body, `href="dir/sub/Potato.txt"`)
+	assert.Contains(t, body, "Back to listing")
+}
@@ -14,6 +14,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rclone/rclone/cmd/serve/http/data"
 	"github.com/rclone/rclone/fs"
+	httpauth "github.com/rclone/rclone/lib/http/auth"
 )
 
 // Globals
@@ -97,7 +99,7 @@ To create an htpasswd file:
 The password file can be updated while rclone is running.
 
 Use --realm to set the authentication realm.
-
+` + httpauth.ProvidersHelp + `
 #### SSL/TLS
 
 By default this will serve over http.  If you want you can serve over
@@ -127,6 +129,8 @@ type Options struct {
 	BasicPass          string        // password for BasicUser
 	Auth               AuthFn        `json:"-"` // custom Auth (not set by command line flags)
 	Template           string        // User specified template
+	PublicPaths        []string      `json:"-"` // paths under BaseURL which GET and HEAD can use without auth
+	httpauth.ProviderOptions
 }
 
 // AuthFn if used will be used to authenticate user, pass. If an error
@@ -142,6 +146,7 @@ var DefaultOpt = Options{
 	ServerReadTimeout:  1 * time.Hour,
 	ServerWriteTimeout: 1 * time.Hour,
 	MaxHeaderBytes:     4096,
+	ProviderOptions:    httpauth.DefaultProviderOpt,
 }
 
 // Server contains info about the running http server
@@ -167,6 +172,10 @@ type contextAuthType struct{}
 // ContextAuthKey is a simple context key for storing info returned by AuthFn
 var ContextAuthKey = &contextAuthType{}
 
+// ContextRootKey is a context key for storing the root directory the
+// user of the request is confined to, if any
+var ContextRootKey = httpauth.ContextRootKey
+
 // singleUserProvider provides the encrypted password for a single user
 func (s *Server) singleUserProvider(user, realm string) string {
 	if user == s.Opt.BasicUser {
@@ -210,10 +219,20 @@ func NewServer(handler http.Handler, opt *Options) *Server {
 		s.Opt = DefaultOpt
 	}
 
+	// Use the built in providers if configured
+	providers, err := httpauth.NewProviders(context.Background(), &s.Opt.ProviderOptions)
+	if err != nil {
+		log.Fatalf("Failed to set up authentication: %v", err)
+	}
+	usingPasswords := s.Opt.HtPasswd != "" || s.Opt.BasicUser != "" || s.Opt.Auth != nil
+	if usingPasswords && providers.UsesPasswords() {
+		log.Fatalf("Can't use LDAP with other password authentication")
+	}
+
 	// Use htpasswd if required on everything
-	if s.Opt.HtPasswd != "" || s.Opt.BasicUser != "" || s.Opt.Auth != nil {
+	if usingPasswords || providers != nil {
 		var authenticator *auth.BasicAuth
-		if s.Opt.Auth == nil {
+		if s.Opt.Auth == nil && (s.Opt.HtPasswd != "" || s.Opt.BasicUser != "") {
 			var secretProvider auth.SecretProvider
 			if s.Opt.HtPasswd != "" {
 				fs.Infof(nil, "Using %q as htpasswd storage", s.Opt.HtPasswd)
@@ -232,23 +251,38 @@ func NewServer(handler http.Handler, opt *Options) *Server {
 				oldHandler.ServeHTTP(w, r)
 				return
 			}
+			// Or for reading public paths
+			if (r.Method == "GET" || r.Method == "HEAD") && s.isPublic(r.URL.Path) {
+				oldHandler.ServeHTTP(w, r)
+				return
+			}
 			unauthorized := func() {
 				w.Header().Set("Content-Type", "text/plain")
 				w.Header().Set("WWW-Authenticate", `Basic realm="`+s.Opt.Realm+`"`)
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			}
+			// Client certificates and bearer tokens
+			if user, root, handled, err := providers.Authenticate(r); handled {
+				if err != nil {
+					fs.Infof(r.URL.Path, "%s: Auth failed: %v", r.RemoteAddr, err)
+					w.Header().Set("WWW-Authenticate", `Bearer realm="`+s.Opt.Realm+`", error="invalid_token"`)
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+				r = r.WithContext(context.WithValue(r.Context(), ContextUserKey, user))
+				if root != "" {
+					r = r.WithContext(context.WithValue(r.Context(), ContextRootKey, root))
+				}
+				oldHandler.ServeHTTP(w, r)
+				return
+			}
 			user, pass, authValid := parseAuthorization(r)
 			if !authValid {
 				unauthorized()
 				return
 			}
-			if s.Opt.Auth == nil {
-				if username := authenticator.CheckAuth(r); username == "" {
-					fs.Infof(r.URL.Path, "%s: Unauthorized request from %s", r.RemoteAddr, user)
-					unauthorized()
-					return
-				}
-			} else {
+			switch {
+			case s.Opt.Auth != nil:
 				// Custom Auth
 				value, err := s.Opt.Auth(user, pass)
 				if err != nil {
@@ -259,6 +293,22 @@ func NewServer(handler http.Handler, opt *Options) *Server {
 				if value != nil {
 					r = r.WithContext(context.WithValue(r.Context(), ContextAuthKey, value))
 				}
+			case authenticator != nil:
+				if username := authenticator.CheckAuth(r); username == "" {
+					fs.Infof(r.URL.Path, "%s: Unauthorized request from %s", r.RemoteAddr, user)
+					unauthorized()
+					return
+				}
+			case providers.UsesPasswords():
+				if err := providers.CheckPassword(r.Context(), user, pass); err != nil {
+					fs.Infof(r.URL.Path, "%s: Auth failed from %s: %v", r.RemoteAddr, user, err)
+					unauthorized()
+					return
+				}
+			default:
+				fs.Infof(r.URL.Path, "%s: Unauthorized request from %s", r.RemoteAddr, user)
+				unauthorized()
+				return
 			}
 			r = r.WithContext(context.WithValue(r.Context(), ContextUserKey, user))
 			oldHandler.ServeHTTP(w, r)
@@ -316,6 +366,16 @@ func NewServer(handler http.Handler, opt *Options) *Server {
 	return s
 }
 
+// isPublic returns true if urlPath is under one of the PublicPaths
+func (s *Server) isPublic(urlPath string) bool {
+	for _, public := range s.Opt.PublicPaths {
+		if strings.HasPrefix(path.Clean(urlPath), s.Opt.BaseURL+"/"+strings.TrimLeft(public, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
 // Serve runs the server - returns an error only if
 // the listener was not started; does not block, so
 // use s.Wait() to block on the listener indefinitely.
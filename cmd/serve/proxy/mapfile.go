@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	RegisterPlugin("map", newMapFile)
+}
+
+// mapEntry is a line of the map file
+type mapEntry struct {
+	auth   string // bcrypt hash of the password or "pubkey:" and the key
+	remote string // template for the remote
+}
+
+// mapFile is an auth proxy which looks up the user in a file mapping
+// users to remotes.
+//
+// Each line of the file is "user auth remote" where auth is a bcrypt
+// hash of the password, as made by "htpasswd -B", or "pubkey:" and
+// the base64 public key. The file is read again if it changes.
+type mapFile struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	entries map[string]mapEntry
+}
+
+// newMapFile makes a mapFile plugin reading path
+func newMapFile(ctx context.Context, path string) (Plugin, error) {
+	m := &mapFile{path: path}
+	err := m.reload()
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// reload reads the file again if it has changed - call with the lock held
+func (m *mapFile) reload() (err error) {
+	fi, err := os.Stat(m.path)
+	if err != nil {
+		return err
+	}
+	if m.entries != nil && fi.ModTime().Equal(m.modTime) && fi.Size() == m.size {
+		return nil
+	}
+	in, err := os.Open(m.path)
+	if err != nil {
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	entries := map[string]mapEntry{}
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return errors.Errorf("%s:%d: need 3 fields: user auth remote", m.path, lineNumber)
+		}
+		user, auth := fields[0], fields[1]
+		if !strings.HasPrefix(auth, "pubkey:") {
+			if _, err := bcrypt.Cost([]byte(auth)); err != nil {
+				return errors.Wrapf(err, "%s:%d: bad password hash for %q", m.path, lineNumber, user)
+			}
+		}
+		if _, found := entries[user]; found {
+			return errors.Errorf("%s:%d: duplicate user %q", m.path, lineNumber, user)
+		}
+		entries[user] = mapEntry{auth: auth, remote: fields[2]}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	fs.Debugf(nil, "Read %d users from auth proxy map %q", len(entries), m.path)
+	m.entries, m.modTime, m.size = entries, fi.ModTime(), fi.Size()
+	return nil
+}
+
+// Auth implements Plugin
+func (m *mapFile) Auth(ctx context.Context, in map[string]string) (configmap.Simple, error) {
+	m.mu.Lock()
+	err := m.reload()
+	if err != nil {
+		// keep using the old version
+		fs.Errorf(nil, "Failed to reload auth proxy map: %v", err)
+	}
+	entry, found := m.entries[in["user"]]
+	m.mu.Unlock()
+	if !found {
+		return nil, errors.New("unknown user")
+	}
+	if publicKey, ok := in["public_key"]; ok {
+		want := strings.TrimPrefix(entry.auth, "pubkey:")
+		if want == entry.auth || subtle.ConstantTimeCompare([]byte(want), []byte(publicKey)) != 1 {
+			return nil, errors.New("incorrect public key")
+		}
+	} else {
+		if strings.HasPrefix(entry.auth, "pubkey:") || bcrypt.CompareHashAndPassword([]byte(entry.auth), []byte(in["pass"])) != nil {
+			return nil, errors.New("incorrect password")
+		}
+	}
+	remote, err := expandTemplate(entry.remote, map[string]string{"user": in["user"]})
+	if err != nil {
+		return nil, err
+	}
+	return configmap.Simple{"_remote": remote}, nil
+}
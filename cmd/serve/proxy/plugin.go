@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs/config/configmap"
+)
+
+// Plugin is an auth proxy which runs inside rclone rather than as an
+// external program.
+//
+// Auth is passed the same input as the external program - "user" and
+// one of "pass" or "public_key" - and should return the config for
+// the backend in the same format, or an error if the login should be
+// refused. The result is cached by the Proxy in the same way.
+type Plugin interface {
+	Auth(ctx context.Context, in map[string]string) (configmap.Simple, error)
+}
+
+// NewPluginFn makes a Plugin from its argument, which is the part of
+// --auth-proxy after the "name:" prefix
+type NewPluginFn func(ctx context.Context, arg string) (Plugin, error)
+
+var (
+	pluginsMu sync.Mutex
+	plugins   = map[string]NewPluginFn{}
+)
+
+// RegisterPlugin registers an in-process auth proxy called name
+// which is used with --auth-proxy name:arg
+func RegisterPlugin(name string, newPlugin NewPluginFn) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	plugins[name] = newPlugin
+}
+
+// Plugins returns the names of the registered plugins
+func Plugins() (names []string) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matches the plugin name at the start of --auth-proxy
+var pluginRe = regexp.MustCompile(`^([a-z][a-z0-9]*):(.*)$`)
+
+// newPlugin makes the plugin for the --auth-proxy value authProxy.
+//
+// It returns a nil Plugin if authProxy is an external program.
+func newPlugin(ctx context.Context, authProxy string) (Plugin, error) {
+	authProxy = strings.TrimSpace(authProxy)
+	// A URL is short for the webhook
+	if strings.HasPrefix(authProxy, "http://") || strings.HasPrefix(authProxy, "https://") {
+		authProxy = "webhook:" + authProxy
+	}
+	match := pluginRe.FindStringSubmatch(authProxy)
+	if match == nil {
+		return nil, nil
+	}
+	pluginsMu.Lock()
+	newPlugin := plugins[match[1]]
+	pluginsMu.Unlock()
+	if newPlugin == nil {
+		// Could be a program called "c:\path\to\proxy.exe"
+		return nil, nil
+	}
+	plugin, err := newPlugin(ctx, match[2])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to make %q auth proxy", match[1])
+	}
+	return plugin, nil
+}
+
+// expandTemplate replaces {user}, {pass} and {public_key} in template
+// with the values from in.
+//
+// It returns an error if the user name could be used to escape from
+// a path in the template.
+func expandTemplate(template string, in map[string]string) (string, error) {
+	user := in["user"]
+	if strings.Contains(template, "{user}") {
+		if user == "" || user == "." || user == ".." || strings.ContainsAny(user, "/\\:\x00") {
+			return "", errors.Errorf("user name %q not allowed", user)
+		}
+	}
+	return strings.NewReplacer(
+		"{user}", user,
+		"{pass}", in["pass"],
+		"{public_key}", in["public_key"],
+	).Replace(template), nil
+}
@@ -0,0 +1,202 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNewPlugin(t *testing.T) {
+	ctx := context.Background()
+	for _, authProxy := range []string{
+		"go run proxy_code.go",
+		"/usr/bin/proxy",
+		`c:\path\to\proxy.exe`,
+		"nosuchplugin:potato",
+	} {
+		plugin, err := newPlugin(ctx, authProxy)
+		require.NoError(t, err, authProxy)
+		assert.Nil(t, plugin, authProxy)
+	}
+
+	plugin, err := newPlugin(ctx, "https://example.com/auth")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/auth", plugin.(*webhook).url)
+
+	_, err = newPlugin(ctx, "webhook:ftp://example.com")
+	assert.Error(t, err)
+	_, err = newPlugin(ctx, "map:/does/not/exist")
+	assert.Error(t, err)
+	_, err = newPlugin(ctx, `template:{"user":"{user}"}`)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"map", "template", "webhook"}, Plugins())
+
+	// A broken plugin refuses all logins
+	p := New(ctx, &Options{AuthProxy: "map:/does/not/exist"})
+	_, _, err = p.Call("user", "pass", false)
+	assert.Error(t, err)
+}
+
+func TestExpandTemplate(t *testing.T) {
+	in := map[string]string{"user": "me", "pass": "secret"}
+	got, err := expandTemplate("s3:bucket/{user}/{pass}", in)
+	require.NoError(t, err)
+	assert.Equal(t, "s3:bucket/me/secret", got)
+
+	for _, user := range []string{"", ".", "..", "a/b", `a\b`, "a:b"} {
+		_, err = expandTemplate("s3:bucket/{user}", map[string]string{"user": user})
+		assert.Error(t, err, user)
+	}
+	// only checked if {user} is used
+	got, err = expandTemplate("s3:bucket", map[string]string{"user": "../.."})
+	require.NoError(t, err)
+	assert.Equal(t, "s3:bucket", got)
+}
+
+func TestWebhook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-proxy")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "POST", r.Method)
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch {
+		case in["pass"] == "broken":
+			_, _ = w.Write([]byte("not json"))
+		case in["pass"] != "secret":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			_, _ = fmt.Fprintf(w, `{"type":"local","_root":%q}`, dir)
+		}
+	}))
+	defer srv.Close()
+
+	p := New(context.Background(), &Options{AuthProxy: srv.URL})
+	require.NotNil(t, p.plugin)
+
+	VFS, key, err := p.Call("me", "secret", false)
+	require.NoError(t, err)
+	assert.Equal(t, "me", key)
+	assert.Equal(t, "proxy-me", VFS.Fs().Name())
+	assert.Equal(t, 1, calls)
+
+	// Cached
+	_, _, err = p.Call("me", "secret", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	_, _, err = p.Call("you", "wrong", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refused")
+
+	_, _, err = p.Call("other", "broken", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad JSON")
+}
+
+func TestMapFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-proxy")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	for _, user := range []string{"alice", "bob"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, user), 0777))
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mapPath := filepath.Join(dir, "users")
+	contents := fmt.Sprintf(`# test users
+alice %s %s/{user}
+
+bob pubkey:AAAAbobkey %s/{user}
+`, hash, dir, dir)
+	require.NoError(t, ioutil.WriteFile(mapPath, []byte(contents), 0600))
+
+	ctx := context.Background()
+	plugin, err := newPlugin(ctx, "map:"+mapPath)
+	require.NoError(t, err)
+
+	config, err := plugin.Auth(ctx, map[string]string{"user": "alice", "pass": "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, configmap.Simple{"_remote": dir + "/alice"}, config)
+
+	_, err = plugin.Auth(ctx, map[string]string{"user": "alice", "pass": "wrong"})
+	assert.EqualError(t, err, "incorrect password")
+	_, err = plugin.Auth(ctx, map[string]string{"user": "alice", "public_key": "AAAAbobkey"})
+	assert.EqualError(t, err, "incorrect public key")
+	_, err = plugin.Auth(ctx, map[string]string{"user": "bob", "pass": "pubkey:AAAAbobkey"})
+	assert.EqualError(t, err, "incorrect password")
+	_, err = plugin.Auth(ctx, map[string]string{"user": "carol", "pass": "secret"})
+	assert.EqualError(t, err, "unknown user")
+
+	config, err = plugin.Auth(ctx, map[string]string{"user": "bob", "public_key": "AAAAbobkey"})
+	require.NoError(t, err)
+	assert.Equal(t, configmap.Simple{"_remote": dir + "/bob"}, config)
+
+	// Through the proxy the remote is used
+	p := New(ctx, &Options{AuthProxy: "map:" + mapPath})
+	VFS, _, err := p.Call("alice", "secret", false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "alice"), filepath.Clean(VFS.Fs().Root()))
+
+	// The file is reloaded when it changes and errors are found
+	require.NoError(t, ioutil.WriteFile(mapPath, []byte(contents+"carol x y\n"), 0600))
+	_, err = newPlugin(ctx, "map:"+mapPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad password hash")
+	require.NoError(t, ioutil.WriteFile(mapPath, []byte(contents+"dave pubkey:AAAAdave "+dir+"/{user} extra\n"), 0600))
+	_, err = newPlugin(ctx, "map:"+mapPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "need 3 fields")
+	require.NoError(t, ioutil.WriteFile(mapPath, []byte("dave pubkey:AAAAdave "+dir+"/{user}\n"), 0600))
+	_, err = plugin.Auth(ctx, map[string]string{"user": "alice", "pass": "secret"})
+	assert.EqualError(t, err, "unknown user")
+	_, err = plugin.Auth(ctx, map[string]string{"user": "dave", "public_key": "AAAAdave"})
+	assert.NoError(t, err)
+}
+
+func TestTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-proxy")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "me"), 0777))
+
+	ctx := context.Background()
+	template := fmt.Sprintf(`{"type":"local","_root":%q,"description":"{pass}","_obscure":"description"}`, dir+"/{user}")
+	plugin, err := newPlugin(ctx, "template:"+template)
+	require.NoError(t, err)
+	config, err := plugin.Auth(ctx, map[string]string{"user": "me", "pass": "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, configmap.Simple{
+		"type":        "local",
+		"_root":       dir + "/me",
+		"description": "secret",
+		"_obscure":    "description",
+	}, config)
+	_, err = plugin.Auth(ctx, map[string]string{"user": "../me", "pass": "secret"})
+	assert.Error(t, err)
+
+	// From a file
+	templatePath := filepath.Join(dir, "template.json")
+	require.NoError(t, ioutil.WriteFile(templatePath, []byte(template), 0600))
+	p := New(ctx, &Options{AuthProxy: "template:" + templatePath})
+	VFS, _, err := p.Call("me", "secret", false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "me"), filepath.Clean(VFS.Fs().Root()))
+}
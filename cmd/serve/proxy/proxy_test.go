@@ -123,6 +123,7 @@ func TestRun(t *testing.T) {
 		t.Run("Get", func(t *testing.T) {
 			assert.Equal(t, vfs, p.Get(testUser))
 			assert.Nil(t, p.Get("unknown"))
+			assert.Nil(t, p.Groups(testUser))
 		})
 
 		// now try again from the cache
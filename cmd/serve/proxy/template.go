@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs/config/configmap"
+)
+
+func init() {
+	RegisterPlugin("template", newTemplate)
+}
+
+// template is an auth proxy which fills in a config template with the
+// user's credentials.
+//
+// It doesn't check the credentials itself so it must only be used
+// with backends which do, for example sftp or ftp.
+type template struct {
+	config map[string]string
+}
+
+// newTemplate makes a template plugin from the JSON config in arg,
+// or in the file arg if it doesn't start with "{"
+func newTemplate(ctx context.Context, arg string) (Plugin, error) {
+	data := []byte(arg)
+	if !strings.HasPrefix(strings.TrimSpace(arg), "{") {
+		var err error
+		data, err = ioutil.ReadFile(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t := &template{}
+	err := json.Unmarshal(data, &t.config)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad config template")
+	}
+	if t.config["type"] == "" && t.config["_remote"] == "" {
+		return nil, errors.New("config template needs type or _remote")
+	}
+	return t, nil
+}
+
+// Auth implements Plugin
+func (t *template) Auth(ctx context.Context, in map[string]string) (configmap.Simple, error) {
+	config := configmap.Simple{}
+	for key, value := range t.config {
+		value, err := expandTemplate(value, in)
+		if err != nil {
+			return nil, err
+		}
+		config[key] = value
+	}
+	if _, ok := config["_root"]; !ok && config["_remote"] == "" {
+		config["_root"] = ""
+	}
+	return config, nil
+}
@@ -0,0 +1,81 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/fshttp"
+)
+
+// maximum size of the webhook response read
+const maxWebhookResponse = 1024 * 1024
+
+func init() {
+	RegisterPlugin("webhook", newWebhook)
+}
+
+// webhook is an auth proxy which POSTs the input to a URL and reads
+// the config from the response
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// newWebhook makes a webhook plugin which calls rawURL
+func newWebhook(ctx context.Context, rawURL string) (Plugin, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("need an http or https URL not %q", rawURL)
+	}
+	return &webhook{
+		url:    rawURL,
+		client: fshttp.NewClient(ctx),
+	}, nil
+}
+
+// Auth implements Plugin
+func (w *webhook) Auth(ctx context.Context, in map[string]string) (configmap.Simple, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhook failed")
+	}
+	defer fs.CheckClose(resp.Body, &err)
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+	if err != nil {
+		return nil, errors.Wrap(err, "webhook failed to read response")
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, errors.New("webhook refused login")
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, errors.Errorf("webhook returned %s: %q", resp.Status, strings.TrimSpace(string(data)))
+	}
+	var config configmap.Simple
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "webhook returned bad JSON: %q", string(data))
+	}
+	return config, nil
+}
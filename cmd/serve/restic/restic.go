@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
@@ -32,6 +33,8 @@ var (
 	appendOnly   bool
 	privateRepos bool
 	cacheObjects bool
+	quota        = fs.SizeSuffix(-1)
+	adminUser    string
 )
 
 func init() {
@@ -41,6 +44,8 @@ func init() {
 	flags.BoolVarP(flagSet, &appendOnly, "append-only", "", false, "disallow deletion of repository data")
 	flags.BoolVarP(flagSet, &privateRepos, "private-repos", "", false, "users can only access their private repo")
 	flags.BoolVarP(flagSet, &cacheObjects, "cache-objects", "", true, "cache listed objects")
+	flags.FVarP(flagSet, &quota, "quota", "", "maximum size of each repository, or each user with --private-repos")
+	flags.StringVarP(flagSet, &adminUser, "admin-user", "", "", "read-only user who may access all repos and delete in append-only mode")
 }
 
 // Command definition for cobra
@@ -128,6 +133,45 @@ these **must** end with /.  Eg
 
 The "--private-repos" flag can be used to limit users to repositories starting
 with a path of ` + "`/<username>/`" + `.
+
+#### Quotas ####
+
+The "--quota" flag limits the size of each repository, or with
+"--private-repos" the total size of each user's repositories, for
+example "--quota 100G". Uploads which would take the repository over
+its quota are refused with "413 Request Entity Too Large" which restic
+reports as an error. Existing data is never removed to make space.
+
+The size of a repository is read by listing it the first time it is
+written to, then kept up to date as files are added and deleted, so
+changes made to the remote by other means won't be noticed until
+rclone is restarted.
+
+#### Admin user ####
+
+The "--admin-user" flag names a user who may read every repository
+regardless of "--private-repos" and who may delete files even with
+"--append-only". This lets the admin run "restic forget" to remove
+old snapshots while the normal users can only add data. The admin is
+otherwise read-only, so may not upload files or create repositories.
+
+#### Usage statistics ####
+
+A GET request to "/.stats" (under "--baseurl" if set) returns a JSON
+object with the size, number of objects, time of the newest snapshot
+and quota of each repository, or each user with "--private-repos",
+for example
+
+    $ curl -u admin:password http://localhost:8080/.stats
+    {"repos":{"user1":{"size":1234,"objects":12,"lastBackup":"2020-06-09T16:07:32Z"}}}
+
+The remote is listed to make the statistics, which may be slow on
+large remotes, so this is done at most once a minute. In between the
+statistics are kept up to date with the changes made through the
+server.
+With "--private-repos" users see only their own entry. If
+"--admin-user" is set then the admin sees every repository and, without
+"--private-repos", no one else may read the statistics.
 ` + httplib.Help,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(1, 1, command, args)
@@ -170,6 +214,7 @@ type Server struct {
 	*httplib.Server
 	f     fs.Fs
 	cache *cache
+	usage *usageTracker
 }
 
 // NewServer returns an HTTP server that speaks the rest protocol
@@ -179,6 +224,7 @@ func NewServer(f fs.Fs, opt *httplib.Options) *Server {
 		Server: httplib.NewServer(mux, opt),
 		f:      f,
 		cache:  newCache(),
+		usage:  newUsageTracker(),
 	}
 	mux.HandleFunc(s.Opt.BaseURL+"/", s.ServeHTTP)
 	return s
@@ -227,8 +273,24 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	remote := makeRemote(path)
 	fs.Debugf(s.f, "%s %s", r.Method, path)
 
-	v := r.Context().Value(httplib.ContextUserKey)
-	if privateRepos && (v == nil || !strings.HasPrefix(path, "/"+v.(string)+"/")) {
+	user, _ := r.Context().Value(httplib.ContextUserKey).(string)
+	isAdmin := adminUser != "" && user == adminUser
+	if path == statsPath {
+		if r.Method != "GET" {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		s.serveStats(w, r, user, isAdmin)
+		return
+	}
+	if privateRepos && !isAdmin && (user == "" || !strings.HasPrefix(path, "/"+user+"/")) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	// The admin user may read and delete but not write
+	if isAdmin && r.Method == "POST" {
+		fs.Errorf(remote, "Post request: refusing write by admin user %q", user)
 		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 		return
 	}
@@ -250,7 +312,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		case "POST":
 			s.postObject(w, r, remote)
 		case "DELETE":
-			s.deleteObject(w, r, remote)
+			s.deleteObject(w, r, remote, isAdmin)
 		default:
 			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 		}
@@ -285,19 +347,57 @@ func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, remote stri
 
 // postObject posts an object to the repository
 func (s *Server) postObject(w http.ResponseWriter, r *http.Request, remote string) {
-	if appendOnly {
+	oldSize := int64(-1)
+	if appendOnly || quota >= 0 {
 		// make sure the file does not exist yet
-		_, err := s.newObject(r.Context(), remote)
+		old, err := s.newObject(r.Context(), remote)
 		if err == nil {
-			fs.Errorf(remote, "Post request: file already exists, refusing to overwrite in append-only mode")
-			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			if appendOnly {
+				fs.Errorf(remote, "Post request: file already exists, refusing to overwrite in append-only mode")
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+				return
+			}
+			oldSize = old.Size()
+		}
+	}
 
+	in := r.Body
+	if quota >= 0 {
+		key := repoKey(remote)
+		_, err := s.usage.reserve(r.Context(), s.f, key, r.ContentLength, int64(quota))
+		if err == errQuotaExceeded {
+			fs.Errorf(remote, "Post request: refusing upload of %d bytes as repository is over quota", r.ContentLength)
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
+			fs.Errorf(remote, "Post request: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
+		if r.ContentLength < 0 {
+			qr := &quotaReader{
+				ctx:   r.Context(),
+				in:    r.Body,
+				usage: s.usage,
+				f:     s.f,
+				key:   key,
+				quota: int64(quota),
+			}
+			defer qr.release()
+			in = ioutil.NopCloser(qr)
+		} else {
+			defer s.usage.release(key, r.ContentLength)
+		}
 	}
 
-	o, err := operations.RcatSize(r.Context(), s.f, remote, r.Body, r.ContentLength, time.Now())
+	o, err := operations.RcatSize(r.Context(), s.f, remote, in, r.ContentLength, time.Now())
 	if err != nil {
+		if errors.Is(err, errQuotaExceeded) {
+			fs.Errorf(remote, "Post request: upload exceeded the repository quota")
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
 		err = accounting.Stats(r.Context()).Error(err)
 		fs.Errorf(remote, "Post request rcat error: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -307,11 +407,12 @@ func (s *Server) postObject(w http.ResponseWriter, r *http.Request, remote strin
 
 	// if successfully uploaded add to cache
 	s.cache.add(remote, o)
+	s.usage.added(o, oldSize)
 }
 
 // delete the remote
-func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request, remote string) {
-	if appendOnly {
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request, remote string, isAdmin bool) {
+	if appendOnly && !isAdmin {
 		parts := strings.Split(r.URL.Path, "/")
 
 		// if path doesn't end in "/locks/:name", disallow the operation
@@ -340,6 +441,7 @@ func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request, remote str
 
 	// remove object from cache
 	s.cache.remove(remote)
+	s.usage.removed(o)
 }
 
 // listItem is an element returned for the restic v2 list response
@@ -432,3 +534,43 @@ func (s *Server) createRepo(w http.ResponseWriter, r *http.Request, remote strin
 		}
 	}
 }
+
+// statsPath is the path of the usage statistics endpoint
+const statsPath = "/.stats"
+
+// statsResponse is returned by the usage statistics endpoint
+type statsResponse struct {
+	Repos map[string]repoUsage `json:"repos"`
+}
+
+// serveStats returns the usage of the repositories user may see
+func (s *Server) serveStats(w http.ResponseWriter, r *http.Request, user string, isAdmin bool) {
+	if adminUser != "" && !isAdmin && !privateRepos {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	repos, err := s.usage.stats(r.Context(), s.f)
+	if err != nil {
+		fs.Errorf(nil, "Stats request failed: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	out := statsResponse{Repos: map[string]repoUsage{}}
+	for key, usage := range repos {
+		if privateRepos && !isAdmin && key != user {
+			continue
+		}
+		if quota >= 0 {
+			usage.Quota = int64(quota)
+		}
+		if key == "" {
+			key = "/"
+		}
+		out.Repos[key] = usage
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(out)
+	if err != nil {
+		fs.Errorf(nil, "Failed to write stats: %v", err)
+	}
+}
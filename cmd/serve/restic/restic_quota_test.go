@@ -0,0 +1,259 @@
+package restic
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/httplib"
+	"github.com/rclone/rclone/cmd/serve/httplib/httpflags"
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUserRequest returns a new HTTP request authenticated as user
+func newUserRequest(t testing.TB, user, method, path string, body string) *http.Request {
+	req := newRequest(t, method, path, strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), httplib.ContextUserKey, user))
+}
+
+// getStats reads the stats endpoint as user
+func getStats(t *testing.T, srv *Server, user string) statsResponse {
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, newUserRequest(t, user, "GET", statsPath, ""))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var stats statsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stats))
+	return stats
+}
+
+func TestRepoKey(t *testing.T) {
+	for _, test := range []struct {
+		remote string
+		want   string
+	}{
+		{"config", ""},
+		{"data/12/1234", ""},
+		{"keys/1234", ""},
+		{"user/repo/config", "user/repo"},
+		{"user/repo/data/12/1234", "user/repo"},
+		{"user/repo/snapshots/1234", "user/repo"},
+		{"user/repo/locks", "user/repo/locks"},
+	} {
+		assert.Equal(t, test.want, repoKey(test.remote), test.remote)
+	}
+
+	prev := privateRepos
+	privateRepos = true
+	defer func() { privateRepos = prev }()
+	assert.Equal(t, "user", repoKey("user/repo/data/12/1234"))
+	assert.Equal(t, "user", repoKey("user/config"))
+}
+
+// TestResticQuota tests quotas, the admin user and the stats endpoint
+func TestResticQuota(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "rclone-restic-test-")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempdir))
+	}()
+
+	prevPrivate, prevAppend, prevQuota, prevAdmin := privateRepos, appendOnly, quota, adminUser
+	privateRepos, appendOnly, quota, adminUser = true, true, fs.SizeSuffix(20), "admin"
+	defer func() {
+		privateRepos, appendOnly, quota, adminUser = prevPrivate, prevAppend, prevQuota, prevAdmin
+	}()
+
+	f := cmd.NewFsSrc([]string{tempdir})
+	srv := NewServer(f, &httpflags.Opt)
+
+	for _, user := range []string{"alice", "bob"} {
+		checkRequest(t, srv.ServeHTTP,
+			newUserRequest(t, user, "POST", "/"+user+"/?create=true", ""),
+			[]wantFunc{wantCode(http.StatusOK)})
+	}
+
+	// Uploads up to the quota are allowed then refused
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "POST", "/alice/config", "0123456789"),
+		[]wantFunc{wantCode(http.StatusOK)})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "POST", "/alice/snapshots/1234", "01234"),
+		[]wantFunc{wantCode(http.StatusOK)})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "POST", "/alice/data/123456", "0123456789"),
+		[]wantFunc{wantCode(http.StatusRequestEntityTooLarge)})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "GET", "/alice/data/123456", ""),
+		[]wantFunc{wantCode(http.StatusNotFound)})
+
+	// Quotas are per user
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "bob", "POST", "/bob/data/123456", "0123456789"),
+		[]wantFunc{wantCode(http.StatusOK)})
+
+	// Unknown length uploads are limited too
+	req := newUserRequest(t, "alice", "POST", "/alice/data/abcdef", "0123456789")
+	req.ContentLength = -1
+	checkRequest(t, srv.ServeHTTP, req, []wantFunc{wantCode(http.StatusRequestEntityTooLarge)})
+
+	// Normal users can't delete or see other users' repos
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "DELETE", "/alice/config", ""),
+		[]wantFunc{wantCode(http.StatusForbidden)})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "GET", "/bob/data/123456", ""),
+		[]wantFunc{wantCode(http.StatusForbidden)})
+
+	stats := getStats(t, srv, "alice")
+	require.Equal(t, []string{"alice"}, keys(stats.Repos))
+	alice := stats.Repos["alice"]
+	assert.Equal(t, int64(15), alice.Size)
+	assert.Equal(t, int64(2), alice.Objects)
+	assert.Equal(t, int64(20), alice.Quota)
+	assert.NotNil(t, alice.LastBackup)
+
+	stats = getStats(t, srv, "admin")
+	assert.Equal(t, []string{"alice", "bob"}, keys(stats.Repos))
+	assert.Nil(t, stats.Repos["bob"].LastBackup)
+
+	// The admin can read and delete in any repo but not overwrite
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "admin", "GET", "/bob/data/123456", ""),
+		[]wantFunc{wantCode(http.StatusOK), wantBody("0123456789")})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "admin", "POST", "/alice/config", "new"),
+		[]wantFunc{wantCode(http.StatusForbidden)})
+
+	// The admin is read-only apart from deleting
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "admin", "POST", "/bob/keys/1234", "new"),
+		[]wantFunc{wantCode(http.StatusForbidden)})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "admin", "POST", "/admin/?create=true", ""),
+		[]wantFunc{wantCode(http.StatusForbidden)})
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "admin", "DELETE", "/alice/config", ""),
+		[]wantFunc{wantCode(http.StatusOK)})
+
+	// Deleting frees up space
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "POST", "/alice/data/123456", "0123456789"),
+		[]wantFunc{wantCode(http.StatusOK)})
+
+	stats = getStats(t, srv, "alice")
+	assert.Equal(t, int64(15), stats.Repos["alice"].Size)
+
+	// Without private repos the stats are admin only
+	privateRepos = false
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "GET", statsPath, ""),
+		[]wantFunc{wantCode(http.StatusForbidden)})
+}
+
+// TestResticStatsCached tests the stats aren't listed every time
+func TestResticStatsCached(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "rclone-restic-test-")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempdir))
+	}()
+
+	prevPrivate, prevExpiry := privateRepos, statsExpiry
+	privateRepos = true
+	defer func() {
+		privateRepos, statsExpiry = prevPrivate, prevExpiry
+	}()
+
+	f := cmd.NewFsSrc([]string{tempdir})
+	srv := NewServer(f, &httpflags.Opt)
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "POST", "/alice/config", "0123456789"),
+		[]wantFunc{wantCode(http.StatusOK)})
+	assert.Equal(t, int64(10), getStats(t, srv, "alice").Repos["alice"].Size)
+
+	// Changes made through the server are seen straight away but
+	// changes made outside it wait for the next listing
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempdir, "alice", "outside"), []byte("01234"), 0600))
+	checkRequest(t, srv.ServeHTTP,
+		newUserRequest(t, "alice", "POST", "/alice/keys/1234", "012"),
+		[]wantFunc{wantCode(http.StatusOK)})
+	assert.Equal(t, int64(13), getStats(t, srv, "alice").Repos["alice"].Size)
+
+	statsExpiry = 0
+	assert.Equal(t, int64(18), getStats(t, srv, "alice").Repos["alice"].Size)
+}
+
+// TestQuotaReader tests uploads of unknown length reserve space as
+// they are read
+func TestQuotaReader(t *testing.T) {
+	ctx := context.Background()
+	tempdir, err := ioutil.TempDir("", "rclone-restic-test-")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempdir))
+	}()
+	f := cmd.NewFsSrc([]string{tempdir})
+	u := newUsageTracker()
+
+	newReader := func(in string) *quotaReader {
+		return &quotaReader{ctx: ctx, in: strings.NewReader(in), usage: u, f: f, key: "repo", quota: 15}
+	}
+	r1 := newReader("0123456789")
+	data, err := ioutil.ReadAll(r1)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+	assert.Equal(t, int64(10), u.reserved["repo"])
+
+	// A second upload while the first is in progress is limited
+	r2 := newReader("0123456789")
+	_, err = ioutil.ReadAll(r2)
+	assert.Equal(t, errQuotaExceeded, err)
+
+	r1.release()
+	r2.release()
+	assert.Equal(t, int64(0), u.reserved["repo"])
+}
+
+// TestResticStatsMerge tests listing the remote for the stats keeps
+// the usage already being tracked
+func TestResticStatsMerge(t *testing.T) {
+	ctx := context.Background()
+	tempdir, err := ioutil.TempDir("", "rclone-restic-test-")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tempdir))
+	}()
+	require.NoError(t, os.MkdirAll(filepath.Join(tempdir, "repo"), 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempdir, "repo", "config"), []byte("01234"), 0600))
+	f := cmd.NewFsSrc([]string{tempdir})
+	u := newUsageTracker()
+
+	require.NoError(t, u.load(ctx, f, "repo"))
+	require.NoError(t, u.load(ctx, f, "new"))
+	repo, newRepo := u.repos["repo"], u.repos["new"]
+
+	stats, err := u.stats(ctx, f)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats["repo"].Size)
+	assert.Equal(t, repo, u.repos["repo"])
+	assert.Equal(t, newRepo, u.repos["new"])
+}
+
+// keys returns the sorted keys of repos
+func keys(repos map[string]repoUsage) (out []string) {
+	for key := range repos {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,302 @@
+package restic
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/walk"
+	"golang.org/x/sync/singleflight"
+)
+
+// repoUsage is the storage used by a repository
+type repoUsage struct {
+	Size       int64      `json:"size"`
+	Objects    int64      `json:"objects"`
+	LastBackup *time.Time `json:"lastBackup,omitempty"` // time of the newest snapshot
+	Quota      int64      `json:"quota,omitempty"`
+}
+
+// statsExpiry is how long the usage of all the repositories from a
+// full listing is used for the stats before listing the remote again
+var statsExpiry = time.Minute
+
+// allRepos is the scans key for a listing of the whole remote - it
+// can't be a repoKey as those have no leading "/"
+const allRepos = "/"
+
+// usageTracker keeps track of the storage used by the repositories.
+//
+// The usage of a repository is read by listing it the first time it
+// is needed, then kept up to date as objects are added and removed.
+//
+// The listings are done without holding the lock so they don't hold
+// up other repositories, and concurrent listings of the same
+// repository are shared.
+type usageTracker struct {
+	mu       sync.Mutex
+	repos    map[string]*repoUsage // by repoKey
+	reserved map[string]int64      // bytes being uploaded by repoKey
+	scanned  time.Time             // when the whole remote was last listed
+	scans    singleflight.Group    // listings in progress by repoKey or allRepos
+}
+
+// newUsageTracker makes a new usageTracker
+func newUsageTracker() *usageTracker {
+	return &usageTracker{
+		repos:    map[string]*repoUsage{},
+		reserved: map[string]int64{},
+	}
+}
+
+// matches the parts of a repository
+var matchRepoPart = regexp.MustCompile(`^(.*?)/?(?:config|(?:index|keys|locks|snapshots)/[^/]*|data/(?:[^/]*/)?[^/]*)$`)
+
+// repoKey returns the repository the remote belongs to. With
+// --private-repos this is the user's directory so quotas and stats
+// are per user.
+func repoKey(remote string) string {
+	remote = strings.Trim(remote, "/")
+	if privateRepos {
+		if i := strings.IndexRune(remote, '/'); i >= 0 {
+			return remote[:i]
+		}
+		return remote
+	}
+	if parts := matchRepoPart.FindStringSubmatch(remote); parts != nil {
+		return parts[1]
+	}
+	return remote
+}
+
+// isSnapshot returns true if remote is a snapshot file
+func isSnapshot(remote string) bool {
+	dir := remote
+	if i := strings.LastIndex(dir, "/"); i >= 0 {
+		dir = dir[:i]
+	} else {
+		return false
+	}
+	return dir == "snapshots" || strings.HasSuffix(dir, "/snapshots")
+}
+
+// add accounts the object o to usage
+func (usage *repoUsage) add(o fs.Object) {
+	usage.Size += o.Size()
+	usage.Objects++
+	if isSnapshot(o.Remote()) {
+		modTime := o.ModTime(context.Background())
+		if usage.LastBackup == nil || modTime.After(*usage.LastBackup) {
+			usage.LastBackup = &modTime
+		}
+	}
+}
+
+// scan lists dir returning the usage of each repository in it
+func scanUsage(ctx context.Context, f fs.Fs, dir string) (repos map[string]*repoUsage, err error) {
+	repos = map[string]*repoUsage{}
+	err = walk.ListR(ctx, f, dir, true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			if o, ok := entry.(fs.Object); ok {
+				key := repoKey(o.Remote())
+				usage := repos[key]
+				if usage == nil {
+					usage = &repoUsage{}
+					repos[key] = usage
+				}
+				usage.add(o)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if _, cause := fserrors.Cause(err); cause != fs.ErrorDirNotFound {
+			return nil, err
+		}
+	}
+	return repos, nil
+}
+
+// load makes sure the usage of the repository key has been read,
+// listing it if it hasn't been seen yet - call without the lock held
+func (u *usageTracker) load(ctx context.Context, f fs.Fs, key string) error {
+	u.mu.Lock()
+	usage := u.repos[key]
+	u.mu.Unlock()
+	if usage != nil {
+		return nil
+	}
+	_, err, _ := u.scans.Do(key, func() (interface{}, error) {
+		repos, err := scanUsage(ctx, f, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read usage of %q", key)
+		}
+		usage := repos[key]
+		if usage == nil {
+			usage = &repoUsage{}
+		}
+		u.mu.Lock()
+		if u.repos[key] == nil {
+			u.repos[key] = usage
+		}
+		u.mu.Unlock()
+		return nil, nil
+	})
+	return err
+}
+
+// reserve checks there is space for size more bytes in the
+// repository key, reserving it if so.
+//
+// If size is negative (unknown) it only checks there is some space
+// left. It returns an error if the quota is exceeded.
+func (u *usageTracker) reserve(ctx context.Context, f fs.Fs, key string, size int64, quota int64) (left int64, err error) {
+	err = u.load(ctx, f, key)
+	if err != nil {
+		return 0, err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	usage := u.repos[key]
+	if usage == nil {
+		// a concurrent listing of the whole remote didn't find it
+		usage = &repoUsage{}
+		u.repos[key] = usage
+	}
+	left = quota - usage.Size - u.reserved[key]
+	if size < 0 {
+		if left <= 0 {
+			return 0, errQuotaExceeded
+		}
+		return left, nil
+	}
+	if size > left {
+		return 0, errQuotaExceeded
+	}
+	u.reserved[key] += size
+	return left - size, nil
+}
+
+// release releases size bytes reserved with reserve
+func (u *usageTracker) release(key string, size int64) {
+	if size <= 0 {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.reserved[key] -= size
+	if u.reserved[key] <= 0 {
+		delete(u.reserved, key)
+	}
+}
+
+// added accounts for o being uploaded, replacing an object of
+// oldSize bytes if oldSize >= 0
+func (u *usageTracker) added(o fs.Object, oldSize int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	usage := u.repos[repoKey(o.Remote())]
+	if usage == nil {
+		return
+	}
+	usage.add(o)
+	if oldSize >= 0 {
+		usage.Size -= oldSize
+		usage.Objects--
+	}
+}
+
+// removed accounts for o being deleted
+func (u *usageTracker) removed(o fs.Object) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	usage := u.repos[repoKey(o.Remote())]
+	if usage == nil {
+		return
+	}
+	usage.Size -= o.Size()
+	usage.Objects--
+}
+
+// stats returns the usage of all the repositories in the remote.
+//
+// This lists the whole remote, remembering the result for quotas, if
+// it wasn't listed in the last statsExpiry. Otherwise the usage which
+// has been kept up to date since then is returned.
+func (u *usageTracker) stats(ctx context.Context, f fs.Fs) (map[string]repoUsage, error) {
+	u.mu.Lock()
+	fresh := time.Since(u.scanned) < statsExpiry
+	u.mu.Unlock()
+	if !fresh {
+		_, err, _ := u.scans.Do(allRepos, func() (interface{}, error) {
+			repos, err := scanUsage(ctx, f, "")
+			if err != nil {
+				return nil, err
+			}
+			u.mu.Lock()
+			// Merge rather than replace so repositories loaded
+			// for quotas while listing aren't lost
+			for key, usage := range repos {
+				if existing := u.repos[key]; existing != nil {
+					*existing = *usage
+				} else {
+					u.repos[key] = usage
+				}
+			}
+			u.scanned = time.Now()
+			u.mu.Unlock()
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	result := make(map[string]repoUsage, len(u.repos))
+	for key, usage := range u.repos {
+		result[key] = *usage
+	}
+	return result, nil
+}
+
+// errQuotaExceeded is returned when an upload would exceed the quota
+var errQuotaExceeded = errors.New("quota exceeded")
+
+// quotaReader reserves the bytes read from it in the repository key
+// as they are read, returning errQuotaExceeded if that would take the
+// repository over quota. The reserved bytes should be released with
+// release when the upload is done.
+type quotaReader struct {
+	ctx      context.Context
+	in       io.Reader
+	usage    *usageTracker
+	f        fs.Fs
+	key      string
+	quota    int64
+	reserved int64
+}
+
+// Read implements io.Reader
+func (q *quotaReader) Read(p []byte) (n int, err error) {
+	n, err = q.in.Read(p)
+	if n > 0 {
+		_, reserveErr := q.usage.reserve(q.ctx, q.f, q.key, int64(n), q.quota)
+		if reserveErr != nil {
+			return n, reserveErr
+		}
+		q.reserved += int64(n)
+	}
+	return n, err
+}
+
+// release releases the bytes reserved while reading
+func (q *quotaReader) release() {
+	q.usage.release(q.key, q.reserved)
+}
@@ -14,6 +14,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/pkg/sftp"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/cmd/serve/acl/aclflags"
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/hash"
 	"github.com/rclone/rclone/lib/terminal"
@@ -39,6 +41,11 @@ type execCommand struct {
 	Command string
 }
 
+// errRsyncNotSupported is returned for rsync exec requests as the
+// rsync protocol isn't implemented yet - see the rsync section of the
+// help
+var errRsyncNotSupported = errors.New("rsync is not supported by rclone serve sftp yet - use rclone sync with an sftp remote, or rsync on an sshfs mount instead")
+
 var shellUnEscapeRegex = regexp.MustCompile(`\\(.)`)
 
 // Unescape a string that was escaped by rclone
@@ -50,118 +57,201 @@ func shellUnEscape(str string) string {
 
 // Info about the current connection
 type conn struct {
-	vfs      *vfs.VFS
+	vfs      *acl.VFS
 	handlers sftp.Handlers
 	what     string
 }
 
-// execCommand implements an extremely limited number of commands to
-// interoperate with the rclone sftp backend
-func (c *conn) execCommand(ctx context.Context, out io.Writer, command string) (err error) {
+// shellSplit splits a command line into words, removing backslash
+// escapes and single and double quotes
+func shellSplit(str string) (words []string) {
+	var (
+		word    strings.Builder
+		inWord  bool
+		escaped bool
+		quote   rune
+	)
+	for _, r := range str {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' {
+				escaped = true
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// hashCommand returns the hash type for a command like md5sum or
+// sha256sum
+func hashCommand(binary string) (ht hash.Type, ok bool) {
+	if !strings.HasSuffix(binary, "sum") {
+		return hash.None, false
+	}
+	err := ht.Set(strings.TrimSuffix(binary, "sum"))
+	return ht, err == nil && ht != hash.None
+}
+
+// execCommand implements a limited number of commands to
+// interoperate with the rclone sftp backend and scp clients
+//
+// in and out are the standard input and output of the command.
+func (c *conn) execCommand(ctx context.Context, in io.Reader, out io.Writer, command string) (err error) {
 	binary, args := command, ""
 	space := strings.Index(command, " ")
 	if space >= 0 {
 		binary = command[:space]
 		args = strings.TrimLeft(command[space+1:], " ")
 	}
-	args = shellUnEscape(args)
 	fs.Debugf(c.what, "exec command: binary = %q, args = %q", binary, args)
+	if ht, ok := hashCommand(binary); ok {
+		return c.hashSum(ctx, in, out, ht, args)
+	}
 	switch binary {
 	case "df":
-		about := c.vfs.Fs().Features().About
-		if about == nil {
-			return errors.New("df not supported")
+		return c.df(out)
+	case "echo":
+		args = shellUnEscape(args)
+		// special cases for rclone command detection
+		if strings.HasPrefix(args, "'abc' | ") {
+			if ht, ok := hashCommand(strings.TrimPrefix(args, "'abc' | ")); ok {
+				if !c.vfs.Fs().Hashes().Contains(ht) {
+					return errors.Errorf("%v hash not supported", ht)
+				}
+				return c.hashSum(ctx, strings.NewReader("abc\n"), out, ht, "")
+			}
 		}
-		usage, err := about(ctx)
+		_, err = fmt.Fprintf(out, "%s\n", args)
 		if err != nil {
-			return errors.Wrap(err, "About failed")
-		}
-		total, used, free := int64(-1), int64(-1), int64(-1)
-		if usage.Total != nil {
-			total = *usage.Total / 1024
-		}
-		if usage.Used != nil {
-			used = *usage.Used / 1024
+			return errors.Wrap(err, "send output failed")
 		}
-		if usage.Free != nil {
-			free = *usage.Free / 1024
+	case "scp":
+		return c.scp(in, out, shellSplit(args))
+	case "rsync":
+		return errRsyncNotSupported
+	default:
+		return errors.Errorf("%q not implemented\n", command)
+	}
+	return nil
+}
+
+// df prints the usage of the remote in the format of "df -k"
+func (c *conn) df(out io.Writer) (err error) {
+	if c.vfs.Fs().Features().About == nil {
+		return errors.New("df not supported")
+	}
+	total, used, free := c.vfs.Statfs()
+	toK := func(n int64) int64 {
+		if n < 0 {
+			return n
 		}
-		perc := int64(0)
-		if total > 0 && used >= 0 {
-			perc = (100 * used) / total
+		return n / 1024
+	}
+	perc := int64(0)
+	if total > 0 && used >= 0 {
+		perc = (100 * used) / total
+	}
+	_, err = fmt.Fprintf(out, "Filesystem 1K-blocks Used Available Use%% Mounted on\n/dev/root %d %d %d %d%% /\n", toK(total), toK(used), toK(free), perc)
+	if err != nil {
+		return errors.Wrap(err, "send output failed")
+	}
+	return nil
+}
+
+// hashSum prints the ht hash of each of the files in args in the
+// format of md5sum. With no files, or "-", it hashes the input.
+func (c *conn) hashSum(ctx context.Context, in io.Reader, out io.Writer, ht hash.Type, args string) (err error) {
+	files := shellSplit(args)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	var lastErr error
+	options := true
+	for _, file := range files {
+		if options && file == "--" {
+			options = false
+			continue
+		} else if options && strings.HasPrefix(file, "-") && file != "-" {
+			// ignore options like --binary
+			continue
 		}
-		_, err = fmt.Fprintf(out, `		Filesystem                   1K-blocks      Used Available Use%% Mounted on
-/dev/root %d %d  %d  %d%% /
-`, total, used, free, perc)
+		hashSum, err := c.hashFile(ctx, in, ht, file)
 		if err != nil {
-			return errors.Wrap(err, "send output failed")
-		}
-	case "md5sum", "sha1sum":
-		ht := hash.MD5
-		if binary == "sha1sum" {
-			ht = hash.SHA1
+			fs.Debugf(c.what, "%s: %v", file, err)
+			lastErr = errors.Wrapf(err, "%s", file)
+			continue
 		}
-		var hashSum string
-		if args == "" {
-			// empty hash for no input
-			if ht == hash.MD5 {
-				hashSum = "d41d8cd98f00b204e9800998ecf8427e"
-			} else {
-				hashSum = "da39a3ee5e6b4b0d3255bfef95601890afd80709"
-			}
-			args = "-"
-		} else {
-			node, err := c.vfs.Stat(args)
-			if err != nil {
-				return errors.Wrapf(err, "hash failed finding file %q", args)
-			}
-			if node.IsDir() {
-				return errors.New("can't hash directory")
-			}
-			o, ok := node.DirEntry().(fs.ObjectInfo)
-			if !ok {
-				return errors.New("unexpected non file")
-			}
-			hashSum, err = o.Hash(ctx, ht)
-			if err != nil {
-				return errors.Wrap(err, "hash failed")
-			}
-		}
-		_, err = fmt.Fprintf(out, "%s  %s\n", hashSum, args)
+		_, err = fmt.Fprintf(out, "%s  %s\n", hashSum, file)
 		if err != nil {
 			return errors.Wrap(err, "send output failed")
 		}
-	case "echo":
-		// special cases for rclone command detection
-		switch args {
-		case "'abc' | md5sum":
-			if c.vfs.Fs().Hashes().Contains(hash.MD5) {
-				_, err = fmt.Fprintf(out, "0bee89b07a248e27c83fc3d5951213c1  -\n")
-				if err != nil {
-					return errors.Wrap(err, "send output failed")
-				}
-			} else {
-				return errors.New("md5 hash not supported")
-			}
-		case "'abc' | sha1sum":
-			if c.vfs.Fs().Hashes().Contains(hash.SHA1) {
-				_, err = fmt.Fprintf(out, "03cfd743661f07975fa2f1220c5194cbaff48451  -\n")
-				if err != nil {
-					return errors.Wrap(err, "send output failed")
-				}
-			} else {
-				return errors.New("sha1 hash not supported")
-			}
-		default:
-			_, err = fmt.Fprintf(out, "%s\n", args)
-			if err != nil {
-				return errors.Wrap(err, "send output failed")
-			}
+	}
+	return lastErr
+}
+
+// hashFile returns the ht hash of file, or the input if file is "-"
+func (c *conn) hashFile(ctx context.Context, in io.Reader, ht hash.Type, file string) (string, error) {
+	if file == "-" {
+		sums, err := hash.StreamTypes(in, hash.NewHashSet(ht))
+		if err != nil {
+			return "", errors.Wrap(err, "hash failed")
 		}
-	default:
-		return errors.Errorf("%q not implemented\n", command)
+		return sums[ht], nil
 	}
-	return nil
+	node, err := c.vfs.Stat(file)
+	if err != nil {
+		return "", errors.Wrap(err, "hash failed finding file")
+	}
+	if node.IsDir() {
+		return "", errors.New("can't hash directory")
+	}
+	err = c.vfs.Check(file, acl.PermRead)
+	if err != nil {
+		return "", err
+	}
+	o, ok := node.DirEntry().(fs.ObjectInfo)
+	if !ok {
+		return "", errors.New("unexpected non file")
+	}
+	hashSum, err := o.Hash(ctx, ht)
+	if err != nil {
+		return "", errors.Wrap(err, "hash failed")
+	}
+	if hashSum == "" {
+		return "", errors.Errorf("%v hash not available", ht)
+	}
+	return hashSum, nil
 }
 
 // handle a new incoming channel request
@@ -234,7 +324,7 @@ func (c *conn) handleChannel(newChannel ssh.NewChannel) {
 		}
 	} else {
 		var rc = uint32(0)
-		err := c.execCommand(context.TODO(), channel, command.Command)
+		err := c.execCommand(context.TODO(), channel, channel, command.Command)
 		if err != nil {
 			rc = 1
 			_, errPrint := fmt.Fprintf(channel.Stderr(), "%v\n", err)
@@ -282,7 +372,11 @@ func serveStdio(f fs.Fs) error {
 		stdin:  os.Stdin,
 		stdout: os.Stdout,
 	}
-	handlers := newVFSHandler(vfs.New(f, &vfsflags.Opt))
+	a, err := acl.New(&aclflags.Opt)
+	if err != nil {
+		return err
+	}
+	handlers := newVFSHandler(acl.NewVFS(vfs.New(f, &vfsflags.Opt), a, "", nil))
 	return serveChannel(sshChannel, handlers, "stdio")
 }
 
@@ -4,10 +4,23 @@
 package sftp
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+	"github.com/rclone/rclone/vfs"
+	"github.com/rclone/rclone/vfs/vfsflags"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestShellEscape(t *testing.T) {
@@ -24,3 +37,112 @@ func TestShellEscape(t *testing.T) {
 		assert.Equal(t, test.unescaped, got, fmt.Sprintf("Test %d unescaped = %q", i, test.unescaped))
 	}
 }
+
+func TestShellSplit(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"a b  c", []string{"a", "b", "c"}},
+		{`/path\ with\ spaces other`, []string{"/path with spaces", "other"}},
+		{`'single quoted' "double \"quoted\""`, []string{"single quoted", `double "quoted"`}},
+		{"/test/'\n'", []string{"/test/\n"}},
+		{`\$\(rm\ -rf\ /\)`, []string{"$(rm -rf /)"}},
+		{`''`, []string{""}},
+	} {
+		assert.Equal(t, test.want, shellSplit(test.in), test.in)
+	}
+}
+
+func TestHashCommand(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want hash.Type
+		ok   bool
+	}{
+		{"md5sum", hash.MD5, true},
+		{"sha1sum", hash.SHA1, true},
+		{"sha256sum", hash.SHA256, true},
+		{"crc32sum", hash.CRC32, true},
+		{"nonesum", hash.None, false},
+		{"potatosum", hash.None, false},
+		{"md5", hash.None, false},
+	} {
+		got, ok := hashCommand(test.in)
+		assert.Equal(t, test.ok, ok, test.in)
+		if ok {
+			assert.Equal(t, test.want, got, test.in)
+		}
+	}
+}
+
+// newTestConn makes a conn serving a temporary directory
+func newTestConn(t *testing.T) (c *conn, dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "rclone-serve-sftp")
+	require.NoError(t, err)
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+	a, err := acl.New(&acl.Options{})
+	require.NoError(t, err)
+	VFS := vfs.New(f, &vfsflags.Opt)
+	c = &conn{
+		vfs:  acl.NewVFS(VFS, a, "", nil),
+		what: "test",
+	}
+	return c, dir, func() {
+		VFS.Shutdown()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestExecHashSum(t *testing.T) {
+	c, dir, cleanup := newTestConn(t)
+	defer cleanup()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file with space.txt"), []byte("hello"), 0600))
+	ctx := context.Background()
+
+	run := func(command, input string) (string, error) {
+		var out bytes.Buffer
+		err := c.execCommand(ctx, strings.NewReader(input), &out, command)
+		return out.String(), err
+	}
+
+	out, err := run(`md5sum /file\ with\ space.txt`, "")
+	require.NoError(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592  /file with space.txt\n", out)
+
+	out, err = run(`sha256sum '/file with space.txt'`, "")
+	require.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  /file with space.txt\n", out)
+
+	out, err = run("sha1sum", "")
+	require.NoError(t, err)
+	assert.Equal(t, "da39a3ee5e6b4b0d3255bfef95601890afd80709  -\n", out)
+
+	out, err = run("md5sum -", "abc\n")
+	require.NoError(t, err)
+	assert.Equal(t, "0bee89b07a248e27c83fc3d5951213c1  -\n", out)
+
+	out, err = run("echo 'abc' | md5sum", "")
+	require.NoError(t, err)
+	assert.Equal(t, "0bee89b07a248e27c83fc3d5951213c1  -\n", out)
+
+	out, err = run("echo 'abc' | sha256sum", "")
+	require.NoError(t, err)
+	assert.Equal(t, "edeaaff3f1774ad2888673770c6d64097e391bc362d7d6fb34982ddf0efd18cb  -\n", out)
+
+	out, err = run("md5sum /missing /file\\ with\\ space.txt", "")
+	require.Error(t, err)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592  /file with space.txt\n", out)
+
+	out, err = run("df -k /", "")
+	require.NoError(t, err)
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 3)
+	assert.Len(t, strings.Fields(lines[1]), 6)
+
+	_, err = run("rsync --server -vlogDtpre.iLsfxC . /", "")
+	assert.Equal(t, errRsyncNotSupported, err)
+}
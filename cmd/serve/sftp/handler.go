@@ -10,18 +10,18 @@ import (
 	"time"
 
 	"github.com/pkg/sftp"
-	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/cmd/serve/acl"
 	"github.com/rclone/rclone/vfs"
 )
 
 // vfsHandler converts the VFS to be served by SFTP
 type vfsHandler struct {
-	*vfs.VFS
+	*acl.VFS
 }
 
 // vfsHandler returns a Handlers object with the test handlers.
-func newVFSHandler(vfs *vfs.VFS) sftp.Handlers {
-	v := vfsHandler{VFS: vfs}
+func newVFSHandler(VFS *acl.VFS) sftp.Handlers {
+	v := vfsHandler{VFS: VFS}
 	return sftp.Handlers{
 		FileGet:  v,
 		FilePut:  v,
@@ -104,7 +104,6 @@ func (f listerat) ListAt(ls []os.FileInfo, offset int64) (int, error) {
 
 func (v vfsHandler) Filelist(r *sftp.Request) (l sftp.ListerAt, err error) {
 	var node vfs.Node
-	var handle vfs.Handle
 	switch r.Method {
 	case "List":
 		node, err = v.Stat(r.Filepath)
@@ -114,12 +113,7 @@ func (v vfsHandler) Filelist(r *sftp.Request) (l sftp.ListerAt, err error) {
 		if !node.IsDir() {
 			return nil, syscall.ENOTDIR
 		}
-		handle, err = node.Open(os.O_RDONLY)
-		if err != nil {
-			return nil, err
-		}
-		defer fs.CheckClose(handle, &err)
-		fis, err := handle.Readdir(-1)
+		fis, err := v.ReadDir(r.Filepath)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,453 @@
+//go:build !plan9
+// +build !plan9
+
+package sftp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/vfs"
+)
+
+// The scp protocol as spoken by OpenSSH's scp when it runs "scp -t"
+// (sink) or "scp -f" (source) on the server.
+//
+// Each message is a line starting with a letter
+//
+//     C0644 <size> <name>  - a file of size bytes follows
+//     D0755 0 <name>       - enter a directory
+//     E                    - leave the directory
+//     T<mtime> 0 <atime> 0 - times of the next file or directory
+//
+// and is acknowledged with a single byte, 0 for OK, or 1 (warning) or
+// 2 (fatal) followed by a message line.
+
+// scpOptions are the options for the scp command
+type scpOptions struct {
+	sink      bool // -t: receive files
+	source    bool // -f: send files
+	recursive bool // -r: copy directories
+	preserve  bool // -p: preserve modification times
+	targetDir bool // -d: the target must be a directory
+	paths     []string
+}
+
+// parseScpArgs parses the arguments of the scp command
+func parseScpArgs(args []string) (opt scpOptions, err error) {
+	for i, arg := range args {
+		if arg == "--" {
+			opt.paths = append(opt.paths, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			opt.paths = append(opt.paths, arg)
+			continue
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 't':
+				opt.sink = true
+			case 'f':
+				opt.source = true
+			case 'r':
+				opt.recursive = true
+			case 'p':
+				opt.preserve = true
+			case 'd':
+				opt.targetDir = true
+			case 'v':
+			default:
+				return opt, errors.Errorf("scp: unsupported option -%c", flag)
+			}
+		}
+	}
+	if opt.sink == opt.source {
+		return opt, errors.New("scp: need exactly one of -t or -f")
+	}
+	if len(opt.paths) == 0 {
+		return opt, errors.New("scp: no paths")
+	}
+	if opt.sink && len(opt.paths) != 1 {
+		return opt, errors.New("scp: need exactly one target")
+	}
+	return opt, nil
+}
+
+// scpPath makes an scp path into a path in the VFS
+func scpPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// scpSession is a running scp command
+type scpSession struct {
+	c     *conn
+	opt   scpOptions
+	in    *bufio.Reader
+	out   io.Writer
+	err   error // the last error for a single file
+	times *[2]time.Time
+}
+
+// scp runs the scp command with args
+func (c *conn) scp(in io.Reader, out io.Writer, args []string) error {
+	opt, err := parseScpArgs(args)
+	if err != nil {
+		return err
+	}
+	s := &scpSession{
+		c:   c,
+		opt: opt,
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+	if opt.sink {
+		err = s.sink()
+	} else {
+		err = s.source()
+	}
+	if err != nil {
+		return err
+	}
+	return s.err
+}
+
+// ack sends an OK
+func (s *scpSession) ack() error {
+	_, err := s.out.Write([]byte{0})
+	return err
+}
+
+// warn notes err for the exit status and sends it to the client
+func (s *scpSession) warn(err error) error {
+	fs.Debugf(s.c.what, "scp: %v", err)
+	s.err = err
+	_, writeErr := fmt.Fprintf(s.out, "\x01scp: %v\n", err)
+	return writeErr
+}
+
+// response reads the acknowledgement of a message
+func (s *scpSession) response() error {
+	code, err := s.in.ReadByte()
+	if err != nil {
+		return err
+	}
+	if code == 0 {
+		return nil
+	}
+	message, err := s.in.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	message = strings.TrimSuffix(message, "\n")
+	if code == 1 {
+		fs.Debugf(s.c.what, "scp: client warning: %s", message)
+		s.err = errors.New(message)
+		return nil
+	}
+	return errors.Errorf("scp: client error: %s", message)
+}
+
+// send sends a message and reads the response, returning false if
+// the client wasn't happy with it
+func (s *scpSession) send(format string, a ...interface{}) (ok bool, err error) {
+	_, err = fmt.Fprintf(s.out, format, a...)
+	if err != nil {
+		return false, err
+	}
+	oldErr := s.err
+	s.err = nil
+	err = s.response()
+	ok = s.err == nil
+	if ok {
+		s.err = oldErr
+	}
+	return ok, err
+}
+
+// source sends the files in the paths to the client
+func (s *scpSession) source() error {
+	// wait for the client to be ready
+	err := s.response()
+	if err != nil {
+		return err
+	}
+	for _, p := range s.opt.paths {
+		remote := scpPath(p)
+		node, err := s.c.vfs.Stat(remote)
+		if err != nil {
+			err = s.warn(errors.Wrapf(err, "%s", p))
+		} else {
+			err = s.sendNode(remote, node)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendTimes sends the times of node if required
+func (s *scpSession) sendTimes(node vfs.Node) (ok bool, err error) {
+	if !s.opt.preserve {
+		return true, nil
+	}
+	modTime := node.ModTime().Unix()
+	return s.send("T%d 0 %d 0\n", modTime, modTime)
+}
+
+// sendNode sends the file or directory node at remote
+func (s *scpSession) sendNode(remote string, node vfs.Node) error {
+	if node.IsDir() {
+		return s.sendDir(remote, node)
+	}
+	return s.sendFile(remote, node)
+}
+
+// sendFile sends the file node at remote
+func (s *scpSession) sendFile(remote string, node vfs.Node) (err error) {
+	in, err := s.c.vfs.OpenFile(remote, os.O_RDONLY, 0)
+	if err != nil {
+		return s.warn(errors.Wrapf(err, "%s", remote))
+	}
+	defer fs.CheckClose(in, &err)
+	ok, err := s.sendTimes(node)
+	if err != nil || !ok {
+		return err
+	}
+	size := node.Size()
+	ok, err = s.send("C%04o %d %s\n", node.Mode().Perm(), size, path.Base(remote))
+	if err != nil || !ok {
+		return err
+	}
+	n, err := io.Copy(s.out, io.LimitReader(in, size))
+	if err == nil && n != size {
+		err = errors.Errorf("file changed size from %d to %d", size, n)
+	}
+	if err != nil {
+		// The client is expecting size bytes so we can't carry on
+		return errors.Wrapf(err, "scp: failed to send %s", remote)
+	}
+	_, err = s.send("\x00")
+	return err
+}
+
+// sendDir sends the directory node at remote and everything in it
+func (s *scpSession) sendDir(remote string, node vfs.Node) error {
+	if !s.opt.recursive {
+		return s.warn(errors.Errorf("%s: not a regular file", remote))
+	}
+	nodes, err := s.c.vfs.ReadDirAll(remote)
+	if err != nil {
+		return s.warn(errors.Wrapf(err, "%s", remote))
+	}
+	ok, err := s.sendTimes(node)
+	if err != nil || !ok {
+		return err
+	}
+	ok, err = s.send("D%04o 0 %s\n", node.Mode().Perm(), path.Base(remote))
+	if err != nil || !ok {
+		return err
+	}
+	for _, child := range nodes {
+		err = s.sendNode(path.Join(remote, child.Name()), child)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = s.send("E\n")
+	return err
+}
+
+// parseCommand parses a C or D message into its size and name
+func parseCommand(line string) (size int64, name string, err error) {
+	fields := strings.SplitN(line[1:], " ", 3)
+	if len(fields) != 3 {
+		return 0, "", errors.Errorf("bad message %q", line)
+	}
+	size, err = strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || size < 0 {
+		return 0, "", errors.Errorf("bad size in %q", line)
+	}
+	name = fields[2]
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") {
+		return 0, "", errors.Errorf("bad name %q", name)
+	}
+	return size, name, nil
+}
+
+// parseTimes parses a T message
+func parseTimes(line string) (times *[2]time.Time, err error) {
+	fields := strings.Fields(line[1:])
+	if len(fields) != 4 {
+		return nil, errors.Errorf("bad message %q", line)
+	}
+	mtime, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("bad time in %q", line)
+	}
+	atime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("bad time in %q", line)
+	}
+	return &[2]time.Time{time.Unix(atime, 0), time.Unix(mtime, 0)}, nil
+}
+
+// sink receives files from the client into the target
+func (s *scpSession) sink() error {
+	target := scpPath(s.opt.paths[0])
+	targetIsDir := false
+	if node, err := s.c.vfs.Stat(target); err == nil {
+		targetIsDir = node.IsDir()
+	}
+	if s.opt.targetDir && !targetIsDir {
+		err := errors.Errorf("%s: not a directory", s.opt.paths[0])
+		_, _ = fmt.Fprintf(s.out, "\x02scp: %v\n", err)
+		return err
+	}
+	// dirs is the stack of directories being received into
+	var dirs []string
+	// dest returns where name goes
+	dest := func(name string) string {
+		if len(dirs) > 0 {
+			return path.Join(dirs[len(dirs)-1], name)
+		}
+		if targetIsDir {
+			return path.Join(target, name)
+		}
+		return target
+	}
+	err := s.ack()
+	if err != nil {
+		return err
+	}
+	for {
+		line, err := s.in.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return s.fatal(errors.New("empty message"))
+		}
+		var (
+			size int64
+			name string
+		)
+		switch line[0] {
+		case 1, 2:
+			fs.Debugf(s.c.what, "scp: client message: %s", line[1:])
+			if line[0] == 2 {
+				return errors.Errorf("scp: client error: %s", line[1:])
+			}
+			s.err = errors.New(line[1:])
+			continue
+		case 'T':
+			s.times, err = parseTimes(line)
+			if err != nil {
+				return s.fatal(err)
+			}
+			err = s.ack()
+		case 'C':
+			size, name, err = parseCommand(line)
+			if err != nil {
+				return s.fatal(err)
+			}
+			err = s.receiveFile(dest(name), size)
+		case 'D':
+			_, name, err = parseCommand(line)
+			if err != nil {
+				return s.fatal(err)
+			}
+			if !s.opt.recursive {
+				return s.fatal(errors.New("received directory without -r"))
+			}
+			dir := dest(name)
+			err = s.c.vfs.Mkdir(dir, 0777)
+			if err != nil && !os.IsExist(err) {
+				return s.fatal(errors.Wrapf(err, "%s", dir))
+			}
+			dirs = append(dirs, dir)
+			s.setTimes(dir)
+			err = s.ack()
+		case 'E':
+			if len(dirs) == 0 {
+				return s.fatal(errors.New("unexpected end of directory"))
+			}
+			dirs = dirs[:len(dirs)-1]
+			err = s.ack()
+		default:
+			return s.fatal(errors.Errorf("bad message %q", line))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// fatal sends err to the client and returns it
+func (s *scpSession) fatal(err error) error {
+	fs.Debugf(s.c.what, "scp: %v", err)
+	_, _ = fmt.Fprintf(s.out, "\x02scp: %v\n", err)
+	return err
+}
+
+// setTimes sets the times from the last T message on remote
+func (s *scpSession) setTimes(remote string) {
+	if s.times == nil {
+		return
+	}
+	err := s.c.vfs.Chtimes(remote, s.times[0], s.times[1])
+	if err != nil {
+		fs.Debugf(s.c.what, "scp: failed to set times on %s: %v", remote, err)
+	}
+	s.times = nil
+}
+
+// receiveFile receives size bytes from the client into remote
+func (s *scpSession) receiveFile(remote string, size int64) (err error) {
+	out, openErr := s.c.vfs.OpenFile(remote, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if openErr != nil {
+		// Refuse the file - the client won't send it
+		return s.warn(errors.Wrapf(openErr, "%s", remote))
+	}
+	err = s.ack()
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	n, copyErr := io.Copy(out, io.LimitReader(s.in, size))
+	closeErr := out.Close()
+	if copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		// Make sure the rest of the file is read
+		_, err = io.CopyN(ioutil.Discard, s.in, size-n)
+		if err != nil {
+			return err
+		}
+	}
+	// The client sends its status after the data
+	err = s.response()
+	if err != nil {
+		return err
+	}
+	if copyErr != nil {
+		return s.warn(errors.Wrapf(copyErr, "%s", remote))
+	}
+	s.setTimes(remote)
+	fs.Debugf(s.c.what, "scp: received %s", remote)
+	return s.ack()
+}
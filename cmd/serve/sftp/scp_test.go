@@ -0,0 +1,136 @@
+//go:build !plan9
+// +build !plan9
+
+package sftp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScpArgs(t *testing.T) {
+	opt, err := parseScpArgs([]string{"-v", "-rp", "-t", "--", "-dir"})
+	require.NoError(t, err)
+	assert.Equal(t, scpOptions{sink: true, recursive: true, preserve: true, paths: []string{"-dir"}}, opt)
+
+	opt, err = parseScpArgs([]string{"-f", "a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, scpOptions{source: true, paths: []string{"a", "b"}}, opt)
+
+	for _, args := range [][]string{
+		{"a"},
+		{"-t", "-f", "a"},
+		{"-t"},
+		{"-t", "a", "b"},
+		{"-x", "-t", "a"},
+	} {
+		_, err = parseScpArgs(args)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestScpSink(t *testing.T) {
+	c, dir, cleanup := newTestConn(t)
+	defer cleanup()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "target"), 0700))
+
+	// What the scp client sends for "scp -rp dir host:/target"
+	input := strings.Join([]string{
+		"T1500000000 0 1500000000 0\n",
+		"D0755 0 dir\n",
+		"T1600000000 0 1600000000 0\n",
+		"C0644 5 hello.txt\n", "hello", "\x00",
+		"D0755 0 sub\n",
+		"C0644 0 empty.txt\n", "\x00",
+		"E\n",
+		"E\n",
+	}, "")
+	var out bytes.Buffer
+	err := c.scp(strings.NewReader(input), &out, []string{"-r", "-p", "-t", "/target"})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("\x00", 11), out.String())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "target", "dir", "hello.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	fi, err := os.Stat(filepath.Join(dir, "target", "dir", "hello.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1600000000, 0), fi.ModTime())
+	fi, err = os.Stat(filepath.Join(dir, "target", "dir", "sub", "empty.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), fi.Size())
+
+	// A single file to a new name
+	out.Reset()
+	err = c.scp(strings.NewReader("C0644 3 ignored.txt\nnew\x00"), &out, []string{"-t", "/target/renamed.txt"})
+	require.NoError(t, err)
+	data, err = ioutil.ReadFile(filepath.Join(dir, "target", "renamed.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	// Directories need -r
+	out.Reset()
+	err = c.scp(strings.NewReader("D0755 0 dir\nE\n"), &out, []string{"-t", "/target"})
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "\x02scp: received directory without -r")
+
+	// Bad names are refused
+	out.Reset()
+	err = c.scp(strings.NewReader("C0644 3 ../x\nnew\x00"), &out, []string{"-t", "/target"})
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "\x02scp: bad name")
+
+	// -d needs a directory
+	out.Reset()
+	err = c.scp(strings.NewReader(""), &out, []string{"-d", "-t", "/target/renamed.txt"})
+	require.Error(t, err)
+}
+
+func TestScpSource(t *testing.T) {
+	c, dir, cleanup := newTestConn(t)
+	defer cleanup()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dir", "sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "dir", "hello.txt"), []byte("hello"), 0600))
+	modTime := time.Unix(1600000000, 0)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "dir", "hello.txt"), modTime, modTime))
+
+	// The client acknowledges everything
+	acks := strings.NewReader(strings.Repeat("\x00", 100))
+	var out bytes.Buffer
+	err := c.scp(acks, &out, []string{"-f", "/dir/hello.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "C0644 5 hello.txt\nhello\x00", out.String())
+
+	out.Reset()
+	acks = strings.NewReader(strings.Repeat("\x00", 100))
+	err = c.scp(acks, &out, []string{"-r", "-p", "-f", "/dir"})
+	require.NoError(t, err)
+	got := out.String()
+	assert.True(t, strings.HasPrefix(got, "T"), got)
+	assert.Contains(t, got, "\nD0755 0 dir\n")
+	assert.Contains(t, got, "T1600000000 0 1600000000 0\nC0644 5 hello.txt\nhello\x00")
+	assert.Contains(t, got, "D0755 0 sub\n")
+	assert.True(t, strings.HasSuffix(got, "E\n"), got)
+
+	// Directories need -r and missing files are reported
+	out.Reset()
+	acks = strings.NewReader(strings.Repeat("\x00", 100))
+	err = c.scp(acks, &out, []string{"-f", "/dir", "/missing"})
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "\x01scp: /dir: not a regular file\n")
+	assert.Contains(t, out.String(), "\x01scp: /missing: ")
+
+	// The client can refuse a file
+	out.Reset()
+	err = c.scp(strings.NewReader("\x00\x01no thanks\n"), &out, []string{"-f", "/dir/hello.txt"})
+	require.Error(t, err)
+	assert.Equal(t, "C0644 5 hello.txt\n", out.String())
+}
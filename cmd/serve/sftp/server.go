@@ -23,6 +23,8 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/cmd/serve/acl/aclflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
 	"github.com/rclone/rclone/fs"
@@ -44,6 +46,7 @@ type server struct {
 	listener net.Listener
 	waitChan chan struct{} // for waiting on the listener to close
 	proxy    *proxy.Proxy
+	acl      *acl.ACL // access control list if set
 }
 
 func newServer(ctx context.Context, f fs.Fs, opt *Options) *server {
@@ -61,10 +64,10 @@ func newServer(ctx context.Context, f fs.Fs, opt *Options) *server {
 	return s
 }
 
-// getVFS gets the vfs from s or the proxy
-func (s *server) getVFS(what string, sshConn *ssh.ServerConn) (VFS *vfs.VFS) {
+// getVFS gets the vfs from s or the proxy as seen by the user
+func (s *server) getVFS(what string, sshConn *ssh.ServerConn) *acl.VFS {
 	if s.proxy == nil {
-		return s.vfs
+		return acl.NewVFS(s.vfs, s.acl, sshConn.User(), nil)
 	}
 	if sshConn.Permissions == nil && sshConn.Permissions.Extensions == nil {
 		fs.Infof(what, "SSH Permissions Extensions not found")
@@ -75,12 +78,12 @@ func (s *server) getVFS(what string, sshConn *ssh.ServerConn) (VFS *vfs.VFS) {
 		fs.Infof(what, "VFS key not found")
 		return nil
 	}
-	VFS = s.proxy.Get(key)
+	VFS := s.proxy.Get(key)
 	if VFS == nil {
 		fs.Infof(what, "failed to read VFS from cache")
 		return nil
 	}
-	return VFS
+	return acl.NewVFS(VFS, s.acl, sshConn.User(), s.proxy.Groups(key))
 }
 
 // Accept a single connection - run in a go routine as the ssh
@@ -139,6 +142,11 @@ func (s *server) serve() (err error) {
 		return errors.New("--auth-proxy and --authorized-keys cannot be used at the same time")
 	}
 
+	s.acl, err = acl.New(&aclflags.Opt)
+	if err != nil {
+		return err
+	}
+
 	// Load the authorized keys
 	if s.opt.AuthorizedKeys != "" && proxyflags.Opt.AuthProxy == "" {
 		authKeysFile := env.ShellExpand(s.opt.AuthorizedKeys)
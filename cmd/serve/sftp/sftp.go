@@ -9,6 +9,8 @@ import (
 	"context"
 
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/cmd/serve/acl/aclflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
 	"github.com/rclone/rclone/cmd/serve/proxy/proxyflags"
 	"github.com/rclone/rclone/fs"
@@ -55,6 +57,7 @@ func AddFlags(flagSet *pflag.FlagSet, Opt *Options) {
 func init() {
 	vfsflags.AddFlags(Command.Flags())
 	proxyflags.AddFlags(Command.Flags())
+	aclflags.AddFlags(Command.Flags())
 	AddFlags(Command.Flags(), &Opt)
 }
 
@@ -80,10 +83,34 @@ default is the same as ssh), an --auth-proxy, or set the --no-auth flag for no
 authentication when logging in.
 
 Note that this also implements a small number of shell commands so
-that it can provide md5sum/sha1sum/df information for the rclone sftp
+that it can provide hashes and df information for the rclone sftp
 backend.  This means that is can support SHA1SUMs, MD5SUMs and the
 about command when paired with the rclone sftp backend.
 
+The shell commands are
+
+- "md5sum", "sha1sum" and a "<hash>sum" command for each hash type
+  rclone knows, e.g. "sha256sum" or "quickxorsum", which read the hash
+  from the remote if it supports that hash type
+- "df" which reports the usage of the remote from its about command
+- "scp" so that scp clients can copy files to and from the server with
+  the original scp protocol. Recent OpenSSH scp clients use SFTP by
+  default which works too, or use "scp -O" for the scp protocol.
+
+#### rsync
+
+rsync over ssh isn't implemented yet, so "rsync --server" exec
+requests are refused with an error. rsync needs the remote end to
+speak the whole rsync protocol, with file lists, checksum negotiation
+and delta transfers, and even a subset of that is a larger piece of
+work than the shell commands above, so it will be added separately.
+Until then
+
+- use "rclone sync" or "rclone copy" with an sftp remote pointing at
+  the server, which checks sizes, modification times and hashes like
+  rsync does
+- or mount the server with sshfs and run rsync on the mount.
+
 If you don't supply a host --key then rclone will generate rsa, ecdsa
 and ed25519 variants, and cache them for later use in rclone's cache
 directory (see "rclone help flags cache-dir") in the "serve-sftp"
@@ -100,7 +127,7 @@ be used with sshd via ~/.ssh/authorized_keys, for example:
 
     restrict,command="rclone serve sftp --stdio ./photos" ssh-rsa ...
 
-` + vfs.Help + proxy.Help,
+` + vfs.Help + proxy.Help + acl.Help,
 	Run: func(command *cobra.Command, args []string) {
 		var f fs.Fs
 		if proxyflags.Opt.AuthProxy == "" {
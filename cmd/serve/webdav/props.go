@@ -0,0 +1,108 @@
+package webdav
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"golang.org/x/net/webdav"
+)
+
+// Properties which are handled specially rather than being stored
+var (
+	// Set by Windows to the modification time
+	propWin32LastModifiedTime = xml.Name{Space: "urn:schemas-microsoft-com:", Local: "Win32LastModifiedTime"}
+	// RFC 4331 quota properties
+	propQuotaAvailable = xml.Name{Space: "DAV:", Local: "quota-available-bytes"}
+	propQuotaUsed      = xml.Name{Space: "DAV:", Local: "quota-used-bytes"}
+)
+
+// check interface
+var _ webdav.DeadPropsHolder = (*Handle)(nil)
+
+// remote returns the path of the handle in the remote and the config
+// string of the remote which the properties are stored under
+func (h *Handle) remote() (fsString, remote string) {
+	return fs.ConfigString(h.VFS.Fs()), h.Node().Path()
+}
+
+// DeadProps returns a copy of the dead properties held.
+//
+// As well as those set with PROPPATCH, directories have the quota
+// properties if the remote supports About.
+func (h *Handle) DeadProps() (map[xml.Name]webdav.Property, error) {
+	props := h.w.state.getProps(h.remote())
+	if h.Node().IsDir() {
+		_, used, free := h.VFS.Statfs()
+		if free >= 0 {
+			props[propQuotaAvailable] = webdav.Property{XMLName: propQuotaAvailable, InnerXML: []byte(strconv.FormatInt(free, 10))}
+		}
+		if used >= 0 {
+			props[propQuotaUsed] = webdav.Property{XMLName: propQuotaUsed, InnerXML: []byte(strconv.FormatInt(used, 10))}
+		}
+	}
+	return props, nil
+}
+
+// Patch patches the dead properties held.
+//
+// Setting Win32LastModifiedTime sets the modification time of the file
+// rather than being stored.
+func (h *Handle) Patch(patches []webdav.Proppatch) ([]webdav.Propstat, error) {
+	// The quota properties are protected so fail everything if
+	// they are set
+	forbidden := webdav.Propstat{
+		Status:   http.StatusForbidden,
+		XMLError: `<D:cannot-modify-protected-property xmlns:D="DAV:"/>`,
+	}
+	failed := webdav.Propstat{Status: webdav.StatusFailedDependency}
+	ok := webdav.Propstat{Status: http.StatusOK}
+	var mtime time.Time
+	var store []webdav.Proppatch
+	for _, patch := range patches {
+		var keep []webdav.Property
+		for _, p := range patch.Props {
+			switch p.XMLName {
+			case propQuotaAvailable, propQuotaUsed:
+				forbidden.Props = append(forbidden.Props, p)
+				continue
+			case propWin32LastModifiedTime:
+				if !patch.Remove {
+					t, err := http.ParseTime(strings.TrimSpace(string(p.InnerXML)))
+					if err != nil {
+						fs.Debugf(h.Node().Path(), "Ignoring bad %s %q: %v", p.XMLName.Local, p.InnerXML, err)
+					} else {
+						mtime = t
+					}
+				}
+			default:
+				keep = append(keep, p)
+			}
+			failed.Props = append(failed.Props, p)
+			ok.Props = append(ok.Props, p)
+		}
+		if len(keep) > 0 {
+			store = append(store, webdav.Proppatch{Remove: patch.Remove, Props: keep})
+		}
+	}
+	if len(forbidden.Props) > 0 {
+		if len(failed.Props) == 0 {
+			return []webdav.Propstat{forbidden}, nil
+		}
+		return []webdav.Propstat{forbidden, failed}, nil
+	}
+	if !mtime.IsZero() {
+		err := h.VFS.Chtimes(h.name, mtime, mtime)
+		if err != nil {
+			fs.Errorf(h.Node().Path(), "Failed to set modification time: %v", err)
+		}
+	}
+	if len(store) > 0 {
+		fsString, remote := h.remote()
+		h.w.state.patchProps(fsString, remote, store)
+	}
+	return []webdav.Propstat{ok}, nil
+}
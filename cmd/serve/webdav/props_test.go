@@ -0,0 +1,97 @@
+package webdav
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/cmd/serve/httplib"
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropsAndMtime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-webdav-props")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+	opt := httplib.DefaultOpt
+	opt.ListenAddr = testBindAddress
+	w := newWebDAV(context.Background(), f, &opt)
+	require.NoError(t, w.serve())
+	defer func() {
+		w.Close()
+		w.Wait()
+	}()
+	url := w.Server.URL()
+
+	do := func(method, path, body string, headers ...string) (int, string) {
+		req, err := http.NewRequest(method, url+path, strings.NewReader(body))
+		require.NoError(t, err)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	// Windows setting the modification time and a custom property
+	status, body := do("PROPPATCH", "file.txt", `<?xml version="1.0" encoding="utf-8" ?>
+<D:propertyupdate xmlns:D="DAV:" xmlns:Z="urn:schemas-microsoft-com:" xmlns:X="http://example.com/">
+  <D:set><D:prop>
+    <Z:Win32LastModifiedTime>Tue, 02 Jan 2001 03:04:05 GMT</Z:Win32LastModifiedTime>
+    <X:colour>red</X:colour>
+  </D:prop></D:set>
+</D:propertyupdate>`)
+	assert.Equal(t, http.StatusMultiStatus, status)
+	assert.Contains(t, body, "200 OK")
+	fi, err := os.Stat(filepath.Join(dir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2001, 1, 2, 3, 4, 5, 0, time.UTC), fi.ModTime().UTC())
+
+	status, body = do("PROPFIND", "file.txt", `<?xml version="1.0" encoding="utf-8" ?>
+<D:propfind xmlns:D="DAV:"><D:allprop/></D:propfind>`, "Depth", "0")
+	assert.Equal(t, http.StatusMultiStatus, status)
+	assert.Contains(t, body, "red")
+
+	// The quota properties can't be set
+	status, body = do("PROPPATCH", "", `<?xml version="1.0" encoding="utf-8" ?>
+<D:propertyupdate xmlns:D="DAV:"><D:set><D:prop><D:quota-used-bytes>1</D:quota-used-bytes></D:prop></D:set></D:propertyupdate>`)
+	assert.Equal(t, http.StatusMultiStatus, status)
+	assert.Contains(t, body, "403 Forbidden")
+
+	// ownCloud clients setting the modification time on upload
+	status, _ = do("PUT", "new.txt", "potato", "X-OC-Mtime", "1000000000")
+	assert.Equal(t, http.StatusCreated, status)
+	fi, err = os.Stat(filepath.Join(dir, "new.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000000000), fi.ModTime().Unix())
+
+	// Properties follow renames
+	status, _ = do("MOVE", "file.txt", "", "Destination", url+"moved.txt")
+	assert.Equal(t, http.StatusCreated, status)
+	_, body = do("PROPFIND", "moved.txt", "", "Depth", "0")
+	assert.Contains(t, body, "red")
+
+	// Locks
+	status, body = do("LOCK", "moved.txt", `<?xml version="1.0" encoding="utf-8" ?>
+<D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope><D:locktype><D:write/></D:locktype><D:owner>me</D:owner></D:lockinfo>`, "Timeout", "Second-60")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "opaquelocktoken:")
+	status, _ = do("PUT", "moved.txt", "changed")
+	assert.Equal(t, http.StatusLocked, status)
+}
@@ -0,0 +1,453 @@
+package webdav
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/lib/atexit"
+	"golang.org/x/net/webdav"
+)
+
+// how long to wait after a change before saving the state
+const stateSaveDelay = time.Second
+
+// lockEntry is a lock taken out with LOCK
+type lockEntry struct {
+	Token   string
+	Fs      string // config string of the remote the lock is on
+	Path    string // path of Details.Root in the remote
+	Details webdav.LockDetails
+	Expiry  time.Time // zero if the lock doesn't expire
+	held    bool      // set while confirmed by a request
+}
+
+// propEntry is a dead property set with PROPPATCH
+type propEntry struct {
+	Lang string `json:",omitempty"`
+	XML  string
+}
+
+// propMap is the dead properties of one path by name
+type propMap map[string]propEntry
+
+// savedState is the format the state is saved in
+type savedState struct {
+	Locks []*lockEntry
+	Props map[string]map[string]propMap // remote, path, property name
+}
+
+// state holds the locks and dead properties of the server, saving
+// them to a file if one is set.
+//
+// Both are kept for each remote, as users of the auth proxy may have
+// different ones, by the paths in the remote.
+type state struct {
+	path      string
+	mu        sync.Mutex
+	locks     map[string]*lockEntry         // by token
+	props     map[string]map[string]propMap // remote, path, property name
+	saveTimer *time.Timer
+}
+
+// newState makes the state, reading it from path if set
+func newState(path string) (*state, error) {
+	s := &state{
+		path:  path,
+		locks: map[string]*lockEntry{},
+		props: map[string]map[string]propMap{},
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		var saved savedState
+		err = json.Unmarshal(data, &saved)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse state file %q", path)
+		}
+		for _, l := range saved.Locks {
+			s.locks[l.Token] = l
+		}
+		if saved.Props != nil {
+			s.props = saved.Props
+		}
+		fs.Infof(nil, "Read %d locks from state file %q", len(s.locks), path)
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to read state file")
+	}
+	atexit.Register(s.flush)
+	return s, nil
+}
+
+// changed schedules the state to be saved - call with the lock held
+func (s *state) changed() {
+	if s.path == "" || s.saveTimer != nil {
+		return
+	}
+	s.saveTimer = time.AfterFunc(stateSaveDelay, s.flush)
+}
+
+// flush saves the state to the file if it has changed
+func (s *state) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveTimer == nil {
+		return
+	}
+	s.saveTimer.Stop()
+	s.saveTimer = nil
+	if err := s.save(); err != nil {
+		fs.Errorf(nil, "Failed to save WebDAV state: %v", err)
+	}
+}
+
+// save writes the state to the file - call with the lock held
+func (s *state) save() error {
+	saved := savedState{Props: s.props}
+	for _, l := range s.locks {
+		saved.Locks = append(saved.Locks, l)
+	}
+	data, err := json.MarshalIndent(&saved, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// slashClean turns name into the form the locks use
+func slashClean(name string) string {
+	return path.Clean("/" + name)
+}
+
+// covers returns true if the lock l applies to p, a path in the
+// remote fsString
+func (l *lockEntry) covers(fsString, p string) bool {
+	if l.Fs != fsString {
+		return false
+	}
+	if p == l.Path {
+		return true
+	}
+	return !l.Details.ZeroDepth && (l.Path == "/" || strings.HasPrefix(p, l.Path+"/"))
+}
+
+// expire removes the expired locks - call with the lock held
+func (s *state) expire(now time.Time) {
+	for token, l := range s.locks {
+		if !l.held && !l.Expiry.IsZero() && !now.Before(l.Expiry) {
+			delete(s.locks, token)
+			s.changed()
+		}
+	}
+}
+
+// lockSystem is the view of the locks in state for users of one
+// remote whose names are relative to root in it.
+//
+// It implements webdav.LockSystem.
+type lockSystem struct {
+	s        *state
+	fsString string
+	root     string
+}
+
+// check interface
+var _ webdav.LockSystem = (*lockSystem)(nil)
+
+// lockSystem returns the locks for users of the remote fsString
+// whose names are relative to root in it
+func (s *state) lockSystem(fsString, root string) *lockSystem {
+	return &lockSystem{
+		s:        s,
+		fsString: fsString,
+		root:     slashClean(root),
+	}
+}
+
+// path returns the path in the remote of name
+func (ls *lockSystem) path(name string) string {
+	return slashClean(path.Join(ls.root, name))
+}
+
+// get returns the lock with token if it is in this remote - call with
+// the lock held
+func (ls *lockSystem) get(token string) *lockEntry {
+	l := ls.s.locks[token]
+	if l == nil || l.Fs != ls.fsString {
+		return nil
+	}
+	return l
+}
+
+// lookup returns the lock on name which matches one of conditions
+// and isn't already held, or nil - call with the lock held
+func (ls *lockSystem) lookup(name string, conditions ...webdav.Condition) *lockEntry {
+	p := ls.path(name)
+	for _, c := range conditions {
+		l := ls.get(c.Token)
+		if l == nil || l.held {
+			continue
+		}
+		if l.covers(ls.fsString, p) {
+			return l
+		}
+	}
+	return nil
+}
+
+// Confirm confirms that the caller can claim all of the locks
+// specified by the given conditions.
+func (ls *lockSystem) Confirm(now time.Time, name0, name1 string, conditions ...webdav.Condition) (release func(), err error) {
+	s := ls.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	var l0, l1 *lockEntry
+	if name0 != "" {
+		if l0 = ls.lookup(name0, conditions...); l0 == nil {
+			return nil, webdav.ErrConfirmationFailed
+		}
+	}
+	if name1 != "" {
+		if l1 = ls.lookup(name1, conditions...); l1 == nil {
+			return nil, webdav.ErrConfirmationFailed
+		}
+	}
+	if l1 == l0 {
+		l1 = nil
+	}
+	for _, l := range []*lockEntry{l0, l1} {
+		if l != nil {
+			l.held = true
+		}
+	}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, l := range []*lockEntry{l0, l1} {
+			if l != nil {
+				l.held = false
+			}
+		}
+	}, nil
+}
+
+// canCreate returns true if a lock could be made on the path p -
+// call with the lock held
+func (ls *lockSystem) canCreate(p string, zeroDepth bool) bool {
+	for _, l := range ls.s.locks {
+		if l.Fs != ls.fsString {
+			continue
+		}
+		if l.covers(ls.fsString, p) {
+			return false
+		}
+		if !zeroDepth && (p == "/" || strings.HasPrefix(l.Path, p+"/")) {
+			return false
+		}
+	}
+	return true
+}
+
+// newToken makes a unique lock token
+func newToken() (string, error) {
+	var id [16]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		return "", err
+	}
+	return "opaquelocktoken:" + hex.EncodeToString(id[:]), nil
+}
+
+// Create creates a lock with the given depth, duration, owner and
+// root (name).
+func (ls *lockSystem) Create(now time.Time, details webdav.LockDetails) (token string, err error) {
+	s := ls.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	details.Root = slashClean(details.Root)
+	p := ls.path(details.Root)
+	if !ls.canCreate(p, details.ZeroDepth) {
+		return "", webdav.ErrLocked
+	}
+	token, err = newToken()
+	if err != nil {
+		return "", err
+	}
+	l := &lockEntry{
+		Token:   token,
+		Fs:      ls.fsString,
+		Path:    p,
+		Details: details,
+	}
+	if details.Duration >= 0 {
+		l.Expiry = now.Add(details.Duration)
+	}
+	s.locks[token] = l
+	s.changed()
+	return token, nil
+}
+
+// Refresh refreshes the lock with the given token.
+func (ls *lockSystem) Refresh(now time.Time, token string, duration time.Duration) (webdav.LockDetails, error) {
+	s := ls.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	l := ls.get(token)
+	if l == nil {
+		return webdav.LockDetails{}, webdav.ErrNoSuchLock
+	}
+	if l.held {
+		return webdav.LockDetails{}, webdav.ErrLocked
+	}
+	l.Details.Duration = duration
+	l.Expiry = time.Time{}
+	if duration >= 0 {
+		l.Expiry = now.Add(duration)
+	}
+	s.changed()
+	return l.Details, nil
+}
+
+// Unlock unlocks the lock with the given token.
+func (ls *lockSystem) Unlock(now time.Time, token string) error {
+	s := ls.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	l := ls.get(token)
+	if l == nil {
+		return webdav.ErrNoSuchLock
+	}
+	if l.held {
+		return webdav.ErrLocked
+	}
+	delete(s.locks, token)
+	s.changed()
+	return nil
+}
+
+// propName turns an XML name into the key the properties use
+func propName(name xml.Name) string {
+	return "{" + name.Space + "}" + name.Local
+}
+
+// parsePropName turns a property key back into an XML name
+func parsePropName(key string) xml.Name {
+	i := strings.IndexByte(key, '}')
+	if !strings.HasPrefix(key, "{") || i < 0 {
+		return xml.Name{Local: key}
+	}
+	return xml.Name{Space: key[1:i], Local: key[i+1:]}
+}
+
+// getProps returns a copy of the dead properties of remote in the
+// remote described by fsString
+func (s *state) getProps(fsString, remote string) map[xml.Name]webdav.Property {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	props := s.props[fsString][remote]
+	out := make(map[xml.Name]webdav.Property, len(props))
+	for key, p := range props {
+		name := parsePropName(key)
+		out[name] = webdav.Property{
+			XMLName:  name,
+			Lang:     p.Lang,
+			InnerXML: []byte(p.XML),
+		}
+	}
+	return out
+}
+
+// patchProps sets and removes dead properties of remote
+func (s *state) patchProps(fsString, remote string, patches []webdav.Proppatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := s.props[fsString]
+	if paths == nil {
+		paths = map[string]propMap{}
+		s.props[fsString] = paths
+	}
+	props := paths[remote]
+	if props == nil {
+		props = propMap{}
+		paths[remote] = props
+	}
+	for _, patch := range patches {
+		for _, p := range patch.Props {
+			if patch.Remove {
+				delete(props, propName(p.XMLName))
+			} else {
+				props[propName(p.XMLName)] = propEntry{Lang: p.Lang, XML: string(p.InnerXML)}
+			}
+		}
+	}
+	if len(props) == 0 {
+		delete(paths, remote)
+	}
+	if len(paths) == 0 {
+		delete(s.props, fsString)
+	}
+	s.changed()
+}
+
+// under returns true if remote is dir or inside it
+func under(remote, dir string) bool {
+	return dir == "" || remote == dir || strings.HasPrefix(remote, dir+"/")
+}
+
+// renameProps moves the dead properties of oldRemote and anything
+// inside it to newRemote
+func (s *state) renameProps(fsString, oldRemote, newRemote string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := s.props[fsString]
+	moved := map[string]propMap{}
+	for remote, props := range paths {
+		if under(remote, oldRemote) {
+			delete(paths, remote)
+			moved[newRemote+remote[len(oldRemote):]] = props
+		}
+	}
+	for remote, props := range moved {
+		paths[remote] = props
+		s.changed()
+	}
+}
+
+// removeProps removes the dead properties of remote and anything
+// inside it
+func (s *state) removeProps(fsString, remote string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := s.props[fsString]
+	for p := range paths {
+		if under(p, remote) {
+			delete(paths, p)
+			s.changed()
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package webdav
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/webdav"
+)
+
+func TestStateLocks(t *testing.T) {
+	state, err := newState("")
+	require.NoError(t, err)
+	s := state.lockSystem("remote:", "")
+	now := time.Now()
+
+	token, err := s.Create(now, webdav.LockDetails{Root: "/dir", Duration: time.Minute})
+	require.NoError(t, err)
+	assert.Contains(t, token, "opaquelocktoken:")
+
+	// Conflicting locks
+	_, err = s.Create(now, webdav.LockDetails{Root: "/dir", Duration: -1, ZeroDepth: true})
+	assert.Equal(t, webdav.ErrLocked, err)
+	_, err = s.Create(now, webdav.LockDetails{Root: "/dir/file", Duration: -1, ZeroDepth: true})
+	assert.Equal(t, webdav.ErrLocked, err)
+	_, err = s.Create(now, webdav.LockDetails{Root: "/", Duration: -1})
+	assert.Equal(t, webdav.ErrLocked, err)
+	other, err := s.Create(now, webdav.LockDetails{Root: "/other", Duration: -1, ZeroDepth: true})
+	require.NoError(t, err)
+
+	// Confirm needs the right token and holds the lock
+	_, err = s.Confirm(now, "/dir/file", "", webdav.Condition{Token: other})
+	assert.Equal(t, webdav.ErrConfirmationFailed, err)
+	release, err := s.Confirm(now, "/dir/file", "/dir", webdav.Condition{Token: token})
+	require.NoError(t, err)
+	_, err = s.Confirm(now, "/dir/file", "", webdav.Condition{Token: token})
+	assert.Equal(t, webdav.ErrConfirmationFailed, err)
+	assert.Equal(t, webdav.ErrLocked, s.Unlock(now, token))
+	release()
+
+	// Refresh and expiry
+	details, err := s.Refresh(now, token, 2*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "/dir", details.Root)
+	_, err = s.Refresh(now.Add(3*time.Minute), token, time.Minute)
+	assert.Equal(t, webdav.ErrNoSuchLock, err)
+
+	require.NoError(t, s.Unlock(now, other))
+	assert.Equal(t, webdav.ErrNoSuchLock, s.Unlock(now, other))
+}
+
+func TestStateLocksByRemote(t *testing.T) {
+	state, err := newState("")
+	require.NoError(t, err)
+	now := time.Now()
+	alice := state.lockSystem("alice:", "")
+	bob := state.lockSystem("bob:", "")
+	token, err := alice.Create(now, webdav.LockDetails{Root: "/file", Duration: -1})
+	require.NoError(t, err)
+
+	// Users of other remotes aren't affected by the lock and can't
+	// use it
+	_, err = bob.Create(now, webdav.LockDetails{Root: "/file", Duration: -1})
+	require.NoError(t, err)
+	_, err = bob.Confirm(now, "/file", "", webdav.Condition{Token: token})
+	assert.Equal(t, webdav.ErrConfirmationFailed, err)
+	assert.Equal(t, webdav.ErrNoSuchLock, bob.Unlock(now, token))
+
+	// Users of the same remote with a root see it at their path
+	home := state.lockSystem("alice:", "/home")
+	_, err = home.Create(now, webdav.LockDetails{Root: "/file", Duration: -1})
+	require.NoError(t, err)
+	_, err = state.lockSystem("alice:", "").Create(now, webdav.LockDetails{Root: "/home/file", Duration: -1})
+	assert.Equal(t, webdav.ErrLocked, err)
+	release, err := alice.Confirm(now, "/file", "", webdav.Condition{Token: token})
+	require.NoError(t, err)
+	release()
+}
+
+func TestStateProps(t *testing.T) {
+	s, err := newState("")
+	require.NoError(t, err)
+	name := xml.Name{Space: "http://example.com/", Local: "colour"}
+	s.patchProps("remote:", "dir/file", []webdav.Proppatch{{Props: []webdav.Property{{XMLName: name, InnerXML: []byte("red")}}}})
+	props := s.getProps("remote:", "dir/file")
+	assert.Equal(t, "red", string(props[name].InnerXML))
+	assert.Empty(t, s.getProps("other:", "dir/file"))
+
+	s.renameProps("remote:", "dir", "moved")
+	assert.Empty(t, s.getProps("remote:", "dir/file"))
+	assert.Equal(t, "red", string(s.getProps("remote:", "moved/file")[name].InnerXML))
+
+	s.removeProps("remote:", "moved")
+	assert.Empty(t, s.getProps("remote:", "moved/file"))
+
+	s.patchProps("remote:", "file", []webdav.Proppatch{{Props: []webdav.Property{{XMLName: name, InnerXML: []byte("blue")}}}})
+	s.patchProps("remote:", "file", []webdav.Proppatch{{Remove: true, Props: []webdav.Property{{XMLName: name}}}})
+	assert.Empty(t, s.getProps("remote:", "file"))
+	assert.Empty(t, s.props)
+}
+
+func TestStatePersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-webdav-state")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "sub", "state.json")
+
+	s, err := newState(path)
+	require.NoError(t, err)
+	now := time.Now()
+	token, err := s.lockSystem("remote:", "").Create(now, webdav.LockDetails{Root: "/file", Duration: time.Hour, OwnerXML: "<owner/>"})
+	require.NoError(t, err)
+	name := xml.Name{Space: "DAV:", Local: "displayname2"}
+	s.patchProps("remote:", "file", []webdav.Proppatch{{Props: []webdav.Property{{XMLName: name, InnerXML: []byte("x")}}}})
+	s.flush()
+
+	s2, err := newState(path)
+	require.NoError(t, err)
+	details, err := s2.lockSystem("remote:", "").Refresh(now, token, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "<owner/>", details.OwnerXML)
+	assert.Equal(t, "x", string(s2.getProps("remote:", "file")[name].InnerXML))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = newState(path)
+	assert.Error(t, err)
+}
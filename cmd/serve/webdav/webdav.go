@@ -3,12 +3,17 @@ package webdav
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"os"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/cmd/serve/acl"
+	"github.com/rclone/rclone/cmd/serve/acl/aclflags"
 	"github.com/rclone/rclone/cmd/serve/httplib"
 	"github.com/rclone/rclone/cmd/serve/httplib/httpflags"
 	"github.com/rclone/rclone/cmd/serve/proxy"
@@ -28,6 +33,7 @@ var (
 	hashName      string
 	hashType      = hash.None
 	disableGETDir = false
+	stateFile     = ""
 )
 
 func init() {
@@ -35,8 +41,10 @@ func init() {
 	httpflags.AddFlags(flagSet)
 	vfsflags.AddFlags(flagSet)
 	proxyflags.AddFlags(flagSet)
+	aclflags.AddFlags(flagSet)
 	flags.StringVarP(flagSet, &hashName, "etag-hash", "", "", "Which hash to use for the ETag, or auto or blank for off")
 	flags.BoolVarP(flagSet, &disableGETDir, "disable-dir-list", "", false, "Disable HTML directory list on GET request for a directory")
+	flags.StringVarP(flagSet, &stateFile, "state-file", "", "", "File to keep locks and properties in - if not set they are kept in memory")
 }
 
 // Command definition for cobra
@@ -62,7 +70,21 @@ supported hash on the backend or you can use a named hash such as
 
 Use "rclone hashsum" to see the full list.
 
-` + httplib.Help + vfs.Help + proxy.Help,
+#### --state-file
+
+Clients may LOCK files and set properties on them with PROPPATCH,
+which Microsoft Office, Windows Explorer and macOS Finder all do. By
+default these are kept in memory so are lost when rclone restarts.
+Use "--state-file /path/to/file" to keep them in a file instead.
+
+Setting the "Win32LastModifiedTime" property as Windows does, or
+uploading a file with the "X-OC-Mtime" header as ownCloud and
+Nextcloud clients do, sets the modification time of the file.
+
+Directories have the "quota-available-bytes" and "quota-used-bytes"
+properties if the remote can report its usage.
+
+` + httplib.Help + vfs.Help + proxy.Help + acl.Help,
 	RunE: func(command *cobra.Command, args []string) error {
 		var f fs.Fs
 		if proxyflags.Opt.AuthProxy == "" {
@@ -112,8 +134,9 @@ type WebDAV struct {
 	*httplib.Server
 	f             fs.Fs
 	_vfs          *vfs.VFS // don't use directly, use getVFS
-	webdavhandler *webdav.Handler
 	proxy         *proxy.Proxy
+	acl           *acl.ACL        // access control list if set
+	state         *state          // locks and dead properties
 	ctx           context.Context // for global config
 }
 
@@ -135,31 +158,38 @@ func newWebDAV(ctx context.Context, f fs.Fs, opt *httplib.Options) *WebDAV {
 	} else {
 		w._vfs = vfs.New(f, &vfsflags.Opt)
 	}
-	w.Server = httplib.NewServer(http.HandlerFunc(w.handler), opt)
-	webdavHandler := &webdav.Handler{
-		Prefix:     w.Server.Opt.BaseURL,
-		FileSystem: w,
-		LockSystem: webdav.NewMemLS(),
-		Logger:     w.logRequest, // FIXME
+	var err error
+	w.acl, err = acl.New(&aclflags.Opt)
+	if err != nil {
+		log.Fatalf("Failed to load access control list: %v", err)
 	}
-	w.webdavhandler = webdavHandler
+	w.state, err = newState(stateFile)
+	if err != nil {
+		log.Fatalf("Failed to load state: %v", err)
+	}
+	w.Server = httplib.NewServer(http.HandlerFunc(w.handler), opt)
 	return w
 }
 
-// Gets the VFS in use for this request
-func (w *WebDAV) getVFS(ctx context.Context) (VFS *vfs.VFS, err error) {
+// Gets the VFS in use for this request as seen by its user
+func (w *WebDAV) getVFS(ctx context.Context) (VFS *acl.VFS, err error) {
+	user, _ := ctx.Value(httplib.ContextUserKey).(string)
 	if w._vfs != nil {
-		return w._vfs, nil
+		VFS = acl.NewVFS(w._vfs, w.acl, user, nil)
+		if root, ok := ctx.Value(httplib.ContextRootKey).(string); ok {
+			VFS = VFS.WithRoot(root)
+		}
+		return VFS, nil
 	}
 	value := ctx.Value(httplib.ContextAuthKey)
 	if value == nil {
 		return nil, errors.New("no VFS found in context")
 	}
-	VFS, ok := value.(*vfs.VFS)
+	proxyVFS, ok := value.(*vfs.VFS)
 	if !ok {
 		return nil, errors.Errorf("context value is not VFS: %#v", value)
 	}
-	return VFS, nil
+	return acl.NewVFS(proxyVFS, w.acl, user, w.proxy.Groups(user)), nil
 }
 
 // auth does proxy authorization
@@ -182,9 +212,47 @@ func (w *WebDAV) handler(rw http.ResponseWriter, r *http.Request) {
 		w.serveDir(rw, r, remote)
 		return
 	}
-	w.webdavhandler.ServeHTTP(rw, r)
+	switch r.Method {
+	case "PUT":
+		// ownCloud and Nextcloud clients send the modification time
+		if mtime := r.Header.Get("X-OC-Mtime"); mtime != "" {
+			secs, err := strconv.ParseFloat(mtime, 64)
+			if err != nil {
+				fs.Debugf(remote, "Ignoring bad X-OC-Mtime %q: %v", mtime, err)
+			} else {
+				t := time.Unix(0, int64(secs*1e9))
+				r = r.WithContext(context.WithValue(r.Context(), mtimeKey, t))
+				rw.Header().Set("X-OC-Mtime", "accepted")
+			}
+		}
+	case "PROPPATCH":
+		r = r.WithContext(context.WithValue(r.Context(), propPatchKey, true))
+	}
+	// Users with different remotes or roots each see their own locks
+	VFS, err := w.getVFS(r.Context())
+	if err != nil {
+		http.Error(rw, "Root directory not found", http.StatusNotFound)
+		fs.Errorf(nil, "Failed to serve request: %v", err)
+		return
+	}
+	webdavHandler := &webdav.Handler{
+		Prefix:     w.Server.Opt.BaseURL,
+		FileSystem: w,
+		LockSystem: w.state.lockSystem(fs.ConfigString(VFS.Fs()), VFS.Root()),
+		Logger:     w.logRequest, // FIXME
+	}
+	webdavHandler.ServeHTTP(rw, r)
 }
 
+type contextKey int
+
+// Context keys passing information about the request to the
+// FileSystem methods
+const (
+	mtimeKey     contextKey = iota // time.Time to set the modification time of an upload to
+	propPatchKey                   // set if the request is a PROPPATCH
+)
+
 // serveDir serves a directory index at dirRemote
 // This is similar to serveDir in serve http.
 func (w *WebDAV) serveDir(rw http.ResponseWriter, r *http.Request, dirRemote string) {
@@ -207,10 +275,11 @@ func (w *WebDAV) serveDir(rw http.ResponseWriter, r *http.Request, dirRemote str
 		http.Error(rw, "Not a directory", http.StatusNotFound)
 		return
 	}
-	dir := node.(*vfs.Dir)
-	dirEntries, err := dir.ReadDirAll()
-
-	if err != nil {
+	dirEntries, err := VFS.ReadDirAll(dirRemote)
+	if err == vfs.EPERM {
+		http.Error(rw, "Forbidden", http.StatusForbidden)
+		return
+	} else if err != nil {
 		serve.Error(dirRemote, rw, "Failed to list directory", err)
 		return
 	}
@@ -219,9 +288,9 @@ func (w *WebDAV) serveDir(rw http.ResponseWriter, r *http.Request, dirRemote str
 	directory := serve.NewDirectory(dirRemote, w.HTMLTemplate)
 	for _, node := range dirEntries {
 		if vfsflags.Opt.NoModTime {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), time.Time{})
+			directory.AddHTMLEntry(path.Join(dirRemote, node.Name()), node.IsDir(), node.Size(), time.Time{})
 		} else {
-			directory.AddHTMLEntry(node.Path(), node.IsDir(), node.Size(), node.ModTime().UTC())
+			directory.AddHTMLEntry(path.Join(dirRemote, node.Name()), node.IsDir(), node.Size(), node.ModTime().UTC())
 		}
 	}
 
@@ -256,12 +325,7 @@ func (w *WebDAV) Mkdir(ctx context.Context, name string, perm os.FileMode) (err
 	if err != nil {
 		return err
 	}
-	dir, leaf, err := VFS.StatParent(name)
-	if err != nil {
-		return err
-	}
-	_, err = dir.Mkdir(leaf)
-	return err
+	return VFS.Mkdir(name, perm)
 }
 
 // OpenFile opens a file or a directory
@@ -271,11 +335,23 @@ func (w *WebDAV) OpenFile(ctx context.Context, name string, flags int, perm os.F
 	if err != nil {
 		return nil, err
 	}
+	if flags == os.O_RDWR && ctx.Value(propPatchKey) != nil {
+		// PROPPATCH opens the file read/write but only changes
+		// its properties so don't open it for writing
+		if err = VFS.Check(name, acl.PermWrite); err != nil {
+			return nil, err
+		}
+		flags = os.O_RDONLY
+	}
 	f, err := VFS.OpenFile(name, flags, perm)
 	if err != nil {
 		return nil, err
 	}
-	return Handle{f}, nil
+	h := &Handle{Handle: f, w: w, VFS: VFS, name: name}
+	if flags&(os.O_WRONLY|os.O_RDWR) != 0 {
+		h.mtime, _ = ctx.Value(mtimeKey).(time.Time)
+	}
+	return h, nil
 }
 
 // RemoveAll removes a file or a directory and its contents
@@ -289,10 +365,11 @@ func (w *WebDAV) RemoveAll(ctx context.Context, name string) (err error) {
 	if err != nil {
 		return err
 	}
-	err = node.RemoveAll()
+	err = VFS.RemoveAll(name)
 	if err != nil {
 		return err
 	}
+	w.state.removeProps(fs.ConfigString(VFS.Fs()), node.Path())
 	return nil
 }
 
@@ -303,7 +380,21 @@ func (w *WebDAV) Rename(ctx context.Context, oldName, newName string) (err error
 	if err != nil {
 		return err
 	}
-	return VFS.Rename(oldName, newName)
+	node, err := VFS.Stat(oldName)
+	if err != nil {
+		return err
+	}
+	oldRemote := node.Path()
+	err = VFS.Rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+	node, err = VFS.Stat(newName)
+	if err != nil {
+		return err
+	}
+	w.state.renameProps(fs.ConfigString(VFS.Fs()), oldRemote, node.Path())
+	return nil
 }
 
 // Stat returns info about the file or directory
@@ -323,6 +414,19 @@ func (w *WebDAV) Stat(ctx context.Context, name string) (fi os.FileInfo, err err
 // Handle represents an open file
 type Handle struct {
 	vfs.Handle
+	w     *WebDAV
+	VFS   *acl.VFS
+	name  string    // name the file was opened with
+	mtime time.Time // modification time to set on Close if set
+}
+
+// Close the handle, setting the modification time if required
+func (h *Handle) Close() error {
+	err := h.Handle.Close()
+	if err != nil || h.mtime.IsZero() {
+		return err
+	}
+	return h.VFS.Chtimes(h.name, h.mtime, h.mtime)
 }
 
 // Readdir reads directory entries from the handle
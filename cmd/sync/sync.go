@@ -6,18 +6,21 @@ import (
 	"github.com/rclone/rclone/cmd"
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/operations/operationsflags"
 	"github.com/rclone/rclone/fs/sync"
 	"github.com/spf13/cobra"
 )
 
 var (
 	createEmptySrcDirs = false
+	reportOpt          = operationsflags.ReportOpt{}
 )
 
 func init() {
 	cmd.Root.AddCommand(commandDefinition)
 	cmdFlags := commandDefinition.Flags()
 	flags.BoolVarP(cmdFlags, &createEmptySrcDirs, "create-empty-src-dirs", "", createEmptySrcDirs, "Create empty source dirs on destination after sync")
+	operationsflags.AddReportFlags(cmdFlags, &reportOpt)
 }
 
 var commandDefinition = &cobra.Command{
@@ -51,13 +54,18 @@ go there.
 
 **Note**: Use the ` + "`rclone dedupe`" + ` command to deal with "Duplicate object/directory found in source/destination - ignoring" errors.
 See [this forum post](https://forum.rclone.org/t/sync-not-clearing-duplicates/14372) for more info.
-`,
+` + operationsflags.ReportHelp,
 	Run: func(command *cobra.Command, args []string) {
 		cmd.CheckArgs(2, 2, command, args)
 		fsrc, srcFileName, fdst := cmd.NewFsSrcFileDst(args)
 		cmd.Run(true, true, command, func() error {
 			if srcFileName == "" {
-				return sync.Sync(context.Background(), fdst, fsrc, createEmptySrcDirs)
+				ctx, close, err := operationsflags.ConfigureReport(context.Background(), &reportOpt)
+				if err != nil {
+					return err
+				}
+				defer close()
+				return sync.Sync(ctx, fdst, fsrc, createEmptySrcDirs)
 			}
 			return operations.CopyFile(context.Background(), fdst, fsrc, srcFileName, srcFileName)
 		})
@@ -0,0 +1,305 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/rc"
+)
+
+// ErrorMaxAPICallsReached is returned when the --max-api-calls
+// budget has been used up.
+var ErrorMaxAPICallsReached = errors.New("Max API calls reached as set by --max-api-calls")
+
+// ErrorMaxAPICallsReachedFatal is returned instead of making an API
+// call when the budget is used up and --cutoff-mode is HARD.
+var ErrorMaxAPICallsReachedFatal = fserrors.FatalError(ErrorMaxAPICallsReached)
+
+// ErrorMaxAPICallsReachedGraceful is returned from operations.Copy
+// when the budget is used up and a graceful stop is required.
+var ErrorMaxAPICallsReachedGraceful = fserrors.NoRetryError(ErrorMaxAPICallsReached)
+
+// ErrorMaxEgressReached is returned when the --max-egress budget has
+// been used up.
+var ErrorMaxEgressReached = errors.New("Max egress reached as set by --max-egress")
+
+// ErrorMaxEgressReachedFatal is returned from reading a response body
+// when the budget is used up and --cutoff-mode is HARD.
+var ErrorMaxEgressReachedFatal = fserrors.FatalError(ErrorMaxEgressReached)
+
+// ErrorMaxEgressReachedGraceful is returned from operations.Copy
+// when the budget is used up and a graceful stop is required.
+var ErrorMaxEgressReachedGraceful = fserrors.NoRetryError(ErrorMaxEgressReached)
+
+// APIClass is the class an API call is billed as by providers such
+// as S3, GCS and B2.
+type APIClass int
+
+// APIClass constants
+const (
+	APIClassA    APIClass = iota // calls which write or list
+	APIClassB                    // calls which read objects or metadata
+	APIClassFree                 // calls which are normally free, such as deletes
+	apiClasses
+)
+
+var apiClassNames = [apiClasses]string{"classA", "classB", "free"}
+
+// String turns an APIClass into a string
+func (c APIClass) String() string {
+	if c < 0 || c >= apiClasses {
+		return fmt.Sprintf("APIClass(%d)", int(c))
+	}
+	return apiClassNames[c]
+}
+
+// apiCounts are the counts of calls and egress for one remote
+type apiCounts struct {
+	calls  [apiClasses]int64
+	egress int64
+}
+
+// total returns the total number of calls
+func (a *apiCounts) total() (n int64) {
+	for _, calls := range a.calls {
+		n += calls
+	}
+	return n
+}
+
+// cost returns the price of the calls and egress
+func (a *apiCounts) cost(c *APICalls) float64 {
+	return float64(a.calls[APIClassA])*c.priceClassA/1000 +
+		float64(a.calls[APIClassB])*c.priceClassB/1000 +
+		float64(a.egress)*c.priceEgress/(1<<30)
+}
+
+// APICalls counts the API calls made to one remote and the bytes
+// downloaded from it (egress), along with what the operations
+// skipped by --dry-run would have needed.
+type APICalls struct {
+	name        string
+	mu          sync.Mutex
+	actual      apiCounts
+	estimated   apiCounts
+	priceClassA float64 // price of 1000 class A calls
+	priceClassB float64 // price of 1000 class B calls
+	priceEgress float64 // price of 1 GiB of egress
+}
+
+var (
+	apiCallsMu    sync.Mutex
+	apiCalls      = map[string]*APICalls{}
+	totalAPICalls int64 // accessed atomically
+	totalEgress   int64 // accessed atomically
+)
+
+// APICallsFor returns the counters for the remote called name,
+// making them if necessary. The prices are read from the config in
+// ctx so should be the one the remote was made with.
+func APICallsFor(ctx context.Context, name string) *APICalls {
+	ci := fs.GetConfig(ctx)
+	apiCallsMu.Lock()
+	defer apiCallsMu.Unlock()
+	c, ok := apiCalls[name]
+	if !ok {
+		c = &APICalls{name: name}
+		apiCalls[name] = c
+	}
+	c.mu.Lock()
+	c.priceClassA = ci.PriceClassA
+	c.priceClassB = ci.PriceClassB
+	c.priceEgress = ci.PriceEgress
+	c.mu.Unlock()
+	return c
+}
+
+// LookupAPICalls returns the counters for the remote called name or
+// nil if no API calls are counted for it.
+func LookupAPICalls(name string) *APICalls {
+	apiCallsMu.Lock()
+	defer apiCallsMu.Unlock()
+	return apiCalls[name]
+}
+
+// Call counts an API call of class to the remote.
+//
+// If --max-api-calls has been used up and --cutoff-mode is HARD then
+// it returns an error and the call should not be made.
+func (c *APICalls) Call(ctx context.Context, class APIClass) error {
+	ci := fs.GetConfig(ctx)
+	if ci.MaxAPICalls >= 0 && ci.CutoffMode == fs.CutoffModeHard && atomic.LoadInt64(&totalAPICalls) >= ci.MaxAPICalls {
+		return ErrorMaxAPICallsReachedFatal
+	}
+	atomic.AddInt64(&totalAPICalls, 1)
+	c.mu.Lock()
+	c.actual.calls[class]++
+	c.mu.Unlock()
+	return nil
+}
+
+// Egress counts n bytes downloaded from the remote.
+//
+// If --max-egress has been exceeded and --cutoff-mode is HARD then it
+// returns an error which should be returned from the read.
+func (c *APICalls) Egress(ctx context.Context, n int64) error {
+	total := atomic.AddInt64(&totalEgress, n)
+	c.mu.Lock()
+	c.actual.egress += n
+	c.mu.Unlock()
+	ci := fs.GetConfig(ctx)
+	if ci.MaxEgress >= 0 && ci.CutoffMode == fs.CutoffModeHard && total > int64(ci.MaxEgress) {
+		return ErrorMaxEgressReachedFatal
+	}
+	return nil
+}
+
+// Estimate records the calls of class and the egress that an
+// operation skipped by --dry-run would have needed.
+func (c *APICalls) Estimate(class APIClass, calls int64, egress int64) {
+	c.mu.Lock()
+	c.estimated.calls[class] += calls
+	c.estimated.egress += egress
+	c.mu.Unlock()
+}
+
+// CheckAPIBudget returns an error if --max-api-calls or --max-egress
+// have been used up.
+//
+// It is called before starting each transfer so that --cutoff-mode
+// SOFT and CAUTIOUS let the running transfers finish but start no
+// more.
+func CheckAPIBudget(ctx context.Context) error {
+	ci := fs.GetConfig(ctx)
+	if ci.MaxAPICalls >= 0 && atomic.LoadInt64(&totalAPICalls) >= ci.MaxAPICalls {
+		if ci.CutoffMode == fs.CutoffModeHard {
+			return ErrorMaxAPICallsReachedFatal
+		}
+		return ErrorMaxAPICallsReachedGraceful
+	}
+	if ci.MaxEgress >= 0 && atomic.LoadInt64(&totalEgress) >= int64(ci.MaxEgress) {
+		if ci.CutoffMode == fs.CutoffModeHard {
+			return ErrorMaxEgressReachedFatal
+		}
+		return ErrorMaxEgressReachedGraceful
+	}
+	return nil
+}
+
+// snapshotAPICalls returns the counters sorted by remote name with
+// the actual and estimated counts copied out under the lock.
+func snapshotAPICalls() (names []string, actual, estimated []apiCounts, costs, estimatedCosts []float64) {
+	apiCallsMu.Lock()
+	defer apiCallsMu.Unlock()
+	for name := range apiCalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c := apiCalls[name]
+		c.mu.Lock()
+		actual = append(actual, c.actual)
+		estimated = append(estimated, c.estimated)
+		costs = append(costs, c.actual.cost(c))
+		estimatedCosts = append(estimatedCosts, c.estimated.cost(c))
+		c.mu.Unlock()
+	}
+	return names, actual, estimated, costs, estimatedCosts
+}
+
+// apiCountsParams converts counts to rc.Params
+func apiCountsParams(a apiCounts, cost float64) rc.Params {
+	out := rc.Params{
+		"egress": a.egress,
+		"cost":   cost,
+	}
+	for class, calls := range a.calls {
+		out[APIClass(class).String()] = calls
+	}
+	return out
+}
+
+// apiRemoteStats returns the API calls for rc by remote name, or nil
+// if none have been counted.
+func apiRemoteStats() rc.Params {
+	names, actual, estimated, costs, estimatedCosts := snapshotAPICalls()
+	var out rc.Params
+	for i, name := range names {
+		if actual[i].total() == 0 && estimated[i].total() == 0 {
+			continue
+		}
+		if out == nil {
+			out = rc.Params{}
+		}
+		remote := apiCountsParams(actual[i], costs[i])
+		if estimated[i].total() != 0 {
+			remote["estimated"] = apiCountsParams(estimated[i], estimatedCosts[i])
+		}
+		out[name] = remote
+	}
+	return out
+}
+
+// apiSummary returns the number of calls totalled over all the
+// remotes along with a description of them by class.
+func apiSummary(estimate bool) (total int64, detail string) {
+	_, actual, estimated, costs, estimatedCosts := snapshotAPICalls()
+	counts, prices := actual, costs
+	if estimate {
+		counts, prices = estimated, estimatedCosts
+	}
+	var sum apiCounts
+	var cost float64
+	for i := range counts {
+		for class := range sum.calls {
+			sum.calls[class] += counts[i].calls[class]
+		}
+		sum.egress += counts[i].egress
+		cost += prices[i]
+	}
+	if sum.total() == 0 {
+		return 0, ""
+	}
+	parts := []string{
+		fmt.Sprintf("%d class A", sum.calls[APIClassA]),
+		fmt.Sprintf("%d class B", sum.calls[APIClassB]),
+		fmt.Sprintf("%d free", sum.calls[APIClassFree]),
+		fmt.Sprintf("%s egress", fs.SizeSuffix(sum.egress).ByteUnit()),
+	}
+	if cost != 0 {
+		parts = append(parts, fmt.Sprintf("cost %.4f", cost))
+	}
+	return sum.total(), strings.Join(parts, ", ")
+}
+
+// APIEstimate returns a description of the API calls and egress the
+// operations skipped by --dry-run would have needed, or "" if there
+// weren't any.
+func APIEstimate() string {
+	total, detail := apiSummary(true)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Estimated API calls needed: %d (%s)", total, detail)
+}
+
+// ResetAPICalls sets all the API call counters to 0
+func ResetAPICalls() {
+	apiCallsMu.Lock()
+	for _, c := range apiCalls {
+		c.mu.Lock()
+		c.actual = apiCounts{}
+		c.estimated = apiCounts{}
+		c.mu.Unlock()
+	}
+	apiCallsMu.Unlock()
+	atomic.StoreInt64(&totalAPICalls, 0)
+	atomic.StoreInt64(&totalEgress, 0)
+}
@@ -0,0 +1,94 @@
+package accounting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/rc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClassString(t *testing.T) {
+	assert.Equal(t, "classA", APIClassA.String())
+	assert.Equal(t, "classB", APIClassB.String())
+	assert.Equal(t, "free", APIClassFree.String())
+	assert.Equal(t, "APIClass(99)", APIClass(99).String())
+}
+
+func TestAPICallsBudget(t *testing.T) {
+	defer ResetAPICalls()
+	ResetAPICalls()
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.MaxAPICalls = 2
+	ci.MaxEgress = 100
+
+	c := APICallsFor(ctx, "TestAPICallsBudget")
+	assert.Equal(t, c, LookupAPICalls("TestAPICallsBudget"))
+	assert.Nil(t, LookupAPICalls("TestAPICallsBudget-not-found"))
+
+	require.NoError(t, c.Call(ctx, APIClassA))
+	require.NoError(t, CheckAPIBudget(ctx))
+	require.NoError(t, c.Call(ctx, APIClassB))
+
+	// HARD refuses more calls
+	err := c.Call(ctx, APIClassB)
+	assert.Equal(t, ErrorMaxAPICallsReachedFatal, err)
+	assert.True(t, fserrors.IsFatalError(err))
+	assert.Equal(t, ErrorMaxAPICallsReachedFatal, CheckAPIBudget(ctx))
+
+	// SOFT lets the call through but stops new transfers
+	ci.CutoffMode = fs.CutoffModeSoft
+	require.NoError(t, c.Call(ctx, APIClassB))
+	assert.Equal(t, ErrorMaxAPICallsReachedGraceful, CheckAPIBudget(ctx))
+
+	// Egress
+	ci.MaxAPICalls = -1
+	require.NoError(t, c.Egress(ctx, 60))
+	require.NoError(t, CheckAPIBudget(ctx))
+	require.NoError(t, c.Egress(ctx, 60))
+	assert.Equal(t, ErrorMaxEgressReachedGraceful, CheckAPIBudget(ctx))
+	ci.CutoffMode = fs.CutoffModeHard
+	assert.Equal(t, ErrorMaxEgressReachedFatal, c.Egress(ctx, 1))
+}
+
+func TestAPICallsStats(t *testing.T) {
+	defer ResetAPICalls()
+	ResetAPICalls()
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.PriceClassA = 5
+	ci.PriceClassB = 0.4
+	ci.PriceEgress = 0.09
+
+	total, _ := apiSummary(false)
+	assert.Equal(t, int64(0), total)
+	assert.Equal(t, "", APIEstimate())
+
+	c := APICallsFor(ctx, "TestAPICallsStats")
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.Call(ctx, APIClassA))
+		require.NoError(t, c.Call(ctx, APIClassB))
+	}
+	require.NoError(t, c.Call(ctx, APIClassFree))
+	require.NoError(t, c.Egress(ctx, 1<<30))
+
+	total, detail := apiSummary(false)
+	assert.Equal(t, int64(21), total)
+	assert.Equal(t, "10 class A, 10 class B, 1 free, 1 GiB egress, cost 0.1440", detail)
+
+	c.Estimate(APIClassA, 1000, 0)
+	c.Estimate(APIClassB, 1000, 2<<30)
+	assert.Equal(t, "Estimated API calls needed: 2000 (1000 class A, 1000 class B, 0 free, 2 GiB egress, cost 5.5800)", APIEstimate())
+
+	out := apiRemoteStats()
+	remote := out["TestAPICallsStats"].(rc.Params)
+	assert.Equal(t, int64(10), remote["classA"])
+	assert.Equal(t, int64(1), remote["free"])
+	assert.Equal(t, int64(1<<30), remote["egress"])
+	assert.Equal(t, int64(1000), remote["estimated"].(rc.Params)["classB"])
+
+	ResetAPICalls()
+	assert.Nil(t, apiRemoteStats())
+}
@@ -47,6 +47,9 @@ type StatsInfo struct {
 	renameQueueSize   int64
 	deletes           int64
 	deletedDirs       int64
+	activeTransfers   int    // transfers chosen by --adaptive-concurrency, 0 if not in use
+	activeCheckers    int    // checkers chosen by --adaptive-concurrency, 0 if not in use
+	concurrencyReason string // why the active transfers and checkers last changed
 	inProgress        *inProgress
 	startedTransfers  []*Transfer   // currently active transfers
 	oldTimeRanges     timeRanges    // a merged list of time ranges for the transfers
@@ -106,6 +109,11 @@ func (s *StatsInfo) RemoteStats() (out rc.Params, err error) {
 	out["deletedDirs"] = s.deletedDirs
 	out["renames"] = s.renames
 	out["elapsedTime"] = time.Since(s.startTime).Seconds()
+	if s.activeTransfers > 0 || s.activeCheckers > 0 {
+		out["activeTransfers"] = s.activeTransfers
+		out["activeCheckers"] = s.activeCheckers
+		out["concurrencyReason"] = s.concurrencyReason
+	}
 	eta, etaOK := eta(s.bytes, ts.totalBytes, ts.speed)
 	if etaOK {
 		out["eta"] = eta.Seconds()
@@ -123,6 +131,9 @@ func (s *StatsInfo) RemoteStats() (out rc.Params, err error) {
 	if s.errors > 0 {
 		out["lastError"] = s.lastError.Error()
 	}
+	if apiCalls := apiRemoteStats(); apiCalls != nil {
+		out["apiCalls"] = apiCalls
+	}
 
 	return out, nil
 }
@@ -437,6 +448,13 @@ func (s *StatsInfo) String() string {
 			_, _ = fmt.Fprintf(buf, "Transferred:   %10d / %d, %s\n",
 				s.transfers, ts.totalTransfers, percent(s.transfers, ts.totalTransfers))
 		}
+		if total, detail := apiSummary(false); total != 0 {
+			_, _ = fmt.Fprintf(buf, "API calls:     %10d (%s)\n", total, detail)
+		}
+		if s.activeTransfers > 0 || s.activeCheckers > 0 {
+			_, _ = fmt.Fprintf(buf, "Concurrency:   %10d transfers, %d checkers (%s)\n",
+				s.activeTransfers, s.activeCheckers, s.concurrencyReason)
+		}
 		_, _ = fmt.Fprintf(buf, "Elapsed time:  %10ss\n", strings.TrimRight(elapsedTime.Truncate(time.Minute).String(), "0s")+fmt.Sprintf("%.1f", elapsedTimeSecondsOnly.Seconds()))
 	}
 
@@ -725,6 +743,16 @@ func (s *StatsInfo) DoneTransferring(remote string, ok bool) {
 	}
 }
 
+// SetConcurrency records the number of active transfers and checkers
+// chosen by --adaptive-concurrency and the reason for the last change
+func (s *StatsInfo) SetConcurrency(transfers, checkers int, reason string) {
+	s.mu.Lock()
+	s.activeTransfers = transfers
+	s.activeCheckers = checkers
+	s.concurrencyReason = reason
+	s.mu.Unlock()
+}
+
 // SetCheckQueue sets the number of queued checks
 func (s *StatsInfo) SetCheckQueue(n int, size int64) {
 	s.mu.Lock()
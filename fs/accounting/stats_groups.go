@@ -86,8 +86,22 @@ Returns the following values:
 
 ` + "```" + `
 {
+	"activeCheckers": checkers currently allowed to run by --adaptive-concurrency (only if set),
+	"activeTransfers": transfers currently allowed to run by --adaptive-concurrency (only if set),
+	"apiCalls": API calls made by HTTP based remotes since rclone started, by remote name (only if any):
+		{
+			"remote": {
+				"classA": number of write and list calls,
+				"classB": number of read calls,
+				"free": number of delete calls,
+				"egress": bytes downloaded,
+				"cost": cost using the --price-* options,
+				"estimated": the same for the operations skipped by --dry-run
+			}
+		},
 	"bytes": total transferred bytes since the start of the group,
 	"checks": number of files checked,
+	"concurrencyReason": why --adaptive-concurrency last changed the active transfers or checkers,
 	"deletes" : number of files deleted,
 	"elapsedTime": time in floating point seconds since rclone was started,
 	"errors": number of errors,
@@ -366,6 +380,11 @@ func (sg *statsGroups) sum(ctx context.Context) *StatsInfo {
 			sum.deletes += stats.deletes
 			sum.deletedDirs += stats.deletedDirs
 			sum.renames += stats.renames
+			sum.activeTransfers += stats.activeTransfers
+			sum.activeCheckers += stats.activeCheckers
+			if sum.concurrencyReason == "" {
+				sum.concurrencyReason = stats.concurrencyReason
+			}
 			sum.checking.merge(stats.checking)
 			sum.transferring.merge(stats.transferring)
 			sum.inProgress.merge(stats.inProgress)
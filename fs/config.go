@@ -37,6 +37,19 @@ var (
 	// implementation from the fs
 	CountError = func(err error) error { return err }
 
+	// CheckProxy returns an error if the --proxy URL can't be used
+	//
+	// This is a function pointer to decouple the fshttp
+	// implementation from the fs
+	CheckProxy = func(proxy string) error { return nil }
+
+	// CheckDNSServer returns an error if the --dns-server can't be
+	// used
+	//
+	// This is a function pointer to decouple the fshttp
+	// implementation from the fs
+	CheckDNSServer = func(server string) error { return nil }
+
 	// ConfigProvider is the config key used for provider options
 	ConfigProvider = "provider"
 
@@ -59,6 +72,10 @@ type ConfigInfo struct {
 	ModifyWindow           time.Duration
 	Checkers               int
 	Transfers              int
+	AdaptiveConcurrency    bool          // Tune the active transfers and checkers from throughput
+	AdaptiveInterval       time.Duration // How often to tune the active transfers and checkers
+	MinTransfers           int           // Fewest active transfers with AdaptiveConcurrency
+	MinCheckers            int           // Fewest active checkers with AdaptiveConcurrency
 	ConnectTimeout         time.Duration // Connect timeout
 	Timeout                time.Duration // Data channel timeout
 	ExpectContinueTimeout  time.Duration
@@ -76,6 +93,7 @@ type ConfigInfo struct {
 	IgnoreChecksum         bool
 	IgnoreCaseSync         bool
 	NoTraverse             bool
+	IncrementalState       string
 	CheckFirst             bool
 	NoCheckDest            bool
 	NoUnicodeNormalization bool
@@ -84,6 +102,9 @@ type ConfigInfo struct {
 	CompareDest            []string
 	CopyDest               []string
 	BackupDir              string
+	BackupVersions         bool
+	BackupKeepDaily        int
+	BackupKeepWeekly       int
 	Suffix                 string
 	SuffixKeepExtension    bool
 	UseListR               bool
@@ -93,6 +114,9 @@ type ConfigInfo struct {
 	TPSLimit               float64
 	TPSLimitBurst          int
 	BindAddr               net.IP
+	Proxy                  string // HTTP(S) or SOCKS5 proxy URL for outgoing connections
+	NoProxy                string // Comma separated list of hosts not to use the Proxy for
+	DNSServer              string // DNS server or DNS over HTTPS URL to use instead of the system resolver
 	DisableFeatures        []string
 	UserAgent              string
 	Immutable              bool
@@ -105,6 +129,11 @@ type ConfigInfo struct {
 	MaxTransfer            SizeSuffix
 	MaxDuration            time.Duration
 	CutoffMode             CutoffMode
+	MaxAPICalls            int64      // Stop after this many API calls, -1 for no limit
+	MaxEgress              SizeSuffix // Stop after downloading this much, -1 for no limit
+	PriceClassA            float64    // Price of 1000 class A API calls
+	PriceClassB            float64    // Price of 1000 class B API calls
+	PriceEgress            float64    // Price of 1 GiB of egress
 	MaxBacklog             int
 	MaxStatsGroups         int
 	StatsOneLine           bool
@@ -131,6 +160,10 @@ type ConfigInfo struct {
 	FsCacheExpireDuration  time.Duration
 	FsCacheExpireInterval  time.Duration
 	DisableHTTP2           bool
+	MaxConnsPerHost        int           // Max connections per host, 0 for unlimited
+	HTTP2ReadIdleTimeout   time.Duration // Send an HTTP/2 ping if nothing read for this long
+	HTTP2PingTimeout       time.Duration // Close the HTTP/2 connection if no ping reply in this long
+	HTTP2StrictStreams     bool          // Respect the server's HTTP/2 max concurrent streams
 	HumanReadable          bool
 }
 
@@ -146,6 +179,9 @@ func NewConfig() *ConfigInfo {
 	c.ModifyWindow = time.Nanosecond
 	c.Checkers = 8
 	c.Transfers = 4
+	c.AdaptiveInterval = 10 * time.Second
+	c.MinTransfers = 1
+	c.MinCheckers = 1
 	c.ConnectTimeout = 60 * time.Second
 	c.Timeout = 5 * 60 * time.Second
 	c.ExpectContinueTimeout = 1 * time.Second
@@ -162,6 +198,8 @@ func NewConfig() *ConfigInfo {
 	c.AskPassword = true
 	c.TPSLimitBurst = 1
 	c.MaxTransfer = -1
+	c.MaxAPICalls = -1
+	c.MaxEgress = -1
 	c.MaxBacklog = 10000
 	// We do not want to set the default here. We use this variable being empty as part of the fall-through of options.
 	//	c.StatsOneLineDateFormat = "2006/01/02 15:04:05 - "
@@ -202,6 +240,29 @@ func (c *ConfigInfo) TimeoutOrInfinite() time.Duration {
 	return ModTimeNotSupported
 }
 
+// FixConcurrency makes MinTransfers and MinCheckers at least 1 and
+// no more than Transfers and Checkers, which must be set first. It
+// returns an error if AdaptiveConcurrency is set without a positive
+// AdaptiveInterval.
+func (c *ConfigInfo) FixConcurrency() error {
+	if c.MinTransfers < 1 {
+		c.MinTransfers = 1
+	}
+	if c.MinCheckers < 1 {
+		c.MinCheckers = 1
+	}
+	if c.MinTransfers > c.Transfers {
+		c.MinTransfers = c.Transfers
+	}
+	if c.MinCheckers > c.Checkers {
+		c.MinCheckers = c.Checkers
+	}
+	if c.AdaptiveConcurrency && c.AdaptiveInterval <= 0 {
+		return errors.New("--adaptive-interval must be greater than 0")
+	}
+	return nil
+}
+
 type configContextKeyType struct{}
 
 // Context key for config
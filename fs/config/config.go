@@ -21,6 +21,7 @@ import (
 	"github.com/rclone/rclone/fs/cache"
 	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fs/config/obscure"
+	_ "github.com/rclone/rclone/fs/config/secrets" // import the secret providers
 	"github.com/rclone/rclone/fs/fspath"
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/lib/file"
@@ -107,12 +108,37 @@ var (
 )
 
 var (
-	configPath string
-	cacheDir   string
-	data       Storage
-	dataLoaded bool
+	configPath   string
+	configScheme string
+	cacheDir     string
+	data         Storage
+	dataLoaded   bool
 )
 
+// NewStorageFn makes a Storage for the config at path
+type NewStorageFn func(path string) Storage
+
+var storages = map[string]NewStorageFn{}
+
+// RegisterStorage makes a Storage available as "--config scheme:path"
+func RegisterStorage(scheme string, newStorage NewStorageFn) {
+	storages[scheme] = newStorage
+}
+
+// NewStorage makes the Storage registered as scheme for the config at
+// path. It returns nil if scheme isn't registered.
+func NewStorage(scheme, path string) Storage {
+	newStorage := storages[scheme]
+	if newStorage == nil {
+		return nil
+	}
+	return newStorage(path)
+}
+
+// matches a storage scheme on the config path - single letters are
+// windows drive letters
+var matchConfigScheme = regexp.MustCompile(`^([a-z][a-z0-9]+):(.*)$`)
+
 func init() {
 	// Set the function pointers up in fs
 	fs.ConfigFileGet = FileGetFlag
@@ -309,11 +335,34 @@ func GetConfigPath() string {
 	return configPath
 }
 
+// GetConfigScheme returns the storage scheme given on the config
+// path, eg "json" for "json:/path/to/config", or "" if none was given
+func GetConfigScheme() string {
+	return configScheme
+}
+
 // SetConfigPath sets new config file path
 //
 // Checks for empty string, os null device, or special path, all of which indicates in-memory config.
+//
+// The path may start with the scheme of a registered Storage, eg
+// "json:/path/to/config". URLs are given the "url" scheme.
 func SetConfigPath(path string) (err error) {
 	var cfgPath string
+	scheme := ""
+	if parts := matchConfigScheme.FindStringSubmatch(path); parts != nil && storages[parts[1]] != nil {
+		scheme, path = parts[1], parts[2]
+		if path == "" {
+			return errors.Errorf("no path after %q", scheme+":")
+		}
+	}
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		scheme = "url"
+	}
+	if scheme == "url" {
+		configPath, configScheme = path, scheme
+		return nil
+	}
 	if path == "" || path == os.DevNull {
 		cfgPath = ""
 	} else if filepath.Base(path) == noConfigFile {
@@ -323,7 +372,7 @@ func SetConfigPath(path string) (err error) {
 	} else if cfgPath, err = filepath.Abs(path); err != nil {
 		return err
 	}
-	configPath = cfgPath
+	configPath, configScheme = cfgPath, scheme
 	return nil
 }
 
@@ -369,6 +418,10 @@ func LoadedData() Storage {
 // ErrorConfigFileNotFound is returned when the config file is not found
 var ErrorConfigFileNotFound = errors.New("config file not found")
 
+// ErrorConfigReadOnly is returned by Storage.Save if the config can't
+// be written
+var ErrorConfigReadOnly = errors.New("config is read only")
+
 // SaveConfig calling function which saves configuration file.
 // if SaveConfig returns error trying again after sleep.
 func SaveConfig() {
@@ -378,6 +431,9 @@ func SaveConfig() {
 	for i := 0; i < ci.LowLevelRetries+1; i++ {
 		if err = LoadedData().Save(); err == nil {
 			return
+		} else if err == ErrorConfigReadOnly {
+			fs.Logf(nil, "Not saving config changes: %v", err)
+			return
 		}
 		waitingTimeMs := mathrand.Intn(1000)
 		time.Sleep(time.Duration(waitingTimeMs) * time.Millisecond)
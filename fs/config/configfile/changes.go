@@ -0,0 +1,53 @@
+package configfile
+
+// change is a change made to the config in memory
+type change struct {
+	section string
+	key     string // "" if the section was deleted
+	value   string
+	delete  bool // set if the key was deleted
+}
+
+// changes are the changes made to the config since it was last
+// saved.
+//
+// If another process changes the config file the file is read again
+// and the changes are replayed on top of it so neither process loses
+// its changes.
+type changes []change
+
+// setValue records key being set to value in section
+func (cs *changes) setValue(section, key, value string) {
+	*cs = append(*cs, change{section: section, key: key, value: value})
+}
+
+// deleteKey records key being deleted from section
+func (cs *changes) deleteKey(section, key string) {
+	*cs = append(*cs, change{section: section, key: key, delete: true})
+}
+
+// deleteSection records section being deleted
+func (cs *changes) deleteSection(section string) {
+	*cs = append(*cs, change{section: section, delete: true})
+}
+
+// configEditor can have changes replayed onto it
+type configEditor interface {
+	setValue(section, key, value string)
+	deleteKey(section, key string) bool
+	deleteSection(section string) bool
+}
+
+// replay the changes onto e
+func (cs changes) replay(e configEditor) {
+	for _, c := range cs {
+		switch {
+		case !c.delete:
+			e.setValue(c.section, c.key, c.value)
+		case c.key == "":
+			e.deleteSection(c.section)
+		default:
+			e.deleteKey(c.section, c.key)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package configfile
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -17,23 +18,46 @@ import (
 )
 
 // Install installs the config file handler
+//
+// The storage is chosen by the scheme given on the config path, eg
+// "json:/path/to/config", or from the path itself: a directory of
+// snippets, a .json or .yaml file, an http or https URL or the normal
+// INI based file otherwise.
 func Install() {
-	config.SetData(&Storage{})
+	config.SetData(newStorage(config.GetConfigScheme(), config.GetConfigPath()))
 }
 
 // Storage implements config.Storage for saving and loading config
 // data in a simple INI based file.
 type Storage struct {
-	gc *goconfig.ConfigFile // config file loaded - thread safe
-	mu sync.Mutex           // to protect the following variables
-	fi os.FileInfo          // stat of the file when last loaded
+	gc      *goconfig.ConfigFile // config file loaded - thread safe
+	mu      sync.Mutex           // to protect the following variables
+	fi      os.FileInfo          // stat of the file when last loaded
+	changes changes              // changes since the config was saved
+}
+
+// gcEditor adapts a goconfig.ConfigFile so changes can be replayed
+// onto it
+type gcEditor struct {
+	gc *goconfig.ConfigFile
 }
 
+func (e gcEditor) setValue(section, key, value string) { e.gc.SetValue(section, key, value) }
+func (e gcEditor) deleteKey(section, key string) bool  { return e.gc.DeleteKey(section, key) }
+func (e gcEditor) deleteSection(section string) bool   { return e.gc.DeleteSection(section) }
+
 // Check to see if we need to reload the config
 func (s *Storage) check() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s._check()
+}
 
+// Check to see if we need to reload the config, replaying any
+// unsaved changes on top of the new config
+//
+// mu must be held when calling this
+func (s *Storage) _check() {
 	if configPath := config.GetConfigPath(); configPath != "" {
 		// Check to see if config file has changed since it was last loaded
 		fi, err := os.Stat(configPath)
@@ -44,6 +68,9 @@ func (s *Storage) check() {
 				err := s._load()
 				if err != nil {
 					fs.Errorf(nil, "Failed to read config file - using previous config: %v", err)
+				} else if len(s.changes) > 0 {
+					fs.Debugf(nil, "Reapplying %d unsaved config changes", len(s.changes))
+					s.changes.replay(gcEditor{s.gc})
 				}
 			}
 		}
@@ -96,6 +123,7 @@ func (s *Storage) _load() (err error) {
 func (s *Storage) Load() (err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.changes = nil
 	return s._load()
 }
 
@@ -109,8 +137,58 @@ func (s *Storage) Save() error {
 		return errors.Errorf("Failed to save config file: Path is empty")
 	}
 
-	dir, name := filepath.Split(configPath)
-	err := file.MkdirAll(dir, os.ModePerm)
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Merge in any changes made by other processes
+	s._check()
+
+	err = writeAtomic(configPath, func(out io.Writer) error {
+		var buf bytes.Buffer
+		if err := goconfig.SaveConfigData(s.gc, &buf); err != nil {
+			return errors.Errorf("Failed to save config file: %v", err)
+		}
+		return config.Encrypt(&buf, out)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update s.fi with the newly written file
+	s.fi, _ = os.Stat(configPath)
+	s.changes = nil
+
+	return nil
+}
+
+// lockConfig takes the lock for writing the config at path, returning
+// a function to release it
+func lockConfig(path string) (unlock func(), err error) {
+	dir := filepath.Dir(path)
+	err = file.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create config directory")
+	}
+	unlockFile, err := file.Lock(path + ".lock")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock config file")
+	}
+	return func() {
+		if err := unlockFile(); err != nil {
+			fs.Errorf(nil, "Failed to unlock config file: %v", err)
+		}
+	}, nil
+}
+
+// writeAtomic writes the file at path with write, replacing it only
+// once it has been written successfully. The permissions and group of
+// the old file are kept.
+func writeAtomic(path string, write func(out io.Writer) error) (err error) {
+	dir, name := filepath.Split(path)
+	err = file.MkdirAll(dir, os.ModePerm)
 	if err != nil {
 		return errors.Wrap(err, "failed to create config directory")
 	}
@@ -125,12 +203,7 @@ func (s *Storage) Save() error {
 		}
 	}()
 
-	var buf bytes.Buffer
-	if err := goconfig.SaveConfigData(s.gc, &buf); err != nil {
-		return errors.Errorf("Failed to save config file: %v", err)
-	}
-
-	if err := config.Encrypt(&buf, f); err != nil {
+	if err := write(f); err != nil {
 		return err
 	}
 
@@ -141,7 +214,7 @@ func (s *Storage) Save() error {
 	}
 
 	var fileMode os.FileMode = 0600
-	info, err := os.Stat(configPath)
+	info, err := os.Stat(path)
 	if err != nil {
 		fs.Debugf(nil, "Using default permissions for config file: %v", fileMode)
 	} else if info.Mode() != fileMode {
@@ -149,26 +222,22 @@ func (s *Storage) Save() error {
 		fileMode = info.Mode()
 	}
 
-	attemptCopyGroup(configPath, f.Name())
+	attemptCopyGroup(path, f.Name())
 
 	err = os.Chmod(f.Name(), fileMode)
 	if err != nil {
 		fs.Errorf(nil, "Failed to set permissions on config file: %v", err)
 	}
 
-	if err = os.Rename(configPath, configPath+".old"); err != nil && !os.IsNotExist(err) {
+	if err = os.Rename(path, path+".old"); err != nil && !os.IsNotExist(err) {
 		return errors.Errorf("Failed to move previous config to backup location: %v", err)
 	}
-	if err = os.Rename(f.Name(), configPath); err != nil {
+	if err = os.Rename(f.Name(), path); err != nil {
 		return errors.Errorf("Failed to move newly written config from %s to final location: %v", f.Name(), err)
 	}
-	if err := os.Remove(configPath + ".old"); err != nil && !os.IsNotExist(err) {
+	if err := os.Remove(path + ".old"); err != nil && !os.IsNotExist(err) {
 		fs.Errorf(nil, "Failed to remove backup config file: %v", err)
 	}
-
-	// Update s.fi with the newly written file
-	s.fi, _ = os.Stat(configPath)
-
 	return nil
 }
 
@@ -196,7 +265,10 @@ func (s *Storage) HasSection(section string) bool {
 // DeleteSection removes the named section and all config from the
 // config file
 func (s *Storage) DeleteSection(section string) {
-	s.check()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s._check()
+	s.changes.deleteSection(section)
 	s.gc.DeleteSection(section)
 }
 
@@ -225,17 +297,23 @@ func (s *Storage) GetValue(section string, key string) (value string, found bool
 
 // SetValue sets the value under key in section
 func (s *Storage) SetValue(section string, key string, value string) {
-	s.check()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s._check()
 	if strings.HasPrefix(section, ":") {
 		fs.Logf(nil, "Can't save config %q for on the fly backend %q", key, section)
 		return
 	}
+	s.changes.setValue(section, key, value)
 	s.gc.SetValue(section, key, value)
 }
 
 // DeleteKey removes the key under section
 func (s *Storage) DeleteKey(section string, key string) bool {
-	s.check()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s._check()
+	s.changes.deleteKey(section, key)
 	return s.gc.DeleteKey(section, key)
 }
 
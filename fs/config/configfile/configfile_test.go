@@ -44,6 +44,7 @@ func setConfigFile(t *testing.T, data string) func() {
 	return func() {
 		assert.NoError(t, config.SetConfigPath(old))
 		_ = os.Remove(filePath)
+		_ = os.Remove(filePath + ".lock")
 	}
 }
 
@@ -0,0 +1,335 @@
+package configfile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+)
+
+// dirFile is one of the files in a config directory
+type dirFile struct {
+	name   string // leaf name of the file
+	format format
+	doc    *document
+	dirty  bool // set if doc needs saving
+}
+
+// dirStorage implements config.Storage for a directory of config
+// snippets, eg conf.d, read in name order.
+//
+// Files ending .conf or .ini are in the normal config format, and
+// files ending .json, .yaml or .yml are JSON or YAML. Other files and
+// hidden files are ignored.
+//
+// If a section appears in more than one file the keys are merged with
+// the keys in later files taking priority. Changes are written to the
+// file the key came from and new remotes are written to a new file
+// named after the remote.
+type dirStorage struct {
+	dir       string
+	mu        sync.Mutex // to protect the following variables
+	files     []*dirFile
+	signature string  // of the files when last loaded
+	loaded    bool    // set once Load has been called
+	changes   changes // changes since the config was saved
+}
+
+// newDirStorage makes a dirStorage for dir
+func newDirStorage(dir string) *dirStorage {
+	return &dirStorage{dir: dir}
+}
+
+// list the config files in the directory in order
+func (s *dirStorage) list() (entries []os.FileInfo, signature string, err error) {
+	all, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, "", err
+	}
+	var sig strings.Builder
+	for _, fi := range all {
+		name := fi.Name()
+		if strings.HasPrefix(name, ".") || !fi.Mode().IsRegular() || formatFromName(name) == "" {
+			continue
+		}
+		entries = append(entries, fi)
+		fmt.Fprintf(&sig, "%s:%d:%d\n", name, fi.Size(), fi.ModTime().UnixNano())
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, sig.String(), nil
+}
+
+// _load the config - call with mu held
+func (s *dirStorage) _load() error {
+	entries, signature, err := s.list()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config.ErrorConfigFileNotFound
+		}
+		return err
+	}
+	files := make([]*dirFile, 0, len(entries))
+	for _, fi := range entries {
+		f := &dirFile{
+			name:   fi.Name(),
+			format: formats[formatFromName(fi.Name())],
+		}
+		f.doc, err = s.read(f)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", f.name)
+		}
+		files = append(files, f)
+	}
+	s.files, s.signature = files, signature
+	return nil
+}
+
+// read the document in f
+func (s *dirStorage) read(f *dirFile) (doc *document, err error) {
+	in, err := os.Open(filepath.Join(s.dir, f.name))
+	if err != nil {
+		return nil, err
+	}
+	defer fs.CheckClose(in, &err)
+	return decode(in, f.format)
+}
+
+// lock the storage, reloading it if the files have changed
+func (s *dirStorage) lock() {
+	s.mu.Lock()
+	s._check()
+}
+
+// _check reloads the config if the files have changed, replaying any
+// unsaved changes on top - call with mu held
+func (s *dirStorage) _check() {
+	if !s.loaded {
+		return
+	}
+	_, signature, err := s.list()
+	if err != nil || signature == s.signature {
+		return
+	}
+	fs.Debugf(nil, "Config directory has changed externaly - reloading")
+	err = s._load()
+	if err != nil {
+		fs.Errorf(nil, "Failed to read config directory - using previous config: %v", err)
+	} else if len(s.changes) > 0 {
+		fs.Debugf(nil, "Reapplying %d unsaved config changes", len(s.changes))
+		s.changes.replay(s)
+	}
+}
+
+// Load the config from permanent storage, decrypting if necessary
+func (s *dirStorage) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changes = nil
+	err := s._load()
+	s.loaded = true
+	return err
+}
+
+// Save the changed files, encrypting if necessary
+func (s *dirStorage) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := lockConfig(filepath.Join(s.dir, ".rclone"))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// Merge in any changes made by other processes
+	s._check()
+	files := s.files[:0]
+	for _, f := range s.files {
+		path := filepath.Join(s.dir, f.name)
+		if f.dirty && len(f.doc.sections) == 0 {
+			fs.Debugf(nil, "Removing empty config file %q", path)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if f.dirty {
+			data, err := f.format.encode(f.doc)
+			if err != nil {
+				return errors.Wrapf(err, "failed to save %q", path)
+			}
+			err = writeAtomic(path, func(out io.Writer) error {
+				return config.Encrypt(bytes.NewReader(data), out)
+			})
+			if err != nil {
+				return err
+			}
+			f.dirty = false
+		}
+		files = append(files, f)
+	}
+	s.files = files
+	_, s.signature, _ = s.list()
+	s.changes = nil
+	return nil
+}
+
+// merged returns the config from all the files merged
+func (s *dirStorage) merged() *document {
+	doc := newDocument()
+	for _, f := range s.files {
+		for _, sec := range f.doc.sections {
+			if doc.find(sec.name) == nil {
+				doc.sections = append(doc.sections, &section{name: sec.name, values: map[string]string{}})
+			}
+			for _, key := range sec.keys {
+				doc.setValue(sec.name, key, sec.values[key])
+			}
+		}
+	}
+	return doc
+}
+
+// Serialize the merged config into a string
+func (s *dirStorage) Serialize() (string, error) {
+	s.lock()
+	defer s.mu.Unlock()
+	data, err := iniFormat{}.encode(s.merged())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetSectionList returns a slice of strings with names for all the
+// sections
+func (s *dirStorage) GetSectionList() []string {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.merged().sectionList()
+}
+
+// HasSection returns true if section exists in the config file
+func (s *dirStorage) HasSection(section string) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	for _, f := range s.files {
+		if f.doc.find(section) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteSection removes the named section from all the files
+func (s *dirStorage) DeleteSection(section string) {
+	s.lock()
+	defer s.mu.Unlock()
+	s.changes.deleteSection(section)
+	s.deleteSection(section)
+}
+
+// deleteSection removes the named section from all the files - call
+// with mu held
+func (s *dirStorage) deleteSection(section string) (deleted bool) {
+	for _, f := range s.files {
+		if f.doc.deleteSection(section) {
+			f.dirty = true
+			deleted = true
+		}
+	}
+	return deleted
+}
+
+// GetKeyList returns the keys in this section
+func (s *dirStorage) GetKeyList(section string) []string {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.merged().keyList(section)
+}
+
+// GetValue returns the key in section with a found flag
+func (s *dirStorage) GetValue(section string, key string) (value string, found bool) {
+	s.lock()
+	defer s.mu.Unlock()
+	for i := len(s.files) - 1; i >= 0; i-- {
+		value, found = s.files[i].doc.getValue(section, key)
+		if found {
+			return value, found
+		}
+	}
+	return "", false
+}
+
+// SetValue sets the value under key in section, in the file the key
+// or section came from, or a new file named after the section
+func (s *dirStorage) SetValue(section string, key string, value string) {
+	s.lock()
+	defer s.mu.Unlock()
+	if strings.HasPrefix(section, ":") {
+		fs.Logf(nil, "Can't save config %q for on the fly backend %q", key, section)
+		return
+	}
+	s.changes.setValue(section, key, value)
+	s.setValue(section, key, value)
+}
+
+// setValue sets the value under key in section - call with mu held
+func (s *dirStorage) setValue(section string, key string, value string) {
+	var target *dirFile
+	for i := len(s.files) - 1; i >= 0 && target == nil; i-- {
+		if _, found := s.files[i].doc.getValue(section, key); found {
+			target = s.files[i]
+		}
+	}
+	for i := len(s.files) - 1; i >= 0 && target == nil; i-- {
+		if s.files[i].doc.find(section) != nil {
+			target = s.files[i]
+		}
+	}
+	if target == nil {
+		name := section + ".conf"
+		for _, f := range s.files {
+			if f.name == name {
+				target = f
+			}
+		}
+		if target == nil {
+			target = &dirFile{name: name, format: iniFormat{}, doc: newDocument()}
+			s.files = append(s.files, target)
+			sort.SliceStable(s.files, func(i, j int) bool { return s.files[i].name < s.files[j].name })
+		}
+	}
+	target.doc.setValue(section, key, value)
+	target.dirty = true
+}
+
+// DeleteKey removes the key under section from all the files
+func (s *dirStorage) DeleteKey(section string, key string) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	s.changes.deleteKey(section, key)
+	return s.deleteKey(section, key)
+}
+
+// deleteKey removes the key under section from all the files - call
+// with mu held
+func (s *dirStorage) deleteKey(section string, key string) (deleted bool) {
+	for _, f := range s.files {
+		if f.doc.deleteKey(section, key) {
+			f.dirty = true
+			deleted = true
+		}
+	}
+	return deleted
+}
+
+// Check the interface is satisfied
+var _ config.Storage = (*dirStorage)(nil)
@@ -0,0 +1,337 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/Unknwon/goconfig"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// section is a named section of the config with its keys in order
+type section struct {
+	name   string
+	keys   []string
+	values map[string]string
+}
+
+// document is the config held in memory by the storages which don't
+// use goconfig directly. The order of sections and keys is kept.
+type document struct {
+	sections []*section
+}
+
+// newDocument makes an empty document
+func newDocument() *document {
+	return &document{}
+}
+
+// find returns the section called name or nil
+func (d *document) find(name string) *section {
+	for _, s := range d.sections {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// sectionList returns the names of the sections in order
+func (d *document) sectionList() []string {
+	names := make([]string, 0, len(d.sections))
+	for _, s := range d.sections {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// keyList returns the keys in section name in order
+func (d *document) keyList(name string) []string {
+	s := d.find(name)
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s.keys...)
+}
+
+// getValue returns the value of key in section name
+func (d *document) getValue(name, key string) (value string, found bool) {
+	s := d.find(name)
+	if s == nil {
+		return "", false
+	}
+	value, found = s.values[key]
+	return value, found
+}
+
+// setValue sets key in section name, making the section if needed
+func (d *document) setValue(name, key, value string) {
+	s := d.find(name)
+	if s == nil {
+		s = &section{name: name, values: map[string]string{}}
+		d.sections = append(d.sections, s)
+	}
+	if _, found := s.values[key]; !found {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+}
+
+// deleteKey removes key from section name returning true if it existed
+func (d *document) deleteKey(name, key string) bool {
+	s := d.find(name)
+	if s == nil {
+		return false
+	}
+	if _, found := s.values[key]; !found {
+		return false
+	}
+	delete(s.values, key)
+	for i, k := range s.keys {
+		if k == key {
+			s.keys = append(s.keys[:i], s.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// deleteSection removes section name returning true if it existed
+func (d *document) deleteSection(name string) bool {
+	for i, s := range d.sections {
+		if s.name == name {
+			d.sections = append(d.sections[:i], d.sections[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// format reads and writes a document in a file format
+type format interface {
+	decode(in io.Reader) (*document, error)
+	encode(d *document) ([]byte, error)
+}
+
+// formats by scheme
+var formats = map[string]format{
+	"ini":  iniFormat{},
+	"json": jsonFormat{},
+	"yaml": yamlFormat{},
+}
+
+// formatFromName returns the scheme of the format the file name is
+// in from its extension, or "" if it isn't known
+func formatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".conf", ".ini":
+		return "ini"
+	}
+	return ""
+}
+
+// iniFormat is the normal rclone config file format
+type iniFormat struct{}
+
+func (iniFormat) decode(in io.Reader) (*document, error) {
+	gc, err := goconfig.LoadFromReader(in)
+	if err != nil {
+		return nil, err
+	}
+	d := newDocument()
+	for _, name := range gc.GetSectionList() {
+		values, err := gc.GetSection(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range gc.GetKeyList(name) {
+			d.setValue(name, key, values[key])
+		}
+		if d.find(name) == nil {
+			d.sections = append(d.sections, &section{name: name, values: map[string]string{}})
+		}
+	}
+	return d, nil
+}
+
+func (iniFormat) encode(d *document) ([]byte, error) {
+	gc, err := goconfig.LoadFromReader(bytes.NewReader(nil))
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range d.sections {
+		if len(s.keys) == 0 {
+			// goconfig can't make an empty section so make it
+			// with a key then remove the key
+			gc.SetValue(s.name, "_", "")
+			gc.DeleteKey(s.name, "_")
+		}
+		for _, key := range s.keys {
+			gc.SetValue(s.name, key, s.values[key])
+		}
+	}
+	var buf bytes.Buffer
+	err = goconfig.SaveConfigData(gc, &buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonFormat is a JSON object of remotes each of which is an object
+// of string values, eg {"remote":{"type":"local"}}
+type jsonFormat struct{}
+
+// readJSONObject reads the keys and values of a JSON object in order
+// calling fn for each one
+func readJSONObject(dec *json.Decoder, fn func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return errors.Errorf("expecting JSON object but got %v", tok)
+	}
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		err = fn(tok.(string))
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func (jsonFormat) decode(in io.Reader) (*document, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	d := newDocument()
+	if len(bytes.TrimSpace(data)) == 0 {
+		return d, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = readJSONObject(dec, func(name string) error {
+		d.deleteSection(name)
+		d.sections = append(d.sections, &section{name: name, values: map[string]string{}})
+		return readJSONObject(dec, func(key string) error {
+			var value interface{}
+			err := dec.Decode(&value)
+			if err != nil {
+				return err
+			}
+			switch value.(type) {
+			case string, json.Number, bool:
+				d.setValue(name, key, fmt.Sprint(value))
+			default:
+				return errors.Errorf("value of %q in %q must be a string, number or boolean", key, name)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse JSON config")
+	}
+	return d, nil
+}
+
+func (jsonFormat) encode(d *document) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, s := range d.sections {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, _ := json.Marshal(s.name)
+		fmt.Fprintf(&buf, "\n  %s: {", name)
+		for j, key := range s.keys {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+			k, _ := json.Marshal(key)
+			v, _ := json.Marshal(s.values[key])
+			fmt.Fprintf(&buf, "\n    %s: %s", k, v)
+		}
+		if len(s.keys) > 0 {
+			buf.WriteString("\n  ")
+		}
+		buf.WriteString("}")
+	}
+	if len(d.sections) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// yamlFormat is a YAML map of remotes each of which is a map of
+// values, eg
+//
+//	remote:
+//	  type: local
+type yamlFormat struct{}
+
+func (yamlFormat) decode(in io.Reader) (*document, error) {
+	var remotes yaml.MapSlice
+	err := yaml.NewDecoder(in).Decode(&remotes)
+	if err == io.EOF {
+		return newDocument(), nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to parse YAML config")
+	}
+	d := newDocument()
+	for _, remote := range remotes {
+		name := fmt.Sprint(remote.Key)
+		d.deleteSection(name)
+		d.sections = append(d.sections, &section{name: name, values: map[string]string{}})
+		if remote.Value == nil {
+			continue
+		}
+		values, ok := remote.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, errors.Errorf("failed to parse YAML config: %q must be a map", name)
+		}
+		for _, item := range values {
+			switch item.Value.(type) {
+			case yaml.MapSlice, []interface{}:
+				return nil, errors.Errorf("failed to parse YAML config: value of %q in %q must be a string, number or boolean", item.Key, name)
+			case nil:
+				d.setValue(name, fmt.Sprint(item.Key), "")
+			default:
+				d.setValue(name, fmt.Sprint(item.Key), fmt.Sprint(item.Value))
+			}
+		}
+	}
+	return d, nil
+}
+
+func (yamlFormat) encode(d *document) ([]byte, error) {
+	remotes := make(yaml.MapSlice, 0, len(d.sections))
+	for _, s := range d.sections {
+		values := make(yaml.MapSlice, 0, len(s.keys))
+		for _, key := range s.keys {
+			values = append(values, yaml.MapItem{Key: key, Value: s.values[key]})
+		}
+		remotes = append(remotes, yaml.MapItem{Key: s.name, Value: values})
+	}
+	if len(remotes) == 0 {
+		return []byte{}, nil
+	}
+	return yaml.Marshal(remotes)
+}
@@ -0,0 +1,246 @@
+package configfile
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+)
+
+func init() {
+	config.RegisterStorage("ini", func(path string) config.Storage { return &Storage{} })
+	config.RegisterStorage("json", func(path string) config.Storage { return newFileStorage(path, "json") })
+	config.RegisterStorage("yaml", func(path string) config.Storage { return newFileStorage(path, "yaml") })
+	config.RegisterStorage("dir", func(path string) config.Storage { return newDirStorage(path) })
+	config.RegisterStorage("url", func(path string) config.Storage { return newURLStorage(path) })
+}
+
+// newStorage makes the storage for the config at path. If scheme
+// isn't set it is worked out from the path.
+func newStorage(scheme, path string) config.Storage {
+	if scheme == "" {
+		scheme = formatFromName(path)
+		if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+			scheme = "url"
+		} else if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			scheme = "dir"
+		}
+	}
+	if scheme == "" {
+		scheme = "ini"
+	}
+	storage := config.NewStorage(scheme, path)
+	if storage == nil {
+		fs.Errorf(nil, "Unknown config storage %q - using ini", scheme)
+		storage = &Storage{}
+	}
+	return storage
+}
+
+// docStorage implements the parts of config.Storage which read and
+// change the document in memory
+type docStorage struct {
+	mu      sync.Mutex // to protect the following variables
+	doc     *document  // the config
+	changes changes    // changes since the config was saved
+	check   func()     // if set, called with mu held to reload doc if changed
+}
+
+// lock the storage making sure the document is up to date
+func (s *docStorage) lock() {
+	s.mu.Lock()
+	if s.check != nil {
+		s.check()
+	}
+	if s.doc == nil {
+		s.doc = newDocument()
+	}
+}
+
+// GetSectionList returns a slice of strings with names for all the
+// sections
+func (s *docStorage) GetSectionList() []string {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.doc.sectionList()
+}
+
+// HasSection returns true if section exists in the config file
+func (s *docStorage) HasSection(section string) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.doc.find(section) != nil
+}
+
+// DeleteSection removes the named section and all config from the
+// config file
+func (s *docStorage) DeleteSection(section string) {
+	s.lock()
+	defer s.mu.Unlock()
+	s.changes.deleteSection(section)
+	s.doc.deleteSection(section)
+}
+
+// GetKeyList returns the keys in this section
+func (s *docStorage) GetKeyList(section string) []string {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.doc.keyList(section)
+}
+
+// GetValue returns the key in section with a found flag
+func (s *docStorage) GetValue(section string, key string) (value string, found bool) {
+	s.lock()
+	defer s.mu.Unlock()
+	return s.doc.getValue(section, key)
+}
+
+// SetValue sets the value under key in section
+func (s *docStorage) SetValue(section string, key string, value string) {
+	s.lock()
+	defer s.mu.Unlock()
+	if strings.HasPrefix(section, ":") {
+		fs.Logf(nil, "Can't save config %q for on the fly backend %q", key, section)
+		return
+	}
+	s.changes.setValue(section, key, value)
+	s.doc.setValue(section, key, value)
+}
+
+// DeleteKey removes the key under section
+func (s *docStorage) DeleteKey(section string, key string) bool {
+	s.lock()
+	defer s.mu.Unlock()
+	s.changes.deleteKey(section, key)
+	return s.doc.deleteKey(section, key)
+}
+
+// decode reads a document in format from in, decrypting it if necessary
+func decode(in io.Reader, f format) (*document, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := config.Decrypt(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return f.decode(decrypted)
+}
+
+// fileStorage implements config.Storage for config in a single JSON
+// or YAML file
+type fileStorage struct {
+	docStorage
+	path   string
+	format format
+	fi     os.FileInfo // stat of the file when last loaded
+}
+
+// newFileStorage makes a fileStorage for path in the format scheme
+func newFileStorage(path, scheme string) *fileStorage {
+	s := &fileStorage{
+		path:   path,
+		format: formats[scheme],
+	}
+	s.check = s._check
+	return s
+}
+
+// _check reloads the file if it has changed - call with mu held
+func (s *fileStorage) _check() {
+	fi, err := os.Stat(s.path)
+	if err != nil || s.doc == nil {
+		return
+	}
+	if s.fi == nil || !fi.ModTime().Equal(s.fi.ModTime()) || fi.Size() != s.fi.Size() {
+		fs.Debugf(nil, "Config file has changed externaly - reloading")
+		err := s._load()
+		if err != nil {
+			fs.Errorf(nil, "Failed to read config file - using previous config: %v", err)
+		} else if len(s.changes) > 0 {
+			fs.Debugf(nil, "Reapplying %d unsaved config changes", len(s.changes))
+			s.changes.replay(s.doc)
+		}
+	}
+}
+
+// _load the config - call with mu held
+func (s *fileStorage) _load() (err error) {
+	in, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config.ErrorConfigFileNotFound
+		}
+		return err
+	}
+	defer fs.CheckClose(in, &err)
+	fi, _ := in.Stat()
+	doc, err := decode(in, s.format)
+	if err != nil {
+		return err
+	}
+	s.doc, s.fi = doc, fi
+	return nil
+}
+
+// Load the config from permanent storage, decrypting if necessary
+func (s *fileStorage) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changes = nil
+	err := s._load()
+	if s.doc == nil {
+		s.doc = newDocument()
+	}
+	return err
+}
+
+// Save the config to permanent storage, encrypting if necessary
+func (s *fileStorage) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := lockConfig(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// Merge in any changes made by other processes
+	s._check()
+	if s.doc == nil {
+		s.doc = newDocument()
+	}
+	data, err := s.format.encode(s.doc)
+	if err != nil {
+		return errors.Wrap(err, "failed to save config file")
+	}
+	err = writeAtomic(s.path, func(out io.Writer) error {
+		return config.Encrypt(bytes.NewReader(data), out)
+	})
+	if err != nil {
+		return err
+	}
+	s.fi, _ = os.Stat(s.path)
+	s.changes = nil
+	return nil
+}
+
+// Serialize the config into a string
+func (s *fileStorage) Serialize() (string, error) {
+	s.lock()
+	defer s.mu.Unlock()
+	data, err := s.format.encode(s.doc)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Check the interface is satisfied
+var _ config.Storage = (*fileStorage)(nil)
@@ -0,0 +1,294 @@
+package configfile
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rclone/rclone/fs/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDocument is the document in configData
+func testDocument(t *testing.T) *document {
+	doc, err := iniFormat{}.decode(strings.NewReader(configData))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestFormats(t *testing.T) {
+	doc := testDocument(t)
+	assert.Equal(t, []string{"one", "two", "three"}, doc.sectionList())
+	assert.Equal(t, []string{"type", "fruit", "topping"}, doc.keyList("two"))
+
+	for _, scheme := range []string{"ini", "json", "yaml"} {
+		t.Run(scheme, func(t *testing.T) {
+			f := formats[scheme]
+			data, err := f.encode(doc)
+			require.NoError(t, err)
+			got, err := f.decode(strings.NewReader(string(data)))
+			require.NoError(t, err)
+			assert.Equal(t, doc, got)
+
+			empty, err := f.decode(strings.NewReader(""))
+			require.NoError(t, err)
+			assert.Equal(t, 0, len(empty.sections))
+		})
+	}
+
+	doc, err := jsonFormat{}.decode(strings.NewReader(`{"b":{"port":22,"tls":true,"user":"me"},"a":{}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, doc.sectionList())
+	value, _ := doc.getValue("b", "port")
+	assert.Equal(t, "22", value)
+	value, _ = doc.getValue("b", "tls")
+	assert.Equal(t, "true", value)
+	_, err = jsonFormat{}.decode(strings.NewReader(`{"b":{"list":[1,2]}}`))
+	assert.Error(t, err)
+	_, err = jsonFormat{}.decode(strings.NewReader(`["b"]`))
+	assert.Error(t, err)
+
+	doc, err = yamlFormat{}.decode(strings.NewReader("b:\n  port: 22\n  empty:\na:\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, doc.sectionList())
+	assert.Equal(t, []string{"port", "empty"}, doc.keyList("b"))
+	_, err = yamlFormat{}.decode(strings.NewReader("b:\n  nested:\n    x: 1\n"))
+	assert.Error(t, err)
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-configfile-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, scheme := range []string{"json", "yaml"} {
+		t.Run(scheme, func(t *testing.T) {
+			path := filepath.Join(dir, "rclone."+scheme)
+			s := newFileStorage(path, scheme)
+			assert.Equal(t, config.ErrorConfigFileNotFound, s.Load())
+			assert.Equal(t, []string{}, s.GetSectionList())
+
+			s.SetValue("one", "type", "local")
+			s.SetValue("one", "token", "abc")
+			s.SetValue(":local", "token", "abc")
+			require.NoError(t, s.Save())
+
+			s2 := newFileStorage(path, scheme)
+			require.NoError(t, s2.Load())
+			assert.Equal(t, []string{"one"}, s2.GetSectionList())
+			value, found := s2.GetValue("one", "token")
+			assert.True(t, found)
+			assert.Equal(t, "abc", value)
+
+			// Changes by another process are read
+			s2.SetValue("one", "token", "def")
+			assert.True(t, s2.DeleteKey("one", "type"))
+			require.NoError(t, s2.Save())
+			value, _ = s.GetValue("one", "token")
+			assert.Equal(t, "def", value)
+			assert.Equal(t, []string{"token"}, s.GetKeyList("one"))
+
+			s.DeleteSection("one")
+			assert.False(t, s.HasSection("one"))
+		})
+	}
+}
+
+func TestDirStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-configfile-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	write := func(name, data string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600))
+	}
+	read := func(name string) string {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return toUnix(string(data))
+	}
+	write("10-base.conf", configData)
+	write("20-override.json", `{"two":{"fruit":"pear"},"four":{"type":"number4"}}`)
+	write("ignored.txt", "[ignored]\ntype = x\n")
+	write(".hidden.conf", "[hidden]\ntype = x\n")
+
+	s := newStorage("", dir)
+	require.IsType(t, &dirStorage{}, s)
+	require.NoError(t, s.Load())
+
+	assert.Equal(t, []string{"one", "two", "three", "four"}, s.GetSectionList())
+	value, _ := s.GetValue("two", "fruit")
+	assert.Equal(t, "pear", value)
+	value, _ = s.GetValue("two", "topping")
+	assert.Equal(t, "nuts", value)
+	assert.Equal(t, []string{"type", "fruit", "topping"}, s.GetKeyList("two"))
+
+	// Changes go to the file the key came from
+	s.SetValue("two", "fruit", "plum")
+	s.SetValue("two", "topping", "seeds")
+	s.SetValue("four", "token", "abc")
+	// New remotes get a new file
+	s.SetValue("five", "type", "number5")
+	// Empty files are removed
+	s.DeleteSection("four")
+	require.NoError(t, s.Save())
+
+	assert.Equal(t, `{
+  "two": {
+    "fruit": "plum"
+  }
+}
+`, read("20-override.json"))
+	assert.Contains(t, read("10-base.conf"), "topping = seeds")
+	assert.Equal(t, "[five]\ntype = number5\n\n", read("five.conf"))
+
+	s2 := newStorage("dir", dir)
+	require.NoError(t, s2.Load())
+	assert.Equal(t, []string{"one", "two", "three", "five"}, s2.GetSectionList())
+
+	s2.DeleteSection("two")
+	require.NoError(t, s2.Save())
+	_, err = os.Stat(filepath.Join(dir, "20-override.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	// The other storage sees the change
+	assert.False(t, s.HasSection("two"))
+
+	buf, err := s.Serialize()
+	require.NoError(t, err)
+	assert.Contains(t, toUnix(buf), "[five]\ntype = number5\n")
+}
+
+func TestURLStorage(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "rclone-configfile-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(cacheDir) }()
+	oldCacheDir := config.GetCacheDir()
+	require.NoError(t, config.SetCacheDir(cacheDir))
+	defer func() { _ = config.SetCacheDir(oldCacheDir) }()
+
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/config" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"one":{"type":"number1"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(configData))
+	}))
+	defer srv.Close()
+
+	s := newStorage("", srv.URL+"/rclone.conf")
+	require.NoError(t, s.Load())
+	assert.Equal(t, []string{"one", "two", "three"}, s.GetSectionList())
+
+	s = newStorage("url", srv.URL+"/config")
+	require.NoError(t, s.Load())
+	assert.Equal(t, []string{"one"}, s.GetSectionList())
+
+	// Changes are kept in memory only
+	s.SetValue("one", "token", "abc")
+	value, _ := s.GetValue("one", "token")
+	assert.Equal(t, "abc", value)
+	assert.Equal(t, config.ErrorConfigReadOnly, s.Save())
+
+	// The cached copy is used if the URL can't be read
+	up = false
+	s = newStorage("url", srv.URL+"/config")
+	require.NoError(t, s.Load())
+	assert.Equal(t, []string{"one"}, s.GetSectionList())
+	s = newStorage("url", srv.URL+"/notcached")
+	assert.Error(t, s.Load())
+}
+
+func TestNewStorage(t *testing.T) {
+	assert.IsType(t, &Storage{}, newStorage("", "/path/to/rclone.conf"))
+	assert.IsType(t, &Storage{}, newStorage("ini", "/path/to/rclone.json"))
+	assert.IsType(t, &fileStorage{}, newStorage("", "/path/to/rclone.json"))
+	assert.IsType(t, &fileStorage{}, newStorage("", "/path/to/rclone.yml"))
+	assert.IsType(t, &fileStorage{}, newStorage("yaml", "/path/to/rclone.conf"))
+	assert.IsType(t, &dirStorage{}, newStorage("dir", "/path/to/conf.d"))
+
+	old := config.GetConfigPath()
+	defer func() { _ = config.SetConfigPath(old) }()
+	for _, test := range []struct {
+		in     string
+		scheme string
+		path   string
+	}{
+		{"json:/path/rclone.json", "json", "/path/rclone.json"},
+		{"dir:/etc/rclone/conf.d", "dir", "/etc/rclone/conf.d"},
+		{"https://example.com/rclone.conf", "url", "https://example.com/rclone.conf"},
+		{"url:http://example.com/rclone.conf", "url", "http://example.com/rclone.conf"},
+		{"/path/rclone.conf", "", "/path/rclone.conf"},
+	} {
+		require.NoError(t, config.SetConfigPath(test.in))
+		assert.Equal(t, test.scheme, config.GetConfigScheme(), test.in)
+		assert.Equal(t, filepath.FromSlash(test.path), filepath.FromSlash(config.GetConfigPath()), test.in)
+	}
+	assert.Error(t, config.SetConfigPath("json:"))
+}
+
+func TestMergeOnSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-configfile-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, test := range []struct {
+		name string
+		path string
+		new  func(path string) config.Storage
+	}{
+		{"ini", "rclone.conf", func(path string) config.Storage { return &Storage{} }},
+		{"json", "rclone.json", func(path string) config.Storage { return newFileStorage(path, "json") }},
+		{"dir", "conf.d", func(path string) config.Storage { return newDirStorage(path) }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.path)
+			if test.name == "dir" {
+				require.NoError(t, os.Mkdir(path, 0700))
+				require.NoError(t, ioutil.WriteFile(filepath.Join(path, "base.conf"), []byte(configData), 0600))
+			} else {
+				data, err := formats[test.name].encode(testDocument(t))
+				require.NoError(t, err)
+				require.NoError(t, ioutil.WriteFile(path, data, 0600))
+			}
+			old := config.GetConfigPath()
+			require.NoError(t, config.SetConfigPath(path))
+			defer func() { _ = config.SetConfigPath(old) }()
+
+			// Two processes load the same config
+			s1, s2 := test.new(path), test.new(path)
+			require.NoError(t, s1.Load())
+			require.NoError(t, s2.Load())
+
+			// and change different things
+			s1.SetValue("one", "token", "token1")
+			s2.SetValue("two", "token", "token2")
+			s2.DeleteKey("three", "fruit")
+			require.NoError(t, s1.Save())
+			require.NoError(t, s2.Save())
+
+			// Nothing is lost
+			for _, s := range []config.Storage{s1, s2, test.new(path)} {
+				if s != s1 && s != s2 {
+					require.NoError(t, s.Load())
+				}
+				value, _ := s.GetValue("one", "token")
+				assert.Equal(t, "token1", value)
+				value, _ = s.GetValue("two", "token")
+				assert.Equal(t, "token2", value)
+				_, found := s.GetValue("three", "fruit")
+				assert.False(t, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package configfile
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config"
+	"github.com/rclone/rclone/fs/fshttp"
+	"github.com/rclone/rclone/lib/file"
+)
+
+// maximum size of config to download
+const maxURLConfigSize = 16 * 1024 * 1024
+
+// urlStorage implements config.Storage for config read from an HTTP
+// or HTTPS URL.
+//
+// The config is read only. It is cached in the cache directory and
+// the cached copy is used if the URL can't be read. Changes, such as
+// refreshed tokens, are kept in memory only.
+type urlStorage struct {
+	docStorage
+	url string
+}
+
+// newURLStorage makes a urlStorage for the config at rawURL
+func newURLStorage(rawURL string) *urlStorage {
+	return &urlStorage{url: rawURL}
+}
+
+// cachePath returns the path the config is cached at
+func (s *urlStorage) cachePath() string {
+	hash := md5.Sum([]byte(s.url))
+	return filepath.Join(config.GetCacheDir(), "config", hex.EncodeToString(hash[:]))
+}
+
+// fetch reads the config from the URL returning it and its content type
+func (s *urlStorage) fetch() (data []byte, contentType string, err error) {
+	ctx := context.Background()
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := fshttp.NewClient(ctx).Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer fs.CheckClose(resp.Body, &err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.Errorf("HTTP error %s", resp.Status)
+	}
+	data, err = ioutil.ReadAll(io.LimitReader(resp.Body, maxURLConfigSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxURLConfigSize {
+		return nil, "", errors.New("config too large")
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// formatForURL works out the format of the config from the URL, the
+// content type and the data
+func (s *urlStorage) formatForURL(contentType string, data []byte) format {
+	if u, err := url.Parse(s.url); err == nil {
+		if scheme := formatFromName(u.Path); scheme != "" {
+			return formats[scheme]
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return jsonFormat{}
+	case strings.HasSuffix(mediaType, "yaml"):
+		return yamlFormat{}
+	case bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")):
+		return jsonFormat{}
+	}
+	return iniFormat{}
+}
+
+// Load the config from the URL, or the cache if that fails
+func (s *urlStorage) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc == nil {
+		s.doc = newDocument()
+	}
+	cachePath := s.cachePath()
+	data, contentType, err := s.fetch()
+	if err != nil {
+		fs.Errorf(nil, "Failed to read config from %q - trying cached copy: %v", s.url, err)
+		var cacheErr error
+		data, cacheErr = ioutil.ReadFile(cachePath)
+		if cacheErr != nil {
+			return errors.Wrapf(err, "failed to read config from %q", s.url)
+		}
+	} else {
+		err = file.MkdirAll(filepath.Dir(cachePath), 0700)
+		if err == nil {
+			err = writeAtomic(cachePath, func(out io.Writer) error {
+				_, err := out.Write(data)
+				return err
+			})
+		}
+		if err != nil {
+			fs.Errorf(nil, "Failed to cache config: %v", err)
+		}
+	}
+	doc, err := decode(bytes.NewReader(data), s.formatForURL(contentType, data))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse config from %q", s.url)
+	}
+	s.doc = doc
+	return nil
+}
+
+// Save is not supported as the config is read only
+func (s *urlStorage) Save() error {
+	return config.ErrorConfigReadOnly
+}
+
+// Serialize the config into a string
+func (s *urlStorage) Serialize() (string, error) {
+	s.lock()
+	defer s.mu.Unlock()
+	data, err := iniFormat{}.encode(s.doc)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Check the interface is satisfied
+var _ config.Storage = (*urlStorage)(nil)
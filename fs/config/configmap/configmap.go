@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"sort"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -45,13 +46,16 @@ type Mapper interface {
 // Map provides a wrapper around multiple Setter and
 // Getter interfaces.
 type Map struct {
-	setters []Setter
-	getters []getprio
+	setters   []Setter
+	getters   []getprio
+	mu        sync.Mutex // protects secretErr
+	secretErr error      // first error reading a secret
 }
 
 type getprio struct {
 	getter   Getter
 	priority Priority
+	secrets  bool // resolve secret references in values
 }
 
 // New returns an empty Map
@@ -61,7 +65,23 @@ func New() *Map {
 
 // AddGetter appends a getter onto the end of the getters in priority order
 func (c *Map) AddGetter(getter Getter, priority Priority) *Map {
-	c.getters = append(c.getters, getprio{getter, priority})
+	return c.addGetter(getprio{getter: getter, priority: priority})
+}
+
+// AddSecretGetter appends a getter like AddGetter but any ${name:ref}
+// references to secret providers in its values are resolved when
+// read, and values set for those keys are written back to the secret
+// provider.
+//
+// Only use this for trusted sources of config such as the config
+// file, not for config supplied by remote users.
+func (c *Map) AddSecretGetter(getter Getter, priority Priority) *Map {
+	return c.addGetter(getprio{getter: getter, priority: priority, secrets: true})
+}
+
+// addGetter appends item onto the end of the getters in priority order
+func (c *Map) addGetter(item getprio) *Map {
+	c.getters = append(c.getters, item)
 	sort.SliceStable(c.getters, func(i, j int) bool {
 		return c.getters[i].priority < c.getters[j].priority
 	})
@@ -103,12 +123,56 @@ func (c *Map) GetPriority(key string, maxPriority Priority) (value string, ok bo
 		}
 		value, ok = item.getter.Get(key)
 		if ok {
+			if item.secrets {
+				resolved, err := ResolveSecrets(value)
+				if err != nil {
+					SecretError(key, err)
+					c.setSecretErr(errors.Wrapf(err, "config %q", key))
+					return "", false
+				}
+				value = resolved
+			}
 			return value, ok
 		}
 	}
 	return "", false
 }
 
+// setSecretErr records err if it is the first secret read error
+func (c *Map) setSecretErr(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.secretErr == nil {
+		c.secretErr = err
+	}
+}
+
+// SecretErr returns the first error reading a secret referred to by
+// a value, or nil if there wasn't one.
+//
+// Get treats a value whose secret can't be read as not found, so
+// check this before relying on the values read.
+func (c *Map) SecretErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.secretErr
+}
+
+// secretRef returns the secret provider and reference if the value
+// read for key is a reference to a secret
+func (c *Map) secretRef(key string) (provider SecretProvider, ref string, ok bool) {
+	for _, item := range c.getters {
+		value, found := item.getter.Get(key)
+		if found {
+			if !item.secrets {
+				return nil, "", false
+			}
+			return SecretRef(value)
+		}
+	}
+	return nil, "", false
+}
+
 // Get gets an item with the key passed in and return the value from
 // the first getter. If the item is found then it returns true,
 // otherwise false.
@@ -117,7 +181,17 @@ func (c *Map) Get(key string) (value string, ok bool) {
 }
 
 // Set sets an item into all the stored setters.
+//
+// If the value for key is read from a secret provider then the value
+// is written there instead so the reference is kept.
 func (c *Map) Set(key, value string) {
+	if provider, ref, ok := c.secretRef(key); ok {
+		err := provider.Set(ref, value)
+		if err != nil {
+			SecretError(key, err)
+		}
+		return
+	}
 	for _, do := range c.setters {
 		do.Set(key, value)
 	}
@@ -214,18 +214,18 @@ func TestConfigMapClearGetters(t *testing.T) {
 	m.AddGetter(m2, PriorityDefault)
 	m.AddGetter(m3, PriorityConfig)
 	assert.Equal(t, []getprio{
-		{m1, PriorityNormal},
-		{m3, PriorityConfig},
-		{m2, PriorityDefault},
+		{m1, PriorityNormal, false},
+		{m3, PriorityConfig, false},
+		{m2, PriorityDefault, false},
 	}, m.getters)
 	m.ClearGetters(PriorityConfig)
 	assert.Equal(t, []getprio{
-		{m1, PriorityNormal},
-		{m2, PriorityDefault},
+		{m1, PriorityNormal, false},
+		{m2, PriorityDefault, false},
 	}, m.getters)
 	m.ClearGetters(PriorityNormal)
 	assert.Equal(t, []getprio{
-		{m2, PriorityDefault},
+		{m2, PriorityDefault, false},
 	}, m.getters)
 	m.ClearGetters(PriorityDefault)
 	assert.Equal(t, []getprio{}, m.getters)
@@ -0,0 +1,155 @@
+// Secret providers for config values
+
+package configmap
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// SecretProvider reads and writes secrets kept outside the config
+// file.
+//
+// A config value refers to a secret with ${name:ref} where name is
+// the name the provider was registered with and ref is passed to the
+// provider.
+type SecretProvider interface {
+	// Get returns the secret ref
+	Get(ref string) (value string, err error)
+	// Set stores value as the secret ref. Providers which can't
+	// be written to should return ErrorSecretReadOnly.
+	Set(ref, value string) error
+}
+
+// ErrorSecretReadOnly is returned by SecretProvider.Set if the secret
+// can't be written
+var ErrorSecretReadOnly = errors.New("secret provider is read only")
+
+// SecretError is called when a secret can't be read or written. It
+// is set by the fs package to log the error.
+var SecretError = func(key string, err error) {}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{}
+)
+
+// RegisterSecretProvider makes the provider available as ${name:ref}
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[name] = provider
+}
+
+// SecretProviders returns the sorted names of the registered secret
+// providers
+func SecretProviders() (names []string) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	for name := range secretProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getSecretProvider returns the provider called name or nil
+func getSecretProvider(name string) SecretProvider {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	return secretProviders[name]
+}
+
+// matches ${name:ref}
+var matchSecret = regexp.MustCompile(`\$\{([a-z][a-z0-9]*):([^}]*)\}`)
+
+// SecretRef returns the provider and reference if value consists of
+// a single reference to a registered secret provider.
+func SecretRef(value string) (provider SecretProvider, ref string, ok bool) {
+	match := matchSecret.FindStringSubmatchIndex(value)
+	if match == nil || match[0] != 0 || match[1] != len(value) {
+		return nil, "", false
+	}
+	provider = getSecretProvider(value[match[2]:match[3]])
+	if provider == nil {
+		return nil, "", false
+	}
+	return provider, value[match[4]:match[5]], true
+}
+
+// ResolveSecrets replaces each ${name:ref} in value with the secret
+// from the provider registered as name.
+//
+// References to providers which aren't registered are left alone so
+// existing values containing "${" keep working.
+func ResolveSecrets(value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	var err error
+	value = matchSecret.ReplaceAllStringFunc(value, func(match string) string {
+		parts := matchSecret.FindStringSubmatch(match)
+		provider := getSecretProvider(parts[1])
+		if provider == nil || err != nil {
+			return match
+		}
+		var secret string
+		secret, err = provider.Get(parts[2])
+		if err != nil {
+			err = errors.Wrapf(err, "failed to read secret %s", match)
+		}
+		return secret
+	})
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// cachedSecrets wraps a SecretProvider which is slow to read,
+// remembering the secrets read and written
+type cachedSecrets struct {
+	SecretProvider
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// CacheSecrets returns provider wrapped so each secret is only read
+// once
+func CacheSecrets(provider SecretProvider) SecretProvider {
+	return &cachedSecrets{
+		SecretProvider: provider,
+		cache:          map[string]string{},
+	}
+}
+
+// Get the secret from the cache or the provider
+func (c *cachedSecrets) Get(ref string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, ok := c.cache[ref]; ok {
+		return value, nil
+	}
+	value, err := c.SecretProvider.Get(ref)
+	if err != nil {
+		return "", err
+	}
+	c.cache[ref] = value
+	return value, nil
+}
+
+// Set the secret in the provider and the cache
+func (c *cachedSecrets) Set(ref, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := c.SecretProvider.Set(ref, value)
+	if err != nil {
+		return err
+	}
+	c.cache[ref] = value
+	return nil
+}
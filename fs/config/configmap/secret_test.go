@@ -0,0 +1,126 @@
+package configmap
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSecrets is a SecretProvider for testing
+type testSecrets Simple
+
+func (s testSecrets) Get(ref string) (string, error) {
+	value, ok := s[ref]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func (s testSecrets) Set(ref, value string) error {
+	if ref == "readonly" {
+		return ErrorSecretReadOnly
+	}
+	s[ref] = value
+	return nil
+}
+
+func TestSecrets(t *testing.T) {
+	secrets := testSecrets{"pass": "secret", "token": "old", "readonly": "x"}
+	RegisterSecretProvider("test", secrets)
+	defer func() {
+		secretProvidersMu.Lock()
+		delete(secretProviders, "test")
+		secretProvidersMu.Unlock()
+	}()
+	assert.Contains(t, SecretProviders(), "test")
+
+	var errs []string
+	oldSecretError := SecretError
+	SecretError = func(key string, err error) { errs = append(errs, key+": "+err.Error()) }
+	defer func() { SecretError = oldSecretError }()
+
+	for _, test := range []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{"plain", "plain", false},
+		{"${test:pass}", "secret", false},
+		{"user:${test:pass}@${test:token}", "user:secret@old", false},
+		{"${unknown:pass}", "${unknown:pass}", false},
+		{"$test:pass", "$test:pass", false},
+		{"${test:missing}", "", true},
+	} {
+		got, err := ResolveSecrets(test.in)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.err, err != nil, test.in)
+	}
+
+	_, ref, ok := SecretRef("${test:token}")
+	assert.True(t, ok)
+	assert.Equal(t, "token", ref)
+	for _, value := range []string{"x${test:token}", "${test:token}x", "${unknown:token}", "token"} {
+		_, _, ok = SecretRef(value)
+		assert.False(t, ok, value)
+	}
+
+	config := Simple{"pass": "${test:pass}", "token": "${test:token}", "ro": "${test:readonly}", "missing": "${test:missing}", "plain": "plain"}
+	untrusted := Simple{"user": "${test:pass}"}
+	m := New()
+	m.AddGetter(untrusted, PriorityNormal)
+	m.AddSecretGetter(config, PriorityConfig)
+	m.AddSetter(config)
+	assert.NoError(t, m.SecretErr())
+
+	value, ok := m.Get("pass")
+	assert.True(t, ok)
+	assert.Equal(t, "secret", value)
+	value, ok = m.Get("user")
+	assert.True(t, ok)
+	assert.Equal(t, "${test:pass}", value)
+	_, ok = m.Get("missing")
+	assert.False(t, ok)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "missing: failed to read secret ${test:missing}")
+	require.Error(t, m.SecretErr())
+	assert.Contains(t, m.SecretErr().Error(), `config "missing"`)
+
+	// Set writes back to the provider keeping the reference
+	m.Set("token", "new")
+	assert.Equal(t, "new", secrets["token"])
+	assert.Equal(t, "${test:token}", config["token"])
+	value, _ = m.Get("token")
+	assert.Equal(t, "new", value)
+
+	// Read only providers aren't overwritten
+	m.Set("ro", "y")
+	assert.Equal(t, "${test:readonly}", config["ro"])
+	require.Len(t, errs, 2)
+	assert.Equal(t, "ro: "+ErrorSecretReadOnly.Error(), errs[1])
+
+	// Other values are set as normal
+	m.Set("plain", "changed")
+	assert.Equal(t, "changed", config["plain"])
+	m.Set("user", "other")
+	assert.Equal(t, "other", config["user"])
+}
+
+func TestCacheSecrets(t *testing.T) {
+	secrets := testSecrets{"pass": "secret"}
+	cached := CacheSecrets(secrets)
+	value, err := cached.Get("pass")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", value)
+	secrets["pass"] = "changed"
+	value, _ = cached.Get("pass")
+	assert.Equal(t, "secret", value)
+	require.NoError(t, cached.Set("pass", "new"))
+	value, _ = cached.Get("pass")
+	assert.Equal(t, "new", value)
+	assert.Equal(t, "new", secrets["pass"])
+	_, err = cached.Get("missing")
+	assert.Error(t, err)
+}
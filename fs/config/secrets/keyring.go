@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs/config/configmap"
+)
+
+// keyringSecrets reads and writes secrets in the operating system
+// keyring as ${keyring:service/account}. If there is no "/" then the
+// service is "rclone".
+//
+// It uses the "security" tool on macOS and "secret-tool" from
+// libsecret on other unix systems. Secrets can't be written on macOS.
+type keyringSecrets struct{}
+
+// splitKeyringRef splits ref into service and account
+func splitKeyringRef(ref string) (service, account string) {
+	i := strings.LastIndex(ref, "/")
+	if i < 0 {
+		return "rclone", ref
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// runKeyring runs the keyring tool with stdin returning its output
+func runKeyring(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "%s failed: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
+
+// Get the secret from the keyring
+func (keyringSecrets) Get(ref string) (string, error) {
+	service, account := splitKeyringRef(ref)
+	switch runtime.GOOS {
+	case "windows":
+		return "", errors.New("keyring secrets are not supported on windows")
+	case "darwin":
+		return runKeyring("", "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	default:
+		value, err := runKeyring("", "secret-tool", "lookup", "service", service, "account", account)
+		if err == nil && value == "" {
+			err = errors.Errorf("secret %q not found in keyring", ref)
+		}
+		return value, err
+	}
+}
+
+// Set the secret in the keyring
+func (keyringSecrets) Set(ref, value string) error {
+	service, account := splitKeyringRef(ref)
+	var err error
+	switch runtime.GOOS {
+	case "windows":
+		err = errors.New("keyring secrets are not supported on windows")
+	case "darwin":
+		// security only takes the password on its command line
+		// where other users can see it, so don't write it
+		err = errors.Wrap(configmap.ErrorSecretReadOnly, "keyring secrets can't be written on macOS - update them with Keychain Access")
+	default:
+		_, err = runKeyring(value, "secret-tool", "store", "--label", "rclone "+ref, "service", service, "account", account)
+	}
+	return err
+}
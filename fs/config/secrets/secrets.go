@@ -0,0 +1,78 @@
+// Package secrets implements the built in secret providers which
+// config values can refer to with ${name:ref}
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs/config/configmap"
+)
+
+func init() {
+	configmap.RegisterSecretProvider("env", envSecrets{})
+	configmap.RegisterSecretProvider("file", fileSecrets{})
+	configmap.RegisterSecretProvider("keyring", configmap.CacheSecrets(keyringSecrets{}))
+	configmap.RegisterSecretProvider("vault", configmap.CacheSecrets(newVaultSecrets()))
+}
+
+// envSecrets reads secrets from environment variables as ${env:VAR}
+type envSecrets struct{}
+
+// Get the environment variable ref
+func (envSecrets) Get(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", errors.Errorf("environment variable %q not set", ref)
+	}
+	return value, nil
+}
+
+// Set is not supported
+func (envSecrets) Set(ref, value string) error {
+	return configmap.ErrorSecretReadOnly
+}
+
+// fileSecrets reads secrets from files as ${file:/path/to/file}
+//
+// A single trailing newline is removed from the contents of the file
+// as most tools which write secrets to files add one.
+type fileSecrets struct{}
+
+// Get the contents of the file ref
+func (fileSecrets) Get(ref string) (string, error) {
+	data, err := ioutil.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(value, "\r"), nil
+}
+
+// Set the contents of the file ref, replacing it atomically
+//
+// The new contents are written to a uniquely named temporary file
+// first so concurrent writers can't interfere with each other.
+func (fileSecrets) Set(ref, value string) (err error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(ref), filepath.Base(ref)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	_, err = tmp.WriteString(value + "\n")
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), ref)
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSecrets(t *testing.T) {
+	require.NoError(t, os.Setenv("RCLONE_TEST_SECRET", "potato"))
+	defer func() { _ = os.Unsetenv("RCLONE_TEST_SECRET") }()
+	value, err := configmap.ResolveSecrets("${env:RCLONE_TEST_SECRET}")
+	require.NoError(t, err)
+	assert.Equal(t, "potato", value)
+	_, err = configmap.ResolveSecrets("${env:RCLONE_TEST_SECRET_NOT_SET}")
+	assert.Error(t, err)
+	assert.Equal(t, configmap.ErrorSecretReadOnly, envSecrets{}.Set("RCLONE_TEST_SECRET", "x"))
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-secrets")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "secret")
+	require.NoError(t, ioutil.WriteFile(path, []byte("potato\n"), 0600))
+
+	value, err := configmap.ResolveSecrets("${file:" + path + "}")
+	require.NoError(t, err)
+	assert.Equal(t, "potato", value)
+
+	require.NoError(t, fileSecrets{}.Set(path, "carrot"))
+	value, err = fileSecrets{}.Get(path)
+	require.NoError(t, err)
+	assert.Equal(t, "carrot", value)
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file left behind")
+
+	_, err = fileSecrets{}.Get(filepath.Join(dir, "notfound"))
+	assert.Error(t, err)
+}
+
+func TestSplitRefs(t *testing.T) {
+	service, account := splitKeyringRef("rclone/s3")
+	assert.Equal(t, "rclone", service)
+	assert.Equal(t, "s3", account)
+	service, account = splitKeyringRef("drive")
+	assert.Equal(t, "rclone", service)
+	assert.Equal(t, "drive", account)
+
+	path, key := splitVaultRef("kv/data/rclone#token")
+	assert.Equal(t, "kv/data/rclone", path)
+	assert.Equal(t, "token", key)
+	path, key = splitVaultRef("secret/rclone")
+	assert.Equal(t, "secret/rclone", path)
+	assert.Equal(t, "value", key)
+}
+
+func TestVaultSecrets(t *testing.T) {
+	store := map[string]map[string]interface{}{
+		"kv/data/rclone": {"token": "v2token", "other": "keep"},
+		"secret/rclone":  {"value": "v1value"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "testtoken", r.Header.Get("X-Vault-Token"))
+		path := r.URL.Path[len("/v1/"):]
+		data, ok := store[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		v2 := path == "kv/data/rclone"
+		switch r.Method {
+		case "GET":
+			var out interface{} = map[string]interface{}{"data": data}
+			if v2 {
+				out = map[string]interface{}{"data": map[string]interface{}{"data": data, "metadata": map[string]interface{}{}}}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(out))
+		case "POST":
+			var in map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+			if v2 {
+				in = in["data"].(map[string]interface{})
+			}
+			store[path] = in
+		}
+	}))
+	defer srv.Close()
+	for key, value := range map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "testtoken"} {
+		old, wasSet := os.LookupEnv(key)
+		require.NoError(t, os.Setenv(key, value))
+		defer func(key, old string, wasSet bool) {
+			if wasSet {
+				_ = os.Setenv(key, old)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		}(key, old, wasSet)
+	}
+
+	v := newVaultSecrets()
+	value, err := v.Get("kv/data/rclone#token")
+	require.NoError(t, err)
+	assert.Equal(t, "v2token", value)
+	value, err = v.Get("secret/rclone")
+	require.NoError(t, err)
+	assert.Equal(t, "v1value", value)
+	_, err = v.Get("kv/data/rclone#missing")
+	assert.Error(t, err)
+	_, err = v.Get("kv/data/notfound#token")
+	assert.Error(t, err)
+
+	require.NoError(t, v.Set("kv/data/rclone#token", "new"))
+	assert.Equal(t, map[string]interface{}{"token": "new", "other": "keep"}, store["kv/data/rclone"])
+	require.NoError(t, v.Set("secret/rclone", "new1"))
+	assert.Equal(t, map[string]interface{}{"value": "new1"}, store["secret/rclone"])
+}
@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+)
+
+// vaultSecrets reads and writes secrets in HashiCorp Vault as
+// ${vault:path#key}, for example ${vault:kv/data/rclone#token}. If
+// there is no "#key" then the key is "value".
+//
+// Both version 1 and version 2 of the KV secrets engine are
+// supported. Vault is found with the VAULT_ADDR, VAULT_TOKEN and
+// VAULT_NAMESPACE environment variables, or ~/.vault-token as
+// written by "vault login".
+type vaultSecrets struct {
+	client *http.Client
+}
+
+// newVaultSecrets makes a new vault secret provider
+func newVaultSecrets() *vaultSecrets {
+	return &vaultSecrets{
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// splitVaultRef splits ref into path and key
+func splitVaultRef(ref string) (path, key string) {
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return ref, "value"
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// vaultToken returns the token to use with vault
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.New("VAULT_TOKEN not set")
+	}
+	token, err := ioutil.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", errors.New("VAULT_TOKEN not set and no ~/.vault-token")
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// call does an API request to vault
+func (v *vaultSecrets) call(method, path string, in, out interface{}) error {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return errors.New("VAULT_ADDR not set")
+	}
+	token, err := vaultToken()
+	if err != nil {
+		return err
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		errBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return errors.Errorf("vault returned %s: %s", resp.Status, strings.TrimSpace(string(errBody)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// read returns the secrets at path and whether it is a KV version 2 store
+func (v *vaultSecrets) read(path string) (data map[string]interface{}, v2 bool, err error) {
+	var result struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err = v.call("GET", path, nil, &result)
+	if err != nil {
+		return nil, false, err
+	}
+	if inner, ok := result.Data["data"].(map[string]interface{}); ok {
+		if _, ok := result.Data["metadata"]; ok {
+			return inner, true, nil
+		}
+	}
+	return result.Data, false, nil
+}
+
+// Get the secret from vault
+func (v *vaultSecrets) Get(ref string) (string, error) {
+	path, key := splitVaultRef(ref)
+	data, _, err := v.read(path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[key].(string)
+	if !ok {
+		return "", errors.Errorf("key %q not found in vault secret %q", key, path)
+	}
+	return value, nil
+}
+
+// Set the secret in vault keeping the other keys at path
+func (v *vaultSecrets) Set(ref, value string) error {
+	path, key := splitVaultRef(ref)
+	data, v2, err := v.read(path)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data[key] = value
+	if v2 {
+		return v.call("POST", path, map[string]interface{}{"data": data}, nil)
+	}
+	return v.call("POST", path, data, nil)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetConfig(t *testing.T) {
@@ -27,3 +28,16 @@ func TestGetConfig(t *testing.T) {
 	config2ctx := GetConfig(ctx2)
 	assert.Equal(t, config2, config2ctx)
 }
+
+func TestFixConcurrency(t *testing.T) {
+	c := NewConfig()
+	c.Transfers, c.Checkers = 4, 2
+	c.MinTransfers, c.MinCheckers = 0, 3
+	require.NoError(t, c.FixConcurrency())
+	assert.Equal(t, 1, c.MinTransfers)
+	assert.Equal(t, 2, c.MinCheckers)
+
+	c.AdaptiveConcurrency = true
+	c.AdaptiveInterval = 0
+	assert.Error(t, c.FixConcurrency())
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/rclone/rclone/fs/config/configmap"
 )
 
+func init() {
+	configmap.SecretError = func(key string, err error) {
+		Errorf(nil, "Config %q: %v", key, err)
+	}
+}
+
 // A configmap.Getter to read from the environment RCLONE_CONFIG_backend_option_name
 type configEnvVars string
 
@@ -93,6 +99,10 @@ func (section getConfigFile) Get(key string) (value string, ok bool) {
 // configName passed in. If connectionStringConfig has any entries (it may be nil),
 // then it will be added to the lookup with the highest priority.
 //
+// Values from the flags, environment and config file may refer to
+// secret providers with ${name:ref}, but values from the connection
+// string may not, as it can come from remote users.
+//
 // If fsInfo is nil then the returned configmap.Map should only be
 // used for reading non backend specific parameters, such as "type".
 func ConfigMap(fsInfo *RegInfo, configName string, connectionStringConfig configmap.Simple) (config *configmap.Map) {
@@ -108,19 +118,19 @@ func ConfigMap(fsInfo *RegInfo, configName string, connectionStringConfig config
 
 	// flag values
 	if fsInfo != nil {
-		config.AddGetter(&regInfoValues{fsInfo, false}, configmap.PriorityNormal)
+		config.AddSecretGetter(&regInfoValues{fsInfo, false}, configmap.PriorityNormal)
 	}
 
 	// remote specific environment vars
-	config.AddGetter(configEnvVars(configName), configmap.PriorityNormal)
+	config.AddSecretGetter(configEnvVars(configName), configmap.PriorityNormal)
 
 	// backend specific environment vars
 	if fsInfo != nil {
-		config.AddGetter(optionEnvVars{fsInfo: fsInfo}, configmap.PriorityNormal)
+		config.AddSecretGetter(optionEnvVars{fsInfo: fsInfo}, configmap.PriorityNormal)
 	}
 
 	// config file
-	config.AddGetter(getConfigFile(configName), configmap.PriorityConfig)
+	config.AddSecretGetter(getConfigFile(configName), configmap.PriorityConfig)
 
 	// default values
 	if fsInfo != nil {
@@ -72,6 +72,31 @@ type Features struct {
 	// uses polling, it should adhere to the given interval.
 	ChangeNotify func(context.Context, func(string, EntryType), <-chan time.Duration)
 
+	// ChangeCursor returns a cursor for the current position in
+	// the changes of the remote to pass to ListChanges later.
+	ChangeCursor func(ctx context.Context) (cursor string, err error)
+
+	// ListChanges calls callback with the path of each entry
+	// under the root changed since cursor was read and returns
+	// the cursor to use next time. See ChangeLister for details.
+	ListChanges func(ctx context.Context, cursor string, callback func(remote string, entryType EntryType) error) (newCursor string, err error)
+
+	// ListVersions calls callback with the versions of each
+	// object under dir, newest first. See Versioner for details.
+	ListVersions func(ctx context.Context, dir string, callback func(remote string, versions []ObjectVersion) error) error
+
+	// OpenVersion opens the version of the object at remote
+	// with the ID given for reading.
+	OpenVersion func(ctx context.Context, remote string, versionID string, options ...OpenOption) (io.ReadCloser, error)
+
+	// RestoreVersion makes the version of the object at remote
+	// with the ID given the current version.
+	RestoreVersion func(ctx context.Context, remote string, versionID string) error
+
+	// DeleteVersion deletes the version of the object at remote
+	// with the ID given.
+	DeleteVersion func(ctx context.Context, remote string, versionID string) error
+
 	// UnWrap returns the Fs that this Fs is wrapping
 	UnWrap func() Fs
 
@@ -133,6 +158,16 @@ type Features struct {
 	// of listing recursively that doing a directory traversal.
 	ListR ListRFn
 
+	// ListHinted lists the objects and directories in dir like
+	// List, but may use the hint to ask the remote for fewer
+	// entries. It must return at least every entry which could
+	// match the hint, and may return more.
+	ListHinted func(ctx context.Context, dir string, hint *ListHint) (entries DirEntries, err error)
+
+	// ListRHinted lists recursively like ListR, but may use the
+	// hint to ask the remote for fewer entries, like ListHinted.
+	ListRHinted func(ctx context.Context, dir string, hint *ListHint, callback ListRCallback) error
+
 	// About gets quota information from the Fs
 	About func(ctx context.Context) (*Usage, error)
 
@@ -244,6 +279,16 @@ func (ft *Features) Fill(ctx context.Context, f Fs) *Features {
 	if do, ok := f.(ChangeNotifier); ok {
 		ft.ChangeNotify = do.ChangeNotify
 	}
+	if do, ok := f.(ChangeLister); ok {
+		ft.ChangeCursor = do.ChangeCursor
+		ft.ListChanges = do.ListChanges
+	}
+	if do, ok := f.(Versioner); ok {
+		ft.ListVersions = do.ListVersions
+		ft.OpenVersion = do.OpenVersion
+		ft.RestoreVersion = do.RestoreVersion
+		ft.DeleteVersion = do.DeleteVersion
+	}
 	if do, ok := f.(UnWrapper); ok {
 		ft.UnWrap = do.UnWrap
 	}
@@ -272,6 +317,12 @@ func (ft *Features) Fill(ctx context.Context, f Fs) *Features {
 	if do, ok := f.(ListRer); ok {
 		ft.ListR = do.ListR
 	}
+	if do, ok := f.(ListHinter); ok {
+		ft.ListHinted = do.ListHinted
+	}
+	if do, ok := f.(ListRHinter); ok {
+		ft.ListRHinted = do.ListRHinted
+	}
 	if do, ok := f.(Abouter); ok {
 		ft.About = do.About
 	}
@@ -330,6 +381,24 @@ func (ft *Features) Mask(ctx context.Context, f Fs) *Features {
 	if mask.ChangeNotify == nil {
 		ft.ChangeNotify = nil
 	}
+	if mask.ChangeCursor == nil {
+		ft.ChangeCursor = nil
+	}
+	if mask.ListChanges == nil {
+		ft.ListChanges = nil
+	}
+	if mask.ListVersions == nil {
+		ft.ListVersions = nil
+	}
+	if mask.OpenVersion == nil {
+		ft.OpenVersion = nil
+	}
+	if mask.RestoreVersion == nil {
+		ft.RestoreVersion = nil
+	}
+	if mask.DeleteVersion == nil {
+		ft.DeleteVersion = nil
+	}
 	// if mask.UnWrap == nil {
 	// 	ft.UnWrap = nil
 	// }
@@ -357,6 +426,12 @@ func (ft *Features) Mask(ctx context.Context, f Fs) *Features {
 	if mask.ListR == nil {
 		ft.ListR = nil
 	}
+	if mask.ListHinted == nil {
+		ft.ListHinted = nil
+	}
+	if mask.ListRHinted == nil {
+		ft.ListRHinted = nil
+	}
 	if mask.About == nil {
 		ft.About = nil
 	}
@@ -466,6 +541,53 @@ type ChangeNotifier interface {
 	ChangeNotify(context.Context, func(string, EntryType), <-chan time.Duration)
 }
 
+// ChangeLister is an optional interface for Fs
+type ChangeLister interface {
+	// ChangeCursor returns a cursor for the current position in
+	// the changes of the remote to pass to ListChanges later.
+	ChangeCursor(ctx context.Context) (cursor string, err error)
+
+	// ListChanges calls callback with the path of each entry
+	// under the root changed since cursor was read and returns
+	// the cursor to use next time.
+	//
+	// A path may be given more than once. If entryType is
+	// EntryDirectory then anything in the directory may have
+	// changed too, and a deleted entry which might have been a
+	// directory should be given as one.
+	//
+	// If the cursor is invalid or has expired it should return
+	// ErrorChangeCursorInvalid. If some changes couldn't be
+	// turned into paths, like deleted entries whose paths
+	// weren't known, it should return the new cursor with
+	// ErrorChangesIncomplete.
+	ListChanges(ctx context.Context, cursor string, callback func(remote string, entryType EntryType) error) (newCursor string, err error)
+}
+
+// Versioner is an optional interface for Fs which keep old versions
+// of objects
+type Versioner interface {
+	// ListVersions calls callback with the versions of each
+	// object under dir recursively, newest first.
+	//
+	// Objects which have been deleted but still have old versions
+	// should be included. If the backend records when objects
+	// were deleted the newest version should be a Deleted one.
+	ListVersions(ctx context.Context, dir string, callback func(remote string, versions []ObjectVersion) error) error
+
+	// OpenVersion opens the version of the object at remote
+	// with the ID given for reading.
+	OpenVersion(ctx context.Context, remote string, versionID string, options ...OpenOption) (io.ReadCloser, error)
+
+	// RestoreVersion makes the version of the object at remote
+	// with the ID given the current version.
+	RestoreVersion(ctx context.Context, remote string, versionID string) error
+
+	// DeleteVersion deletes the version of the object at remote
+	// with the ID given.
+	DeleteVersion(ctx context.Context, remote string, versionID string) error
+}
+
 // EntryType can be associated with remote paths to identify their type
 type EntryType int
 
@@ -564,6 +686,22 @@ type ListRer interface {
 	ListR(ctx context.Context, dir string, callback ListRCallback) error
 }
 
+// ListHinter is an optional interface for Fs
+type ListHinter interface {
+	// ListHinted lists the objects and directories in dir like
+	// List, but may use the hint to ask the remote for fewer
+	// entries. It must return at least every entry which could
+	// match the hint, and may return more.
+	ListHinted(ctx context.Context, dir string, hint *ListHint) (entries DirEntries, err error)
+}
+
+// ListRHinter is an optional interface for Fs
+type ListRHinter interface {
+	// ListRHinted lists recursively like ListR, but may use the
+	// hint to ask the remote for fewer entries, like ListHinted.
+	ListRHinted(ctx context.Context, dir string, hint *ListHint, callback ListRCallback) error
+}
+
 // RangeSeeker is the interface that wraps the RangeSeek method.
 //
 // Some of the returns from Object.Open() may optionally implement
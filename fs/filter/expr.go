@@ -0,0 +1,590 @@
+// Boolean filter expressions for --filter-expr
+
+package filter
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// exprNode is a node of a parsed filter expression
+type exprNode interface {
+	// eval returns whether the target matches the expression
+	eval(t *exprTarget) bool
+	// String returns the expression in canonical form
+	String() string
+}
+
+// exprAnd matches if both sides match
+type exprAnd struct {
+	left, right exprNode
+}
+
+func (e *exprAnd) eval(t *exprTarget) bool {
+	return e.left.eval(t) && e.right.eval(t)
+}
+
+func (e *exprAnd) String() string {
+	return "(" + e.left.String() + " AND " + e.right.String() + ")"
+}
+
+// exprOr matches if either side matches
+type exprOr struct {
+	left, right exprNode
+}
+
+func (e *exprOr) eval(t *exprTarget) bool {
+	return e.left.eval(t) || e.right.eval(t)
+}
+
+func (e *exprOr) String() string {
+	return "(" + e.left.String() + " OR " + e.right.String() + ")"
+}
+
+// exprNot matches if x doesn't match
+type exprNot struct {
+	x exprNode
+}
+
+func (e *exprNot) eval(t *exprTarget) bool {
+	return !e.x.eval(t)
+}
+
+func (e *exprNot) String() string {
+	return "NOT " + e.x.String()
+}
+
+// fieldKind is the type of the value of a field
+type fieldKind byte
+
+// Types of field
+const (
+	kindString fieldKind = iota
+	kindInt
+	kindTime
+)
+
+// exprField is a property of an object which can be compared
+type exprField struct {
+	name     string // canonical name, eg "size" or "hash.md5"
+	kind     fieldKind
+	hashType hash.Type // for hash.TYPE
+	metaKey  string    // for meta.KEY
+}
+
+// parseField parses the name of a field
+func parseField(name string) (field exprField, err error) {
+	lower := strings.ToLower(name)
+	switch lower {
+	case "name", "path", "dir", "mime":
+		field.kind = kindString
+	case "size", "depth":
+		field.kind = kindInt
+	case "age", "modtime":
+		field.kind = kindTime
+	default:
+		switch {
+		case strings.HasPrefix(lower, "hash."):
+			field.kind = kindString
+			err = field.hashType.Set(name[len("hash."):])
+			if err != nil || field.hashType == hash.None {
+				return field, errors.Errorf("unknown hash type in %q", name)
+			}
+			lower = "hash." + field.hashType.String()
+		case strings.HasPrefix(lower, "meta.") && len(name) > len("meta."):
+			field.kind = kindString
+			field.metaKey = name[len("meta."):]
+			lower = "meta." + field.metaKey
+		default:
+			return field, errors.Errorf("unknown field %q", name)
+		}
+	}
+	field.name = lower
+	return field, nil
+}
+
+// exprCompare compares a field of the object with a value
+type exprCompare struct {
+	field      exprField
+	op         string // one of = != < <= > >= ~ !~ =~
+	value      string // as written
+	ignoreCase bool
+	re         *regexp.Regexp // for ~ !~ =~
+	n          int64          // for kindInt
+	t          time.Time      // for kindTime
+	timeOp     string         // op to compare the modtime with t
+}
+
+// newCompare makes an exprCompare checking the value is valid for
+// the field and op
+func newCompare(field exprField, op, value string, ignoreCase bool, now time.Time) (c *exprCompare, err error) {
+	if op == "==" {
+		op = "="
+	}
+	c = &exprCompare{
+		field:      field,
+		op:         op,
+		value:      value,
+		ignoreCase: ignoreCase,
+	}
+	switch op {
+	case "~", "!~", "=~":
+		if field.kind != kindString {
+			return nil, errors.Errorf("can't use %q with %q", op, field.name)
+		}
+	}
+	switch field.kind {
+	case kindString:
+		switch op {
+		case "~", "!~":
+			c.re, err = globToRegexp(value, ignoreCase)
+		case "=~":
+			if ignoreCase {
+				value = "(?i)" + value
+			}
+			c.re, err = regexp.Compile(value)
+		}
+	case kindInt:
+		if field.name == "size" {
+			var size fs.SizeSuffix
+			err = size.Set(value)
+			c.n = int64(size)
+		} else {
+			c.n, err = strconv.ParseInt(value, 10, 64)
+		}
+	case kindTime:
+		var d time.Duration
+		d, err = fs.ParseDuration(value)
+		c.t = now.Add(-d)
+		c.timeOp = op
+		if field.name == "age" {
+			// the older the object the smaller its modtime
+			c.timeOp = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[op]
+			if c.timeOp == "" {
+				c.timeOp = op
+			}
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad value for %q", field.name)
+	}
+	return c, nil
+}
+
+// test applies op to the result of a comparison
+func test(op string, cmp int) bool {
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func (c *exprCompare) eval(t *exprTarget) bool {
+	switch c.field.kind {
+	case kindInt:
+		n := t.size
+		if c.field.name == "depth" {
+			n = int64(strings.Count(t.remote, "/") + 1)
+		}
+		switch {
+		case n < c.n:
+			return test(c.op, -1)
+		case n > c.n:
+			return test(c.op, 1)
+		}
+		return test(c.op, 0)
+	case kindTime:
+		modTime := t.getModTime()
+		switch {
+		case modTime.Before(c.t):
+			return test(c.timeOp, -1)
+		case modTime.After(c.t):
+			return test(c.timeOp, 1)
+		}
+		return test(c.timeOp, 0)
+	}
+	value, ok := c.stringValue(t)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "~", "=~":
+		return c.re.MatchString(value)
+	case "!~":
+		return !c.re.MatchString(value)
+	case "=", "!=":
+		equal := value == c.value || (c.ignoreCase && strings.EqualFold(value, c.value))
+		return equal == (c.op == "=")
+	}
+	return test(c.op, strings.Compare(value, c.value))
+}
+
+// stringValue returns the value of a string field or false if it
+// isn't available
+func (c *exprCompare) stringValue(t *exprTarget) (string, bool) {
+	switch c.field.name {
+	case "name":
+		return path.Base(t.remote), true
+	case "path":
+		return t.remote, true
+	case "dir":
+		dir := path.Dir(t.remote)
+		if dir == "." {
+			dir = ""
+		}
+		return dir, true
+	case "mime":
+		return t.getMimeType(), true
+	}
+	if c.field.metaKey != "" {
+		return t.getMetadata(c.field.metaKey)
+	}
+	return t.getHash(c.field.hashType)
+}
+
+func (c *exprCompare) String() string {
+	return c.field.name + " " + c.op + " " + quoteExprValue(c.value)
+}
+
+// quoteExprValue quotes value if it wouldn't be read back as a
+// single word
+func quoteExprValue(value string) string {
+	if value == "" || isExprKeyword(value) {
+		return strconv.Quote(value)
+	}
+	for _, c := range value {
+		if !isExprWordRune(c) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
+
+// exprTarget is the object a filter expression is evaluated
+// against. The properties which are expensive to read are only read
+// from the object if needed.
+type exprTarget struct {
+	ctx         context.Context
+	remote      string
+	size        int64
+	o           fs.Object // may be nil
+	modTime     time.Time
+	haveModTime bool
+	metadata    map[string]string
+	haveMeta    bool
+}
+
+// getModTime returns the modification time of the target
+func (t *exprTarget) getModTime() time.Time {
+	if !t.haveModTime && t.o != nil {
+		t.modTime = t.o.ModTime(t.ctx)
+	}
+	t.haveModTime = true
+	return t.modTime
+}
+
+// getMimeType returns the mime type of the target
+func (t *exprTarget) getMimeType() string {
+	if t.o != nil {
+		return fs.MimeType(t.ctx, t.o)
+	}
+	return fs.MimeTypeFromName(t.remote)
+}
+
+// getHash returns the hash of the target or false if it isn't
+// available
+func (t *exprTarget) getHash(ht hash.Type) (string, bool) {
+	if t.o == nil {
+		return "", false
+	}
+	sum, err := t.o.Hash(t.ctx, ht)
+	if err != nil {
+		fs.Debugf(t.o, "Failed to read %v hash for filter expression: %v", ht, err)
+		return "", false
+	}
+	return sum, sum != ""
+}
+
+// getMetadata returns the metadata key of the target or false if it
+// isn't available
+func (t *exprTarget) getMetadata(key string) (string, bool) {
+	if !t.haveMeta {
+		t.haveMeta = true
+		if do, ok := t.o.(fs.Metadataer); ok {
+			metadata, err := do.Metadata(t.ctx)
+			if err != nil {
+				fs.Debugf(t.o, "Failed to read metadata for filter expression: %v", err)
+			}
+			t.metadata = metadata
+		}
+	}
+	value, ok := t.metadata[key]
+	return value, ok
+}
+
+// Types of token
+const (
+	tokenEOF = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenOpen
+	tokenClose
+)
+
+// exprToken is a lexical token of a filter expression
+type exprToken struct {
+	kind int
+	text string
+	pos  int
+}
+
+// operators in the order they should be matched
+var exprOps = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "=", "<", ">", "~", "!"}
+
+// isCompareOp returns true if op compares a field with a value
+func isCompareOp(op string) bool {
+	switch op {
+	case "&&", "||", "!":
+		return false
+	}
+	return true
+}
+
+// isExprWordRune returns true if c can be part of an unquoted word
+func isExprWordRune(c rune) bool {
+	return !unicode.IsSpace(c) && !strings.ContainsRune(`()"'=!<>~&|`, c)
+}
+
+// isExprKeyword returns true if word is AND, OR or NOT in any case
+func isExprKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "AND", "OR", "NOT":
+		return true
+	}
+	return false
+}
+
+// lexExpr splits expr into tokens
+func lexExpr(expr string) (tokens []exprToken, err error) {
+	i := 0
+	for i < len(expr) {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, exprToken{kind: tokenOpen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, exprToken{kind: tokenClose, text: ")", pos: i})
+			i++
+		case c == '"':
+			// a Go style double quoted string
+			end := i + 1
+			for ; end < len(expr) && expr[end] != '"'; end++ {
+				if expr[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(expr) {
+				return nil, errors.Errorf("unterminated string at position %d", i)
+			}
+			text, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "bad string at position %d", i)
+			}
+			tokens = append(tokens, exprToken{kind: tokenString, text: text, pos: i})
+			i = end + 1
+		case c == '\'':
+			// a single quoted string with no escapes
+			end := strings.IndexByte(expr[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, exprToken{kind: tokenString, text: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case !isExprWordRune(c):
+			op := ""
+			for _, try := range exprOps {
+				if strings.HasPrefix(expr[i:], try) {
+					op = try
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Errorf("unexpected %q at position %d", c, i)
+			}
+			tokens = append(tokens, exprToken{kind: tokenOp, text: op, pos: i})
+			i += len(op)
+		default:
+			end := i
+			for _, c := range expr[i:] {
+				if !isExprWordRune(c) {
+					break
+				}
+				end += len(string(c))
+			}
+			tokens = append(tokens, exprToken{kind: tokenWord, text: expr[i:end], pos: i})
+			i = end
+		}
+	}
+	tokens = append(tokens, exprToken{kind: tokenEOF, pos: len(expr)})
+	return tokens, nil
+}
+
+// exprParser is a recursive descent parser for filter expressions
+//
+//	expr    := and { ( OR | || ) and }
+//	and     := unary { ( AND | && ) unary }
+//	unary   := ( NOT | ! ) unary | primary
+//	primary := "(" expr ")" | field op value | glob
+type exprParser struct {
+	tokens     []exprToken
+	i          int
+	ignoreCase bool
+	now        time.Time
+}
+
+// parseExpr parses expr into a tree of exprNode
+func parseExpr(expr string, ignoreCase bool, now time.Time) (exprNode, error) {
+	tokens, err := lexExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, ignoreCase: ignoreCase, now: now}
+	if p.peek().kind == tokenEOF {
+		return nil, errors.New("empty expression")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errors.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+// peek returns the next token without consuming it
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.i]
+}
+
+// next consumes the next token
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.i]
+	if tok.kind != tokenEOF {
+		p.i++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the keyword or op passed in
+func (p *exprParser) accept(keyword, op string) bool {
+	tok := p.peek()
+	if (tok.kind == tokenWord && strings.EqualFold(tok.text, keyword)) || (tok.kind == tokenOp && tok.text == op) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND", "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &exprAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.accept("NOT", "!") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenOpen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if close := p.next(); close.kind != tokenClose {
+			return nil, errors.Errorf("expecting \")\" at position %d", close.pos)
+		}
+		return node, nil
+	case tokenWord, tokenString:
+		if tok.kind == tokenWord && isExprKeyword(tok.text) {
+			break
+		}
+		op := p.peek()
+		if tok.kind == tokenWord && op.kind == tokenOp && isCompareOp(op.text) {
+			p.next()
+			field, err := parseField(tok.text)
+			if err != nil {
+				return nil, errors.Wrapf(err, "at position %d", tok.pos)
+			}
+			value := p.next()
+			if value.kind != tokenWord && value.kind != tokenString {
+				return nil, errors.Errorf("expecting value after %q at position %d", op.text, value.pos)
+			}
+			return newCompare(field, op.text, value.text, p.ignoreCase, p.now)
+		}
+		// a bare glob matches the path like a filter rule
+		return newCompare(exprField{name: "path", kind: kindString}, "~", tok.text, p.ignoreCase, p.now)
+	}
+	if tok.kind == tokenEOF {
+		return nil, errors.New("unexpected end of expression")
+	}
+	return nil, errors.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fstest/mockobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpr(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		in   string
+		want string
+		err  string
+	}{
+		{in: "*.log", want: "path ~ *.log"},
+		{in: "size>1G", want: "size > 1G"},
+		{in: "SIZE == 100", want: "size = 100"},
+		{in: "name = 'a b'", want: `name = "a b"`},
+		{in: `path ~ "and"`, want: `path ~ "and"`},
+		{in: "hash.MD5 = abc", want: "hash.md5 = abc"},
+		{in: "meta.Owner != bob", want: "meta.Owner != bob"},
+		{in: "*.log AND size > 1G OR age > 30d AND NOT /keep/**", want: "((path ~ *.log AND size > 1G) OR (age > 30d AND NOT path ~ /keep/**))"},
+		{in: "!(a || b) && c", want: "(NOT (path ~ a OR path ~ b) AND path ~ c)"},
+		{in: "mime =~ ^image/", want: "mime =~ ^image/"},
+		{in: "", err: "empty expression"},
+		{in: "(a", err: `expecting ")"`},
+		{in: "a b", err: `unexpected "b"`},
+		{in: "a AND", err: "unexpected end of expression"},
+		{in: "potato = 1", err: `unknown field "potato"`},
+		{in: "hash.potato = 1", err: "unknown hash type"},
+		{in: "size ~ 1", err: `can't use "~" with "size"`},
+		{in: "size > big", err: `bad value for "size"`},
+		{in: "age > old", err: `bad value for "age"`},
+		{in: "depth > 1.5", err: `bad value for "depth"`},
+		{in: "name =~ (", err: `expecting value after "=~"`},
+		{in: "name =~ '('", err: `bad value for "name"`},
+		{in: `name = "abc`, err: "unterminated string"},
+		{in: "name = ", err: `expecting value after "="`},
+	} {
+		node, err := parseExpr(test.in, false, now)
+		if test.err != "" {
+			require.Error(t, err, test.in)
+			assert.Contains(t, err.Error(), test.err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.want, node.String(), test.in)
+
+		// The canonical form should parse to itself
+		again, err := parseExpr(node.String(), false, now)
+		require.NoError(t, err, test.in)
+		assert.Equal(t, node.String(), again.String(), test.in)
+	}
+}
+
+func TestFilterExprInclude(t *testing.T) {
+	f, err := NewFilter(nil)
+	require.NoError(t, err)
+	assert.True(t, f.InActive())
+	require.NoError(t, f.AddExpr("(*.log AND size > 1k) OR age > 30d AND NOT /keep/**"))
+	assert.False(t, f.InActive())
+
+	now := time.Now().Unix()
+	old := now - 40*24*3600
+	testInclude(t, f, []includeTest{
+		{"file.log", 1025, now, true},
+		{"dir/file.log", 1024, now, false},
+		{"file.txt", 10, old, true},
+		{"keep/file.txt", 10, old, false},
+		{"keep/file.log", 2048, old, true},
+		{"file.txt", 10, now, false},
+	})
+
+	require.NoError(t, f.AddExpr("depth <= 1 && name != big.log"))
+	testInclude(t, f, []includeTest{
+		{"file.log", 1025, now, true},
+		{"big.log", 1025, now, false},
+		{"keep/file.log", 2048, old, false},
+	})
+
+	assert.Error(t, f.AddExpr("size >"))
+}
+
+func TestFilterExprIgnoreCase(t *testing.T) {
+	opt := DefaultOpt
+	opt.FilterExpr = []string{"name = FILE.TXT OR dir ~ /Photos OR path =~ ^DOCS/"}
+	opt.IgnoreCase = true
+	f, err := NewFilter(&opt)
+	require.NoError(t, err)
+	testInclude(t, f, []includeTest{
+		{"file.txt", 0, 0, true},
+		{"photos/a.jpg", 0, 0, true},
+		{"docs/a.jpg", 0, 0, true},
+		{"music/a.mp3", 0, 0, false},
+	})
+}
+
+// metadataObject is an object with metadata
+type metadataObject struct {
+	fs.Object
+	metadata map[string]string
+}
+
+// Metadata returns the metadata of the object
+func (o metadataObject) Metadata(ctx context.Context) (map[string]string, error) {
+	return o.metadata, nil
+}
+
+func TestFilterExprIncludeObject(t *testing.T) {
+	ctx := context.Background()
+	f, err := NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, f.AddExpr(`hash.md5 = 5d41402abc4b2a76b9719d911017c592 OR meta.owner = bob OR mime = image/jpeg`))
+
+	hello := mockobject.New("hello.txt").WithContent([]byte("hello"), mockobject.SeekModeNone)
+	other := mockobject.New("other.txt").WithContent([]byte("other"), mockobject.SeekModeNone)
+	assert.True(t, f.IncludeObject(ctx, hello))
+	assert.False(t, f.IncludeObject(ctx, other))
+	assert.True(t, f.IncludeObject(ctx, metadataObject{Object: other, metadata: map[string]string{"owner": "bob"}}))
+	assert.False(t, f.IncludeObject(ctx, metadataObject{Object: other, metadata: map[string]string{"owner": "alice"}}))
+	assert.True(t, f.IncludeObject(ctx, mockobject.New("photo.jpg")))
+
+	// Without an object the hash and metadata aren't available
+	assert.False(t, f.Include("hello.txt", 5, time.Now()))
+	assert.True(t, f.Include("photo.jpg", 5, time.Now()))
+}
+
+func TestFilterExprFilesFrom(t *testing.T) {
+	opt := DefaultOpt
+	opt.FilterExpr = []string{"*.txt"}
+	opt.FilesFrom = []string{"-"}
+	_, err := NewFilter(&opt)
+	assert.Error(t, err)
+}
+
+func TestFilterExprDump(t *testing.T) {
+	f, err := NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, f.AddExpr("*.jpg or size<=10M"))
+	dump := f.DumpFilters()
+	assert.True(t, strings.HasSuffix(dump, "--- Filter expressions ---\n(path ~ *.jpg OR size <= 10M)"), dump)
+}
@@ -4,11 +4,14 @@ package filter
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +29,7 @@ var globalConfig = mustNewFilter(nil)
 type rule struct {
 	Include bool
 	Regexp  *regexp.Regexp
+	glob    string // the glob the rule was made from
 }
 
 // Match returns true if rule matches path
@@ -49,13 +53,14 @@ type rules struct {
 }
 
 // add adds a rule if it doesn't exist already
-func (rs *rules) add(Include bool, re *regexp.Regexp) {
+func (rs *rules) add(Include bool, re *regexp.Regexp, glob string) {
 	if rs.existing == nil {
 		rs.existing = make(map[string]struct{})
 	}
 	newRule := rule{
 		Include: Include,
 		Regexp:  re,
+		glob:    glob,
 	}
 	newRuleString := newRule.String()
 	if _, ok := rs.existing[newRuleString]; ok {
@@ -76,6 +81,39 @@ func (rs *rules) len() int {
 	return len(rs.rules)
 }
 
+// priorityRule gives the files matching Regexp, and the size limit
+// if set, a transfer priority
+type priorityRule struct {
+	Priority int
+	Regexp   *regexp.Regexp
+	Larger   int64 // files must be larger than this if >= 0
+	Smaller  int64 // files must be smaller than this if >= 0
+}
+
+// match returns true if the rule matches the file remote of size
+// bytes. Rules with a size limit don't match files of unknown size.
+func (rule *priorityRule) match(remote string, size int64) bool {
+	if rule.Larger >= 0 && (size < 0 || size <= rule.Larger) {
+		return false
+	}
+	if rule.Smaller >= 0 && (size < 0 || size >= rule.Smaller) {
+		return false
+	}
+	return rule.Regexp.MatchString(remote)
+}
+
+// String returns the rule as it would be written for --priority
+func (rule *priorityRule) String() string {
+	out := fmt.Sprintf("%d %s", rule.Priority, rule.Regexp.String())
+	if rule.Larger >= 0 {
+		out += " >" + fs.SizeSuffix(rule.Larger).String()
+	}
+	if rule.Smaller >= 0 {
+		out += " <" + fs.SizeSuffix(rule.Smaller).String()
+	}
+	return out
+}
+
 // FilesMap describes the map of files to transfer
 type FilesMap map[string]struct{}
 
@@ -96,6 +134,10 @@ type Opt struct {
 	MinSize        fs.SizeSuffix
 	MaxSize        fs.SizeSuffix
 	IgnoreCase     bool
+	PriorityRule   []string
+	FilterExpr     []string
+	IgnoreFileName []string
+	IgnoreFileGit  bool
 }
 
 // DefaultOpt is the default config for the filter
@@ -113,14 +155,21 @@ type Filter struct {
 	ModTimeTo   time.Time
 	fileRules   rules
 	dirRules    rules
-	files       FilesMap // files if filesFrom
-	dirs        FilesMap // dirs from filesFrom
+	priorities  []priorityRule // from --priority, first match wins
+	exprs       []exprNode     // from --filter-expr, all must match
+	ignores     *ignoreFiles   // from --ignore-file-name
+	ignoreSrc   fs.Fs          // if set read the ignore files from here for every Fs
+	files       FilesMap       // files if filesFrom
+	dirs        FilesMap       // dirs from filesFrom
+	restricted  bool           // files restrict the other filters rather than replacing them
 }
 
 // NewFilter parses the command line options and creates a Filter
 // object.  If opt is nil, then DefaultOpt will be used
 func NewFilter(opt *Opt) (f *Filter, err error) {
-	f = &Filter{}
+	f = &Filter{
+		ignores: &ignoreFiles{rules: map[string]ignoreRules{}},
+	}
 
 	// Make a copy of the options
 	if opt != nil {
@@ -195,6 +244,20 @@ func NewFilter(opt *Opt) (f *Filter, err error) {
 		}
 	}
 
+	for _, rule := range f.Opt.PriorityRule {
+		err = f.AddPriorityRule(rule)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, expr := range f.Opt.FilterExpr {
+		err = f.AddExpr(expr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	inActive := f.InActive()
 
 	for _, rule := range f.Opt.FilesFrom {
@@ -258,7 +321,7 @@ func (f *Filter) addDirGlobs(Include bool, glob string) error {
 		if err != nil {
 			return err
 		}
-		f.dirRules.add(Include, dirRe)
+		f.dirRules.add(Include, dirRe, dirGlob)
 	}
 	return nil
 }
@@ -279,7 +342,7 @@ func (f *Filter) Add(Include bool, glob string) error {
 		return err
 	}
 	if isFileRule {
-		f.fileRules.add(Include, re)
+		f.fileRules.add(Include, re, glob)
 		// If include rule work out what directories are needed to scan
 		// if exclude rule, we can't rule anything out
 		// Unless it is `*` which matches everything
@@ -292,7 +355,7 @@ func (f *Filter) Add(Include bool, glob string) error {
 		}
 	}
 	if isDirRule {
-		f.dirRules.add(Include, re)
+		f.dirRules.add(Include, re, glob)
 	}
 	return nil
 }
@@ -321,6 +384,109 @@ func (f *Filter) AddRule(rule string) error {
 	return errors.Errorf("malformed rule %q", rule)
 }
 
+// AddPriority gives the files matching glob the transfer priority
+// passed in. Files with a higher priority are transferred first.
+func (f *Filter) AddPriority(priority int, glob string) error {
+	return f.addPriority(priority, glob, -1, -1)
+}
+
+// addPriority adds a priority rule for the files matching glob which
+// are larger and smaller than the sizes given, if they are >= 0
+func (f *Filter) addPriority(priority int, glob string, larger, smaller int64) error {
+	re, err := globToRegexp(glob, f.Opt.IgnoreCase)
+	if err != nil {
+		return err
+	}
+	f.priorities = append(f.priorities, priorityRule{
+		Priority: priority,
+		Regexp:   re,
+		Larger:   larger,
+		Smaller:  smaller,
+	})
+	return nil
+}
+
+// AddPriorityRule adds a --priority rule of the form
+//
+//   PRIORITY GLOB [<SIZE|>SIZE]
+//
+// where PRIORITY is an integer and the optional size limits the rule
+// to files smaller or larger than SIZE, e.g. "10 *.db" or
+// "5 * <1M"
+func (f *Filter) AddPriorityRule(rule string) error {
+	i := strings.IndexByte(rule, ' ')
+	if i < 0 {
+		return errors.Errorf("malformed priority rule %q: need PRIORITY GLOB", rule)
+	}
+	priority, err := strconv.Atoi(rule[:i])
+	if err != nil {
+		return errors.Errorf("malformed priority rule %q: bad priority %q", rule, rule[:i])
+	}
+	glob := strings.TrimLeft(rule[i+1:], " ")
+	larger, smaller := int64(-1), int64(-1)
+	if j := strings.LastIndexByte(glob, ' '); j >= 0 && j+1 < len(glob) && (glob[j+1] == '<' || glob[j+1] == '>') {
+		var size fs.SizeSuffix
+		if err := size.Set(glob[j+2:]); err != nil {
+			return errors.Errorf("malformed priority rule %q: bad size %q", rule, glob[j+2:])
+		}
+		if glob[j+1] == '<' {
+			smaller = int64(size)
+		} else {
+			larger = int64(size)
+		}
+		glob = strings.TrimRight(glob[:j], " ")
+	}
+	return f.addPriority(priority, glob, larger, smaller)
+}
+
+// priority returns the priority set by the first rule remote of size
+// bytes matches
+func (f *Filter) priority(remote string, size int64) int {
+	for i := range f.priorities {
+		rule := &f.priorities[i]
+		if rule.match(remote, size) {
+			return rule.Priority
+		}
+	}
+	return 0
+}
+
+// Priority returns the transfer priority of remote as set by the
+// first --priority rule it matches, or 0 if it matches none.
+//
+// The size of remote isn't known so rules with a size limit don't
+// match - use PriorityObject if the size is known.
+func (f *Filter) Priority(remote string) int {
+	return f.priority(remote, -1)
+}
+
+// PriorityObject returns the transfer priority of o as set by the
+// first --priority rule it matches, or 0 if it matches none.
+func (f *Filter) PriorityObject(o fs.ObjectInfo) int {
+	return f.priority(o.Remote(), o.Size())
+}
+
+// AddExpr adds a boolean filter expression which files must match
+// to be included, e.g. "(*.log AND size > 1G) OR age > 30d"
+func (f *Filter) AddExpr(expr string) error {
+	node, err := parseExpr(expr, f.Opt.IgnoreCase, time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "bad filter expression %q", expr)
+	}
+	f.exprs = append(f.exprs, node)
+	return nil
+}
+
+// includeExprs returns whether t matches all the filter expressions
+func (f *Filter) includeExprs(t *exprTarget) bool {
+	for _, node := range f.exprs {
+		if !node.eval(t) {
+			return false
+		}
+	}
+	return true
+}
+
 // initAddFile creates f.files and f.dirs
 func (f *Filter) initAddFile() {
 	if f.files == nil {
@@ -355,6 +521,29 @@ func (f *Filter) Files() FilesMap {
 	return f.files
 }
 
+// Restrict returns a copy of the filter which only includes the
+// remotes given, as if they were given with --files-from, and only
+// if the rest of the filter includes them too.
+//
+// This can't be used if --files-from is in use.
+func (f *Filter) Restrict(remotes []string) (*Filter, error) {
+	if f.HaveFilesFrom() {
+		return nil, errors.New("can't restrict a filter using --files-from")
+	}
+	newF := *f
+	newF.files = nil
+	newF.dirs = nil
+	newF.restricted = true
+	newF.initAddFile()
+	for _, remote := range remotes {
+		err := newF.AddFile(remote)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &newF, nil
+}
+
 // Clear clears all the filter rules
 func (f *Filter) Clear() {
 	f.fileRules.clear()
@@ -370,6 +559,8 @@ func (f *Filter) InActive() bool {
 		f.Opt.MaxSize < 0 &&
 		f.fileRules.len() == 0 &&
 		f.dirRules.len() == 0 &&
+		len(f.exprs) == 0 &&
+		len(f.Opt.IgnoreFileName) == 0 &&
 		len(f.Opt.ExcludeFile) == 0)
 }
 
@@ -415,10 +606,21 @@ func (f *Filter) IncludeDirectory(ctx context.Context, fs fs.Fs) func(string) (b
 			return false, nil
 		}
 
+		// then the ignore files
+		ignored, err := f.Ignored(ctx, fs, remote, true)
+		if err != nil {
+			return false, err
+		}
+		if ignored {
+			return false, nil
+		}
+
 		// filesFrom takes precedence
 		if f.files != nil {
 			_, include := f.dirs[remote]
-			return include, nil
+			if !include || !f.restricted {
+				return include, nil
+			}
 		}
 		remote += "/"
 		for _, rule := range f.dirRules.rules {
@@ -450,10 +652,26 @@ func (f *Filter) DirContainsExcludeFile(ctx context.Context, fremote fs.Fs, remo
 // Include returns whether this object should be included into the
 // sync or not
 func (f *Filter) Include(remote string, size int64, modTime time.Time) bool {
+	if !f.include(remote, size, modTime) {
+		return false
+	}
+	return f.includeExprs(&exprTarget{
+		ctx:         context.Background(),
+		remote:      remote,
+		size:        size,
+		modTime:     modTime,
+		haveModTime: true,
+	})
+}
+
+// include checks everything but the filter expressions
+func (f *Filter) include(remote string, size int64, modTime time.Time) bool {
 	// filesFrom takes precedence
 	if f.files != nil {
 		_, include := f.files[remote]
-		return include
+		if !include || !f.restricted {
+			return include
+		}
 	}
 	if !f.ModTimeFrom.IsZero() && modTime.Before(f.ModTimeFrom) {
 		return false
@@ -476,13 +694,35 @@ func (f *Filter) Include(remote string, size int64, modTime time.Time) bool {
 func (f *Filter) IncludeObject(ctx context.Context, o fs.Object) bool {
 	var modTime time.Time
 
-	if !f.ModTimeFrom.IsZero() || !f.ModTimeTo.IsZero() {
+	haveModTime := !f.ModTimeFrom.IsZero() || !f.ModTimeTo.IsZero()
+	if haveModTime {
 		modTime = o.ModTime(ctx)
 	} else {
 		modTime = time.Unix(0, 0)
 	}
 
-	return f.Include(o.Remote(), o.Size(), modTime)
+	if !f.include(o.Remote(), o.Size(), modTime) {
+		return false
+	}
+	if fremote, ok := o.Fs().(fs.Fs); ok && f.UsesIgnoreFiles() {
+		ignored, err := f.Ignored(ctx, fremote, o.Remote(), false)
+		if err != nil {
+			fs.Errorf(o, "Excluding as failed to read ignore files: %v", err)
+			return false
+		}
+		if ignored {
+			return false
+		}
+	}
+	// The filter expressions read anything else they need from o
+	return f.includeExprs(&exprTarget{
+		ctx:         ctx,
+		remote:      o.Remote(),
+		size:        o.Size(),
+		o:           o,
+		modTime:     modTime,
+		haveModTime: haveModTime,
+	})
 }
 
 // forEachLine calls fn on every line in the file pointed to by path
@@ -533,9 +773,49 @@ func (f *Filter) DumpFilters() string {
 	for _, dirRule := range f.dirRules.rules {
 		rules = append(rules, dirRule.String())
 	}
+	if f.UsesIgnoreFiles() {
+		rules = append(rules, "--- Ignore files ---")
+		rules = append(rules, f.Opt.IgnoreFileName...)
+	}
+	if len(f.exprs) > 0 {
+		rules = append(rules, "--- Filter expressions ---")
+		for _, node := range f.exprs {
+			rules = append(rules, node.String())
+		}
+	}
+	if len(f.priorities) > 0 {
+		rules = append(rules, "--- Priority rules ---")
+		for i := range f.priorities {
+			rules = append(rules, f.priorities[i].String())
+		}
+	}
 	return strings.Join(rules, "\n")
 }
 
+// Fingerprint returns a short hash of the rules which choose the
+// files the filter includes. It stays the same from run to run with
+// the same flags, so --min-age and --max-age are hashed as durations
+// rather than as times, and --priority is left out as it only changes
+// the order.
+func (f *Filter) Fingerprint() string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "min-age %v\nmax-age %v\nmin-size %v\nmax-size %v\n", f.Opt.MinAge, f.Opt.MaxAge, f.Opt.MinSize, f.Opt.MaxSize)
+	_, _ = fmt.Fprintf(h, "delete-excluded %v\nignore-case %v\nignore-git %v\n", f.Opt.DeleteExcluded, f.Opt.IgnoreCase, f.Opt.IgnoreFileGit)
+	for _, rule := range f.fileRules.rules {
+		_, _ = fmt.Fprintf(h, "file %s\n", rule.String())
+	}
+	for _, rule := range f.dirRules.rules {
+		_, _ = fmt.Fprintf(h, "dir %s\n", rule.String())
+	}
+	for _, name := range f.Opt.IgnoreFileName {
+		_, _ = fmt.Fprintf(h, "ignore %s\n", name)
+	}
+	for _, node := range f.exprs {
+		_, _ = fmt.Fprintf(h, "expr %s\n", node.String())
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
 // HaveFilesFrom returns true if --files-from has been supplied
 func (f *Filter) HaveFilesFrom() bool {
 	return f.files != nil
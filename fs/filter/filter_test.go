@@ -769,6 +769,73 @@ func TestNewFilterUsesDirectoryFilters(t *testing.T) {
 	}
 }
 
+func TestPriority(t *testing.T) {
+	f, err := NewFilter(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, f.Priority("file.db"))
+
+	require.NoError(t, f.AddPriorityRule("10 *.db"))
+	require.NoError(t, f.AddPriorityRule("-1  /logs/**"))
+	require.NoError(t, f.AddPriorityRule("5 *.db"))
+	assert.Error(t, f.AddPriorityRule("*.db"))
+	assert.Error(t, f.AddPriorityRule("high *.db"))
+	assert.Error(t, f.AddPriorityRule("1 {a"))
+
+	assert.Equal(t, 10, f.Priority("file.db"))
+	assert.Equal(t, 10, f.Priority("dir/file.db"))
+	assert.Equal(t, -1, f.Priority("logs/file.txt"))
+	assert.Equal(t, 10, f.Priority("logs/file.db"))
+	assert.Equal(t, 0, f.Priority("file.txt"))
+
+	// Priority rules don't filter
+	assert.True(t, f.InActive())
+
+	// Rules can be limited by size
+	f, err = NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, f.AddPriorityRule("10 *.txt <1k"))
+	require.NoError(t, f.AddPriorityRule("-5 * >1M"))
+	require.NoError(t, f.AddPriorityRule("1 my file.tmp"))
+	assert.Error(t, f.AddPriorityRule("1 * <potato"))
+	small := mockobject.New("small.txt").WithContent(make([]byte, 10), mockobject.SeekModeNone)
+	assert.Equal(t, 10, f.PriorityObject(small))
+	medium := mockobject.New("medium.txt").WithContent(make([]byte, 2048), mockobject.SeekModeNone)
+	assert.Equal(t, 0, f.PriorityObject(medium))
+	big := mockobject.New("big.txt").WithContent(make([]byte, 2*1024*1024), mockobject.SeekModeNone)
+	assert.Equal(t, -5, f.PriorityObject(big))
+	assert.Equal(t, 0, f.Priority("small.txt"))
+	assert.Equal(t, 1, f.Priority("my file.tmp"))
+	assert.Contains(t, f.DumpFilters(), "--- Priority rules ---\n10 (^|/)[^/]*\\.txt$ <1Ki\n-5 (^|/)[^/]*$ >1Mi\n")
+}
+
+func TestFingerprint(t *testing.T) {
+	newFilter := func(opt Opt, rules ...string) *Filter {
+		f, err := NewFilter(&opt)
+		require.NoError(t, err)
+		for _, rule := range rules {
+			require.NoError(t, f.AddRule(rule))
+		}
+		return f
+	}
+	empty := newFilter(DefaultOpt).Fingerprint()
+	assert.Equal(t, 16, len(empty))
+
+	// The same flags give the same fingerprint at different times
+	opt := DefaultOpt
+	opt.MaxAge = fs.Duration(time.Hour)
+	aged := newFilter(opt).Fingerprint()
+	assert.NotEqual(t, empty, aged)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, aged, newFilter(opt).Fingerprint())
+
+	// Rules change it, priorities don't
+	assert.NotEqual(t, empty, newFilter(DefaultOpt, "- *.tmp").Fingerprint())
+	assert.NotEqual(t, newFilter(DefaultOpt, "- *.tmp").Fingerprint(), newFilter(DefaultOpt, "+ *.tmp").Fingerprint())
+	f := newFilter(DefaultOpt)
+	require.NoError(t, f.AddPriorityRule("10 *.db"))
+	assert.Equal(t, empty, f.Fingerprint())
+}
+
 func TestGetConfig(t *testing.T) {
 	ctx := context.Background()
 
@@ -167,3 +167,73 @@ func globToDirGlobs(glob string) (out []string) {
 
 	return out
 }
+
+// gitGlobToRegexp converts a gitignore style pattern to a regexp
+// matching paths relative to the directory of the ignore file.
+//
+// Unlike the rsync style globs, a pattern with a "/" at the start or
+// in the middle is anchored to the directory, "**" is only special
+// as a whole path segment and "{" "}" aren't special.
+func gitGlobToRegexp(glob string, ignoreCase bool) (*regexp.Regexp, error) {
+	var re bytes.Buffer
+	if ignoreCase {
+		_, _ = re.WriteString("(?i)")
+	}
+	if strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+		_, _ = re.WriteRune('^')
+	} else {
+		_, _ = re.WriteString("(^|/)")
+	}
+	startOfSegment := true
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case startOfSegment && strings.HasPrefix(glob[i:], "**/"):
+			_, _ = re.WriteString(`(.*/)?`)
+			i += 2
+			continue
+		case startOfSegment && glob[i:] == "**":
+			_, _ = re.WriteString(`.*`)
+			i++
+			continue
+		case c == '*':
+			_, _ = re.WriteString(`[^/]*`)
+		case c == '?':
+			_, _ = re.WriteString(`[^/]`)
+		case c == '\\' && i+1 < len(glob):
+			i++
+			_, _ = re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			// find the end of the class allowing "]" first
+			j := i + 1
+			if j < len(glob) && (glob[j] == '!' || glob[j] == '^') {
+				j++
+			}
+			if j < len(glob) && glob[j] == ']' {
+				j++
+			}
+			for j < len(glob) && glob[j] != ']' {
+				j++
+			}
+			if j >= len(glob) {
+				return nil, errors.Errorf("mismatched '[' and ']' in glob %q", glob)
+			}
+			class := glob[i+1 : j]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			_, _ = re.WriteString("[" + class + "]")
+			i = j
+		default:
+			_, _ = re.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+		startOfSegment = c == '/'
+	}
+	_, _ = re.WriteRune('$')
+	result, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad glob pattern %q (regexp %q)", glob, re.String())
+	}
+	return result, nil
+}
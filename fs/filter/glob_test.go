@@ -60,6 +60,38 @@ func TestGlobToRegexp(t *testing.T) {
 	}
 }
 
+func TestGitGlobToRegexp(t *testing.T) {
+	for _, test := range []struct {
+		in    string
+		want  string
+		error string
+	}{
+		{`potato`, `(^|/)potato$`, ``},
+		{`/potato`, `^potato$`, ``},
+		{`a/potato`, `^a/potato$`, ``},
+		{`*.jpg`, `(^|/)[^/]*\.jpg$`, ``},
+		{`a{b,c}`, `(^|/)a\{b,c\}$`, ``},
+		{`**/potato`, `^(.*/)?potato$`, ``},
+		{`a/**`, `^a/.*$`, ``},
+		{`a/**/b`, `^a/(.*/)?b$`, ``},
+		{`a**b`, `(^|/)a[^/]*[^/]*b$`, ``},
+		{`potat[!a-z]`, `(^|/)potat[^a-z]$`, ``},
+		{`potat[]x]`, `(^|/)potat[]x]$`, ``},
+		{`\!important`, `(^|/)!important$`, ``},
+		{`a\*b`, `(^|/)a\*b$`, ``},
+		{`ab[c`, ``, `mismatched '[' and ']'`},
+	} {
+		gotRe, err := gitGlobToRegexp(test.in, false)
+		if test.error == "" {
+			require.NoError(t, err, test.in)
+			assert.Equal(t, test.want, gotRe.String(), test.in)
+		} else {
+			require.Error(t, err, test.in)
+			assert.Contains(t, err.Error(), test.error, test.in)
+		}
+	}
+}
+
 func TestGlobToDirGlobs(t *testing.T) {
 	for _, test := range []struct {
 		in   string
@@ -0,0 +1,262 @@
+// Per directory ignore files for --ignore-file-name
+
+package filter
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+)
+
+// ignoreRule is one line of an ignore file
+type ignoreRule struct {
+	negate  bool // ! - re-include a path excluded by an earlier rule
+	dirOnly bool // trailing / - only match directories
+	re      *regexp.Regexp
+}
+
+// ignoreRules are the rules read from the ignore files in one
+// directory
+type ignoreRules []ignoreRule
+
+// parseIgnoreFile reads the rules from an ignore file.
+//
+// If git is set the patterns are read with gitignore syntax,
+// otherwise they are rclone filter patterns.
+func parseIgnoreFile(in io.Reader, git bool, ignoreCase bool) (rules ignoreRules, err error) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if git {
+			// Trailing spaces are ignored unless escaped
+			trimmed := strings.TrimRight(line, " ")
+			if strings.HasSuffix(trimmed, `\`) && len(trimmed) < len(line) {
+				trimmed += " "
+			}
+			line = trimmed
+		} else {
+			line = strings.TrimSpace(line)
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var rule ignoreRule
+		if line[0] == '!' {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if git {
+			rule.re, err = gitGlobToRegexp(line, ignoreCase)
+		} else {
+			rule.re, err = globToRegexp(line, ignoreCase)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// match returns whether the last rule matching remote excludes it,
+// and whether any rule matched at all
+func (rules ignoreRules) match(remote string, isDir bool) (ignored, matched bool) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		rule := &rules[i]
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(remote) {
+			return !rule.negate, true
+		}
+	}
+	return false, false
+}
+
+// ignoreFiles caches the rules read from the ignore files by Fs and
+// directory
+type ignoreFiles struct {
+	mu    sync.Mutex
+	rules map[string]ignoreRules // nil if the directory has no ignore files
+}
+
+// ignoreKey returns the cache key for dir in fremote
+func ignoreKey(fremote fs.Fs, dir string) string {
+	return fs.ConfigString(fremote) + "\x00" + dir
+}
+
+// UsesIgnoreFiles returns true if the filter reads ignore files
+// with --ignore-file-name
+func (f *Filter) UsesIgnoreFiles() bool {
+	return len(f.Opt.IgnoreFileName) > 0
+}
+
+// IgnoreFilesFrom returns a context whose filter reads the ignore
+// files from fsrc whichever Fs is being listed, finding them by the
+// path of the directory relative to the root.
+//
+// This is used when marching a source and destination so the files
+// the source's ignore files exclude are excluded on the destination
+// too, rather than being deleted there by a sync.
+func IgnoreFilesFrom(ctx context.Context, fsrc fs.Fs) context.Context {
+	if !GetConfig(ctx).UsesIgnoreFiles() {
+		return ctx
+	}
+	newCtx, fi := AddConfig(ctx)
+	fi.ignoreSrc = fsrc
+	return newCtx
+}
+
+// ignoreFs returns the Fs to read the ignore files for fremote from
+func (f *Filter) ignoreFs(fremote fs.Fs) fs.Fs {
+	if f.ignoreSrc != nil {
+		return f.ignoreSrc
+	}
+	return fremote
+}
+
+// readIgnoreFile reads the rules from the ignore file o
+func (f *Filter) readIgnoreFile(ctx context.Context, o fs.Object) (rules ignoreRules, err error) {
+	in, err := o.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open ignore file %q", o.Remote())
+	}
+	defer fs.CheckClose(in, &err)
+	rules, err = parseIgnoreFile(in, f.Opt.IgnoreFileGit, f.Opt.IgnoreCase)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ignore file %q", o.Remote())
+	}
+	return rules, nil
+}
+
+// ReadIgnoreFiles reads the ignore files from the listing of dir so
+// the rules in them apply to the entries of dir and below.
+//
+// It is called for each directory listed so changes to the ignore
+// files are noticed when a directory is listed again. If the ignore
+// files are read from another Fs with IgnoreFilesFrom then those in
+// fremote are not used.
+func (f *Filter) ReadIgnoreFiles(ctx context.Context, fremote fs.Fs, dir string, entries fs.DirEntries) error {
+	if !f.UsesIgnoreFiles() {
+		return nil
+	}
+	if fs.ConfigString(f.ignoreFs(fremote)) != fs.ConfigString(fremote) {
+		return nil
+	}
+	var rules ignoreRules
+	for _, name := range f.Opt.IgnoreFileName {
+		for _, entry := range entries {
+			o, ok := entry.(fs.Object)
+			if !ok || path.Base(o.Remote()) != name {
+				continue
+			}
+			fileRules, err := f.readIgnoreFile(ctx, o)
+			if err != nil {
+				return err
+			}
+			fs.Debugf(o, "Read %d rules from ignore file", len(fileRules))
+			rules = append(rules, fileRules...)
+		}
+	}
+	f.ignores.mu.Lock()
+	f.ignores.rules[ignoreKey(fremote, dir)] = rules
+	f.ignores.mu.Unlock()
+	return nil
+}
+
+// ignoreRulesFor returns the rules from the ignore files in dir,
+// reading them if dir hasn't been listed
+func (f *Filter) ignoreRulesFor(ctx context.Context, fremote fs.Fs, dir string) (ignoreRules, error) {
+	key := ignoreKey(fremote, dir)
+	f.ignores.mu.Lock()
+	rules, found := f.ignores.rules[key]
+	f.ignores.mu.Unlock()
+	if found {
+		return rules, nil
+	}
+	rules = nil
+	for _, name := range f.Opt.IgnoreFileName {
+		o, err := fremote.NewObject(ctx, path.Join(dir, name))
+		if err == fs.ErrorObjectNotFound || err == fs.ErrorNotAFile || err == fs.ErrorPermissionDenied || err == fs.ErrorDirNotFound {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to find ignore file %q", path.Join(dir, name))
+		}
+		fileRules, err := f.readIgnoreFile(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+	f.ignores.mu.Lock()
+	f.ignores.rules[key] = rules
+	f.ignores.mu.Unlock()
+	return rules, nil
+}
+
+// Ignored returns true if remote, or any directory above it, is
+// excluded by the ignore files in the directories above it up to the
+// root of fremote, or of the Fs set with IgnoreFilesFrom.
+//
+// The rules in a directory take priority over those above it and
+// later rules take priority over earlier ones. As with git a file
+// can't be re-included if a directory above it is excluded. The
+// parent directories are checked too as files found without listing,
+// eg with --no-traverse or copyto, don't go through the walk which
+// skips ignored directories.
+func (f *Filter) Ignored(ctx context.Context, fremote fs.Fs, remote string, isDir bool) (bool, error) {
+	if !f.UsesIgnoreFiles() {
+		return false, nil
+	}
+	fremote = f.ignoreFs(fremote)
+	remote = strings.Trim(remote, "/")
+	if remote == "" {
+		return false, nil
+	}
+	parts := strings.Split(remote, "/")
+	for i := 1; i < len(parts); i++ {
+		ignored, err := f.ignoredIn(ctx, fremote, path.Join(parts[:i]...), true)
+		if err != nil || ignored {
+			return ignored, err
+		}
+	}
+	return f.ignoredIn(ctx, fremote, remote, isDir)
+}
+
+// ignoredIn returns true if remote is excluded by the ignore files in
+// the directories above it, not checking the directories themselves
+func (f *Filter) ignoredIn(ctx context.Context, fremote fs.Fs, remote string, isDir bool) (bool, error) {
+	dir := remote
+	for dir != "" {
+		dir = path.Dir(dir)
+		if dir == "." {
+			dir = ""
+		}
+		rules, err := f.ignoreRulesFor(ctx, fremote, dir)
+		if err != nil {
+			return false, err
+		}
+		relative := remote
+		if dir != "" {
+			relative = remote[len(dir)+1:]
+		}
+		if ignored, matched := rules.match(relative, isDir); matched {
+			return ignored, nil
+		}
+	}
+	return false, nil
+}
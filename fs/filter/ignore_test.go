@@ -0,0 +1,75 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	const ignoreFile = `
+# comment
+*.tmp
+!keep.tmp
+build/
+/top.txt
+docs/*.pdf
+`
+	for _, test := range []struct {
+		remote string
+		isDir  bool
+		rclone bool // ignored with rclone syntax
+		git    bool // ignored with gitignore syntax
+	}{
+		{"a.tmp", false, true, true},
+		{"sub/a.tmp", false, true, true},
+		{"keep.tmp", false, false, false},
+		{"build", true, true, true},
+		{"build", false, false, false},
+		{"sub/build", true, true, true},
+		{"top.txt", false, true, true},
+		{"sub/top.txt", false, false, false},
+		{"docs/a.pdf", false, true, true},
+		{"sub/docs/a.pdf", false, true, false},
+		{"a.txt", false, false, false},
+	} {
+		for _, git := range []bool{false, true} {
+			rules, err := parseIgnoreFile(strings.NewReader(ignoreFile), git, false)
+			require.NoError(t, err)
+			require.Len(t, rules, 5)
+			want := test.rclone
+			if git {
+				want = test.git
+			}
+			got, _ := rules.match(test.remote, test.isDir)
+			assert.Equal(t, want, got, "%s git=%v", test.remote, git)
+		}
+	}
+
+	// Trailing spaces are significant only when escaped with git
+	rules, err := parseIgnoreFile(strings.NewReader("a  \nb\\  \n"), true, false)
+	require.NoError(t, err)
+	got, _ := rules.match("a", false)
+	assert.True(t, got)
+	got, _ = rules.match("b ", false)
+	assert.True(t, got)
+
+	_, err = parseIgnoreFile(strings.NewReader("ab{c\n"), false, false)
+	assert.Error(t, err)
+}
+
+func TestIgnoreFilesInActive(t *testing.T) {
+	opt := DefaultOpt
+	opt.IgnoreFileName = []string{".rcloneignore"}
+	f, err := NewFilter(&opt)
+	require.NoError(t, err)
+	assert.False(t, f.InActive())
+	assert.True(t, f.UsesIgnoreFiles())
+	assert.Contains(t, f.DumpFilters(), "--- Ignore files ---\n.rcloneignore")
+
+	opt.FilesFrom = []string{"-"}
+	_, err = NewFilter(&opt)
+	assert.Error(t, err)
+}
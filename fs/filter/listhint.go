@@ -0,0 +1,259 @@
+// Hints for backends which can list less using the filters
+
+package filter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+)
+
+const (
+	// most prefixes to put in a hint - with more the backend
+	// would likely be slower listing each of them than listing
+	// everything
+	maxListHintPrefixes = 32
+	// most --files-from files to make prefixes from as the prefixes
+	// are found again for each directory listed
+	maxListHintFiles = 1000
+)
+
+// globPrefixes returns the literal parts at the start of the paths an
+// anchored glob can match, one for each of its {a,b} alternatives, or
+// false if the glob isn't anchored.
+func globPrefixes(glob string) ([]string, bool) {
+	if !strings.HasPrefix(glob, "/") {
+		return nil, false
+	}
+	return literalPrefixes(glob[1:]), true
+}
+
+// literalPrefixes returns the literal parts at the start of the paths
+// glob can match. If the alternatives make more than
+// maxListHintPrefixes then just the part before them is returned.
+func literalPrefixes(glob string) []string {
+	var prefix []byte
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '\\':
+			// \ quotes punctuation, but \d and the like are
+			// regexp classes
+			if i+1 >= len(glob) || isAlphaNumeric(glob[i+1]) {
+				return []string{string(prefix)}
+			}
+			i++
+			prefix = append(prefix, glob[i])
+		case '*', '?', '[':
+			return []string{string(prefix)}
+		case '{':
+			alternatives, rest, ok := splitAlternatives(glob[i+1:])
+			if !ok {
+				return []string{string(prefix)}
+			}
+			var out []string
+			for _, alternative := range alternatives {
+				for _, p := range literalPrefixes(alternative + rest) {
+					out = append(out, string(prefix)+p)
+				}
+				if len(out) > maxListHintPrefixes {
+					return []string{string(prefix)}
+				}
+			}
+			return out
+		default:
+			prefix = append(prefix, c)
+		}
+	}
+	return []string{string(prefix)}
+}
+
+// isAlphaNumeric returns true if c is an ASCII letter or digit
+func isAlphaNumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// splitAlternatives splits the alternatives of a glob just after a {
+// returning them and the glob after the }, or false if there is no }.
+func splitAlternatives(glob string) (alternatives []string, rest string, ok bool) {
+	start, inBrackets := 0, 0
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			i++
+		case '[':
+			inBrackets++
+		case ']':
+			if inBrackets > 0 {
+				inBrackets--
+			}
+		case ',':
+			if inBrackets == 0 {
+				alternatives = append(alternatives, glob[start:i])
+				start = i + 1
+			}
+		case '}':
+			if inBrackets == 0 {
+				return append(alternatives, glob[start:i]), glob[i+1:], true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// includePrefixes returns the prefixes of the paths which can be
+// included, or nil if any path might be.
+//
+// This is only possible if the rules end by excluding everything and
+// all the include rules are anchored. Exclude rules only exclude
+// more so they can be ignored.
+func (f *Filter) includePrefixes() (prefixes []string) {
+	if f.files != nil {
+		if len(f.files) > maxListHintFiles {
+			return nil
+		}
+		for file := range f.files {
+			prefixes = append(prefixes, file)
+		}
+		return prefixes
+	}
+	n := f.fileRules.len()
+	if n == 0 {
+		return nil
+	}
+	last := f.fileRules.rules[n-1]
+	if last.Include || (last.glob != "/**" && last.glob != "**") {
+		return nil
+	}
+	for _, rule := range f.fileRules.rules[:n-1] {
+		if !rule.Include {
+			continue
+		}
+		rulePrefixes, anchored := globPrefixes(rule.glob)
+		if !anchored {
+			return nil
+		}
+		prefixes = append(prefixes, rulePrefixes...)
+	}
+	return prefixes
+}
+
+// exprStartAfter returns the path that all included paths must sort
+// after according to the top level "path >" and "path >=" terms of
+// the filter expressions, or "" if there isn't one.
+func (f *Filter) exprStartAfter() (startAfter string) {
+	var find func(node exprNode)
+	find = func(node exprNode) {
+		switch x := node.(type) {
+		case *exprAnd:
+			find(x.left)
+			find(x.right)
+		case *exprCompare:
+			if x.field.name != "path" || x.ignoreCase {
+				return
+			}
+			value := x.value
+			switch x.op {
+			case ">":
+			case ">=":
+				// a proper prefix of value sorts before it
+				if value == "" {
+					return
+				}
+				value = value[:len(value)-1]
+			default:
+				return
+			}
+			if value > startAfter {
+				startAfter = value
+			}
+		}
+	}
+	for _, node := range f.exprs {
+		find(node)
+	}
+	return startAfter
+}
+
+// relativePrefixes returns the prefixes relative to dir, or nil if
+// anything in dir might be needed or there are more than
+// maxListHintPrefixes of them.
+//
+// If not recursive only the first path segment of each prefix is
+// kept as only the entries of dir are being listed.
+func relativePrefixes(prefixes []string, dir string, recursive bool) (out []string) {
+	if dir != "" {
+		dir += "/"
+	}
+	for _, prefix := range prefixes {
+		switch {
+		case strings.HasPrefix(prefix, dir):
+			prefix = prefix[len(dir):]
+			if i := strings.IndexRune(prefix, '/'); i >= 0 && !recursive {
+				prefix = prefix[:i]
+			}
+			if prefix == "" {
+				return nil
+			}
+			out = append(out, prefix)
+		case strings.HasPrefix(dir, prefix):
+			return nil
+		}
+		// otherwise nothing in dir can match prefix
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	// Remove duplicates and prefixes of which another is a prefix
+	sort.Strings(out)
+	kept := out[:1]
+	for _, prefix := range out[1:] {
+		if !strings.HasPrefix(prefix, kept[len(kept)-1]) {
+			kept = append(kept, prefix)
+		}
+	}
+	if len(kept) > maxListHintPrefixes {
+		return nil
+	}
+	return kept
+}
+
+// relativeStartAfter returns startAfter relative to dir, or "" if
+// anything in dir might be needed.
+func relativeStartAfter(startAfter string, dir string, recursive bool) string {
+	if dir != "" {
+		if !strings.HasPrefix(startAfter, dir+"/") {
+			return ""
+		}
+		startAfter = startAfter[len(dir)+1:]
+	}
+	if i := strings.IndexRune(startAfter, '/'); i > 0 && !recursive {
+		// The directory startAfter is in is needed so start
+		// before its name
+		startAfter = startAfter[:i-1]
+	}
+	return startAfter
+}
+
+// ListHint returns a hint describing the entries of dir which can
+// pass the filter, for backends which can use it to list less, or
+// nil if there is nothing to hint.
+//
+// If recursive is set the hint is for a recursive listing of dir.
+func (f *Filter) ListHint(dir string, recursive bool) *fs.ListHint {
+	hint := &fs.ListHint{
+		ModifiedSince:  f.ModTimeFrom,
+		ModifiedBefore: f.ModTimeTo,
+	}
+	// The names on the remote may differ in case from the
+	// filters and the ignore files must be listed to be read
+	if !f.Opt.IgnoreCase && !f.UsesIgnoreFiles() {
+		hint.Prefixes = relativePrefixes(f.includePrefixes(), dir, recursive)
+		hint.StartAfter = relativeStartAfter(f.exprStartAfter(), dir, recursive)
+	}
+	if hint.IsEmpty() {
+		return nil
+	}
+	return hint
+}
@@ -0,0 +1,172 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobPrefixes(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		want     []string
+		anchored bool
+	}{
+		{"/photos/**", []string{"photos/"}, true},
+		{"/docs/*.txt", []string{"docs/"}, true},
+		{"/a/b{c,d}", []string{"a/bc", "a/bd"}, true},
+		{"/{2020,2021}/{jan,feb*}/**", []string{"2020/jan/", "2020/feb", "2021/jan/", "2021/feb"}, true},
+		{"/{a[,]b,c}", []string{"a", "c"}, true},
+		{"/a\\*b/**", []string{"a*b/"}, true},
+		{"/a\\d", []string{"a"}, true},
+		{"/a{b,c", []string{"a"}, true},
+		{"/**", []string{""}, true},
+		{"/file.txt", []string{"file.txt"}, true},
+		{"*.jpg", nil, false},
+		{"photos/**", nil, false},
+	} {
+		got, anchored := globPrefixes(test.in)
+		assert.Equal(t, test.want, got, test.in)
+		assert.Equal(t, test.anchored, anchored, test.in)
+	}
+
+	// Too many alternatives give the part before them
+	glob := "/x/{" + strings.Repeat("a,", maxListHintPrefixes) + "b}"
+	got, _ := globPrefixes(glob)
+	assert.Equal(t, []string{"x/"}, got)
+}
+
+func TestRelativePrefixes(t *testing.T) {
+	prefixes := []string{"photos/2020/", "photos/2021/", "docs/", "music"}
+	for _, test := range []struct {
+		dir       string
+		recursive bool
+		want      []string
+	}{
+		{"", false, []string{"docs", "music", "photos"}},
+		{"", true, []string{"docs/", "music", "photos/2020/", "photos/2021/"}},
+		{"photos", false, []string{"2020", "2021"}},
+		{"photos", true, []string{"2020/", "2021/"}},
+		{"photos/2020", true, nil},
+		{"photos/2020/jan", true, nil},
+		{"other", true, nil},
+		{"music", false, nil},
+	} {
+		got := relativePrefixes(prefixes, test.dir, test.recursive)
+		assert.Equal(t, test.want, got, test.dir)
+	}
+	// A prefix of another prefix is enough on its own
+	assert.Equal(t, []string{"a"}, relativePrefixes([]string{"ab", "a", "ac/d"}, "", true))
+
+	// Too many prefixes give none
+	prefixes = nil
+	for i := 0; i <= maxListHintPrefixes; i++ {
+		prefixes = append(prefixes, fmt.Sprintf("dir%02d/file", i))
+	}
+	assert.Nil(t, relativePrefixes(prefixes, "", false))
+	assert.Equal(t, []string{"file"}, relativePrefixes(prefixes, "dir01", false))
+}
+
+func TestRelativeStartAfter(t *testing.T) {
+	for _, test := range []struct {
+		startAfter string
+		dir        string
+		recursive  bool
+		want       string
+	}{
+		{"", "", true, ""},
+		{"2021/05", "", true, "2021/05"},
+		{"2021/05", "", false, "202"},
+		{"2021/05", "2021", false, "05"},
+		{"2021/05", "2020", false, ""},
+		{"2021/05/x", "2021", false, "0"},
+	} {
+		got := relativeStartAfter(test.startAfter, test.dir, test.recursive)
+		assert.Equal(t, test.want, got, test)
+	}
+}
+
+func TestFilterListHint(t *testing.T) {
+	f, err := NewFilter(nil)
+	require.NoError(t, err)
+	assert.Nil(t, f.ListHint("", true))
+
+	// Only anchored includes give prefixes
+	opt := DefaultOpt
+	opt.IncludeRule = []string{"/photos/**", "/docs/*.txt"}
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"docs", "photos"}}, f.ListHint("", false))
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"docs/", "photos/"}}, f.ListHint("", true))
+	assert.Nil(t, f.ListHint("photos", false))
+
+	opt.IncludeRule = []string{"/photos/**", "*.txt"}
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Nil(t, f.ListHint("", false))
+
+	// Excludes don't stop prefixes being used
+	opt = DefaultOpt
+	opt.FilterRule = []string{"- /photos/tmp/**", "+ /photos/**", "- **"}
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"photos/"}}, f.ListHint("", true))
+
+	// But not if everything else isn't excluded
+	opt.FilterRule = []string{"+ /photos/**", "- *.jpg"}
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Nil(t, f.ListHint("", true))
+
+	// Names might differ in case with --ignore-case
+	opt = DefaultOpt
+	opt.IncludeRule = []string{"/photos/**"}
+	opt.IgnoreCase = true
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Nil(t, f.ListHint("", true))
+
+	// Files from
+	f, err = NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, f.AddFile("a/b.txt"))
+	require.NoError(t, f.AddFile("c.txt"))
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"a", "c.txt"}}, f.ListHint("", false))
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"b.txt"}}, f.ListHint("a", false))
+
+	// but not too many of them
+	for i := 0; i < maxListHintFiles; i++ {
+		require.NoError(t, f.AddFile(fmt.Sprintf("a/%d.txt", i)))
+	}
+	assert.Nil(t, f.ListHint("a", false))
+
+	// Alternatives give a prefix each
+	opt = DefaultOpt
+	opt.IncludeRule = []string{"/{2020,2021}/**"}
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"2020/", "2021/"}}, f.ListHint("", true))
+
+	// Filter expressions
+	f, err = NewFilter(nil)
+	require.NoError(t, err)
+	require.NoError(t, f.AddExpr("path >= 2021/05 AND size > 1k"))
+	require.NoError(t, f.AddExpr("path > 2020 OR size > 1k"))
+	assert.Equal(t, &fs.ListHint{StartAfter: "2021/0"}, f.ListHint("", true))
+	assert.Equal(t, &fs.ListHint{StartAfter: "202"}, f.ListHint("", false))
+
+	// Modification times
+	opt = DefaultOpt
+	opt.MaxAge = fs.Duration(time.Hour)
+	f, err = NewFilter(&opt)
+	require.NoError(t, err)
+	hint := f.ListHint("dir", false)
+	require.NotNil(t, hint)
+	assert.Equal(t, f.ModTimeFrom, hint.ModifiedSince)
+	assert.True(t, hint.ModifiedBefore.IsZero())
+}
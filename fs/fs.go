@@ -49,6 +49,8 @@ var (
 	ErrorNotImplemented              = errors.New("optional feature not implemented")
 	ErrorCommandNotFound             = errors.New("command not found")
 	ErrorFileNameTooLong             = errors.New("file name too long")
+	ErrorChangeCursorInvalid         = errors.New("change cursor is invalid or has expired")
+	ErrorChangesIncomplete           = errors.New("some changes couldn't be found")
 )
 
 // CheckClose is a utility function used to check the return from
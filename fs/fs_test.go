@@ -143,6 +143,18 @@ func TestPacerCall(t *testing.T) {
 	require.Implements(t, (*fserrors.Retrier)(nil), err)
 }
 
+func TestPacerLowLevelRetries(t *testing.T) {
+	ctx, config := AddConfig(context.Background())
+	config.LowLevelRetries = 3
+	before := LowLevelRetries("other")
+	p := NewPacer(context.WithValue(ctx, remoteNameKey, "retrier"), pacer.NewDefault(pacer.MinSleep(1*time.Millisecond), pacer.MaxSleep(2*time.Millisecond)))
+
+	dp := &dummyPaced{retry: true}
+	_ = p.Call(dp.fn)
+	assert.Equal(t, int64(3), LowLevelRetries("retrier"))
+	assert.Equal(t, before, LowLevelRetries("other"))
+}
+
 func TestPacerCallNoRetry(t *testing.T) {
 	p := NewPacer(context.Background(), pacer.NewDefault(pacer.MinSleep(1*time.Millisecond), pacer.MaxSleep(2*time.Millisecond)))
 
@@ -380,3 +392,66 @@ func TestOptionGetters(t *testing.T) {
 	}
 
 }
+
+func TestAddRemoteConfigOverrides(t *testing.T) {
+	ctx := context.Background()
+	ci := GetConfig(ctx)
+	configMap := func(m configmap.Simple) *configmap.Map {
+		return configmap.New().AddGetter(m, configmap.PriorityNormal)
+	}
+
+	// No overrides so context unchanged
+	newCtx, err := addRemoteConfigOverrides(ctx, nil, configMap(configmap.Simple{"type": "s3"}))
+	require.NoError(t, err)
+	assert.Equal(t, ctx, newCtx)
+
+	newCtx, err = addRemoteConfigOverrides(ctx, nil, configMap(configmap.Simple{
+		"proxy":                   "socks5://127.0.0.1:1080",
+		"dns_server":              "1.1.1.1",
+		"disable_http2":           "true",
+		"max_conns_per_host":      "4",
+		"http2_read_idle_timeout": "30s",
+		"price_class_a":           "0.005",
+	}))
+	require.NoError(t, err)
+	newCi := GetConfig(newCtx)
+	assert.NotEqual(t, ci, newCi)
+	assert.Equal(t, "socks5://127.0.0.1:1080", newCi.Proxy)
+	assert.Equal(t, ci.NoProxy, newCi.NoProxy)
+	assert.Equal(t, "1.1.1.1", newCi.DNSServer)
+	assert.Equal(t, true, newCi.DisableHTTP2)
+	assert.Equal(t, 4, newCi.MaxConnsPerHost)
+	assert.Equal(t, 30*time.Second, newCi.HTTP2ReadIdleTimeout)
+	assert.Equal(t, 0.005, newCi.PriceClassA)
+	assert.Equal(t, "", ci.Proxy)
+
+	_, err = addRemoteConfigOverrides(ctx, nil, configMap(configmap.Simple{"max_conns_per_host": "potato"}))
+	assert.Error(t, err)
+
+	// Network options are checked
+	oldCheckProxy, oldCheckDNSServer := CheckProxy, CheckDNSServer
+	defer func() {
+		CheckProxy, CheckDNSServer = oldCheckProxy, oldCheckDNSServer
+	}()
+	CheckProxy = func(proxy string) error { return errors.New("bad proxy") }
+	CheckDNSServer = func(server string) error { return errors.New("bad dns server") }
+	_, err = addRemoteConfigOverrides(ctx, nil, configMap(configmap.Simple{"proxy": "ftp://proxy"}))
+	assert.EqualError(t, err, `couldn't parse config item "proxy" = "ftp://proxy": bad proxy`)
+	_, err = addRemoteConfigOverrides(ctx, nil, configMap(configmap.Simple{"dns_server": "tls://1.1.1.1"}))
+	assert.EqualError(t, err, `couldn't parse config item "dns_server" = "tls://1.1.1.1": bad dns server`)
+
+	// Defaults aren't overrides
+	m := configmap.New().AddGetter(configmap.Simple{"max_conns_per_host": "4"}, configmap.PriorityDefault)
+	newCtx, err = addRemoteConfigOverrides(ctx, nil, m)
+	require.NoError(t, err)
+	assert.Equal(t, ctx, newCtx)
+
+	// Options of the backend are left to the backend
+	fsInfo := &RegInfo{
+		Name:    "test",
+		Options: Options{{Name: "disable_http2", Default: false}},
+	}
+	newCtx, err = addRemoteConfigOverrides(ctx, fsInfo, configMap(configmap.Simple{"disable_http2": "true"}))
+	require.NoError(t, err)
+	assert.Equal(t, ctx, newCtx)
+}
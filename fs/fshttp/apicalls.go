@@ -0,0 +1,93 @@
+package fshttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+)
+
+// apiCallsFor returns the API call counters for the remote being
+// made with ctx or nil if there isn't one.
+func apiCallsFor(ctx context.Context) *accounting.APICalls {
+	name := fs.RemoteName(ctx)
+	if name == "" {
+		return nil
+	}
+	return accounting.APICallsFor(ctx, name)
+}
+
+// listQueryParams are the query parameters which mark a GET as a
+// listing in the S3 and GCS APIs
+var listQueryParams = []string{
+	"list-type",
+	"delimiter",
+	"prefix",
+	"marker",
+	"continuation-token",
+	"uploads",
+	"pageToken",
+}
+
+// apiClass works out how the request is likely to be billed.
+//
+// Writes and listings are class A, other reads are class B and
+// deletes are free. This matches S3 and GCS and is close enough for
+// most other providers.
+func apiClass(req *http.Request) accounting.APIClass {
+	switch req.Method {
+	case "DELETE":
+		return accounting.APIClassFree
+	case "HEAD":
+		return accounting.APIClassB
+	case "GET":
+		query := req.URL.Query()
+		if query.Get("comp") == "list" {
+			// Azure blob listing
+			return accounting.APIClassA
+		}
+		for _, param := range listQueryParams {
+			if _, ok := query[param]; ok {
+				return accounting.APIClassA
+			}
+		}
+		return accounting.APIClassB
+	}
+	return accounting.APIClassA
+}
+
+// forRemote returns a copy of t which counts the API calls made
+// through it against the remote being made with ctx, or doesn't count
+// them if there isn't one.
+//
+// The copy shares the underlying http.Transport so the connections
+// are still pooled between remotes.
+func (t *Transport) forRemote(ctx context.Context) *Transport {
+	calls := apiCallsFor(ctx)
+	if calls == t.apiCalls {
+		return t
+	}
+	newT := *t
+	newT.apiCalls = calls
+	return &newT
+}
+
+// egressBody counts the bytes read from a response body as egress
+type egressBody struct {
+	io.ReadCloser
+	ctx   context.Context
+	calls *accounting.APICalls
+}
+
+// Read from the body counting the bytes
+func (b *egressBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	if n > 0 {
+		if egressErr := b.calls.Egress(b.ctx, int64(n)); egressErr != nil && err == nil {
+			err = egressErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,84 @@
+package fshttp
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/rc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClass(t *testing.T) {
+	for _, test := range []struct {
+		method string
+		url    string
+		want   accounting.APIClass
+	}{
+		{"GET", "https://bucket.s3.amazonaws.com/file.txt", accounting.APIClassB},
+		{"HEAD", "https://bucket.s3.amazonaws.com/file.txt", accounting.APIClassB},
+		{"GET", "https://bucket.s3.amazonaws.com/?list-type=2&prefix=dir%2F", accounting.APIClassA},
+		{"GET", "https://storage.googleapis.com/storage/v1/b/bucket/o?pageToken=xyz", accounting.APIClassA},
+		{"GET", "https://account.blob.core.windows.net/container?restype=container&comp=list", accounting.APIClassA},
+		{"GET", "https://account.blob.core.windows.net/container/file?comp=metadata", accounting.APIClassB},
+		{"PUT", "https://bucket.s3.amazonaws.com/file.txt", accounting.APIClassA},
+		{"POST", "https://api.backblazeb2.com/b2api/v2/b2_list_file_names", accounting.APIClassA},
+		{"PROPFIND", "https://dav.example.com/dir/", accounting.APIClassA},
+		{"DELETE", "https://bucket.s3.amazonaws.com/file.txt", accounting.APIClassFree},
+	} {
+		req, err := http.NewRequest(test.method, test.url, nil)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, apiClass(req), test.method+" "+test.url)
+	}
+}
+
+func TestTransportCountsAPICalls(t *testing.T) {
+	defer accounting.ResetAPICalls()
+	accounting.ResetAPICalls()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.MaxAPICalls = 2
+	tr := NewTransportCustom(ctx, nil).(*Transport)
+	assert.Nil(t, tr.apiCalls)
+	calls := accounting.APICallsFor(ctx, "TestTransportCountsAPICalls")
+	tr.apiCalls = calls
+	client := &http.Client{Transport: tr}
+
+	get := func() error {
+		req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", string(body))
+		return nil
+	}
+	require.NoError(t, get())
+	require.NoError(t, get())
+	err := get()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), accounting.ErrorMaxAPICallsReached.Error())
+	out, err := accounting.GlobalStats().RemoteStats()
+	require.NoError(t, err)
+	remote := out["apiCalls"].(rc.Params)["TestTransportCountsAPICalls"].(rc.Params)
+	assert.Equal(t, int64(2), remote["classB"])
+	assert.Equal(t, int64(20), remote["egress"])
+
+	// A copy for another remote shares the http.Transport
+	ctx2 := context.Background()
+	assert.Equal(t, tr.Transport, tr.forRemote(ctx2).Transport)
+	assert.Nil(t, tr.forRemote(ctx2).apiCalls)
+}
@@ -3,6 +3,7 @@ package fshttp
 import (
 	"context"
 	"net"
+	"net/url"
 	"runtime"
 	"strings"
 	"sync"
@@ -15,7 +16,10 @@ import (
 )
 
 func dialContext(ctx context.Context, network, address string, ci *fs.ConfigInfo) (net.Conn, error) {
-	return NewDialer(ctx).DialContext(ctx, network, address)
+	dialer := NewDialer(ctx)
+	// The http.Transport does its own proxying
+	dialer.proxy = nil
+	return dialer.DialContext(ctx, network, address)
 }
 
 // Dialer structure contains default dialer and timeout, tclass support
@@ -23,10 +27,11 @@ type Dialer struct {
 	net.Dialer
 	timeout time.Duration
 	tclass  int
+	proxy   func(*url.URL) (*url.URL, error) // the proxy for an address, if any
 }
 
 // NewDialer creates a Dialer structure with Timeout, Keepalive,
-// LocalAddr and DSCP set from rclone flags.
+// LocalAddr, DSCP, proxy and DNS resolver set from rclone flags.
 func NewDialer(ctx context.Context) *Dialer {
 	ci := fs.GetConfig(ctx)
 	dialer := &Dialer{
@@ -40,6 +45,10 @@ func NewDialer(ctx context.Context) *Dialer {
 	if ci.BindAddr != nil {
 		dialer.Dialer.LocalAddr = &net.TCPAddr{IP: ci.BindAddr}
 	}
+	if ci.Proxy != "" {
+		dialer.proxy = proxyURLFunc(ci)
+	}
+	dialer.Dialer.Resolver = resolver(ci)
 	return dialer
 }
 
@@ -53,7 +62,7 @@ var warnDSCPFail, warnDSCPWindows sync.Once
 // DialContext connects to the address on the named network using
 // the provided context.
 func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
-	c, err := d.Dialer.DialContext(ctx, network, address)
+	c, err := d.dialProxy(ctx, network, address)
 	if err != nil {
 		return c, err
 	}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net"
@@ -18,6 +19,7 @@ import (
 	"github.com/rclone/rclone/fs"
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/lib/structs"
+	"golang.org/x/net/http2"
 	"golang.org/x/net/publicsuffix"
 )
 
@@ -29,6 +31,8 @@ const (
 var (
 	transport    http.RoundTripper
 	noTransport  = new(sync.Once)
+	transportsMu sync.Mutex
+	transports   = map[string]http.RoundTripper{}
 	cookieJar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 	logMutex     sync.Mutex
 )
@@ -37,6 +41,9 @@ var (
 // Should only be used for testing.
 func ResetTransport() {
 	noTransport = new(sync.Once)
+	transportsMu.Lock()
+	transports = map[string]http.RoundTripper{}
+	transportsMu.Unlock()
 }
 
 // NewTransportCustom returns an http.RoundTripper with the correct timeouts.
@@ -48,7 +55,9 @@ func NewTransportCustom(ctx context.Context, customize func(*http.Transport)) ht
 	// This also means we get new stuff when it gets added to go
 	t := new(http.Transport)
 	structs.SetDefaults(t, http.DefaultTransport.(*http.Transport))
-	t.Proxy = http.ProxyFromEnvironment
+	// Don't share the HTTP/2 setup of http.DefaultTransport if it has been used
+	t.TLSNextProto = nil
+	t.Proxy = proxyFunc(ci)
 	t.MaxIdleConnsPerHost = 2 * (ci.Checkers + ci.Transfers + 1)
 	t.MaxIdleConns = 2 * t.MaxIdleConnsPerHost
 	t.TLSHandshakeTimeout = ci.ConnectTimeout
@@ -103,22 +112,63 @@ func NewTransportCustom(ctx context.Context, customize func(*http.Transport)) ht
 	if ci.DisableHTTP2 {
 		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
 	}
+	t.MaxConnsPerHost = ci.MaxConnsPerHost
 
 	// customize the transport if required
 	if customize != nil {
 		customize(t)
 	}
 
+	// Configure HTTP/2 after customize so it sees the final TLS config
+	if t.TLSNextProto == nil && (ci.HTTP2ReadIdleTimeout > 0 || ci.HTTP2PingTimeout > 0 || ci.HTTP2StrictStreams) {
+		t2, err := http2.ConfigureTransports(t)
+		if err != nil {
+			fs.Errorf(nil, "Failed to configure HTTP/2 transport: %v", err)
+		} else {
+			t2.ReadIdleTimeout = ci.HTTP2ReadIdleTimeout
+			t2.PingTimeout = ci.HTTP2PingTimeout
+			t2.StrictMaxConcurrentStreams = ci.HTTP2StrictStreams
+		}
+	}
+
+	// There is no HTTP/3 (QUIC) transport. quic-go needs a much newer
+	// Go and golang.org/x libraries than rclone builds with, so it
+	// has been left for a separate change once the minimum Go
+	// version is raised.
+
 	// Wrap that http.Transport in our own transport
-	return newTransport(ci, t)
+	newT := newTransport(ci, t)
+	newT.apiCalls = apiCallsFor(ctx)
+	return newT
 }
 
 // NewTransport returns an http.RoundTripper with the correct timeouts
+//
+// If the network config in ctx differs from the global config, for
+// example because a remote has its own proxy, then a separate
+// transport is made and shared between users of that config.
 func NewTransport(ctx context.Context) http.RoundTripper {
+	if key := networkKey(fs.GetConfig(ctx)); key != networkKey(fs.GetConfig(context.Background())) {
+		transportsMu.Lock()
+		defer transportsMu.Unlock()
+		t, ok := transports[key]
+		if !ok {
+			t = NewTransportCustom(ctx, nil)
+			transports[key] = t
+		}
+		return t.(*Transport).forRemote(ctx)
+	}
 	(*noTransport).Do(func() {
 		transport = NewTransportCustom(ctx, nil)
 	})
-	return transport
+	return transport.(*Transport).forRemote(ctx)
+}
+
+// networkKey identifies the config items which need a separate
+// transport
+func networkKey(ci *fs.ConfigInfo) string {
+	return fmt.Sprintf("%q,%q,%q,%v,%d,%v,%v,%v", ci.Proxy, ci.NoProxy, ci.DNSServer,
+		ci.DisableHTTP2, ci.MaxConnsPerHost, ci.HTTP2ReadIdleTimeout, ci.HTTP2PingTimeout, ci.HTTP2StrictStreams)
 }
 
 // NewClient returns an http.Client with the correct timeouts
@@ -142,6 +192,7 @@ type Transport struct {
 	filterRequest func(req *http.Request)
 	userAgent     string
 	headers       []*fs.HTTPOption
+	apiCalls      *accounting.APICalls // count the calls against this remote if set
 }
 
 // newTransport wraps the http.Transport passed in and logs all
@@ -267,6 +318,13 @@ func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 		fs.Debugf(nil, "%s", separatorReq)
 		logMutex.Unlock()
 	}
+	// Count the API call
+	if t.apiCalls != nil {
+		err = t.apiCalls.Call(req.Context(), apiClass(req))
+		if err != nil {
+			return nil, err
+		}
+	}
 	// Do round trip
 	resp, err = t.Transport.RoundTrip(req)
 	// Logf response
@@ -285,6 +343,9 @@ func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error
 	}
 	if err == nil {
 		checkServerTime(req, resp)
+		if t.apiCalls != nil {
+			resp.Body = &egressBody{ReadCloser: resp.Body, ctx: req.Context(), calls: t.apiCalls}
+		}
 	}
 	return resp, err
 }
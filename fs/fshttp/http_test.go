@@ -1,8 +1,11 @@
 package fshttp
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/rclone/rclone/fs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -45,3 +48,29 @@ func TestCleanAuths(t *testing.T) {
 		assert.Equal(t, test.want, got, test.in)
 	}
 }
+
+func TestNewTransportCustomHTTP2(t *testing.T) {
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.MaxConnsPerHost = 3
+	ci.HTTP2ReadIdleTimeout = 30 * time.Second
+	tr := NewTransportCustom(ctx, nil).(*Transport)
+	assert.Equal(t, 3, tr.MaxConnsPerHost)
+	assert.Contains(t, tr.TLSNextProto, "h2")
+	assert.Contains(t, tr.TLSClientConfig.NextProtos, "h2")
+
+	ci.DisableHTTP2 = true
+	tr = NewTransportCustom(ctx, nil).(*Transport)
+	assert.NotContains(t, tr.TLSNextProto, "h2")
+}
+
+func TestNewTransportPerRemote(t *testing.T) {
+	ResetTransport()
+	defer ResetTransport()
+	global := NewTransport(context.Background())
+	ctx, ci := fs.AddConfig(context.Background())
+	assert.Equal(t, global, NewTransport(ctx))
+	ci.Proxy = "http://proxy.example.com:3128"
+	perRemote := NewTransport(ctx)
+	assert.NotEqual(t, global, perRemote)
+	assert.Equal(t, perRemote, NewTransport(ctx))
+}
@@ -0,0 +1,180 @@
+package fshttp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+func init() {
+	fs.CheckProxy = CheckProxy
+}
+
+// proxyFunc returns the function used to choose a proxy for each
+// request. If --proxy isn't set this reads the environment as usual.
+func proxyFunc(ci *fs.ConfigInfo) func(*http.Request) (*url.URL, error) {
+	if ci.Proxy == "" {
+		return http.ProxyFromEnvironment
+	}
+	fn := proxyURLFunc(ci)
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
+
+// proxyURLFunc returns a function which returns the proxy to use for
+// the URL passed in, or nil if it should be reached directly.
+func proxyURLFunc(ci *fs.ConfigInfo) func(*url.URL) (*url.URL, error) {
+	proxy := ci.Proxy
+	// Host names are always resolved by the SOCKS5 proxy so socks5h
+	// is the same as socks5 here
+	if strings.HasPrefix(proxy, "socks5h://") {
+		proxy = "socks5://" + strings.TrimPrefix(proxy, "socks5h://")
+	}
+	config := httpproxy.Config{
+		HTTPProxy:  proxy,
+		HTTPSProxy: proxy,
+		NoProxy:    ci.NoProxy,
+	}
+	return config.ProxyFunc()
+}
+
+// CheckProxy returns an error if the proxy URL can't be used
+func CheckProxy(proxyString string) error {
+	if proxyString == "" {
+		return nil
+	}
+	proxyURL, err := url.Parse(proxyString)
+	if err != nil {
+		return errors.Wrapf(err, "invalid proxy %q", proxyString)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return errors.Errorf("invalid proxy %q: scheme must be http, https, socks5 or socks5h", proxyString)
+	}
+	if proxyURL.Host == "" {
+		return errors.Errorf("invalid proxy %q: no host", proxyString)
+	}
+	return nil
+}
+
+// dialProxy connects to address via the proxy if one is configured
+// for it, otherwise directly.
+func (d *Dialer) dialProxy(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.proxy == nil {
+		return d.Dialer.DialContext(ctx, network, address)
+	}
+	proxyURL, err := d.proxy(&url.URL{Scheme: "https", Host: address})
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return d.Dialer.DialContext(ctx, network, address)
+	}
+	switch proxyURL.Scheme {
+	case "socks5":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			auth = &proxy.Auth{User: proxyURL.User.Username()}
+			auth.Password, _ = proxyURL.User.Password()
+		}
+		dialer, err := proxy.SOCKS5("tcp", proxyAddr(proxyURL), auth, &d.Dialer)
+		if err != nil {
+			return nil, err
+		}
+		return dialer.(proxy.ContextDialer).DialContext(ctx, network, address)
+	case "http", "https":
+		return d.dialConnect(ctx, address, proxyURL)
+	}
+	return nil, errors.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+}
+
+// dialConnect connects to address by tunneling through the HTTP
+// proxy with the CONNECT method.
+func (d *Dialer) dialConnect(ctx context.Context, address string, proxyURL *url.URL) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, "tcp", proxyAddr(proxyURL))
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else if d.Dialer.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(d.Dialer.Timeout))
+	}
+	req := &http.Request{
+		Method: "CONNECT",
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		auth := proxyURL.User.Username() + ":" + password
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+	}
+	err = req.Write(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "failed to write CONNECT to proxy")
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "failed to read CONNECT response from proxy")
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, errors.Errorf("proxy refused CONNECT to %s: %s", address, resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	if br.Buffered() > 0 {
+		// The server spoke first so keep what we read already
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// proxyAddr returns the host:port of the proxy with the default port
+// for the scheme if none was given
+func proxyAddr(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// bufferedConn is a net.Conn which reads through a bufio.Reader
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Read from the buffer then the underlying connection
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,203 @@
+package fshttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+)
+
+var (
+	resolversMu sync.Mutex
+	resolvers   = map[string]*net.Resolver{}
+)
+
+func init() {
+	fs.CheckDNSServer = CheckDNSServer
+}
+
+// dnsServer describes how to reach the server given with --dns-server
+type dnsServer struct {
+	network string // "udp", "tcp" or "" to use whatever the resolver asks for
+	address string // host:port of the server
+	dohURL  string // URL to use for DNS over HTTPS if set
+}
+
+// parseDNSServer parses the --dns-server value which may be
+//
+//   - an IP address or host:port, e.g. 1.1.1.1 or 1.1.1.1:53
+//   - udp://host:port or tcp://host:port to force the network
+//   - an https:// URL for DNS over HTTPS (RFC 8484)
+func parseDNSServer(server string) (dns dnsServer, err error) {
+	switch {
+	case strings.HasPrefix(server, "https://"):
+		if _, err = url.Parse(server); err != nil {
+			return dns, errors.Wrapf(err, "invalid DNS server %q", server)
+		}
+		dns.dohURL = server
+		return dns, nil
+	case strings.HasPrefix(server, "udp://"):
+		dns.network, server = "udp", server[6:]
+	case strings.HasPrefix(server, "tcp://"):
+		dns.network, server = "tcp", server[6:]
+	case strings.Contains(server, "://"):
+		return dns, errors.Errorf("invalid DNS server %q: scheme must be udp, tcp or https", server)
+	}
+	if _, _, err = net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+	if _, _, err = net.SplitHostPort(server); err != nil {
+		return dns, errors.Wrapf(err, "invalid DNS server %q", server)
+	}
+	dns.address = server
+	return dns, nil
+}
+
+// CheckDNSServer returns an error if the DNS server can't be used
+func CheckDNSServer(server string) error {
+	if server == "" {
+		return nil
+	}
+	_, err := parseDNSServer(server)
+	return err
+}
+
+// resolver returns the resolver for --dns-server or nil to use the
+// system resolver.
+func resolver(ci *fs.ConfigInfo) *net.Resolver {
+	if ci.DNSServer == "" {
+		return nil
+	}
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	if r, ok := resolvers[ci.DNSServer]; ok {
+		return r
+	}
+	dns, err := parseDNSServer(ci.DNSServer)
+	r := &net.Resolver{PreferGo: true}
+	if err != nil {
+		// Fail the lookups rather than silently using the system
+		// resolver instead of the one asked for
+		r.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, err
+		}
+	} else if dns.dohURL != "" {
+		client := &http.Client{
+			Timeout: ci.ConnectTimeout,
+		}
+		r.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			return &dohConn{ctx: ctx, client: client, url: dns.dohURL}, nil
+		}
+	} else {
+		dialer := net.Dialer{Timeout: ci.ConnectTimeout}
+		if ci.BindAddr != nil {
+			dialer.LocalAddr = &net.TCPAddr{IP: ci.BindAddr}
+		}
+		r.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			if dns.network != "" {
+				network = dns.network
+			}
+			return dialer.DialContext(ctx, network, dns.address)
+		}
+	}
+	resolvers[ci.DNSServer] = r
+	return r
+}
+
+// dohConn is a net.Conn which sends the DNS queries written to it
+// with DNS over HTTPS and returns the answers when read.
+//
+// As it isn't a net.PacketConn the resolver uses the TCP framing
+// where each message is preceded by its length as 2 bytes.
+type dohConn struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+	wbuf   bytes.Buffer
+	rbuf   bytes.Buffer
+}
+
+// Write queries to the DoH server
+func (c *dohConn) Write(b []byte) (int, error) {
+	c.wbuf.Write(b)
+	for c.wbuf.Len() >= 2 {
+		n := int(binary.BigEndian.Uint16(c.wbuf.Bytes()))
+		if c.wbuf.Len() < 2+n {
+			break
+		}
+		c.wbuf.Next(2)
+		answer, err := c.query(c.wbuf.Next(n))
+		if err != nil {
+			return 0, err
+		}
+		var length [2]byte
+		binary.BigEndian.PutUint16(length[:], uint16(len(answer)))
+		c.rbuf.Write(length[:])
+		c.rbuf.Write(answer)
+	}
+	return len(b), nil
+}
+
+// query sends a single DNS message and returns the answer
+func (c *dohConn) query(msg []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(c.ctx, "POST", c.url, bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "DNS over HTTPS query failed")
+	}
+	defer fs.CheckClose(resp.Body, &err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("DNS over HTTPS query failed: %s", resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 65535))
+}
+
+// Read answers from the DoH server
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.rbuf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return c.rbuf.Read(b)
+}
+
+// Close the connection
+func (c *dohConn) Close() error { return nil }
+
+// LocalAddr returns a dummy address
+func (c *dohConn) LocalAddr() net.Addr { return dohAddr(c.url) }
+
+// RemoteAddr returns the DoH URL as an address
+func (c *dohConn) RemoteAddr() net.Addr { return dohAddr(c.url) }
+
+// SetDeadline is ignored - the context and client timeout are used
+func (c *dohConn) SetDeadline(t time.Time) error { return nil }
+
+// SetReadDeadline is ignored
+func (c *dohConn) SetReadDeadline(t time.Time) error { return nil }
+
+// SetWriteDeadline is ignored
+func (c *dohConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// dohAddr is the net.Addr of a dohConn
+type dohAddr string
+
+// Network returns the name of the network
+func (a dohAddr) Network() string { return "https" }
+
+// String returns the URL
+func (a dohAddr) String() string { return string(a) }
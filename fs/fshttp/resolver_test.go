@@ -0,0 +1,96 @@
+package fshttp
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func mustParseURL(t *testing.T, in string) *url.URL {
+	u, err := url.Parse(in)
+	require.NoError(t, err)
+	return u
+}
+
+func TestParseDNSServer(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    dnsServer
+		wantErr bool
+	}{
+		{"1.1.1.1", dnsServer{address: "1.1.1.1:53"}, false},
+		{"1.1.1.1:5353", dnsServer{address: "1.1.1.1:5353"}, false},
+		{"2606:4700::1111", dnsServer{address: "[2606:4700::1111]:53"}, false},
+		{"[2606:4700::1111]:53", dnsServer{address: "[2606:4700::1111]:53"}, false},
+		{"tcp://8.8.8.8", dnsServer{network: "tcp", address: "8.8.8.8:53"}, false},
+		{"udp://dns.example.com:53", dnsServer{network: "udp", address: "dns.example.com:53"}, false},
+		{"https://dns.example.com/dns-query", dnsServer{dohURL: "https://dns.example.com/dns-query"}, false},
+		{"tls://1.1.1.1", dnsServer{}, true},
+	} {
+		got, err := parseDNSServer(test.in)
+		assert.Equal(t, test.wantErr, err != nil, test.in)
+		if err == nil {
+			assert.Equal(t, test.want, got, test.in)
+		}
+	}
+}
+
+// Test resolving with DNS over HTTPS
+func TestResolverDoH(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/dns-message", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var query dnsmessage.Message
+		require.NoError(t, query.Unpack(body))
+		answer := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+			Questions: query.Questions,
+		}
+		for _, q := range query.Questions {
+			if q.Type == dnsmessage.TypeA {
+				answer.Answers = append(answer.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+					Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+				})
+			}
+		}
+		out, err := answer.Pack()
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(out)
+	}))
+	defer server.Close()
+
+	// Use the https scheme to select DoH but talk to the test server
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.DNSServer = "https://" + server.Listener.Addr().String() + "/dns-query"
+	r := resolver(ci)
+	require.NotNil(t, r)
+	r.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return &dohConn{ctx: ctx, client: server.Client(), url: server.URL + "/dns-query"}, nil
+	}
+	addrs, err := r.LookupHost(ctx, "rclone.example.org")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1"}, addrs)
+}
+
+// Test a bad --dns-server fails lookups rather than using the system resolver
+func TestResolverInvalid(t *testing.T) {
+	ctx, ci := fs.AddConfig(context.Background())
+	ci.DNSServer = "tls://1.1.1.1"
+	r := resolver(ci)
+	require.NotNil(t, r)
+	_, err := r.LookupHost(ctx, "rclone.example.org")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid DNS server")
+}
@@ -20,19 +20,36 @@ import (
 //
 // Files will be returned in sorted order
 func DirSorted(ctx context.Context, f fs.Fs, includeAll bool, dir string) (entries fs.DirEntries, err error) {
-	// Get unfiltered entries from the fs
-	entries, err = f.List(ctx, dir)
+	fi := filter.GetConfig(ctx)
+	// Get unfiltered entries from the fs, letting the backend
+	// use the filter to list less if it can
+	var hint *fs.ListHint
+	doListHinted := f.Features().ListHinted
+	if doListHinted != nil && !includeAll {
+		hint = fi.ListHint(dir, false)
+	}
+	if hint != nil {
+		fs.Debugf(f, "Listing %q with hint: %v", dir, hint)
+		entries, err = doListHinted(ctx, dir, hint)
+	} else {
+		entries, err = f.List(ctx, dir)
+	}
 	if err != nil {
 		return nil, err
 	}
 	// This should happen only if exclude files lives in the
 	// starting directory, otherwise ListDirSorted should not be
 	// called.
-	fi := filter.GetConfig(ctx)
 	if !includeAll && fi.ListContainsExcludeFile(entries) {
 		fs.Debugf(dir, "Excluded")
 		return nil, nil
 	}
+	if !includeAll {
+		err = fi.ReadIgnoreFiles(ctx, f, dir, entries)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return filterAndSortDir(ctx, entries, includeAll, dir, fi.IncludeObject, fi.IncludeDirectory(ctx, f))
 }
 
@@ -2,11 +2,14 @@ package list
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fstest/mockdir"
+	"github.com/rclone/rclone/fstest/mockfs"
 	"github.com/rclone/rclone/fstest/mockobject"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -103,3 +106,52 @@ func TestFilterAndSortUnknown(t *testing.T) {
 	assert.Error(t, err, "error")
 	assert.Nil(t, newEntries)
 }
+
+// hintFs is a mock Fs which records the hint it is listed with
+type hintFs struct {
+	*mockfs.Fs
+	features *fs.Features
+	hint     *fs.ListHint
+}
+
+// Features returns the optional features of this Fs
+func (f *hintFs) Features() *fs.Features {
+	return f.features
+}
+
+// ListHinted lists the root recording the hint
+func (f *hintFs) ListHinted(ctx context.Context, dir string, hint *fs.ListHint) (fs.DirEntries, error) {
+	f.hint = hint
+	return f.List(ctx, dir)
+}
+
+func TestDirSortedHint(t *testing.T) {
+	ctx := context.Background()
+	f := &hintFs{Fs: mockfs.NewFs(ctx, "mock", "")}
+	f.features = (&fs.Features{}).Fill(ctx, f)
+	f.AddObject(mockobject.Object("a.txt"))
+	f.AddObject(mockobject.Object("b.txt"))
+
+	// No hint without filters
+	_, err := DirSorted(ctx, f, false, "")
+	require.NoError(t, err)
+	assert.Nil(t, f.hint)
+
+	opt := filter.DefaultOpt
+	opt.IncludeRule = []string{"/a*"}
+	fi, err := filter.NewFilter(&opt)
+	require.NoError(t, err)
+	ctx = filter.ReplaceConfig(ctx, fi)
+
+	// The entries the hint doesn't cover are still filtered
+	entries, err := DirSorted(ctx, f, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, &fs.ListHint{Prefixes: []string{"a"}}, f.hint)
+	assert.Equal(t, "[a.txt]", fmt.Sprint(entries))
+
+	// No hint if listing everything
+	f.hint = nil
+	_, err = DirSorted(ctx, f, true, "")
+	require.NoError(t, err)
+	assert.Nil(t, f.hint)
+}
@@ -0,0 +1,107 @@
+package fs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ListHint describes the entries a listing is needed for so that
+// backends which can ask their API for less may do so.
+//
+// Hints are only ever an optimisation - the filters are still
+// applied to whatever the backend returns, so a backend may ignore
+// any part of the hint it can't use.
+type ListHint struct {
+	// Prefixes, if not empty, are the only prefixes of the names
+	// needed, relative to the directory being listed. For a
+	// recursive listing these are prefixes of the paths.
+	Prefixes []string
+
+	// StartAfter, if set, means only names sorting after it are
+	// needed, relative to the directory being listed.
+	StartAfter string
+
+	// ModifiedSince, if set, means only objects modified at or
+	// after it are needed. Directories are always needed in
+	// listings of a single directory but recursive listings may
+	// leave out directories with no objects needed in them.
+	ModifiedSince time.Time
+
+	// ModifiedBefore, if set, means only objects modified at or
+	// before it are needed, like ModifiedSince.
+	ModifiedBefore time.Time
+}
+
+// IsEmpty returns true if the hint doesn't constrain the listing
+func (h *ListHint) IsEmpty() bool {
+	return h == nil || (len(h.Prefixes) == 0 && h.StartAfter == "" && h.ModifiedSince.IsZero() && h.ModifiedBefore.IsZero())
+}
+
+// String returns a description of the hint for debugging
+func (h *ListHint) String() string {
+	if h.IsEmpty() {
+		return "no hint"
+	}
+	var out []string
+	if len(h.Prefixes) > 0 {
+		out = append(out, fmt.Sprintf("prefixes %q", h.Prefixes))
+	}
+	if h.StartAfter != "" {
+		out = append(out, fmt.Sprintf("start after %q", h.StartAfter))
+	}
+	if !h.ModifiedSince.IsZero() {
+		out = append(out, fmt.Sprintf("modified since %v", h.ModifiedSince))
+	}
+	if !h.ModifiedBefore.IsZero() {
+		out = append(out, fmt.Sprintf("modified before %v", h.ModifiedBefore))
+	}
+	return strings.Join(out, ", ")
+}
+
+// safeHintName returns the part of name which can be encoded on its
+// own to give the start of the encoded version of any longer name,
+// or false if there isn't one.
+//
+// Encoders may change characters at the end of names, so any
+// trailing characters which might be encoded differently are
+// removed, and the rest must encode to itself.
+func safeHintName(name string, encode func(string) string) (string, bool) {
+	name = strings.TrimRight(name, " .\t\r\n\v")
+	if name == "" || encode(name) != name {
+		return "", false
+	}
+	return name, true
+}
+
+// EncodedPrefixes returns the Prefixes encoded with encode, which
+// should be the backend's FromStandardPath.
+//
+// It returns nil if there are no prefixes or any of them can't be
+// encoded safely, in which case the listing shouldn't be narrowed.
+func (h *ListHint) EncodedPrefixes(encode func(string) string) (prefixes []string) {
+	if h == nil {
+		return nil
+	}
+	for _, prefix := range h.Prefixes {
+		prefix, ok := safeHintName(prefix, encode)
+		if !ok {
+			return nil
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// EncodedStartAfter returns StartAfter encoded with encode, which
+// should be the backend's FromStandardPath, or "" if it is unset or
+// can't be encoded safely.
+func (h *ListHint) EncodedStartAfter(encode func(string) string) string {
+	if h == nil || h.StartAfter == "" {
+		return ""
+	}
+	// The trimmed StartAfter is a prefix of StartAfter so it
+	// sorts before it and the listing may return more
+	startAfter, _ := safeHintName(h.StartAfter, encode)
+	return startAfter
+}
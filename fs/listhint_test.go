@@ -0,0 +1,42 @@
+package fs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListHintIsEmpty(t *testing.T) {
+	var hint *ListHint
+	assert.True(t, hint.IsEmpty())
+	assert.Equal(t, "no hint", hint.String())
+	assert.True(t, (&ListHint{}).IsEmpty())
+	assert.False(t, (&ListHint{StartAfter: "a"}).IsEmpty())
+	assert.False(t, (&ListHint{ModifiedSince: time.Now()}).IsEmpty())
+	assert.Equal(t, `prefixes ["a" "b"], start after "c"`, (&ListHint{Prefixes: []string{"a", "b"}, StartAfter: "c"}).String())
+}
+
+func TestListHintEncoded(t *testing.T) {
+	identity := func(s string) string { return s }
+	encodeDot := func(s string) string { return strings.Replace(s, ".", "．", -1) }
+
+	var hint *ListHint
+	assert.Nil(t, hint.EncodedPrefixes(identity))
+	assert.Equal(t, "", hint.EncodedStartAfter(identity))
+
+	hint = &ListHint{Prefixes: []string{"photos/", "file "}, StartAfter: "2021/05"}
+	assert.Equal(t, []string{"photos/", "file"}, hint.EncodedPrefixes(identity))
+	assert.Equal(t, "2021/05", hint.EncodedStartAfter(identity))
+
+	// Prefixes which encode differently can't be used
+	hint = &ListHint{Prefixes: []string{"photos/", "a.b"}, StartAfter: "a.b"}
+	assert.Nil(t, hint.EncodedPrefixes(encodeDot))
+	assert.Equal(t, "", hint.EncodedStartAfter(encodeDot))
+
+	// Trailing characters which might be encoded are trimmed
+	hint = &ListHint{Prefixes: []string{"ab."}, StartAfter: "ab."}
+	assert.Equal(t, []string{"ab"}, hint.EncodedPrefixes(encodeDot))
+	assert.Equal(t, "ab", hint.EncodedStartAfter(encodeDot))
+}
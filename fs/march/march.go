@@ -52,6 +52,8 @@ type Marcher interface {
 // Note: this will flag filter-aware backends on the source side
 func (m *March) init(ctx context.Context) {
 	ci := fs.GetConfig(ctx)
+	// use the source's ignore files for the destination too
+	m.Ctx = filter.IgnoreFilesFrom(m.Ctx, m.Fsrc)
 	m.srcListDir = m.makeListDir(ctx, m.Fsrc, m.SrcIncludeAll)
 	if !m.NoTraverse {
 		m.dstListDir = m.makeListDir(ctx, m.Fdst, m.DstIncludeAll)
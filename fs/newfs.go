@@ -9,8 +9,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fs/fspath"
 )
@@ -30,6 +32,10 @@ func NewFs(ctx context.Context, path string) (Fs, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = checkSecrets(fsInfo, config)
+	if err != nil {
+		return nil, err
+	}
 	overridden := fsInfo.Options.Overridden(config)
 	if len(overridden) > 0 {
 		extraConfig := overridden.String()
@@ -48,9 +54,121 @@ func NewFs(ctx context.Context, path string) (Fs, error) {
 		// These need to work as filesystem names as the VFS cache will use them
 		configName += suffix
 	}
+	ctx, err = addRemoteConfigOverrides(ctx, fsInfo, config)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, remoteNameKey, configName)
 	return fsInfo.NewFs(ctx, configName, fsPath, config)
 }
 
+// checkSecrets reads every option of the backend so that a secret
+// which can't be read fails the creation of the Fs rather than the
+// option silently taking its default value.
+func checkSecrets(fsInfo *RegInfo, m *configmap.Map) error {
+	for _, opt := range fsInfo.Options {
+		_, _ = m.Get(opt.Name)
+	}
+	if err := m.SecretErr(); err != nil {
+		return errors.Wrap(err, "failed to read secret")
+	}
+	return nil
+}
+
+type remoteNameKeyType struct{}
+
+// Context key for the name of the remote being made
+var remoteNameKey = remoteNameKeyType{}
+
+// RemoteName returns the name of the remote if ctx is the context
+// passed to a backend's NewFs by NewFs, or "" otherwise.
+//
+// It is used to attribute the API calls made by the backend.
+func RemoteName(ctx context.Context) string {
+	name, _ := ctx.Value(remoteNameKey).(string)
+	return name
+}
+
+// remoteConfigOverrides are the keys which can be set in a remote's
+// config to override the global network options for that remote only
+var remoteConfigOverrides = []struct {
+	key string
+	set func(ci *ConfigInfo, value string) error
+}{
+	{"proxy", func(ci *ConfigInfo, value string) error {
+		ci.Proxy = value
+		return CheckProxy(value)
+	}},
+	{"no_proxy", func(ci *ConfigInfo, value string) error {
+		ci.NoProxy = value
+		return nil
+	}},
+	{"dns_server", func(ci *ConfigInfo, value string) error {
+		ci.DNSServer = value
+		return CheckDNSServer(value)
+	}},
+	{"disable_http2", func(ci *ConfigInfo, value string) (err error) {
+		ci.DisableHTTP2, err = strconv.ParseBool(value)
+		return err
+	}},
+	{"max_conns_per_host", func(ci *ConfigInfo, value string) (err error) {
+		ci.MaxConnsPerHost, err = strconv.Atoi(value)
+		return err
+	}},
+	{"http2_read_idle_timeout", func(ci *ConfigInfo, value string) (err error) {
+		ci.HTTP2ReadIdleTimeout, err = ParseDuration(value)
+		return err
+	}},
+	{"http2_ping_timeout", func(ci *ConfigInfo, value string) (err error) {
+		ci.HTTP2PingTimeout, err = ParseDuration(value)
+		return err
+	}},
+	{"http2_strict_max_concurrent_streams", func(ci *ConfigInfo, value string) (err error) {
+		ci.HTTP2StrictStreams, err = strconv.ParseBool(value)
+		return err
+	}},
+	{"price_class_a", func(ci *ConfigInfo, value string) (err error) {
+		ci.PriceClassA, err = strconv.ParseFloat(value, 64)
+		return err
+	}},
+	{"price_class_b", func(ci *ConfigInfo, value string) (err error) {
+		ci.PriceClassB, err = strconv.ParseFloat(value, 64)
+		return err
+	}},
+	{"price_egress", func(ci *ConfigInfo, value string) (err error) {
+		ci.PriceEgress, err = strconv.ParseFloat(value, 64)
+		return err
+	}},
+}
+
+// addRemoteConfigOverrides returns a context with the global options
+// in remoteConfigOverrides overridden by the remote's config if any
+// of them are set.
+//
+// Only values set for the remote are used, not the backend's
+// defaults, and keys which are options of the backend are left for
+// the backend to use itself, eg disable_http2 for s3 and drive.
+func addRemoteConfigOverrides(ctx context.Context, fsInfo *RegInfo, m *configmap.Map) (context.Context, error) {
+	var ci *ConfigInfo
+	for _, override := range remoteConfigOverrides {
+		if fsInfo != nil && fsInfo.Options.Get(override.key) != nil {
+			continue
+		}
+		value, ok := m.GetPriority(override.key, configmap.PriorityConfig)
+		if !ok {
+			continue
+		}
+		if ci == nil {
+			ctx, ci = AddConfig(ctx)
+		}
+		err := override.set(ci, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "couldn't parse config item %q = %q", override.key, value)
+		}
+	}
+	return ctx, nil
+}
+
 // ConfigFs makes the config for calling NewFs with.
 //
 // It parses the path which is of the form remote:path
@@ -0,0 +1,48 @@
+package fs
+
+import (
+	"time"
+)
+
+// ObjectVersion describes a version of an object on a backend which
+// keeps old versions of objects.
+type ObjectVersion struct {
+	// ID identifies the version to the backend
+	ID string
+
+	// Time is when the version was made, so it was the current
+	// version from then until the next newer version was made.
+	Time time.Time
+
+	// ModTime is the modification time of the version, which is
+	// the same as Time if the backend doesn't store it.
+	ModTime time.Time
+
+	// Size of the version in bytes
+	Size int64
+
+	// Current is set if this is the current version of the
+	// object. This may be a Deleted version if the object has
+	// been deleted.
+	Current bool
+
+	// Deleted is set if this version marks when the object was
+	// deleted, so it can't be opened or restored.
+	Deleted bool
+}
+
+// VersionAt returns the version which was current at the time given
+// from versions sorted newest first, or nil if the object didn't
+// exist then.
+func VersionAt(versions []ObjectVersion, at time.Time) *ObjectVersion {
+	for i := range versions {
+		v := &versions[i]
+		if !v.Time.After(at) {
+			if v.Deleted {
+				return nil
+			}
+			return v
+		}
+	}
+	return nil
+}
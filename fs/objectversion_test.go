@@ -0,0 +1,40 @@
+package fs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionAt(t *testing.T) {
+	hour := func(h int) time.Time {
+		return time.Date(2021, 3, 1, h, 0, 0, 0, time.UTC)
+	}
+	versions := []ObjectVersion{
+		{ID: "4", Time: hour(16), Current: true, Deleted: true},
+		{ID: "3", Time: hour(14)},
+		{ID: "2", Time: hour(12)},
+		{ID: "1", Time: hour(10)},
+	}
+	for _, test := range []struct {
+		at   time.Time
+		want string
+	}{
+		{hour(9), ""},
+		{hour(10), "1"},
+		{hour(11), "1"},
+		{hour(13), "2"},
+		{hour(14), "3"},
+		{hour(15), "3"},
+		{hour(16), ""},
+		{hour(17), ""},
+	} {
+		got := ""
+		if v := VersionAt(versions, test.at); v != nil {
+			got = v.ID
+		}
+		assert.Equal(t, test.want, got, test.at)
+	}
+	assert.Nil(t, VersionAt(nil, hour(10)))
+}
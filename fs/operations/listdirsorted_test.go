@@ -105,3 +105,61 @@ func TestListDirSorted(t *testing.T) {
 	assert.Equal(t, "sub dir/ignore dir/.ignore", str(0))
 	assert.Equal(t, "sub dir/ignore dir/should be ignored", str(1))
 }
+
+// TestListDirSortedIgnoreFiles tests --ignore-file-name
+func TestListDirSortedIgnoreFiles(t *testing.T) {
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	ctx := context.Background()
+	r.WriteObject(ctx, ".rcloneignore", "*.log\nbuild/\n", t1)
+	r.WriteObject(ctx, "a.log", "a", t1)
+	r.WriteObject(ctx, "a.txt", "a", t1)
+	r.WriteObject(ctx, "build/b.txt", "b", t1)
+	r.WriteObject(ctx, "sub/.rcloneignore", "!important.log\n/secret.txt\n", t1)
+	r.WriteObject(ctx, "sub/important.log", "c", t1)
+	r.WriteObject(ctx, "sub/other.log", "d", t1)
+	r.WriteObject(ctx, "sub/secret.txt", "e", t1)
+	r.WriteObject(ctx, "sub/deeper/secret.txt", "f", t1)
+
+	names := func(ctx context.Context, dir string) (out []string) {
+		items, err := list.DirSorted(ctx, r.Fremote, false, dir)
+		require.NoError(t, err)
+		for _, item := range items {
+			name := item.Remote()
+			if _, ok := item.(fs.Directory); ok {
+				name += "/"
+			}
+			out = append(out, name)
+		}
+		return out
+	}
+
+	opt := filter.DefaultOpt
+	opt.IgnoreFileName = []string{".rcloneignore"}
+	fi, err := filter.NewFilter(&opt)
+	require.NoError(t, err)
+
+	// Listing a subdirectory first reads the ignore files above it
+	subCtx := filter.ReplaceConfig(ctx, fi)
+	assert.Equal(t, []string{"sub/.rcloneignore", "sub/deeper/", "sub/important.log"}, names(subCtx, "sub"))
+
+	fi, err = filter.NewFilter(&opt)
+	require.NoError(t, err)
+	ctx = filter.ReplaceConfig(ctx, fi)
+	assert.Equal(t, []string{".rcloneignore", "a.txt", "sub/"}, names(ctx, ""))
+	assert.Equal(t, []string{"sub/.rcloneignore", "sub/deeper/", "sub/important.log"}, names(ctx, "sub"))
+	assert.Equal(t, []string{"sub/deeper/secret.txt"}, names(ctx, "sub/deeper"))
+
+	// Files found without listing are excluded if a directory
+	// above them is ignored
+	for _, remote := range []string{"build/b.txt", "sub/secret.txt", "sub/important.log"} {
+		o, err := r.Fremote.NewObject(ctx, remote)
+		require.NoError(t, err)
+		assert.Equal(t, remote == "sub/important.log", fi.IncludeObject(ctx, o), remote)
+	}
+
+	// Changes to the ignore files are noticed when relisted
+	r.WriteObject(ctx, ".rcloneignore", "*.txt\n", t2)
+	assert.Equal(t, []string{".rcloneignore", "a.log", "build/", "sub/"}, names(ctx, ""))
+}
@@ -372,6 +372,7 @@ func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Obj
 	if SkipDestructive(ctx, src, "copy") {
 		in := tr.Account(ctx, nil)
 		in.DryRun(src.Size())
+		estimateAPICalls(ctx, f, src, false)
 		return newDst, nil
 	}
 	maxTries := ci.LowLevelRetries
@@ -398,6 +399,9 @@ func Copy(ctx context.Context, f fs.Fs, dst fs.Object, remote string, src fs.Obj
 				return nil, accounting.ErrorMaxTransferLimitReachedGraceful
 			}
 		}
+		if err = accounting.CheckAPIBudget(ctx); err != nil {
+			return nil, err
+		}
 		if doCopy := f.Features().Copy; doCopy != nil && (SameConfig(src.Fs(), f) || (SameRemoteType(src.Fs(), f) && f.Features().ServerSideAcrossConfigs)) {
 			in := tr.Account(ctx, nil) // account the transfer
 			in.ServerSideCopyStart()
@@ -586,6 +590,7 @@ func Move(ctx context.Context, fdst fs.Fs, dst fs.Object, remote string, src fs.
 	if SkipDestructive(ctx, src, "move") {
 		in := tr.Account(ctx, nil)
 		in.DryRun(src.Size())
+		estimateAPICalls(ctx, fdst, src, true)
 		return newDst, nil
 	}
 	// See if we have Move available
@@ -658,6 +663,13 @@ func SuffixName(ctx context.Context, remote string) string {
 // If backupDir is set then it moves the file to there instead of
 // deleting
 func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs) (err error) {
+	_, err = deleteFileWithBackupDir(ctx, dst, backupDir)
+	return err
+}
+
+// deleteFileWithBackupDir is DeleteFileWithBackupDir also returning
+// whether the deletion was skipped by --dry-run or --interactive
+func deleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs) (skip bool, err error) {
 	ci := fs.GetConfig(ctx)
 	tr := accounting.Stats(ctx).NewCheckingTransfer(dst)
 	defer func() {
@@ -665,15 +677,19 @@ func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs
 	}()
 	numDeletes := accounting.Stats(ctx).Deletes(1)
 	if ci.MaxDelete != -1 && numDeletes > ci.MaxDelete {
-		return fserrors.FatalError(errors.New("--max-delete threshold reached"))
+		return false, fserrors.FatalError(errors.New("--max-delete threshold reached"))
 	}
 	action, actioned := "delete", "Deleted"
 	if backupDir != nil {
 		action, actioned = "move into backup dir", "Moved into backup dir"
 	}
-	skip := SkipDestructive(ctx, dst, action)
+	skip = SkipDestructive(ctx, dst, action)
 	if skip {
-		// do nothing
+		if backupDir != nil {
+			estimateAPICalls(ctx, backupDir, dst, true)
+		} else if calls := accounting.LookupAPICalls(dst.Fs().Name()); calls != nil && ci.DryRun {
+			calls.Estimate(accounting.APIClassFree, 1, 0)
+		}
 	} else if backupDir != nil {
 		err = MoveBackupDir(ctx, backupDir, dst)
 	} else {
@@ -685,7 +701,7 @@ func DeleteFileWithBackupDir(ctx context.Context, dst fs.Object, backupDir fs.Fs
 	} else if !skip {
 		fs.Infof(dst, actioned)
 	}
-	return err
+	return skip, err
 }
 
 // DeleteFile deletes a single file respecting --dry-run and accumulating stats and errors.
@@ -712,7 +728,16 @@ func DeleteFilesWithBackupDir(ctx context.Context, toBeDeleted fs.ObjectsChan, b
 		go func() {
 			defer wg.Done()
 			for dst := range toBeDeleted {
-				err := DeleteFileWithBackupDir(ctx, dst, backupDir)
+				skip, err := deleteFileWithBackupDir(ctx, dst, backupDir)
+				if skip {
+					reason := "not in source, not deleted as --interactive declined"
+					if ci.DryRun {
+						reason = "not in source, would be deleted without --dry-run"
+					}
+					GetReport(ctx).Add(ReportSkipped, dst, reason, err)
+				} else {
+					GetReport(ctx).Add(ReportDeleted, dst, "not in source", err)
+				}
 				if err != nil {
 					atomic.AddInt32(&errorCount, 1)
 					if fserrors.IsFatalError(err) {
@@ -1423,8 +1448,10 @@ func Rmdirs(ctx context.Context, f fs.Fs, dir string, leaveRoot bool) error {
 	for i := len(toDelete) - 1; i >= 0; i-- {
 		dir := toDelete[i]
 		// If a filter matches the directory then that
-		// directory is a candidate for deletion
-		if !fi.Include(dir+"/", 0, time.Now()) {
+		// directory is a candidate for deletion. Only the path
+		// rules apply as size, age and filter expressions are
+		// for files.
+		if !fi.IncludeRemote(dir + "/") {
 			continue
 		}
 		err = TryRmdir(ctx, f, dir)
@@ -1499,7 +1526,7 @@ func GetCopyDest(ctx context.Context, fdst fs.Fs) (CopyDest []fs.Fs, err error)
 // be copied
 //
 // Returns True if src was copied from --copy-dest
-func copyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CopyDest, backupDir fs.Fs) (NoNeedTransfer bool, err error) {
+func copyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CopyDest, backupDir fs.Fs) (NoNeedTransfer, copied bool, err error) {
 	var remote string
 	if dst == nil {
 		remote = src.Remote()
@@ -1509,11 +1536,11 @@ func copyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CopyDest, bac
 	CopyDestFile, err := CopyDest.NewObject(ctx, remote)
 	switch err {
 	case fs.ErrorObjectNotFound:
-		return false, nil
+		return false, false, nil
 	case nil:
 		break
 	default:
-		return false, err
+		return false, false, err
 	}
 	opt := defaultEqualOpt(ctx)
 	opt.updateModTime = false
@@ -1522,7 +1549,7 @@ func copyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CopyDest, bac
 			if dst != nil && backupDir != nil {
 				err = MoveBackupDir(ctx, backupDir, dst)
 				if err != nil {
-					return false, errors.Wrap(err, "moving to --backup-dir failed")
+					return false, false, errors.Wrap(err, "moving to --backup-dir failed")
 				}
 				// If successful zero out the dstObj as it is no longer there
 				dst = nil
@@ -1530,16 +1557,16 @@ func copyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CopyDest, bac
 			_, err := Copy(ctx, fdst, dst, remote, CopyDestFile)
 			if err != nil {
 				fs.Errorf(src, "Destination found in --copy-dest, error copying")
-				return false, nil
+				return false, false, nil
 			}
 			fs.Debugf(src, "Destination found in --copy-dest, using server-side copy")
-			return true, nil
+			return true, true, nil
 		}
 		fs.Debugf(src, "Unchanged skipping")
-		return true, nil
+		return true, false, nil
 	}
 	fs.Debugf(src, "Destination not found in --copy-dest")
-	return false, nil
+	return false, false, nil
 }
 
 // CompareOrCopyDest checks --compare-dest and --copy-dest to see if src
@@ -1547,23 +1574,31 @@ func copyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CopyDest, bac
 //
 // Returns True if src does not need to be copied
 func CompareOrCopyDest(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CompareOrCopyDest []fs.Fs, backupDir fs.Fs) (NoNeedTransfer bool, err error) {
+	NoNeedTransfer, _, err = CompareOrCopyDestCopied(ctx, fdst, dst, src, CompareOrCopyDest, backupDir)
+	return NoNeedTransfer, err
+}
+
+// CompareOrCopyDestCopied is like CompareOrCopyDest but also returns
+// whether src was put on the destination by a server-side copy from
+// --copy-dest
+func CompareOrCopyDestCopied(ctx context.Context, fdst fs.Fs, dst, src fs.Object, CompareOrCopyDest []fs.Fs, backupDir fs.Fs) (NoNeedTransfer, copied bool, err error) {
 	ci := fs.GetConfig(ctx)
 	if len(ci.CompareDest) > 0 {
 		for _, compareF := range CompareOrCopyDest {
 			NoNeedTransfer, err := compareDest(ctx, dst, src, compareF)
 			if NoNeedTransfer || err != nil {
-				return NoNeedTransfer, err
+				return NoNeedTransfer, false, err
 			}
 		}
 	} else if len(ci.CopyDest) > 0 {
 		for _, copyF := range CompareOrCopyDest {
-			NoNeedTransfer, err := copyDest(ctx, fdst, dst, src, copyF, backupDir)
+			NoNeedTransfer, copied, err := copyDest(ctx, fdst, dst, src, copyF, backupDir)
 			if NoNeedTransfer || err != nil {
-				return NoNeedTransfer, err
+				return NoNeedTransfer, copied, err
 			}
 		}
 	}
-	return false, nil
+	return false, false, nil
 }
 
 // NeedTransfer checks to see if src needs to be copied to dst using
@@ -2217,6 +2252,39 @@ func skipDestructiveChoose(ctx context.Context, subject interface{}, action stri
 	return skip
 }
 
+// estimateAPICalls records the API calls and egress which copying, or
+// moving if move is set, src to fdst would have needed for --dry-run
+func estimateAPICalls(ctx context.Context, fdst fs.Fs, src fs.Object, move bool) {
+	if !fs.GetConfig(ctx).DryRun {
+		return
+	}
+	srcCalls := accounting.LookupAPICalls(src.Fs().Name())
+	dstCalls := accounting.LookupAPICalls(fdst.Name())
+	serverSide := fdst.Features().Copy != nil
+	if move {
+		serverSide = fdst.Features().Move != nil
+	}
+	if serverSide && SameConfig(src.Fs(), fdst) {
+		if dstCalls != nil {
+			dstCalls.Estimate(accounting.APIClassA, 1, 0)
+		}
+		return
+	}
+	if srcCalls != nil {
+		size := src.Size()
+		if size < 0 {
+			size = 0
+		}
+		srcCalls.Estimate(accounting.APIClassB, 1, size)
+		if move {
+			srcCalls.Estimate(accounting.APIClassFree, 1, 0)
+		}
+	}
+	if dstCalls != nil {
+		dstCalls.Estimate(accounting.APIClassA, 1, 0)
+	}
+}
+
 // SkipDestructive should be called whenever rclone is about to do an destructive operation.
 //
 // It will check the --dry-run flag and it will ask the user if the --interactive flag is set.
@@ -0,0 +1,94 @@
+// Package operationsflags implements command line flags to set up
+// reports of what sync, copy and move did
+package operationsflags
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/spf13/pflag"
+)
+
+// ReportOpt contains the file names set by the report flags
+type ReportOpt struct {
+	Combined string // file name for the combined sigil report
+	JSON     string // file name for the JSON report
+}
+
+// AddReportFlags adds the report flags to the cmdFlags command
+func AddReportFlags(cmdFlags *pflag.FlagSet, opt *ReportOpt) {
+	flags.StringVarP(cmdFlags, &opt.Combined, "combined", "", opt.Combined, "Make a combined report of changes to this file")
+	flags.StringVarP(cmdFlags, &opt.JSON, "report", "", opt.JSON, "Make a JSON report of changes with reasons to this file")
+}
+
+// ReportHelp describes the report flags for the help
+var ReportHelp = strings.ReplaceAll(`
+The |--combined| flag will write a file (or stdout if it is |-|)
+which contains all file paths with a symbol and then a space and then
+the path to tell you what happened to it. These are reminiscent of
+diff files and use the same symbols as |rclone check|.
+
+- |+ path| means path was copied as it was missing on the destination
+- |* path| means path was present on the destination but different so was updated
+- |- path| means path was deleted from the destination
+- |> path| means path was renamed on the destination using |--track-renames|
+- |= path| means path was skipped as it did not need transferring
+- |! path| means there was an error transferring or deleting path
+
+The |--report| flag writes one JSON object per line to a file (or
+stdout) with the keys |Action|, |Path|, |Reason|, |Size|, and where
+relevant |From| and |Error|. The |Reason| explains why the file was
+transferred or skipped, for example |new|, |size differs|, |modtime
+differs|, |hash differs| or |unchanged|.
+`, "|", "`")
+
+// ConfigureReport makes a Report from opt and returns a context with
+// the report in it. It returns the context unchanged if no report
+// flags were set. close must be called when the operation is done.
+func ConfigureReport(ctx context.Context, opt *ReportOpt) (newCtx context.Context, close func(), err error) {
+	closers := []io.Closer{}
+	close = func() {
+		for _, closer := range closers {
+			err := closer.Close()
+			if err != nil {
+				fs.Errorf(nil, "Failed to close report output: %v", err)
+			}
+		}
+	}
+
+	open := func(name string, pout *io.Writer) error {
+		if name == "" {
+			return nil
+		}
+		if name == "-" {
+			*pout = os.Stdout
+			return nil
+		}
+		out, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		*pout = out
+		closers = append(closers, out)
+		return nil
+	}
+
+	r := &operations.Report{}
+	if err = open(opt.Combined, &r.Combined); err != nil {
+		close()
+		return nil, nil, err
+	}
+	if err = open(opt.JSON, &r.JSON); err != nil {
+		close()
+		return nil, nil, err
+	}
+	if r.Combined == nil && r.JSON == nil {
+		return ctx, close, nil
+	}
+	return operations.WithReport(ctx, r), close, nil
+}
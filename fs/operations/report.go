@@ -0,0 +1,191 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+)
+
+// ReportAction describes what happened to a file during a sync, copy
+// or move
+type ReportAction string
+
+// Actions which can be recorded in a Report
+const (
+	ReportCopied  ReportAction = "copied"  // file was new on the destination
+	ReportUpdated ReportAction = "updated" // file replaced a different file on the destination
+	ReportDeleted ReportAction = "deleted" // file was deleted from the destination
+	ReportRenamed ReportAction = "renamed" // file was renamed on the destination
+	ReportSkipped ReportAction = "skipped" // file did not need transferring
+	ReportFailed  ReportAction = "failed"  // an error occurred with the file
+)
+
+// Sigil returns the character used to describe the action in a
+// combined report. These are the same as those used by check.
+func (a ReportAction) Sigil() rune {
+	switch a {
+	case ReportCopied:
+		return '+'
+	case ReportUpdated:
+		return '*'
+	case ReportDeleted:
+		return '-'
+	case ReportRenamed:
+		return '>'
+	case ReportSkipped:
+		return '='
+	}
+	return '!'
+}
+
+// ReportEntry is a single record of what happened to a file
+type ReportEntry struct {
+	Action ReportAction
+	Path   string
+	Reason string `json:",omitempty"`
+	Size   int64
+	From   string `json:",omitempty"` // original path if renamed
+	Error  string `json:",omitempty"`
+}
+
+// Report records what a sync, copy or move did to each file.
+//
+// It is passed to the sync routines in the context with WithReport.
+// All methods are safe to call on a nil *Report.
+type Report struct {
+	Combined io.Writer // if set write a sigil and path per file to here
+	JSON     io.Writer // if set write a JSON ReportEntry per file to here
+	Record   bool      // if set keep the entries for Entries()
+
+	// Callback is called with each entry if set
+	Callback func(entry ReportEntry)
+
+	mu      sync.Mutex
+	entries []ReportEntry
+}
+
+// Add an entry to the report
+func (r *Report) Add(action ReportAction, o fs.DirEntry, reason string, err error) {
+	if r == nil {
+		return
+	}
+	entry := ReportEntry{
+		Action: action,
+		Path:   o.Remote(),
+		Reason: reason,
+		Size:   o.Size(),
+	}
+	if err != nil {
+		entry.Action = ReportFailed
+		entry.Error = err.Error()
+	}
+	r.AddEntry(entry)
+}
+
+// AddEntry adds a pre-built entry to the report
+func (r *Report) AddEntry(entry ReportEntry) {
+	if r == nil {
+		return
+	}
+	if r.Combined != nil {
+		syncFprintf(r.Combined, "%c %s\n", entry.Action.Sigil(), entry.Path)
+	}
+	if r.JSON != nil {
+		out, err := json.Marshal(entry)
+		if err != nil {
+			fs.Errorf(entry.Path, "Failed to marshal report entry: %v", err)
+		} else {
+			syncFprintf(r.JSON, "%s\n", out)
+		}
+	}
+	if r.Callback != nil {
+		r.Callback(entry)
+	}
+	if r.Record {
+		r.mu.Lock()
+		r.entries = append(r.entries, entry)
+		r.mu.Unlock()
+	}
+}
+
+// Entries returns a copy of the entries recorded so far if Record
+// was set
+func (r *Report) Entries() []ReportEntry {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]ReportEntry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Context key for the report
+type reportContextKeyType struct{}
+
+var reportContextKey = reportContextKeyType{}
+
+// WithReport returns a new context which will record the actions of
+// sync, copy and move into r
+func WithReport(ctx context.Context, r *Report) context.Context {
+	return context.WithValue(ctx, reportContextKey, r)
+}
+
+// GetReport returns the Report in ctx or nil if there isn't one
+func GetReport(ctx context.Context) *Report {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(reportContextKey).(*Report)
+	return r
+}
+
+// TransferReason returns a short description of why src needs
+// transferring to dst. It should only be called once NeedTransfer has
+// returned true as it doesn't read any hashes.
+func TransferReason(ctx context.Context, dst, src fs.Object) string {
+	ci := fs.GetConfig(ctx)
+	switch {
+	case dst == nil:
+		return "new"
+	case ci.IgnoreTimes:
+		return "ignore times"
+	case sizeDiffers(ctx, src, dst):
+		return "size differs"
+	case ci.CheckSum || ci.SizeOnly:
+		return "hash differs"
+	}
+	modifyWindow := fs.GetModifyWindow(ctx, dst.Fs(), src.Fs())
+	if modifyWindow != fs.ModTimeNotSupported {
+		dt := dst.ModTime(ctx).Sub(src.ModTime(ctx))
+		if dt >= modifyWindow || dt <= -modifyWindow {
+			return "modtime differs"
+		}
+	}
+	return "hash differs"
+}
+
+// SkipReason returns a short description of why src didn't need
+// transferring to dst. It should only be called once NeedTransfer has
+// returned false.
+func SkipReason(ctx context.Context, dst, src fs.Object) string {
+	ci := fs.GetConfig(ctx)
+	if ci.IgnoreExisting {
+		return "exists"
+	}
+	if ci.UpdateOlder {
+		modifyWindow := fs.GetModifyWindow(ctx, dst.Fs(), src.Fs())
+		if modifyWindow == fs.ModTimeNotSupported {
+			modifyWindow = time.Second
+		}
+		if dst.ModTime(ctx).Sub(src.ModTime(ctx)) >= modifyWindow {
+			return "destination newer"
+		}
+	}
+	return "unchanged"
+}
@@ -0,0 +1,284 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/dirtree"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/hash"
+)
+
+// errVersionsReadOnly is returned when trying to modify a VersionsAt Fs
+var errVersionsReadOnly = errors.New("can't modify the files of a remote at a time")
+
+// listVersions calls fn with the versions of each object in f which
+// pass the filters
+func listVersions(ctx context.Context, f fs.Fs, fn func(remote string, versions []fs.ObjectVersion) error) error {
+	doListVersions := f.Features().ListVersions
+	if doListVersions == nil {
+		return errors.Errorf("%v doesn't support versions", f)
+	}
+	fi := filter.GetConfig(ctx)
+	return doListVersions(ctx, "", func(remote string, versions []fs.ObjectVersion) error {
+		v := newestVersion(versions)
+		if v == nil || !fi.Include(remote, v.Size, v.ModTime) {
+			return nil
+		}
+		return fn(remote, versions)
+	})
+}
+
+// newestVersion returns the newest version in versions which isn't a
+// deletion, or nil if there isn't one.
+//
+// Deleted versions have no size or modification time so the object
+// is filtered on the version it had before it was deleted.
+func newestVersion(versions []fs.ObjectVersion) *fs.ObjectVersion {
+	for i := range versions {
+		if !versions[i].Deleted {
+			return &versions[i]
+		}
+	}
+	return nil
+}
+
+// ListVersions lists all the versions of the objects in f to w.
+//
+// Each line has the size, the time the version was made, whether it
+// is the current, an old or a deleted version, its ID and the path.
+func ListVersions(ctx context.Context, f fs.Fs, w io.Writer) error {
+	return listVersions(ctx, f, func(remote string, versions []fs.ObjectVersion) error {
+		for _, v := range versions {
+			status := "old"
+			switch {
+			case v.Deleted:
+				status = "deleted"
+			case v.Current:
+				status = "current"
+			}
+			syncFprintf(w, "%9d %s %-7s %s %s\n", v.Size, v.Time.Local().Format("2006-01-02 15:04:05.000000000"), status, v.ID, remote)
+		}
+		return nil
+	})
+}
+
+// CleanUpVersions deletes the old versions of the objects in f,
+// keeping the newest keep old versions of each one as well as the
+// current version.
+func CleanUpVersions(ctx context.Context, f fs.Fs, keep int) error {
+	doDeleteVersion := f.Features().DeleteVersion
+	if doDeleteVersion == nil {
+		return errors.Errorf("%v doesn't support versions", f)
+	}
+	var errCount int
+	err := listVersions(ctx, f, func(remote string, versions []fs.ObjectVersion) error {
+		kept := 0
+		for _, v := range versions {
+			if v.Current {
+				continue
+			}
+			if !v.Deleted && kept < keep {
+				kept++
+				continue
+			}
+			if SkipDestructive(ctx, remote, fmt.Sprintf("delete version %q", v.ID)) {
+				continue
+			}
+			err := doDeleteVersion(ctx, remote, v.ID)
+			if err != nil {
+				err = fs.CountError(err)
+				fs.Errorf(remote, "Failed to delete version %q: %v", v.ID, err)
+				errCount++
+				continue
+			}
+			fs.Infof(remote, "Deleted version %q from %v", v.ID, v.Time.Local())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if errCount > 0 {
+		return errors.Errorf("failed to delete %d versions", errCount)
+	}
+	return nil
+}
+
+// versionsAtFs is a read only Fs of the objects in an Fs as they
+// were at a time
+type versionsAtFs struct {
+	f        fs.Fs
+	at       time.Time
+	features *fs.Features
+	tree     dirtree.DirTree
+}
+
+// VersionsAt returns a read only Fs of the objects in f as they were
+// at the time given, read from their versions.
+//
+// The objects returned have the modification times of their versions
+// and no hashes.
+func VersionsAt(ctx context.Context, f fs.Fs, at time.Time) (fs.Fs, error) {
+	doListVersions := f.Features().ListVersions
+	if doListVersions == nil || f.Features().OpenVersion == nil {
+		return nil, errors.Errorf("%v doesn't support versions", f)
+	}
+	vf := &versionsAtFs{
+		f:    f,
+		at:   at,
+		tree: dirtree.New(),
+	}
+	vf.features = (&fs.Features{}).Fill(ctx, vf)
+	err := doListVersions(ctx, "", func(remote string, versions []fs.ObjectVersion) error {
+		v := fs.VersionAt(versions, at)
+		if v != nil {
+			vf.tree.AddEntry(&versionObject{
+				f:       vf,
+				remote:  remote,
+				version: *v,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list versions")
+	}
+	return vf, nil
+}
+
+// Name of the remote (as passed into NewFs)
+func (vf *versionsAtFs) Name() string {
+	return vf.f.Name()
+}
+
+// Root of the remote (as passed into NewFs)
+func (vf *versionsAtFs) Root() string {
+	return vf.f.Root()
+}
+
+// String returns a description of the Fs
+func (vf *versionsAtFs) String() string {
+	return fmt.Sprintf("%v at %v", vf.f, vf.at.Local())
+}
+
+// Precision of the ModTimes in this Fs
+func (vf *versionsAtFs) Precision() time.Duration {
+	return vf.f.Precision()
+}
+
+// Hashes returns the supported hash types of the filesystem
+func (vf *versionsAtFs) Hashes() hash.Set {
+	return hash.Set(hash.None)
+}
+
+// Features returns the optional features of this Fs
+func (vf *versionsAtFs) Features() *fs.Features {
+	return vf.features
+}
+
+// List the objects and directories in dir into entries
+func (vf *versionsAtFs) List(ctx context.Context, dir string) (entries fs.DirEntries, err error) {
+	if dir != "" {
+		if _, entry := vf.tree.Find(dir); entry == nil {
+			return nil, fs.ErrorDirNotFound
+		}
+	}
+	return append(entries, vf.tree[dir]...), nil
+}
+
+// NewObject finds the Object at remote
+func (vf *versionsAtFs) NewObject(ctx context.Context, remote string) (fs.Object, error) {
+	_, entry := vf.tree.Find(remote)
+	if o, ok := entry.(fs.Object); ok {
+		return o, nil
+	}
+	return nil, fs.ErrorObjectNotFound
+}
+
+// Put is not supported
+func (vf *versionsAtFs) Put(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) (fs.Object, error) {
+	return nil, errVersionsReadOnly
+}
+
+// Mkdir is not supported
+func (vf *versionsAtFs) Mkdir(ctx context.Context, dir string) error {
+	return errVersionsReadOnly
+}
+
+// Rmdir is not supported
+func (vf *versionsAtFs) Rmdir(ctx context.Context, dir string) error {
+	return errVersionsReadOnly
+}
+
+// versionObject is a version of an object in a versionsAtFs
+type versionObject struct {
+	f       *versionsAtFs
+	remote  string
+	version fs.ObjectVersion
+}
+
+// Fs returns the parent Fs
+func (o *versionObject) Fs() fs.Info {
+	return o.f
+}
+
+// String returns a description of the Object
+func (o *versionObject) String() string {
+	return o.remote
+}
+
+// Remote returns the remote path
+func (o *versionObject) Remote() string {
+	return o.remote
+}
+
+// ModTime returns the modification time of the version
+func (o *versionObject) ModTime(ctx context.Context) time.Time {
+	return o.version.ModTime
+}
+
+// Size returns the size of the version
+func (o *versionObject) Size() int64 {
+	return o.version.Size
+}
+
+// Hash is not supported
+func (o *versionObject) Hash(ctx context.Context, ht hash.Type) (string, error) {
+	return "", hash.ErrUnsupported
+}
+
+// Storable says whether this object can be stored
+func (o *versionObject) Storable() bool {
+	return true
+}
+
+// SetModTime is not supported
+func (o *versionObject) SetModTime(ctx context.Context, t time.Time) error {
+	return errVersionsReadOnly
+}
+
+// Open the version for reading
+func (o *versionObject) Open(ctx context.Context, options ...fs.OpenOption) (io.ReadCloser, error) {
+	return o.f.f.Features().OpenVersion(ctx, o.remote, o.version.ID, options...)
+}
+
+// Update is not supported
+func (o *versionObject) Update(ctx context.Context, in io.Reader, src fs.ObjectInfo, options ...fs.OpenOption) error {
+	return errVersionsReadOnly
+}
+
+// Remove is not supported
+func (o *versionObject) Remove(ctx context.Context) error {
+	return errVersionsReadOnly
+}
+
+// Check the interfaces are satisfied
+var (
+	_ fs.Fs     = (*versionsAtFs)(nil)
+	_ fs.Object = (*versionObject)(nil)
+)
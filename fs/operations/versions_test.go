@@ -0,0 +1,208 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fstest/mockfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionsFs is an Fs which keeps the versions it is given
+type versionsFs struct {
+	fs.Fs
+	features *fs.Features
+	versions map[string][]fs.ObjectVersion
+	contents map[string]string // by version ID
+	deleted  []string
+}
+
+func newVersionsFs(ctx context.Context) *versionsFs {
+	hour := func(h int) time.Time {
+		return time.Date(2021, 3, 1, h, 0, 0, 0, time.UTC)
+	}
+	vf := &versionsFs{
+		Fs: mockfs.NewFs(ctx, "versions", ""),
+		versions: map[string][]fs.ObjectVersion{
+			"a": {
+				{ID: "a3", Time: hour(14), ModTime: hour(13), Size: 2, Current: true},
+				{ID: "a2", Time: hour(12), ModTime: hour(11), Size: 2},
+				{ID: "a1", Time: hour(10), ModTime: hour(9), Size: 1},
+			},
+			"dir/b": {
+				{ID: "b3", Time: hour(15), Current: true, Deleted: true},
+				{ID: "b2", Time: hour(13), ModTime: hour(13), Size: 3},
+				{ID: "b1", Time: hour(11), ModTime: hour(11), Size: 3},
+			},
+		},
+		contents: map[string]string{
+			"a1": "a",
+			"a2": "aa",
+			"a3": "AA",
+			"b1": "bbb",
+			"b2": "BBB",
+		},
+	}
+	vf.features = (&fs.Features{}).Fill(ctx, vf)
+	return vf
+}
+
+// Features returns the optional features of this Fs
+func (vf *versionsFs) Features() *fs.Features {
+	return vf.features
+}
+
+// ListVersions lists the versions sorted by remote
+func (vf *versionsFs) ListVersions(ctx context.Context, dir string, callback func(remote string, versions []fs.ObjectVersion) error) error {
+	var remotes []string
+	for remote := range vf.versions {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+	for _, remote := range remotes {
+		err := callback(remote, vf.versions[remote])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenVersion opens the contents of the version
+func (vf *versionsFs) OpenVersion(ctx context.Context, remote string, versionID string, options ...fs.OpenOption) (io.ReadCloser, error) {
+	contents, ok := vf.contents[versionID]
+	if !ok {
+		return nil, fs.ErrorObjectNotFound
+	}
+	return ioutil.NopCloser(strings.NewReader(contents)), nil
+}
+
+// RestoreVersion isn't needed for these tests
+func (vf *versionsFs) RestoreVersion(ctx context.Context, remote string, versionID string) error {
+	return errors.New("not implemented")
+}
+
+// DeleteVersion records the version as deleted
+func (vf *versionsFs) DeleteVersion(ctx context.Context, remote string, versionID string) error {
+	vf.deleted = append(vf.deleted, versionID)
+	return nil
+}
+
+func TestListVersions(t *testing.T) {
+	ctx := context.Background()
+	vf := newVersionsFs(ctx)
+	var buf bytes.Buffer
+	require.NoError(t, ListVersions(ctx, vf, &buf))
+	var want []string
+	for _, remote := range []string{"a", "dir/b"} {
+		for _, v := range vf.versions[remote] {
+			status := map[string]string{"a3": "current", "b3": "deleted"}[v.ID]
+			if status == "" {
+				status = "old"
+			}
+			want = append(want, fmt.Sprintf("%9d %s %-7s %s %s", v.Size, v.Time.Local().Format("2006-01-02 15:04:05.000000000"), status, v.ID, remote))
+		}
+	}
+	assert.Equal(t, strings.Join(want, "\n")+"\n", buf.String())
+
+	// Check the filters are applied
+	ctx, fi := filter.AddConfig(ctx)
+	require.NoError(t, fi.AddRule("- dir/**"))
+	buf.Reset()
+	require.NoError(t, ListVersions(ctx, vf, &buf))
+	assert.Equal(t, strings.Join(want[:3], "\n")+"\n", buf.String())
+
+	// Check deleted objects are filtered on their last version
+	ctx, fi = filter.AddConfig(context.Background())
+	fi.Opt.MinSize = 3
+	buf.Reset()
+	require.NoError(t, ListVersions(ctx, vf, &buf))
+	assert.Equal(t, strings.Join(want[3:], "\n")+"\n", buf.String())
+
+	// Check an Fs without versions
+	err := ListVersions(ctx, mockfs.NewFs(ctx, "plain", ""), &buf)
+	assert.Error(t, err)
+}
+
+func TestCleanUpVersions(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		keep int
+		want []string
+	}{
+		{0, []string{"a2", "a1", "b2", "b1"}},
+		{1, []string{"a1", "b1"}},
+		{2, nil},
+	} {
+		vf := newVersionsFs(ctx)
+		require.NoError(t, CleanUpVersions(ctx, vf, test.keep))
+		assert.Equal(t, test.want, vf.deleted, "keep=%d", test.keep)
+	}
+
+	// Check --dry-run doesn't delete anything
+	ctx, ci := fs.AddConfig(ctx)
+	ci.DryRun = true
+	vf := newVersionsFs(ctx)
+	require.NoError(t, CleanUpVersions(ctx, vf, 0))
+	assert.Nil(t, vf.deleted)
+}
+
+func TestVersionsAt(t *testing.T) {
+	ctx := context.Background()
+	vf := newVersionsFs(ctx)
+	hour := func(h int) time.Time {
+		return time.Date(2021, 3, 1, h, 0, 0, 0, time.UTC)
+	}
+	read := func(o fs.Object) string {
+		in, err := o.Open(ctx)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(in)
+		require.NoError(t, err)
+		require.NoError(t, in.Close())
+		return string(data)
+	}
+
+	f, err := VersionsAt(ctx, vf, hour(12))
+	require.NoError(t, err)
+	entries, err := f.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(entries))
+	assert.Equal(t, "a", entries[0].Remote())
+	assert.Equal(t, "dir", entries[1].Remote())
+	o, err := f.NewObject(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "aa", read(o))
+	assert.Equal(t, int64(2), o.Size())
+	assert.Equal(t, hour(11), o.ModTime(ctx))
+	o, err = f.NewObject(ctx, "dir/b")
+	require.NoError(t, err)
+	assert.Equal(t, "bbb", read(o))
+	_, err = f.List(ctx, "potato")
+	assert.Equal(t, fs.ErrorDirNotFound, err)
+	_, err = f.NewObject(ctx, "dir")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+	assert.Equal(t, errVersionsReadOnly, o.Remove(ctx))
+
+	// dir/b was deleted by then
+	f, err = VersionsAt(ctx, vf, hour(16))
+	require.NoError(t, err)
+	entries, err = f.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	o, err = f.NewObject(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "AA", read(o))
+	_, err = f.NewObject(ctx, "dir/b")
+	assert.Equal(t, fs.ErrorObjectNotFound, err)
+}
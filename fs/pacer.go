@@ -4,6 +4,8 @@ package fs
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rclone/rclone/fs/fserrors"
@@ -15,6 +17,32 @@ type Pacer struct {
 	*pacer.Pacer
 }
 
+// lowLevelRetries counts the low level retries made by the pacers of
+// each remote by the name of the remote
+var (
+	lowLevelRetriesMu sync.Mutex
+	lowLevelRetries   = map[string]*int64{}
+)
+
+// retryCounter returns the low level retry counter for remoteName
+func retryCounter(remoteName string) *int64 {
+	lowLevelRetriesMu.Lock()
+	defer lowLevelRetriesMu.Unlock()
+	counter := lowLevelRetries[remoteName]
+	if counter == nil {
+		counter = new(int64)
+		lowLevelRetries[remoteName] = counter
+	}
+	return counter
+}
+
+// LowLevelRetries returns the number of low level retries made by the
+// pacers of the remote called remoteName so far. A rising count means
+// the remote is throttling.
+func LowLevelRetries(remoteName string) int64 {
+	return atomic.LoadInt64(retryCounter(remoteName))
+}
+
 type logCalculator struct {
 	pacer.Calculator
 }
@@ -26,9 +54,13 @@ func NewPacer(ctx context.Context, c pacer.Calculator) *Pacer {
 	if retries <= 0 {
 		retries = 1
 	}
+	counter := retryCounter(RemoteName(ctx))
+	invoker := func(try, retries int, f pacer.Paced) (bool, error) {
+		return pacerInvoker(counter, try, retries, f)
+	}
 	p := &Pacer{
 		Pacer: pacer.New(
-			pacer.InvokerOption(pacerInvoker),
+			pacer.InvokerOption(invoker),
 			pacer.MaxConnectionsOption(ci.Checkers+ci.Transfers),
 			pacer.RetriesOption(retries),
 			pacer.CalculatorOption(c),
@@ -84,9 +116,10 @@ func (p *Pacer) ModifyCalculator(f func(pacer.Calculator)) {
 	})
 }
 
-func pacerInvoker(try, retries int, f pacer.Paced) (retry bool, err error) {
+func pacerInvoker(counter *int64, try, retries int, f pacer.Paced) (retry bool, err error) {
 	retry, err = f()
 	if retry {
+		atomic.AddInt64(counter, 1)
 		Debugf("pacer", "low level retry %d/%d (error %v)", try, retries, err)
 		err = fserrors.RetryError(err)
 	}
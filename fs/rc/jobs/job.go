@@ -213,6 +213,10 @@ func getConfig(ctx context.Context, in rc.Params) (context.Context, error) {
 	if err != nil {
 		return ctx, err
 	}
+	err = ci.FixConcurrency()
+	if err != nil {
+		return ctx, err
+	}
 	delete(in, "_config") // remove the parameter
 	return ctx, nil
 }
@@ -289,6 +289,9 @@ func TestExecuteJobWithFilter(t *testing.T) {
 		fi := filter.GetConfig(ctx)
 		assert.Equal(t, fs.SizeSuffix(1024), fi.Opt.MaxSize)
 		assert.Equal(t, []string{"a", "b", "c"}, fi.Opt.IncludeRule)
+		assert.Equal(t, []string{"size < 100B"}, fi.Opt.FilterExpr)
+		assert.True(t, fi.Include("a", 10, time.Now()))
+		assert.False(t, fi.Include("a", 200, time.Now()))
 		called = true
 		return nil, nil
 	}
@@ -296,6 +299,7 @@ func TestExecuteJobWithFilter(t *testing.T) {
 		"_filter": rc.Params{
 			"IncludeRule": []string{"a", "b", "c"},
 			"MaxSize":     "1k",
+			"FilterExpr":  []string{"size < 100B"},
 		},
 	})
 	require.NoError(t, err)
@@ -5,12 +5,14 @@ import (
 	"github.com/rclone/rclone/cmd/serve/httplib/httpflags"
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/fs/rc"
+	"github.com/rclone/rclone/lib/http/share"
 	"github.com/spf13/pflag"
 )
 
 // Options set by command line flags
 var (
-	Opt = rc.DefaultOpt
+	Opt      = rc.DefaultOpt
+	ShareOpt = share.DefaultOpt
 )
 
 // AddFlags adds the remote control flags to the flagSet
@@ -30,4 +32,5 @@ func AddFlags(flagSet *pflag.FlagSet) {
 	flags.DurationVarP(flagSet, &Opt.JobExpireDuration, "rc-job-expire-duration", "", Opt.JobExpireDuration, "expire finished async jobs older than this value")
 	flags.DurationVarP(flagSet, &Opt.JobExpireInterval, "rc-job-expire-interval", "", Opt.JobExpireInterval, "interval to check for expired async jobs")
 	httpflags.AddFlagsPrefix(flagSet, "rc-", &Opt.HTTPOptions)
+	share.AddFlagsPrefix(flagSet, "rc-", &ShareOpt)
 }
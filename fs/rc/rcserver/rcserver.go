@@ -35,6 +35,7 @@ import (
 	"github.com/rclone/rclone/fs/rc/jobs"
 	"github.com/rclone/rclone/fs/rc/rcflags"
 	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/rclone/rclone/lib/http/share"
 	"github.com/rclone/rclone/lib/random"
 )
 
@@ -67,6 +68,7 @@ type Server struct {
 	files          http.Handler
 	pluginsHandler http.Handler
 	opt            *rc.Options
+	shares         *share.Manager // share links if enabled
 }
 
 func newServer(ctx context.Context, opt *rc.Options, mux *http.ServeMux) *Server {
@@ -113,13 +115,29 @@ func newServer(ctx context.Context, opt *rc.Options, mux *http.ServeMux) *Server
 		pluginsHandler = http.FileServer(http.Dir(webgui.PluginsPath))
 	}
 
+	// Share links are read without auth
+	httpOpt := opt.HTTPOptions
+	if rcflags.ShareOpt.Enabled {
+		httpOpt.PublicPaths = append(append([]string{}, httpOpt.PublicPaths...), share.Prefix)
+	}
+
 	s := &Server{
-		Server:         httplib.NewServer(mux, &opt.HTTPOptions),
+		Server:         httplib.NewServer(mux, &httpOpt),
 		ctx:            ctx,
 		opt:            opt,
 		files:          fileHandler,
 		pluginsHandler: pluginsHandler,
 	}
+	var err error
+	s.shares, err = share.New("rcd", &rcflags.ShareOpt, func(ctx context.Context, fsString string) (fs.Fs, error) {
+		if fsString == "" {
+			return nil, errors.New("need fs parameter")
+		}
+		return cache.Get(ctx, fsString)
+	}, s.URL)
+	if err != nil {
+		log.Fatalf("Failed to set up share links: %v", err)
+	}
 	mux.HandleFunc("/", s.handler)
 
 	return s
@@ -358,6 +376,10 @@ func (s *Server) handleGet(w http.ResponseWriter, r *http.Request, path string)
 	fsMatchResult := fsMatch.FindStringSubmatch(path)
 
 	switch {
+	case s.shares != nil && strings.HasPrefix(path, share.Prefix):
+		// Serve share links
+		s.shares.ServeHTTP(w, r)
+		return
 	case fsMatchResult != nil && s.opt.Serve:
 		// Serve /[fs]/remote files
 		s.serveRemote(w, r, fsMatchResult[2], fsMatchResult[1])
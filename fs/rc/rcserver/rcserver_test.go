@@ -3,6 +3,7 @@ package rcserver
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -22,6 +23,7 @@ import (
 	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/config/configfile"
 	"github.com/rclone/rclone/fs/rc"
+	"github.com/rclone/rclone/fs/rc/rcflags"
 )
 
 const (
@@ -781,3 +783,58 @@ func TestRCAsync(t *testing.T) {
 	opt.Files = ""
 	testServer(t, tests, &opt)
 }
+
+func TestShareLinks(t *testing.T) {
+	rcflags.ShareOpt.Enabled = true
+	defer func() { rcflags.ShareOpt.Enabled = false }()
+	opt := rc.DefaultOpt
+	opt.HTTPOptions.ListenAddr = testBindAddress
+	opt.HTTPOptions.BasicUser = "user"
+	opt.HTTPOptions.BasicPass = "pass"
+	opt.Enabled = true
+	mux := http.NewServeMux()
+	rcServer := newServer(context.Background(), &opt, mux)
+	require.NoError(t, rcServer.Serve())
+	defer func() {
+		rcServer.Close()
+		rcServer.Wait()
+	}()
+	testURL := rcServer.Server.URL()
+
+	get := func(url string) (int, string) {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, string(body)
+	}
+
+	// The rc still needs auth
+	req, err := http.NewRequest("POST", testURL+"share/create", strings.NewReader(`{"fs":"`+testFs+`","remote":"file.txt"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	req.SetBasicAuth("user", "pass")
+	req.Body = ioutil.NopCloser(strings.NewReader(`{"fs":"` + testFs + `","remote":"file.txt"}`))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var out rc.Params
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, out)
+
+	// But the link doesn't
+	url := out["url"].(string)
+	assert.True(t, strings.HasPrefix(url, testURL+"share/"), url)
+	status, body := get(url)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "this is file1.txt\n", body)
+
+	// And can't be used to reach anything else
+	status, _ = get(testURL + "share/../rc/list")
+	assert.NotEqual(t, http.StatusOK, status)
+}
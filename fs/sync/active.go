@@ -0,0 +1,159 @@
+package sync
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/operations"
+)
+
+// activeSyncs are the syncs which are running by stats group so their
+// queues can be inspected and changed with the rc
+var (
+	activeSyncsMu sync.Mutex
+	activeSyncs   = map[*syncCopyMove]string{}
+)
+
+// register s as running in the stats group in ctx
+func (s *syncCopyMove) register(ctx context.Context) {
+	group, _ := accounting.StatsGroupFromContext(ctx)
+	activeSyncsMu.Lock()
+	activeSyncs[s] = group
+	activeSyncsMu.Unlock()
+}
+
+// unregister s when it has finished
+func (s *syncCopyMove) unregister() {
+	activeSyncsMu.Lock()
+	delete(activeSyncs, s)
+	activeSyncsMu.Unlock()
+}
+
+// findSyncs returns the running syncs in group, or all of them if
+// group is "", sorted by group.
+func findSyncs(group string) (syncs []*syncCopyMove, groups []string) {
+	activeSyncsMu.Lock()
+	defer activeSyncsMu.Unlock()
+	for s, g := range activeSyncs {
+		if group == "" || g == group {
+			syncs = append(syncs, s)
+		}
+	}
+	sort.Slice(syncs, func(i, j int) bool {
+		return activeSyncs[syncs[i]] < activeSyncs[syncs[j]]
+	})
+	for _, s := range syncs {
+		groups = append(groups, activeSyncs[s])
+	}
+	return syncs, groups
+}
+
+// priority returns the priority of pair in the pipes, using the one
+// set with the rc if there is one, otherwise the --priority rules.
+func (s *syncCopyMove) priority(pair fs.ObjectPair) int {
+	remote := pair.Src.Remote()
+	s.prioritiesMu.Lock()
+	priority, ok := s.priorities[remote]
+	s.prioritiesMu.Unlock()
+	if ok {
+		return priority
+	}
+	return s.fi.PriorityObject(pair.Src)
+}
+
+// stage is a named pipe of the sync
+type stage struct {
+	name string
+	p    *pipe
+}
+
+// stages returns the pipes of the sync in the order items go through
+// them
+func (s *syncCopyMove) stages() []stage {
+	return []stage{
+		{"check", s.toBeChecked},
+		{"transfer", s.toBeUploaded},
+		{"rename", s.toBeRenamed},
+	}
+}
+
+// QueuedItem describes a file waiting in a sync for a checker,
+// transfer or rename
+type QueuedItem struct {
+	Group    string `json:"group"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Priority int    `json:"priority"`
+	Queue    string `json:"queue"`
+}
+
+// Queued returns the items waiting in the syncs running in group, or
+// all of them if group is "", in roughly the order they will be
+// processed.
+func Queued(group string) (items []QueuedItem) {
+	syncs, groups := findSyncs(group)
+	for i, s := range syncs {
+		for _, st := range s.stages() {
+			for _, item := range st.p.Pending() {
+				items = append(items, QueuedItem{
+					Group:    groups[i],
+					Name:     item.pair.Src.Remote(),
+					Size:     item.pair.Src.Size(),
+					Priority: item.priority,
+					Queue:    st.name,
+				})
+			}
+		}
+	}
+	return items
+}
+
+// SetPriority sets the priority of the file called remote in the
+// syncs running in group, or all of them if group is "".
+//
+// The priority sticks to the file as it moves from the checkers to the
+// transfers. It returns false if the file wasn't found queued, in
+// which case no priority is stored.
+func SetPriority(group, remote string, priority int) (found bool) {
+	syncs, _ := findSyncs(group)
+	for _, s := range syncs {
+		queued := false
+		for _, st := range s.stages() {
+			if st.p.SetPriority(remote, priority) {
+				queued = true
+			}
+		}
+		if !queued {
+			continue
+		}
+		found = true
+		s.prioritiesMu.Lock()
+		s.priorities[remote] = priority
+		s.prioritiesMu.Unlock()
+	}
+	return found
+}
+
+// Cancel removes the file called remote from the queues of the syncs
+// running in group, or all of them if group is "", so it won't be
+// checked or transferred.
+//
+// It returns false if the file wasn't found queued.
+func Cancel(group, remote string) (found bool) {
+	syncs, _ := findSyncs(group)
+	for _, s := range syncs {
+		for _, st := range s.stages() {
+			pair, ok := st.p.Remove(remote)
+			if !ok {
+				continue
+			}
+			found = true
+			fs.Logf(pair.Src, "Cancelled queued %s", st.name)
+			s.report.Add(operations.ReportSkipped, pair.Src, "cancelled", nil)
+		}
+	}
+	return found
+}
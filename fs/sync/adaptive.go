@@ -0,0 +1,300 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+)
+
+const (
+	adaptiveGain = 1.05 // throughput must rise by this factor to keep a new worker
+	adaptiveHold = 3    // intervals to wait after shrinking before growing again
+)
+
+// workerGate limits how many workers of a pool take work. Workers
+// whose slot is at or above the limit wait until it is raised or the
+// gate is closed.
+//
+// A nil gate lets every worker run.
+type workerGate struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	closed bool
+}
+
+func newWorkerGate(limit int) *workerGate {
+	g := &workerGate{limit: limit}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+// wait blocks until the worker in slot may take work
+//
+// It returns false if the gate was closed while the worker was
+// parked, in which case the worker should exit.
+func (g *workerGate) wait(slot int) bool {
+	if g == nil {
+		return true
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for slot >= g.limit && !g.closed {
+		g.cond.Wait()
+	}
+	return slot < g.limit
+}
+
+// setLimit sets the number of workers which may take work
+func (g *workerGate) setLimit(limit int) {
+	g.mu.Lock()
+	g.limit = limit
+	g.mu.Unlock()
+	g.cond.Broadcast()
+}
+
+// close releases any parked workers so they can exit
+func (g *workerGate) close() {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+	g.cond.Broadcast()
+}
+
+// adaptivePool is a pool of workers whose active size is tuned by
+// the adaptiveController
+type adaptivePool struct {
+	name       string
+	gate       *workerGate
+	min        int
+	max        int
+	active     int
+	done       func() int64 // how much work has been done so far
+	queued     func() int   // how many items are waiting for a worker
+	lastDone   int64        // done at the previous adjustment
+	lastRate   float64      // work per second at the previous adjustment
+	lastActive int          // active at the previous adjustment
+	hold       int          // adjustments left before growing is allowed
+}
+
+func newAdaptivePool(name string, min, max int, done func() int64, queued func() int) *adaptivePool {
+	if max < 1 {
+		max = 1
+	}
+	if min < 1 {
+		min = 1
+	}
+	if min > max {
+		min = max
+	}
+	active := min + (max-min)/2
+	return &adaptivePool{
+		name:       name,
+		gate:       newWorkerGate(active),
+		min:        min,
+		max:        max,
+		active:     active,
+		done:       done,
+		queued:     queued,
+		lastActive: active,
+	}
+}
+
+// adjust works out the number of active workers for the next
+// interval from the work done in the last one of length dt.
+//
+// It shrinks the pool by a quarter if the remote was throttling,
+// otherwise it adds a worker at a time while that raises the
+// throughput, taking it away again if it didn't.
+//
+// It returns a description of the change or "" if there wasn't one.
+func (p *adaptivePool) adjust(throttled bool, dt time.Duration) (reason string) {
+	done := p.done()
+	rate := float64(done-p.lastDone) / dt.Seconds()
+	p.lastDone = done
+	active := p.active
+	switch {
+	case throttled:
+		active -= (active + 3) / 4
+		p.hold = adaptiveHold
+		reason = "throttled"
+	case p.hold > 0:
+		p.hold--
+	case p.active > p.lastActive && rate < p.lastRate*adaptiveGain:
+		active = p.lastActive
+		p.hold = adaptiveHold
+		reason = "no throughput gain"
+	case p.queued() == 0 || rate == 0:
+		// more workers won't help
+	default:
+		active++
+		reason = "throughput rising"
+	}
+	if active < p.min {
+		active = p.min
+	}
+	if active > p.max {
+		active = p.max
+	}
+	p.lastRate, p.lastActive = rate, p.active
+	if active == p.active {
+		return ""
+	}
+	reason = fmt.Sprintf("%s %d -> %d: %s", p.name, p.active, active, reason)
+	p.active = active
+	p.gate.setLimit(active)
+	return reason
+}
+
+// adaptiveController periodically tunes the number of active
+// transfers and checkers for --adaptive-concurrency
+type adaptiveController struct {
+	f           fs.Fs
+	remotes     []string // names of the remotes whose retries count
+	stats       *accounting.StatsInfo
+	interval    time.Duration
+	transfers   *adaptivePool
+	checkers    *adaptivePool
+	lastRetries int64
+	lastErrors  int64
+	reason      string
+	stop        chan struct{}
+	wg          sync.WaitGroup
+}
+
+// newAdaptiveController makes an adaptiveController for s if
+// --adaptive-concurrency is set, or returns nil if not.
+//
+// It must be called after the pipes have been made.
+func newAdaptiveController(ctx context.Context, s *syncCopyMove) *adaptiveController {
+	ci := s.ci
+	if !ci.AdaptiveConcurrency {
+		return nil
+	}
+	stats := accounting.Stats(ctx)
+	queued := func(p *pipe) func() int {
+		return func() int {
+			items, _ := p.Stats()
+			return items
+		}
+	}
+	return &adaptiveController{
+		f:         s.fdst,
+		remotes:   remoteNames(s.fsrc, s.fdst),
+		stats:     stats,
+		interval:  ci.AdaptiveInterval,
+		transfers: newAdaptivePool("transfers", ci.MinTransfers, ci.Transfers, stats.GetBytes, queued(s.toBeUploaded)),
+		checkers:  newAdaptivePool("checkers", ci.MinCheckers, ci.Checkers, stats.GetChecks, queued(s.toBeChecked)),
+		reason:    "starting",
+		stop:      make(chan struct{}),
+	}
+}
+
+// start tuning in the background
+func (a *adaptiveController) start() {
+	if a == nil {
+		return
+	}
+	fs.Infof(a.f, "Adaptive concurrency: starting with %d/%d transfers and %d/%d checkers",
+		a.transfers.active, a.transfers.max, a.checkers.active, a.checkers.max)
+	a.lastRetries = a.retries()
+	a.lastErrors = a.stats.GetErrors()
+	a.transfers.lastDone = a.transfers.done()
+	a.checkers.lastDone = a.checkers.done()
+	a.update()
+	a.wg.Add(1)
+	go a.run()
+}
+
+// run the tuning loop until stopped
+func (a *adaptiveController) run() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			a.tick()
+		}
+	}
+}
+
+// remoteNames returns the names of the remotes fses use, including
+// the remotes they wrap
+func remoteNames(fses ...fs.Fs) (names []string) {
+	seen := map[string]bool{}
+	for _, f := range fses {
+		for _, name := range []string{f.Name(), fs.UnWrapFs(f).Name()} {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// retries returns the low level retries made so far by the remotes
+// of this sync
+func (a *adaptiveController) retries() (n int64) {
+	for _, name := range a.remotes {
+		n += fs.LowLevelRetries(name)
+	}
+	return n
+}
+
+// tick adjusts both pools once
+func (a *adaptiveController) tick() {
+	retries := a.retries()
+	errors := a.stats.GetErrors()
+	throttled := retries > a.lastRetries || errors > a.lastErrors
+	a.lastRetries, a.lastErrors = retries, errors
+	for _, p := range []*adaptivePool{a.transfers, a.checkers} {
+		if reason := p.adjust(throttled, a.interval); reason != "" {
+			fs.Infof(a.f, "Adaptive concurrency: %s", reason)
+			a.reason = reason
+		}
+	}
+	a.update()
+}
+
+// update the stats with the current state
+func (a *adaptiveController) update() {
+	a.stats.SetConcurrency(a.transfers.active, a.checkers.active, a.reason)
+}
+
+// stop tuning and wait for the background goroutine to finish
+func (a *adaptiveController) stopTuning() {
+	if a == nil {
+		return
+	}
+	close(a.stop)
+	a.wg.Wait()
+	// the concurrency no longer applies
+	a.stats.SetConcurrency(0, 0, "")
+}
+
+// transferGate returns the gate for the transfers or nil if not tuning
+func (a *adaptiveController) transferGate() *workerGate {
+	if a == nil {
+		return nil
+	}
+	return a.transfers.gate
+}
+
+// checkerGate returns the gate for the checkers or nil if not tuning
+func (a *adaptiveController) checkerGate() *workerGate {
+	if a == nil {
+		return nil
+	}
+	return a.checkers.gate
+}
@@ -0,0 +1,131 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerGate(t *testing.T) {
+	var nilGate *workerGate
+	assert.True(t, nilGate.wait(100))
+	nilGate.close()
+
+	g := newWorkerGate(1)
+	assert.True(t, g.wait(0))
+
+	var wg sync.WaitGroup
+	results := make(chan bool, 2)
+	for slot := 1; slot <= 2; slot++ {
+		wg.Add(1)
+		go func(slot int) {
+			defer wg.Done()
+			results <- g.wait(slot)
+		}(slot)
+	}
+	select {
+	case <-results:
+		t.Fatal("worker above the limit should be parked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Raising the limit lets slot 1 through
+	g.setLimit(2)
+	assert.True(t, <-results)
+
+	// Closing the gate makes slot 2 exit
+	g.close()
+	assert.False(t, <-results)
+	wg.Wait()
+
+	// Slots under the limit still run after close to drain the queue
+	assert.True(t, g.wait(0))
+}
+
+func TestAdaptivePoolAdjust(t *testing.T) {
+	var done int64
+	queued := 10
+	p := newAdaptivePool("transfers", 2, 8, func() int64 { return done }, func() int { return queued })
+	assert.Equal(t, 5, p.active)
+	assert.Equal(t, 5, p.gate.limit)
+
+	// Throughput steady so probe with one more worker
+	done += 100
+	assert.Equal(t, "transfers 5 -> 6: throughput rising", p.adjust(false, time.Second))
+	assert.Equal(t, 6, p.gate.limit)
+
+	// The extra worker helped so keep going
+	done += 200
+	assert.Equal(t, "transfers 6 -> 7: throughput rising", p.adjust(false, time.Second))
+
+	// The extra worker didn't help so take it away again
+	done += 201
+	assert.Equal(t, "transfers 7 -> 6: no throughput gain", p.adjust(false, time.Second))
+
+	// Hold for a while after backing off
+	for i := 0; i < adaptiveHold; i++ {
+		done += 200
+		assert.Equal(t, "", p.adjust(false, time.Second))
+	}
+
+	// Nothing queued so no point growing
+	queued = 0
+	done += 200
+	assert.Equal(t, "", p.adjust(false, time.Second))
+	queued = 10
+
+	// Throttling shrinks by a quarter but not below min
+	assert.Equal(t, "transfers 6 -> 4: throttled", p.adjust(true, time.Second))
+	assert.Equal(t, "transfers 4 -> 3: throttled", p.adjust(true, time.Second))
+	assert.Equal(t, "transfers 3 -> 2: throttled", p.adjust(true, time.Second))
+	assert.Equal(t, "", p.adjust(true, time.Second))
+	assert.Equal(t, 2, p.gate.limit)
+
+	// There is always at least one worker
+	p = newAdaptivePool("checkers", 0, 0, func() int64 { return done }, func() int { return queued })
+	assert.Equal(t, 1, p.min)
+	assert.Equal(t, 1, p.active)
+	assert.Equal(t, "", p.adjust(true, time.Second))
+	assert.Equal(t, 1, p.gate.limit)
+}
+
+// Test a sync with --adaptive-concurrency runs all the files through
+// the reduced pools and reports them in the stats
+func TestSyncAdaptiveConcurrency(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+
+	ci.AdaptiveConcurrency = true
+	ci.AdaptiveInterval = time.Hour
+	ci.Transfers = 4
+	ci.Checkers = 4
+	// should be treated as 1
+	ci.MinTransfers = 0
+	ci.MinCheckers = 0
+
+	var items []fstest.Item
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		items = append(items, r.WriteFile(name, "content "+name, t1))
+	}
+	fstest.CheckItems(t, r.Flocal, items...)
+
+	accounting.GlobalStats().ResetCounters()
+	err := Sync(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, items...)
+
+	// The concurrency is cleared from the stats when the sync ends
+	out, err := accounting.GlobalStats().RemoteStats()
+	require.NoError(t, err)
+	assert.NotContains(t, out, "activeTransfers")
+	assert.NotContains(t, out, "activeCheckers")
+}
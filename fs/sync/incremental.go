@@ -0,0 +1,227 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/walk"
+)
+
+// incrementalState is the state saved in the --incremental-state file
+type incrementalState struct {
+	// Cursors are the change cursors of the sources read before
+	// the last successful run, by source and destination
+	Cursors map[string]string `json:"cursors"`
+}
+
+// incrementalKey returns the key for the cursor of syncing fsrc to
+// fdst. It includes whether this is a sync or a copy and the filters
+// so changing either of those does a full sync.
+func incrementalKey(fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, fi *filter.Filter) string {
+	mode := "sync"
+	if deleteMode == fs.DeleteModeOff {
+		mode = "copy"
+	}
+	return mode + " " + fs.ConfigString(fsrc) + " -> " + fs.ConfigString(fdst) + " filter " + fi.Fingerprint()
+}
+
+// readIncrementalState reads the state from file, returning an empty
+// state if it doesn't exist
+func readIncrementalState(file string) (*incrementalState, error) {
+	state := &incrementalState{}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		state.Cursors = map[string]string{}
+		return state, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read incremental state")
+	}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse incremental state %q", file)
+	}
+	if state.Cursors == nil {
+		state.Cursors = map[string]string{}
+	}
+	return state, nil
+}
+
+// write the state to file, replacing it atomically so an interrupted
+// write doesn't lose the cursors
+func (state *incrementalState) write(file string) (err error) {
+	data, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to make incremental state")
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to write incremental state")
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	_, err = tmp.Write(data)
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to write incremental state")
+	}
+	return nil
+}
+
+// listObjects calls fn with all the objects in dir in f, ignoring
+// filters.
+//
+// It returns fs.ErrorDirNotFound or fs.ErrorIsFile if dir isn't a
+// directory without counting it as an error.
+func listObjects(ctx context.Context, f fs.Fs, dir string, fn func(o fs.Object)) error {
+	var dirErr error
+	err := walk.Walk(ctx, f, dir, true, -1, func(path string, entries fs.DirEntries, err error) error {
+		if path == dir && (err == fs.ErrorDirNotFound || err == fs.ErrorIsFile) {
+			dirErr = err
+			return nil
+		} else if err != nil {
+			return err
+		}
+		entries.ForObject(fn)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return dirErr
+}
+
+// listChangedRemotes lists the remotes which may have changed in
+// fsrc since cursor.
+//
+// Changed directories are listed in fsrc and fdst to find the
+// remotes in them.
+func listChangedRemotes(ctx context.Context, fdst, fsrc fs.Fs, cursor string) (remotes []string, newCursor string, err error) {
+	found := map[string]struct{}{}
+	var dirs []string
+	newCursor, err = fsrc.Features().ListChanges(ctx, cursor, func(remote string, entryType fs.EntryType) error {
+		found[remote] = struct{}{}
+		if entryType == fs.EntryDirectory {
+			dirs = append(dirs, remote)
+		}
+		return nil
+	})
+	if err != nil && err != fs.ErrorChangesIncomplete {
+		return nil, "", err
+	}
+	listErr := err
+	for _, dir := range dirs {
+		isDir := false
+		for _, f := range []fs.Fs{fsrc, fdst} {
+			err = listObjects(ctx, f, dir, func(o fs.Object) {
+				found[o.Remote()] = struct{}{}
+			})
+			if err == nil {
+				isDir = true
+			} else if err != fs.ErrorDirNotFound && err != fs.ErrorIsFile {
+				return nil, "", errors.Wrapf(err, "failed to list changed directory %q", dir)
+			}
+		}
+		// A changed path which isn't a directory on either side
+		// may have been a file
+		if isDir {
+			delete(found, dir)
+		}
+	}
+	for remote := range found {
+		if remote != "" {
+			remotes = append(remotes, remote)
+		}
+	}
+	sort.Strings(remotes)
+	return remotes, newCursor, listErr
+}
+
+// runIncremental syncs or copies fsrc to fdst using the change
+// cursor saved in the --incremental-state file to only look at the
+// remotes changed since the last successful run.
+//
+// If there is no cursor or it can't be used then run is called to
+// do a full sync. The new cursor is only saved if the sync succeeds.
+func runIncremental(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.DeleteMode, run func(ctx context.Context) error) error {
+	ci := fs.GetConfig(ctx)
+	fi := filter.GetConfig(ctx)
+	features := fsrc.Features()
+	if features.ChangeCursor == nil || features.ListChanges == nil {
+		fs.Logf(fsrc, "Doing a full sync as --incremental-state isn't supported by this remote")
+		return run(ctx)
+	}
+	if fi.HaveFilesFrom() {
+		fs.Logf(fsrc, "Doing a full sync as --incremental-state can't be used with --files-from")
+		return run(ctx)
+	}
+	state, err := readIncrementalState(ci.IncrementalState)
+	if err != nil {
+		return err
+	}
+	key := incrementalKey(fdst, fsrc, deleteMode, fi)
+	cursor := state.Cursors[key]
+
+	// Read a new cursor before a full sync so changes made
+	// during it are seen next time
+	fullSync := func(reason string) (newCursor string, err error) {
+		fs.Infof(fsrc, "Doing a full sync as %s", reason)
+		newCursor, err = features.ChangeCursor(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read change cursor")
+		}
+		return newCursor, run(ctx)
+	}
+
+	var newCursor string
+	if cursor == "" {
+		newCursor, err = fullSync("there is no saved change cursor")
+	} else {
+		var remotes []string
+		remotes, newCursor, err = listChangedRemotes(ctx, fdst, fsrc, cursor)
+		switch {
+		case err == fs.ErrorChangeCursorInvalid:
+			newCursor, err = fullSync("the saved change cursor is invalid or has expired")
+		case err == fs.ErrorChangesIncomplete && deleteMode != fs.DeleteModeOff:
+			newCursor, err = fullSync("not all the changes could be found to delete")
+		case err != nil && err != fs.ErrorChangesIncomplete:
+			return errors.Wrap(err, "failed to list changes")
+		case len(remotes) == 0:
+			fs.Infof(fsrc, "No changes since the last sync")
+			err = nil
+		default:
+			fs.Infof(fsrc, "Syncing %d changed paths", len(remotes))
+			var newFi *filter.Filter
+			newFi, err = fi.Restrict(remotes)
+			if err != nil {
+				return err
+			}
+			// Find the changed remotes with NewObject rather
+			// than by listing
+			newCtx := filter.ReplaceConfig(ctx, newFi)
+			newCtx, newCi := fs.AddConfig(newCtx)
+			newCi.NoTraverse = true
+			err = run(newCtx)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	state.Cursors[key] = newCursor
+	return state.write(ci.IncrementalState)
+}
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changeFs is an Fs which lists the changes it is given
+type changeFs struct {
+	fs.Fs
+	features *fs.Features
+	cursor   int
+	changes  map[string]fs.EntryType
+	err      error
+}
+
+func newChangeFs(ctx context.Context, f fs.Fs) *changeFs {
+	c := &changeFs{Fs: f}
+	c.features = (&fs.Features{}).Fill(ctx, c)
+	return c
+}
+
+// Features returns the optional features of this Fs
+func (c *changeFs) Features() *fs.Features {
+	return c.features
+}
+
+// ChangeCursor returns the current cursor
+func (c *changeFs) ChangeCursor(ctx context.Context) (string, error) {
+	return strconv.Itoa(c.cursor), nil
+}
+
+// ListChanges lists the changes given since the last call
+func (c *changeFs) ListChanges(ctx context.Context, cursor string, callback func(string, fs.EntryType) error) (string, error) {
+	if cursor != strconv.Itoa(c.cursor) {
+		return "", fs.ErrorChangeCursorInvalid
+	}
+	if c.err != nil && c.err != fs.ErrorChangesIncomplete {
+		return "", c.err
+	}
+	for remote, entryType := range c.changes {
+		err := callback(remote, entryType)
+		if err != nil {
+			return "", err
+		}
+	}
+	c.changes = nil
+	c.cursor++
+	return strconv.Itoa(c.cursor), c.err
+}
+
+func TestIncrementalSync(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	r.Mkdir(ctx, r.Fremote)
+
+	dir, err := ioutil.TempDir("", "rclone-incremental-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	ci.IncrementalState = filepath.Join(dir, "state.json")
+	accounting.GlobalStats().ResetCounters()
+	fsrc := newChangeFs(ctx, r.Flocal)
+
+	// The first sync is a full one
+	file1 := r.WriteFile("a/one", "one", t1)
+	file2 := r.WriteFile("two", "two", t1)
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file2)
+	state, err := readIncrementalState(ci.IncrementalState)
+	require.NoError(t, err)
+	syncKey := incrementalKey(r.Fremote, fsrc, fs.DeleteModeDefault, filter.GetConfig(ctx))
+	assert.Equal(t, map[string]string{syncKey: "0"}, state.Cursors)
+
+	// Only the changes are synced
+	file3 := r.WriteFile("three", "three", t2)
+	file4 := r.WriteFile("four", "four", t2)
+	o, err := r.Flocal.NewObject(ctx, "two")
+	require.NoError(t, err)
+	require.NoError(t, o.Remove(ctx))
+	fsrc.changes = map[string]fs.EntryType{"three": fs.EntryObject, "two": fs.EntryObject}
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3)
+
+	// Everything in changed directories is synced
+	file5 := r.WriteFile("a/b/five", "five", t2)
+	fsrc.changes = map[string]fs.EntryType{"a": fs.EntryDirectory}
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3, file5)
+
+	// A copy has its own cursor so starts with a full copy
+	extra := r.WriteObject(ctx, "extra", "extra", t1)
+	require.NoError(t, CopyDir(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5, extra)
+	copyKey := incrementalKey(r.Fremote, fsrc, fs.DeleteModeOff, filter.GetConfig(ctx))
+	assert.NotEqual(t, syncKey, copyKey)
+	state, err = readIncrementalState(ci.IncrementalState)
+	require.NoError(t, err)
+	assert.Equal(t, "2", state.Cursors[copyKey])
+	assert.Equal(t, "2", state.Cursors[syncKey])
+
+	// Changing the filters does a full sync, which deletes extra
+	filterCtx, fi := filter.AddConfig(ctx)
+	require.NoError(t, fi.AddRule("- nothing"))
+	assert.NotEqual(t, syncKey, incrementalKey(r.Fremote, fsrc, fs.DeleteModeDefault, fi))
+	require.NoError(t, Sync(filterCtx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5)
+
+	// Incomplete changes are fine for copy
+	fsrc.err = fs.ErrorChangesIncomplete
+	file7 := r.WriteFile("seven", "seven", t2)
+	fsrc.changes = map[string]fs.EntryType{"seven": fs.EntryObject}
+	require.NoError(t, CopyDir(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5, file7)
+
+	// But need a full sync to find the deletions
+	r.WriteObject(ctx, "deleted", "deleted", t1)
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5, file7)
+
+	// An invalid cursor makes a full sync
+	fsrc.err = nil
+	file6 := r.WriteFile("six", "six", t2)
+	fsrc.cursor = 100
+	require.NoError(t, Sync(ctx, r.Fremote, fsrc, false))
+	fstest.CheckItems(t, r.Fremote, file1, file3, file4, file5, file6, file7)
+	state, err = readIncrementalState(ci.IncrementalState)
+	require.NoError(t, err)
+	assert.Equal(t, "100", state.Cursors[syncKey])
+}
+
+func TestIncrementalSyncUnsupported(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	r.Mkdir(ctx, r.Fremote)
+
+	dir, err := ioutil.TempDir("", "rclone-incremental-test")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	ci.IncrementalState = filepath.Join(dir, "state.json")
+	accounting.GlobalStats().ResetCounters()
+
+	file1 := r.WriteFile("one", "one", t1)
+	require.NoError(t, Sync(ctx, r.Fremote, r.Flocal, false))
+	fstest.CheckItems(t, r.Fremote, file1)
+	_, err = os.Stat(ci.IncrementalState)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 	"math/bits"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,18 +17,84 @@ import (
 // compare two items for order by
 type lessFn func(a, b fs.ObjectPair) bool
 
+// pipeItem is an ObjectPair in the pipe along with its priority
+type pipeItem struct {
+	pair     fs.ObjectPair
+	priority int
+	seq      uint64 // order the item was put in the pipe
+}
+
+// pipeQueue is a double ended heap of the items with one priority
+type pipeQueue struct {
+	items []pipeItem
+	less  lessFn
+}
+
+// Len satisfy heap.Interface - must be called with lock held
+func (q *pipeQueue) Len() int {
+	return len(q.items)
+}
+
+// Less satisfy heap.Interface - must be called with lock held
+//
+// If there is no order-by then the items come out in the order they
+// went in.
+func (q *pipeQueue) Less(i, j int) bool {
+	if q.less == nil {
+		return q.items[i].seq < q.items[j].seq
+	}
+	return q.less(q.items[i].pair, q.items[j].pair)
+}
+
+// Swap satisfy heap.Interface - must be called with lock held
+func (q *pipeQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+// Push satisfy heap.Interface - must be called with lock held
+func (q *pipeQueue) Push(item interface{}) {
+	q.items = append(q.items, item.(pipeItem))
+}
+
+// Pop satisfy heap.Interface - must be called with lock held
+func (q *pipeQueue) Pop() interface{} {
+	old := q.items
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = pipeItem{} // avoid memory leak
+	q.items = old[0 : n-1]
+	return item
+}
+
+// find returns the index of the item with Src remote or -1 if not found
+func (q *pipeQueue) find(remote string) int {
+	for i := range q.items {
+		if q.items[i].pair.Src.Remote() == remote {
+			return i
+		}
+	}
+	return -1
+}
+
 // pipe provides an unbounded channel like experience
 //
+// Items with a higher priority come out first, then they are ordered
+// by order-by if set, otherwise by the order they went in.
+//
 // Note unlike channels these aren't strictly ordered.
 type pipe struct {
-	mu        sync.Mutex
-	c         chan struct{}
-	queue     []fs.ObjectPair
-	closed    bool
-	totalSize int64
-	stats     func(items int, totalSize int64)
-	less      lessFn
-	fraction  int
+	mu         sync.Mutex
+	c          chan struct{}
+	queues     map[int]*pipeQueue // queue for each priority
+	priorities []int              // priorities in queues, highest first
+	items      int                // number of items in all the queues
+	seq        uint64             // sequence number of the next item
+	closed     bool
+	totalSize  int64
+	stats      func(items int, totalSize int64)
+	less       lessFn
+	fraction   int
+	priority   func(pair fs.ObjectPair) int // priority of each item if set
 }
 
 func newPipe(orderBy string, stats func(items int, totalSize int64), maxBacklog int) (*pipe, error) {
@@ -40,44 +107,55 @@ func newPipe(orderBy string, stats func(items int, totalSize int64), maxBacklog
 	}
 	p := &pipe{
 		c:        make(chan struct{}, maxBacklog),
+		queues:   make(map[int]*pipeQueue),
 		stats:    stats,
 		less:     less,
 		fraction: fraction,
 	}
-	if p.less != nil {
-		deheap.Init(p)
-	}
 	return p, nil
 }
 
-// Len satisfy heap.Interface - must be called with lock held
-func (p *pipe) Len() int {
-	return len(p.queue)
-}
-
-// Len satisfy heap.Interface - must be called with lock held
-func (p *pipe) Less(i, j int) bool {
-	return p.less(p.queue[i], p.queue[j])
+// queue returns the queue for priority making it if necessary - must
+// be called with lock held
+func (p *pipe) queue(priority int) *pipeQueue {
+	q, ok := p.queues[priority]
+	if ok {
+		return q
+	}
+	q = &pipeQueue{less: p.less}
+	p.queues[priority] = q
+	i := sort.Search(len(p.priorities), func(i int) bool { return p.priorities[i] < priority })
+	p.priorities = append(p.priorities, 0)
+	copy(p.priorities[i+1:], p.priorities[i:])
+	p.priorities[i] = priority
+	return q
 }
 
-// Swap satisfy heap.Interface - must be called with lock held
-func (p *pipe) Swap(i, j int) {
-	p.queue[i], p.queue[j] = p.queue[j], p.queue[i]
+// push item into the queue for its priority - must be called with
+// lock held
+func (p *pipe) push(item pipeItem) {
+	deheap.Push(p.queue(item.priority), item)
+	p.items++
 }
 
-// Push satisfy heap.Interface - must be called with lock held
-func (p *pipe) Push(item interface{}) {
-	p.queue = append(p.queue, item.(fs.ObjectPair))
+// remove the item at index i of q - must be called with lock held
+func (p *pipe) remove(q *pipeQueue, i int) pipeItem {
+	item := deheap.Remove(q, i).(pipeItem)
+	p.items--
+	return item
 }
 
-// Pop satisfy heap.Interface - must be called with lock held
-func (p *pipe) Pop() interface{} {
-	old := p.queue
-	n := len(old)
-	item := old[n-1]
-	old[n-1] = fs.ObjectPair{} // avoid memory leak
-	p.queue = old[0 : n-1]
-	return item
+// addSize adds delta to the total size and updates the stats - must
+// be called with lock held
+func (p *pipe) addSize(pair fs.ObjectPair, sign int64) {
+	size := pair.Src.Size()
+	if size > 0 {
+		p.totalSize += sign * size
+	}
+	if p.totalSize < 0 {
+		p.totalSize = 0
+	}
+	p.stats(p.items, p.totalSize)
 }
 
 // Put a pair into the pipe
@@ -89,18 +167,14 @@ func (p *pipe) Put(ctx context.Context, pair fs.ObjectPair) (ok bool) {
 	if ctx.Err() != nil {
 		return false
 	}
-	p.mu.Lock()
-	if p.less == nil {
-		// no order-by
-		p.queue = append(p.queue, pair)
-	} else {
-		deheap.Push(p, pair)
+	priority := 0
+	if p.priority != nil {
+		priority = p.priority(pair)
 	}
-	size := pair.Src.Size()
-	if size > 0 {
-		p.totalSize += size
-	}
-	p.stats(len(p.queue), p.totalSize)
+	p.mu.Lock()
+	p.push(pipeItem{pair: pair, priority: priority, seq: p.seq})
+	p.seq++
+	p.addSize(pair, 1)
 	p.mu.Unlock()
 	select {
 	case <-ctx.Done():
@@ -112,44 +186,50 @@ func (p *pipe) Put(ctx context.Context, pair fs.ObjectPair) (ok bool) {
 
 // Get a pair from the pipe
 //
-// If fraction is > the mixed fraction set in the pipe then it gets it
-// from the other end of the heap if order-by is in effect
+// It takes from the queue with the highest priority. If fraction is
+// > the mixed fraction set in the pipe then it gets it from the
+// other end of that queue if order-by is in effect.
 //
 // It returns ok = false if the context was cancelled or Close() has
 // been called.
 func (p *pipe) GetMax(ctx context.Context, fraction int) (pair fs.ObjectPair, ok bool) {
-	if ctx.Err() != nil {
-		return
-	}
-	select {
-	case <-ctx.Done():
-		return
-	case _, ok = <-p.c:
-		if !ok {
+	for {
+		if ctx.Err() != nil {
 			return
 		}
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok = <-p.c:
+			if !ok {
+				return
+			}
+		}
+		p.mu.Lock()
+		var q *pipeQueue
+		for _, priority := range p.priorities {
+			if p.queues[priority].Len() > 0 {
+				q = p.queues[priority]
+				break
+			}
+		}
+		if q == nil {
+			// The item for this token was removed so wait for another
+			p.mu.Unlock()
+			continue
+		}
+		var item pipeItem
+		if p.less == nil || p.fraction < 0 || fraction < p.fraction {
+			item = deheap.Pop(q).(pipeItem)
+		} else {
+			item = deheap.PopMax(q).(pipeItem)
+		}
+		p.items--
+		pair = item.pair
+		p.addSize(pair, -1)
+		p.mu.Unlock()
+		return pair, true
 	}
-	p.mu.Lock()
-	if p.less == nil {
-		// no order-by
-		pair = p.queue[0]
-		p.queue[0] = fs.ObjectPair{} // avoid memory leak
-		p.queue = p.queue[1:]
-	} else if p.fraction < 0 || fraction < p.fraction {
-		pair = deheap.Pop(p).(fs.ObjectPair)
-	} else {
-		pair = deheap.PopMax(p).(fs.ObjectPair)
-	}
-	size := pair.Src.Size()
-	if size > 0 {
-		p.totalSize -= size
-	}
-	if p.totalSize < 0 {
-		p.totalSize = 0
-	}
-	p.stats(len(p.queue), p.totalSize)
-	p.mu.Unlock()
-	return pair, true
 }
 
 // Get a pair from the pipe
@@ -163,11 +243,58 @@ func (p *pipe) Get(ctx context.Context) (pair fs.ObjectPair, ok bool) {
 // Stats reads the number of items in the queue and the totalSize
 func (p *pipe) Stats() (items int, totalSize int64) {
 	p.mu.Lock()
-	items, totalSize = len(p.queue), p.totalSize
+	items, totalSize = p.items, p.totalSize
 	p.mu.Unlock()
 	return items, totalSize
 }
 
+// Pending returns the items waiting in the pipe in roughly the order
+// they will come out.
+func (p *pipe) Pending() (items []pipeItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, priority := range p.priorities {
+		q := p.queues[priority]
+		sorted := append([]pipeItem(nil), q.items...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return (&pipeQueue{items: sorted, less: q.less}).Less(i, j)
+		})
+		items = append(items, sorted...)
+	}
+	return items
+}
+
+// SetPriority moves the item whose source is remote to the queue for
+// priority, returning false if it isn't in the pipe.
+func (p *pipe) SetPriority(remote string, priority int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, q := range p.queues {
+		if i := q.find(remote); i >= 0 {
+			item := p.remove(q, i)
+			item.priority = priority
+			p.push(item)
+			return true
+		}
+	}
+	return false
+}
+
+// Remove takes the item whose source is remote out of the pipe,
+// returning false if it isn't in the pipe.
+func (p *pipe) Remove(remote string) (pair fs.ObjectPair, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, q := range p.queues {
+		if i := q.find(remote); i >= 0 {
+			pair = p.remove(q, i).pair
+			p.addSize(pair, -1)
+			return pair, true
+		}
+	}
+	return pair, false
+}
+
 // Close the pipe
 //
 // Writes to a closed pipe will panic as will double closing a pipe
@@ -3,6 +3,7 @@ package sync
 import (
 	"container/heap"
 	"context"
+	"path"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -14,7 +15,7 @@ import (
 )
 
 // Check interface satisfied
-var _ heap.Interface = (*pipe)(nil)
+var _ heap.Interface = (*pipeQueue)(nil)
 
 func TestPipe(t *testing.T) {
 	var queueLength int
@@ -201,6 +202,73 @@ func TestPipeOrderBy(t *testing.T) {
 	}
 }
 
+func TestPipePriority(t *testing.T) {
+	var (
+		queueLength int
+		queueSize   int64
+		stats       = func(n int, size int64) { queueLength, queueSize = n, size }
+		ctx         = context.Background()
+		objs        = map[string]fs.ObjectPair{}
+	)
+	for _, name := range []string{"a.txt", "b.db", "c.txt", "d.db", "e.log"} {
+		obj := mockobject.New(name).WithContent([]byte(name), mockobject.SeekModeNone)
+		objs[name] = fs.ObjectPair{Src: obj}
+	}
+
+	p, err := newPipe("", stats, 10)
+	require.NoError(t, err)
+	p.priority = func(pair fs.ObjectPair) int {
+		switch path.Ext(pair.Src.Remote()) {
+		case ".db":
+			return 10
+		case ".log":
+			return -1
+		}
+		return 0
+	}
+
+	for _, name := range []string{"e.log", "a.txt", "b.db", "c.txt", "d.db"} {
+		assert.True(t, p.Put(ctx, objs[name]))
+	}
+	assert.Equal(t, 5, queueLength)
+	assert.Equal(t, int64(23), queueSize)
+
+	remotes := func() (out []string) {
+		for _, item := range p.Pending() {
+			out = append(out, item.pair.Src.Remote())
+		}
+		return out
+	}
+	assert.Equal(t, []string{"b.db", "d.db", "a.txt", "c.txt", "e.log"}, remotes())
+
+	// Reprioritise an item
+	assert.True(t, p.SetPriority("e.log", 20))
+	assert.False(t, p.SetPriority("potato", 20))
+	assert.Equal(t, []string{"e.log", "b.db", "d.db", "a.txt", "c.txt"}, remotes())
+
+	// Remove an item
+	pair, ok := p.Remove("d.db")
+	assert.True(t, ok)
+	assert.Equal(t, objs["d.db"], pair)
+	_, ok = p.Remove("d.db")
+	assert.False(t, ok)
+	assert.Equal(t, 4, queueLength)
+	assert.Equal(t, int64(19), queueSize)
+
+	// Read them out - the token for the removed item is skipped
+	for _, name := range []string{"e.log", "b.db", "a.txt", "c.txt"} {
+		pair, ok := p.Get(ctx)
+		require.True(t, ok)
+		assert.Equal(t, name, pair.Src.Remote())
+	}
+	assert.Equal(t, 0, queueLength)
+	assert.Equal(t, int64(0), queueSize)
+
+	p.Close()
+	_, ok = p.Get(ctx)
+	assert.False(t, ok)
+}
+
 func TestNewLess(t *testing.T) {
 	t.Run("blankOK", func(t *testing.T) {
 		less, _, err := newLess("")
@@ -3,6 +3,8 @@ package sync
 import (
 	"context"
 
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs/operations"
 	"github.com/rclone/rclone/fs/rc"
 )
 
@@ -25,13 +27,132 @@ func init() {
 - srcFs - a remote name string e.g. "drive:src" for the source
 - dstFs - a remote name string e.g. "drive:dst" for the destination
 - createEmptySrcDirs - create empty src directories on destination if set
-` + moveHelp + `
+` + moveHelp + `- report - if set return a report of what happened to each file
+
+If report is set the output contains
+
+- report - a list of objects, one per file, with these keys
+    - Action - one of copied, updated, deleted, renamed, skipped or failed
+    - Path - the path of the file
+    - Reason - why the action was taken e.g. "new" or "size differs"
+    - Size - the size of the file
+    - From - the original path if the file was renamed
+    - Error - the error if the action failed
 
 See the [` + name + ` command](/commands/rclone_` + name + `/) command for more information on the above.`,
 		})
 	}
 }
 
+func init() {
+	rc.Add(rc.Call{
+		Path:  "sync/queue-list",
+		Fn:    rcQueueList,
+		Title: "List the files waiting to be checked or transferred",
+		Help: `This lists the files queued in the running syncs, copies and moves.
+
+It takes the following parameters
+
+- group - only list the files of this stats group, e.g. "job/1" for an async job
+
+Returns
+
+- queued - a list of files in roughly the order they will be processed
+    - group - the stats group of the sync
+    - name - the path of the file
+    - size - the size of the file
+    - priority - higher priority files are processed first
+    - queue - one of check, transfer or rename
+`,
+	})
+	rc.Add(rc.Call{
+		Path:         "sync/queue-priority",
+		AuthRequired: true,
+		Fn:           rcQueuePriority,
+		Title:        "Change the priority of a queued file",
+		Help: `This sets the priority of a file waiting to be checked or transferred.
+
+It takes the following parameters
+
+- name - the path of the file as shown by sync/queue-list
+- priority - an integer, higher priority files are processed first
+- group - only change the file in this stats group, e.g. "job/1"
+
+The default priority is 0 unless changed with --priority. The
+priority sticks to the file when it moves from being checked to being
+transferred. It is an error if the file isn't queued.
+`,
+	})
+	rc.Add(rc.Call{
+		Path:         "sync/queue-cancel",
+		AuthRequired: true,
+		Fn:           rcQueueCancel,
+		Title:        "Cancel a queued file",
+		Help: `This removes a file waiting to be checked or transferred so it
+won't be.
+
+It takes the following parameters
+
+- name - the path of the file as shown by sync/queue-list
+- group - only cancel the file in this stats group, e.g. "job/1"
+
+Files which are already being transferred can be stopped with
+job/stop. It is an error if the file isn't queued.
+`,
+	})
+}
+
+// getQueueParams reads the group and name parameters
+func getQueueParams(in rc.Params) (group, name string, err error) {
+	group, err = in.GetString("group")
+	if rc.NotErrParamNotFound(err) {
+		return "", "", err
+	}
+	name, err = in.GetString("name")
+	return group, name, err
+}
+
+// List the queued files
+func rcQueueList(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	group, err := in.GetString("group")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	queued := Queued(group)
+	if queued == nil {
+		queued = []QueuedItem{}
+	}
+	return rc.Params{"queued": queued}, nil
+}
+
+// Change the priority of a queued file
+func rcQueuePriority(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	group, name, err := getQueueParams(in)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := in.GetInt64("priority")
+	if err != nil {
+		return nil, err
+	}
+	if !SetPriority(group, name, int(priority)) {
+		return nil, errors.Errorf("%q is not queued", name)
+	}
+	return nil, nil
+}
+
+// Cancel a queued file
+func rcQueueCancel(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	group, name, err := getQueueParams(in)
+	if err != nil {
+		return nil, err
+	}
+	if !Cancel(group, name) {
+		return nil, errors.Errorf("%q is not queued", name)
+	}
+	return nil, nil
+}
+
 // Sync/Copy/Move a file
 func rcSyncCopyMove(ctx context.Context, in rc.Params, name string) (out rc.Params, err error) {
 	srcFs, err := rc.GetFsNamed(ctx, in, "srcFs")
@@ -46,17 +167,31 @@ func rcSyncCopyMove(ctx context.Context, in rc.Params, name string) (out rc.Para
 	if rc.NotErrParamNotFound(err) {
 		return nil, err
 	}
+	report, err := in.GetBool("report")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	var r *operations.Report
+	if report {
+		r = &operations.Report{Record: true}
+		ctx = operations.WithReport(ctx, r)
+	}
 	switch name {
 	case "sync":
-		return nil, Sync(ctx, dstFs, srcFs, createEmptySrcDirs)
+		err = Sync(ctx, dstFs, srcFs, createEmptySrcDirs)
 	case "copy":
-		return nil, CopyDir(ctx, dstFs, srcFs, createEmptySrcDirs)
+		err = CopyDir(ctx, dstFs, srcFs, createEmptySrcDirs)
 	case "move":
-		deleteEmptySrcDirs, err := in.GetBool("deleteEmptySrcDirs")
-		if rc.NotErrParamNotFound(err) {
-			return nil, err
+		deleteEmptySrcDirs, getErr := in.GetBool("deleteEmptySrcDirs")
+		if rc.NotErrParamNotFound(getErr) {
+			return nil, getErr
 		}
-		return nil, MoveDir(ctx, dstFs, srcFs, deleteEmptySrcDirs, createEmptySrcDirs)
+		err = MoveDir(ctx, dstFs, srcFs, deleteEmptySrcDirs, createEmptySrcDirs)
+	default:
+		panic("unknown rcSyncCopyMove type")
+	}
+	if r != nil {
+		out = rc.Params{"report": r.Entries()}
 	}
-	panic("unknown rcSyncCopyMove type")
+	return out, err
 }
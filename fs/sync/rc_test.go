@@ -4,7 +4,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
 	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/filter"
 	"github.com/rclone/rclone/fs/rc"
 	"github.com/rclone/rclone/fstest"
 	"github.com/stretchr/testify/assert"
@@ -97,3 +100,61 @@ func TestRcSync(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file1, file2)
 	fstest.CheckItems(t, r.Fremote, file1, file2)
 }
+
+// sync/queue-list, sync/queue-priority and sync/queue-cancel:
+// inspect and change the queues of a running sync
+func TestRcQueue(t *testing.T) {
+	r, list := rcNewRun(t, "sync/queue-list")
+	defer r.Finalise()
+	priority := rc.Calls.Get("sync/queue-priority")
+	require.NotNil(t, priority)
+	cancel := rc.Calls.Get("sync/queue-cancel")
+	require.NotNil(t, cancel)
+
+	ctx := accounting.WithStatsGroup(context.Background(), "job/42")
+	ctx, fi := filter.AddConfig(ctx)
+	require.NoError(t, fi.AddPriorityRule("10 *.db"))
+	file1 := r.WriteFile("a.txt", "a", t1)
+	file2 := r.WriteFile("b.db", "bb", t1)
+	file3 := r.WriteFile("c.txt", "ccc", t1)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file3)
+
+	s, err := newSyncCopyMove(ctx, r.Fremote, r.Flocal, fs.DeleteModeOff, false, false, false)
+	require.NoError(t, err)
+	s.register(ctx)
+	defer s.unregister()
+	for _, name := range []string{"a.txt", "b.db", "c.txt"} {
+		src, err := r.Flocal.NewObject(ctx, name)
+		require.NoError(t, err)
+		require.True(t, s.toBeUploaded.Put(ctx, fs.ObjectPair{Src: src}))
+	}
+
+	names := func(group string) (out []string) {
+		res, err := list.Fn(context.Background(), rc.Params{"group": group})
+		require.NoError(t, err)
+		for _, item := range res["queued"].([]QueuedItem) {
+			assert.Equal(t, "job/42", item.Group)
+			assert.Equal(t, "transfer", item.Queue)
+			out = append(out, item.Name)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"b.db", "a.txt", "c.txt"}, names("job/42"))
+	assert.Equal(t, []string(nil), names("job/43"))
+
+	_, err = priority.Fn(context.Background(), rc.Params{"name": "c.txt", "priority": 20})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.txt", "b.db", "a.txt"}, names(""))
+	_, err = priority.Fn(context.Background(), rc.Params{"name": "potato", "priority": 20})
+	assert.Error(t, err)
+	s.prioritiesMu.Lock()
+	_, stored := s.priorities["potato"]
+	s.prioritiesMu.Unlock()
+	assert.False(t, stored)
+
+	_, err = cancel.Fn(context.Background(), rc.Params{"name": "b.db", "group": "job/42"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.txt", "a.txt"}, names(""))
+	_, err = cancel.Fn(context.Background(), rc.Params{"name": "b.db"})
+	assert.Error(t, err)
+}
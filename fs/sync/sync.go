@@ -73,6 +73,12 @@ type syncCopyMove struct {
 	compareCopyDest        []fs.Fs                // place to check for files to server side copy
 	backupDir              fs.Fs                  // place to store overwrites/deletes
 	checkFirst             bool                   // if set run all the checkers before starting transfers
+	report                 *operations.Report     // if set record what happened to each file
+	reasonsMu              sync.Mutex             // protect reasons
+	reasons                map[string]string      // why each src needs transferring - only used if report set
+	adaptive               *adaptiveController    // tunes the active checkers and transfers if set
+	prioritiesMu           sync.Mutex             // protect priorities
+	priorities             map[string]int         // priority of src files set with the rc
 }
 
 type trackRenamesStrategy byte
@@ -125,6 +131,9 @@ func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 		modifyWindow:           fs.GetModifyWindow(ctx, fsrc, fdst),
 		trackRenamesCh:         make(chan fs.Object, ci.Checkers),
 		checkFirst:             ci.CheckFirst,
+		report:                 operations.GetReport(ctx),
+		reasons:                make(map[string]string),
+		priorities:             make(map[string]int),
 	}
 	backlog := ci.MaxBacklog
 	if s.checkFirst {
@@ -144,6 +153,10 @@ func newSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 	if err != nil {
 		return nil, err
 	}
+	for _, p := range []*pipe{s.toBeChecked, s.toBeUploaded, s.toBeRenamed} {
+		p.priority = s.priority
+	}
+	s.adaptive = newAdaptiveController(ctx, s)
 	// If a max session duration has been defined add a deadline to the context
 	if ci.MaxDuration > 0 {
 		endTime := time.Now().Add(ci.MaxDuration)
@@ -260,7 +273,7 @@ func (s *syncCopyMove) processError(err error) {
 	}
 	if err == context.DeadlineExceeded {
 		err = fserrors.NoRetryError(err)
-	} else if err == accounting.ErrorMaxTransferLimitReachedGraceful {
+	} else if err == accounting.ErrorMaxTransferLimitReachedGraceful || err == accounting.ErrorMaxAPICallsReachedGraceful || err == accounting.ErrorMaxEgressReachedGraceful {
 		if s.inCtx.Err() == nil {
 			fs.Logf(nil, "%v - stopping transfers", err)
 			// Cancel the march and stop the pipes
@@ -305,9 +318,13 @@ func (s *syncCopyMove) currentError() error {
 // pairChecker reads Objects~s on in send to out if they need transferring.
 //
 // FIXME potentially doing lots of hashes at once
-func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, fraction int, wg *sync.WaitGroup) {
+func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, slot, fraction int, wg *sync.WaitGroup) {
 	defer wg.Done()
+	gate := s.adaptive.checkerGate()
 	for {
+		if !gate.wait(slot) {
+			return
+		}
 		pair, ok := in.GetMax(s.inCtx, fraction)
 		if !ok {
 			return
@@ -317,22 +334,41 @@ func (s *syncCopyMove) pairChecker(in *pipe, out *pipe, fraction int, wg *sync.W
 		tr := accounting.Stats(s.ctx).NewCheckingTransfer(src)
 		// Check to see if can store this
 		if src.Storable() {
-			NoNeedTransfer, err := operations.CompareOrCopyDest(s.ctx, s.fdst, pair.Dst, pair.Src, s.compareCopyDest, s.backupDir)
+			NoNeedTransfer, copied, err := operations.CompareOrCopyDestCopied(s.ctx, s.fdst, pair.Dst, pair.Src, s.compareCopyDest, s.backupDir)
 			if err != nil {
 				s.processError(err)
 			}
-			if !NoNeedTransfer && operations.NeedTransfer(s.ctx, pair.Dst, pair.Src) {
+			needTransfer := !NoNeedTransfer && operations.NeedTransfer(s.ctx, pair.Dst, pair.Src)
+			if s.report != nil {
+				switch {
+				case needTransfer:
+					s.setReason(src, operations.TransferReason(s.ctx, pair.Dst, src))
+				case copied:
+					action := operations.ReportCopied
+					if pair.Dst != nil {
+						action = operations.ReportUpdated
+					}
+					s.report.Add(action, src, "copied from --copy-dest", err)
+				case NoNeedTransfer:
+					s.report.Add(operations.ReportSkipped, src, "found in --compare-dest or --copy-dest", err)
+				default:
+					s.report.Add(operations.ReportSkipped, src, operations.SkipReason(s.ctx, pair.Dst, src), nil)
+				}
+			}
+			if needTransfer {
 				// If files are treated as immutable, fail if destination exists and does not match
 				if s.ci.Immutable && pair.Dst != nil {
 					err := fs.CountError(fserrors.NoRetryError(fs.ErrorImmutableModified))
 					fs.Errorf(pair.Dst, "Source and destination exist but do not match: %v", err)
 					s.processError(err)
+					s.report.Add(operations.ReportFailed, src, s.popReason(src), err)
 				} else {
 					// If destination already exists, then we must move it into --backup-dir if required
 					if pair.Dst != nil && s.backupDir != nil {
 						err := operations.MoveBackupDir(s.ctx, s.backupDir, pair.Dst)
 						if err != nil {
 							s.processError(err)
+							s.report.Add(operations.ReportFailed, src, s.popReason(src), err)
 						} else {
 							// If successful zero out the dst as it is no longer there and copy the file
 							pair.Dst = nil
@@ -387,10 +423,14 @@ func (s *syncCopyMove) pairRenamer(in *pipe, out *pipe, fraction int, wg *sync.W
 }
 
 // pairCopyOrMove reads Objects on in and moves or copies them.
-func (s *syncCopyMove) pairCopyOrMove(ctx context.Context, in *pipe, fdst fs.Fs, fraction int, wg *sync.WaitGroup) {
+func (s *syncCopyMove) pairCopyOrMove(ctx context.Context, in *pipe, fdst fs.Fs, slot, fraction int, wg *sync.WaitGroup) {
 	defer wg.Done()
+	gate := s.adaptive.transferGate()
 	var err error
 	for {
+		if !gate.wait(slot) {
+			return
+		}
 		pair, ok := in.GetMax(s.inCtx, fraction)
 		if !ok {
 			return
@@ -402,7 +442,41 @@ func (s *syncCopyMove) pairCopyOrMove(ctx context.Context, in *pipe, fdst fs.Fs,
 			_, err = operations.Copy(ctx, fdst, pair.Dst, src.Remote(), src)
 		}
 		s.processError(err)
+		if s.report != nil {
+			reason := s.popReason(src)
+			action := operations.ReportUpdated
+			if reason == "new" {
+				action = operations.ReportCopied
+			}
+			s.report.Add(action, src, reason, err)
+		}
+	}
+}
+
+// setReason records why src needs transferring for the report
+func (s *syncCopyMove) setReason(src fs.Object, reason string) {
+	if s.report == nil {
+		return
 	}
+	s.reasonsMu.Lock()
+	s.reasons[src.Remote()] = reason
+	s.reasonsMu.Unlock()
+}
+
+// popReason returns why src needed transferring, defaulting to "new"
+// if the reason wasn't recorded, and forgets it.
+func (s *syncCopyMove) popReason(src fs.Object) string {
+	if s.report == nil {
+		return ""
+	}
+	s.reasonsMu.Lock()
+	defer s.reasonsMu.Unlock()
+	reason, ok := s.reasons[src.Remote()]
+	if !ok {
+		return "new"
+	}
+	delete(s.reasons, src.Remote())
+	return reason
 }
 
 // This starts the background checkers.
@@ -410,13 +484,14 @@ func (s *syncCopyMove) startCheckers() {
 	s.checkerWg.Add(s.ci.Checkers)
 	for i := 0; i < s.ci.Checkers; i++ {
 		fraction := (100 * i) / s.ci.Checkers
-		go s.pairChecker(s.toBeChecked, s.toBeUploaded, fraction, &s.checkerWg)
+		go s.pairChecker(s.toBeChecked, s.toBeUploaded, i, fraction, &s.checkerWg)
 	}
 }
 
 // This stops the background checkers
 func (s *syncCopyMove) stopCheckers() {
 	s.toBeChecked.Close()
+	s.adaptive.checkerGate().close()
 	fs.Debugf(s.fdst, "Waiting for checks to finish")
 	s.checkerWg.Wait()
 }
@@ -426,13 +501,14 @@ func (s *syncCopyMove) startTransfers() {
 	s.transfersWg.Add(s.ci.Transfers)
 	for i := 0; i < s.ci.Transfers; i++ {
 		fraction := (100 * i) / s.ci.Transfers
-		go s.pairCopyOrMove(s.ctx, s.toBeUploaded, s.fdst, fraction, &s.transfersWg)
+		go s.pairCopyOrMove(s.ctx, s.toBeUploaded, s.fdst, i, fraction, &s.transfersWg)
 	}
 }
 
 // This stops the background transfers
 func (s *syncCopyMove) stopTransfers() {
 	s.toBeUploaded.Close()
+	s.adaptive.transferGate().close()
 	fs.Debugf(s.fdst, "Waiting for transfers to finish")
 	s.transfersWg.Wait()
 }
@@ -806,6 +882,13 @@ func (s *syncCopyMove) tryRename(src fs.Object) bool {
 	s.dstFilesMu.Unlock()
 
 	fs.Infof(src, "Renamed from %q", dst.Remote())
+	s.report.AddEntry(operations.ReportEntry{
+		Action: operations.ReportRenamed,
+		Path:   src.Remote(),
+		Reason: "track renames",
+		Size:   src.Size(),
+		From:   dst.Remote(),
+	})
 	return true
 }
 
@@ -822,6 +905,9 @@ func (s *syncCopyMove) run() error {
 		return nil
 	}
 
+	s.register(s.ctx)
+	defer s.unregister()
+
 	// Start background checking and transferring pipeline
 	s.startCheckers()
 	s.startRenamers()
@@ -829,6 +915,7 @@ func (s *syncCopyMove) run() error {
 		s.startTransfers()
 	}
 	s.startDeleters()
+	s.adaptive.start()
 	s.dstFiles = make(map[string]fs.Object)
 
 	s.startTrackRenames()
@@ -868,6 +955,7 @@ func (s *syncCopyMove) run() error {
 	}
 	s.stopRenamers()
 	s.stopTransfers()
+	s.adaptive.stopTuning()
 	s.stopDeleters()
 
 	if s.copyEmptySrcDirs {
@@ -973,7 +1061,7 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 			}
 		} else {
 			// Check CompareDest && CopyDest
-			NoNeedTransfer, err := operations.CompareOrCopyDest(s.ctx, s.fdst, nil, x, s.compareCopyDest, s.backupDir)
+			NoNeedTransfer, copied, err := operations.CompareOrCopyDestCopied(s.ctx, s.fdst, nil, x, s.compareCopyDest, s.backupDir)
 			if err != nil {
 				s.processError(err)
 			}
@@ -983,6 +1071,10 @@ func (s *syncCopyMove) SrcOnly(src fs.DirEntry) (recurse bool) {
 				if !ok {
 					return
 				}
+			} else if copied {
+				s.report.Add(operations.ReportCopied, x, "copied from --copy-dest", err)
+			} else {
+				s.report.Add(operations.ReportSkipped, x, "found in --compare-dest or --copy-dest", err)
 			}
 		}
 	case fs.Directory:
@@ -1059,28 +1151,45 @@ func runSyncCopyMove(ctx context.Context, fdst, fsrc fs.Fs, deleteMode fs.Delete
 	if deleteMode != fs.DeleteModeOff && DoMove {
 		return fserrors.FatalError(errors.New("can't delete and move at the same time"))
 	}
-	// Run an extra pass to delete only
-	if deleteMode == fs.DeleteModeBefore {
-		if ci.TrackRenames {
-			return fserrors.FatalError(errors.New("can't use --delete-before with --track-renames"))
+	if deleteMode == fs.DeleteModeBefore && ci.TrackRenames {
+		return fserrors.FatalError(errors.New("can't use --delete-before with --track-renames"))
+	}
+	run := func(ctx context.Context) error {
+		deleteMode := deleteMode
+		// Run an extra pass to delete only
+		if deleteMode == fs.DeleteModeBefore {
+			// only delete stuff during in this pass
+			do, err := newSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOnly, false, deleteEmptySrcDirs, copyEmptySrcDirs)
+			if err != nil {
+				return err
+			}
+			err = do.run()
+			if err != nil {
+				return err
+			}
+			// Next pass does a copy only
+			deleteMode = fs.DeleteModeOff
 		}
-		// only delete stuff during in this pass
-		do, err := newSyncCopyMove(ctx, fdst, fsrc, fs.DeleteModeOnly, false, deleteEmptySrcDirs, copyEmptySrcDirs)
+		do, err := newSyncCopyMove(ctx, fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs, copyEmptySrcDirs)
 		if err != nil {
 			return err
 		}
-		err = do.run()
-		if err != nil {
-			return err
+		return do.run()
+	}
+	if ci.BackupVersions {
+		versionedRun := run
+		run = func(ctx context.Context) error {
+			return runVersioned(ctx, versionedRun)
 		}
-		// Next pass does a copy only
-		deleteMode = fs.DeleteModeOff
 	}
-	do, err := newSyncCopyMove(ctx, fdst, fsrc, deleteMode, DoMove, deleteEmptySrcDirs, copyEmptySrcDirs)
-	if err != nil {
-		return err
+	if ci.IncrementalState != "" {
+		if DoMove {
+			fs.Errorf(nil, "Ignoring --incremental-state with move")
+		} else {
+			return runIncremental(ctx, fdst, fsrc, deleteMode, run)
+		}
 	}
-	return do.run()
+	return run(ctx)
 }
 
 // Sync fsrc into fdst
@@ -3,9 +3,11 @@
 package sync
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -690,6 +692,67 @@ func TestSyncAfterChangingContentsOnly(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2)
 }
 
+// Sync with a report of what happened to each file
+func TestSyncWithReport(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteBoth(ctx, "same", "same contents", t1)
+	file2 := r.WriteFile("new", "new contents", t2)
+	file3 := r.WriteObject(ctx, "gone", "gone contents", t2)
+	r.WriteObject(ctx, "changed", "old", t3)
+	file4 := r.WriteFile("changed", "new and longer", t3)
+	fstest.CheckItems(t, r.Flocal, file1, file2, file4)
+
+	var combined bytes.Buffer
+	report := &operations.Report{Combined: &combined, Record: true}
+	ctx = operations.WithReport(ctx, report)
+
+	accounting.GlobalStats().ResetCounters()
+	err := Sync(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file4)
+
+	entries := report.Entries()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	assert.Equal(t, []operations.ReportEntry{
+		{Action: operations.ReportUpdated, Path: "changed", Reason: "size differs", Size: file4.Size},
+		{Action: operations.ReportDeleted, Path: "gone", Reason: "not in source", Size: file3.Size},
+		{Action: operations.ReportCopied, Path: "new", Reason: "new", Size: file2.Size},
+		{Action: operations.ReportSkipped, Path: "same", Reason: "unchanged", Size: file1.Size},
+	}, entries)
+
+	lines := strings.Split(strings.TrimSpace(combined.String()), "\n")
+	sort.Strings(lines)
+	assert.Equal(t, []string{"* changed", "+ new", "- gone", "= same"}, lines)
+}
+
+// Sync with a report under --dry-run
+func TestSyncWithReportDryRun(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteFile("new", "new contents", t1)
+	file2 := r.WriteObject(ctx, "gone", "gone contents", t2)
+
+	report := &operations.Report{Record: true}
+	ctx = operations.WithReport(ctx, report)
+
+	ci.DryRun = true
+	accounting.GlobalStats().ResetCounters()
+	err := Sync(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file2)
+
+	entries := report.Entries()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	assert.Equal(t, []operations.ReportEntry{
+		{Action: operations.ReportSkipped, Path: "gone", Reason: "not in source, would be deleted without --dry-run", Size: file2.Size},
+		{Action: operations.ReportCopied, Path: "new", Reason: "new", Size: file1.Size},
+	}, entries)
+}
+
 // Sync after removing a file and adding a file --dry-run
 func TestSyncAfterRemovingAFileAndAddingAFileDryRun(t *testing.T) {
 	ctx := context.Background()
@@ -958,6 +1021,31 @@ func TestSyncWithExclude(t *testing.T) {
 	fstest.CheckItems(t, r.Flocal, file2, file1, file3)
 }
 
+// Test the ignore files in the source apply to the destination
+func TestSyncWithIgnoreFiles(t *testing.T) {
+	ctx := context.Background()
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	file1 := r.WriteFile(".rcloneignore", "*.log\n", t1)
+	file2 := r.WriteFile("sub/keep.txt", "keep", t1)
+	file3 := r.WriteObject(ctx, "sub/remote.log", "remote only", t1)
+	file4 := r.WriteObject(ctx, "sub/stale.txt", "stale", t1)
+	fstest.CheckItems(t, r.Flocal, file1, file2)
+	fstest.CheckItems(t, r.Fremote, file3, file4)
+
+	fi, err := filter.NewFilter(nil)
+	require.NoError(t, err)
+	fi.Opt.IgnoreFileName = []string{".rcloneignore"}
+	ctx = filter.ReplaceConfig(ctx, fi)
+
+	// sub/remote.log is ignored by the source's ignore file so
+	// isn't deleted even though the destination has no ignore file
+	accounting.GlobalStats().ResetCounters()
+	err = Sync(ctx, r.Fremote, r.Flocal, false)
+	require.NoError(t, err)
+	fstest.CheckItems(t, r.Fremote, file1, file2, file3)
+}
+
 // Test with exclude and delete excluded
 func TestSyncWithExcludeAndDeleteExcluded(t *testing.T) {
 	ctx := context.Background()
@@ -1701,14 +1789,21 @@ func TestSyncCopyDest(t *testing.T) {
 	fstest.CheckItems(t, r.Fremote, file2, file2dst, file3, file4)
 	fstest.CheckItems(t, r.Flocal, file1c, file5)
 
+	report := &operations.Report{Record: true}
 	accounting.GlobalStats().ResetCounters()
-	err = Sync(ctx, fdst, r.Flocal, false)
+	err = Sync(operations.WithReport(ctx, report), fdst, r.Flocal, false)
 	require.NoError(t, err)
 
 	file4dst := file4
 	file4dst.Path = "dst/two"
 
 	fstest.CheckItems(t, r.Fremote, file2, file2dst, file3, file4, file4dst)
+	entries := report.Entries()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	assert.Equal(t, []operations.ReportEntry{
+		{Action: operations.ReportSkipped, Path: "one", Reason: "unchanged", Size: file1c.Size},
+		{Action: operations.ReportCopied, Path: "two", Reason: "copied from --copy-dest", Size: file5.Size},
+	}, entries)
 
 	// check new dest, new copy
 	accounting.GlobalStats().ResetCounters()
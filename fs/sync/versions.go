@@ -0,0 +1,471 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/cache"
+	"github.com/rclone/rclone/fs/filter"
+	"github.com/rclone/rclone/fs/fserrors"
+	"github.com/rclone/rclone/fs/fspath"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fs/walk"
+)
+
+const (
+	// versionTimeFormat is the format of the names of the backup
+	// directories made by --backup-versions. It has milliseconds
+	// so runs in the same second get different directories.
+	versionTimeFormat = "2006-01-02-150405.000"
+
+	// versionIndexSuffix is added to the name of the backup
+	// directory to make the name of its index
+	versionIndexSuffix = ".index.json"
+)
+
+// Actions recorded for each file in a versionIndex
+const (
+	versionCreated = "created" // the file didn't exist before the run
+	versionSaved   = "saved"   // the file before the run is in the backup directory
+)
+
+// versionNow returns the time of a run - overridden in tests
+var versionNow = time.Now
+
+// versionIndex records what a run with --backup-versions did to the
+// files in the destination
+type versionIndex struct {
+	Time  time.Time         `json:"time"`
+	Files map[string]string `json:"files"`
+}
+
+// versionRun is a run found in --backup-dir
+type versionRun struct {
+	name  string // name of the backup directory
+	index *versionIndex
+}
+
+// unfilteredContext returns a context without the filters in ctx so
+// all the backups are seen
+func unfilteredContext(ctx context.Context) (context.Context, error) {
+	fi, err := filter.NewFilter(nil)
+	if err != nil {
+		return nil, err
+	}
+	return filter.ReplaceConfig(ctx, fi), nil
+}
+
+// versionRunFs returns the Fs for the backup directory of the run
+// called name
+func versionRunFs(ctx context.Context, name string) (fs.Fs, error) {
+	ci := fs.GetConfig(ctx)
+	f, err := cache.Get(ctx, fspath.JoinRootPath(ci.BackupDir, name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to make fs for backup version %q", name)
+	}
+	return f, nil
+}
+
+// readVersionRuns reads the indexes of the runs in fbackup returning
+// them oldest first
+func readVersionRuns(ctx context.Context, fbackup fs.Fs) (runs []*versionRun, err error) {
+	entries, err := fbackup.List(ctx, "")
+	if err == fs.ErrorDirNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to list backup versions")
+	}
+	for _, entry := range entries {
+		o, ok := entry.(fs.Object)
+		if !ok || !strings.HasSuffix(o.Remote(), versionIndexSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(o.Remote(), versionIndexSuffix)
+		if _, err := time.Parse(versionTimeFormat, name); err != nil {
+			continue
+		}
+		index, err := readVersionIndex(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, &versionRun{name: name, index: index})
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].index.Time.Before(runs[j].index.Time)
+	})
+	return runs, nil
+}
+
+// readVersionIndex reads the index in o
+func readVersionIndex(ctx context.Context, o fs.Object) (index *versionIndex, err error) {
+	in, err := o.Open(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open backup index %q", o.Remote())
+	}
+	defer fs.CheckClose(in, &err)
+	index = &versionIndex{}
+	err = json.NewDecoder(in).Decode(index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read backup index %q", o.Remote())
+	}
+	if index.Files == nil {
+		index.Files = map[string]string{}
+	}
+	return index, nil
+}
+
+// writeVersionIndex writes the index of run into fbackup
+func writeVersionIndex(ctx context.Context, fbackup fs.Fs, run *versionRun) error {
+	data, err := json.MarshalIndent(run.index, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to make backup index")
+	}
+	_, err = operations.RcatSize(ctx, fbackup, run.name+versionIndexSuffix, ioutil.NopCloser(bytes.NewReader(data)), int64(len(data)), run.index.Time)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write backup index for %q", run.name)
+	}
+	return nil
+}
+
+// removeVersionRun removes the index and backup directory of run
+func removeVersionRun(ctx context.Context, fbackup fs.Fs, run *versionRun) error {
+	fs.Infof(fbackup, "Removing backup version %q", run.name)
+	o, err := fbackup.NewObject(ctx, run.name+versionIndexSuffix)
+	if err == nil {
+		err = o.Remove(ctx)
+	}
+	if err != nil && err != fs.ErrorObjectNotFound {
+		return errors.Wrapf(err, "failed to remove backup index for %q", run.name)
+	}
+	err = operations.Purge(ctx, fbackup, run.name)
+	if err != nil && errors.Cause(err) != fs.ErrorDirNotFound {
+		return errors.Wrapf(err, "failed to remove backup version %q", run.name)
+	}
+	return nil
+}
+
+// mergeVersionRun merges run from into the next newer run to and
+// removes it.
+//
+// The versions in from take precedence so restoring to a time before
+// to gives the files as they were before from.
+func mergeVersionRun(ctx context.Context, fbackup fs.Fs, from, to *versionRun) error {
+	fs.Debugf(fbackup, "Merging backup version %q into %q", from.name, to.name)
+	ffrom, err := versionRunFs(ctx, from.name)
+	if err != nil {
+		return err
+	}
+	fto, err := versionRunFs(ctx, to.name)
+	if err != nil {
+		return err
+	}
+	for remote, action := range from.index.Files {
+		var existing fs.Object
+		if to.index.Files[remote] == versionSaved {
+			existing, _ = fto.NewObject(ctx, remote)
+		}
+		switch action {
+		case versionSaved:
+			o, err := ffrom.NewObject(ctx, remote)
+			if err != nil {
+				return errors.Wrapf(err, "failed to find backup of %q in %q", remote, from.name)
+			}
+			_, err = operations.Move(ctx, fto, existing, remote, o)
+			if err != nil {
+				return err
+			}
+		case versionCreated:
+			if existing != nil {
+				err = existing.Remove(ctx)
+				if err != nil {
+					return errors.Wrapf(err, "failed to remove backup of %q in %q", remote, to.name)
+				}
+			}
+		}
+		to.index.Files[remote] = action
+	}
+	err = writeVersionIndex(ctx, fbackup, to)
+	if err != nil {
+		return err
+	}
+	return removeVersionRun(ctx, fbackup, from)
+}
+
+// keptVersions returns which of the backup versions made at the
+// times given, oldest first, are kept by keeping the last version of
+// the last daily days and weekly weeks. The newest is always kept.
+func keptVersions(times []time.Time, daily, weekly int) []bool {
+	keep := make([]bool, len(times))
+	if len(times) == 0 {
+		return keep
+	}
+	keep[len(times)-1] = true
+	keepLast := func(n int, period func(t time.Time) string) {
+		last := ""
+		for i := len(times) - 1; i >= 0 && n > 0; i-- {
+			p := period(times[i].Local())
+			if p != last {
+				keep[i] = true
+				last = p
+				n--
+			}
+		}
+	}
+	keepLast(daily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepLast(weekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	return keep
+}
+
+// pruneVersions removes the backup versions not kept by
+// --backup-keep-daily and --backup-keep-weekly.
+//
+// The backups made by each run hold the state of the destination
+// before it, so to remove a version its backups are merged into the
+// next run's. All the versions older than the oldest kept are removed.
+func pruneVersions(ctx context.Context, fbackup fs.Fs) error {
+	ci := fs.GetConfig(ctx)
+	runs, err := readVersionRuns(ctx, fbackup)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+	times := make([]time.Time, len(runs))
+	for i, run := range runs {
+		times[i] = run.index.Time
+	}
+	keep := keptVersions(times, ci.BackupKeepDaily, ci.BackupKeepWeekly)
+	first := 0
+	for !keep[first] {
+		first++
+	}
+	for _, run := range runs[:first] {
+		err = removeVersionRun(ctx, fbackup, run)
+		if err != nil {
+			return err
+		}
+	}
+	for i := first + 1; i < len(runs)-1; i++ {
+		if !keep[i] {
+			err = mergeVersionRun(ctx, fbackup, runs[i], runs[i+1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runVersioned calls run with --backup-dir set to a new dated
+// directory inside it and writes an index of the files it created and
+// backed up next to it so the destination can be restored to how it
+// was before.
+//
+// Old backups are pruned afterwards if --backup-keep-daily or
+// --backup-keep-weekly are set.
+func runVersioned(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	ci := fs.GetConfig(ctx)
+	if ci.BackupDir == "" {
+		return fserrors.FatalError(errors.New("--backup-versions needs --backup-dir"))
+	}
+	if ci.Suffix != "" {
+		return fserrors.FatalError(errors.New("can't use --suffix with --backup-versions"))
+	}
+	if ci.DryRun {
+		return run(ctx)
+	}
+	fbackup, err := cache.Get(ctx, ci.BackupDir)
+	if err != nil {
+		return fserrors.FatalError(errors.Wrapf(err, "failed to make fs for --backup-dir %q", ci.BackupDir))
+	}
+	uctx, err := unfilteredContext(ctx)
+	if err != nil {
+		return err
+	}
+	index := &versionIndex{
+		Time:  versionNow().UTC(),
+		Files: map[string]string{},
+	}
+	thisRun := &versionRun{
+		name:  index.Time.Format(versionTimeFormat),
+		index: index,
+	}
+	_, err = fbackup.NewObject(uctx, thisRun.name+versionIndexSuffix)
+	if err == nil {
+		return fserrors.FatalError(errors.Errorf("backup version %q already exists in --backup-dir", thisRun.name))
+	} else if err != fs.ErrorObjectNotFound && err != fs.ErrorDirNotFound {
+		return errors.Wrapf(err, "failed to check for backup version %q", thisRun.name)
+	}
+
+	// Record the files created by the run
+	var mu sync.Mutex
+	oldReport := operations.GetReport(ctx)
+	report := &operations.Report{
+		Callback: func(entry operations.ReportEntry) {
+			if entry.Action == operations.ReportCopied {
+				mu.Lock()
+				index.Files[entry.Path] = versionCreated
+				mu.Unlock()
+			}
+			oldReport.AddEntry(entry)
+		},
+	}
+	newCtx, newCi := fs.AddConfig(operations.WithReport(ctx, report))
+	newCi.BackupDir = fspath.JoinRootPath(ci.BackupDir, thisRun.name)
+	if newCi.TrackRenames {
+		fs.Errorf(nil, "Ignoring --track-renames with --backup-versions")
+		newCi.TrackRenames = false
+	}
+	err = run(newCtx)
+
+	// Index the backups even if the run failed so they can be
+	// restored
+	indexErr := func() error {
+		frun, err := versionRunFs(ctx, thisRun.name)
+		if err != nil {
+			return err
+		}
+		err = listObjects(uctx, frun, "", func(o fs.Object) {
+			index.Files[o.Remote()] = versionSaved
+		})
+		if err != nil && err != fs.ErrorDirNotFound {
+			return errors.Wrapf(err, "failed to list backup version %q", thisRun.name)
+		}
+		if len(index.Files) == 0 {
+			fs.Infof(fbackup, "Not saving backup version as nothing changed")
+			return nil
+		}
+		fs.Infof(fbackup, "Saving backup version %q of %d files", thisRun.name, len(index.Files))
+		return writeVersionIndex(uctx, fbackup, thisRun)
+	}()
+	if err != nil {
+		if indexErr != nil {
+			fs.Errorf(fbackup, "%v", indexErr)
+		}
+		return err
+	}
+	if indexErr != nil {
+		return indexErr
+	}
+	if ci.BackupKeepDaily > 0 || ci.BackupKeepWeekly > 0 {
+		return pruneVersions(uctx, fbackup)
+	}
+	return nil
+}
+
+// Restore copies the files in fsrc, which has been synced to with
+// --backup-versions, to fdst as they were at the time given using the
+// backups in --backup-dir.
+//
+// The version of each file at the time is the one backed up by the
+// first run after it, or the one in fsrc if it hasn't changed since.
+// Files created after the time aren't copied.
+func Restore(ctx context.Context, fdst, fsrc fs.Fs, at time.Time) error {
+	ci := fs.GetConfig(ctx)
+	fi := filter.GetConfig(ctx)
+	if ci.BackupDir == "" {
+		return fserrors.FatalError(errors.New("restore needs --backup-dir"))
+	}
+	fbackup, err := cache.Get(ctx, ci.BackupDir)
+	if err != nil {
+		return fserrors.FatalError(errors.Wrapf(err, "failed to make fs for --backup-dir %q", ci.BackupDir))
+	}
+	if operations.Overlapping(fdst, fsrc) || operations.Overlapping(fdst, fbackup) {
+		return fserrors.FatalError(errors.New("can't restore into the source or the --backup-dir"))
+	}
+	uctx, err := unfilteredContext(ctx)
+	if err != nil {
+		return err
+	}
+	runs, err := readVersionRuns(uctx, fbackup)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return errors.Errorf("no backup versions found in %q", ci.BackupDir)
+	}
+	if at.Before(runs[0].index.Time) {
+		fs.Logf(fbackup, "Restoring the oldest versions kept, from before %v", runs[0].index.Time.Local())
+	}
+
+	// Find which run each file should be restored from
+	found := map[string]struct{}{}
+	saved := make([][]string, len(runs))
+	for i, run := range runs {
+		if !run.index.Time.After(at) {
+			continue
+		}
+		for remote, action := range run.index.Files {
+			if _, ok := found[remote]; ok {
+				continue
+			}
+			found[remote] = struct{}{}
+			if action == versionSaved {
+				saved[i] = append(saved[i], remote)
+			}
+		}
+	}
+	var current []string
+	err = walk.ListR(ctx, fsrc, "", true, -1, walk.ListObjects, func(entries fs.DirEntries) error {
+		for _, entry := range entries {
+			if _, ok := found[entry.Remote()]; !ok {
+				current = append(current, entry.Remote())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Copy the files from each place with --no-traverse
+	newCtx, newCi := fs.AddConfig(ctx)
+	newCi.BackupDir = ""
+	newCi.BackupVersions = false
+	newCi.IncrementalState = ""
+	newCi.NoTraverse = true
+	copyFiles := func(f fs.Fs, remotes []string) error {
+		if len(remotes) == 0 {
+			return nil
+		}
+		newFi, err := fi.Restrict(remotes)
+		if err != nil {
+			return err
+		}
+		return CopyDir(filter.ReplaceConfig(newCtx, newFi), fdst, f, false)
+	}
+	var retErr error
+	err = copyFiles(fsrc, current)
+	if err != nil {
+		retErr = err
+	}
+	for i, run := range runs {
+		if len(saved[i]) == 0 {
+			continue
+		}
+		frun, err := versionRunFs(ctx, run.name)
+		if err == nil {
+			err = copyFiles(frun, saved[i])
+		}
+		if err != nil {
+			fs.Errorf(fbackup, "Failed to restore from backup version %q: %v", run.name, err)
+			retErr = err
+		}
+	}
+	return retErr
+}
@@ -0,0 +1,212 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/accounting"
+	"github.com/rclone/rclone/fs/operations"
+	"github.com/rclone/rclone/fstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeptVersions(t *testing.T) {
+	day := func(d, h int) time.Time {
+		return time.Date(2021, 3, d, h, 0, 0, 0, time.Local)
+	}
+	times := []time.Time{
+		day(1, 10), // Monday
+		day(2, 10),
+		day(2, 12),
+		day(3, 10),
+		day(8, 10), // next Monday
+		day(9, 10),
+	}
+	for _, test := range []struct {
+		daily, weekly int
+		want          []bool
+	}{
+		{0, 0, []bool{false, false, false, false, false, true}},
+		{1, 0, []bool{false, false, false, false, false, true}},
+		{2, 0, []bool{false, false, false, false, true, true}},
+		{4, 0, []bool{false, false, true, true, true, true}},
+		{10, 0, []bool{true, false, true, true, true, true}},
+		{0, 1, []bool{false, false, false, false, false, true}},
+		{0, 2, []bool{false, false, false, true, false, true}},
+		{2, 2, []bool{false, false, false, true, true, true}},
+	} {
+		got := keptVersions(times, test.daily, test.weekly)
+		assert.Equal(t, test.want, got, "daily=%d weekly=%d", test.daily, test.weekly)
+	}
+	assert.Equal(t, []bool{}, keptVersions(nil, 1, 1))
+}
+
+func TestBackupVersions(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !operations.CanServerSideMove(r.Fremote) {
+		t.Skip("Skipping test as remote does not support server-side move")
+	}
+	r.Mkdir(ctx, r.Fremote)
+	defer func() {
+		versionNow = time.Now
+	}()
+	ci.BackupDir = r.FremoteName + "/backup"
+	ci.BackupVersions = true
+	fdst, err := fs.NewFs(ctx, r.FremoteName+"/dst")
+	require.NoError(t, err)
+
+	day := func(d, h int) time.Time {
+		return time.Date(2021, 3, d, h, 0, 0, 0, time.Local)
+	}
+	sync := func(now time.Time) {
+		versionNow = func() time.Time { return now }
+		accounting.GlobalStats().ResetCounters()
+		require.NoError(t, Sync(ctx, fdst, r.Flocal, false))
+	}
+	remove := func(remote string) {
+		o, err := r.Flocal.NewObject(ctx, remote)
+		require.NoError(t, err)
+		require.NoError(t, o.Remove(ctx))
+	}
+
+	a1 := r.WriteFile("a", "a1", t1)
+	b1 := r.WriteFile("b", "b1", t1)
+	sync(day(1, 10))
+	a2 := r.WriteFile("a", "a2-2", t2)
+	c2 := r.WriteFile("c", "c2", t2)
+	remove("b")
+	sync(day(2, 10))
+	a3 := r.WriteFile("a", "a3-333", t3)
+	sync(day(2, 12))
+	sync(day(2, 14)) // nothing changed so no version
+	restore := func(name string, at time.Time, want ...fstest.Item) {
+		frestore, err := fs.NewFs(ctx, r.FremoteName+"/"+name)
+		require.NoError(t, err)
+		accounting.GlobalStats().ResetCounters()
+		require.NoError(t, Restore(ctx, frestore, fdst, at))
+		fstest.CheckListingWithPrecision(t, frestore, want, nil, fs.GetModifyWindow(ctx, frestore))
+	}
+	restore("restore", day(2, 11), a2, c2)
+	remove("c")
+	ci.BackupKeepDaily = 3
+	sync(day(3, 10))
+
+	// The version from day 2 at 10:00 is merged into the one at
+	// 12:00
+	fbackup, err := fs.NewFs(ctx, ci.BackupDir)
+	require.NoError(t, err)
+	runs, err := readVersionRuns(ctx, fbackup)
+	require.NoError(t, err)
+	var names []string
+	for _, run := range runs {
+		names = append(names, run.name)
+	}
+	assert.Equal(t, []string{
+		day(1, 10).UTC().Format(versionTimeFormat),
+		day(2, 12).UTC().Format(versionTimeFormat),
+		day(3, 10).UTC().Format(versionTimeFormat),
+	}, names)
+
+	for i, test := range []struct {
+		at   time.Time
+		want []fstest.Item
+	}{
+		{day(1, 9), nil},
+		{day(1, 11), []fstest.Item{a1, b1}},
+		{day(2, 11), []fstest.Item{a1, b1}}, // version was removed
+		{day(2, 13), []fstest.Item{a3, c2}},
+		{day(3, 11), []fstest.Item{a3}},
+	} {
+		restore(fmt.Sprintf("restore%d", i), test.at, test.want...)
+	}
+}
+
+func TestPruneVersions(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !operations.CanServerSideMove(r.Fremote) {
+		t.Skip("Skipping test as remote does not support server-side move")
+	}
+	ci.BackupDir = r.FremoteName + "/backup"
+	ci.BackupKeepDaily = 1
+	fbackup, err := fs.NewFs(ctx, ci.BackupDir)
+	require.NoError(t, err)
+
+	// Nothing to prune
+	require.NoError(t, pruneVersions(ctx, fbackup))
+
+	day := func(d, h int) time.Time {
+		return time.Date(2021, 3, d, h, 0, 0, 0, time.Local)
+	}
+	addRun := func(at time.Time, content string) *versionRun {
+		run := &versionRun{
+			name: at.UTC().Format(versionTimeFormat),
+			index: &versionIndex{
+				Time:  at.UTC(),
+				Files: map[string]string{"a": versionSaved},
+			},
+		}
+		r.WriteObject(ctx, "backup/"+run.name+"/a", content, at)
+		require.NoError(t, writeVersionIndex(ctx, fbackup, run))
+		return run
+	}
+	addRun(day(1, 10), "a1")
+	addRun(day(2, 10), "a2")
+	newest := addRun(day(2, 12), "a3")
+
+	require.NoError(t, pruneVersions(ctx, fbackup))
+
+	// Only the newest version is kept, with its backups
+	runs, err := readVersionRuns(ctx, fbackup)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(runs))
+	assert.Equal(t, newest.name, runs[0].name)
+	frun, err := versionRunFs(ctx, newest.name)
+	require.NoError(t, err)
+	o, err := frun.NewObject(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("a3")), o.Size())
+}
+
+func TestBackupVersionsSameTime(t *testing.T) {
+	ctx := context.Background()
+	ctx, ci := fs.AddConfig(ctx)
+	r := fstest.NewRun(t)
+	defer r.Finalise()
+	if !operations.CanServerSideMove(r.Fremote) {
+		t.Skip("Skipping test as remote does not support server-side move")
+	}
+	r.Mkdir(ctx, r.Fremote)
+	r.Mkdir(ctx, r.Flocal)
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	versionNow = func() time.Time { return now }
+	defer func() {
+		versionNow = time.Now
+	}()
+	ci.BackupDir = r.FremoteName + "/backup"
+	ci.BackupVersions = true
+	ci.BackupKeepDaily = 1
+	fdst, err := fs.NewFs(ctx, r.FremoteName+"/dst")
+	require.NoError(t, err)
+
+	// A run with nothing to do doesn't write a version or fail
+	require.NoError(t, Sync(ctx, fdst, r.Flocal, false))
+
+	r.WriteFile("a", "a1", t1)
+	require.NoError(t, Sync(ctx, fdst, r.Flocal, false))
+
+	// A second run at the same time can't reuse the version
+	r.WriteFile("a", "a2-2", t2)
+	err = Sync(ctx, fdst, r.Flocal, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
@@ -183,6 +183,13 @@ type GetTierer interface {
 	GetTier() string
 }
 
+// Metadataer is an optional interface for Object
+type Metadataer interface {
+	// Metadata returns the user metadata of the Object as key
+	// value pairs, or nil if there isn't any
+	Metadata(ctx context.Context) (map[string]string, error)
+}
+
 // FullObjectInfo contains all the read-only optional interfaces
 //
 // Use for checking making wrapping ObjectInfos implement everything
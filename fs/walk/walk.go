@@ -52,7 +52,9 @@ type Func func(path string, entries fs.DirEntries, err error) error
 // Parent directories are always listed before their children
 //
 // This is implemented by WalkR if Config.UseListR is true
-// and f supports it and level > 1, or WalkN otherwise.
+// and f supports it and level > 1, or WalkN otherwise. WalkN is
+// always used with --ignore-file-name as the ignore files must be
+// read before the directories below them.
 //
 // If --files-from and --no-traverse is set then a DirTree will be
 // constructed with just those files in and then walked with WalkR
@@ -68,7 +70,7 @@ func Walk(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel i
 		return walkR(ctx, f, path, includeAll, maxLevel, fn, fi.MakeListR(ctx, f.NewObject))
 	}
 	// FIXME should this just be maxLevel < 0 - why the maxLevel > 1
-	if (maxLevel < 0 || maxLevel > 1) && ci.UseListR && f.Features().ListR != nil {
+	if (maxLevel < 0 || maxLevel > 1) && ci.UseListR && f.Features().ListR != nil && (includeAll || !fi.UsesIgnoreFiles()) {
 		return walkListR(ctx, f, path, includeAll, maxLevel, fn)
 	}
 	return walkListDirSorted(ctx, f, path, includeAll, maxLevel, fn)
@@ -154,13 +156,33 @@ func ListR(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel
 		fi.HaveFilesFrom() || // ...using --files-from
 		maxLevel >= 0 || // ...using bounded recursion
 		len(fi.Opt.ExcludeFile) > 0 || // ...using --exclude-file
+		fi.UsesIgnoreFiles() || // ...using --ignore-file-name
 		fi.UsesDirectoryFilters() { // ...using any directory filters
 		return listRwalk(ctx, f, path, includeAll, maxLevel, listType, fn)
 	}
 	ctx = filter.SetUseFilter(ctx, !includeAll) // make filter-aware backends constrain List
+	doListR = hintedListR(ctx, f, path, includeAll)
 	return listR(ctx, f, path, includeAll, listType, fn, doListR, listType.Dirs() && f.Features().BucketBased)
 }
 
+// hintedListR returns the ListR of f for listing dir, which passes
+// the filter to the backend as a hint if it can use one
+func hintedListR(ctx context.Context, f fs.Fs, dir string, includeAll bool) fs.ListRFn {
+	doListR := f.Features().ListR
+	doListRHinted := f.Features().ListRHinted
+	if doListR == nil || doListRHinted == nil || includeAll {
+		return doListR
+	}
+	hint := filter.GetConfig(ctx).ListHint(dir, true)
+	if hint == nil {
+		return doListR
+	}
+	fs.Debugf(f, "Listing %q recursively with hint: %v", dir, hint)
+	return func(ctx context.Context, dir string, callback fs.ListRCallback) error {
+		return doListRHinted(ctx, dir, hint, callback)
+	}
+}
+
 // listRwalk walks the file tree for ListR using Walk
 // Note: this will flag filter-aware backends (via Walk)
 func listRwalk(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, listType ListType, fn fs.ListRCallback) error {
@@ -353,7 +375,7 @@ func walkListDirSorted(ctx context.Context, f fs.Fs, path string, includeAll boo
 // It implements Walk using recursive directory listing if
 // available, or returns ErrorCantListR if not.
 func walkListR(ctx context.Context, f fs.Fs, path string, includeAll bool, maxLevel int, fn Func) error {
-	listR := f.Features().ListR
+	listR := hintedListR(ctx, f, path, includeAll)
 	if listR == nil {
 		return ErrorCantListR
 	}
@@ -574,7 +596,7 @@ func NewDirTree(ctx context.Context, f fs.Fs, path string, includeAll bool, maxL
 	}
 	// if have ListR; and recursing; and not using --files-from; then build a DirTree with ListR
 	if ListR := f.Features().ListR; (maxLevel < 0 || maxLevel > 1) && ListR != nil && !fi.HaveFilesFrom() {
-		return walkRDirTree(ctx, f, path, includeAll, maxLevel, ListR)
+		return walkRDirTree(ctx, f, path, includeAll, maxLevel, hintedListR(ctx, f, path, includeAll))
 	}
 	// otherwise just use List
 	return walkNDirTree(ctx, f, path, includeAll, maxLevel, list.DirSorted)
@@ -0,0 +1,49 @@
+package file
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrLocked is returned by TryLock if the file is locked already
+var ErrLocked = errors.New("file is locked")
+
+// Lock takes an exclusive advisory lock on the file at path,
+// creating it if necessary and waiting until the lock is available.
+//
+// The lock is shared between processes but not between goroutines
+// of the same process, so use a mutex as well if needed. Call the
+// returned function to release the lock.
+//
+// Use a file which isn't replaced by renaming as the lock, eg the
+// path of the file being protected with ".lock" appended.
+func Lock(path string) (unlock func() error, err error) {
+	return lock(path, lockFile)
+}
+
+// TryLock is like Lock but returns ErrLocked straight away instead of
+// waiting if the lock is held by someone else.
+func TryLock(path string) (unlock func() error, err error) {
+	return lock(path, tryLockFile)
+}
+
+// lock opens the file at path and locks it with lockFn
+func lock(path string, lockFn func(f *os.File) error) (unlock func() error, err error) {
+	f, err := OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	err = lockFn(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() error {
+		err := unlockFile(f)
+		closeErr := f.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}, nil
+}
@@ -0,0 +1,21 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package file
+
+import "os"
+
+// lockFile does nothing as locking isn't supported on this platform
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// tryLockFile does nothing as locking isn't supported on this platform
+func tryLockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing as locking isn't supported on this platform
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package file
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	switch runtime.GOOS {
+	case "js", "plan9":
+		t.Skip("locking not supported on " + runtime.GOOS)
+	}
+	dir, tidy := testDir(t)
+	defer tidy()
+	path := filepath.Join(dir, "file.lock")
+
+	unlock, err := Lock(path)
+	require.NoError(t, err)
+
+	locked := make(chan func() error)
+	go func() {
+		unlock2, err := Lock(path)
+		assert.NoError(t, err)
+		locked <- unlock2
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lock taken twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, unlock())
+	select {
+	case unlock2 := <-locked:
+		require.NoError(t, unlock2())
+	case <-time.After(10 * time.Second):
+		t.Fatal("lock not released")
+	}
+}
+
+func TestTryLock(t *testing.T) {
+	switch runtime.GOOS {
+	case "js", "plan9":
+		t.Skip("locking not supported on " + runtime.GOOS)
+	}
+	dir, tidy := testDir(t)
+	defer tidy()
+	path := filepath.Join(dir, "file.lock")
+
+	unlock, err := TryLock(path)
+	require.NoError(t, err)
+
+	_, err = TryLock(path)
+	assert.Equal(t, ErrLocked, err)
+
+	require.NoError(t, unlock())
+	unlock, err = TryLock(path)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+}
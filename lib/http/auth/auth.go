@@ -1,6 +1,9 @@
 package auth
 
 import (
+	"context"
+
+	"github.com/pkg/errors"
 	"github.com/rclone/rclone/fs/config/flags"
 	"github.com/rclone/rclone/lib/http"
 	"github.com/spf13/pflag"
@@ -47,6 +50,7 @@ type Options struct {
 	BasicPass string       // password for BasicUser
 	Salt      string       // password hashing salt
 	Auth      CustomAuthFn `json:"-"` // custom Auth (not set by command line flags)
+	ProviderOptions
 }
 
 // Auth instantiates middleware that authenticates users based on the configuration
@@ -61,10 +65,35 @@ func Auth(opt Options) http.Middleware {
 	return nil
 }
 
+// NewAuth instantiates middleware that authenticates users with the
+// built in providers configured in opt as well as the methods used by
+// Auth.
+//
+// Users with a client certificate or bearer token are checked by the
+// providers, others by Auth or by LDAP if configured.
+func NewAuth(ctx context.Context, opt Options) (http.Middleware, error) {
+	p, err := NewProviders(ctx, &opt.ProviderOptions)
+	if err != nil {
+		return nil, err
+	}
+	basic := Auth(opt)
+	if p == nil {
+		return basic, nil
+	}
+	if p.UsesPasswords() {
+		if basic != nil {
+			return nil, errors.New("can't use LDAP with other password authentication")
+		}
+		basic = ldapAuth(p, opt.Realm)
+	}
+	return providersAuth(p, basic, opt.Realm), nil
+}
+
 // Options set by command line flags
 var (
 	Opt = Options{
-		Salt: "dlPL2MqE",
+		Salt:            "dlPL2MqE",
+		ProviderOptions: DefaultProviderOpt,
 	}
 )
 
@@ -75,6 +104,7 @@ func AddFlagsPrefix(flagSet *pflag.FlagSet, prefix string, Opt *Options) {
 	flags.StringVarP(flagSet, &Opt.BasicUser, prefix+"user", "", Opt.BasicUser, "User name for authentication.")
 	flags.StringVarP(flagSet, &Opt.BasicPass, prefix+"pass", "", Opt.BasicPass, "Password for authentication.")
 	flags.StringVarP(flagSet, &Opt.Salt, prefix+"salt", "", Opt.Salt, "Password hashing salt")
+	AddProviderFlagsPrefix(flagSet, prefix, &Opt.ProviderOptions)
 }
 
 // AddFlags adds flags for the http/auth
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+)
+
+// clientCertUsers maps the subjects of client certificates to users
+type clientCertUsers struct {
+	users map[string]string // subject to user
+}
+
+// newClientCertUsers reads the map of subjects to users from path
+func newClientCertUsers(path string) (c *clientCertUsers, err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "client cert users")
+	}
+	defer fs.CheckClose(in, &err)
+	c = &clientCertUsers{users: map[string]string{}}
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 || strings.TrimSpace(fields[1]) == "" {
+			return nil, errors.Errorf("client cert users: line %d: need USER SUBJECT", lineNumber)
+		}
+		c.users[strings.TrimSpace(fields[1])] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "client cert users")
+	}
+	fs.Infof(nil, "Using %q to map %d client certificates to users", path, len(c.users))
+	return c, nil
+}
+
+// user returns the user for the verified client certificate of r
+func (c *clientCertUsers) user(r *http.Request) (user string, ok bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	subject := r.TLS.VerifiedChains[0][0].Subject.String()
+	user, ok = c.users[subject]
+	if !ok {
+		fs.Debugf(r.URL.Path, "%s: no user for client certificate %q", r.RemoteAddr, subject)
+	}
+	return user, ok
+}
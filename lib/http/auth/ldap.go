@@ -0,0 +1,357 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+)
+
+// how long a successful bind is remembered for
+const ldapCacheTTL = time.Minute
+
+// ldap checks passwords by doing a simple bind to an LDAP server as
+// the user.
+//
+// Only the bind and StartTLS operations are needed so this speaks
+// just enough of the protocol (RFC 4511) to do those. Passwords are
+// only ever sent over TLS.
+type ldap struct {
+	address string         // host:port
+	tls     bool           // use ldaps rather than StartTLS
+	host    string         // host name to check the certificate with
+	bindDN  string         // DN with {user} to be replaced
+	rootCAs *x509.CertPool // CAs to check the certificate with - nil for the system ones
+	salt    []byte         // random salt for the cache keys
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]time.Time // hash of successful user and password to expiry time
+}
+
+// newLDAP makes a new LDAP password checker from opt
+func newLDAP(opt *ProviderOptions) (*ldap, error) {
+	if opt.LDAPURL == "" || opt.LDAPBindDN == "" {
+		return nil, errors.New("ldap: need both --ldap-url and --ldap-bind-dn")
+	}
+	if !strings.Contains(opt.LDAPBindDN, "{user}") {
+		return nil, errors.New("ldap: --ldap-bind-dn must contain {user}")
+	}
+	u, err := url.Parse(opt.LDAPURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "ldap: bad --ldap-url")
+	}
+	l := &ldap{
+		host:   u.Hostname(),
+		bindDN: opt.LDAPBindDN,
+		salt:   make([]byte, 32),
+		cache:  map[[sha256.Size]byte]time.Time{},
+	}
+	if _, err = rand.Read(l.salt); err != nil {
+		return nil, errors.Wrap(err, "ldap: failed to make salt")
+	}
+	if opt.LDAPCA != "" {
+		data, err := ioutil.ReadFile(opt.LDAPCA)
+		if err != nil {
+			return nil, errors.Wrap(err, "ldap: failed to read --ldap-ca")
+		}
+		l.rootCAs = x509.NewCertPool()
+		if !l.rootCAs.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("ldap: no certificates found in %q", opt.LDAPCA)
+		}
+	}
+	port := u.Port()
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		l.tls = true
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, errors.Errorf("ldap: unknown scheme %q in --ldap-url - use ldap:// or ldaps://", u.Scheme)
+	}
+	if l.host == "" {
+		return nil, errors.New("ldap: no host in --ldap-url")
+	}
+	l.address = net.JoinHostPort(l.host, port)
+	fs.Infof(nil, "Using LDAP server %q to check passwords", opt.LDAPURL)
+	return l, nil
+}
+
+// escapeDN escapes a value to put in a DN as described in RFC 4514
+func escapeDN(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 0:
+			out.WriteString(`\00`)
+			continue
+		case strings.IndexByte(`,+"\<>;=`, c) >= 0,
+			i == 0 && (c == ' ' || c == '#'),
+			i == len(s)-1 && c == ' ':
+			out.WriteByte('\\')
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+// ldapResult codes from RFC 4511
+const (
+	ldapSuccess            = 0
+	ldapInvalidCredentials = 49
+)
+
+// LDAP protocol operation tags from RFC 4511
+const (
+	ldapBindRequest      = 0
+	ldapBindResponse     = 1
+	ldapExtendedRequest  = 23
+	ldapExtendedResponse = 24
+)
+
+// OID of the StartTLS extended operation
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// ldapMessage makes an LDAP message with the operation tag made of
+// the values
+func ldapMessage(messageID int, tag int, values ...interface{}) ([]byte, error) {
+	var op []byte
+	for _, v := range values {
+		data, err := asn1.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		op = append(op, data...)
+	}
+	id, err := asn1.Marshal(messageID)
+	if err != nil {
+		return nil, err
+	}
+	protocolOp, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassApplication, Tag: tag, IsCompound: true, Bytes: op})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: append(id, protocolOp...)})
+}
+
+// bindRequest makes an LDAP message with a simple bind request
+func bindRequest(messageID int, dn, password string) ([]byte, error) {
+	return ldapMessage(messageID, ldapBindRequest,
+		3, // version
+		asn1.RawValue{Tag: asn1.TagOctetString, Bytes: []byte(dn)},
+		asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: []byte(password)}, // simple
+	)
+}
+
+// startTLSRequest makes an LDAP message with a StartTLS request
+func startTLSRequest(messageID int) ([]byte, error) {
+	return ldapMessage(messageID, ldapExtendedRequest,
+		asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, Bytes: []byte(ldapStartTLSOID)}, // requestName
+	)
+}
+
+// readMessage reads a single BER encoded message
+func readMessage(in *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2, 6)
+	if _, err := io.ReadFull(in, header); err != nil {
+		return nil, err
+	}
+	length := int(header[1])
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 {
+			return nil, errors.New("bad message length")
+		}
+		header = header[:2+n]
+		if _, err := io.ReadFull(in, header[2:]); err != nil {
+			return nil, err
+		}
+		length = 0
+		for _, b := range header[2:] {
+			length = length<<8 | int(b)
+		}
+		if length > 1<<20 {
+			return nil, errors.New("message too long")
+		}
+	}
+	msg := make([]byte, len(header)+length)
+	copy(msg, header)
+	_, err := io.ReadFull(in, msg[len(header):])
+	return msg, err
+}
+
+// parseResponse returns the result code and diagnostic message of
+// the response with the operation tag in msg
+func parseResponse(msg []byte, messageID int, tag int) (code int, diagnostic string, err error) {
+	var envelope asn1.RawValue
+	if _, err = asn1.Unmarshal(msg, &envelope); err != nil {
+		return 0, "", err
+	}
+	var id int
+	rest, err := asn1.Unmarshal(envelope.Bytes, &id)
+	if err != nil {
+		return 0, "", err
+	}
+	if id != messageID {
+		return 0, "", errors.Errorf("unexpected message ID %d", id)
+	}
+	var op asn1.RawValue
+	if _, err = asn1.Unmarshal(rest, &op); err != nil {
+		return 0, "", err
+	}
+	if op.Class != asn1.ClassApplication || op.Tag != tag {
+		return 0, "", errors.Errorf("unexpected response tag %d", op.Tag)
+	}
+	var result asn1.Enumerated
+	rest, err = asn1.Unmarshal(op.Bytes, &result)
+	if err != nil {
+		return 0, "", err
+	}
+	var matchedDN, message asn1.RawValue
+	if rest, err = asn1.Unmarshal(rest, &matchedDN); err == nil {
+		if _, err = asn1.Unmarshal(rest, &message); err == nil {
+			diagnostic = string(message.Bytes)
+		}
+	}
+	return int(result), diagnostic, nil
+}
+
+// cacheKey returns the key to remember a successful bind with
+func (l *ldap) cacheKey(user, password string) (key [sha256.Size]byte) {
+	h := sha256.New()
+	_, _ = h.Write(l.salt)
+	_, _ = h.Write([]byte(user))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(password))
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// cached returns true if user and password bound successfully less
+// than ldapCacheTTL ago
+func (l *ldap) cached(key [sha256.Size]byte) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	expires, ok := l.cache[key]
+	return ok && time.Now().Before(expires)
+}
+
+// remember records a successful bind, removing expired ones
+func (l *ldap) remember(key [sha256.Size]byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for k, expires := range l.cache {
+		if !now.Before(expires) {
+			delete(l.cache, k)
+		}
+	}
+	l.cache[key] = now.Add(ldapCacheTTL)
+}
+
+// startTLS upgrades conn to TLS with the StartTLS operation
+func (l *ldap) startTLS(conn net.Conn, messageID int) error {
+	req, err := startTLSRequest(messageID)
+	if err != nil {
+		return errors.Wrap(err, "ldap: failed to make StartTLS request")
+	}
+	if _, err = conn.Write(req); err != nil {
+		return errors.Wrap(err, "ldap: failed to send StartTLS request")
+	}
+	// the server sends nothing more until the client starts the
+	// handshake so the buffer can't swallow any of it
+	msg, err := readMessage(bufio.NewReader(conn))
+	if err != nil {
+		return errors.Wrap(err, "ldap: failed to read StartTLS response")
+	}
+	code, diagnostic, err := parseResponse(msg, messageID, ldapExtendedResponse)
+	if err != nil {
+		return errors.Wrap(err, "ldap: bad StartTLS response")
+	}
+	if code != ldapSuccess {
+		return errors.Errorf("ldap: server refused StartTLS with result %d: %s - use ldaps:// or enable StartTLS on the server", code, diagnostic)
+	}
+	return nil
+}
+
+// bind checks user and password by binding as them
+//
+// Successful binds are remembered for ldapCacheTTL so the server isn't
+// asked on every request.
+func (l *ldap) bind(ctx context.Context, user, password string) (err error) {
+	if user == "" || password == "" {
+		// an empty password would be an unauthenticated bind
+		// which succeeds without checking anything
+		return errors.New("ldap: empty user name or password")
+	}
+	key := l.cacheKey(user, password)
+	if l.cached(key) {
+		return nil
+	}
+	dn := strings.Replace(l.bindDN, "{user}", escapeDN(user), -1)
+	req, err := bindRequest(2, dn, password)
+	if err != nil {
+		return errors.Wrap(err, "ldap: failed to make bind request")
+	}
+	ci := fs.GetConfig(ctx)
+	dialer := net.Dialer{Timeout: ci.ConnectTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", l.address)
+	if err != nil {
+		return errors.Wrap(err, "ldap: failed to connect")
+	}
+	if ci.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(ci.Timeout))
+	}
+	if !l.tls {
+		if err = l.startTLS(conn, 1); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: l.host, RootCAs: l.rootCAs})
+	if err = tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return errors.Wrap(err, "ldap: TLS handshake failed")
+	}
+	conn = tlsConn
+	defer fs.CheckClose(conn, &err)
+	if _, err = conn.Write(req); err != nil {
+		return errors.Wrap(err, "ldap: failed to send bind request")
+	}
+	msg, err := readMessage(bufio.NewReader(conn))
+	if err != nil {
+		return errors.Wrap(err, "ldap: failed to read bind response")
+	}
+	code, diagnostic, err := parseResponse(msg, 2, ldapBindResponse)
+	if err != nil {
+		return errors.Wrap(err, "ldap: bad bind response")
+	}
+	switch code {
+	case ldapSuccess:
+		l.remember(key)
+		// unbind to be polite - the server will close the connection
+		_, _ = conn.Write([]byte{0x30, 0x05, 0x02, 0x01, 0x03, 0x42, 0x00})
+		return nil
+	case ldapInvalidCredentials:
+		return errors.New("ldap: invalid credentials")
+	}
+	return errors.Errorf("ldap: bind failed with result %d: %s", code, diagnostic)
+}
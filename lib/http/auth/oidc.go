@@ -0,0 +1,342 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/form3tech-oss/jwt-go"
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+)
+
+const (
+	jwksRefresh     = time.Hour        // how often to re-read the keys from the URL
+	jwksMinRefresh  = 30 * time.Second // minimum time between reads for unknown key IDs
+	oidcMaxLifetime = 24 * time.Hour   // longest time a token may be valid for
+)
+
+// signing methods accepted - the symmetric ones are never allowed as
+// the keys are public
+var oidcMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// oidc checks bearer tokens issued by an OpenID Connect provider
+type oidc struct {
+	ctx     context.Context
+	opt     ProviderOptions
+	client  *http.Client
+	parser  jwt.Parser
+	mu      sync.Mutex
+	keys    map[string]interface{} // key ID to public key, "" for keys without an ID
+	fetched time.Time              // when the keys were last read from the URL
+}
+
+// newOIDC makes a new token checker reading the keys from the URL or
+// file in opt
+func newOIDC(ctx context.Context, opt *ProviderOptions) (o *oidc, err error) {
+	if opt.OIDCJWKSURL != "" && opt.OIDCKeyFile != "" {
+		return nil, errors.New("oidc: can't use both --oidc-jwks-url and --oidc-key-file")
+	}
+	if opt.OIDCUserClaim == "" {
+		return nil, errors.New("oidc: --oidc-user-claim must be set")
+	}
+	o = &oidc{
+		ctx:    ctx,
+		opt:    *opt,
+		parser: jwt.Parser{ValidMethods: oidcMethods},
+	}
+	if opt.OIDCKeyFile != "" {
+		data, err := ioutil.ReadFile(opt.OIDCKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "oidc: failed to read key file")
+		}
+		o.keys, err = parseKeys(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "oidc: failed to parse key file %q", opt.OIDCKeyFile)
+		}
+		fs.Infof(nil, "Using %d keys from %q to check OIDC tokens", len(o.keys), opt.OIDCKeyFile)
+		return o, nil
+	}
+	// fshttp can't be used here as rc imports this package
+	o.client = &http.Client{Timeout: fs.GetConfig(ctx).Timeout}
+	o.mu.Lock()
+	err = o.fetch()
+	o.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	fs.Infof(nil, "Using %d keys from %q to check OIDC tokens", len(o.keys), opt.OIDCJWKSURL)
+	return o, nil
+}
+
+// fetch reads the keys from the JWKS URL - call with the lock held
+func (o *oidc) fetch() (err error) {
+	o.fetched = time.Now()
+	req, err := http.NewRequestWithContext(o.ctx, "GET", o.opt.OIDCJWKSURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "oidc: bad JWKS URL")
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "oidc: failed to fetch JWKS")
+	}
+	defer fs.CheckClose(resp.Body, &err)
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("oidc: failed to fetch JWKS: %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "oidc: failed to read JWKS")
+	}
+	keys, err := parseKeys(data)
+	if err != nil {
+		return errors.Wrap(err, "oidc: failed to parse JWKS")
+	}
+	o.keys = keys
+	return nil
+}
+
+// key finds the public key to check token with
+func (o *oidc) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.client != nil {
+		_, known := o.keys[kid]
+		age := time.Since(o.fetched)
+		if age > jwksRefresh || (!known && age > jwksMinRefresh) {
+			if err := o.fetch(); err != nil {
+				fs.Errorf(nil, "Failed to refresh OIDC keys - using old ones: %v", err)
+			}
+		}
+	}
+	key, ok := o.keys[kid]
+	if !ok {
+		// a key without an ID checks tokens with any ID
+		key, ok = o.keys[""]
+	}
+	if !ok {
+		return nil, errors.Errorf("unknown key ID %q", kid)
+	}
+	return key, nil
+}
+
+// claimString reads a string claim
+func claimString(claims jwt.MapClaims, name string) string {
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// claimTime reads a numeric date claim
+func claimTime(claims jwt.MapClaims, name string) (t int64, ok bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v), true
+	case json.Number:
+		t, err := v.Int64()
+		return t, err == nil
+	}
+	return 0, false
+}
+
+// checkTimes checks the token has an expiry time no more than
+// oidcMaxLifetime after it was issued and that the nbf and iat
+// claims, if present, are numbers. The parser has already checked the
+// times against the current time.
+func checkTimes(claims jwt.MapClaims) error {
+	exp, ok := claimTime(claims, "exp")
+	if !ok {
+		return errors.New("bad token: no valid exp claim")
+	}
+	issued := time.Now().Unix()
+	for _, name := range []string{"nbf", "iat"} {
+		if _, found := claims[name]; !found {
+			continue
+		}
+		t, ok := claimTime(claims, name)
+		if !ok {
+			return errors.Errorf("bad token: %s claim is not a number", name)
+		}
+		if name == "iat" {
+			issued = t
+		}
+	}
+	if time.Duration(exp-issued)*time.Second > oidcMaxLifetime {
+		return errors.Errorf("bad token: expires more than %v after it was issued", oidcMaxLifetime)
+	}
+	return nil
+}
+
+// authenticate checks the token, returning the user and root from
+// its claims
+func (o *oidc) authenticate(tokenString string) (user, root string, err error) {
+	claims := jwt.MapClaims{}
+	_, err = o.parser.ParseWithClaims(tokenString, claims, o.key)
+	if err != nil {
+		return "", "", errors.Wrap(err, "bad token")
+	}
+	if err = checkTimes(claims); err != nil {
+		return "", "", err
+	}
+	if o.opt.OIDCIssuer != "" && !claims.VerifyIssuer(o.opt.OIDCIssuer, true) {
+		return "", "", errors.Errorf("bad token: issuer %q is not %q", claims["iss"], o.opt.OIDCIssuer)
+	}
+	if o.opt.OIDCAudience != "" && !claims.VerifyAudience(o.opt.OIDCAudience, true) {
+		return "", "", errors.Errorf("bad token: audience is not %q", o.opt.OIDCAudience)
+	}
+	user = claimString(claims, o.opt.OIDCUserClaim)
+	if user == "" {
+		return "", "", errors.Errorf("bad token: no %q claim", o.opt.OIDCUserClaim)
+	}
+	if o.opt.OIDCRootClaim != "" {
+		root = claimString(claims, o.opt.OIDCRootClaim)
+		if strings.Trim(root, "/") == "" {
+			return "", "", errors.Errorf("bad token: no %q claim", o.opt.OIDCRootClaim)
+		}
+	}
+	return user, root, nil
+}
+
+// jwk is a single key in a JWKS
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// bigInt decodes a base64url encoded big endian number
+func bigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// publicKey turns the JWK into an *rsa.PublicKey or *ecdsa.PublicKey
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := bigInt(k.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad n")
+		}
+		e, err := bigInt(k.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad e")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unknown curve %q", k.Crv)
+		}
+		x, err := bigInt(k.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad x")
+		}
+		y, err := bigInt(k.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad y")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.Errorf("unsupported key type %q", k.Kty)
+}
+
+// parseKeys reads keys from a JWKS or the first PEM encoded public
+// key or certificate
+func parseKeys(data []byte) (keys map[string]interface{}, err error) {
+	keys = map[string]interface{}{}
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var jwks struct {
+			Keys []jwk `json:"keys"`
+		}
+		err = json.Unmarshal(data, &jwks)
+		if err != nil {
+			return nil, err
+		}
+		for i := range jwks.Keys {
+			k := &jwks.Keys[i]
+			if k.Use != "" && k.Use != "sig" {
+				continue
+			}
+			key, err := k.publicKey()
+			if err != nil {
+				fs.Debugf(nil, "OIDC: ignoring key %q: %v", k.Kid, err)
+				continue
+			}
+			keys[k.Kid] = key
+		}
+	} else {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			var key interface{}
+			switch block.Type {
+			case "CERTIFICATE":
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, err
+				}
+				key = cert.PublicKey
+			case "PUBLIC KEY":
+				key, err = x509.ParsePKIXPublicKey(block.Bytes)
+				if err != nil {
+					return nil, err
+				}
+			case "RSA PUBLIC KEY":
+				key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+				if err != nil {
+					return nil, err
+				}
+			default:
+				continue
+			}
+			// PEM keys have no ID so only the first is used
+			keys[""] = key
+			break
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable keys found")
+	}
+	return keys, nil
+}
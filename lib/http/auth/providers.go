@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	httplib "github.com/rclone/rclone/lib/http"
+	"github.com/spf13/pflag"
+)
+
+// ProvidersHelp contains text describing the built in authentication
+// providers to add to the command help.
+var ProvidersHelp = `
+#### OIDC, LDAP and client certificate authentication
+
+As well as the methods above, users can be authenticated by these
+built in providers. They can be combined with each other and with the
+methods above, in which case they are tried in the order below.
+
+Use --client-cert-users /path/to/file to log in clients which present
+a certificate signed by --client-ca. Each line of the file is a user
+name followed by the certificate subject which maps to it, e.g.
+
+    alice CN=alice,OU=Engineering,O=Example Ltd
+
+Lines starting with # are ignored.
+
+Use --oidc-jwks-url or --oidc-key-file to accept OAuth2 bearer tokens
+(JWTs) issued by an OpenID Connect provider. The signature of the token
+is checked with the keys found at the URL, which is refreshed
+periodically, or in the file, which can be a PEM public key or
+certificate or a JWKS. Use --oidc-issuer and --oidc-audience to check
+the iss and aud claims of the token. The user name is read from the
+claim given by --oidc-user-claim. If --oidc-root-claim is set then that
+claim is used as a directory within the remote which the user is
+confined to. Tokens must have an exp claim and can't be valid for
+more than 24 hours after they were issued.
+
+Use --ldap-url and --ldap-bind-dn to check basic authentication user
+names and passwords by binding to an LDAP server as the user. The
+bind DN should contain {user} which is replaced with the user name,
+e.g. --ldap-url ldaps://ldap.example.com --ldap-bind-dn
+"uid={user},ou=people,dc=example,dc=com".
+
+Passwords are only sent over TLS - with ldaps:// URLs the connection
+uses TLS from the start and with ldap:// URLs it is upgraded with
+StartTLS, failing if the server doesn't support it. The server's
+certificate is checked with the system CAs, or those in --ldap-ca if
+set. Successful logins
+are remembered for a minute so the server isn't asked on every
+request.
+`
+
+// ProviderOptions are the options for the built in authentication
+// providers
+type ProviderOptions struct {
+	ClientCertUsers string // file mapping client certificate subjects to users
+	OIDCJWKSURL     string // URL to read the keys to check OIDC tokens from
+	OIDCKeyFile     string // file with the keys to check OIDC tokens
+	OIDCIssuer      string // if set the iss claim must be this
+	OIDCAudience    string // if set the aud claim must contain this
+	OIDCUserClaim   string // claim to use as the user name
+	OIDCRootClaim   string // claim to use as the root of the user if set
+	LDAPURL         string // URL of the LDAP server
+	LDAPBindDN      string // DN to bind as with {user} replaced by the user name
+	LDAPCA          string // file of CA certificates to check the LDAP server with if set
+}
+
+// DefaultProviderOpt is the default values used for ProviderOptions
+var DefaultProviderOpt = ProviderOptions{
+	OIDCUserClaim: "sub",
+}
+
+// AddProviderFlagsPrefix adds flags for the built in authentication providers
+func AddProviderFlagsPrefix(flagSet *pflag.FlagSet, prefix string, Opt *ProviderOptions) {
+	flags.StringVarP(flagSet, &Opt.ClientCertUsers, prefix+"client-cert-users", "", Opt.ClientCertUsers, "File mapping client certificate subjects to user names")
+	flags.StringVarP(flagSet, &Opt.OIDCJWKSURL, prefix+"oidc-jwks-url", "", Opt.OIDCJWKSURL, "URL of the JWKS to check OIDC bearer tokens with")
+	flags.StringVarP(flagSet, &Opt.OIDCKeyFile, prefix+"oidc-key-file", "", Opt.OIDCKeyFile, "PEM or JWKS file of keys to check OIDC bearer tokens with")
+	flags.StringVarP(flagSet, &Opt.OIDCIssuer, prefix+"oidc-issuer", "", Opt.OIDCIssuer, "Issuer which OIDC bearer tokens must have")
+	flags.StringVarP(flagSet, &Opt.OIDCAudience, prefix+"oidc-audience", "", Opt.OIDCAudience, "Audience which OIDC bearer tokens must have")
+	flags.StringVarP(flagSet, &Opt.OIDCUserClaim, prefix+"oidc-user-claim", "", Opt.OIDCUserClaim, "Claim of the OIDC bearer token to use as the user name")
+	flags.StringVarP(flagSet, &Opt.OIDCRootClaim, prefix+"oidc-root-claim", "", Opt.OIDCRootClaim, "Claim of the OIDC bearer token to use as the root of the user")
+	flags.StringVarP(flagSet, &Opt.LDAPURL, prefix+"ldap-url", "", Opt.LDAPURL, "URL of the LDAP server to check passwords with, e.g. ldaps://ldap.example.com")
+	flags.StringVarP(flagSet, &Opt.LDAPBindDN, prefix+"ldap-bind-dn", "", Opt.LDAPBindDN, "DN to bind to the LDAP server as, with {user} replaced by the user name")
+	flags.StringVarP(flagSet, &Opt.LDAPCA, prefix+"ldap-ca", "", Opt.LDAPCA, "PEM file of CA certificates to check the LDAP server's certificate with")
+}
+
+type contextRootType struct{}
+
+// ContextRootKey is a simple context key for storing the root
+// directory the user of the request is confined to, if any
+var ContextRootKey = &contextRootType{}
+
+// Providers are the configured built in authentication providers
+type Providers struct {
+	certs *clientCertUsers
+	oidc  *oidc
+	ldap  *ldap
+}
+
+// NewProviders makes the providers configured in opt, returning nil
+// if there aren't any.
+func NewProviders(ctx context.Context, opt *ProviderOptions) (p *Providers, err error) {
+	p = &Providers{}
+	if opt.ClientCertUsers != "" {
+		p.certs, err = newClientCertUsers(opt.ClientCertUsers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.OIDCJWKSURL != "" || opt.OIDCKeyFile != "" {
+		p.oidc, err = newOIDC(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.LDAPURL != "" || opt.LDAPBindDN != "" {
+		p.ldap, err = newLDAP(opt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.certs == nil && p.oidc == nil && p.ldap == nil {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// Authenticate checks the client certificate or bearer token of r.
+//
+// It returns handled = false if r has neither which the providers can
+// check, in which case basic authentication should be tried.
+func (p *Providers) Authenticate(r *http.Request) (user, root string, handled bool, err error) {
+	if p == nil {
+		return "", "", false, nil
+	}
+	if p.certs != nil {
+		if user, ok := p.certs.user(r); ok {
+			return user, "", true, nil
+		}
+	}
+	if p.oidc != nil {
+		authHeader := r.Header.Get("Authorization")
+		if len(authHeader) > 7 && strings.EqualFold(authHeader[:7], "Bearer ") {
+			user, root, err = p.oidc.authenticate(strings.TrimSpace(authHeader[7:]))
+			return user, root, true, err
+		}
+	}
+	return "", "", false, nil
+}
+
+// UsesPasswords returns true if the providers check user names and
+// passwords
+func (p *Providers) UsesPasswords() bool {
+	return p != nil && p.ldap != nil
+}
+
+// CheckPassword checks user and pass against the LDAP server
+func (p *Providers) CheckPassword(ctx context.Context, user, pass string) error {
+	if !p.UsesPasswords() {
+		return errors.New("no password authentication configured")
+	}
+	return p.ldap.bind(ctx, user, pass)
+}
+
+// WithUser returns r with the user and root in its context
+func WithUser(r *http.Request, user, root string) *http.Request {
+	ctx := context.WithValue(r.Context(), ContextUserKey, user)
+	if root != "" {
+		ctx = context.WithValue(ctx, ContextRootKey, root)
+	}
+	return r.WithContext(ctx)
+}
+
+// providersAuth instantiates middleware that authenticates with the
+// providers, falling back to basic if set
+func providersAuth(p *Providers, basic httplib.Middleware, realm string) httplib.Middleware {
+	return func(next http.Handler) http.Handler {
+		var basicHandler http.Handler
+		if basic != nil {
+			basicHandler = basic(next)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, root, handled, err := p.Authenticate(r)
+			if handled {
+				if err != nil {
+					fs.Infof(r.URL.Path, "%s: Auth failed: %v", r.RemoteAddr, err)
+					w.Header().Set("WWW-Authenticate", `Bearer realm="`+realm+`", error="invalid_token"`)
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, WithUser(r, user, root))
+				return
+			}
+			if basicHandler != nil {
+				basicHandler.ServeHTTP(w, r)
+				return
+			}
+			if p.oidc != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+realm+`"`)
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	}
+}
+
+// ldapAuth instantiates middleware that authenticates basic auth
+// users against the LDAP server
+func ldapAuth(p *Providers, realm string) httplib.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := parseAuthorization(r)
+			if ok {
+				err := p.CheckPassword(r.Context(), user, pass)
+				if err == nil {
+					next.ServeHTTP(w, WithUser(r, user, ""))
+					return
+				}
+				fs.Infof(r.URL.Path, "%s: Auth failed from %s: %v", r.RemoteAddr, user, err)
+			}
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwt "github.com/form3tech-oss/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemp writes data to a file in dir returning its path
+func writeTemp(t *testing.T, dir, name, data string) string {
+	p := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(p, []byte(data), 0600))
+	return p
+}
+
+func newTestKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestOIDCKeyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-auth-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	key, pubPEM := newTestKey(t)
+	otherKey, _ := newTestKey(t)
+
+	opt := DefaultProviderOpt
+	opt.OIDCKeyFile = writeTemp(t, dir, "key.pem", pubPEM)
+	opt.OIDCIssuer = "https://issuer.example.com"
+	opt.OIDCAudience = "rclone"
+	opt.OIDCRootClaim = "home"
+	p, err := NewProviders(context.Background(), &opt)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.False(t, p.UsesPasswords())
+
+	good := jwt.MapClaims{
+		"sub":  "alice",
+		"iss":  "https://issuer.example.com",
+		"aud":  []string{"other", "rclone"},
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"home": "/users/alice",
+	}
+	with := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range good {
+			claims[k] = v
+		}
+		change(claims)
+		return claims
+	}
+
+	// HS256 signed with the public key must not be accepted
+	hsToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, good).SignedString([]byte(pubPEM))
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name  string
+		token string
+		user  string
+		root  string
+	}{
+		{"good", sign(t, key, "", good), "alice", "/users/alice"},
+		{"good with kid", sign(t, key, "any", good), "alice", "/users/alice"},
+		{"wrong key", sign(t, otherKey, "", good), "", ""},
+		{"HS256", hsToken, "", ""},
+		{"expired", sign(t, key, "", with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() })), "", ""},
+		{"no exp", sign(t, key, "", with(func(c jwt.MapClaims) { delete(c, "exp") })), "", ""},
+		{"bad exp", sign(t, key, "", with(func(c jwt.MapClaims) { c["exp"] = "tomorrow" })), "", ""},
+		{"nbf", sign(t, key, "", with(func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(-time.Minute).Unix() })), "alice", "/users/alice"},
+		{"nbf future", sign(t, key, "", with(func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Minute).Unix() })), "", ""},
+		{"bad nbf", sign(t, key, "", with(func(c jwt.MapClaims) { c["nbf"] = "now" })), "", ""},
+		{"iat", sign(t, key, "", with(func(c jwt.MapClaims) { c["iat"] = time.Now().Add(-time.Minute).Unix() })), "alice", "/users/alice"},
+		{"iat future", sign(t, key, "", with(func(c jwt.MapClaims) { c["iat"] = time.Now().Add(time.Minute).Unix() })), "", ""},
+		{"long lived", sign(t, key, "", with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(48 * time.Hour).Unix() })), "", ""},
+		{"long lived iat", sign(t, key, "", with(func(c jwt.MapClaims) { c["iat"] = time.Now().Add(-48 * time.Hour).Unix() })), "", ""},
+		{"bad iat", sign(t, key, "", with(func(c jwt.MapClaims) { c["iat"] = "yesterday" })), "", ""},
+		{"issuer", sign(t, key, "", with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" })), "", ""},
+		{"audience", sign(t, key, "", with(func(c jwt.MapClaims) { c["aud"] = "other" })), "", ""},
+		{"no user", sign(t, key, "", with(func(c jwt.MapClaims) { delete(c, "sub") })), "", ""},
+		{"no root", sign(t, key, "", with(func(c jwt.MapClaims) { delete(c, "home") })), "", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+test.token)
+			user, root, handled, err := p.Authenticate(r)
+			assert.True(t, handled)
+			if test.user == "" {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.user, user)
+			assert.Equal(t, test.root, root)
+		})
+	}
+
+	// Requests without a bearer token are left for basic auth
+	r := httptest.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("alice", "secret")
+	_, _, handled, err := p.Authenticate(r)
+	assert.False(t, handled)
+	assert.NoError(t, err)
+}
+
+func TestOIDCJWKS(t *testing.T) {
+	key, _ := newTestKey(t)
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		e := big.NewInt(int64(key.PublicKey.E)).Bytes()
+		_, _ = fmt.Fprintf(w, `{"keys":[{"kty":"RSA","kid":"k1","use":"sig","n":%q,"e":%q},{"kty":"oct","kid":"k2"}]}`,
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(e))
+	}))
+	defer server.Close()
+
+	opt := DefaultProviderOpt
+	opt.OIDCJWKSURL = server.URL
+	opt.OIDCUserClaim = "email"
+	p, err := NewProviders(context.Background(), &opt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	token := sign(t, key, "k1", jwt.MapClaims{"email": "bob@example.com", "exp": exp})
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	user, root, handled, err := p.Authenticate(r)
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, "bob@example.com", user)
+	assert.Equal(t, "", root)
+
+	// Unknown key IDs are rejected without hammering the server
+	token = sign(t, key, "k3", jwt.MapClaims{"email": "bob@example.com", "exp": exp})
+	r.Header.Set("Authorization", "Bearer "+token)
+	_, _, _, err = p.Authenticate(r)
+	assert.Error(t, err)
+	assert.Equal(t, 1, fetches)
+}
+
+func TestClientCertUsers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-auth-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	opt := DefaultProviderOpt
+	opt.ClientCertUsers = writeTemp(t, dir, "users", "# users\nalice CN=alice,O=Example\n\nbob CN=bob\n")
+	p, err := NewProviders(context.Background(), &opt)
+	require.NoError(t, err)
+
+	request := func(subject *pkix.Name) *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		if subject != nil {
+			r.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: *subject}}},
+			}
+		}
+		return r
+	}
+
+	user, _, handled, err := p.Authenticate(request(&pkix.Name{CommonName: "alice", Organization: []string{"Example"}}))
+	require.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, "alice", user)
+
+	_, _, handled, _ = p.Authenticate(request(&pkix.Name{CommonName: "carol"}))
+	assert.False(t, handled)
+	_, _, handled, _ = p.Authenticate(request(nil))
+	assert.False(t, handled)
+
+	opt.ClientCertUsers = writeTemp(t, dir, "bad", "alice\n")
+	_, err = NewProviders(context.Background(), &opt)
+	assert.Error(t, err)
+}
+
+func TestEscapeDN(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"alice", "alice"},
+		{"a,b=c", `a\,b\=c`},
+		{" #x ", `\ #x\ `},
+		{"#x", `\#x`},
+		{"a\x00b", `a\00b`},
+		{`a+b"c\d<e>f;g`, `a\+b\"c\\d\<e\>f\;g`},
+	} {
+		assert.Equal(t, test.want, escapeDN(test.in), test.in)
+	}
+}
+
+// testTLSConfig makes a TLS config with a self signed certificate
+// for 127.0.0.1 and returns the certificate PEM encoded
+func testTLSConfig(t *testing.T) (*tls.Config, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, string(certPEM)
+}
+
+// ldapResponse makes a response with the operation tag and result
+// code
+func ldapResponse(id, tag, code int) []byte {
+	var result []byte
+	for _, v := range []interface{}{
+		asn1.Enumerated(code),
+		asn1.RawValue{Tag: asn1.TagOctetString},
+		asn1.RawValue{Tag: asn1.TagOctetString, Bytes: []byte("diagnostic")},
+	} {
+		data, _ := asn1.Marshal(v)
+		result = append(result, data...)
+	}
+	idBytes, _ := asn1.Marshal(id)
+	resp, _ := asn1.Marshal(asn1.RawValue{Class: asn1.ClassApplication, Tag: tag, IsCompound: true, Bytes: result})
+	resp, _ = asn1.Marshal(asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: append(idBytes, resp...)})
+	return resp
+}
+
+// fakeLDAP serves bind requests, accepting only dn with password and
+// counting them in binds.
+//
+// mode is "ldaps" to use TLS from the start, "starttls" to support
+// StartTLS or "plain" to refuse it.
+func fakeLDAP(t *testing.T, dn, password, mode string, binds *int32) (url string, caPEM string, stop func()) {
+	tlsConfig, caPEM := testTLSConfig(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url = "ldap://" + l.Addr().String()
+	if mode == "ldaps" {
+		url = "ldaps://" + l.Addr().String()
+		l = tls.NewListener(l, tlsConfig)
+	}
+	serve := func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		in := bufio.NewReader(conn)
+		for {
+			msg, err := readMessage(in)
+			if err != nil {
+				return
+			}
+			var envelope, op asn1.RawValue
+			var id int
+			_, _ = asn1.Unmarshal(msg, &envelope)
+			rest, _ := asn1.Unmarshal(envelope.Bytes, &id)
+			_, _ = asn1.Unmarshal(rest, &op)
+			switch op.Tag {
+			case ldapExtendedRequest:
+				var name asn1.RawValue
+				_, _ = asn1.Unmarshal(op.Bytes, &name)
+				code := ldapSuccess
+				if mode != "starttls" || string(name.Bytes) != ldapStartTLSOID {
+					code = 2 // protocolError
+				}
+				_, _ = conn.Write(ldapResponse(id, ldapExtendedResponse, code))
+				if code != ldapSuccess {
+					return
+				}
+				conn = tls.Server(conn, tlsConfig)
+				in = bufio.NewReader(conn)
+			case ldapBindRequest:
+				atomic.AddInt32(binds, 1)
+				var version int
+				var gotDN, gotPassword asn1.RawValue
+				rest, _ = asn1.Unmarshal(op.Bytes, &version)
+				rest, _ = asn1.Unmarshal(rest, &gotDN)
+				_, _ = asn1.Unmarshal(rest, &gotPassword)
+				code := ldapInvalidCredentials
+				if version == 3 && string(gotDN.Bytes) == dn && string(gotPassword.Bytes) == password {
+					code = ldapSuccess
+				}
+				_, _ = conn.Write(ldapResponse(id, ldapBindResponse, code))
+			default:
+				return
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return url, caPEM, func() { _ = l.Close() }
+}
+
+func TestLDAP(t *testing.T) {
+	var binds int32
+	url, caPEM, stop := fakeLDAP(t, `uid=a\,b,ou=people,dc=example,dc=com`, "secret", "starttls", &binds)
+	defer stop()
+
+	opt := DefaultProviderOpt
+	opt.LDAPURL = url
+	opt.LDAPBindDN = "uid={user},ou=people,dc=example,dc=com"
+	p, err := NewProviders(context.Background(), &opt)
+	require.NoError(t, err)
+	assert.True(t, p.UsesPasswords())
+
+	// The certificate isn't trusted
+	ctx := context.Background()
+	assert.Error(t, p.CheckPassword(ctx, "a,b", "secret"))
+
+	dir, err := ioutil.TempDir("", "rclone-auth-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	opt.LDAPCA = writeTemp(t, dir, "ca.pem", caPEM)
+	p, err = NewProviders(context.Background(), &opt)
+	require.NoError(t, err)
+
+	assert.NoError(t, p.CheckPassword(ctx, "a,b", "secret"))
+	assert.Error(t, p.CheckPassword(ctx, "a,b", "wrong"))
+	assert.Error(t, p.CheckPassword(ctx, "a", "secret"))
+	assert.Error(t, p.CheckPassword(ctx, "a,b", ""))
+
+	// Successful binds are cached, failed ones aren't
+	before := atomic.LoadInt32(&binds)
+	assert.NoError(t, p.CheckPassword(ctx, "a,b", "secret"))
+	assert.Error(t, p.CheckPassword(ctx, "a,b", "wrong"))
+	assert.Equal(t, before+1, atomic.LoadInt32(&binds))
+	p.ldap.cache = map[[sha256.Size]byte]time.Time{}
+	assert.NoError(t, p.CheckPassword(ctx, "a,b", "secret"))
+	assert.Equal(t, before+2, atomic.LoadInt32(&binds))
+
+	// As middleware
+	opts := Options{Realm: "test", ProviderOptions: opt}
+	m, err := NewAuth(ctx, opts)
+	require.NoError(t, err)
+	handler := m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := r.Context().Value(ContextUserKey).(string)
+		_, _ = w.Write([]byte(user))
+	}))
+	for _, test := range []struct {
+		user, pass string
+		status     int
+	}{
+		{"a,b", "secret", http.StatusOK},
+		{"a,b", "wrong", http.StatusUnauthorized},
+		{"", "", http.StatusUnauthorized},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		if test.user != "" {
+			r.SetBasicAuth(test.user, test.pass)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, test.status, w.Code, test.user)
+		if test.status == http.StatusOK {
+			assert.Equal(t, test.user, w.Body.String())
+		}
+	}
+
+	// LDAP can't be used with other password methods
+	opts.BasicUser, opts.BasicPass = "user", "pass"
+	_, err = NewAuth(ctx, opts)
+	assert.Error(t, err)
+
+	opt.LDAPBindDN = "uid=fixed"
+	_, err = NewProviders(ctx, &opt)
+	assert.Error(t, err)
+}
+
+func TestLDAPTLS(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "rclone-auth-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	for _, mode := range []string{"ldaps", "plain"} {
+		var binds int32
+		url, caPEM, stop := fakeLDAP(t, "uid=alice", "secret", mode, &binds)
+		opt := DefaultProviderOpt
+		opt.LDAPURL = url
+		opt.LDAPBindDN = "uid={user}"
+		opt.LDAPCA = writeTemp(t, dir, mode+".pem", caPEM)
+		p, err := NewProviders(ctx, &opt)
+		require.NoError(t, err)
+		err = p.CheckPassword(ctx, "alice", "secret")
+		if mode == "ldaps" {
+			assert.NoError(t, err, mode)
+		} else {
+			// the password must not be sent without TLS
+			assert.Error(t, err, mode)
+			assert.Equal(t, int32(0), atomic.LoadInt32(&binds), mode)
+		}
+		stop()
+	}
+}
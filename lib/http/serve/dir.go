@@ -37,6 +37,9 @@ type Directory struct {
 	Breadcrumb   []Crumb
 	Sort         string
 	Order        string
+	Tools        bool   // set to offer search and zip download
+	Writable     bool   // set to offer upload, mkdir, rename and delete
+	Search       string // set if the entries are search results
 }
 
 // Crumb is a breadcrumb entry
@@ -105,6 +108,24 @@ func (d *Directory) AddHTMLEntry(remote string, isDir bool, size int64, modTime
 	})
 }
 
+// AddSearchEntry adds an entry found by searching the directory
+// where relPath is the path of the entry relative to the directory
+func (d *Directory) AddSearchEntry(relPath string, isDir bool, size int64, modTime time.Time) {
+	leaf, urlRemote := relPath, relPath
+	if isDir {
+		leaf += "/"
+		urlRemote += "/"
+	}
+	d.Entries = append(d.Entries, DirEntry{
+		remote:  path.Join(d.DirRemote, relPath),
+		URL:     rest.URLPathEscape(urlRemote) + d.Query,
+		Leaf:    leaf,
+		IsDir:   isDir,
+		Size:    size,
+		ModTime: modTime,
+	})
+}
+
 // AddEntry adds an entry to that directory
 func (d *Directory) AddEntry(remote string, isDir bool) {
 	leaf := path.Base(remote)
@@ -63,6 +63,17 @@ func TestAddHTMLEntry(t *testing.T) {
 	}, d.Entries)
 }
 
+func TestAddSearchEntry(t *testing.T) {
+	var modtime = time.Now()
+	var d = NewDirectory("z", GetTemplate(t))
+	d.AddSearchEntry("a/b/c.txt", false, 64, modtime)
+	d.AddSearchEntry("a/b", true, 0, modtime)
+	assert.Equal(t, []DirEntry{
+		{remote: "z/a/b/c.txt", URL: "a/b/c.txt", Leaf: "a/b/c.txt", IsDir: false, Size: 64, ModTime: modtime},
+		{remote: "z/a/b", URL: "a/b/", Leaf: "a/b/", IsDir: true, Size: 0, ModTime: modtime},
+	}, d.Entries)
+}
+
 func TestAddEntry(t *testing.T) {
 	var d = NewDirectory("z", GetTemplate(t))
 	d.AddEntry("", true)
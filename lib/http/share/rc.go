@@ -0,0 +1,211 @@
+package share
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/rc"
+)
+
+func init() {
+	rc.Add(rc.Call{
+		Path:         "share/create",
+		AuthRequired: true,
+		Fn:           rcCreate,
+		Title:        "Make a share link to a file",
+		Help: `This makes a signed link to a single file which is served by
+"rclone serve http --shares" or "rclone rcd --rc-shares" without
+needing a login.
+
+This takes the following parameters
+
+- fs - the remote the file is on - leave out for "serve http" which can only share the remote it serves
+- remote - the path of the file within the remote
+- expire - how long the link lasts, e.g. "1d" - the default is forever
+- password - a password which must be given to download the file (optional)
+- maxDownloads - the number of times the link can be used (optional)
+- server - "http" or "rcd" - which server serves the link if more than one has shares enabled
+
+Returns
+
+- id - the ID of the link for share/revoke
+- url - the link
+- expires - when the link expires if it does
+
+Eg
+
+    rclone rc share/create fs=drive: remote=dir/file.txt expire=1d maxDownloads=3
+`,
+	})
+	rc.Add(rc.Call{
+		Path:         "share/list",
+		AuthRequired: true,
+		Fn:           rcList,
+		Title:        "List the share links",
+		Help: `This lists the share links which haven't expired or been used up.
+
+This takes the following parameters
+
+- server - "http" or "rcd" - only list the links of this server (optional)
+
+Returns
+
+- links - a list of the links with their id, fs, remote, url, created,
+  expires, maxDownloads, downloads and whether they need a password
+`,
+	})
+	rc.Add(rc.Call{
+		Path:         "share/revoke",
+		AuthRequired: true,
+		Fn:           rcRevoke,
+		Title:        "Revoke a share link",
+		Help: `This stops a share link working.
+
+This takes the following parameters
+
+- id - the ID of the link as returned by share/create or share/list
+`,
+	})
+}
+
+// getManager returns the manager for the server in the parameters,
+// or the only one if not set
+func getManager(in rc.Params) (*Manager, error) {
+	name, err := in.GetString("server")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	managersMu.Lock()
+	defer managersMu.Unlock()
+	if name != "" {
+		m := managers[name]
+		if m == nil {
+			return nil, errors.Errorf("share links not enabled on server %q", name)
+		}
+		return m, nil
+	}
+	switch len(managers) {
+	case 0:
+		return nil, errors.New("share links not enabled - use --shares or --rc-shares")
+	case 1:
+		for _, m := range managers {
+			return m, nil
+		}
+	}
+	var names []string
+	for name := range managers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return nil, errors.Errorf("more than one server has share links - set server to one of %s", strings.Join(names, ", "))
+}
+
+// rcLink returns the link as rc output
+func (m *Manager) rcLink(l *Link) rc.Params {
+	out := rc.Params{
+		"id":           l.ID,
+		"fs":           l.Fs,
+		"remote":       l.Remote,
+		"url":          m.URL(l),
+		"created":      l.Created,
+		"maxDownloads": l.MaxDownloads,
+		"downloads":    l.Downloads,
+		"password":     l.PasswordHash != "",
+		"server":       m.name,
+	}
+	if !l.Expires.IsZero() {
+		out["expires"] = l.Expires
+	}
+	return out
+}
+
+// Make a share link
+func rcCreate(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	m, err := getManager(in)
+	if err != nil {
+		return nil, err
+	}
+	fsString, err := in.GetString("fs")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	remote, err := in.GetString("remote")
+	if err != nil {
+		return nil, err
+	}
+	var expire time.Duration
+	expireString, err := in.GetString("expire")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	if expireString != "" {
+		d, err := fs.ParseDuration(expireString)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad expire")
+		}
+		expire = d
+	}
+	password, err := in.GetString("password")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	maxDownloads, err := in.GetInt64("maxDownloads")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	if maxDownloads < 0 {
+		return nil, errors.New("maxDownloads must not be negative")
+	}
+	l, err := m.Create(ctx, fsString, remote, expire, password, int(maxDownloads))
+	if err != nil {
+		return nil, err
+	}
+	return m.rcLink(l), nil
+}
+
+// List the share links
+func rcList(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	name, err := in.GetString("server")
+	if rc.NotErrParamNotFound(err) {
+		return nil, err
+	}
+	managersMu.Lock()
+	var ms []*Manager
+	for _, m := range managers {
+		if name == "" || m.name == name {
+			ms = append(ms, m)
+		}
+	}
+	managersMu.Unlock()
+	links := []rc.Params{}
+	for _, m := range ms {
+		for _, l := range m.List() {
+			links = append(links, m.rcLink(&l))
+		}
+	}
+	return rc.Params{"links": links}, nil
+}
+
+// Revoke a share link
+func rcRevoke(ctx context.Context, in rc.Params) (out rc.Params, err error) {
+	id, err := in.GetString("id")
+	if err != nil {
+		return nil, err
+	}
+	managersMu.Lock()
+	var ms []*Manager
+	for _, m := range managers {
+		ms = append(ms, m)
+	}
+	managersMu.Unlock()
+	for _, m := range ms {
+		if m.Revoke(id) {
+			return nil, nil
+		}
+	}
+	return nil, errors.Errorf("share link %q not found", id)
+}
@@ -0,0 +1,440 @@
+// Package share implements links to files signed and served by rclone
+// itself, for serve http and the rc server.
+package share
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/flags"
+	"github.com/rclone/rclone/lib/http/serve"
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Prefix is the path under the root of the server the links are
+// served from
+const Prefix = "share/"
+
+// Help contains text describing share links to add to the command help
+var Help = strings.Replace(`
+#### Share links
+
+If |--shares| is set then rclone can make links to single files which
+can be given to people without a login. Links are signed with
+|--share-secret|, expire, and may need a password or allow only a
+limited number of downloads. They are served under |/share/| which
+doesn't need authentication, so that path can't be used for anything
+else.
+
+Every GET of a link counts as a download, including requests for part
+of the file, so allow a few more downloads than needed for clients
+which fetch files in pieces.
+
+Make links with |rclone link --share URL| or the |share/create| rc
+call, list them with |share/list| and revoke them with
+|share/revoke|. For these rclone must be run with |--rc|, or be |rclone
+rcd| itself.
+
+If |--share-secret| isn't set then a random one is used, so the links
+only last until rclone is restarted. Use |--share-state| to save the
+links in a file as well so they last between restarts. The download
+counts are saved in it every few seconds rather than on every
+download.
+`, "|", "`", -1)
+
+// Options contains options for share links
+type Options struct {
+	Enabled bool   // set to serve share links
+	Secret  string // key to sign the links with
+	State   string // file to save the links in
+}
+
+// DefaultOpt is the default values used for Options
+var DefaultOpt = Options{}
+
+// AddFlagsPrefix adds flags for share links
+func AddFlagsPrefix(flagSet *pflag.FlagSet, prefix string, Opt *Options) {
+	flags.BoolVarP(flagSet, &Opt.Enabled, prefix+"shares", "", Opt.Enabled, "Enable links to files signed by rclone")
+	flags.StringVarP(flagSet, &Opt.Secret, prefix+"share-secret", "", Opt.Secret, "Secret to sign share links with - random if not set")
+	flags.StringVarP(flagSet, &Opt.State, prefix+"share-state", "", Opt.State, "File to save share links in so they last between restarts")
+}
+
+// Link is a share link to a file
+type Link struct {
+	ID           string    `json:"id"`
+	Fs           string    `json:"fs,omitempty"` // remote the file is on, "" for the one being served
+	Remote       string    `json:"remote"`       // path of the file on the remote
+	Created      time.Time `json:"created"`
+	Expires      time.Time `json:"expires,omitempty"` // zero if the link doesn't expire
+	PasswordHash string    `json:"passwordHash,omitempty"`
+	MaxDownloads int       `json:"maxDownloads,omitempty"` // 0 for no limit
+	Downloads    int       `json:"downloads"`
+}
+
+// expired returns true if the link can no longer be used
+func (l *Link) expired(now time.Time) bool {
+	return (!l.Expires.IsZero() && !now.Before(l.Expires)) || (l.MaxDownloads > 0 && l.Downloads >= l.MaxDownloads)
+}
+
+// NewFsFn makes the Fs for the remote called fsString, which is ""
+// for the remote being served
+type NewFsFn func(ctx context.Context, fsString string) (fs.Fs, error)
+
+// Manager makes, checks and serves the share links of a server
+type Manager struct {
+	name   string
+	opt    Options
+	secret []byte
+	newFs  NewFsFn
+	url    func() string // root URL of the server
+	mu     sync.Mutex
+	links  map[string]*Link
+	timer  *time.Timer // set if a save is pending
+}
+
+// saveDelay is how long after a download the links are saved
+var saveDelay = 10 * time.Second
+
+var (
+	managersMu sync.Mutex
+	managers   = map[string]*Manager{}
+)
+
+// New makes the share link manager for the server called name,
+// returning nil if share links aren't enabled.
+//
+// newFs makes the Fs the links are to and url returns the root URL
+// of the server which the links are relative to.
+func New(name string, opt *Options, newFs NewFsFn, url func() string) (*Manager, error) {
+	if !opt.Enabled {
+		return nil, nil
+	}
+	m := &Manager{
+		name:  name,
+		opt:   *opt,
+		newFs: newFs,
+		url:   url,
+		links: map[string]*Link{},
+	}
+	if opt.Secret != "" {
+		m.secret = []byte(opt.Secret)
+	} else {
+		m.secret = make([]byte, 32)
+		if _, err := rand.Read(m.secret); err != nil {
+			return nil, errors.Wrap(err, "share: failed to make secret")
+		}
+		if opt.State != "" {
+			fs.Logf(nil, "Share links saved in %q won't work after a restart without --share-secret", opt.State)
+		}
+	}
+	if opt.State != "" {
+		err := m.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+	managersMu.Lock()
+	managers[name] = m
+	managersMu.Unlock()
+	return m, nil
+}
+
+// Close stops the rc calls using the manager, saving any pending
+// changes to the links
+func (m *Manager) Close() {
+	managersMu.Lock()
+	if managers[m.name] == m {
+		delete(managers, m.name)
+	}
+	managersMu.Unlock()
+	m.mu.Lock()
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+		m.save()
+	}
+	m.mu.Unlock()
+}
+
+// load reads the links from the state file
+func (m *Manager) load() error {
+	data, err := ioutil.ReadFile(m.opt.State)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "share: failed to read state")
+	}
+	var links []*Link
+	err = json.Unmarshal(data, &links)
+	if err != nil {
+		return errors.Wrapf(err, "share: failed to parse state file %q", m.opt.State)
+	}
+	for _, l := range links {
+		m.links[l.ID] = l
+	}
+	return nil
+}
+
+// save writes the links to the state file if set - call with the lock held
+func (m *Manager) save() {
+	if m.opt.State == "" {
+		return
+	}
+	links := make([]*Link, 0, len(m.links))
+	for _, l := range m.links {
+		links = append(links, l)
+	}
+	data, err := json.MarshalIndent(links, "", "\t")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(m.opt.State), 0700)
+	}
+	if err == nil {
+		tmp := m.opt.State + ".tmp"
+		err = ioutil.WriteFile(tmp, data, 0600)
+		if err == nil {
+			err = os.Rename(tmp, m.opt.State)
+		}
+	}
+	if err != nil {
+		fs.Errorf(nil, "Failed to save share links: %v", err)
+	}
+}
+
+// saveLater saves the links after saveDelay unless a save is already
+// pending so they aren't saved on every download - call with the lock
+// held
+func (m *Manager) saveLater() {
+	if m.opt.State == "" || m.timer != nil {
+		return
+	}
+	m.timer = time.AfterFunc(saveDelay, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.timer = nil
+		m.save()
+	})
+}
+
+// sign returns the signature of the link
+func (m *Manager) sign(l *Link) string {
+	mac := hmac.New(sha256.New, m.secret)
+	expires := ""
+	if !l.Expires.IsZero() {
+		expires = l.Expires.UTC().Format(time.RFC3339)
+	}
+	for _, s := range []string{l.ID, l.Fs, l.Remote, expires} {
+		_, _ = mac.Write([]byte(s))
+		_, _ = mac.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// URL returns the URL of the link
+func (m *Manager) URL(l *Link) string {
+	return m.url() + Prefix + l.ID + "/" + m.sign(l) + "/" + url.PathEscape(path.Base(l.Remote))
+}
+
+// Create makes a link to remote on the remote fsString which expires
+// after expire if it is > 0.
+//
+// If password is set then it must be supplied to download the file
+// and if maxDownloads > 0 then the link can only be used that many
+// times.
+func (m *Manager) Create(ctx context.Context, fsString, remote string, expire time.Duration, password string, maxDownloads int) (*Link, error) {
+	f, err := m.newFs(ctx, fsString)
+	if err != nil {
+		return nil, err
+	}
+	remote = strings.Trim(path.Clean("/"+remote), "/")
+	if _, err := f.NewObject(ctx, remote); err != nil {
+		return nil, errors.Wrapf(err, "can only share files: %q", remote)
+	}
+	var id [12]byte
+	if _, err = rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	l := &Link{
+		ID:           hex.EncodeToString(id[:]),
+		Fs:           fsString,
+		Remote:       remote,
+		Created:      now,
+		MaxDownloads: maxDownloads,
+	}
+	if expire > 0 {
+		l.Expires = now.Add(expire)
+	}
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		l.PasswordHash = string(hash)
+	}
+	m.mu.Lock()
+	m.links[l.ID] = l
+	m.save()
+	m.mu.Unlock()
+	fs.Infof(remote, "Made share link %s", l.ID)
+	copy := *l
+	return &copy, nil
+}
+
+// List returns copies of the links which are still valid sorted by
+// creation time, removing the expired ones.
+func (m *Manager) List() (links []Link) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	changed := false
+	for id, l := range m.links {
+		if l.expired(now) {
+			delete(m.links, id)
+			changed = true
+			continue
+		}
+		links = append(links, *l)
+	}
+	if changed {
+		m.save()
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Created.Before(links[j].Created) })
+	return links
+}
+
+// Revoke removes the link with id, returning false if not found
+func (m *Manager) Revoke(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.links[id]; !ok {
+		return false
+	}
+	delete(m.links, id)
+	m.save()
+	fs.Infof(nil, "Revoked share link %s", id)
+	return true
+}
+
+// find returns a copy of the link for the id and signature if it
+// can be used
+func (m *Manager) find(id, sig string) (l Link, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	link, ok := m.links[id]
+	if !ok || !hmac.Equal([]byte(sig), []byte(m.sign(link))) {
+		return l, http.StatusNotFound
+	}
+	if link.expired(time.Now()) {
+		return l, http.StatusGone
+	}
+	return *link, http.StatusOK
+}
+
+// check returns the link for the id and signature if it can be used
+// and counts a download if download is set
+//
+// The password is checked without the lock held as bcrypt is slow.
+func (m *Manager) check(id, sig string, r *http.Request, download bool) (l Link, status int) {
+	l, status = m.find(id, sig)
+	if status != http.StatusOK {
+		return l, status
+	}
+	if l.PasswordHash != "" {
+		_, pass, ok := r.BasicAuth()
+		if !ok || bcrypt.CompareHashAndPassword([]byte(l.PasswordHash), []byte(pass)) != nil {
+			return Link{}, http.StatusUnauthorized
+		}
+	}
+	if !download {
+		return l, http.StatusOK
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// The link may have been revoked or used up meanwhile
+	link, ok := m.links[id]
+	if !ok {
+		return Link{}, http.StatusNotFound
+	}
+	if link.expired(time.Now()) {
+		return Link{}, http.StatusGone
+	}
+	link.Downloads++
+	m.saveLater()
+	return *link, http.StatusOK
+}
+
+// uncount takes back a download counted by check when the file
+// couldn't be served
+func (m *Manager) uncount(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if link, ok := m.links[id]; ok && link.Downloads > 0 {
+		link.Downloads--
+		m.saveLater()
+	}
+}
+
+// ServeHTTP serves the file of a link - the URL path should end with
+// the path of the link after the root of the server.
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	// The path ends share/<id>/<signature>/<leaf>
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[len(parts)-4]+"/" != Prefix {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	parts = parts[len(parts)-3:]
+	// Count every GET, whatever part of the file it is for, so
+	// the limit can't be got round with range requests
+	download := r.Method == "GET"
+	l, status := m.check(parts[0], parts[1], r, download)
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="rclone share"`)
+	}
+	if status != http.StatusOK {
+		fs.Infof(r.URL.Path, "%s: share link refused: %s", r.RemoteAddr, http.StatusText(status))
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	f, err := m.newFs(r.Context(), l.Fs)
+	if err != nil {
+		if download {
+			m.uncount(l.ID)
+		}
+		serve.Error(l.Remote, w, "Failed to find remote", err)
+		return
+	}
+	o, err := f.NewObject(r.Context(), l.Remote)
+	if err != nil {
+		if download {
+			m.uncount(l.ID)
+		}
+		fs.Infof(l.Remote, "%s: share link file not found: %v", r.RemoteAddr, err)
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''`+url.PathEscape(path.Base(l.Remote)))
+	serve.Object(w, r, o)
+}
@@ -0,0 +1,217 @@
+package share
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/rclone/rclone/backend/local"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/rc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestManager makes a manager serving the files in a temporary
+// directory with an httptest server
+func newTestManager(t *testing.T, opt Options) (m *Manager, dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "rclone-share")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), []byte("hello"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir"), 0700))
+	f, err := fs.NewFs(context.Background(), dir)
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	opt.Enabled = true
+	m, err = New("test", &opt, func(ctx context.Context, fsString string) (fs.Fs, error) {
+		return f, nil
+	}, func() string { return server.URL + "/" })
+	require.NoError(t, err)
+	server = httptest.NewServer(m)
+	return m, dir, func() {
+		server.Close()
+		m.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// get fetches url returning the status and body
+func get(t *testing.T, url, password string) (int, string) {
+	return getRange(t, url, password, "")
+}
+
+// getRange fetches rangeHeader of url returning the status and body
+func getRange(t *testing.T, url, password, rangeHeader string) (int, string) {
+	req, err := http.NewRequest("GET", url, nil)
+	require.NoError(t, err)
+	if password != "" {
+		req.SetBasicAuth("", password)
+	}
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestNewDisabled(t *testing.T) {
+	m, err := New("test", &Options{}, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestShare(t *testing.T) {
+	m, _, cleanup := newTestManager(t, Options{Secret: "secret"})
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := m.Create(ctx, "", "dir", 0, "", 0)
+	assert.Error(t, err)
+	_, err = m.Create(ctx, "", "missing.txt", 0, "", 0)
+	assert.Error(t, err)
+
+	l, err := m.Create(ctx, "", "/file.txt", 0, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "file.txt", l.Remote)
+	url := m.URL(l)
+	assert.True(t, strings.HasSuffix(url, "/file.txt"), url)
+
+	status, body := get(t, url, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "hello", body)
+
+	// Tampered signature
+	bad := strings.Replace(url, "/"+l.ID+"/", "/"+l.ID+"/x", 1)
+	status, _ = get(t, bad, "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Revoked
+	assert.True(t, m.Revoke(l.ID))
+	assert.False(t, m.Revoke(l.ID))
+	status, _ = get(t, url, "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestShareLimits(t *testing.T) {
+	m, _, cleanup := newTestManager(t, Options{})
+	defer cleanup()
+	ctx := context.Background()
+
+	// Password and download limit
+	l, err := m.Create(ctx, "", "file.txt", 0, "potato", 2)
+	require.NoError(t, err)
+	url := m.URL(l)
+	status, _ := get(t, url, "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = get(t, url, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	for i := 0; i < 2; i++ {
+		status, body := get(t, url, "potato")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "hello", body)
+	}
+	status, _ = get(t, url, "potato")
+	assert.Equal(t, http.StatusGone, status)
+
+	// Range requests count as downloads too
+	l, err = m.Create(ctx, "", "file.txt", 0, "", 2)
+	require.NoError(t, err)
+	url = m.URL(l)
+	status, body := getRange(t, url, "", "bytes=-2")
+	assert.Equal(t, http.StatusPartialContent, status)
+	assert.Equal(t, "lo", body)
+	status, _ = getRange(t, url, "", "bytes=1-")
+	assert.Equal(t, http.StatusPartialContent, status)
+	status, _ = getRange(t, url, "", "bytes=0-")
+	assert.Equal(t, http.StatusGone, status)
+	assert.True(t, m.Revoke(l.ID))
+
+	// Expiry
+	l2, err := m.Create(ctx, "", "file.txt", time.Hour, "", 0)
+	require.NoError(t, err)
+	links := m.List()
+	require.Len(t, links, 1)
+	assert.Equal(t, l2.ID, links[0].ID)
+	url = m.URL(l2)
+	m.mu.Lock()
+	expired := m.links[l2.ID]
+	expired.Expires = time.Now().Add(-time.Second)
+	m.mu.Unlock()
+	status, _ = get(t, m.URL(expired), "")
+	assert.Equal(t, http.StatusGone, status)
+	// the signature covers the expiry
+	status, _ = get(t, url, "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Len(t, m.List(), 0)
+}
+
+func TestShareState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rclone-share-state")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	opt := Options{Secret: "secret", State: filepath.Join(dir, "sub", "shares.json")}
+
+	m, _, cleanup := newTestManager(t, opt)
+	l, err := m.Create(context.Background(), "", "file.txt", time.Hour, "", 0)
+	require.NoError(t, err)
+	sig := m.sign(l)
+	status, _ := get(t, m.URL(l), "")
+	assert.Equal(t, http.StatusOK, status)
+	// the download count is saved later, or on close
+	m.mu.Lock()
+	assert.NotNil(t, m.timer)
+	m.mu.Unlock()
+	cleanup()
+
+	m2, _, cleanup2 := newTestManager(t, opt)
+	defer cleanup2()
+	links := m2.List()
+	require.Len(t, links, 1)
+	assert.Equal(t, sig, m2.sign(&links[0]))
+	assert.Equal(t, 1, links[0].Downloads)
+
+	require.NoError(t, ioutil.WriteFile(opt.State, []byte("{"), 0600))
+	_, err = New("bad", &Options{Enabled: true, State: opt.State}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestShareRc(t *testing.T) {
+	m, _, cleanup := newTestManager(t, Options{})
+	defer cleanup()
+	ctx := context.Background()
+
+	call := rc.Calls.Get("share/create")
+	require.NotNil(t, call)
+	out, err := call.Fn(ctx, rc.Params{"remote": "file.txt", "expire": "1h", "maxDownloads": 1})
+	require.NoError(t, err)
+	id := out["id"].(string)
+	assert.Contains(t, out["url"], "/"+Prefix+id+"/")
+	assert.Contains(t, out, "expires")
+	_, err = call.Fn(ctx, rc.Params{"remote": "file.txt", "server": "other"})
+	assert.Error(t, err)
+	_, err = call.Fn(ctx, rc.Params{"remote": "file.txt", "expire": "potato"})
+	assert.Error(t, err)
+
+	out, err = rc.Calls.Get("share/list").Fn(ctx, rc.Params{})
+	require.NoError(t, err)
+	links := out["links"].([]rc.Params)
+	require.Len(t, links, 1)
+	assert.Equal(t, id, links[0]["id"])
+
+	_, err = rc.Calls.Get("share/revoke").Fn(ctx, rc.Params{"id": id})
+	require.NoError(t, err)
+	_, err = rc.Calls.Get("share/revoke").Fn(ctx, rc.Params{"id": id})
+	assert.Error(t, err)
+	assert.Len(t, m.List(), 0)
+}
@@ -2,12 +2,15 @@ package oauthutil
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"net"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +21,7 @@ import (
 	"github.com/rclone/rclone/fs/config/configmap"
 	"github.com/rclone/rclone/fs/fserrors"
 	"github.com/rclone/rclone/fs/fshttp"
+	"github.com/rclone/rclone/lib/file"
 	"github.com/rclone/rclone/lib/random"
 	"github.com/skratchdot/open-golang/open"
 	"golang.org/x/oauth2"
@@ -203,6 +207,57 @@ func (ts *TokenSource) reReadToken() (changed bool) {
 	return changed
 }
 
+// tokenLockTimeout is how long to wait for another rclone process to
+// refresh the token before refreshing it without the lock
+var tokenLockTimeout = 30 * time.Second
+
+// tokenLockRetry is how often to try the lock while waiting for it
+var tokenLockRetry = 100 * time.Millisecond
+
+// lockToken takes a lock shared by the rclone processes using the
+// same config file so only one of them refreshes the token for the
+// remote name at once. This matters for providers where refresh
+// tokens can only be used once. It returns a function to release the
+// lock.
+//
+// If the lock can't be taken within tokenLockTimeout, for example
+// because a stuck process is holding it, it carries on without the
+// lock rather than blocking the caller forever.
+func lockToken(name string) (unlock func()) {
+	configPath := config.GetConfigPath()
+	if configPath == "" {
+		return func() {}
+	}
+	hash := md5.Sum([]byte(configPath + "\x00" + name))
+	dir := filepath.Join(config.GetCacheDir(), "token-locks")
+	err := file.MkdirAll(dir, 0700)
+	if err != nil {
+		fs.Debugf(name, "Failed to make token lock directory: %v", err)
+		return func() {}
+	}
+	lockPath := filepath.Join(dir, hex.EncodeToString(hash[:])+".lock")
+	deadline := time.Now().Add(tokenLockTimeout)
+	for {
+		unlockFile, err := file.TryLock(lockPath)
+		if err == nil {
+			return func() {
+				if err := unlockFile(); err != nil {
+					fs.Debugf(name, "Failed to unlock token: %v", err)
+				}
+			}
+		}
+		if err != file.ErrLocked {
+			fs.Debugf(name, "Failed to lock token: %v", err)
+			return func() {}
+		}
+		if time.Now().After(deadline) {
+			fs.Logf(name, "Timed out after %v waiting for another rclone to refresh the token - refreshing it anyway", tokenLockTimeout)
+			return func() {}
+		}
+		time.Sleep(tokenLockRetry)
+	}
+}
+
 // Token returns a token or an error.
 // Token must be safe for concurrent use by multiple goroutines.
 // The returned Token must not be modified.
@@ -218,6 +273,13 @@ func (ts *TokenSource) Token() (*oauth2.Token, error) {
 	)
 	const maxTries = 5
 
+	// If the token needs refreshing, hold the lock until the new
+	// token is saved so another rclone process doesn't refresh it
+	// too. It will read the new token from the config instead.
+	if !ts.token.Valid() {
+		defer lockToken(ts.name)()
+	}
+
 	// Try getting the token a few times
 	for i := 1; i <= maxTries; i++ {
 		// Try reading the token from the config file in case it has